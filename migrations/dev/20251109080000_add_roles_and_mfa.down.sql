@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS mfa_recovery_codes;
+DROP TABLE IF EXISTS mfa_credentials;
+ALTER TABLE users DROP COLUMN IF EXISTS role;
\ No newline at end of file
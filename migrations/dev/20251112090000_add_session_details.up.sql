@@ -0,0 +1,4 @@
+ALTER TABLE sessions ADD COLUMN device VARCHAR(64);
+ALTER TABLE sessions ADD COLUMN ip VARCHAR(64);
+ALTER TABLE sessions ADD COLUMN user_agent VARCHAR(512);
+ALTER TABLE sessions ADD COLUMN last_seen_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP;
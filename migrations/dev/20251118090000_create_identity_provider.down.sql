@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS signing_keys;
+DROP TABLE IF EXISTS oauth_authorization_codes;
+DROP TABLE IF EXISTS oauth_consents;
+DROP TABLE IF EXISTS oauth_clients;
\ No newline at end of file
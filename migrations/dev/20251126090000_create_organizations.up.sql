@@ -0,0 +1,40 @@
+CREATE TABLE organizations (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    slug VARCHAR(63) UNIQUE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Existing users and invitations move into the default organization
+INSERT INTO organizations (name, slug) VALUES ('Default', 'default');
+
+CREATE TABLE memberships (
+    id SERIAL PRIMARY KEY,
+    organization_id INTEGER NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_memberships_organization_user ON memberships (organization_id, user_id);
+CREATE INDEX idx_memberships_user_id ON memberships (user_id);
+
+ALTER TABLE users ADD COLUMN organization_id INTEGER REFERENCES organizations (id);
+UPDATE users SET organization_id = (SELECT id FROM organizations WHERE slug = 'default');
+ALTER TABLE users ALTER COLUMN organization_id SET NOT NULL;
+
+-- Usernames are unique per organization
+ALTER TABLE users DROP CONSTRAINT users_username_key;
+CREATE UNIQUE INDEX idx_users_organization_username ON users (organization_id, username);
+
+INSERT INTO memberships (organization_id, user_id, role)
+SELECT organization_id, id, CASE WHEN role = 'admin' THEN 'admin' ELSE 'member' END
+FROM users;
+
+ALTER TABLE invitations ADD COLUMN organization_id INTEGER REFERENCES organizations (id);
+UPDATE invitations SET organization_id = (SELECT id FROM organizations WHERE slug = 'default');
+ALTER TABLE invitations ALTER COLUMN organization_id SET NOT NULL;
+
+CREATE INDEX idx_invitations_organization_id ON invitations (organization_id);
@@ -0,0 +1,34 @@
+CREATE TABLE groups (
+    id SERIAL PRIMARY KEY,
+    organization_id INTEGER NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    parent_id INTEGER REFERENCES groups (id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_groups_organization_name ON groups (organization_id, name);
+CREATE INDEX idx_groups_parent_id ON groups (parent_id);
+
+CREATE TABLE group_members (
+    id SERIAL PRIMARY KEY,
+    organization_id INTEGER NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    group_id INTEGER NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_group_members_group_user ON group_members (group_id, user_id);
+CREATE INDEX idx_group_members_organization_id ON group_members (organization_id);
+CREATE INDEX idx_group_members_user_id ON group_members (user_id);
+
+CREATE TABLE group_permissions (
+    id SERIAL PRIMARY KEY,
+    organization_id INTEGER NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    group_id INTEGER NOT NULL REFERENCES groups (id) ON DELETE CASCADE,
+    permission VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_group_permissions_group_permission ON group_permissions (group_id, permission);
+CREATE INDEX idx_group_permissions_organization_id ON group_permissions (organization_id);
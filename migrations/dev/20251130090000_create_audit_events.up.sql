@@ -0,0 +1,39 @@
+-- No foreign keys: the log outlives the organizations and users it records
+CREATE TABLE audit_events (
+    id SERIAL PRIMARY KEY,
+    organization_id INTEGER NOT NULL,
+    action VARCHAR(64) NOT NULL,
+    actor_user_id INTEGER,
+    actor_api_key_id INTEGER,
+    actor_service VARCHAR(100),
+    target_type VARCHAR(32) NOT NULL,
+    target_id INTEGER NOT NULL,
+    ip VARCHAR(45),
+    request_id VARCHAR(64),
+    changes TEXT NOT NULL,
+    prev_hash VARCHAR(64) NOT NULL,
+    hash VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- One event per predecessor keeps the chain of each organization linear
+CREATE UNIQUE INDEX idx_audit_events_organization_prev_hash ON audit_events (organization_id, prev_hash);
+CREATE INDEX idx_audit_events_action ON audit_events (action);
+CREATE INDEX idx_audit_events_actor_user_id ON audit_events (actor_user_id);
+CREATE INDEX idx_audit_events_target ON audit_events (target_type, target_id);
+CREATE INDEX idx_audit_events_created_at ON audit_events (created_at);
+
+-- The log is append-only
+CREATE FUNCTION audit_events_append_only() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+CREATE TRIGGER audit_events_no_truncate
+    BEFORE TRUNCATE ON audit_events
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
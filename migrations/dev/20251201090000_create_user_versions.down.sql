@@ -0,0 +1 @@
+DROP TABLE IF EXISTS user_versions;
\ No newline at end of file
@@ -0,0 +1,22 @@
+CREATE TABLE user_versions (
+    id SERIAL PRIMARY KEY,
+    organization_id INTEGER NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    username VARCHAR(50) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    pending_email VARCHAR(255),
+    role VARCHAR(32) NOT NULL,
+    email_verified_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_user_versions_user_version ON user_versions (user_id, version);
+CREATE INDEX idx_user_versions_organization_id ON user_versions (organization_id);
+CREATE INDEX idx_user_versions_created_at ON user_versions (created_at);
+
+-- Existing users start their history at their current version
+INSERT INTO user_versions (organization_id, user_id, version, username, email, pending_email, role, email_verified_at, created_at)
+SELECT organization_id, id, version, username, email, pending_email, role, email_verified_at, updated_at
+FROM users
+WHERE deleted_at IS NULL;
@@ -0,0 +1,24 @@
+-- Jobs enqueued outside an organization have organization_id 0, so it has no foreign key
+CREATE TABLE jobs (
+    id SERIAL PRIMARY KEY,
+    organization_id INTEGER NOT NULL DEFAULT 0,
+    type VARCHAR(100) NOT NULL,
+    payload TEXT NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    unique_key VARCHAR(255),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL,
+    run_at TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP,
+    last_error TEXT,
+    finished_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Only one unfinished job can hold a unique key
+CREATE UNIQUE INDEX idx_jobs_unique_key ON jobs (unique_key) WHERE finished_at IS NULL;
+CREATE INDEX idx_jobs_organization_id ON jobs (organization_id);
+CREATE INDEX idx_jobs_type ON jobs (type);
+CREATE INDEX idx_jobs_status_run_at ON jobs (status, run_at);
+CREATE INDEX idx_jobs_locked_until ON jobs (locked_until);
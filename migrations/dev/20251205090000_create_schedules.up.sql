@@ -0,0 +1,19 @@
+CREATE TABLE schedules (
+    name VARCHAR(100) PRIMARY KEY,
+    expression VARCHAR(100) NOT NULL,
+    next_run_at TIMESTAMP NOT NULL,
+    running_since TIMESTAMP,
+    last_run_at TIMESTAMP,
+    last_status VARCHAR(16),
+    last_error TEXT,
+    last_duration_ms BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Named locks, such as the one held by the replica that runs scheduled tasks
+CREATE TABLE leases (
+    name VARCHAR(100) PRIMARY KEY,
+    holder VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
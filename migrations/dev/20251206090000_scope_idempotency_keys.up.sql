@@ -0,0 +1,8 @@
+-- Keys are scoped to the organization and credentials of the caller; stored
+-- responses of unscoped keys cannot be told apart and are dropped
+DELETE FROM idempotency_keys;
+
+ALTER TABLE idempotency_keys DROP CONSTRAINT idempotency_keys_pkey;
+ALTER TABLE idempotency_keys ADD COLUMN scope VARCHAR(64) NOT NULL;
+ALTER TABLE idempotency_keys ADD COLUMN lock_id VARCHAR(36) NOT NULL;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (scope, key);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS gateway_tickets;
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE gateway_tickets (
+    id SERIAL PRIMARY KEY,
+    organization_id INTEGER NOT NULL REFERENCES organizations (id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) UNIQUE NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_gateway_tickets_organization_id ON gateway_tickets (organization_id);
+CREATE INDEX idx_gateway_tickets_expires_at ON gateway_tickets (expires_at);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_api_keys_service_organization_id;
+ALTER TABLE api_keys DROP COLUMN service_organization_id;
@@ -0,0 +1,6 @@
+-- Service keys only act in the organization they were created in; existing
+-- ones move into the default organization
+ALTER TABLE api_keys ADD COLUMN service_organization_id INTEGER REFERENCES organizations (id) ON DELETE CASCADE;
+UPDATE api_keys SET service_organization_id = (SELECT id FROM organizations WHERE slug = 'default') WHERE user_id IS NULL;
+
+CREATE INDEX idx_api_keys_service_organization_id ON api_keys (service_organization_id);
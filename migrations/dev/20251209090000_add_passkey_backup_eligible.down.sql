@@ -0,0 +1 @@
+ALTER TABLE passkeys DROP COLUMN backup_eligible;
@@ -0,0 +1,3 @@
+-- Whether a passkey may be synced is recorded at registration and checked on
+-- every login. Existing passkeys record it on their next login.
+ALTER TABLE passkeys ADD COLUMN backup_eligible BOOLEAN;
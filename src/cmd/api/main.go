@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"os/signal"
@@ -8,8 +9,15 @@ import (
 
 	"github.com/weeranieb/go-kit-base/src/internal/config"
 	"github.com/weeranieb/go-kit-base/src/internal/di"
+	"github.com/weeranieb/go-kit-base/src/internal/gateway"
 	"github.com/weeranieb/go-kit-base/src/internal/handler"
+	"github.com/weeranieb/go-kit-base/src/internal/jobs"
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
+	"github.com/weeranieb/go-kit-base/src/internal/outbox"
 	"github.com/weeranieb/go-kit-base/src/internal/router"
+	"github.com/weeranieb/go-kit-base/src/internal/scheduler"
+	"github.com/weeranieb/go-kit-base/src/internal/stream"
+	"github.com/weeranieb/go-kit-base/src/internal/webhook"
 
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/dig"
@@ -17,6 +25,8 @@ import (
 
 var (
 	app *fiber.App
+	// stopWorkers stops the background workers on shutdown
+	stopWorkers context.CancelFunc = func() {}
 )
 
 var (
@@ -38,13 +48,24 @@ var (
 // @host localhost:8080
 // @BasePath /api/v1
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Access token from /auth/login, as "Bearer <token>"
+
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key from /api-keys; may also be sent as "Bearer <key>"
+
 func main() {
 	conf := LoadConfigFunc()
 
 	// Dependency Injection
 	container := di.NewContainer(conf)
 
-	// Start Fiber + Router
+	// Set up Fiber + Router and start the background workers; the server
+	// only listens once the shutdown handler is installed
 	setupAndStartServer(conf, container)
 
 	// Graceful shutdown
@@ -69,23 +90,50 @@ func setupAndStartServer(conf *config.Config, container *dig.Container) {
 		BodyLimit:      10 * 1024 * 1024, // 10MB
 	})
 
-	// Construct the Handler using DI container
+	// Construct the Handler and Middleware using DI container
 	var handlers *handler.Handler
-
-	err := container.Invoke(func(h *handler.Handler) {
+	var middlewares *middleware.Middleware
+	var relay *outbox.Relay
+	var webhookWorker *webhook.Worker
+	var jobWorker *jobs.Worker
+	var taskScheduler *scheduler.Scheduler
+	var eventHub *stream.Hub
+	var wsGateway *gateway.Gateway
+
+	err := container.Invoke(func(h *handler.Handler, m *middleware.Middleware, r *outbox.Relay, w *webhook.Worker, j *jobs.Worker, s *scheduler.Scheduler, e *stream.Hub, g *gateway.Gateway) {
 		handlers = h
+		middlewares = m
+		relay = r
+		webhookWorker = w
+		jobWorker = j
+		taskScheduler = s
+		eventHub = e
+		wsGateway = g
 	})
 	if err != nil {
 		log.Fatal("DI error", err)
 	}
 
-	router.SetupRoutes(app, conf, handlers)
-	app.Listen(conf.GetServerAddress())
+	// Publish domain events from the outbox, send webhooks, run jobs and
+	// scheduled tasks and stream events in the background; the gateway
+	// closes its connections on shutdown
+	var ctx context.Context
+	ctx, stopWorkers = context.WithCancel(context.Background())
+	go relay.Run(ctx)
+	go webhookWorker.Run(ctx)
+	go jobWorker.Run(ctx)
+	go taskScheduler.Run(ctx)
+	go eventHub.Run(ctx)
+	go wsGateway.Run(ctx)
+
+	router.SetupRoutes(app, conf, handlers, middlewares)
 }
 
 func shutdownServer() {
 	log.Println("Fiber was successfully shut down.")
 
+	stopWorkers()
+
 	if err := app.Shutdown(); err != nil {
 		log.Fatal("Error shutting down Fiber", err)
 	}
@@ -0,0 +1,156 @@
+// Package audit describes who made a change for the audit log. The source
+// of a request, its caller, address and request ID, travels in its context
+// from the middleware to the repositories, which record it with the change
+// in the same transaction. Events are chained by hash so that editing or
+// removing one breaks the chain.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// Redacted replaces the values of secret fields, such as password hashes,
+// in recorded changes. The change itself is still recorded.
+const Redacted = "[redacted]"
+
+// Source is where a change came from. Changes made outside a request, or
+// before the caller is known, have an empty actor.
+type Source struct {
+	UserID    uint
+	APIKeyID  uint
+	Service   string
+	IP        string
+	RequestID string
+}
+
+type contextKey struct{}
+
+// WithSource returns a context carrying the source.
+func WithSource(ctx context.Context, source Source) context.Context {
+	return context.WithValue(ctx, contextKey{}, source)
+}
+
+// SourceFromContext returns the source in the context, or an empty one.
+func SourceFromContext(ctx context.Context) Source {
+	if ctx == nil {
+		return Source{}
+	}
+	source, _ := ctx.Value(contextKey{}).(Source)
+	return source
+}
+
+// NewEvent returns an event for the change from the source in ctx. Its time
+// is in UTC and truncated to what the database stores, so that the hash can
+// be recomputed from the stored event.
+func NewEvent(ctx context.Context, action, targetType string, targetID uint, changes model.AuditChanges) *model.AuditEvent {
+	source := SourceFromContext(ctx)
+	event := &model.AuditEvent{
+		Action:       action,
+		ActorService: source.Service,
+		TargetType:   targetType,
+		TargetID:     targetID,
+		IP:           source.IP,
+		RequestID:    source.RequestID,
+		Changes:      changes,
+		CreatedAt:    time.Now().UTC().Truncate(time.Microsecond),
+	}
+	if source.UserID != 0 {
+		event.ActorUserID = &source.UserID
+	}
+	if source.APIKeyID != 0 {
+		event.ActorAPIKeyID = &source.APIKeyID
+	}
+	return event
+}
+
+// Hash returns the hash of the event chained to the hash of the event
+// before it in its organization.
+func Hash(prevHash string, event *model.AuditEvent) (string, error) {
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return "", err
+	}
+
+	// Field order is fixed by the struct, and map keys in changes are sorted
+	content, err := json.Marshal(struct {
+		PrevHash       string          `json:"prev_hash"`
+		OrganizationID uint            `json:"organization_id"`
+		Action         string          `json:"action"`
+		ActorUserID    *uint           `json:"actor_user_id"`
+		ActorAPIKeyID  *uint           `json:"actor_api_key_id"`
+		ActorService   string          `json:"actor_service"`
+		TargetType     string          `json:"target_type"`
+		TargetID       uint            `json:"target_id"`
+		IP             string          `json:"ip"`
+		RequestID      string          `json:"request_id"`
+		Changes        json.RawMessage `json:"changes"`
+		CreatedAt      string          `json:"created_at"`
+	}{
+		PrevHash:       prevHash,
+		OrganizationID: event.OrganizationID,
+		Action:         event.Action,
+		ActorUserID:    event.ActorUserID,
+		ActorAPIKeyID:  event.ActorAPIKeyID,
+		ActorService:   event.ActorService,
+		TargetType:     event.TargetType,
+		TargetID:       event.TargetID,
+		IP:             event.IP,
+		RequestID:      event.RequestID,
+		Changes:        changes,
+		CreatedAt:      event.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Diff returns the fields whose values differ between before and after.
+// Either may be nil for records that are created or deleted.
+func Diff(before, after map[string]interface{}) model.AuditChanges {
+	changes := model.AuditChanges{}
+	for field, value := range after {
+		if previous, ok := before[field]; !ok || !equal(previous, value) {
+			changes[field] = model.AuditChange{Before: before[field], After: value}
+		}
+	}
+	for field, previous := range before {
+		if _, ok := after[field]; !ok {
+			changes[field] = model.AuditChange{Before: previous}
+		}
+	}
+	return changes
+}
+
+// Redact replaces the values of the fields in changes with Redacted.
+func Redact(changes model.AuditChanges, fields ...string) {
+	for _, field := range fields {
+		change, ok := changes[field]
+		if !ok {
+			continue
+		}
+		if change.Before != nil {
+			change.Before = Redacted
+		}
+		if change.After != nil {
+			change.After = Redacted
+		}
+		changes[field] = change
+	}
+}
+
+// equal compares field values by their JSON encoding, which is also how
+// they are stored.
+func equal(a, b interface{}) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
@@ -0,0 +1,115 @@
+package audit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/audit"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+type AuditTestSuite struct {
+	suite.Suite
+	event *model.AuditEvent
+}
+
+func (s *AuditTestSuite) SetupTest() {
+	actor := uint(7)
+	s.event = &model.AuditEvent{
+		OrganizationID: 1,
+		Action:         model.AuditUserUpdated,
+		ActorUserID:    &actor,
+		TargetType:     model.AuditTargetUser,
+		TargetID:       3,
+		IP:             "203.0.113.7",
+		RequestID:      "req-1",
+		Changes:        model.AuditChanges{"username": {Before: "old", After: "new"}},
+		CreatedAt:      time.Date(2025, 11, 30, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestAuditSuite(t *testing.T) {
+	suite.Run(t, new(AuditTestSuite))
+}
+
+func (s *AuditTestSuite) TestSourceFromContext() {
+	assert.Equal(s.T(), audit.Source{}, audit.SourceFromContext(context.Background()))
+
+	source := audit.Source{UserID: 7, IP: "203.0.113.7", RequestID: "req-1"}
+	ctx := audit.WithSource(context.Background(), source)
+	assert.Equal(s.T(), source, audit.SourceFromContext(ctx))
+}
+
+func (s *AuditTestSuite) TestNewEvent() {
+	ctx := audit.WithSource(context.Background(), audit.Source{APIKeyID: 5, Service: "billing", IP: "203.0.113.7", RequestID: "req-1"})
+
+	event := audit.NewEvent(ctx, model.AuditUserDeleted, model.AuditTargetUser, 3, model.AuditChanges{})
+
+	assert.Nil(s.T(), event.ActorUserID)
+	assert.Equal(s.T(), uint(5), *event.ActorAPIKeyID)
+	assert.Equal(s.T(), "billing", event.ActorService)
+	assert.Equal(s.T(), "req-1", event.RequestID)
+	assert.Equal(s.T(), time.UTC, event.CreatedAt.Location())
+	assert.Zero(s.T(), event.CreatedAt.Nanosecond()%int(time.Microsecond))
+}
+
+func (s *AuditTestSuite) TestHash_Deterministic() {
+	first, err := audit.Hash("", s.event)
+	assert.NoError(s.T(), err)
+	second, _ := audit.Hash("", s.event)
+
+	assert.Len(s.T(), first, 64)
+	assert.Equal(s.T(), first, second)
+}
+
+func (s *AuditTestSuite) TestHash_CoversContentAndChain() {
+	original, _ := audit.Hash("", s.event)
+
+	chained, _ := audit.Hash("abc", s.event)
+	assert.NotEqual(s.T(), original, chained)
+
+	s.event.Changes["username"] = model.AuditChange{Before: "old", After: "forged"}
+	changed, _ := audit.Hash("", s.event)
+	assert.NotEqual(s.T(), original, changed)
+}
+
+func (s *AuditTestSuite) TestHash_SameInstantInOtherZone() {
+	original, _ := audit.Hash("", s.event)
+
+	s.event.CreatedAt = s.event.CreatedAt.In(time.FixedZone("ICT", 7*60*60))
+	local, _ := audit.Hash("", s.event)
+
+	assert.Equal(s.T(), original, local)
+}
+
+func (s *AuditTestSuite) TestDiff() {
+	before := map[string]interface{}{"username": "old", "email": "a@example.com", "role": "user"}
+	after := map[string]interface{}{"username": "new", "email": "a@example.com", "role": "user"}
+
+	changes := audit.Diff(before, after)
+
+	assert.Equal(s.T(), model.AuditChanges{"username": {Before: "old", After: "new"}}, changes)
+}
+
+func (s *AuditTestSuite) TestDiff_CreatedAndDeleted() {
+	fields := map[string]interface{}{"username": "new"}
+
+	assert.Equal(s.T(), model.AuditChanges{"username": {After: "new"}}, audit.Diff(nil, fields))
+	assert.Equal(s.T(), model.AuditChanges{"username": {Before: "new"}}, audit.Diff(fields, nil))
+}
+
+func (s *AuditTestSuite) TestRedact() {
+	changes := model.AuditChanges{
+		"password": {Before: "$2a$old", After: "$2a$new"},
+		"username": {Before: "old", After: "new"},
+	}
+
+	audit.Redact(changes, "password", "missing")
+
+	assert.Equal(s.T(), model.AuditChange{Before: audit.Redacted, After: audit.Redacted}, changes["password"])
+	assert.Equal(s.T(), "new", changes["username"].After)
+	assert.NotContains(s.T(), changes, "missing")
+}
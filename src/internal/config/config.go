@@ -5,14 +5,35 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	App      AppConfig      `mapstructure:"app"`
+	Server      ServerConfig      `mapstructure:"server"`
+	Database    DatabaseConfig    `mapstructure:"database"`
+	App         AppConfig         `mapstructure:"app"`
+	Idempotency IdempotencyConfig `mapstructure:"idempotency"`
+	Auth        AuthConfig        `mapstructure:"auth"`
+	Password    PasswordConfig    `mapstructure:"password"`
+	Mail        MailConfig        `mapstructure:"mail"`
+
+	EmailVerification EmailVerificationConfig `mapstructure:"email_verification"`
+	MFA               MFAConfig               `mapstructure:"mfa"`
+	Lockout           LockoutConfig           `mapstructure:"lockout"`
+	OIDC              OIDCConfig              `mapstructure:"oidc"`
+	IdentityProvider  IdentityProviderConfig  `mapstructure:"identity_provider"`
+	MagicLink         MagicLinkConfig         `mapstructure:"magic_link"`
+	WebAuthn          WebAuthnConfig          `mapstructure:"webauthn"`
+	Invitation        InvitationConfig        `mapstructure:"invitation"`
+	Tenant            TenantConfig            `mapstructure:"tenant"`
+	Outbox            OutboxConfig            `mapstructure:"outbox"`
+	Webhook           WebhookConfig           `mapstructure:"webhook"`
+	Jobs              JobsConfig              `mapstructure:"jobs"`
+	Scheduler         SchedulerConfig         `mapstructure:"scheduler"`
+	EventStream       EventStreamConfig       `mapstructure:"event_stream"`
+	Gateway           GatewayConfig           `mapstructure:"gateway"`
 }
 
 type ServerConfig struct {
@@ -35,6 +56,286 @@ type AppConfig struct {
 	Debug       bool   `mapstructure:"debug"`
 }
 
+type IdempotencyConfig struct {
+	TTL         time.Duration `mapstructure:"ttl"`
+	LockTimeout time.Duration `mapstructure:"lock_timeout"`
+}
+
+// AuthConfig configures sessions and access tokens. Session state is cached
+// in memory for SessionCacheTTL, which bounds how long a revocation made by
+// another instance can go unnoticed. LastSeenAt is written at most once per
+// LastSeenInterval. SelfRegistration lets people without an account create
+// one, through POST /users or by signing in with a magic link; without it
+// accounts are created by admins or through invitations.
+type AuthConfig struct {
+	JWTSecret        string        `mapstructure:"jwt_secret"`
+	Issuer           string        `mapstructure:"issuer"`
+	SessionTTL       time.Duration `mapstructure:"session_ttl"`
+	SessionCacheTTL  time.Duration `mapstructure:"session_cache_ttl"`
+	LastSeenInterval time.Duration `mapstructure:"last_seen_interval"`
+	SelfRegistration bool          `mapstructure:"self_registration"`
+}
+
+type PasswordConfig struct {
+	MinLength        int           `mapstructure:"min_length"`
+	RequireUpper     bool          `mapstructure:"require_upper"`
+	RequireLower     bool          `mapstructure:"require_lower"`
+	RequireDigit     bool          `mapstructure:"require_digit"`
+	RequireSymbol    bool          `mapstructure:"require_symbol"`
+	DisallowIdentity bool          `mapstructure:"disallow_identity"`
+	HistorySize      int           `mapstructure:"history_size"`
+	ResetTokenTTL    time.Duration `mapstructure:"reset_token_ttl"`
+	ResetURL         string        `mapstructure:"reset_url"`
+
+	Hasher PasswordHasherConfig `mapstructure:"hasher"`
+}
+
+type PasswordHasherConfig struct {
+	Algorithm  string         `mapstructure:"algorithm"`
+	BcryptCost int            `mapstructure:"bcrypt_cost"`
+	Argon2id   Argon2idConfig `mapstructure:"argon2id"`
+}
+
+// Argon2idConfig holds the argon2id cost parameters. Memory is in KiB.
+type Argon2idConfig struct {
+	Memory      uint32 `mapstructure:"memory"`
+	Iterations  uint32 `mapstructure:"iterations"`
+	Parallelism uint8  `mapstructure:"parallelism"`
+	SaltLength  uint32 `mapstructure:"salt_length"`
+	KeyLength   uint32 `mapstructure:"key_length"`
+}
+
+type MailConfig struct {
+	Driver  string     `mapstructure:"driver"`
+	From    string     `mapstructure:"from"`
+	FileDir string     `mapstructure:"file_dir"`
+	SMTP    SMTPConfig `mapstructure:"smtp"`
+}
+
+type SMTPConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+// MFAConfig configures TOTP second factors. EncryptionKey protects the
+// stored TOTP secrets; users with one of RequiredRoles must enroll before
+// they can log in.
+type MFAConfig struct {
+	Issuer        string        `mapstructure:"issuer"`
+	EncryptionKey string        `mapstructure:"encryption_key"`
+	ChallengeTTL  time.Duration `mapstructure:"challenge_ttl"`
+	RecoveryCodes int           `mapstructure:"recovery_codes"`
+	RequiredRoles []string      `mapstructure:"required_roles"`
+}
+
+// LockoutConfig configures brute-force protection on login. Each failed
+// attempt on an account delays the next one by BaseDelay, doubling up to
+// MaxDelay. MaxFailures failures within FailureWindow lock the account for
+// Duration; IPMaxFailures failures from one address lock that address for
+// IPDuration.
+type LockoutConfig struct {
+	MaxFailures   int           `mapstructure:"max_failures"`
+	Duration      time.Duration `mapstructure:"duration"`
+	IPMaxFailures int           `mapstructure:"ip_max_failures"`
+	IPDuration    time.Duration `mapstructure:"ip_duration"`
+	FailureWindow time.Duration `mapstructure:"failure_window"`
+	BaseDelay     time.Duration `mapstructure:"base_delay"`
+	MaxDelay      time.Duration `mapstructure:"max_delay"`
+}
+
+type EmailVerificationConfig struct {
+	TokenTTL       time.Duration `mapstructure:"token_ttl"`
+	ResendInterval time.Duration `mapstructure:"resend_interval"`
+	VerifyURL      string        `mapstructure:"verify_url"`
+}
+
+// OIDCConfig configures sign-in with external OpenID Connect providers,
+// keyed by the provider name used in the login URLs. StateTTL bounds how
+// long a user may take to sign in at the provider.
+type OIDCConfig struct {
+	StateTTL  time.Duration                 `mapstructure:"state_ttl"`
+	Providers map[string]OIDCProviderConfig `mapstructure:"providers"`
+}
+
+// OIDCProviderConfig describes one provider. RedirectURL must be registered
+// with the provider and lead to the callback route. Scopes are requested in
+// addition to "openid".
+type OIDCProviderConfig struct {
+	Issuer       string   `mapstructure:"issuer"`
+	ClientID     string   `mapstructure:"client_id"`
+	ClientSecret string   `mapstructure:"client_secret"`
+	RedirectURL  string   `mapstructure:"redirect_url"`
+	Scopes       []string `mapstructure:"scopes"`
+}
+
+// IdentityProviderConfig configures this service as an OpenID Connect
+// provider for other apps; it is off while Issuer is empty. Issuer is the
+// public base URL of the service. LoginURL, if set, is the page that signs
+// users in and passes authorization requests on to the API; it is published
+// as the authorization endpoint. Signing keys are encrypted at rest with
+// EncryptionKey and replaced every KeyRotationInterval.
+type IdentityProviderConfig struct {
+	Issuer              string        `mapstructure:"issuer"`
+	LoginURL            string        `mapstructure:"login_url"`
+	EncryptionKey       string        `mapstructure:"encryption_key"`
+	KeyRotationInterval time.Duration `mapstructure:"key_rotation_interval"`
+	CodeTTL             time.Duration `mapstructure:"code_ttl"`
+	TokenTTL            time.Duration `mapstructure:"token_ttl"`
+}
+
+// MagicLinkConfig configures passwordless login. LoginURL is the page the
+// emailed link opens; it redeems the token with a POST, so that mail
+// scanners fetching the link do not use it up. At most MaxPerWindow links
+// are sent to one address per Window.
+type MagicLinkConfig struct {
+	TokenTTL     time.Duration `mapstructure:"token_ttl"`
+	LoginURL     string        `mapstructure:"login_url"`
+	MaxPerWindow int           `mapstructure:"max_per_window"`
+	Window       time.Duration `mapstructure:"window"`
+}
+
+// WebAuthnConfig configures passkeys. RPID is the domain passkeys are
+// bound to and must be the host of each of Origins, or a parent domain of
+// it; changing it orphans all registered passkeys. ChallengeTTL bounds how
+// long a registration or login may take.
+type WebAuthnConfig struct {
+	RPID         string        `mapstructure:"rp_id"`
+	RPName       string        `mapstructure:"rp_name"`
+	Origins      []string      `mapstructure:"origins"`
+	ChallengeTTL time.Duration `mapstructure:"challenge_ttl"`
+}
+
+// InvitationConfig configures invitations. AcceptURL is the page the
+// emailed link opens, where the invitee picks a username and password.
+type InvitationConfig struct {
+	TokenTTL  time.Duration `mapstructure:"token_ttl"`
+	AcceptURL string        `mapstructure:"accept_url"`
+}
+
+// TenantConfig configures how the organization of a request is found: from
+// the Header (an organization slug), from the subdomain of BaseDomain the
+// request was sent to, or from the access token. Requests with none of
+// these belong to DefaultOrganization.
+type TenantConfig struct {
+	Header              string `mapstructure:"header"`
+	BaseDomain          string `mapstructure:"base_domain"`
+	DefaultOrganization string `mapstructure:"default_organization"`
+}
+
+// OutboxConfig configures the relay that publishes domain events from the
+// outbox with the Publisher driver, "log" or "http" (a POST to URL). The
+// relay polls every PollInterval for up to BatchSize events; a claimed event
+// is retried by any relay after Lease. Failed events are retried after
+// BaseBackoff, doubling up to MaxBackoff.
+type OutboxConfig struct {
+	Publisher      string        `mapstructure:"publisher"`
+	URL            string        `mapstructure:"url"`
+	PublishTimeout time.Duration `mapstructure:"publish_timeout"`
+	PollInterval   time.Duration `mapstructure:"poll_interval"`
+	BatchSize      int           `mapstructure:"batch_size"`
+	Lease          time.Duration `mapstructure:"lease"`
+	BaseBackoff    time.Duration `mapstructure:"base_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
+}
+
+// WebhookConfig configures outgoing webhooks. Their signing secrets are
+// stored encrypted with EncryptionKey. The worker polls every PollInterval
+// for up to BatchSize deliveries, each sent with Timeout; a claimed delivery
+// is retried by any worker after Lease. A failed delivery is retried after
+// BaseBackoff, doubling up to MaxBackoff, and dead-lettered after
+// MaxAttempts. A webhook is disabled after DisableAfter failed attempts in a
+// row. Endpoints on private, loopback and link-local addresses are refused
+// unless AllowPrivateNetworks is set, which is meant for development.
+type WebhookConfig struct {
+	EncryptionKey        string        `mapstructure:"encryption_key"`
+	Timeout              time.Duration `mapstructure:"timeout"`
+	PollInterval         time.Duration `mapstructure:"poll_interval"`
+	BatchSize            int           `mapstructure:"batch_size"`
+	Lease                time.Duration `mapstructure:"lease"`
+	MaxAttempts          int           `mapstructure:"max_attempts"`
+	BaseBackoff          time.Duration `mapstructure:"base_backoff"`
+	MaxBackoff           time.Duration `mapstructure:"max_backoff"`
+	DisableAfter         int           `mapstructure:"disable_after"`
+	AllowPrivateNetworks bool          `mapstructure:"allow_private_networks"`
+}
+
+// JobsConfig configures the background job queue. Concurrency jobs run at
+// once per process (0 runs none, for processes that only serve requests),
+// each checking for work every PollInterval when idle. A
+// running job is cancelled after VisibilityTimeout and then claimed again
+// by any worker, so a crashed worker does not lose it. A failed job is
+// retried after BaseBackoff, doubling up to MaxBackoff, until it has had
+// MaxAttempts unless enqueued with its own limit. Finished jobs are deleted
+// by a scheduled task once they are older than Retention.
+type JobsConfig struct {
+	Concurrency       int           `mapstructure:"concurrency"`
+	PollInterval      time.Duration `mapstructure:"poll_interval"`
+	VisibilityTimeout time.Duration `mapstructure:"visibility_timeout"`
+	MaxAttempts       int           `mapstructure:"max_attempts"`
+	BaseBackoff       time.Duration `mapstructure:"base_backoff"`
+	MaxBackoff        time.Duration `mapstructure:"max_backoff"`
+	Retention         time.Duration `mapstructure:"retention"`
+}
+
+// SchedulerConfig configures the scheduled tasks. One replica at a time
+// runs them, holding a lease of LeaseTTL that it renews every PollInterval
+// while it checks for due tasks; if it stops, another takes over once the
+// lease expires. A task that does not catch up on missed runs skips a run
+// that is more than MissedAfter late.
+type SchedulerConfig struct {
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	LeaseTTL     time.Duration `mapstructure:"lease_ttl"`
+	MissedAfter  time.Duration `mapstructure:"missed_after"`
+}
+
+// EventStreamConfig configures the server-sent event streams. Each process
+// checks the outbox for new events every PollInterval and keeps the latest
+// BufferSize of them for subscribers that reconnect. An event ID that is
+// missing, such as one of a transaction that has not committed yet, holds
+// back the events after it for up to GapTimeout. A subscriber that falls
+// SubscriberBuffer events behind is disconnected; at most MaxSubscribers
+// are connected at once. Streams send a heartbeat every HeartbeatInterval
+// and are closed after MaxDuration, so the client reconnects and is
+// authorized again.
+type EventStreamConfig struct {
+	PollInterval      time.Duration `mapstructure:"poll_interval"`
+	BufferSize        int           `mapstructure:"buffer_size"`
+	GapTimeout        time.Duration `mapstructure:"gap_timeout"`
+	SubscriberBuffer  int           `mapstructure:"subscriber_buffer"`
+	MaxSubscribers    int           `mapstructure:"max_subscribers"`
+	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval"`
+	MaxDuration       time.Duration `mapstructure:"max_duration"`
+}
+
+// GatewayConfig configures the WebSocket gateway. Presence is tracked by
+// the Presence driver, "memory" for a single process. Each process holds at
+// most MaxConnections, and reads client messages of up to MaxMessageSize
+// bytes. The gateway pings every PingInterval and drops connections it has
+// heard nothing from for PongTimeout; writes time out after WriteTimeout.
+// Messages to a connection wait in a queue of SendBuffer; a connection
+// whose queue is full or that leaves MaxUnacked messages unacknowledged is
+// dropped, and messages not acknowledged within AckTimeout are sent again.
+// Connections are closed after MaxDuration, so the client reconnects and
+// is authorized again. Browsers may only connect from AllowedOrigins.
+// Connections are opened with a single-use ticket that is valid for
+// TicketTTL.
+type GatewayConfig struct {
+	Presence       string        `mapstructure:"presence"`
+	MaxConnections int           `mapstructure:"max_connections"`
+	MaxMessageSize int64         `mapstructure:"max_message_size"`
+	PingInterval   time.Duration `mapstructure:"ping_interval"`
+	PongTimeout    time.Duration `mapstructure:"pong_timeout"`
+	WriteTimeout   time.Duration `mapstructure:"write_timeout"`
+	SendBuffer     int           `mapstructure:"send_buffer"`
+	MaxUnacked     int           `mapstructure:"max_unacked"`
+	AckTimeout     time.Duration `mapstructure:"ack_timeout"`
+	MaxDuration    time.Duration `mapstructure:"max_duration"`
+	AllowedOrigins []string      `mapstructure:"allowed_origins"`
+	TicketTTL      time.Duration `mapstructure:"ticket_ttl"`
+}
+
 // LoadConfig loads configuration using viper
 func LoadConfig() *Config {
 	viper.SetConfigName("config")
@@ -82,6 +383,156 @@ func setDefaults() {
 	viper.SetDefault("app.environment", "development")
 	viper.SetDefault("app.log_level", "info")
 	viper.SetDefault("app.debug", false)
+
+	// Idempotency defaults
+	viper.SetDefault("idempotency.ttl", "24h")
+	viper.SetDefault("idempotency.lock_timeout", "1m")
+
+	// Auth defaults
+	viper.SetDefault("auth.issuer", "go-kit-base")
+	viper.SetDefault("auth.session_ttl", "24h")
+	viper.SetDefault("auth.session_cache_ttl", "30s")
+	viper.SetDefault("auth.last_seen_interval", "1m")
+	viper.SetDefault("auth.self_registration", true)
+
+	// Password policy defaults
+	viper.SetDefault("password.min_length", 8)
+	viper.SetDefault("password.require_upper", false)
+	viper.SetDefault("password.require_lower", false)
+	viper.SetDefault("password.require_digit", false)
+	viper.SetDefault("password.require_symbol", false)
+	viper.SetDefault("password.disallow_identity", true)
+	viper.SetDefault("password.history_size", 5)
+	viper.SetDefault("password.reset_token_ttl", "1h")
+	viper.SetDefault("password.reset_url", "http://localhost:8080/reset-password")
+	viper.SetDefault("password.hasher.algorithm", "argon2id")
+	viper.SetDefault("password.hasher.bcrypt_cost", 12)
+	viper.SetDefault("password.hasher.argon2id.memory", 65536)
+	viper.SetDefault("password.hasher.argon2id.iterations", 3)
+	viper.SetDefault("password.hasher.argon2id.parallelism", 2)
+	viper.SetDefault("password.hasher.argon2id.salt_length", 16)
+	viper.SetDefault("password.hasher.argon2id.key_length", 32)
+
+	// Mail defaults
+	viper.SetDefault("mail.driver", "log")
+	viper.SetDefault("mail.from", "no-reply@localhost")
+	viper.SetDefault("mail.file_dir", "./tmp/mail")
+	viper.SetDefault("mail.smtp.host", "localhost")
+	viper.SetDefault("mail.smtp.port", 25)
+	viper.SetDefault("mail.smtp.username", "")
+	viper.SetDefault("mail.smtp.password", "")
+
+	// MFA defaults
+	viper.SetDefault("mfa.issuer", "go-kit-base")
+	viper.SetDefault("mfa.encryption_key", "")
+	viper.SetDefault("mfa.challenge_ttl", "5m")
+	viper.SetDefault("mfa.recovery_codes", 10)
+	viper.SetDefault("mfa.required_roles", []string{})
+
+	// Lockout defaults
+	viper.SetDefault("lockout.max_failures", 5)
+	viper.SetDefault("lockout.duration", "15m")
+	viper.SetDefault("lockout.ip_max_failures", 50)
+	viper.SetDefault("lockout.ip_duration", "15m")
+	viper.SetDefault("lockout.failure_window", "15m")
+	viper.SetDefault("lockout.base_delay", "1s")
+	viper.SetDefault("lockout.max_delay", "30s")
+
+	// Email verification defaults
+	viper.SetDefault("email_verification.token_ttl", "24h")
+	viper.SetDefault("email_verification.resend_interval", "1m")
+	viper.SetDefault("email_verification.verify_url", "http://localhost:8080/verify-email")
+
+	// OIDC defaults
+	viper.SetDefault("oidc.state_ttl", "10m")
+
+	// Identity provider defaults
+	viper.SetDefault("identity_provider.issuer", "")
+	viper.SetDefault("identity_provider.login_url", "")
+	viper.SetDefault("identity_provider.encryption_key", "")
+	viper.SetDefault("identity_provider.key_rotation_interval", "720h")
+	viper.SetDefault("identity_provider.code_ttl", "1m")
+	viper.SetDefault("identity_provider.token_ttl", "1h")
+
+	// Magic link defaults
+	viper.SetDefault("magic_link.token_ttl", "15m")
+	viper.SetDefault("magic_link.login_url", "http://localhost:8080/magic-link")
+	viper.SetDefault("magic_link.max_per_window", 3)
+	viper.SetDefault("magic_link.window", "15m")
+
+	// WebAuthn defaults
+	viper.SetDefault("webauthn.rp_id", "localhost")
+	viper.SetDefault("webauthn.rp_name", "go-kit-base")
+	viper.SetDefault("webauthn.origins", []string{"http://localhost:8080"})
+	viper.SetDefault("webauthn.challenge_ttl", "5m")
+
+	// Invitation defaults
+	viper.SetDefault("invitation.token_ttl", "168h")
+	viper.SetDefault("invitation.accept_url", "http://localhost:8080/accept-invitation")
+
+	// Tenant defaults
+	viper.SetDefault("tenant.header", "X-Organization")
+	viper.SetDefault("tenant.base_domain", "")
+	viper.SetDefault("tenant.default_organization", "default")
+
+	// Outbox defaults
+	viper.SetDefault("outbox.publisher", "log")
+	viper.SetDefault("outbox.url", "")
+	viper.SetDefault("outbox.publish_timeout", "10s")
+	viper.SetDefault("outbox.poll_interval", "1s")
+	viper.SetDefault("outbox.batch_size", 100)
+	viper.SetDefault("outbox.lease", "1m")
+	viper.SetDefault("outbox.base_backoff", "1s")
+	viper.SetDefault("outbox.max_backoff", "1h")
+
+	// Webhook defaults
+	viper.SetDefault("webhook.encryption_key", "")
+	viper.SetDefault("webhook.timeout", "10s")
+	viper.SetDefault("webhook.poll_interval", "1s")
+	viper.SetDefault("webhook.batch_size", 20)
+	viper.SetDefault("webhook.lease", "1m")
+	viper.SetDefault("webhook.max_attempts", 10)
+	viper.SetDefault("webhook.base_backoff", "10s")
+	viper.SetDefault("webhook.max_backoff", "6h")
+	viper.SetDefault("webhook.disable_after", 50)
+	viper.SetDefault("webhook.allow_private_networks", false)
+
+	// Job queue defaults
+	viper.SetDefault("jobs.concurrency", 4)
+	viper.SetDefault("jobs.poll_interval", "1s")
+	viper.SetDefault("jobs.visibility_timeout", "5m")
+	viper.SetDefault("jobs.max_attempts", 5)
+	viper.SetDefault("jobs.base_backoff", "10s")
+	viper.SetDefault("jobs.max_backoff", "1h")
+	viper.SetDefault("jobs.retention", "720h")
+
+	// Scheduler defaults
+	viper.SetDefault("scheduler.poll_interval", "5s")
+	viper.SetDefault("scheduler.lease_ttl", "30s")
+	viper.SetDefault("scheduler.missed_after", "5m")
+
+	// Event stream defaults
+	viper.SetDefault("event_stream.poll_interval", "1s")
+	viper.SetDefault("event_stream.buffer_size", 1000)
+	viper.SetDefault("event_stream.gap_timeout", "5s")
+	viper.SetDefault("event_stream.subscriber_buffer", 100)
+	viper.SetDefault("event_stream.max_subscribers", 1000)
+	viper.SetDefault("event_stream.heartbeat_interval", "15s")
+	viper.SetDefault("event_stream.max_duration", "30m")
+
+	// Gateway defaults
+	viper.SetDefault("gateway.presence", "memory")
+	viper.SetDefault("gateway.max_connections", 10000)
+	viper.SetDefault("gateway.max_message_size", 4096)
+	viper.SetDefault("gateway.ping_interval", "30s")
+	viper.SetDefault("gateway.pong_timeout", "75s")
+	viper.SetDefault("gateway.write_timeout", "10s")
+	viper.SetDefault("gateway.send_buffer", 64)
+	viper.SetDefault("gateway.max_unacked", 100)
+	viper.SetDefault("gateway.ack_timeout", "30s")
+	viper.SetDefault("gateway.max_duration", "30m")
+	viper.SetDefault("gateway.allowed_origins", []string{})
+	viper.SetDefault("gateway.ticket_ttl", "30s")
 }
 
 // GetDSN returns the database connection string
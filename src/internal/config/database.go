@@ -4,6 +4,8 @@ import (
 	"log"
 	"strings"
 
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -34,6 +36,12 @@ func (c *Config) ConnectDB() *gorm.DB {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
+	// Scope queries on organization-owned tables to the organization of the
+	// request
+	if err := db.Use(tenant.Plugin{}); err != nil {
+		log.Fatal("Failed to register tenant plugin:", err)
+	}
+
 	log.Println("Database connected successfully")
 	return db
 }
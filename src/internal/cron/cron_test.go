@@ -0,0 +1,113 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type CronTestSuite struct {
+	suite.Suite
+	// now is a Wednesday
+	now time.Time
+}
+
+func (s *CronTestSuite) SetupTest() {
+	s.now = time.Date(2025, time.January, 15, 10, 30, 0, 0, time.UTC)
+}
+
+func TestCronSuite(t *testing.T) {
+	suite.Run(t, new(CronTestSuite))
+}
+
+func (s *CronTestSuite) next(expr string, from time.Time) time.Time {
+	schedule, err := Parse(expr)
+	if err != nil {
+		s.T().Fatalf("Failed to parse %q: %v", expr, err)
+	}
+	return schedule.Next(from)
+}
+
+func (s *CronTestSuite) TestNext() {
+	cases := map[string]time.Time{
+		"* * * * *":        time.Date(2025, time.January, 15, 10, 31, 0, 0, time.UTC),
+		"*/15 * * * *":     time.Date(2025, time.January, 15, 10, 45, 0, 0, time.UTC),
+		"0 * * * *":        time.Date(2025, time.January, 15, 11, 0, 0, 0, time.UTC),
+		"30 9 * * *":       time.Date(2025, time.January, 16, 9, 30, 0, 0, time.UTC),
+		"0 0 1 * *":        time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC),
+		"0 12 * * MON-FRI": time.Date(2025, time.January, 15, 12, 0, 0, 0, time.UTC),
+		"0 12 * * sat,sun": time.Date(2025, time.January, 18, 12, 0, 0, 0, time.UTC),
+		"0 0 * * 7":        time.Date(2025, time.January, 19, 0, 0, 0, 0, time.UTC),
+		"5/20 10 * * *":    time.Date(2025, time.January, 15, 10, 45, 0, 0, time.UTC),
+		"0 0 29 FEB *":     time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC),
+		"0 8-18/4 * * *":   time.Date(2025, time.January, 15, 12, 0, 0, 0, time.UTC),
+	}
+
+	for expr, expected := range cases {
+		assert.Equal(s.T(), expected, s.next(expr, s.now), expr)
+	}
+}
+
+func (s *CronTestSuite) TestNext_Descriptors() {
+	cases := map[string]time.Time{
+		"@hourly":  time.Date(2025, time.January, 15, 11, 0, 0, 0, time.UTC),
+		"@daily":   time.Date(2025, time.January, 16, 0, 0, 0, 0, time.UTC),
+		"@weekly":  time.Date(2025, time.January, 19, 0, 0, 0, 0, time.UTC),
+		"@monthly": time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC),
+		"@yearly":  time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	for expr, expected := range cases {
+		assert.Equal(s.T(), expected, s.next(expr, s.now), expr)
+	}
+}
+
+func (s *CronTestSuite) TestNext_Every() {
+	from := s.now.Add(20 * time.Second)
+
+	assert.Equal(s.T(), from.Add(90*time.Minute), s.next("@every 1h30m", from))
+}
+
+func (s *CronTestSuite) TestNext_DayOfMonthOrDayOfWeek() {
+	// Both restricted: the 20th, or any Friday
+	assert.Equal(s.T(), time.Date(2025, time.January, 17, 0, 0, 0, 0, time.UTC), s.next("0 0 20 * FRI", s.now))
+	assert.Equal(s.T(), time.Date(2025, time.January, 20, 0, 0, 0, 0, time.UTC), s.next("0 0 20 * FRI", time.Date(2025, time.January, 17, 1, 0, 0, 0, time.UTC)))
+	// Only the day of month restricted
+	assert.Equal(s.T(), time.Date(2025, time.January, 20, 0, 0, 0, 0, time.UTC), s.next("0 0 20 * *", s.now))
+}
+
+func (s *CronTestSuite) TestNext_InUTC() {
+	bangkok := time.FixedZone("ICT", 7*60*60)
+
+	next := s.next("0 0 * * *", time.Date(2025, time.January, 15, 3, 0, 0, 0, bangkok))
+
+	assert.Equal(s.T(), time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC), next)
+}
+
+func (s *CronTestSuite) TestNext_NeverMatches() {
+	assert.True(s.T(), s.next("0 0 30 2 *", s.now).IsZero())
+}
+
+func (s *CronTestSuite) TestParse_Invalid() {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"* * * foo *",
+		"@every",
+		"@every 10ms",
+		"@fortnightly",
+	} {
+		_, err := Parse(expr)
+		assert.Error(s.T(), err, expr)
+	}
+}
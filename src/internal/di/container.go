@@ -2,9 +2,20 @@ package di
 
 import (
 	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/gateway"
 	"github.com/weeranieb/go-kit-base/src/internal/handler"
+	"github.com/weeranieb/go-kit-base/src/internal/hasher"
+	"github.com/weeranieb/go-kit-base/src/internal/jobs"
+	"github.com/weeranieb/go-kit-base/src/internal/mailer"
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
+	"github.com/weeranieb/go-kit-base/src/internal/oidc"
+	"github.com/weeranieb/go-kit-base/src/internal/outbox"
+	"github.com/weeranieb/go-kit-base/src/internal/presence"
 	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/scheduler"
 	"github.com/weeranieb/go-kit-base/src/internal/service"
+	"github.com/weeranieb/go-kit-base/src/internal/stream"
+	"github.com/weeranieb/go-kit-base/src/internal/webhook"
 
 	"go.uber.org/dig"
 )
@@ -12,17 +23,130 @@ import (
 func NewContainer(conf *config.Config) *dig.Container {
 	c := dig.New()
 
+	c.Provide(func() *config.Config { return conf })
 	c.Provide(conf.ConnectDB)
 
 	// Repository
 	c.Provide(repository.NewUserRepository)
+	c.Provide(repository.NewIdempotencyRepository)
+	c.Provide(repository.NewSessionRepository)
+	c.Provide(repository.NewPasswordHistoryRepository)
+	c.Provide(repository.NewPasswordResetRepository)
+	c.Provide(repository.NewEmailVerificationRepository)
+	c.Provide(repository.NewMFARepository)
+	c.Provide(repository.NewLoginAttemptRepository)
+	c.Provide(repository.NewLoginThrottleRepository)
+	c.Provide(repository.NewAPIKeyRepository)
+	c.Provide(repository.NewExternalIdentityRepository)
+	c.Provide(repository.NewOIDCStateRepository)
+	c.Provide(repository.NewOAuthClientRepository)
+	c.Provide(repository.NewOAuthConsentRepository)
+	c.Provide(repository.NewOAuthCodeRepository)
+	c.Provide(repository.NewSigningKeyRepository)
+	c.Provide(repository.NewMagicLinkRepository)
+	c.Provide(repository.NewPasskeyRepository)
+	c.Provide(repository.NewPasskeyChallengeRepository)
+	c.Provide(repository.NewInvitationRepository)
+	c.Provide(repository.NewOrganizationRepository)
+	c.Provide(repository.NewMembershipRepository)
+	c.Provide(repository.NewGroupRepository)
+	c.Provide(repository.NewAuditRepository)
+	c.Provide(repository.NewUserVersionRepository)
+	c.Provide(repository.NewOutboxRepository)
+	c.Provide(repository.NewWebhookRepository)
+	c.Provide(repository.NewJobRepository)
+	c.Provide(repository.NewScheduleRepository)
+	c.Provide(repository.NewLeaseRepository)
+	c.Provide(repository.NewGatewayTicketRepository)
+	c.Provide(repository.NewTransactor)
+
+	// Mailer
+	c.Provide(mailer.NewMailer)
+
+	// Domain events
+	c.Provide(outbox.NewPublisher)
+	c.Provide(outbox.NewRelay)
+	c.Provide(webhook.NewWorker)
+	c.Provide(stream.NewHub)
+
+	// WebSocket gateway and the presence of its users
+	c.Provide(presence.NewStore)
+	c.Provide(gateway.NewGateway)
+
+	// Background jobs; handlers join the worker through the handler group
+	c.Provide(jobs.NewSendEmailHandler, dig.Group(jobs.HandlerGroup))
+	c.Provide(jobs.NewWorker)
+
+	// Scheduled tasks; tasks join the scheduler through the task group
+	c.Provide(scheduler.NewIdempotencyCleanupTask, dig.Group(scheduler.TaskGroup))
+	c.Provide(scheduler.NewJobRetentionTask, dig.Group(scheduler.TaskGroup))
+	c.Provide(scheduler.NewGatewayTicketCleanupTask, dig.Group(scheduler.TaskGroup))
+	c.Provide(scheduler.NewScheduler)
+
+	// OpenID Connect providers
+	c.Provide(oidc.NewProviders)
+
+	// Hasher
+	c.Provide(hasher.NewPasswordHasher)
 
 	// Service
+	c.Provide(service.NewPasswordPolicy)
+	c.Provide(service.NewTokenService)
+	c.Provide(service.NewSessionService)
+	c.Provide(service.NewAPIKeyService)
+	c.Provide(service.NewEmailVerificationService)
+	c.Provide(service.NewMFAService)
+	c.Provide(service.NewLockoutService)
 	c.Provide(service.NewUserService)
+	c.Provide(service.NewAuthService)
+	c.Provide(service.NewPasswordService)
+	c.Provide(service.NewOIDCLoginService)
+	c.Provide(service.NewSigningKeyService)
+	c.Provide(service.NewOAuthClientService)
+	c.Provide(service.NewIdentityProviderService)
+	c.Provide(service.NewMagicLinkService)
+	c.Provide(service.NewPasskeyService)
+	c.Provide(service.NewInvitationService)
+	c.Provide(service.NewOrganizationService)
+	c.Provide(service.NewGroupService)
+	c.Provide(service.NewAuditService)
+	c.Provide(service.NewWebhookService)
+	c.Provide(service.NewJobService)
+	c.Provide(service.NewScheduleService)
+	c.Provide(service.NewPresenceService)
+	c.Provide(service.NewGatewayTicketService)
 
 	// Handler
 	c.Provide(handler.NewUserHandler)
+	c.Provide(handler.NewAuthHandler)
+	c.Provide(handler.NewPasswordHandler)
+	c.Provide(handler.NewEmailVerificationHandler)
+	c.Provide(handler.NewMFAHandler)
+	c.Provide(handler.NewLockoutHandler)
+	c.Provide(handler.NewSessionHandler)
+	c.Provide(handler.NewAPIKeyHandler)
+	c.Provide(handler.NewOIDCHandler)
+	c.Provide(handler.NewIdentityProviderHandler)
+	c.Provide(handler.NewMagicLinkHandler)
+	c.Provide(handler.NewPasskeyHandler)
+	c.Provide(handler.NewInvitationHandler)
+	c.Provide(handler.NewOrganizationHandler)
+	c.Provide(handler.NewGroupHandler)
+	c.Provide(handler.NewAuditHandler)
+	c.Provide(handler.NewWebhookHandler)
+	c.Provide(handler.NewJobHandler)
+	c.Provide(handler.NewScheduleHandler)
+	c.Provide(handler.NewUserEventHandler)
+	c.Provide(handler.NewGatewayHandler)
+	c.Provide(handler.NewPresenceHandler)
 	c.Provide(handler.NewHandler)
 
+	// Middleware
+	c.Provide(middleware.NewIdempotencyMiddleware)
+	c.Provide(middleware.NewAuthMiddleware)
+	c.Provide(middleware.NewTenantMiddleware)
+	c.Provide(middleware.NewAuditMiddleware)
+	c.Provide(middleware.NewMiddleware)
+
 	return c
 }
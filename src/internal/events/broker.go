@@ -0,0 +1,60 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+// NATSConn is the part of a NATS connection the NATS publisher uses;
+// *nats.Conn satisfies it.
+type NATSConn interface {
+	Publish(subject string, data []byte) error
+}
+
+type natsPublisher struct {
+	conn          NATSConn
+	subjectPrefix string
+}
+
+// NewNATSPublisher returns a Publisher that sends each message as JSON to
+// the subject subjectPrefix followed by the event type, such as
+// "events.user.created".
+func NewNATSPublisher(conn NATSConn, subjectPrefix string) Publisher {
+	return &natsPublisher{conn: conn, subjectPrefix: subjectPrefix}
+}
+
+func (p *natsPublisher) Publish(ctx context.Context, msg *Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	return p.conn.Publish(p.subjectPrefix+msg.Type, data)
+}
+
+// KafkaProducer is the part of a Kafka client the Kafka publisher uses. It
+// should return once the broker has acknowledged the record.
+type KafkaProducer interface {
+	Produce(ctx context.Context, topic string, key, value []byte) error
+}
+
+type kafkaPublisher struct {
+	producer KafkaProducer
+	topic    string
+}
+
+// NewKafkaPublisher returns a Publisher that writes each message as JSON to
+// topic. Records are keyed by aggregate, so the events of one aggregate land
+// on the same partition and keep their order.
+func NewKafkaPublisher(producer KafkaProducer, topic string) Publisher {
+	return &kafkaPublisher{producer: producer, topic: topic}
+}
+
+func (p *kafkaPublisher) Publish(ctx context.Context, msg *Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	key := msg.AggregateType + ":" + strconv.FormatUint(uint64(msg.AggregateID), 10)
+	return p.producer.Produce(ctx, p.topic, []byte(key), data)
+}
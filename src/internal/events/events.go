@@ -0,0 +1,101 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// Event is a domain event. Events of the same aggregate are published in the
+// order they were stored.
+type Event interface {
+	EventType() string
+	AggregateType() string
+	AggregateID() uint
+}
+
+// Message is an event as it is handed to a Publisher. Delivery is
+// at-least-once, so consumers should use ID to skip events they have
+// already seen.
+type Message struct {
+	ID             uint      `json:"id"`
+	OrganizationID uint      `json:"organization_id"`
+	Type           string    `json:"type"`
+	AggregateType  string    `json:"aggregate_type"`
+	AggregateID    uint      `json:"aggregate_id"`
+	OccurredAt     time.Time `json:"occurred_at"`
+	// Data is the event encoded as JSON.
+	Data json.RawMessage `json:"data"`
+}
+
+// NewMessage returns the event stored in the outbox message.
+func NewMessage(message *model.OutboxMessage) *Message {
+	return &Message{
+		ID:             message.ID,
+		OrganizationID: message.OrganizationID,
+		Type:           message.EventType,
+		AggregateType:  message.AggregateType,
+		AggregateID:    message.AggregateID,
+		OccurredAt:     message.CreatedAt,
+		Data:           json.RawMessage(message.Payload),
+	}
+}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=Publisher --output=./mocks/events --outpkg=events --filename=publisher.go --structname=MockPublisher --with-expecter=false
+type Publisher interface {
+	Publish(ctx context.Context, msg *Message) error
+}
+
+// NewPublisher returns the Publisher selected by outbox.publisher.
+func NewPublisher(conf *config.Config) (Publisher, error) {
+	switch strings.ToLower(conf.Outbox.Publisher) {
+	case "", "log":
+		return NewLogPublisher(), nil
+	case "http":
+		if conf.Outbox.URL == "" {
+			return nil, fmt.Errorf("outbox.url is required by the http publisher")
+		}
+		return NewHTTPPublisher(conf.Outbox.URL, conf.Outbox.PublishTimeout), nil
+	default:
+		return nil, fmt.Errorf("unknown outbox publisher %q", conf.Outbox.Publisher)
+	}
+}
+
+type multiPublisher struct {
+	publishers []Publisher
+}
+
+// Multi returns a Publisher that hands each message to all publishers in
+// turn, and fails if any of them does. The message is then published again
+// to all of them, so each must cope with seeing it twice.
+func Multi(publishers ...Publisher) Publisher {
+	return &multiPublisher{publishers: publishers}
+}
+
+func (p *multiPublisher) Publish(ctx context.Context, msg *Message) error {
+	for _, publisher := range p.publishers {
+		if err := publisher.Publish(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type logPublisher struct{}
+
+// NewLogPublisher returns a Publisher that writes events to the application
+// log. It is meant for local development.
+func NewLogPublisher() Publisher {
+	return &logPublisher{}
+}
+
+func (p *logPublisher) Publish(ctx context.Context, msg *Message) error {
+	log.Printf("Event id=%d type=%s %s=%d %s", msg.ID, msg.Type, msg.AggregateType, msg.AggregateID, msg.Data)
+	return nil
+}
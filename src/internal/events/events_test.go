@@ -0,0 +1,150 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+)
+
+// fakeNATSConn and fakeKafkaProducer record what is published, in place of
+// a broker.
+type fakeNATSConn struct {
+	subjects []string
+	data     [][]byte
+	err      error
+}
+
+func (c *fakeNATSConn) Publish(subject string, data []byte) error {
+	c.subjects = append(c.subjects, subject)
+	c.data = append(c.data, data)
+	return c.err
+}
+
+type fakeKafkaProducer struct {
+	topic string
+	keys  []string
+	err   error
+}
+
+func (p *fakeKafkaProducer) Produce(ctx context.Context, topic string, key, value []byte) error {
+	p.topic = topic
+	p.keys = append(p.keys, string(key))
+	return p.err
+}
+
+type EventsTestSuite struct {
+	suite.Suite
+	msg *Message
+}
+
+func (s *EventsTestSuite) SetupTest() {
+	data, _ := json.Marshal(UserDeleted{UserID: 3, OrganizationID: 1})
+	s.msg = &Message{
+		ID:             42,
+		OrganizationID: 1,
+		Type:           UserDeletedType,
+		AggregateType:  AggregateUser,
+		AggregateID:    3,
+		OccurredAt:     time.Date(2025, 12, 2, 9, 0, 0, 0, time.UTC),
+		Data:           data,
+	}
+}
+
+func TestEventsSuite(t *testing.T) {
+	suite.Run(t, new(EventsTestSuite))
+}
+
+func (s *EventsTestSuite) TestNewPublisher() {
+	p, err := NewPublisher(&config.Config{Outbox: config.OutboxConfig{Publisher: "log"}})
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), p.Publish(context.Background(), s.msg))
+
+	_, err = NewPublisher(&config.Config{Outbox: config.OutboxConfig{Publisher: "http"}})
+	assert.Error(s.T(), err)
+
+	_, err = NewPublisher(&config.Config{Outbox: config.OutboxConfig{Publisher: "carrier-pigeon"}})
+	assert.Error(s.T(), err)
+}
+
+func (s *EventsTestSuite) TestUserEvents() {
+	var event Event = UserUpdated{User: UserSnapshot{ID: 3}}
+
+	assert.Equal(s.T(), UserUpdatedType, event.EventType())
+	assert.Equal(s.T(), AggregateUser, event.AggregateType())
+	assert.Equal(s.T(), uint(3), event.AggregateID())
+}
+
+func (s *EventsTestSuite) TestHTTPPublisher() {
+	var header http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	err := NewHTTPPublisher(server.URL, time.Second).Publish(context.Background(), s.msg)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "42", header.Get("X-Event-ID"))
+	assert.Equal(s.T(), UserDeletedType, header.Get("X-Event-Type"))
+	assert.JSONEq(s.T(), `{
+		"id": 42,
+		"organization_id": 1,
+		"type": "user.deleted",
+		"aggregate_type": "user",
+		"aggregate_id": 3,
+		"occurred_at": "2025-12-02T09:00:00Z",
+		"data": {"user_id": 3, "organization_id": 1}
+	}`, string(body))
+}
+
+func (s *EventsTestSuite) TestHTTPPublisher_Rejected() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	err := NewHTTPPublisher(server.URL, time.Second).Publish(context.Background(), s.msg)
+
+	assert.ErrorContains(s.T(), err, "503")
+}
+
+func (s *EventsTestSuite) TestNATSPublisher() {
+	conn := &fakeNATSConn{}
+
+	err := NewNATSPublisher(conn, "events.").Publish(context.Background(), s.msg)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"events.user.deleted"}, conn.subjects)
+	var sent Message
+	assert.NoError(s.T(), json.Unmarshal(conn.data[0], &sent))
+	assert.Equal(s.T(), s.msg.ID, sent.ID)
+
+	conn.err = errors.New("nats: connection closed")
+	assert.Error(s.T(), NewNATSPublisher(conn, "events.").Publish(context.Background(), s.msg))
+}
+
+func (s *EventsTestSuite) TestKafkaPublisher() {
+	producer := &fakeKafkaProducer{}
+
+	err := NewKafkaPublisher(producer, "domain-events").Publish(context.Background(), s.msg)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "domain-events", producer.topic)
+	// Keyed by aggregate so that its events share a partition
+	assert.Equal(s.T(), []string{"user:3"}, producer.keys)
+
+	producer.err = errors.New("kafka: leader not available")
+	assert.Error(s.T(), NewKafkaPublisher(producer, "domain-events").Publish(context.Background(), s.msg))
+}
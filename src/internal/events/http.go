@@ -0,0 +1,55 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type httpPublisher struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewHTTPPublisher returns a Publisher that POSTs each message as JSON to
+// url. Any response other than 2xx fails the delivery, so the receiver sees
+// a message again until it accepts it.
+func NewHTTPPublisher(url string, timeout time.Duration) Publisher {
+	return &httpPublisher{
+		url:        url,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *httpPublisher) Publish(ctx context.Context, msg *Message) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event-ID", strconv.FormatUint(uint64(msg.ID), 10))
+	req.Header.Set("X-Event-Type", msg.Type)
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("event endpoint responded with %d", resp.StatusCode)
+	}
+	return nil
+}
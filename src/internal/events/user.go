@@ -0,0 +1,71 @@
+package events
+
+import (
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// Types of the events of users.
+const (
+	AggregateUser = "user"
+
+	UserCreatedType = "user.created"
+	UserUpdatedType = "user.updated"
+	UserDeletedType = "user.deleted"
+)
+
+// UserSnapshot is the state of a user carried by its events. It never
+// includes the password.
+type UserSnapshot struct {
+	ID              uint       `json:"id"`
+	OrganizationID  uint       `json:"organization_id"`
+	Username        string     `json:"username"`
+	Email           string     `json:"email"`
+	PendingEmail    *string    `json:"pending_email"`
+	Role            string     `json:"role"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	Version         uint       `json:"version"`
+}
+
+func NewUserSnapshot(user *model.User) UserSnapshot {
+	return UserSnapshot{
+		ID:              user.ID,
+		OrganizationID:  user.OrganizationID,
+		Username:        user.Username,
+		Email:           user.Email,
+		PendingEmail:    user.PendingEmail,
+		Role:            user.Role,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		Version:         user.Version,
+	}
+}
+
+// UserCreated is emitted when a user is created.
+type UserCreated struct {
+	User UserSnapshot `json:"user"`
+}
+
+func (e UserCreated) EventType() string     { return UserCreatedType }
+func (e UserCreated) AggregateType() string { return AggregateUser }
+func (e UserCreated) AggregateID() uint     { return e.User.ID }
+
+// UserUpdated is emitted when a user is changed, with the user as it is
+// after the change.
+type UserUpdated struct {
+	User UserSnapshot `json:"user"`
+}
+
+func (e UserUpdated) EventType() string     { return UserUpdatedType }
+func (e UserUpdated) AggregateType() string { return AggregateUser }
+func (e UserUpdated) AggregateID() uint     { return e.User.ID }
+
+// UserDeleted is emitted when a user is deleted.
+type UserDeleted struct {
+	UserID         uint `json:"user_id"`
+	OrganizationID uint `json:"organization_id"`
+}
+
+func (e UserDeleted) EventType() string     { return UserDeletedType }
+func (e UserDeleted) AggregateType() string { return AggregateUser }
+func (e UserDeleted) AggregateID() uint     { return e.UserID }
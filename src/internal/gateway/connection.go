@@ -0,0 +1,293 @@
+package gateway
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"log"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+
+	"github.com/fasthttp/websocket"
+)
+
+// pendingMessage is a message the client has not acknowledged yet.
+type pendingMessage struct {
+	envelope *Envelope
+	sentAt   time.Time
+}
+
+// connection is a WebSocket connection of a user. Its reader runs on the
+// goroutine of Gateway.Serve and its writer on one of its own; everything
+// is written by the writer, apart from the pongs and the answer to a close
+// frame that the websocket package sends while reading.
+type connection struct {
+	id      string
+	userID  uint
+	ws      *websocket.Conn
+	gateway *Gateway
+	conf    config.GatewayConfig
+	queue   chan *Envelope
+
+	// watching holds the users whose presence the connection is subscribed
+	// to; the gateway guards it
+	watching map[uint]struct{}
+
+	mu      sync.Mutex
+	lastID  uint64
+	pending map[string]*pendingMessage
+	// draining is set once the reader has been given a last deadline,
+	// after the close frame was sent or a write failed
+	draining bool
+
+	closeOnce sync.Once
+	closing   chan struct{}
+	closeCode int
+	closeText string
+}
+
+func newConnection(g *Gateway, ws *websocket.Conn, userID uint) *connection {
+	return &connection{
+		id:       newConnectionID(),
+		userID:   userID,
+		ws:       ws,
+		gateway:  g,
+		conf:     g.conf,
+		queue:    make(chan *Envelope, g.conf.SendBuffer),
+		watching: make(map[uint]struct{}),
+		pending:  make(map[string]*pendingMessage),
+		closing:  make(chan struct{}),
+	}
+}
+
+// send queues a message for the writer. A connection whose queue is full
+// is not keeping up and is closed.
+func (c *connection) send(env *Envelope) {
+	select {
+	case c.queue <- env:
+	default:
+		c.close(websocket.ClosePolicyViolation, "too slow")
+	}
+}
+
+// deliver sends a message on a channel under a new ID, keeping it until the
+// client acknowledges it.
+func (c *connection) deliver(env *Envelope) {
+	c.mu.Lock()
+	if len(c.pending) >= c.conf.MaxUnacked {
+		c.mu.Unlock()
+		c.close(websocket.ClosePolicyViolation, "too many unacknowledged messages")
+		return
+	}
+	c.lastID++
+	env.ID = strconv.FormatUint(c.lastID, 10)
+	c.pending[env.ID] = &pendingMessage{envelope: env, sentAt: time.Now()}
+	c.mu.Unlock()
+
+	c.send(env)
+}
+
+func (c *connection) acknowledge(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.pending, id)
+}
+
+// close makes the writer send a close frame with the code and stop; code 0
+// stops it without one, for connections that are already gone.
+func (c *connection) close(code int, text string) {
+	c.closeOnce.Do(func() {
+		c.closeCode = code
+		c.closeText = text
+		close(c.closing)
+	})
+}
+
+func (c *connection) isClosing() bool {
+	select {
+	case <-c.closing:
+		return true
+	default:
+		return false
+	}
+}
+
+// readLoop handles the messages of the client until the connection fails,
+// the client closes it or answers the close frame of the writer.
+func (c *connection) readLoop(ctx context.Context) {
+	c.ws.SetReadLimit(c.conf.MaxMessageSize)
+	c.extendReadDeadline()
+	c.ws.SetPongHandler(func(string) error {
+		c.extendReadDeadline()
+		if err := c.gateway.store.Touch(ctx, c.userID, c.id); err != nil {
+			log.Printf("Failed to record gateway activity of user %d: %v", c.userID, err)
+		}
+		return nil
+	})
+
+	for {
+		messageType, data, err := c.ws.ReadMessage()
+		if err != nil {
+			return
+		}
+		c.extendReadDeadline()
+
+		// Once closing, only the answer to the close frame matters
+		if c.isClosing() {
+			continue
+		}
+		if messageType != websocket.TextMessage {
+			c.close(websocket.CloseUnsupportedData, "messages must be JSON text")
+			continue
+		}
+
+		var env Envelope
+		if err := json.Unmarshal(data, &env); err != nil {
+			c.send(&Envelope{Type: TypeError, Error: errInvalidMessage.Error()})
+			continue
+		}
+		c.handle(ctx, &env)
+	}
+}
+
+// extendReadDeadline gives the client another pong timeout to be heard
+// from, unless the connection is draining.
+func (c *connection) extendReadDeadline() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.draining {
+		c.ws.SetReadDeadline(time.Now().Add(c.conf.PongTimeout))
+	}
+}
+
+func (c *connection) handle(ctx context.Context, env *Envelope) {
+	switch env.Type {
+	case TypeAck:
+		c.acknowledge(env.ID)
+	case TypePing:
+		c.reply(env, nil, nil)
+	case TypeSubscribe:
+		data, err := c.gateway.subscribe(ctx, c, env.Channel)
+		c.reply(env, data, err)
+	case TypeUnsubscribe:
+		c.reply(env, nil, c.gateway.unsubscribe(c, env.Channel))
+	default:
+		c.reply(env, nil, errUnknownType)
+	}
+}
+
+// reply answers a request with an error, or with an ack carrying data when
+// the request has an ID to acknowledge.
+func (c *connection) reply(req *Envelope, data any, err error) {
+	if err != nil {
+		c.send(&Envelope{ID: req.ID, Type: TypeError, Channel: req.Channel, Error: err.Error()})
+		return
+	}
+	if req.ID == "" {
+		return
+	}
+
+	resp := &Envelope{ID: req.ID, Type: TypeAck, Channel: req.Channel}
+	if data != nil {
+		resp.Data = marshal(data)
+	}
+	c.send(resp)
+}
+
+// writeLoop writes queued messages, pings the client and sends messages it
+// has not acknowledged again until the connection is closing, then sends
+// the close frame.
+func (c *connection) writeLoop() {
+	ping := time.NewTicker(c.conf.PingInterval)
+	defer ping.Stop()
+	redeliver := time.NewTicker(c.conf.AckTimeout)
+	defer redeliver.Stop()
+	expire := time.NewTimer(c.conf.MaxDuration)
+	defer expire.Stop()
+
+	for {
+		var err error
+		select {
+		case env := <-c.queue:
+			err = c.write(env)
+		case <-ping.C:
+			err = c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.conf.WriteTimeout))
+		case <-redeliver.C:
+			err = c.redeliver()
+		case <-expire.C:
+			c.close(websocket.CloseGoingAway, "connection expired")
+		case <-c.closing:
+			c.sendClose()
+			return
+		}
+
+		// A failed write leaves the connection broken. The server closes it
+		// once Serve returns, so the reader is stopped instead
+		if err != nil {
+			c.drain(time.Now())
+			return
+		}
+	}
+}
+
+func (c *connection) write(env *Envelope) error {
+	data, err := json.Marshal(env)
+	if err != nil {
+		log.Printf("Failed to encode gateway message for user %d: %v", c.userID, err)
+		return nil
+	}
+	c.ws.SetWriteDeadline(time.Now().Add(c.conf.WriteTimeout))
+	return c.ws.WriteMessage(websocket.TextMessage, data)
+}
+
+// redeliver writes the messages that have not been acknowledged within the
+// ack timeout again, in the order they were first sent.
+func (c *connection) redeliver() error {
+	now := time.Now()
+
+	c.mu.Lock()
+	var due []*Envelope
+	for _, msg := range c.pending {
+		if now.Sub(msg.sentAt) >= c.conf.AckTimeout {
+			msg.sentAt = now
+			due = append(due, msg.envelope)
+		}
+	}
+	c.mu.Unlock()
+
+	slices.SortFunc(due, func(a, b *Envelope) int {
+		x, _ := strconv.ParseUint(a.ID, 10, 64)
+		y, _ := strconv.ParseUint(b.ID, 10, 64)
+		return cmp.Compare(x, y)
+	})
+	for _, env := range due {
+		if err := c.write(env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sendClose sends the close frame and gives the client a while to answer
+// it before the reader gives up.
+func (c *connection) sendClose() {
+	if c.closeCode == 0 {
+		return
+	}
+
+	message := websocket.FormatCloseMessage(c.closeCode, c.closeText)
+	c.ws.WriteControl(websocket.CloseMessage, message, time.Now().Add(c.conf.WriteTimeout))
+	c.drain(time.Now().Add(closeTimeout))
+}
+
+// drain makes the reader give up at deadline, whatever it reads until then.
+func (c *connection) drain(deadline time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.draining = true
+	c.ws.SetReadDeadline(deadline)
+}
@@ -0,0 +1,371 @@
+// Package gateway serves the WebSocket connections of users. Messages in
+// both directions are JSON envelopes. Each user has a channel, user:<id>,
+// that their connections are subscribed to and that SendToUser sends on;
+// presence:<id> channels tell when a user of the organization comes online
+// or goes offline. Messages the gateway sends on channels carry an ID and
+// must be acknowledged, or they are sent again.
+//
+// Presence is kept in a presence.Store, so a shared store sees the users
+// connected to every replica. Channels only reach the connections of their
+// own process.
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/presence"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+
+	"github.com/fasthttp/websocket"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Envelope types. Clients send subscribe, unsubscribe and ping requests,
+// which are answered with an ack or an error carrying their ID, and ack
+// the messages of the gateway that have an ID. The gateway sends connected
+// first, then presence messages and those sent with SendToUser.
+const (
+	TypeSubscribe   = "subscribe"
+	TypeUnsubscribe = "unsubscribe"
+	TypePing        = "ping"
+	TypeAck         = "ack"
+	TypeError       = "error"
+	TypeConnected   = "connected"
+	TypePresence    = "presence"
+)
+
+// Channel prefixes, followed by a user ID.
+const (
+	userChannelPrefix     = "user:"
+	presenceChannelPrefix = "presence:"
+)
+
+const (
+	// maxSubscriptions is how many presence channels a connection may
+	// subscribe to.
+	maxSubscriptions = 100
+	// closeTimeout is how long a connection waits for the client to answer
+	// its close frame.
+	closeTimeout = 5 * time.Second
+)
+
+var (
+	ErrTooManyConnections = errors.New("too many gateway connections")
+	ErrGatewayStopped     = errors.New("gateway stopped")
+)
+
+// requestError is why a request of a client failed, as told in the error
+// envelope answering it.
+type requestError string
+
+func (e requestError) Error() string {
+	return string(e)
+}
+
+const (
+	errUnknownType     requestError = "Unknown message type"
+	errInvalidMessage  requestError = "Invalid message"
+	errUnknownChannel  requestError = "Unknown channel"
+	errForeignChannel  requestError = "Cannot subscribe to the channel of another user"
+	errUserChannel     requestError = "Cannot unsubscribe from the user channel"
+	errUserNotFound    requestError = "User not found"
+	errSubscribeFailed requestError = "Failed to subscribe"
+	errTooManyChannels requestError = "Too many subscriptions"
+)
+
+// Envelope is a message on a connection.
+type Envelope struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Channel string          `json:"channel,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	Error   string          `json:"error,omitempty"`
+}
+
+// Gateway holds the WebSocket connections of a process.
+type Gateway struct {
+	store           presence.Store
+	presenceService service.PresenceService
+	conf            config.GatewayConfig
+
+	mu          sync.Mutex
+	connections map[*connection]struct{}
+	// users maps users to their connections; watchers maps users to the
+	// connections subscribed to their presence
+	users    map[uint]map[*connection]struct{}
+	watchers map[uint]map[*connection]struct{}
+	stopped  bool
+}
+
+func NewGateway(store presence.Store, presenceService service.PresenceService, conf *config.Config) *Gateway {
+	return &Gateway{
+		store:           store,
+		presenceService: presenceService,
+		conf:            conf.Gateway,
+		connections:     make(map[*connection]struct{}),
+		users:           make(map[uint]map[*connection]struct{}),
+		watchers:        make(map[uint]map[*connection]struct{}),
+	}
+}
+
+// Run waits until ctx is done, then closes all connections and refuses new
+// ones.
+func (g *Gateway) Run(ctx context.Context) {
+	<-ctx.Done()
+
+	g.mu.Lock()
+	g.stopped = true
+	connections := make([]*connection, 0, len(g.connections))
+	for c := range g.connections {
+		connections = append(connections, c)
+	}
+	g.mu.Unlock()
+
+	for _, c := range connections {
+		c.close(websocket.CloseGoingAway, "server shutting down")
+	}
+}
+
+// Connections returns how many connections are open.
+func (g *Gateway) Connections() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.connections)
+}
+
+// Serve runs the connection of the user until it closes. ctx is the context
+// of the request, scoped to its organization, which presence channels are
+// limited to.
+func (g *Gateway) Serve(ctx context.Context, ws *websocket.Conn, userID uint) {
+	c := newConnection(g, ws, userID)
+	// Queued before the connection can be sent anything else
+	c.send(&Envelope{Type: TypeConnected, Data: marshal(map[string]any{
+		"connection_id": c.id,
+		"user_id":       userID,
+		"channel":       userChannel(userID),
+	})})
+	if err := g.register(c); err != nil {
+		code := websocket.CloseTryAgainLater
+		if errors.Is(err, ErrGatewayStopped) {
+			code = websocket.CloseGoingAway
+		}
+		message := websocket.FormatCloseMessage(code, err.Error())
+		ws.WriteControl(websocket.CloseMessage, message, time.Now().Add(g.conf.WriteTimeout))
+		return
+	}
+
+	online, err := g.store.Connect(ctx, userID, c.id)
+	if err != nil {
+		log.Printf("Failed to record gateway connection of user %d: %v", userID, err)
+	}
+	if online {
+		g.notifyPresence(userID, true)
+	}
+
+	writerDone := make(chan struct{})
+	go func() {
+		defer close(writerDone)
+		c.writeLoop()
+	}()
+	c.readLoop(ctx)
+
+	// The writer stops once the connection is closing; waiting for it
+	// keeps it from outliving the connection
+	c.close(0, "")
+	<-writerDone
+
+	g.unregister(c)
+	offline, err := g.store.Disconnect(ctx, userID, c.id)
+	if err != nil {
+		log.Printf("Failed to record gateway disconnection of user %d: %v", userID, err)
+	}
+	if offline {
+		g.notifyPresence(userID, false)
+	}
+}
+
+// SendToUser sends a message of the type with data on the channel of the
+// user to their connections in this process, and returns how many there
+// were.
+func (g *Gateway) SendToUser(userID uint, messageType string, data any) (int, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return 0, err
+	}
+
+	g.mu.Lock()
+	connections := make([]*connection, 0, len(g.users[userID]))
+	for c := range g.users[userID] {
+		connections = append(connections, c)
+	}
+	g.mu.Unlock()
+
+	for _, c := range connections {
+		c.deliver(&Envelope{Type: messageType, Channel: userChannel(userID), Data: raw})
+	}
+	return len(connections), nil
+}
+
+func (g *Gateway) register(c *connection) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.stopped {
+		return ErrGatewayStopped
+	}
+	if len(g.connections) >= g.conf.MaxConnections {
+		return ErrTooManyConnections
+	}
+	g.connections[c] = struct{}{}
+	addConnection(g.users, c.userID, c)
+	return nil
+}
+
+func (g *Gateway) unregister(c *connection) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	delete(g.connections, c)
+	removeConnection(g.users, c.userID, c)
+	for userID := range c.watching {
+		removeConnection(g.watchers, userID, c)
+	}
+}
+
+// subscribe subscribes the connection to a channel and returns the data of
+// the ack: the presence of the user for presence channels.
+func (g *Gateway) subscribe(ctx context.Context, c *connection, channel string) (any, error) {
+	if strings.HasPrefix(channel, userChannelPrefix) {
+		if channel != userChannel(c.userID) {
+			return nil, errForeignChannel
+		}
+		return nil, nil
+	}
+
+	userID, ok := parseChannel(channel, presenceChannelPrefix)
+	if !ok {
+		return nil, errUnknownChannel
+	}
+
+	status, err := g.presenceService.GetPresence(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errUserNotFound
+	}
+	if err != nil {
+		log.Printf("Failed to fetch presence of user %d: %v", userID, err)
+		return nil, errSubscribeFailed
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if _, ok := c.watching[userID]; !ok {
+		if len(c.watching) >= maxSubscriptions {
+			return nil, errTooManyChannels
+		}
+		c.watching[userID] = struct{}{}
+		addConnection(g.watchers, userID, c)
+	}
+	return status, nil
+}
+
+func (g *Gateway) unsubscribe(c *connection, channel string) error {
+	if strings.HasPrefix(channel, userChannelPrefix) {
+		return errUserChannel
+	}
+
+	userID, ok := parseChannel(channel, presenceChannelPrefix)
+	if !ok {
+		return errUnknownChannel
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(c.watching, userID)
+	removeConnection(g.watchers, userID, c)
+	return nil
+}
+
+// notifyPresence tells the connections watching the user that they came
+// online or went offline.
+func (g *Gateway) notifyPresence(userID uint, online bool) {
+	now := time.Now()
+	status := &model.PresenceResponse{
+		UserID:     userID,
+		Status:     model.PresenceOffline,
+		LastSeenAt: &now,
+	}
+	if online {
+		status.Status = model.PresenceOnline
+	}
+	data := marshal(status)
+
+	g.mu.Lock()
+	watchers := make([]*connection, 0, len(g.watchers[userID]))
+	for c := range g.watchers[userID] {
+		watchers = append(watchers, c)
+	}
+	g.mu.Unlock()
+
+	for _, c := range watchers {
+		c.deliver(&Envelope{Type: TypePresence, Channel: presenceChannel(userID), Data: data})
+	}
+}
+
+func addConnection(index map[uint]map[*connection]struct{}, userID uint, c *connection) {
+	connections, ok := index[userID]
+	if !ok {
+		connections = make(map[*connection]struct{})
+		index[userID] = connections
+	}
+	connections[c] = struct{}{}
+}
+
+func removeConnection(index map[uint]map[*connection]struct{}, userID uint, c *connection) {
+	delete(index[userID], c)
+	if len(index[userID]) == 0 {
+		delete(index, userID)
+	}
+}
+
+func userChannel(userID uint) string {
+	return userChannelPrefix + strconv.FormatUint(uint64(userID), 10)
+}
+
+func presenceChannel(userID uint) string {
+	return presenceChannelPrefix + strconv.FormatUint(uint64(userID), 10)
+}
+
+// parseChannel returns the user ID of a channel with the prefix.
+func parseChannel(channel, prefix string) (uint, bool) {
+	id, ok := strings.CutPrefix(channel, prefix)
+	if !ok {
+		return 0, false
+	}
+	userID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil || userID == 0 {
+		return 0, false
+	}
+	return uint(userID), true
+}
+
+// marshal encodes values that always encode, such as responses.
+func marshal(v any) json.RawMessage {
+	data, _ := json.Marshal(v)
+	return data
+}
+
+// newConnectionID returns a connection ID that is unique across replicas,
+// as a shared presence store needs.
+func newConnectionID() string {
+	return uuid.NewString()
+}
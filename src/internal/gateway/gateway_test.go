@@ -0,0 +1,437 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/presence"
+	serviceMocks "github.com/weeranieb/go-kit-base/src/internal/service/mocks/service"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+	"gorm.io/gorm"
+)
+
+type GatewayTestSuite struct {
+	suite.Suite
+	store           presence.Store
+	presenceService *serviceMocks.MockPresenceService
+	gateway         *Gateway
+	app             *fiber.App
+	url             string
+	stop            context.CancelFunc
+}
+
+func TestGatewaySuite(t *testing.T) {
+	suite.Run(t, new(GatewayTestSuite))
+}
+
+func (s *GatewayTestSuite) SetupTest() {
+	s.store = presence.NewMemoryStore()
+	s.presenceService = serviceMocks.NewMockPresenceService(s.T())
+}
+
+func (s *GatewayTestSuite) TearDownTest() {
+	if s.app != nil {
+		s.stop()
+		s.app.Shutdown()
+		s.app = nil
+	}
+}
+
+// start serves the gateway to the user in the user query parameter, in
+// organization 1. Nothing is pinged or sent again unless configure says so.
+func (s *GatewayTestSuite) start(configure func(conf *config.GatewayConfig)) {
+	conf := &config.Config{Gateway: config.GatewayConfig{
+		MaxConnections: 10000,
+		MaxMessageSize: 1024,
+		PingInterval:   time.Hour,
+		PongTimeout:    time.Hour,
+		WriteTimeout:   5 * time.Second,
+		SendBuffer:     16,
+		MaxUnacked:     10,
+		AckTimeout:     time.Hour,
+		MaxDuration:    time.Hour,
+	}}
+	if configure != nil {
+		configure(&conf.Gateway)
+	}
+	s.gateway = NewGateway(s.store, s.presenceService, conf)
+
+	var ctx context.Context
+	ctx, s.stop = context.WithCancel(context.Background())
+	go s.gateway.Run(ctx)
+
+	s.app = fiber.New(fiber.Config{DisableStartupMessage: true})
+	s.app.Get("/ws", func(c *fiber.Ctx) error {
+		userID := uint(c.QueryInt("user"))
+		ctx := tenant.WithOrganization(c.UserContext(), 1)
+		var upgrader websocket.FastHTTPUpgrader
+		return upgrader.Upgrade(c.Context(), func(conn *websocket.Conn) {
+			s.gateway.Serve(ctx, conn, userID)
+		})
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(s.T(), err)
+	go s.app.Listener(ln)
+	s.url = "ws://" + ln.Addr().String() + "/ws"
+}
+
+// dial connects as the user without reading the connected message.
+func (s *GatewayTestSuite) dial(userID uint) *websocket.Conn {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, s.url+"?user="+strconv.FormatUint(uint64(userID), 10), nil)
+	require.NoError(s.T(), err)
+	s.T().Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// connect connects as the user and reads the connected message.
+func (s *GatewayTestSuite) connect(userID uint) *websocket.Conn {
+	conn := s.dial(userID)
+	env := s.read(conn)
+	require.Equal(s.T(), TypeConnected, env.Type)
+	return conn
+}
+
+func (s *GatewayTestSuite) read(conn *websocket.Conn) *Envelope {
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, data, err := conn.ReadMessage()
+	require.NoError(s.T(), err)
+	var env Envelope
+	require.NoError(s.T(), json.Unmarshal(data, &env))
+	return &env
+}
+
+func (s *GatewayTestSuite) write(conn *websocket.Conn, env *Envelope) {
+	data, _ := json.Marshal(env)
+	require.NoError(s.T(), conn.WriteMessage(websocket.TextMessage, data))
+}
+
+// readClose reads until the gateway closes the connection and returns the
+// close code.
+func (s *GatewayTestSuite) readClose(conn *websocket.Conn) int {
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		_, _, err := conn.ReadMessage()
+		var closeErr *websocket.CloseError
+		if errors.As(err, &closeErr) {
+			return closeErr.Code
+		}
+		require.NoError(s.T(), err)
+	}
+}
+
+func (s *GatewayTestSuite) waitForConnections(n int) {
+	assert.Eventually(s.T(), func() bool { return s.gateway.Connections() == n }, 5*time.Second, time.Millisecond)
+}
+
+func (s *GatewayTestSuite) TestConnect() {
+	s.start(nil)
+	conn := s.dial(1)
+
+	env := s.read(conn)
+	assert.Equal(s.T(), TypeConnected, env.Type)
+	assert.Empty(s.T(), env.ID)
+	assert.Contains(s.T(), string(env.Data), `"user_id":1`)
+	assert.Contains(s.T(), string(env.Data), `"channel":"user:1"`)
+
+	status, _ := s.store.Get(context.Background(), 1)
+	assert.True(s.T(), status.Online)
+
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	assert.Equal(s.T(), websocket.CloseNormalClosure, s.readClose(conn))
+	s.waitForConnections(0)
+
+	status, _ = s.store.Get(context.Background(), 1)
+	assert.False(s.T(), status.Online)
+	assert.False(s.T(), status.LastSeenAt.IsZero())
+}
+
+func (s *GatewayTestSuite) TestSendToUser() {
+	s.start(nil)
+	first := s.connect(1)
+	second := s.connect(1)
+	other := s.connect(2)
+
+	sent, err := s.gateway.SendToUser(1, "notification", map[string]string{"text": "hi"})
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, sent)
+	for _, conn := range []*websocket.Conn{first, second} {
+		env := s.read(conn)
+		assert.Equal(s.T(), "notification", env.Type)
+		assert.Equal(s.T(), "user:1", env.Channel)
+		assert.Equal(s.T(), "1", env.ID)
+		assert.JSONEq(s.T(), `{"text":"hi"}`, string(env.Data))
+	}
+
+	// Other users get nothing
+	other.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+	_, _, err = other.ReadMessage()
+	assert.Error(s.T(), err)
+}
+
+func (s *GatewayTestSuite) TestUnacknowledgedMessagesAreSentAgain() {
+	s.start(func(conf *config.GatewayConfig) {
+		conf.AckTimeout = 20 * time.Millisecond
+	})
+	conn := s.connect(1)
+
+	s.gateway.SendToUser(1, "notification", "first")
+	s.gateway.SendToUser(1, "notification", "second")
+	assert.Equal(s.T(), "1", s.read(conn).ID)
+	assert.Equal(s.T(), "2", s.read(conn).ID)
+	s.write(conn, &Envelope{Type: TypeAck, ID: "2"})
+
+	// Only the first comes again, until it is acknowledged
+	again := s.read(conn)
+	assert.Equal(s.T(), "1", again.ID)
+	assert.JSONEq(s.T(), `"first"`, string(again.Data))
+	s.write(conn, &Envelope{Type: TypeAck, ID: "1"})
+
+	assert.Eventually(s.T(), func() bool {
+		conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		_, _, err := conn.ReadMessage()
+		return err != nil
+	}, 5*time.Second, time.Millisecond)
+}
+
+func (s *GatewayTestSuite) TestTooManyUnacknowledgedMessages() {
+	s.start(func(conf *config.GatewayConfig) {
+		conf.MaxUnacked = 2
+	})
+	conn := s.connect(1)
+
+	for range 3 {
+		s.gateway.SendToUser(1, "notification", "hi")
+	}
+
+	assert.Equal(s.T(), websocket.ClosePolicyViolation, s.readClose(conn))
+	s.waitForConnections(0)
+}
+
+func (s *GatewayTestSuite) TestPing() {
+	s.start(nil)
+	conn := s.connect(1)
+
+	s.write(conn, &Envelope{ID: "p1", Type: TypePing})
+
+	env := s.read(conn)
+	assert.Equal(s.T(), TypeAck, env.Type)
+	assert.Equal(s.T(), "p1", env.ID)
+}
+
+func (s *GatewayTestSuite) TestSubscribePresence() {
+	lastSeenAt := time.Now()
+	s.presenceService.On("GetPresence", mock.Anything, uint(2)).Return(&model.PresenceResponse{
+		UserID:     2,
+		Status:     model.PresenceOffline,
+		LastSeenAt: &lastSeenAt,
+	}, nil)
+	s.start(nil)
+	watcher := s.connect(1)
+
+	s.write(watcher, &Envelope{ID: "s1", Type: TypeSubscribe, Channel: "presence:2"})
+	ack := s.read(watcher)
+	assert.Equal(s.T(), TypeAck, ack.Type)
+	assert.Equal(s.T(), "s1", ack.ID)
+	assert.Contains(s.T(), string(ack.Data), `"status":"offline"`)
+
+	watched := s.connect(2)
+	online := s.read(watcher)
+	assert.Equal(s.T(), TypePresence, online.Type)
+	assert.Equal(s.T(), "presence:2", online.Channel)
+	assert.Contains(s.T(), string(online.Data), `"status":"online"`)
+	s.write(watcher, &Envelope{Type: TypeAck, ID: online.ID})
+
+	watched.Close()
+	offline := s.read(watcher)
+	assert.Equal(s.T(), TypePresence, offline.Type)
+	assert.Contains(s.T(), string(offline.Data), `"status":"offline"`)
+
+	// Checks the organization in the context of the request
+	ctx := s.presenceService.Calls[0].Arguments.Get(0).(context.Context)
+	organizationID, _ := tenant.OrganizationFromContext(ctx)
+	assert.Equal(s.T(), uint(1), organizationID)
+}
+
+func (s *GatewayTestSuite) TestUnsubscribePresence() {
+	s.presenceService.On("GetPresence", mock.Anything, uint(2)).Return(&model.PresenceResponse{UserID: 2, Status: model.PresenceOffline}, nil)
+	s.start(nil)
+	watcher := s.connect(1)
+
+	s.write(watcher, &Envelope{ID: "s1", Type: TypeSubscribe, Channel: "presence:2"})
+	s.read(watcher)
+	s.write(watcher, &Envelope{ID: "u1", Type: TypeUnsubscribe, Channel: "presence:2"})
+	assert.Equal(s.T(), "u1", s.read(watcher).ID)
+
+	s.connect(2)
+	s.write(watcher, &Envelope{ID: "p1", Type: TypePing})
+	// The ping is answered without a presence message before it
+	assert.Equal(s.T(), "p1", s.read(watcher).ID)
+}
+
+func (s *GatewayTestSuite) TestSubscribe_Errors() {
+	s.presenceService.On("GetPresence", mock.Anything, uint(3)).Return(nil, gorm.ErrRecordNotFound)
+	s.start(nil)
+	conn := s.connect(1)
+
+	cases := map[string]*Envelope{
+		string(errForeignChannel): {ID: "1", Type: TypeSubscribe, Channel: "user:2"},
+		string(errUnknownChannel): {ID: "2", Type: TypeSubscribe, Channel: "presence:nobody"},
+		string(errUserNotFound):   {ID: "3", Type: TypeSubscribe, Channel: "presence:3"},
+		string(errUserChannel):    {ID: "4", Type: TypeUnsubscribe, Channel: "user:1"},
+		string(errUnknownType):    {ID: "5", Type: "shout"},
+	}
+	for expected, req := range cases {
+		s.write(conn, req)
+		env := s.read(conn)
+		assert.Equal(s.T(), TypeError, env.Type, expected)
+		assert.Equal(s.T(), req.ID, env.ID, expected)
+		assert.Equal(s.T(), expected, env.Error)
+	}
+
+	// The own channel is always subscribed
+	s.write(conn, &Envelope{ID: "6", Type: TypeSubscribe, Channel: "user:1"})
+	assert.Equal(s.T(), TypeAck, s.read(conn).Type)
+}
+
+func (s *GatewayTestSuite) TestInvalidMessages() {
+	s.start(nil)
+	conn := s.connect(1)
+
+	require.NoError(s.T(), conn.WriteMessage(websocket.TextMessage, []byte("not json")))
+	env := s.read(conn)
+	assert.Equal(s.T(), TypeError, env.Type)
+	assert.Equal(s.T(), string(errInvalidMessage), env.Error)
+
+	require.NoError(s.T(), conn.WriteMessage(websocket.BinaryMessage, []byte{1}))
+	assert.Equal(s.T(), websocket.CloseUnsupportedData, s.readClose(conn))
+}
+
+func (s *GatewayTestSuite) TestTooManyConnections() {
+	s.start(func(conf *config.GatewayConfig) {
+		conf.MaxConnections = 1
+	})
+	s.connect(1)
+
+	conn := s.dial(2)
+
+	assert.Equal(s.T(), websocket.CloseTryAgainLater, s.readClose(conn))
+}
+
+func (s *GatewayTestSuite) TestSilentConnectionsAreDropped() {
+	s.start(func(conf *config.GatewayConfig) {
+		conf.PongTimeout = 50 * time.Millisecond
+	})
+	s.connect(1)
+	s.waitForConnections(1)
+
+	s.waitForConnections(0)
+}
+
+func (s *GatewayTestSuite) TestPingsKeepConnectionsOpen() {
+	s.start(func(conf *config.GatewayConfig) {
+		conf.PingInterval = 10 * time.Millisecond
+		conf.PongTimeout = 50 * time.Millisecond
+	})
+	conn := s.connect(1)
+
+	// Reading answers the pings of the gateway
+	conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	_, _, err := conn.ReadMessage()
+
+	var netErr net.Error
+	require.ErrorAs(s.T(), err, &netErr)
+	assert.True(s.T(), netErr.Timeout())
+	assert.Equal(s.T(), 1, s.gateway.Connections())
+}
+
+func (s *GatewayTestSuite) TestConnectionsExpire() {
+	s.start(func(conf *config.GatewayConfig) {
+		conf.MaxDuration = 20 * time.Millisecond
+	})
+	conn := s.connect(1)
+
+	assert.Equal(s.T(), websocket.CloseGoingAway, s.readClose(conn))
+	s.waitForConnections(0)
+}
+
+func (s *GatewayTestSuite) TestRun_ClosesConnectionsOnStop() {
+	s.start(nil)
+	conn := s.connect(1)
+
+	s.stop()
+
+	assert.Equal(s.T(), websocket.CloseGoingAway, s.readClose(conn))
+	s.waitForConnections(0)
+
+	// New connections are refused
+	assert.Equal(s.T(), websocket.CloseGoingAway, s.readClose(s.dial(1)))
+}
+
+// TestThousandsOfConnections holds thousands of connections at once and
+// checks that closing them, by the clients or by stopping the gateway,
+// leaves no goroutines behind.
+func (s *GatewayTestSuite) TestThousandsOfConnections() {
+	const n = 2000
+	s.start(nil)
+
+	// The server keeps a worker around from the first connection
+	s.connect(1).Close()
+	s.waitForConnections(0)
+	baseline := runtime.NumGoroutine()
+
+	conns := make([]*websocket.Conn, n)
+	for i := range conns {
+		conns[i] = s.dial(uint(i%100 + 1))
+	}
+	s.waitForConnections(n)
+	// A reader and a writer for each connection
+	assert.GreaterOrEqual(s.T(), runtime.NumGoroutine(), baseline+2*n)
+
+	sent, err := s.gateway.SendToUser(1, "notification", "hi")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), n/100, sent)
+
+	// Half go away on their own, some cleanly and some not
+	for i, conn := range conns[:n/2] {
+		if i%2 == 0 {
+			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		}
+		conn.Close()
+	}
+	s.waitForConnections(n / 2)
+
+	// The rest are closed by the gateway
+	s.stop()
+	for _, conn := range conns[n/2:] {
+		assert.Equal(s.T(), websocket.CloseGoingAway, s.readClose(conn))
+		conn.Close()
+	}
+	s.waitForConnections(0)
+
+	assert.Eventually(s.T(), func() bool {
+		return runtime.NumGoroutine() <= baseline+10
+	}, 10*time.Second, 10*time.Millisecond, "goroutines left: %d, baseline %d", runtime.NumGoroutine(), baseline)
+
+	for userID := uint(1); userID <= 100; userID++ {
+		status, _ := s.store.Get(context.Background(), userID)
+		assert.False(s.T(), status.Online)
+	}
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=APIKeyHandler --output=./mocks/handler --outpkg=handler --filename=api_key_handler.go --structname=MockAPIKeyHandler --with-expecter=false
+type APIKeyHandler interface {
+	CreateAPIKey(c *fiber.Ctx) error
+	ListAPIKeys(c *fiber.Ctx) error
+	RevokeAPIKey(c *fiber.Ctx) error
+}
+
+type apiKeyHandlerImpl struct {
+	apiKeyService service.APIKeyService
+	validator     *validator.Validate
+}
+
+func NewAPIKeyHandler(apiKeyService service.APIKeyService) APIKeyHandler {
+	return &apiKeyHandlerImpl{
+		apiKeyService: apiKeyService,
+		validator:     validator.New(),
+	}
+}
+
+// CreateAPIKey issues an API key
+// @Summary Create API key
+// @Description Issue an API key owned by a user or, without user_id, by a service. The key is only returned once; store it securely. Callers cannot grant scopes they do not have. Keys of other users need the api_keys:manage permission, service keys the admin role.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param key body model.CreateAPIKeyRequest true "API key"
+// @Success 201 {object} model.CreateAPIKeyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api-keys [post]
+func (h *apiKeyHandlerImpl) CreateAPIKey(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	var req model.CreateAPIKeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	key, err := h.apiKeyService.Create(c.UserContext(), middleware.PrincipalFromContext(c), &req)
+	switch {
+	case errors.Is(err, service.ErrScopeNotGranted), errors.Is(err, service.ErrAPIKeyForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrAPIKeyExpiry):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrAPIKeyOwnerNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create API key",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(key)
+}
+
+// ListAPIKeys lists the API keys of a user or a service
+// @Summary List API keys
+// @Description List the API keys of a user or of a service, including revoked and expired ones. Secrets are never returned. Keys of other users need the api_keys:manage permission, service keys the admin role.
+// @Tags api-keys
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param user_id query int false "Owning user ID"
+// @Param service query string false "Owning service"
+// @Success 200 {array} model.APIKeyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api-keys [get]
+func (h *apiKeyHandlerImpl) ListAPIKeys(c *fiber.Ctx) error {
+	userIDStr := c.Query("user_id")
+	serviceName := c.Query("service")
+
+	if (userIDStr == "") == (serviceName == "") {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Exactly one of user_id and service is required",
+		})
+	}
+
+	caller := middleware.PrincipalFromContext(c)
+	var keys []*model.APIKeyResponse
+	var err error
+	if userIDStr != "" {
+		userID, parseErr := strconv.ParseUint(userIDStr, 10, 32)
+		if parseErr != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid user ID",
+			})
+		}
+		keys, err = h.apiKeyService.ListForUser(c.UserContext(), caller, uint(userID))
+	} else {
+		keys, err = h.apiKeyService.ListForService(c.UserContext(), caller, serviceName)
+	}
+	switch {
+	case errors.Is(err, service.ErrAPIKeyForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrAPIKeyOwnerNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list API keys",
+		})
+	}
+
+	return c.JSON(keys)
+}
+
+// RevokeAPIKey revokes an API key
+// @Summary Revoke API key
+// @Description Revoke an API key. Requests made with it are rejected from then on. Keys of other users need the api_keys:manage permission, service keys the admin role.
+// @Tags api-keys
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "API key ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api-keys/{id} [delete]
+func (h *apiKeyHandlerImpl) RevokeAPIKey(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid API key ID",
+		})
+	}
+
+	err = h.apiKeyService.Revoke(c.UserContext(), middleware.PrincipalFromContext(c), uint(id))
+	switch {
+	case errors.Is(err, service.ErrAPIKeyNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrAPIKeyForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to revoke API key",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
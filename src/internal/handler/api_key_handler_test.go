@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+)
+
+// newAPIKeyApp serves the API key routes as the given caller.
+func (s *HandlerTestSuite) newAPIKeyApp(caller *model.Principal) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("principal", caller)
+		return c.Next()
+	})
+	app.Post("/api-keys", s.apiKeyHandler.CreateAPIKey)
+	app.Get("/api-keys", s.apiKeyHandler.ListAPIKeys)
+	app.Delete("/api-keys/:id", s.apiKeyHandler.RevokeAPIKey)
+	return app
+}
+
+// Test CreateAPIKey handler
+func (s *HandlerTestSuite) TestCreateAPIKey_Success() {
+	caller := &model.Principal{UserID: 1, SessionID: "session-1"}
+	s.apiKeyService.On("Create", mock.Anything, caller, mock.AnythingOfType("*model.CreateAPIKeyRequest")).Return(&model.CreateAPIKeyResponse{
+		APIKeyResponse: model.APIKeyResponse{ID: 7, Service: "billing", Prefix: "gkb_abcdefgh"},
+		Key:            "gkb_abcdefgh_secret",
+	}, nil)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"name":    "billing sync",
+		"service": "billing",
+		"scopes":  []string{model.ScopeUsersRead},
+	})
+	req := httptest.NewRequest("POST", "/api-keys", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.newAPIKeyApp(caller).Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusCreated, resp.StatusCode)
+
+	var result model.CreateAPIKeyResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), "gkb_abcdefgh_secret", result.Key)
+	assert.Equal(s.T(), "gkb_abcdefgh", result.Prefix)
+}
+
+func (s *HandlerTestSuite) TestCreateAPIKey_ValidationError() {
+	cases := []map[string]interface{}{
+		// Neither an owning user nor a service
+		{"name": "orphan", "scopes": []string{model.ScopeUsersRead}},
+		// Both an owning user and a service
+		{"name": "both", "user_id": 1, "service": "billing", "scopes": []string{model.ScopeUsersRead}},
+		{"name": "unknown scope", "service": "billing", "scopes": []string{"admin"}},
+		{"name": "no scopes", "service": "billing", "scopes": []string{}},
+	}
+
+	for _, payload := range cases {
+		body, _ := json.Marshal(payload)
+		req := httptest.NewRequest("POST", "/api-keys", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.newAPIKeyApp(&model.Principal{UserID: 1, SessionID: "session-1"}).Test(req)
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode, payload["name"])
+	}
+	s.apiKeyService.AssertNotCalled(s.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (s *HandlerTestSuite) TestCreateAPIKey_ScopeNotGranted() {
+	s.apiKeyService.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil, service.ErrScopeNotGranted)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"name":    "escalation",
+		"service": "billing",
+		"scopes":  []string{model.ScopeUsersWrite},
+	})
+	req := httptest.NewRequest("POST", "/api-keys", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.newAPIKeyApp(&model.Principal{APIKeyID: 7}).Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusForbidden, resp.StatusCode)
+}
+
+// Test ListAPIKeys handler
+func (s *HandlerTestSuite) TestListAPIKeys_ByUser() {
+	s.apiKeyService.On("ListForUser", mock.Anything, mock.Anything, uint(1)).Return([]*model.APIKeyResponse{{ID: 7, Prefix: "gkb_abcdefgh"}}, nil)
+
+	resp, err := s.newAPIKeyApp(&model.Principal{UserID: 1, SessionID: "session-1"}).Test(httptest.NewRequest("GET", "/api-keys?user_id=1", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result []model.APIKeyResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Len(s.T(), result, 1)
+}
+
+func (s *HandlerTestSuite) TestListAPIKeys_Forbidden() {
+	s.apiKeyService.On("ListForService", mock.Anything, mock.Anything, "billing").Return(nil, service.ErrAPIKeyForbidden)
+
+	resp, err := s.newAPIKeyApp(&model.Principal{UserID: 1, SessionID: "session-1"}).Test(httptest.NewRequest("GET", "/api-keys?service=billing", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusForbidden, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestListAPIKeys_OwnerRequired() {
+	for _, query := range []string{"", "?user_id=1&service=billing", "?user_id=abc"} {
+		resp, err := s.newAPIKeyApp(&model.Principal{UserID: 1, SessionID: "session-1"}).Test(httptest.NewRequest("GET", "/api-keys"+query, nil))
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode, query)
+	}
+}
+
+// Test RevokeAPIKey handler
+func (s *HandlerTestSuite) TestRevokeAPIKey_Success() {
+	s.apiKeyService.On("Revoke", mock.Anything, mock.Anything, uint(7)).Return(nil)
+
+	resp, err := s.newAPIKeyApp(&model.Principal{UserID: 1, SessionID: "session-1"}).Test(httptest.NewRequest("DELETE", "/api-keys/7", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNoContent, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestRevokeAPIKey_NotFound() {
+	s.apiKeyService.On("Revoke", mock.Anything, mock.Anything, uint(999)).Return(service.ErrAPIKeyNotFound)
+
+	resp, err := s.newAPIKeyApp(&model.Principal{UserID: 1, SessionID: "session-1"}).Test(httptest.NewRequest("DELETE", "/api-keys/999", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,204 @@
+package handler
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=AuditHandler --output=./mocks/handler --outpkg=handler --filename=audit_handler.go --structname=MockAuditHandler --with-expecter=false
+type AuditHandler interface {
+	ListEvents(c *fiber.Ctx) error
+	ExportEvents(c *fiber.Ctx) error
+	VerifyLog(c *fiber.Ctx) error
+}
+
+type auditHandlerImpl struct {
+	auditService service.AuditService
+}
+
+func NewAuditHandler(auditService service.AuditService) AuditHandler {
+	return &auditHandlerImpl{
+		auditService: auditService,
+	}
+}
+
+// ListEvents lists audit events
+// @Summary List audit events
+// @Description List the audit log of the organization of the request, newest first. Needs the audit:read permission.
+// @Tags audit
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param action query string false "Action, such as user.updated"
+// @Param actor_id query int false "ID of the user who made the change"
+// @Param target_type query string false "Type of the changed record, such as user"
+// @Param target_id query int false "ID of the changed record"
+// @Param from query string false "Earliest time, RFC 3339"
+// @Param to query string false "Time before which events happened, RFC 3339"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /audit [get]
+func (h *auditHandlerImpl) ListEvents(c *fiber.Ctx) error {
+	filter, err := auditFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	limit := 10 // default
+	offset := 0 // default
+
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	if o, err := strconv.Atoi(c.Query("offset")); err == nil && o >= 0 {
+		offset = o
+	}
+
+	events, err := h.auditService.ListEvents(c.UserContext(), filter, limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch audit events",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"events": events,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// ExportEvents exports audit events
+// @Summary Export audit events
+// @Description Download the audit events matching the filters, oldest first, as CSV or as newline-delimited JSON. Each event carries its hash and that of the event before it. Needs the audit:read permission.
+// @Tags audit
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param format query string false "csv or ndjson" default(ndjson)
+// @Param action query string false "Action, such as user.updated"
+// @Param actor_id query int false "ID of the user who made the change"
+// @Param target_type query string false "Type of the changed record, such as user"
+// @Param target_id query int false "ID of the changed record"
+// @Param from query string false "Earliest time, RFC 3339"
+// @Param to query string false "Time before which events happened, RFC 3339"
+// @Success 200 {string} string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /audit/export [get]
+func (h *auditHandlerImpl) ExportEvents(c *fiber.Ctx) error {
+	filter, err := auditFilter(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	format := c.Query("format", model.AuditFormatNDJSON)
+	contentTypes := map[string]string{
+		model.AuditFormatCSV:    "text/csv; charset=utf-8",
+		model.AuditFormatNDJSON: "application/x-ndjson",
+	}
+	contentType, ok := contentTypes[format]
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Format must be csv or ndjson",
+		})
+	}
+	// Attachment guesses the content type from the file name, so it goes first
+	c.Attachment("audit." + format)
+	c.Set(fiber.HeaderContentType, contentType)
+
+	// The response is streamed once the handler has returned, so a failure
+	// can only cut it short
+	ctx := c.UserContext()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := h.auditService.Export(ctx, filter, format, w); err != nil {
+			log.Printf("Failed to export audit events: %v", err)
+		}
+		w.Flush()
+	})
+	return nil
+}
+
+// VerifyLog verifies the audit log
+// @Summary Verify audit log
+// @Description Recompute the hash chain of the audit log of the organization of the request. An event that was changed or removed breaks the chain at broken_at. Needs the audit:read permission.
+// @Tags audit
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Success 200 {object} model.AuditVerificationResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Router /audit/verify [get]
+func (h *auditHandlerImpl) VerifyLog(c *fiber.Ctx) error {
+	result, err := h.auditService.Verify(c.UserContext())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to verify audit log",
+		})
+	}
+
+	return c.JSON(result)
+}
+
+// auditFilter reads the filters of the audit log from the query string.
+func auditFilter(c *fiber.Ctx) (*model.AuditFilter, error) {
+	filter := &model.AuditFilter{
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+	}
+
+	ids := []struct {
+		param string
+		id    *uint
+	}{
+		{"actor_id", &filter.ActorUserID},
+		{"target_id", &filter.TargetID},
+	}
+	for _, q := range ids {
+		if value := c.Query(q.param); value != "" {
+			parsed, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid %s", q.param)
+			}
+			*q.id = uint(parsed)
+		}
+	}
+
+	times := []struct {
+		param string
+		t     *time.Time
+	}{
+		{"from", &filter.From},
+		{"to", &filter.To},
+	}
+	for _, q := range times {
+		if value := c.Query(q.param); value != "" {
+			parsed, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("Invalid %s, expected an RFC 3339 time", q.param)
+			}
+			*q.t = parsed
+		}
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+func (s *HandlerTestSuite) newAuditApp() *fiber.App {
+	app := fiber.New()
+	app.Get("/audit", s.auditHandler.ListEvents)
+	app.Get("/audit/export", s.auditHandler.ExportEvents)
+	app.Get("/audit/verify", s.auditHandler.VerifyLog)
+	return app
+}
+
+// Test ListEvents handler
+func (s *HandlerTestSuite) TestListEvents_Filters() {
+	filter := &model.AuditFilter{
+		Action:      model.AuditUserUpdated,
+		ActorUserID: 7,
+		TargetType:  model.AuditTargetUser,
+		TargetID:    3,
+		From:        time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC),
+	}
+	s.audit.On("ListEvents", mock.Anything, filter, 20, 40).Return([]*model.AuditEventResponse{{ID: 9, Action: model.AuditUserUpdated}}, nil)
+
+	url := "/audit?action=user.updated&actor_id=7&target_type=user&target_id=3&from=2025-11-01T00:00:00Z&limit=20&offset=40"
+	resp, err := s.newAuditApp().Test(httptest.NewRequest("GET", url, nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Events []model.AuditEventResponse `json:"events"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Len(s.T(), result.Events, 1)
+}
+
+func (s *HandlerTestSuite) TestListEvents_InvalidFilter() {
+	for _, query := range []string{"actor_id=me", "target_id=-1", "from=yesterday", "to=2025-11-01"} {
+		resp, err := s.newAuditApp().Test(httptest.NewRequest("GET", "/audit?"+query, nil))
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode, query)
+	}
+	s.audit.AssertNotCalled(s.T(), "ListEvents", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test ExportEvents handler
+func (s *HandlerTestSuite) TestExportEvents_CSV() {
+	s.audit.On("Export", mock.Anything, &model.AuditFilter{Action: model.AuditUserDeleted}, model.AuditFormatCSV, mock.Anything).
+		Run(func(args mock.Arguments) {
+			io.WriteString(args.Get(3).(io.Writer), "id,action\n1,user.deleted\n")
+		}).
+		Return(nil)
+
+	resp, err := s.newAuditApp().Test(httptest.NewRequest("GET", "/audit/export?format=csv&action=user.deleted", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	assert.Equal(s.T(), "text/csv; charset=utf-8", resp.Header.Get(fiber.HeaderContentType))
+	assert.Equal(s.T(), `attachment; filename="audit.csv"`, resp.Header.Get(fiber.HeaderContentDisposition))
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(s.T(), "id,action\n1,user.deleted\n", string(body))
+}
+
+func (s *HandlerTestSuite) TestExportEvents_DefaultsToNDJSON() {
+	s.audit.On("Export", mock.Anything, mock.Anything, model.AuditFormatNDJSON, mock.Anything).Return(nil)
+
+	resp, err := s.newAuditApp().Test(httptest.NewRequest("GET", "/audit/export", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	assert.Equal(s.T(), "application/x-ndjson", resp.Header.Get(fiber.HeaderContentType))
+}
+
+func (s *HandlerTestSuite) TestExportEvents_UnsupportedFormat() {
+	resp, err := s.newAuditApp().Test(httptest.NewRequest("GET", "/audit/export?format=xml", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+	s.audit.AssertNotCalled(s.T(), "Export", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test VerifyLog handler
+func (s *HandlerTestSuite) TestVerifyLog_Broken() {
+	brokenAt := uint(4)
+	s.audit.On("Verify", mock.Anything).Return(&model.AuditVerificationResponse{Valid: false, Events: 6, BrokenAt: &brokenAt}, nil)
+
+	resp, err := s.newAuditApp().Test(httptest.NewRequest("GET", "/audit/verify", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result model.AuditVerificationResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.False(s.T(), result.Valid)
+	assert.Equal(s.T(), uint(4), *result.BrokenAt)
+}
+
+func (s *HandlerTestSuite) TestVerifyLog_Error() {
+	s.audit.On("Verify", mock.Anything).Return(nil, errors.New("db down"))
+
+	resp, err := s.newAuditApp().Test(httptest.NewRequest("GET", "/audit/verify", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusInternalServerError, resp.StatusCode)
+}
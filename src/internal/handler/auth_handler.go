@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=AuthHandler --output=./mocks/handler --outpkg=handler --filename=auth_handler.go --structname=MockAuthHandler --with-expecter=false
+type AuthHandler interface {
+	Login(c *fiber.Ctx) error
+	LoginMFA(c *fiber.Ctx) error
+}
+
+type authHandlerImpl struct {
+	authService service.AuthService
+	validator   *validator.Validate
+}
+
+func NewAuthHandler(authService service.AuthService) AuthHandler {
+	return &authHandlerImpl{
+		authService: authService,
+		validator:   validator.New(),
+	}
+}
+
+// Login authenticates a user
+// @Summary Log in
+// @Description Authenticate with a username or email address and a password, and start a new session. For users with MFA enabled the response has mfa_required set and an mfa_token to send to /auth/login/mfa instead of an access token.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param credentials body model.LoginRequest true "Credentials"
+// @Success 200 {object} model.LoginResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Header 429 {integer} Retry-After "Seconds until another attempt is allowed"
+// @Failure 500 {object} map[string]string
+// @Router /auth/login [post]
+func (h *authHandlerImpl) Login(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	var req model.LoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	resp, err := h.authService.Login(c.UserContext(), &req, clientInfo(c))
+	var throttledErr *service.LoginThrottledError
+	if errors.As(err, &throttledErr) {
+		return loginThrottled(c, throttledErr)
+	}
+	if errors.Is(err, service.ErrInvalidCredentials) {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if errors.Is(err, service.ErrMFAEnrollmentRequired) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to log in",
+		})
+	}
+
+	return c.JSON(resp)
+}
+
+// LoginMFA completes a login with a second factor
+// @Summary Complete MFA login
+// @Description Redeem the mfa_token from /auth/login with a TOTP code or a recovery code, and start a new session
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body model.MFALoginRequest true "MFA token and code"
+// @Success 200 {object} model.LoginResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Header 429 {integer} Retry-After "Seconds until another attempt is allowed"
+// @Failure 500 {object} map[string]string
+// @Router /auth/login/mfa [post]
+func (h *authHandlerImpl) LoginMFA(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	var req model.MFALoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	resp, err := h.authService.CompleteMFALogin(c.UserContext(), &req, clientInfo(c))
+	var throttledErr *service.LoginThrottledError
+	if errors.As(err, &throttledErr) {
+		return loginThrottled(c, throttledErr)
+	}
+	if errors.Is(err, service.ErrInvalidMFAChallenge) || errors.Is(err, service.ErrInvalidMFACode) {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to log in",
+		})
+	}
+
+	return c.JSON(resp)
+}
+
+// loginThrottled responds with 429 and the time until the next attempt.
+func loginThrottled(c *fiber.Ctx, err *service.LoginThrottledError) error {
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(err.RetryAfter.Seconds()))))
+	return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+		"error": "Too many failed login attempts, try again later",
+	})
+}
+
+// maxUserAgentLength matches the size of the stored user agent column.
+const maxUserAgentLength = 512
+
+// clientInfo describes the client of the request.
+func clientInfo(c *fiber.Ctx) *model.ClientInfo {
+	userAgent := c.Get(fiber.HeaderUserAgent)
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	return &model.ClientInfo{
+		IP:        c.IP(),
+		UserAgent: userAgent,
+	}
+}
@@ -0,0 +1,225 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+)
+
+// Test Login handler
+func (s *HandlerTestSuite) TestLogin_Success() {
+	loginReq := &model.LoginRequest{Login: "testuser", Password: "password123"}
+
+	s.authService.On("Login", mock.Anything, loginReq, mock.AnythingOfType("*model.ClientInfo")).Return(&model.LoginResponse{
+		AccessToken: "token",
+		TokenType:   "Bearer",
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}, nil)
+
+	app := fiber.New()
+	app.Post("/auth/login", s.authHandler.Login)
+
+	body, _ := json.Marshal(loginReq)
+	req := httptest.NewRequest("POST", "/auth/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result model.LoginResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), "token", result.AccessToken)
+	s.authService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestLogin_InvalidBody() {
+	app := fiber.New()
+	app.Post("/auth/login", s.authHandler.Login)
+
+	req := httptest.NewRequest("POST", "/auth/login", bytes.NewBuffer([]byte("invalid json")))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestLogin_ValidationError() {
+	app := fiber.New()
+	app.Post("/auth/login", s.authHandler.Login)
+
+	req := httptest.NewRequest("POST", "/auth/login", bytes.NewBuffer([]byte(`{"login":"testuser"}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestLogin_InvalidCredentials() {
+	loginReq := &model.LoginRequest{Login: "testuser", Password: "wrong"}
+
+	s.authService.On("Login", mock.Anything, loginReq, mock.AnythingOfType("*model.ClientInfo")).Return(nil, service.ErrInvalidCredentials)
+
+	app := fiber.New()
+	app.Post("/auth/login", s.authHandler.Login)
+
+	body, _ := json.Marshal(loginReq)
+	req := httptest.NewRequest("POST", "/auth/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusUnauthorized, resp.StatusCode)
+	s.authService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestLogin_ServiceError() {
+	loginReq := &model.LoginRequest{Login: "testuser", Password: "password123"}
+
+	s.authService.On("Login", mock.Anything, loginReq, mock.AnythingOfType("*model.ClientInfo")).Return(nil, errors.New("database error"))
+
+	app := fiber.New()
+	app.Post("/auth/login", s.authHandler.Login)
+
+	body, _ := json.Marshal(loginReq)
+	req := httptest.NewRequest("POST", "/auth/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusInternalServerError, resp.StatusCode)
+	s.authService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestLogin_MFAEnrollmentRequired() {
+	loginReq := &model.LoginRequest{Login: "admin", Password: "password123"}
+
+	s.authService.On("Login", mock.Anything, loginReq, mock.AnythingOfType("*model.ClientInfo")).Return(nil, service.ErrMFAEnrollmentRequired)
+
+	app := fiber.New()
+	app.Post("/auth/login", s.authHandler.Login)
+
+	body, _ := json.Marshal(loginReq)
+	req := httptest.NewRequest("POST", "/auth/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusForbidden, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestLogin_Throttled() {
+	loginReq := &model.LoginRequest{Login: "testuser", Password: "password123"}
+
+	s.authService.On("Login", mock.Anything, loginReq, mock.AnythingOfType("*model.ClientInfo")).Return(nil, &service.LoginThrottledError{RetryAfter: 1500 * time.Millisecond})
+
+	app := fiber.New()
+	app.Post("/auth/login", s.authHandler.Login)
+
+	body, _ := json.Marshal(loginReq)
+	req := httptest.NewRequest("POST", "/auth/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(s.T(), "2", resp.Header.Get(fiber.HeaderRetryAfter))
+}
+
+func (s *HandlerTestSuite) TestLogin_PassesClientInfo() {
+	loginReq := &model.LoginRequest{Login: "testuser", Password: "password123"}
+
+	s.authService.On("Login", mock.Anything, loginReq, mock.MatchedBy(func(client *model.ClientInfo) bool {
+		return client.IP == "0.0.0.0" && client.UserAgent == "test-agent"
+	})).Return(&model.LoginResponse{AccessToken: "token", TokenType: "Bearer"}, nil)
+
+	app := fiber.New()
+	app.Post("/auth/login", s.authHandler.Login)
+
+	body, _ := json.Marshal(loginReq)
+	req := httptest.NewRequest("POST", "/auth/login", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "test-agent")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	s.authService.AssertExpectations(s.T())
+}
+
+// Test LoginMFA handler
+func (s *HandlerTestSuite) TestLoginMFA_Success() {
+	mfaReq := &model.MFALoginRequest{MFAToken: "challenge", Code: "123456"}
+
+	s.authService.On("CompleteMFALogin", mock.Anything, mfaReq, mock.AnythingOfType("*model.ClientInfo")).Return(&model.LoginResponse{
+		AccessToken: "token",
+		TokenType:   "Bearer",
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}, nil)
+
+	app := fiber.New()
+	app.Post("/auth/login/mfa", s.authHandler.LoginMFA)
+
+	body, _ := json.Marshal(mfaReq)
+	req := httptest.NewRequest("POST", "/auth/login/mfa", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result model.LoginResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), "token", result.AccessToken)
+	s.authService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestLoginMFA_InvalidCode() {
+	mfaReq := &model.MFALoginRequest{MFAToken: "challenge", Code: "000000"}
+
+	s.authService.On("CompleteMFALogin", mock.Anything, mfaReq, mock.AnythingOfType("*model.ClientInfo")).Return(nil, service.ErrInvalidMFACode)
+
+	app := fiber.New()
+	app.Post("/auth/login/mfa", s.authHandler.LoginMFA)
+
+	body, _ := json.Marshal(mfaReq)
+	req := httptest.NewRequest("POST", "/auth/login/mfa", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusUnauthorized, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestLoginMFA_ValidationError() {
+	app := fiber.New()
+	app.Post("/auth/login/mfa", s.authHandler.LoginMFA)
+
+	req := httptest.NewRequest("POST", "/auth/login/mfa", bytes.NewBuffer([]byte(`{"code":"123456"}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=EmailVerificationHandler --output=./mocks/handler --outpkg=handler --filename=email_verification_handler.go --structname=MockEmailVerificationHandler --with-expecter=false
+type EmailVerificationHandler interface {
+	VerifyEmail(c *fiber.Ctx) error
+	ResendVerification(c *fiber.Ctx) error
+}
+
+type emailVerificationHandlerImpl struct {
+	emailVerificationService service.EmailVerificationService
+	validator                *validator.Validate
+}
+
+func NewEmailVerificationHandler(emailVerificationService service.EmailVerificationService) EmailVerificationHandler {
+	return &emailVerificationHandlerImpl{
+		emailVerificationService: emailVerificationService,
+		validator:                validator.New(),
+	}
+}
+
+// VerifyEmail confirms an email address
+// @Summary Verify email
+// @Description Confirm an email address with a token from a verification email. A token for a pending email change makes it the user's email.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body model.VerifyEmailRequest true "Verification token"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/email/verify [post]
+func (h *emailVerificationHandlerImpl) VerifyEmail(c *fiber.Ctx) error {
+	var req model.VerifyEmailRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	err := h.emailVerificationService.VerifyEmail(c.UserContext(), &req)
+	if errors.Is(err, service.ErrInvalidVerificationToken) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if errors.Is(err, service.ErrEmailTaken) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to verify email",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ResendVerification sends a new verification email
+// @Summary Resend verification email
+// @Description Send a new verification link to the pending email, or to the current one if it is not verified yet
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Success 202
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Header 429 {integer} Retry-After "Seconds until another email can be sent"
+// @Router /users/{id}/email/verification [post]
+func (h *emailVerificationHandlerImpl) ResendVerification(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	err = h.emailVerificationService.ResendVerification(c.UserContext(), uint(id))
+
+	var throttledErr *service.ResendThrottledError
+	if errors.As(err, &throttledErr) {
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(throttledErr.RetryAfter.Seconds()))))
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if errors.Is(err, service.ErrEmailAlreadyVerified) {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusAccepted)
+}
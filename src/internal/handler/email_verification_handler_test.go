@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+)
+
+// Test VerifyEmail handler
+func (s *HandlerTestSuite) TestVerifyEmail_Success() {
+	s.verification.On("VerifyEmail", mock.Anything, &model.VerifyEmailRequest{Token: "raw-token"}).Return(nil)
+
+	app := fiber.New()
+	app.Post("/auth/email/verify", s.verifyHandler.VerifyEmail)
+
+	req := httptest.NewRequest("POST", "/auth/email/verify", bytes.NewBuffer([]byte(`{"token":"raw-token"}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNoContent, resp.StatusCode)
+	s.verification.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestVerifyEmail_MissingToken() {
+	app := fiber.New()
+	app.Post("/auth/email/verify", s.verifyHandler.VerifyEmail)
+
+	req := httptest.NewRequest("POST", "/auth/email/verify", bytes.NewBuffer([]byte(`{}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestVerifyEmail_InvalidToken() {
+	s.verification.On("VerifyEmail", mock.Anything, &model.VerifyEmailRequest{Token: "raw-token"}).Return(service.ErrInvalidVerificationToken)
+
+	app := fiber.New()
+	app.Post("/auth/email/verify", s.verifyHandler.VerifyEmail)
+
+	req := httptest.NewRequest("POST", "/auth/email/verify", bytes.NewBuffer([]byte(`{"token":"raw-token"}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestVerifyEmail_EmailTaken() {
+	s.verification.On("VerifyEmail", mock.Anything, &model.VerifyEmailRequest{Token: "raw-token"}).Return(service.ErrEmailTaken)
+
+	app := fiber.New()
+	app.Post("/auth/email/verify", s.verifyHandler.VerifyEmail)
+
+	req := httptest.NewRequest("POST", "/auth/email/verify", bytes.NewBuffer([]byte(`{"token":"raw-token"}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusConflict, resp.StatusCode)
+}
+
+// Test ResendVerification handler
+func (s *HandlerTestSuite) TestResendVerification_Success() {
+	s.verification.On("ResendVerification", mock.Anything, uint(1)).Return(nil)
+
+	app := fiber.New()
+	app.Post("/users/:id/email/verification", s.verifyHandler.ResendVerification)
+
+	req := httptest.NewRequest("POST", "/users/1/email/verification", nil)
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusAccepted, resp.StatusCode)
+	s.verification.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestResendVerification_Throttled() {
+	s.verification.On("ResendVerification", mock.Anything, uint(1)).Return(&service.ResendThrottledError{RetryAfter: 41500 * time.Millisecond})
+
+	app := fiber.New()
+	app.Post("/users/:id/email/verification", s.verifyHandler.ResendVerification)
+
+	req := httptest.NewRequest("POST", "/users/1/email/verification", nil)
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(s.T(), "42", resp.Header.Get("Retry-After"))
+}
+
+func (s *HandlerTestSuite) TestResendVerification_AlreadyVerified() {
+	s.verification.On("ResendVerification", mock.Anything, uint(1)).Return(service.ErrEmailAlreadyVerified)
+
+	app := fiber.New()
+	app.Post("/users/:id/email/verification", s.verifyHandler.ResendVerification)
+
+	req := httptest.NewRequest("POST", "/users/1/email/verification", nil)
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusConflict, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestResendVerification_UserNotFound() {
+	s.verification.On("ResendVerification", mock.Anything, uint(999)).Return(errors.New("not found"))
+
+	app := fiber.New()
+	app.Post("/users/:id/email/verification", s.verifyHandler.ResendVerification)
+
+	req := httptest.NewRequest("POST", "/users/999/email/verification", nil)
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestResendVerification_InvalidID() {
+	app := fiber.New()
+	app.Post("/users/:id/email/verification", s.verifyHandler.ResendVerification)
+
+	req := httptest.NewRequest("POST", "/users/invalid/email/verification", nil)
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
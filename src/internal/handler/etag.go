@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+var errInvalidIfMatch = errors.New("If-Match must be \"*\" or a list of entity tags")
+
+// formatETag renders a user version as a strong entity tag.
+func formatETag(version uint) string {
+	return `"` + strconv.FormatUint(uint64(version), 10) + `"`
+}
+
+// ifMatch is a parsed If-Match header.
+type ifMatch struct {
+	// anyVersion is set when the header is absent or "*"
+	anyVersion bool
+	// versions are the user versions named by strong tags
+	versions []uint
+}
+
+// matches reports whether the current version of a user meets the
+// precondition.
+func (m ifMatch) matches(current uint) bool {
+	return m.anyVersion || slices.Contains(m.versions, current)
+}
+
+// parseIfMatch parses the If-Match header. Weak tags, and tags that are not
+// user versions, never match under the strong comparison If-Match requires,
+// so they are left out; only a header that is not a list of entity tags is
+// an error.
+func parseIfMatch(c *fiber.Ctx) (ifMatch, error) {
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" || header == "*" {
+		return ifMatch{anyVersion: true}, nil
+	}
+
+	var m ifMatch
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		opaque, weak := strings.CutPrefix(tag, "W/")
+		if len(opaque) < 2 || !strings.HasPrefix(opaque, `"`) || !strings.HasSuffix(opaque, `"`) ||
+			strings.Contains(opaque[1:len(opaque)-1], `"`) {
+			return ifMatch{}, errInvalidIfMatch
+		}
+		if weak {
+			continue
+		}
+
+		version, err := strconv.ParseUint(opaque[1:len(opaque)-1], 10, 32)
+		if err == nil && version != 0 {
+			m.versions = append(m.versions, uint(version))
+		}
+	}
+
+	return m, nil
+}
+
+// ifNoneMatch reports whether the If-None-Match header matches etag using
+// the weak comparison defined for GET requests.
+func ifNoneMatch(c *fiber.Ctx, etag string) bool {
+	header := c.Get(fiber.HeaderIfNoneMatch)
+	if header == "" {
+		return false
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http/httptest"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *HandlerTestSuite) TestParseIfMatch() {
+	tests := []struct {
+		name    string
+		header  string
+		want    ifMatch
+		wantErr bool
+	}{
+		{name: "absent", header: "", want: ifMatch{anyVersion: true}},
+		{name: "any", header: "*", want: ifMatch{anyVersion: true}},
+		{name: "single", header: `"3"`, want: ifMatch{versions: []uint{3}}},
+		{name: "list", header: `"3", "5" ,"7"`, want: ifMatch{versions: []uint{3, 5, 7}}},
+		{name: "weak", header: `W/"3"`, want: ifMatch{}},
+		{name: "weak in list", header: `W/"3", "4"`, want: ifMatch{versions: []uint{4}}},
+		{name: "not a version", header: `"abc", "0"`, want: ifMatch{}},
+		{name: "unquoted", header: `3`, wantErr: true},
+		{name: "unquoted in list", header: `"3", 4`, wantErr: true},
+		{name: "any in list", header: `*, "3"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			var got ifMatch
+			var err error
+			app := fiber.New()
+			app.Put("/", func(c *fiber.Ctx) error {
+				got, err = parseIfMatch(c)
+				return nil
+			})
+			req := httptest.NewRequest("PUT", "/", nil)
+			if tt.header != "" {
+				req.Header.Set(fiber.HeaderIfMatch, tt.header)
+			}
+			app.Test(req)
+
+			if tt.wantErr {
+				assert.ErrorIs(s.T(), err, errInvalidIfMatch)
+				return
+			}
+			assert.NoError(s.T(), err)
+			assert.Equal(s.T(), tt.want, got)
+		})
+	}
+}
+
+func (s *HandlerTestSuite) TestIfMatch_Matches() {
+	assert.True(s.T(), ifMatch{anyVersion: true}.matches(7))
+	assert.True(s.T(), ifMatch{versions: []uint{3, 7}}.matches(7))
+	assert.False(s.T(), ifMatch{versions: []uint{3}}.matches(7))
+	assert.False(s.T(), ifMatch{}.matches(7))
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/gateway"
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=GatewayHandler --output=./mocks/handler --outpkg=handler --filename=gateway_handler.go --structname=MockGatewayHandler --with-expecter=false
+type GatewayHandler interface {
+	IssueTicket(c *fiber.Ctx) error
+	Connect(c *fiber.Ctx) error
+}
+
+type gatewayHandlerImpl struct {
+	gateway       *gateway.Gateway
+	ticketService service.GatewayTicketService
+	conf          config.GatewayConfig
+}
+
+func NewGatewayHandler(gateway *gateway.Gateway, ticketService service.GatewayTicketService, conf *config.Config) GatewayHandler {
+	return &gatewayHandlerImpl{
+		gateway:       gateway,
+		ticketService: ticketService,
+		conf:          conf.Gateway,
+	}
+}
+
+// IssueTicket issues a ticket for opening a gateway connection
+// @Summary Issue a gateway ticket
+// @Description Issue a ticket that opens one WebSocket connection to the gateway as the caller, in the organization of the request. The ticket expires after a short while and is passed as the ticket query parameter of the handshake, so that no credentials end up in the URL. Only users can connect.
+// @Tags gateway
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Success 201 {object} model.GatewayTicketResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /ws/ticket [post]
+func (h *gatewayHandlerImpl) IssueTicket(c *fiber.Ctx) error {
+	principal := middleware.PrincipalFromContext(c)
+	if principal == nil || principal.UserID == 0 {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Only users can connect to the gateway",
+		})
+	}
+
+	ticket, err := h.ticketService.IssueTicket(c.UserContext(), principal.UserID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to issue gateway ticket",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(ticket)
+}
+
+// Connect opens a WebSocket connection to the gateway
+// @Summary Connect to the gateway
+// @Description Upgrade to a WebSocket connection for real-time messages, authenticated with a ticket from POST /ws/ticket. Messages are JSON envelopes {id, type, channel, data, error}. The connection starts with a connected message and is subscribed to the channel of its user, user:<id>. Clients send subscribe and unsubscribe requests for presence:<id> channels, which tell when a user of the organization comes online or goes offline, and ping requests; each is answered with an ack or an error with the ID of the request. Messages on channels have an ID and are sent again until the client acks them. Browsers can only connect from the configured origins. Connections are closed after a while, so clients get a new ticket and reconnect.
+// @Tags gateway
+// @Param ticket query string true "Single-use ticket from POST /ws/ticket"
+// @Success 101
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 426 {object} map[string]string
+// @Router /ws [get]
+func (h *gatewayHandlerImpl) Connect(c *fiber.Ctx) error {
+	if !websocket.IsWebSocketUpgrade(c) {
+		c.Set(fiber.HeaderUpgrade, "websocket")
+		c.Set(fiber.HeaderSecWebSocketVersion, "13")
+		return c.Status(fiber.StatusUpgradeRequired).JSON(fiber.Map{
+			"error": "Expected a WebSocket upgrade",
+		})
+	}
+	if !h.allowedOrigin(c.Get(fiber.HeaderOrigin)) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Origin not allowed",
+		})
+	}
+
+	// Checked last, so that a handshake that is refused anyway does not use
+	// up the ticket
+	ticket, err := h.ticketService.RedeemTicket(c.UserContext(), c.Query("ticket"))
+	if errors.Is(err, service.ErrInvalidGatewayTicket) {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid or expired ticket",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to redeem gateway ticket",
+		})
+	}
+
+	// The connection outlives the handler, so it must not use c
+	ctx := tenant.WithOrganization(c.UserContext(), ticket.OrganizationID)
+	userID := ticket.UserID
+	return websocket.New(func(conn *websocket.Conn) {
+		h.gateway.Serve(ctx, conn.Conn, userID)
+	})(c)
+}
+
+// allowedOrigin reports whether a handshake from origin may connect. The
+// same-origin policy does not apply to WebSockets, so any page could open
+// one with the cookies or tokens of its visitor; handshakes without an
+// Origin header do not come from browsers and are let through.
+func (h *gatewayHandlerImpl) allowedOrigin(origin string) bool {
+	return origin == "" || slices.Contains(h.conf.AllowedOrigins, origin)
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/gateway"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/presence"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+)
+
+// newGatewayApp serves the gateway, and tickets to the caller in
+// organization 1.
+func (s *HandlerTestSuite) newGatewayApp(caller *model.Principal) (*fiber.App, *gateway.Gateway) {
+	conf := &config.Config{Gateway: config.GatewayConfig{
+		MaxConnections: 10,
+		MaxMessageSize: 1024,
+		PingInterval:   time.Minute,
+		PongTimeout:    time.Minute,
+		WriteTimeout:   time.Second,
+		SendBuffer:     10,
+		MaxUnacked:     10,
+		AckTimeout:     time.Minute,
+		MaxDuration:    time.Minute,
+		AllowedOrigins: []string{"https://app.example.com"},
+	}}
+	gw := gateway.NewGateway(presence.NewMemoryStore(), s.presences, conf)
+	gatewayHandler := NewGatewayHandler(gw, s.gatewayTickets, conf)
+
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(tenant.WithOrganization(c.UserContext(), 1))
+		c.Locals("principal", caller)
+		return c.Next()
+	})
+	app.Post("/ws/ticket", gatewayHandler.IssueTicket)
+	app.Get("/ws", gatewayHandler.Connect)
+	return app, gw
+}
+
+// upgradeRequest returns a WebSocket handshake for the gateway.
+func upgradeRequest(target, origin string) *http.Request {
+	req := httptest.NewRequest("GET", target, nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	return req
+}
+
+// Test IssueTicket handler
+func (s *HandlerTestSuite) TestIssueTicket_Success() {
+	app, _ := s.newGatewayApp(&model.Principal{UserID: 7, SessionID: "session-1"})
+	expiresAt := time.Now().Add(30 * time.Second)
+	s.gatewayTickets.On("IssueTicket", mock.Anything, uint(7)).Return(&model.GatewayTicketResponse{Ticket: "ticket-1", ExpiresAt: expiresAt}, nil)
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/ws/ticket", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusCreated, resp.StatusCode)
+	var body model.GatewayTicketResponse
+	json.NewDecoder(resp.Body).Decode(&body)
+	assert.Equal(s.T(), "ticket-1", body.Ticket)
+}
+
+func (s *HandlerTestSuite) TestIssueTicket_ServiceAPIKey() {
+	app, _ := s.newGatewayApp(&model.Principal{APIKeyID: 3, Service: "billing"})
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/ws/ticket", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusForbidden, resp.StatusCode)
+	s.gatewayTickets.AssertNotCalled(s.T(), "IssueTicket", mock.Anything, mock.Anything)
+}
+
+func (s *HandlerTestSuite) TestIssueTicket_Fails() {
+	app, _ := s.newGatewayApp(&model.Principal{UserID: 7, SessionID: "session-1"})
+	s.gatewayTickets.On("IssueTicket", mock.Anything, uint(7)).Return(nil, errors.New("db down"))
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/ws/ticket", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusInternalServerError, resp.StatusCode)
+}
+
+// Test Connect handler
+func (s *HandlerTestSuite) TestConnect_Success() {
+	// The ticket decides the user and organization, not the request
+	app, gw := s.newGatewayApp(nil)
+	s.gatewayTickets.On("RedeemTicket", mock.Anything, "ticket-1").Return(&model.GatewayTicket{ID: 3, OrganizationID: 1, UserID: 7}, nil)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(s.T(), err)
+	go app.Listener(ln)
+	defer app.Shutdown()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	header := http.Header{"Origin": {"https://app.example.com"}}
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, "ws://"+ln.Addr().String()+"/ws?ticket=ticket-1", header)
+	require.NoError(s.T(), err)
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, data, err := conn.ReadMessage()
+	require.NoError(s.T(), err)
+	var env gateway.Envelope
+	json.Unmarshal(data, &env)
+	assert.Equal(s.T(), gateway.TypeConnected, env.Type)
+	assert.Contains(s.T(), string(env.Data), `"user_id":7`)
+	assert.Equal(s.T(), 1, gw.Connections())
+}
+
+func (s *HandlerTestSuite) TestConnect_InvalidTicket() {
+	app, gw := s.newGatewayApp(nil)
+	s.gatewayTickets.On("RedeemTicket", mock.Anything, "used").Return(nil, service.ErrInvalidGatewayTicket)
+
+	resp, err := app.Test(upgradeRequest("/ws?ticket=used", ""))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusUnauthorized, resp.StatusCode)
+	assert.Zero(s.T(), gw.Connections())
+}
+
+func (s *HandlerTestSuite) TestConnect_RedeemFails() {
+	app, _ := s.newGatewayApp(nil)
+	s.gatewayTickets.On("RedeemTicket", mock.Anything, "ticket-1").Return(nil, errors.New("db down"))
+
+	resp, err := app.Test(upgradeRequest("/ws?ticket=ticket-1", ""))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusInternalServerError, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestConnect_OriginNotAllowed() {
+	app, gw := s.newGatewayApp(nil)
+
+	resp, err := app.Test(upgradeRequest("/ws?ticket=ticket-1", "https://evil.example.com"))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusForbidden, resp.StatusCode)
+	assert.Zero(s.T(), gw.Connections())
+	// The ticket is still good for an allowed origin
+	s.gatewayTickets.AssertNotCalled(s.T(), "RedeemTicket", mock.Anything, mock.Anything)
+}
+
+func (s *HandlerTestSuite) TestConnect_NotAnUpgrade() {
+	app, _ := s.newGatewayApp(nil)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/ws?ticket=ticket-1", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusUpgradeRequired, resp.StatusCode)
+	assert.Equal(s.T(), "websocket", resp.Header.Get("Upgrade"))
+	s.gatewayTickets.AssertNotCalled(s.T(), "RedeemTicket", mock.Anything, mock.Anything)
+}
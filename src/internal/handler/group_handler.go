@@ -0,0 +1,413 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=GroupHandler --output=./mocks/handler --outpkg=handler --filename=group_handler.go --structname=MockGroupHandler --with-expecter=false
+type GroupHandler interface {
+	CreateGroup(c *fiber.Ctx) error
+	ListGroups(c *fiber.Ctx) error
+	GetGroup(c *fiber.Ctx) error
+	UpdateGroup(c *fiber.Ctx) error
+	DeleteGroup(c *fiber.Ctx) error
+	ListMembers(c *fiber.Ctx) error
+	AddMembers(c *fiber.Ctx) error
+	RemoveMembers(c *fiber.Ctx) error
+	SetPermissions(c *fiber.Ctx) error
+	ListUserGroups(c *fiber.Ctx) error
+}
+
+type groupHandlerImpl struct {
+	groupService service.GroupService
+	validator    *validator.Validate
+}
+
+func NewGroupHandler(groupService service.GroupService) GroupHandler {
+	return &groupHandlerImpl{
+		groupService: groupService,
+		validator:    validator.New(),
+	}
+}
+
+// CreateGroup creates a group
+// @Summary Create group
+// @Description Create a group in the organization of the request, optionally nested in another group. Members of a group are also members of the groups above it. Needs the groups:manage permission.
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param group body model.CreateGroupRequest true "Group"
+// @Success 201 {object} model.GroupResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /groups [post]
+func (h *groupHandlerImpl) CreateGroup(c *fiber.Ctx) error {
+	var req model.CreateGroupRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	group, err := h.groupService.CreateGroup(c.UserContext(), &req)
+	if err != nil {
+		return h.groupError(c, err, "Failed to create group")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(group)
+}
+
+// ListGroups lists groups
+// @Summary List groups
+// @Description List the groups of the organization of the request by name, with the permissions granted to them.
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Success 200 {array} model.GroupResponse
+// @Failure 500 {object} map[string]string
+// @Router /groups [get]
+func (h *groupHandlerImpl) ListGroups(c *fiber.Ctx) error {
+	groups, err := h.groupService.ListGroups(c.UserContext())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch groups",
+		})
+	}
+
+	return c.JSON(groups)
+}
+
+// GetGroup gets a group
+// @Summary Get group
+// @Description Get a group with the permissions granted to it.
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Group ID"
+// @Success 200 {object} model.GroupResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /groups/{id} [get]
+func (h *groupHandlerImpl) GetGroup(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid group ID",
+		})
+	}
+
+	group, err := h.groupService.GetGroup(c.UserContext(), uint(id))
+	if err != nil {
+		return h.groupError(c, err, "Failed to fetch group")
+	}
+
+	return c.JSON(group)
+}
+
+// UpdateGroup renames and moves a group
+// @Summary Update group
+// @Description Rename a group and set its parent; without parent_id it becomes a top-level group. A group cannot be moved into itself or one of its subgroups. Needs the groups:manage permission.
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Group ID"
+// @Param group body model.UpdateGroupRequest true "Group"
+// @Success 200 {object} model.GroupResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /groups/{id} [put]
+func (h *groupHandlerImpl) UpdateGroup(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid group ID",
+		})
+	}
+
+	var req model.UpdateGroupRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	group, err := h.groupService.UpdateGroup(c.UserContext(), uint(id), &req)
+	if err != nil {
+		return h.groupError(c, err, "Failed to update group")
+	}
+
+	return c.JSON(group)
+}
+
+// DeleteGroup deletes a group
+// @Summary Delete group
+// @Description Delete a group with its memberships and permissions. Its subgroups move up to its parent. Needs the groups:manage permission.
+// @Tags groups
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Group ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /groups/{id} [delete]
+func (h *groupHandlerImpl) DeleteGroup(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid group ID",
+		})
+	}
+
+	if err := h.groupService.DeleteGroup(c.UserContext(), uint(id)); err != nil {
+		return h.groupError(c, err, "Failed to delete group")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListMembers lists the members of a group
+// @Summary List group members
+// @Description List the direct members of a group.
+// @Tags groups
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Group ID"
+// @Success 200 {array} model.GroupMemberResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /groups/{id}/members [get]
+func (h *groupHandlerImpl) ListMembers(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid group ID",
+		})
+	}
+
+	members, err := h.groupService.ListMembers(c.UserContext(), uint(id))
+	if err != nil {
+		return h.groupError(c, err, "Failed to fetch group members")
+	}
+
+	return c.JSON(members)
+}
+
+// AddMembers adds members to a group
+// @Summary Add group members
+// @Description Add up to 100 members of the organization to a group and return its members. Users already in the group are skipped; if any user is not a member of the organization, none are added. Needs the groups:manage permission.
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Group ID"
+// @Param members body model.GroupMembersRequest true "Users"
+// @Success 200 {array} model.GroupMemberResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /groups/{id}/members [post]
+func (h *groupHandlerImpl) AddMembers(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid group ID",
+		})
+	}
+
+	var req model.GroupMembersRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	members, err := h.groupService.AddMembers(c.UserContext(), uint(id), &req)
+	if err != nil {
+		return h.groupError(c, err, "Failed to add group members")
+	}
+
+	return c.JSON(members)
+}
+
+// RemoveMembers removes members from a group
+// @Summary Remove group members
+// @Description Remove up to 100 users from a group. Users not in the group are ignored. Needs the groups:manage permission.
+// @Tags groups
+// @Accept json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Group ID"
+// @Param members body model.GroupMembersRequest true "Users"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /groups/{id}/members [delete]
+func (h *groupHandlerImpl) RemoveMembers(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid group ID",
+		})
+	}
+
+	var req model.GroupMembersRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if err := h.groupService.RemoveMembers(c.UserContext(), uint(id), &req); err != nil {
+		return h.groupError(c, err, "Failed to remove group members")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// SetPermissions sets the permissions of a group
+// @Summary Set group permissions
+// @Description Replace the permissions granted to a group. Members of the group and of its subgroups hold them. Needs the groups:manage permission.
+// @Tags groups
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Group ID"
+// @Param permissions body model.GroupPermissionsRequest true "Permissions"
+// @Success 200 {object} model.GroupResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /groups/{id}/permissions [put]
+func (h *groupHandlerImpl) SetPermissions(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid group ID",
+		})
+	}
+
+	var req model.GroupPermissionsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	group, err := h.groupService.SetPermissions(c.UserContext(), uint(id), &req)
+	if err != nil {
+		return h.groupError(c, err, "Failed to set group permissions")
+	}
+
+	return c.JSON(group)
+}
+
+// ListUserGroups lists the groups of a user
+// @Summary List user groups
+// @Description List the groups a user is in within the organization of the request, directly or through a subgroup, and the permissions granted to them.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} model.UserGroupsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/{id}/groups [get]
+func (h *groupHandlerImpl) ListUserGroups(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	groups, err := h.groupService.ListUserGroups(c.UserContext(), uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch groups",
+		})
+	}
+
+	return c.JSON(groups)
+}
+
+func (h *groupHandlerImpl) groupError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, service.ErrGroupNotFound),
+		errors.Is(err, service.ErrParentGroupNotFound),
+		errors.Is(err, service.ErrGroupUserNotMember):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrGroupNameTaken),
+		errors.Is(err, service.ErrGroupCycle):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": message,
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+)
+
+func (s *HandlerTestSuite) newGroupApp() *fiber.App {
+	app := fiber.New()
+	app.Post("/groups", s.groupHandler.CreateGroup)
+	app.Get("/groups/:id", s.groupHandler.GetGroup)
+	app.Put("/groups/:id", s.groupHandler.UpdateGroup)
+	app.Delete("/groups/:id", s.groupHandler.DeleteGroup)
+	app.Post("/groups/:id/members", s.groupHandler.AddMembers)
+	app.Delete("/groups/:id/members", s.groupHandler.RemoveMembers)
+	app.Put("/groups/:id/permissions", s.groupHandler.SetPermissions)
+	app.Get("/users/:id/groups", s.groupHandler.ListUserGroups)
+	return app
+}
+
+// Test CreateGroup handler
+func (s *HandlerTestSuite) TestCreateGroup_Success() {
+	req := &model.CreateGroupRequest{Name: "Engineering"}
+	s.groups.On("CreateGroup", mock.Anything, req).Return(&model.GroupResponse{ID: 1, Name: "Engineering", Permissions: []string{}}, nil)
+
+	body, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest("POST", "/groups", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.newGroupApp().Test(httpReq)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusCreated, resp.StatusCode)
+
+	var result model.GroupResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), "Engineering", result.Name)
+}
+
+func (s *HandlerTestSuite) TestCreateGroup_MissingName() {
+	httpReq := httptest.NewRequest("POST", "/groups", bytes.NewReader([]byte(`{}`)))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.newGroupApp().Test(httpReq)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+	s.groups.AssertNotCalled(s.T(), "CreateGroup", mock.Anything, mock.Anything)
+}
+
+// Test UpdateGroup handler
+func (s *HandlerTestSuite) TestUpdateGroup_Errors() {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{service.ErrGroupNotFound, fiber.StatusNotFound},
+		{service.ErrParentGroupNotFound, fiber.StatusNotFound},
+		{service.ErrGroupNameTaken, fiber.StatusConflict},
+		{service.ErrGroupCycle, fiber.StatusConflict},
+		{errors.New("db down"), fiber.StatusInternalServerError},
+	}
+	for _, tc := range cases {
+		s.groups.ExpectedCalls = nil
+		s.groups.On("UpdateGroup", mock.Anything, uint(1), mock.Anything).Return(nil, tc.err)
+
+		httpReq := httptest.NewRequest("PUT", "/groups/1", bytes.NewReader([]byte(`{"name":"Engineering","parent_id":3}`)))
+		httpReq.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.newGroupApp().Test(httpReq)
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), tc.status, resp.StatusCode, tc.err.Error())
+	}
+}
+
+// Test DeleteGroup handler
+func (s *HandlerTestSuite) TestDeleteGroup_Success() {
+	s.groups.On("DeleteGroup", mock.Anything, uint(1)).Return(nil)
+
+	resp, err := s.newGroupApp().Test(httptest.NewRequest("DELETE", "/groups/1", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNoContent, resp.StatusCode)
+}
+
+// Test AddMembers handler
+func (s *HandlerTestSuite) TestAddMembers_Success() {
+	req := &model.GroupMembersRequest{UserIDs: []uint{1, 2}}
+	s.groups.On("AddMembers", mock.Anything, uint(1), req).Return([]*model.GroupMemberResponse{{UserID: 1}, {UserID: 2}}, nil)
+
+	body, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest("POST", "/groups/1/members", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.newGroupApp().Test(httpReq)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result []model.GroupMemberResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Len(s.T(), result, 2)
+}
+
+func (s *HandlerTestSuite) TestAddMembers_NotOrganizationMember() {
+	s.groups.On("AddMembers", mock.Anything, uint(1), mock.Anything).Return(nil, service.ErrGroupUserNotMember)
+
+	httpReq := httptest.NewRequest("POST", "/groups/1/members", bytes.NewReader([]byte(`{"user_ids":[9]}`)))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.newGroupApp().Test(httpReq)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestAddMembers_Empty() {
+	httpReq := httptest.NewRequest("POST", "/groups/1/members", bytes.NewReader([]byte(`{"user_ids":[]}`)))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.newGroupApp().Test(httpReq)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// Test RemoveMembers handler
+func (s *HandlerTestSuite) TestRemoveMembers_Success() {
+	s.groups.On("RemoveMembers", mock.Anything, uint(1), &model.GroupMembersRequest{UserIDs: []uint{2}}).Return(nil)
+
+	httpReq := httptest.NewRequest("DELETE", "/groups/1/members", bytes.NewReader([]byte(`{"user_ids":[2]}`)))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.newGroupApp().Test(httpReq)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNoContent, resp.StatusCode)
+}
+
+// Test SetPermissions handler
+func (s *HandlerTestSuite) TestSetPermissions_Success() {
+	for _, permission := range []string{
+		model.PermissionGroupsManage,
+		model.PermissionUsersManage,
+		model.PermissionAPIKeysManage,
+		model.PermissionInvitationsManage,
+		model.PermissionAuditRead,
+		model.PermissionWebhooksManage,
+		model.PermissionJobsManage,
+		model.PermissionSchedulesManage,
+	} {
+		req := &model.GroupPermissionsRequest{Permissions: []string{permission}}
+		s.groups.On("SetPermissions", mock.Anything, uint(1), req).Return(&model.GroupResponse{ID: 1, Name: "Engineering", Permissions: req.Permissions}, nil).Once()
+
+		httpReq := httptest.NewRequest("PUT", "/groups/1/permissions", bytes.NewReader([]byte(`{"permissions":["`+permission+`"]}`)))
+		httpReq.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.newGroupApp().Test(httpReq)
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode, permission)
+	}
+	s.groups.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestSetPermissions_UnknownPermission() {
+	httpReq := httptest.NewRequest("PUT", "/groups/1/permissions", bytes.NewReader([]byte(`{"permissions":["everything"]}`)))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.newGroupApp().Test(httpReq)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+	s.groups.AssertNotCalled(s.T(), "SetPermissions", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test ListUserGroups handler
+func (s *HandlerTestSuite) TestListUserGroups_Success() {
+	s.groups.On("ListUserGroups", mock.Anything, uint(2)).Return(&model.UserGroupsResponse{
+		Groups:      []*model.GroupResponse{{ID: 1, Name: "Engineering", Permissions: []string{model.PermissionGroupsManage}}},
+		Permissions: []string{model.PermissionGroupsManage},
+	}, nil)
+
+	resp, err := s.newGroupApp().Test(httptest.NewRequest("GET", "/users/2/groups", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result model.UserGroupsResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), []string{model.PermissionGroupsManage}, result.Permissions)
+}
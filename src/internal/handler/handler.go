@@ -3,17 +3,80 @@ package handler
 import "go.uber.org/dig"
 
 type Handler struct {
-	UserHandler UserHandler
+	UserHandler              UserHandler
+	AuthHandler              AuthHandler
+	PasswordHandler          PasswordHandler
+	EmailVerificationHandler EmailVerificationHandler
+	MFAHandler               MFAHandler
+	LockoutHandler           LockoutHandler
+	SessionHandler           SessionHandler
+	APIKeyHandler            APIKeyHandler
+	OIDCHandler              OIDCHandler
+	IdentityProviderHandler  IdentityProviderHandler
+	MagicLinkHandler         MagicLinkHandler
+	PasskeyHandler           PasskeyHandler
+	InvitationHandler        InvitationHandler
+	OrganizationHandler      OrganizationHandler
+	GroupHandler             GroupHandler
+	AuditHandler             AuditHandler
+	WebhookHandler           WebhookHandler
+	JobHandler               JobHandler
+	ScheduleHandler          ScheduleHandler
+	UserEventHandler         UserEventHandler
+	GatewayHandler           GatewayHandler
+	PresenceHandler          PresenceHandler
 }
 
 type HandlerParams struct {
 	dig.In
 
-	UserHandler UserHandler
+	UserHandler              UserHandler
+	AuthHandler              AuthHandler
+	PasswordHandler          PasswordHandler
+	EmailVerificationHandler EmailVerificationHandler
+	MFAHandler               MFAHandler
+	LockoutHandler           LockoutHandler
+	SessionHandler           SessionHandler
+	APIKeyHandler            APIKeyHandler
+	OIDCHandler              OIDCHandler
+	IdentityProviderHandler  IdentityProviderHandler
+	MagicLinkHandler         MagicLinkHandler
+	PasskeyHandler           PasskeyHandler
+	InvitationHandler        InvitationHandler
+	OrganizationHandler      OrganizationHandler
+	GroupHandler             GroupHandler
+	AuditHandler             AuditHandler
+	WebhookHandler           WebhookHandler
+	JobHandler               JobHandler
+	ScheduleHandler          ScheduleHandler
+	UserEventHandler         UserEventHandler
+	GatewayHandler           GatewayHandler
+	PresenceHandler          PresenceHandler
 }
 
 func NewHandler(params HandlerParams) *Handler {
 	return &Handler{
-		UserHandler: params.UserHandler,
+		UserHandler:              params.UserHandler,
+		AuthHandler:              params.AuthHandler,
+		PasswordHandler:          params.PasswordHandler,
+		EmailVerificationHandler: params.EmailVerificationHandler,
+		MFAHandler:               params.MFAHandler,
+		LockoutHandler:           params.LockoutHandler,
+		SessionHandler:           params.SessionHandler,
+		APIKeyHandler:            params.APIKeyHandler,
+		OIDCHandler:              params.OIDCHandler,
+		IdentityProviderHandler:  params.IdentityProviderHandler,
+		MagicLinkHandler:         params.MagicLinkHandler,
+		PasskeyHandler:           params.PasskeyHandler,
+		InvitationHandler:        params.InvitationHandler,
+		OrganizationHandler:      params.OrganizationHandler,
+		GroupHandler:             params.GroupHandler,
+		AuditHandler:             params.AuditHandler,
+		WebhookHandler:           params.WebhookHandler,
+		JobHandler:               params.JobHandler,
+		ScheduleHandler:          params.ScheduleHandler,
+		UserEventHandler:         params.UserEventHandler,
+		GatewayHandler:           params.GatewayHandler,
+		PresenceHandler:          params.PresenceHandler,
 	}
 }
@@ -9,17 +9,118 @@ import (
 
 type HandlerTestSuite struct {
 	suite.Suite
-	userService *mocks.MockUserService
-	userHandler UserHandler
+	userService     *mocks.MockUserService
+	authService     *mocks.MockAuthService
+	passwordService *mocks.MockPasswordService
+	verification    *mocks.MockEmailVerificationService
+	mfaService      *mocks.MockMFAService
+	lockoutService  *mocks.MockLockoutService
+	sessionService  *mocks.MockSessionService
+	apiKeyService   *mocks.MockAPIKeyService
+	oidcService     *mocks.MockOIDCLoginService
+	idpService      *mocks.MockIdentityProviderService
+	oauthClients    *mocks.MockOAuthClientService
+	magicLinks      *mocks.MockMagicLinkService
+	passkeys        *mocks.MockPasskeyService
+	invitations     *mocks.MockInvitationService
+	organizations   *mocks.MockOrganizationService
+	groups          *mocks.MockGroupService
+	audit           *mocks.MockAuditService
+	webhooks        *mocks.MockWebhookService
+	jobs            *mocks.MockJobService
+	schedules       *mocks.MockScheduleService
+	presences       *mocks.MockPresenceService
+	gatewayTickets  *mocks.MockGatewayTicketService
+	userHandler     UserHandler
+	authHandler     AuthHandler
+	passwordHandler PasswordHandler
+	verifyHandler   EmailVerificationHandler
+	mfaHandler      MFAHandler
+	lockoutHandler  LockoutHandler
+	sessionHandler  SessionHandler
+	apiKeyHandler   APIKeyHandler
+	oidcHandler     OIDCHandler
+	idpHandler      IdentityProviderHandler
+	magicHandler    MagicLinkHandler
+	passkeyHandler  PasskeyHandler
+	inviteHandler   InvitationHandler
+	orgHandler      OrganizationHandler
+	groupHandler    GroupHandler
+	auditHandler    AuditHandler
+	webhookHandler  WebhookHandler
+	jobHandler      JobHandler
+	scheduleHandler ScheduleHandler
+	presenceHandler PresenceHandler
 }
 
 func (s *HandlerTestSuite) SetupTest() {
 	s.userService = mocks.NewMockUserService(s.T())
+	s.authService = mocks.NewMockAuthService(s.T())
+	s.passwordService = mocks.NewMockPasswordService(s.T())
+	s.verification = mocks.NewMockEmailVerificationService(s.T())
+	s.mfaService = mocks.NewMockMFAService(s.T())
+	s.lockoutService = mocks.NewMockLockoutService(s.T())
+	s.sessionService = mocks.NewMockSessionService(s.T())
+	s.apiKeyService = mocks.NewMockAPIKeyService(s.T())
+	s.oidcService = mocks.NewMockOIDCLoginService(s.T())
+	s.idpService = mocks.NewMockIdentityProviderService(s.T())
+	s.oauthClients = mocks.NewMockOAuthClientService(s.T())
+	s.magicLinks = mocks.NewMockMagicLinkService(s.T())
+	s.passkeys = mocks.NewMockPasskeyService(s.T())
+	s.invitations = mocks.NewMockInvitationService(s.T())
+	s.organizations = mocks.NewMockOrganizationService(s.T())
+	s.groups = mocks.NewMockGroupService(s.T())
+	s.audit = mocks.NewMockAuditService(s.T())
+	s.webhooks = mocks.NewMockWebhookService(s.T())
+	s.jobs = mocks.NewMockJobService(s.T())
+	s.schedules = mocks.NewMockScheduleService(s.T())
+	s.presences = mocks.NewMockPresenceService(s.T())
+	s.gatewayTickets = mocks.NewMockGatewayTicketService(s.T())
 	s.userHandler = NewUserHandler(s.userService)
+	s.authHandler = NewAuthHandler(s.authService)
+	s.passwordHandler = NewPasswordHandler(s.passwordService)
+	s.verifyHandler = NewEmailVerificationHandler(s.verification)
+	s.mfaHandler = NewMFAHandler(s.mfaService)
+	s.lockoutHandler = NewLockoutHandler(s.lockoutService)
+	s.sessionHandler = NewSessionHandler(s.sessionService)
+	s.apiKeyHandler = NewAPIKeyHandler(s.apiKeyService)
+	s.oidcHandler = NewOIDCHandler(s.oidcService)
+	s.idpHandler = NewIdentityProviderHandler(s.idpService, s.oauthClients)
+	s.magicHandler = NewMagicLinkHandler(s.magicLinks)
+	s.passkeyHandler = NewPasskeyHandler(s.passkeys)
+	s.inviteHandler = NewInvitationHandler(s.invitations)
+	s.orgHandler = NewOrganizationHandler(s.organizations)
+	s.groupHandler = NewGroupHandler(s.groups)
+	s.auditHandler = NewAuditHandler(s.audit)
+	s.webhookHandler = NewWebhookHandler(s.webhooks)
+	s.jobHandler = NewJobHandler(s.jobs)
+	s.scheduleHandler = NewScheduleHandler(s.schedules)
+	s.presenceHandler = NewPresenceHandler(s.presences)
 }
 
 func (s *HandlerTestSuite) TearDownTest() {
 	s.userService.ExpectedCalls = nil
+	s.authService.ExpectedCalls = nil
+	s.passwordService.ExpectedCalls = nil
+	s.verification.ExpectedCalls = nil
+	s.mfaService.ExpectedCalls = nil
+	s.lockoutService.ExpectedCalls = nil
+	s.sessionService.ExpectedCalls = nil
+	s.apiKeyService.ExpectedCalls = nil
+	s.oidcService.ExpectedCalls = nil
+	s.idpService.ExpectedCalls = nil
+	s.oauthClients.ExpectedCalls = nil
+	s.magicLinks.ExpectedCalls = nil
+	s.passkeys.ExpectedCalls = nil
+	s.invitations.ExpectedCalls = nil
+	s.organizations.ExpectedCalls = nil
+	s.groups.ExpectedCalls = nil
+	s.audit.ExpectedCalls = nil
+	s.webhooks.ExpectedCalls = nil
+	s.jobs.ExpectedCalls = nil
+	s.schedules.ExpectedCalls = nil
+	s.presences.ExpectedCalls = nil
+	s.gatewayTickets.ExpectedCalls = nil
 }
 
 func TestHandlerSuite(t *testing.T) {
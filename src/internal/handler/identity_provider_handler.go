@@ -0,0 +1,456 @@
+package handler
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=IdentityProviderHandler --output=./mocks/handler --outpkg=handler --filename=identity_provider_handler.go --structname=MockIdentityProviderHandler --with-expecter=false
+type IdentityProviderHandler interface {
+	Discovery(c *fiber.Ctx) error
+	JWKS(c *fiber.Ctx) error
+	Authorize(c *fiber.Ctx) error
+	Token(c *fiber.Ctx) error
+	UserInfo(c *fiber.Ctx) error
+	CreateClient(c *fiber.Ctx) error
+	ListClients(c *fiber.Ctx) error
+	RevokeClient(c *fiber.Ctx) error
+	ListConsents(c *fiber.Ctx) error
+	RevokeConsent(c *fiber.Ctx) error
+}
+
+type identityProviderHandlerImpl struct {
+	idpService    service.IdentityProviderService
+	clientService service.OAuthClientService
+	validator     *validator.Validate
+}
+
+func NewIdentityProviderHandler(idpService service.IdentityProviderService, clientService service.OAuthClientService) IdentityProviderHandler {
+	return &identityProviderHandlerImpl{
+		idpService:    idpService,
+		clientService: clientService,
+		validator:     validator.New(),
+	}
+}
+
+// Discovery serves the OpenID Connect discovery document
+// @Summary OpenID Connect discovery
+// @Description The discovery document of the identity provider. It is served at /.well-known/openid-configuration, outside the API prefix.
+// @Tags oauth
+// @Produce json
+// @Success 200 {object} model.OpenIDConfiguration
+// @Router /.well-known/openid-configuration [get]
+func (h *identityProviderHandlerImpl) Discovery(c *fiber.Ctx) error {
+	return c.JSON(h.idpService.Discovery())
+}
+
+// JWKS serves the public signing keys
+// @Summary JSON Web Key Set
+// @Description The public keys that verify ID tokens and access tokens, including recently rotated ones
+// @Tags oauth
+// @Produce json
+// @Success 200 {object} model.JSONWebKeySet
+// @Failure 500 {object} map[string]string
+// @Router /oauth/jwks [get]
+func (h *identityProviderHandlerImpl) JWKS(c *fiber.Ctx) error {
+	keys, err := h.idpService.JWKS()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to load signing keys",
+		})
+	}
+
+	c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(keys)
+}
+
+// Authorize handles an authentication request of a client
+// @Summary Authorize client
+// @Description Called by the login page with the signed-in user's access token and the client's request parameters. Send the browser to redirect_to, or, when consent_required is set, ask the user and POST the request again with consent.
+// @Tags oauth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param response_type query string false "Must be code"
+// @Param client_id query string false "Client ID"
+// @Param redirect_uri query string false "Registered redirect URI"
+// @Param scope query string false "Space separated scopes, including openid"
+// @Param state query string false "Opaque client state"
+// @Param nonce query string false "Nonce to put in the ID token"
+// @Param prompt query string false "none or consent"
+// @Param code_challenge query string false "PKCE code challenge"
+// @Param code_challenge_method query string false "Must be S256"
+// @Param request body model.AuthorizeRequest false "Request with the user's consent (POST only)"
+// @Success 200 {object} model.AuthorizeResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /oauth/authorize [get]
+// @Router /oauth/authorize [post]
+func (h *identityProviderHandlerImpl) Authorize(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	var req model.AuthorizeRequest
+	var err error
+	if c.Method() == fiber.MethodGet {
+		err = c.QueryParser(&req)
+	} else {
+		err = c.BodyParser(&req)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request",
+		})
+	}
+
+	result, err := h.idpService.Authorize(middleware.PrincipalFromContext(c), &req)
+	var oauthErr *service.OAuthError
+	switch {
+	case errors.As(err, &oauthErr):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":             oauthErr.Code,
+			"error_description": oauthErr.Description,
+		})
+	case errors.Is(err, service.ErrSessionRequired):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to authorize client",
+		})
+	}
+
+	return c.JSON(result)
+}
+
+// Token redeems an authorization code
+// @Summary Token endpoint
+// @Description Exchange an authorization code for an access token and an ID token. Confidential clients authenticate with HTTP basic authentication or client_secret in the body; public clients send only client_id and a PKCE code_verifier.
+// @Tags oauth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "Must be authorization_code"
+// @Param code formData string true "Authorization code"
+// @Param redirect_uri formData string true "Redirect URI of the authorization request"
+// @Param code_verifier formData string false "PKCE code verifier"
+// @Param client_id formData string false "Client ID"
+// @Param client_secret formData string false "Client secret"
+// @Success 200 {object} model.TokenResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /oauth/token [post]
+func (h *identityProviderHandlerImpl) Token(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Set(fiber.HeaderPragma, "no-cache")
+
+	var req model.TokenRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":             service.OAuthInvalidRequest,
+			"error_description": "Invalid request body",
+		})
+	}
+
+	usedBasicAuth := false
+	if header := c.Get(fiber.HeaderAuthorization); header != "" {
+		clientID, clientSecret, ok := parseBasicAuth(header)
+		if !ok || (req.ClientID != "" && req.ClientID != clientID) {
+			c.Set(fiber.HeaderWWWAuthenticate, "Basic")
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":             service.OAuthInvalidClient,
+				"error_description": "Invalid client credentials",
+			})
+		}
+		req.ClientID = clientID
+		req.ClientSecret = clientSecret
+		usedBasicAuth = true
+	}
+
+	tokens, err := h.idpService.Token(c.UserContext(), &req)
+	var oauthErr *service.OAuthError
+	switch {
+	case errors.As(err, &oauthErr):
+		status := fiber.StatusBadRequest
+		if oauthErr.Code == service.OAuthInvalidClient {
+			status = fiber.StatusUnauthorized
+			if usedBasicAuth {
+				c.Set(fiber.HeaderWWWAuthenticate, "Basic")
+			}
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error":             oauthErr.Code,
+			"error_description": oauthErr.Description,
+		})
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": service.OAuthServerError,
+		})
+	}
+
+	return c.JSON(tokens)
+}
+
+// UserInfo returns the claims about the user of an access token
+// @Summary UserInfo endpoint
+// @Description Return the claims about the user that the access token's scopes allow
+// @Tags oauth
+// @Produce json
+// @Param Authorization header string true "Bearer access token issued by the token endpoint"
+// @Success 200 {object} model.UserInfo
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /oauth/userinfo [get]
+// @Router /oauth/userinfo [post]
+func (h *identityProviderHandlerImpl) UserInfo(c *fiber.Ctx) error {
+	scheme, token, ok := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing bearer token",
+		})
+	}
+
+	info, err := h.idpService.UserInfo(c.UserContext(), token)
+	var oauthErr *service.OAuthError
+	switch {
+	case errors.As(err, &oauthErr):
+		c.Set(fiber.HeaderWWWAuthenticate, `Bearer error="`+oauthErr.Code+`"`)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error":             oauthErr.Code,
+			"error_description": oauthErr.Description,
+		})
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": service.OAuthServerError,
+		})
+	}
+
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(info)
+}
+
+// CreateClient registers a client of the identity provider
+// @Summary Create OAuth client
+// @Description Register an application that signs users in with this service. Only users with the admin role can manage clients. Redirect URIs must use https, or http on a loopback address. The client secret is only returned once; public clients get none and must use PKCE.
+// @Tags oauth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param client body model.CreateOAuthClientRequest true "Client"
+// @Success 201 {object} model.CreateOAuthClientResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /oauth/clients [post]
+func (h *identityProviderHandlerImpl) CreateClient(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	var req model.CreateOAuthClientRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	client, err := h.clientService.Create(c.UserContext(), middleware.PrincipalFromContext(c), &req)
+	switch {
+	case errors.Is(err, service.ErrInvalidRedirectURI), errors.Is(err, service.ErrOpenIDScopeRequired):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrOAuthClientForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create client",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(client)
+}
+
+// ListClients lists the clients of the identity provider
+// @Summary List OAuth clients
+// @Description List the registered clients, including revoked ones. Secrets are never returned. Only users with the admin role can manage clients.
+// @Tags oauth
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Success 200 {array} model.OAuthClientResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /oauth/clients [get]
+func (h *identityProviderHandlerImpl) ListClients(c *fiber.Ctx) error {
+	clients, err := h.clientService.List(c.UserContext(), middleware.PrincipalFromContext(c))
+	if errors.Is(err, service.ErrOAuthClientForbidden) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to list clients",
+		})
+	}
+
+	return c.JSON(clients)
+}
+
+// RevokeClient revokes a client of the identity provider
+// @Summary Revoke OAuth client
+// @Description Stop a client from signing users in. Its access tokens are rejected by the userinfo endpoint from then on. Only users with the admin role can manage clients.
+// @Tags oauth
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Client ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /oauth/clients/{id} [delete]
+func (h *identityProviderHandlerImpl) RevokeClient(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid client ID",
+		})
+	}
+
+	err = h.clientService.Revoke(c.UserContext(), middleware.PrincipalFromContext(c), uint(id))
+	switch {
+	case errors.Is(err, service.ErrOAuthClientForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrOAuthClientNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to revoke client",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListConsents lists the clients a user has consented to
+// @Summary List consents
+// @Description List the clients a user has allowed to sign them in, with the granted scopes. Open to the user and members with the users:manage permission.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Success 200 {array} model.OAuthConsentResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/{id}/consents [get]
+func (h *identityProviderHandlerImpl) ListConsents(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	consents, err := h.idpService.ListConsents(c.UserContext(), uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	return c.JSON(consents)
+}
+
+// RevokeConsent withdraws a user's consent to a client
+// @Summary Revoke consent
+// @Description Withdraw a user's consent to a client. The client's access tokens for the user stop working and the user is asked again on the next sign-in.
+// @Tags users
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Param client_id path string true "Client ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/{id}/consents/{client_id} [delete]
+func (h *identityProviderHandlerImpl) RevokeConsent(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	err = h.idpService.RevokeConsent(uint(id), c.Params("client_id"))
+	if errors.Is(err, service.ErrConsentNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to revoke consent",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// parseBasicAuth reads client credentials from a basic Authorization header.
+// RFC 6749 form-encodes them before they are joined.
+func parseBasicAuth(header string) (clientID, clientSecret string, ok bool) {
+	scheme, encoded, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Basic") {
+		return "", "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", "", false
+	}
+	rawID, rawSecret, found := strings.Cut(string(decoded), ":")
+	if !found {
+		return "", "", false
+	}
+	clientID, err = url.QueryUnescape(rawID)
+	if err != nil {
+		return "", "", false
+	}
+	clientSecret, err = url.QueryUnescape(rawSecret)
+	if err != nil {
+		return "", "", false
+	}
+	return clientID, clientSecret, true
+}
@@ -0,0 +1,314 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+)
+
+// newIdentityProviderApp serves the identity provider routes as the given
+// caller.
+func (s *HandlerTestSuite) newIdentityProviderApp(caller *model.Principal) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("principal", caller)
+		return c.Next()
+	})
+	app.Get("/oauth/authorize", s.idpHandler.Authorize)
+	app.Post("/oauth/authorize", s.idpHandler.Authorize)
+	app.Post("/oauth/token", s.idpHandler.Token)
+	app.Get("/oauth/userinfo", s.idpHandler.UserInfo)
+	app.Post("/oauth/clients", s.idpHandler.CreateClient)
+	app.Get("/oauth/clients", s.idpHandler.ListClients)
+	app.Delete("/oauth/clients/:id", s.idpHandler.RevokeClient)
+	app.Delete("/users/:id/consents/:client_id", s.idpHandler.RevokeConsent)
+	return app
+}
+
+// Test Authorize handler
+func (s *HandlerTestSuite) TestAuthorize_QueryParameters() {
+	caller := &model.Principal{UserID: 1, SessionID: "session-1"}
+	s.idpService.On("Authorize", caller, mock.AnythingOfType("*model.AuthorizeRequest")).
+		Return(&model.AuthorizeResponse{RedirectTo: "https://app.example.com/callback?code=code-1&state=xyz"}, nil)
+
+	req := httptest.NewRequest("GET", "/oauth/authorize?response_type=code&client_id=client-1&scope=openid+email&state=xyz&consent=true", nil)
+	resp, err := s.newIdentityProviderApp(caller).Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result model.AuthorizeResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), "https://app.example.com/callback?code=code-1&state=xyz", result.RedirectTo)
+
+	// Consent is never taken from a link
+	parsed := s.idpService.Calls[0].Arguments.Get(1).(*model.AuthorizeRequest)
+	assert.Equal(s.T(), "client-1", parsed.ClientID)
+	assert.Equal(s.T(), "openid email", parsed.Scope)
+	assert.Nil(s.T(), parsed.Consent)
+}
+
+func (s *HandlerTestSuite) TestAuthorize_ConsentFromBody() {
+	caller := &model.Principal{UserID: 1, SessionID: "session-1"}
+	s.idpService.On("Authorize", caller, mock.AnythingOfType("*model.AuthorizeRequest")).
+		Return(&model.AuthorizeResponse{RedirectTo: "https://app.example.com/callback?code=code-1"}, nil)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"response_type": "code",
+		"client_id":     "client-1",
+		"consent":       true,
+	})
+	req := httptest.NewRequest("POST", "/oauth/authorize", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.newIdentityProviderApp(caller).Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	parsed := s.idpService.Calls[0].Arguments.Get(1).(*model.AuthorizeRequest)
+	assert.True(s.T(), *parsed.Consent)
+}
+
+func (s *HandlerTestSuite) TestAuthorize_Errors() {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{&service.OAuthError{Code: service.OAuthInvalidRequest, Description: "Unknown client"}, fiber.StatusBadRequest},
+		{service.ErrSessionRequired, fiber.StatusForbidden},
+		{errors.New("database error"), fiber.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		s.idpService.ExpectedCalls = nil
+		s.idpService.On("Authorize", mock.Anything, mock.Anything).Return(nil, tc.err)
+
+		resp, err := s.newIdentityProviderApp(&model.Principal{UserID: 1}).Test(httptest.NewRequest("GET", "/oauth/authorize?client_id=x", nil))
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), tc.status, resp.StatusCode, tc.err.Error())
+	}
+}
+
+// Test Token handler
+func (s *HandlerTestSuite) TestToken_BasicAuth() {
+	s.idpService.On("Token", mock.Anything, mock.AnythingOfType("*model.TokenRequest")).Return(&model.TokenResponse{
+		AccessToken: "access",
+		TokenType:   "Bearer",
+		ExpiresIn:   3600,
+		IDToken:     "id",
+		Scope:       "openid",
+	}, nil)
+
+	req := httptest.NewRequest("POST", "/oauth/token", strings.NewReader("grant_type=authorization_code&code=code-1&redirect_uri=https%3A%2F%2Fapp.example.com%2Fcallback"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	// client%201:secret%3A1, form encoded as RFC 6749 requires
+	req.SetBasicAuth("client%201", "secret%3A1")
+	resp, err := s.newIdentityProviderApp(nil).Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	assert.Equal(s.T(), "no-store", resp.Header.Get("Cache-Control"))
+
+	parsed := s.idpService.Calls[0].Arguments.Get(1).(*model.TokenRequest)
+	assert.Equal(s.T(), "client 1", parsed.ClientID)
+	assert.Equal(s.T(), "secret:1", parsed.ClientSecret)
+	assert.Equal(s.T(), "code-1", parsed.Code)
+	assert.Equal(s.T(), "https://app.example.com/callback", parsed.RedirectURI)
+
+	var result model.TokenResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), "id", result.IDToken)
+}
+
+func (s *HandlerTestSuite) TestToken_InvalidClient() {
+	s.idpService.On("Token", mock.Anything, mock.AnythingOfType("*model.TokenRequest")).
+		Return(nil, &service.OAuthError{Code: service.OAuthInvalidClient, Description: "Client authentication failed"})
+
+	req := httptest.NewRequest("POST", "/oauth/token", strings.NewReader("grant_type=authorization_code&code=code-1"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("client-1", "wrong")
+	resp, err := s.newIdentityProviderApp(nil).Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(s.T(), "Basic", resp.Header.Get("WWW-Authenticate"))
+
+	var result map[string]string
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), "invalid_client", result["error"])
+}
+
+func (s *HandlerTestSuite) TestToken_ConflictingClientID() {
+	req := httptest.NewRequest("POST", "/oauth/token", strings.NewReader("grant_type=authorization_code&client_id=client-2"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth("client-1", "secret-1")
+	resp, err := s.newIdentityProviderApp(nil).Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusUnauthorized, resp.StatusCode)
+	s.idpService.AssertNotCalled(s.T(), "Token", mock.Anything)
+}
+
+func (s *HandlerTestSuite) TestToken_InvalidGrant() {
+	s.idpService.On("Token", mock.Anything, mock.AnythingOfType("*model.TokenRequest")).
+		Return(nil, &service.OAuthError{Code: service.OAuthInvalidGrant, Description: "The code is invalid or expired"})
+
+	req := httptest.NewRequest("POST", "/oauth/token", strings.NewReader("grant_type=authorization_code&code=used&client_id=client-1&code_verifier=v"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := s.newIdentityProviderApp(nil).Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+
+	var result map[string]string
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), "invalid_grant", result["error"])
+	assert.Equal(s.T(), "The code is invalid or expired", result["error_description"])
+}
+
+// Test UserInfo handler
+func (s *HandlerTestSuite) TestUserInfo_Success() {
+	s.idpService.On("UserInfo", mock.Anything, "access-token").Return(&model.UserInfo{Subject: "1", Email: "test@example.com"}, nil)
+
+	req := httptest.NewRequest("GET", "/oauth/userinfo", nil)
+	req.Header.Set("Authorization", "Bearer access-token")
+	resp, err := s.newIdentityProviderApp(nil).Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result model.UserInfo
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), "1", result.Subject)
+	assert.Equal(s.T(), "test@example.com", result.Email)
+}
+
+func (s *HandlerTestSuite) TestUserInfo_InvalidToken() {
+	s.idpService.On("UserInfo", mock.Anything, "expired").
+		Return(nil, &service.OAuthError{Code: service.OAuthInvalidToken, Description: "The access token is invalid or expired"})
+
+	req := httptest.NewRequest("GET", "/oauth/userinfo", nil)
+	req.Header.Set("Authorization", "Bearer expired")
+	resp, err := s.newIdentityProviderApp(nil).Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusUnauthorized, resp.StatusCode)
+	assert.Equal(s.T(), `Bearer error="invalid_token"`, resp.Header.Get("WWW-Authenticate"))
+}
+
+func (s *HandlerTestSuite) TestUserInfo_MissingToken() {
+	resp, err := s.newIdentityProviderApp(nil).Test(httptest.NewRequest("GET", "/oauth/userinfo", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusUnauthorized, resp.StatusCode)
+	s.idpService.AssertNotCalled(s.T(), "UserInfo", mock.Anything)
+}
+
+// Test CreateClient handler
+func (s *HandlerTestSuite) TestCreateClient_Success() {
+	s.oauthClients.On("Create", mock.Anything, &model.Principal{UserID: 1, SessionID: "session-1"}, mock.AnythingOfType("*model.CreateOAuthClientRequest")).Return(&model.CreateOAuthClientResponse{
+		OAuthClientResponse: model.OAuthClientResponse{ID: 3, ClientID: "client-1", Name: "Dashboard"},
+		ClientSecret:        "secret-1",
+	}, nil)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"name":          "Dashboard",
+		"redirect_uris": []string{"https://app.example.com/callback"},
+		"scopes":        []string{"openid", "email"},
+	})
+	req := httptest.NewRequest("POST", "/oauth/clients", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.newIdentityProviderApp(&model.Principal{UserID: 1, SessionID: "session-1"}).Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusCreated, resp.StatusCode)
+
+	var result model.CreateOAuthClientResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), "client-1", result.ClientID)
+	assert.Equal(s.T(), "secret-1", result.ClientSecret)
+}
+
+func (s *HandlerTestSuite) TestCreateClient_ValidationError() {
+	cases := []map[string]interface{}{
+		{"name": "no redirect", "redirect_uris": []string{}, "scopes": []string{"openid"}},
+		{"name": "bad redirect", "redirect_uris": []string{"not a url"}, "scopes": []string{"openid"}},
+		{"name": "unknown scope", "redirect_uris": []string{"https://app.example.com/cb"}, "scopes": []string{"users:write"}},
+	}
+
+	for _, payload := range cases {
+		body, _ := json.Marshal(payload)
+		req := httptest.NewRequest("POST", "/oauth/clients", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.newIdentityProviderApp(&model.Principal{UserID: 1, SessionID: "session-1"}).Test(req)
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode, payload["name"])
+	}
+	s.oauthClients.AssertNotCalled(s.T(), "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *HandlerTestSuite) TestOAuthClients_NotAdmin() {
+	caller := &model.Principal{UserID: 2, SessionID: "session-2"}
+	s.oauthClients.On("Create", mock.Anything, caller, mock.AnythingOfType("*model.CreateOAuthClientRequest")).Return(nil, service.ErrOAuthClientForbidden)
+	s.oauthClients.On("List", mock.Anything, caller).Return(nil, service.ErrOAuthClientForbidden)
+	s.oauthClients.On("Revoke", mock.Anything, caller, uint(3)).Return(service.ErrOAuthClientForbidden)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"name":          "Dashboard",
+		"redirect_uris": []string{"https://app.example.com/callback"},
+		"scopes":        []string{"openid"},
+	})
+	createReq := httptest.NewRequest("POST", "/oauth/clients", bytes.NewReader(body))
+	createReq.Header.Set("Content-Type", "application/json")
+
+	for _, req := range []*http.Request{
+		createReq,
+		httptest.NewRequest("GET", "/oauth/clients", nil),
+		httptest.NewRequest("DELETE", "/oauth/clients/3", nil),
+	} {
+		resp, err := s.newIdentityProviderApp(caller).Test(req)
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), fiber.StatusForbidden, resp.StatusCode, req.Method)
+	}
+}
+
+// Test RevokeClient handler
+func (s *HandlerTestSuite) TestRevokeClient_NotFound() {
+	s.oauthClients.On("Revoke", mock.Anything, mock.Anything, uint(999)).Return(service.ErrOAuthClientNotFound)
+
+	resp, err := s.newIdentityProviderApp(&model.Principal{UserID: 1, SessionID: "session-1"}).Test(httptest.NewRequest("DELETE", "/oauth/clients/999", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
+
+// Test RevokeConsent handler
+func (s *HandlerTestSuite) TestRevokeConsent_Success() {
+	s.idpService.On("RevokeConsent", uint(1), "client-1").Return(nil)
+
+	resp, err := s.newIdentityProviderApp(&model.Principal{UserID: 1, SessionID: "session-1"}).Test(httptest.NewRequest("DELETE", "/users/1/consents/client-1", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNoContent, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestRevokeConsent_NotFound() {
+	s.idpService.On("RevokeConsent", uint(1), "client-1").Return(service.ErrConsentNotFound)
+
+	resp, err := s.newIdentityProviderApp(&model.Principal{UserID: 1, SessionID: "session-1"}).Test(httptest.NewRequest("DELETE", "/users/1/consents/client-1", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
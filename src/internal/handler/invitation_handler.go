@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=InvitationHandler --output=./mocks/handler --outpkg=handler --filename=invitation_handler.go --structname=MockInvitationHandler --with-expecter=false
+type InvitationHandler interface {
+	CreateInvitation(c *fiber.Ctx) error
+	ListInvitations(c *fiber.Ctx) error
+	ResendInvitation(c *fiber.Ctx) error
+	RevokeInvitation(c *fiber.Ctx) error
+	AcceptInvitation(c *fiber.Ctx) error
+}
+
+type invitationHandlerImpl struct {
+	invitationService service.InvitationService
+	validator         *validator.Validate
+}
+
+func NewInvitationHandler(invitationService service.InvitationService) InvitationHandler {
+	return &invitationHandlerImpl{
+		invitationService: invitationService,
+		validator:         validator.New(),
+	}
+}
+
+// CreateInvitation invites someone to create an account
+// @Summary Create invitation
+// @Description Email a link with which the owner of the address can create an account with the given role (default user). Needs the invitations:manage permission; only owners and admins of the organization can invite admins. Invitations expire after invitation.token_ttl.
+// @Tags invitations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param invitation body model.CreateInvitationRequest true "Invitation"
+// @Success 201 {object} model.InvitationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /invitations [post]
+func (h *invitationHandlerImpl) CreateInvitation(c *fiber.Ctx) error {
+	var req model.CreateInvitationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	invitation, err := h.invitationService.CreateInvitation(c.UserContext(), middleware.PrincipalFromContext(c), &req)
+	switch {
+	case errors.Is(err, service.ErrInvitationRoleNotAllowed):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrEmailTaken), errors.Is(err, service.ErrInvitationPending):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create invitation",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(invitation)
+}
+
+// ListInvitations lists invitations
+// @Summary List invitations
+// @Description List invitations newest first, with their status: pending, accepted, revoked or expired.
+// @Tags invitations
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Router /invitations [get]
+func (h *invitationHandlerImpl) ListInvitations(c *fiber.Ctx) error {
+	limit := 10 // default
+	offset := 0 // default
+
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	if o, err := strconv.Atoi(c.Query("offset")); err == nil && o >= 0 {
+		offset = o
+	}
+
+	invitations, err := h.invitationService.ListInvitations(c.UserContext(), limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch invitations",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"invitations": invitations,
+		"limit":       limit,
+		"offset":      offset,
+	})
+}
+
+// ResendInvitation mails a new invitation link
+// @Summary Resend invitation
+// @Description Email a new link for a pending or expired invitation. Earlier links stop working and the expiry starts over.
+// @Tags invitations
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Invitation ID"
+// @Success 200 {object} model.InvitationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /invitations/{id}/resend [post]
+func (h *invitationHandlerImpl) ResendInvitation(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid invitation ID",
+		})
+	}
+
+	invitation, err := h.invitationService.ResendInvitation(c.UserContext(), uint(id))
+	if err != nil {
+		return h.invitationError(c, err, "Failed to resend invitation")
+	}
+
+	return c.JSON(invitation)
+}
+
+// RevokeInvitation revokes an invitation
+// @Summary Revoke invitation
+// @Description Revoke a pending or expired invitation so that its link can no longer be used.
+// @Tags invitations
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Invitation ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /invitations/{id} [delete]
+func (h *invitationHandlerImpl) RevokeInvitation(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid invitation ID",
+		})
+	}
+
+	if err := h.invitationService.RevokeInvitation(c.UserContext(), uint(id)); err != nil {
+		return h.invitationError(c, err, "Failed to revoke invitation")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// AcceptInvitation creates the invited account
+// @Summary Accept invitation
+// @Description Create an account from the token of an invitation link, with a username and password of the invitee's choosing. The email address and role come from the invitation, and the address counts as verified.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body model.AcceptInvitationRequest true "Token and account details"
+// @Success 201 {object} model.UserResponse
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/invitations/accept [post]
+func (h *invitationHandlerImpl) AcceptInvitation(c *fiber.Ctx) error {
+	var req model.AcceptInvitationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	user, err := h.invitationService.AcceptInvitation(c.UserContext(), &req)
+	var policyErr *service.PasswordPolicyError
+	switch {
+	case errors.As(err, &policyErr):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":      policyErr.Error(),
+			"violations": policyErr.Violations,
+		})
+	case errors.Is(err, service.ErrInvalidInvitation):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrUsernameTaken), errors.Is(err, service.ErrEmailTaken):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to accept invitation",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(user)
+}
+
+func (h *invitationHandlerImpl) invitationError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, service.ErrInvitationNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrInvitationClosed):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": message,
+		})
+	}
+}
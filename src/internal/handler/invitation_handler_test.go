@@ -0,0 +1,206 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+)
+
+// newInvitationApp serves the invitation routes as the given caller.
+func (s *HandlerTestSuite) newInvitationApp(caller *model.Principal) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("principal", caller)
+		return c.Next()
+	})
+	app.Post("/invitations", s.inviteHandler.CreateInvitation)
+	app.Get("/invitations", s.inviteHandler.ListInvitations)
+	app.Post("/invitations/:id/resend", s.inviteHandler.ResendInvitation)
+	app.Delete("/invitations/:id", s.inviteHandler.RevokeInvitation)
+	app.Post("/auth/invitations/accept", s.inviteHandler.AcceptInvitation)
+	return app
+}
+
+// Test CreateInvitation handler
+func (s *HandlerTestSuite) TestCreateInvitation_Success() {
+	caller := &model.Principal{UserID: 1, SessionID: "session-1"}
+	req := &model.CreateInvitationRequest{Email: "new@example.com", Role: model.RoleAdmin}
+	s.invitations.On("CreateInvitation", mock.Anything, caller, req).Return(&model.InvitationResponse{
+		ID:     3,
+		Email:  "new@example.com",
+		Role:   model.RoleAdmin,
+		Status: model.InvitationStatusPending,
+	}, nil)
+
+	body, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest("POST", "/invitations", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.newInvitationApp(caller).Test(httpReq)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusCreated, resp.StatusCode)
+
+	var result model.InvitationResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), model.InvitationStatusPending, result.Status)
+}
+
+func (s *HandlerTestSuite) TestCreateInvitation_InvalidRole() {
+	body, _ := json.Marshal(map[string]string{"email": "new@example.com", "role": "owner"})
+	req := httptest.NewRequest("POST", "/invitations", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.newInvitationApp(&model.Principal{UserID: 1}).Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+	s.invitations.AssertNotCalled(s.T(), "CreateInvitation", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *HandlerTestSuite) TestCreateInvitation_Errors() {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{service.ErrInvitationRoleNotAllowed, fiber.StatusForbidden},
+		{service.ErrEmailTaken, fiber.StatusConflict},
+		{service.ErrInvitationPending, fiber.StatusConflict},
+		{errors.New("database error"), fiber.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		s.invitations.ExpectedCalls = nil
+		s.invitations.On("CreateInvitation", mock.Anything, mock.Anything, mock.Anything).Return(nil, tc.err)
+
+		body, _ := json.Marshal(map[string]string{"email": "new@example.com"})
+		req := httptest.NewRequest("POST", "/invitations", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.newInvitationApp(&model.Principal{UserID: 1}).Test(req)
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), tc.status, resp.StatusCode, tc.err.Error())
+	}
+}
+
+// Test ListInvitations handler
+func (s *HandlerTestSuite) TestListInvitations_Success() {
+	s.invitations.On("ListInvitations", mock.Anything, 20, 40).Return([]*model.InvitationResponse{{ID: 3}}, nil)
+
+	resp, err := s.newInvitationApp(&model.Principal{UserID: 1}).Test(httptest.NewRequest("GET", "/invitations?limit=20&offset=40", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Invitations []model.InvitationResponse `json:"invitations"`
+		Limit       int                        `json:"limit"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Len(s.T(), result.Invitations, 1)
+	assert.Equal(s.T(), 20, result.Limit)
+}
+
+// Test ResendInvitation handler
+func (s *HandlerTestSuite) TestResendInvitation_Success() {
+	s.invitations.On("ResendInvitation", mock.Anything, uint(3)).Return(&model.InvitationResponse{ID: 3, Status: model.InvitationStatusPending}, nil)
+
+	resp, err := s.newInvitationApp(&model.Principal{UserID: 1}).Test(httptest.NewRequest("POST", "/invitations/3/resend", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestResendInvitation_Errors() {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{service.ErrInvitationNotFound, fiber.StatusNotFound},
+		{service.ErrInvitationClosed, fiber.StatusConflict},
+		{errors.New("smtp down"), fiber.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		s.invitations.ExpectedCalls = nil
+		s.invitations.On("ResendInvitation", mock.Anything, uint(3)).Return(nil, tc.err)
+
+		resp, err := s.newInvitationApp(&model.Principal{UserID: 1}).Test(httptest.NewRequest("POST", "/invitations/3/resend", nil))
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), tc.status, resp.StatusCode, tc.err.Error())
+	}
+}
+
+// Test RevokeInvitation handler
+func (s *HandlerTestSuite) TestRevokeInvitation_Success() {
+	s.invitations.On("RevokeInvitation", mock.Anything, uint(3)).Return(nil)
+
+	resp, err := s.newInvitationApp(&model.Principal{UserID: 1}).Test(httptest.NewRequest("DELETE", "/invitations/3", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNoContent, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestRevokeInvitation_NotFound() {
+	s.invitations.On("RevokeInvitation", mock.Anything, uint(9)).Return(service.ErrInvitationNotFound)
+
+	resp, err := s.newInvitationApp(&model.Principal{UserID: 1}).Test(httptest.NewRequest("DELETE", "/invitations/9", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
+
+// Test AcceptInvitation handler
+func (s *HandlerTestSuite) TestAcceptInvitation_Success() {
+	req := &model.AcceptInvitationRequest{Token: "invite-token", Username: "newuser", Password: "Sup3rSecret!"}
+	s.invitations.On("AcceptInvitation", mock.Anything, req).Return(&model.UserResponse{ID: 2, Username: "newuser"}, nil)
+
+	body, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest("POST", "/auth/invitations/accept", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.newInvitationApp(nil).Test(httpReq)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusCreated, resp.StatusCode)
+
+	var result model.UserResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), "newuser", result.Username)
+}
+
+func (s *HandlerTestSuite) TestAcceptInvitation_Errors() {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{service.ErrInvalidInvitation, fiber.StatusBadRequest},
+		{&service.PasswordPolicyError{Violations: []string{"must be at least 8 characters"}}, fiber.StatusBadRequest},
+		{service.ErrUsernameTaken, fiber.StatusConflict},
+		{service.ErrEmailTaken, fiber.StatusConflict},
+		{errors.New("database error"), fiber.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		s.invitations.ExpectedCalls = nil
+		s.invitations.On("AcceptInvitation", mock.Anything, mock.Anything).Return(nil, tc.err)
+
+		body, _ := json.Marshal(map[string]string{"token": "invite-token", "username": "newuser", "password": "secret123"})
+		req := httptest.NewRequest("POST", "/auth/invitations/accept", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.newInvitationApp(nil).Test(req)
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), tc.status, resp.StatusCode, tc.err.Error())
+	}
+}
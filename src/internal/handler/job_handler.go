@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=JobHandler --output=./mocks/handler --outpkg=handler --filename=job_handler.go --structname=MockJobHandler --with-expecter=false
+type JobHandler interface {
+	ListJobs(c *fiber.Ctx) error
+	GetJob(c *fiber.Ctx) error
+	RetryJob(c *fiber.Ctx) error
+	CancelJob(c *fiber.Ctx) error
+}
+
+type jobHandlerImpl struct {
+	jobService service.JobService
+}
+
+func NewJobHandler(jobService service.JobService) JobHandler {
+	return &jobHandlerImpl{
+		jobService: jobService,
+	}
+}
+
+// ListJobs lists background jobs
+// @Summary List jobs
+// @Description List background jobs newest first, optionally by status and type. Users see the jobs enqueued in the organization of the request; service API keys see all jobs, including those enqueued outside any organization. Needs the jobs:manage permission.
+// @Tags jobs
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param status query string false "pending, running, succeeded, failed or cancelled"
+// @Param type query string false "Job type, e.g. email.send"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /jobs [get]
+func (h *jobHandlerImpl) ListJobs(c *fiber.Ctx) error {
+	filter := &model.JobFilter{Status: c.Query("status"), Type: c.Query("type")}
+	switch filter.Status {
+	case "", model.JobPending, model.JobRunning, model.JobSucceeded, model.JobFailed, model.JobCancelled:
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Status must be pending, running, succeeded, failed or cancelled",
+		})
+	}
+
+	limit := 10 // default
+	offset := 0 // default
+
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	if o, err := strconv.Atoi(c.Query("offset")); err == nil && o >= 0 {
+		offset = o
+	}
+
+	jobs, err := h.jobService.ListJobs(jobContext(c), filter, limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch jobs",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"jobs":   jobs,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetJob gets a background job
+// @Summary Get job
+// @Description Get a background job with its payload and the error of its last attempt. Needs the jobs:manage permission.
+// @Tags jobs
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Job ID"
+// @Success 200 {object} model.JobResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /jobs/{id} [get]
+func (h *jobHandlerImpl) GetJob(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid job ID",
+		})
+	}
+
+	job, err := h.jobService.GetJob(jobContext(c), uint(id))
+	if err != nil {
+		return h.jobError(c, err, "Failed to fetch job")
+	}
+
+	return c.JSON(job)
+}
+
+// RetryJob runs a failed or cancelled job again
+// @Summary Retry job
+// @Description Queue a failed or cancelled job to run again now, with a fresh set of attempts. Gets 409 for jobs in other states, or while another unfinished job holds its unique key. Needs the jobs:manage permission.
+// @Tags jobs
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Job ID"
+// @Success 200 {object} model.JobResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /jobs/{id}/retry [post]
+func (h *jobHandlerImpl) RetryJob(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid job ID",
+		})
+	}
+
+	job, err := h.jobService.RetryJob(jobContext(c), uint(id))
+	if err != nil {
+		return h.jobError(c, err, "Failed to retry job")
+	}
+
+	return c.JSON(job)
+}
+
+// CancelJob cancels a pending job
+// @Summary Cancel job
+// @Description Stop a pending job from running, including one waiting to be retried. Running jobs cannot be cancelled and get 409. Needs the jobs:manage permission.
+// @Tags jobs
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Job ID"
+// @Success 200 {object} model.JobResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /jobs/{id}/cancel [post]
+func (h *jobHandlerImpl) CancelJob(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid job ID",
+		})
+	}
+
+	job, err := h.jobService.CancelJob(jobContext(c), uint(id))
+	if err != nil {
+		return h.jobError(c, err, "Failed to cancel job")
+	}
+
+	return c.JSON(job)
+}
+
+func (h *jobHandlerImpl) jobError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, service.ErrJobNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrJobNotRetryable),
+		errors.Is(err, service.ErrJobNotCancellable),
+		errors.Is(err, service.ErrJobUniqueKeyTaken):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": message,
+		})
+	}
+}
+
+// jobContext returns the context to look up jobs in. Users see the jobs of
+// the organization of the request; service API keys see all jobs, as many
+// are enqueued outside any organization.
+func jobContext(c *fiber.Ctx) context.Context {
+	if principal := middleware.PrincipalFromContext(c); principal != nil && principal.UserID == 0 {
+		return tenant.WithOrganization(c.UserContext(), 0)
+	}
+	return c.UserContext()
+}
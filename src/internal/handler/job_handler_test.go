@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+)
+
+// newJobApp serves the job routes as the given caller in organization 1.
+func (s *HandlerTestSuite) newJobApp(caller *model.Principal) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(tenant.WithOrganization(c.UserContext(), 1))
+		c.Locals("principal", caller)
+		return c.Next()
+	})
+	app.Get("/jobs", s.jobHandler.ListJobs)
+	app.Get("/jobs/:id", s.jobHandler.GetJob)
+	app.Post("/jobs/:id/retry", s.jobHandler.RetryJob)
+	app.Post("/jobs/:id/cancel", s.jobHandler.CancelJob)
+	return app
+}
+
+// inOrganization matches contexts scoped to the organization, or to none
+// for 0.
+func inOrganization(organizationID uint) interface{} {
+	return mock.MatchedBy(func(ctx context.Context) bool {
+		id, _ := tenant.OrganizationFromContext(ctx)
+		return id == organizationID
+	})
+}
+
+// Test ListJobs handler
+func (s *HandlerTestSuite) TestListJobs_User() {
+	filter := &model.JobFilter{Status: model.JobFailed, Type: "email.send"}
+	s.jobs.On("ListJobs", inOrganization(1), filter, 20, 0).
+		Return([]*model.JobResponse{{ID: 7, Type: "email.send", Status: model.JobFailed, Payload: json.RawMessage(`{}`)}}, nil)
+
+	resp, err := s.newJobApp(&model.Principal{UserID: 1}).Test(httptest.NewRequest("GET", "/jobs?status=failed&type=email.send&limit=20", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Jobs []model.JobResponse `json:"jobs"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Len(s.T(), result.Jobs, 1)
+}
+
+func (s *HandlerTestSuite) TestListJobs_ServiceKeySeesAllJobs() {
+	s.jobs.On("ListJobs", inOrganization(0), &model.JobFilter{}, 10, 0).Return([]*model.JobResponse{}, nil)
+
+	resp, err := s.newJobApp(&model.Principal{APIKeyID: 3, Service: "ops"}).Test(httptest.NewRequest("GET", "/jobs", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestListJobs_InvalidStatus() {
+	resp, err := s.newJobApp(&model.Principal{UserID: 1}).Test(httptest.NewRequest("GET", "/jobs?status=stuck", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// Test GetJob handler
+func (s *HandlerTestSuite) TestGetJob_NotFound() {
+	s.jobs.On("GetJob", mock.Anything, uint(7)).Return(nil, service.ErrJobNotFound)
+
+	resp, err := s.newJobApp(&model.Principal{UserID: 1}).Test(httptest.NewRequest("GET", "/jobs/7", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
+
+// Test RetryJob handler
+func (s *HandlerTestSuite) TestRetryJob_Success() {
+	s.jobs.On("RetryJob", mock.Anything, uint(7)).Return(&model.JobResponse{ID: 7, Status: model.JobPending}, nil)
+
+	resp, err := s.newJobApp(&model.Principal{UserID: 1}).Test(httptest.NewRequest("POST", "/jobs/7/retry", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestRetryJob_Conflict() {
+	s.jobs.On("RetryJob", mock.Anything, uint(7)).Return(nil, service.ErrJobNotRetryable)
+
+	resp, err := s.newJobApp(&model.Principal{UserID: 1}).Test(httptest.NewRequest("POST", "/jobs/7/retry", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusConflict, resp.StatusCode)
+}
+
+// Test CancelJob handler
+func (s *HandlerTestSuite) TestCancelJob_Running() {
+	s.jobs.On("CancelJob", mock.Anything, uint(7)).Return(nil, service.ErrJobNotCancellable)
+
+	resp, err := s.newJobApp(&model.Principal{UserID: 1}).Test(httptest.NewRequest("POST", "/jobs/7/cancel", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusConflict, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestCancelJob_InvalidID() {
+	resp, err := s.newJobApp(&model.Principal{UserID: 1}).Test(httptest.NewRequest("POST", "/jobs/abc/cancel", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=LockoutHandler --output=./mocks/handler --outpkg=handler --filename=lockout_handler.go --structname=MockLockoutHandler --with-expecter=false
+type LockoutHandler interface {
+	Unlock(c *fiber.Ctx) error
+	ListLoginAttempts(c *fiber.Ctx) error
+}
+
+type lockoutHandlerImpl struct {
+	lockoutService service.LockoutService
+}
+
+func NewLockoutHandler(lockoutService service.LockoutService) LockoutHandler {
+	return &lockoutHandlerImpl{
+		lockoutService: lockoutService,
+	}
+}
+
+// Unlock lifts a login lockout
+// @Summary Unlock user
+// @Description Lift a lockout after too many failed logins and clear the failed attempts of the user. Needs the users:manage permission.
+// @Tags users
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/{id}/unlock [post]
+func (h *lockoutHandlerImpl) Unlock(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	if err := h.lockoutService.Unlock(c.UserContext(), uint(id)); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListLoginAttempts lists the login history of a user
+// @Summary List login attempts
+// @Description List successful and failed logins of a user, newest first. Needs the users:manage permission.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/{id}/login-attempts [get]
+func (h *lockoutHandlerImpl) ListLoginAttempts(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	limitStr := c.Query("limit")
+	offsetStr := c.Query("offset")
+
+	limit := 20 // default
+	offset := 0 // default
+
+	if limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+
+	if offsetStr != "" {
+		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
+			offset = o
+		}
+	}
+
+	attempts, err := h.lockoutService.ListLoginAttempts(c.UserContext(), uint(id), limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"login_attempts": attempts,
+		"limit":          limit,
+		"offset":         offset,
+	})
+}
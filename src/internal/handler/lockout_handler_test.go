@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// Test Unlock handler
+func (s *HandlerTestSuite) TestUnlock_Success() {
+	s.lockoutService.On("Unlock", mock.Anything, uint(1)).Return(nil)
+
+	app := fiber.New()
+	app.Post("/users/:id/unlock", s.lockoutHandler.Unlock)
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/users/1/unlock", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNoContent, resp.StatusCode)
+	s.lockoutService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestUnlock_InvalidID() {
+	app := fiber.New()
+	app.Post("/users/:id/unlock", s.lockoutHandler.Unlock)
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/users/abc/unlock", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestUnlock_UserNotFound() {
+	s.lockoutService.On("Unlock", mock.Anything, uint(999)).Return(errors.New("not found"))
+
+	app := fiber.New()
+	app.Post("/users/:id/unlock", s.lockoutHandler.Unlock)
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/users/999/unlock", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
+
+// Test ListLoginAttempts handler
+func (s *HandlerTestSuite) TestListLoginAttempts_Success() {
+	s.lockoutService.On("ListLoginAttempts", mock.Anything, uint(1), 5, 10).Return([]*model.LoginAttemptResponse{
+		{ID: 2, IP: "192.0.2.1", Success: true},
+		{ID: 1, IP: "192.0.2.1", Reason: "invalid_credentials"},
+	}, nil)
+
+	app := fiber.New()
+	app.Get("/users/:id/login-attempts", s.lockoutHandler.ListLoginAttempts)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/1/login-attempts?limit=5&offset=10", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result struct {
+		LoginAttempts []model.LoginAttemptResponse `json:"login_attempts"`
+		Limit         int                          `json:"limit"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Len(s.T(), result.LoginAttempts, 2)
+	assert.Equal(s.T(), 5, result.Limit)
+}
+
+func (s *HandlerTestSuite) TestListLoginAttempts_UserNotFound() {
+	s.lockoutService.On("ListLoginAttempts", mock.Anything, uint(999), 20, 0).Return(nil, errors.New("not found"))
+
+	app := fiber.New()
+	app.Get("/users/:id/login-attempts", s.lockoutHandler.ListLoginAttempts)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/999/login-attempts", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
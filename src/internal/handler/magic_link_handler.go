@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=MagicLinkHandler --output=./mocks/handler --outpkg=handler --filename=magic_link_handler.go --structname=MockMagicLinkHandler --with-expecter=false
+type MagicLinkHandler interface {
+	SendLink(c *fiber.Ctx) error
+	Redeem(c *fiber.Ctx) error
+}
+
+type magicLinkHandlerImpl struct {
+	magicLinkService service.MagicLinkService
+	validator        *validator.Validate
+}
+
+func NewMagicLinkHandler(magicLinkService service.MagicLinkService) MagicLinkHandler {
+	return &magicLinkHandlerImpl{
+		magicLinkService: magicLinkService,
+		validator:        validator.New(),
+	}
+}
+
+// SendLink emails a login link
+// @Summary Request a login link
+// @Description Email a single-use, short-lived login link. If self-registration is enabled, an address without an account gets one too and the account is created when the link is used. The response is the same whether or not a link was sent.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body model.MagicLinkRequest true "Email address"
+// @Success 202
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/magic-link [post]
+func (h *magicLinkHandlerImpl) SendLink(c *fiber.Ctx) error {
+	var req model.MagicLinkRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if err := h.magicLinkService.SendLink(c.UserContext(), &req); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to send login link",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusAccepted)
+}
+
+// Redeem logs in with a login link
+// @Summary Log in with a login link
+// @Description Redeem the token of a login link and start a new session, like /auth/login. The page the link opens should only post the token once the user acts, so that mail scanners fetching the link do not use it up.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body model.RedeemMagicLinkRequest true "Login link token"
+// @Success 200 {object} model.LoginResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/magic-link/verify [post]
+func (h *magicLinkHandlerImpl) Redeem(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	var req model.RedeemMagicLinkRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	result, err := h.magicLinkService.Redeem(c.UserContext(), &req, clientInfo(c))
+	switch {
+	case errors.Is(err, service.ErrInvalidMagicLink):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrMFAEnrollmentRequired):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to log in",
+		})
+	}
+
+	return c.JSON(result)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+)
+
+func (s *HandlerTestSuite) newMagicLinkApp() *fiber.App {
+	app := fiber.New()
+	app.Post("/auth/magic-link", s.magicHandler.SendLink)
+	app.Post("/auth/magic-link/verify", s.magicHandler.Redeem)
+	return app
+}
+
+// Test SendLink handler
+func (s *HandlerTestSuite) TestSendMagicLink_Accepted() {
+	s.magicLinks.On("SendLink", mock.Anything, &model.MagicLinkRequest{Email: "test@example.com"}).Return(nil)
+
+	body, _ := json.Marshal(map[string]string{"email": "test@example.com"})
+	req := httptest.NewRequest("POST", "/auth/magic-link", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.newMagicLinkApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusAccepted, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestSendMagicLink_InvalidEmail() {
+	body, _ := json.Marshal(map[string]string{"email": "not-an-email"})
+	req := httptest.NewRequest("POST", "/auth/magic-link", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.newMagicLinkApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+	s.magicLinks.AssertNotCalled(s.T(), "SendLink", mock.Anything)
+}
+
+// Test Redeem handler
+func (s *HandlerTestSuite) TestRedeemMagicLink_Success() {
+	s.magicLinks.On("Redeem", mock.Anything, &model.RedeemMagicLinkRequest{Token: "magic-token"}, mock.AnythingOfType("*model.ClientInfo")).
+		Return(&model.LoginResponse{AccessToken: "access", TokenType: "Bearer"}, nil)
+
+	body, _ := json.Marshal(map[string]string{"token": "magic-token"})
+	req := httptest.NewRequest("POST", "/auth/magic-link/verify", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.newMagicLinkApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result model.LoginResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), "access", result.AccessToken)
+}
+
+func (s *HandlerTestSuite) TestRedeemMagicLink_Errors() {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{service.ErrInvalidMagicLink, fiber.StatusUnauthorized},
+		{service.ErrMFAEnrollmentRequired, fiber.StatusForbidden},
+		{errors.New("database error"), fiber.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		s.magicLinks.ExpectedCalls = nil
+		s.magicLinks.On("Redeem", mock.Anything, mock.Anything, mock.Anything).Return(nil, tc.err)
+
+		body, _ := json.Marshal(map[string]string{"token": "magic-token"})
+		req := httptest.NewRequest("POST", "/auth/magic-link/verify", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.newMagicLinkApp().Test(req)
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), tc.status, resp.StatusCode, tc.err.Error())
+	}
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=MFAHandler --output=./mocks/handler --outpkg=handler --filename=mfa_handler.go --structname=MockMFAHandler --with-expecter=false
+type MFAHandler interface {
+	Enroll(c *fiber.Ctx) error
+	Confirm(c *fiber.Ctx) error
+	Disable(c *fiber.Ctx) error
+	RegenerateRecoveryCodes(c *fiber.Ctx) error
+}
+
+type mfaHandlerImpl struct {
+	mfaService service.MFAService
+	validator  *validator.Validate
+}
+
+func NewMFAHandler(mfaService service.MFAService) MFAHandler {
+	return &mfaHandlerImpl{
+		mfaService: mfaService,
+		validator:  validator.New(),
+	}
+}
+
+// Enroll starts MFA enrollment
+// @Summary Enroll in MFA
+// @Description Generate a new TOTP secret and otpauth URI for an authenticator app. MFA is only enabled after the first code is confirmed.
+// @Tags mfa
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} model.MFAEnrollResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /users/{id}/mfa/enroll [post]
+func (h *mfaHandlerImpl) Enroll(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	resp, err := h.mfaService.Enroll(c.UserContext(), uint(id))
+	if err != nil {
+		return mfaError(c, err)
+	}
+
+	return c.JSON(resp)
+}
+
+// Confirm confirms MFA enrollment
+// @Summary Confirm MFA enrollment
+// @Description Enable MFA with a first code from the authenticator app. Returns one-time recovery codes, which are not shown again.
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Param request body model.MFACodeRequest true "TOTP code"
+// @Success 200 {object} model.MFARecoveryCodesResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /users/{id}/mfa/confirm [post]
+func (h *mfaHandlerImpl) Confirm(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	id, req, message := h.parseCodeRequest(c)
+	if message != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": message,
+		})
+	}
+
+	resp, err := h.mfaService.Confirm(id, req)
+	if err != nil {
+		return mfaError(c, err)
+	}
+
+	return c.JSON(resp)
+}
+
+// Disable turns MFA off
+// @Summary Disable MFA
+// @Description Remove the TOTP secret and recovery codes. Requires a current TOTP or recovery code.
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Param request body model.MFACodeRequest true "TOTP or recovery code"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /users/{id}/mfa [delete]
+func (h *mfaHandlerImpl) Disable(c *fiber.Ctx) error {
+	id, req, message := h.parseCodeRequest(c)
+	if message != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": message,
+		})
+	}
+
+	if err := h.mfaService.Disable(id, req); err != nil {
+		return mfaError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes
+// @Summary Regenerate MFA recovery codes
+// @Description Replace all recovery codes with a new set. Requires a current TOTP or recovery code.
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Param request body model.MFACodeRequest true "TOTP or recovery code"
+// @Success 200 {object} model.MFARecoveryCodesResponse
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Router /users/{id}/mfa/recovery-codes [post]
+func (h *mfaHandlerImpl) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	id, req, message := h.parseCodeRequest(c)
+	if message != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": message,
+		})
+	}
+
+	resp, err := h.mfaService.RegenerateRecoveryCodes(id, req)
+	if err != nil {
+		return mfaError(c, err)
+	}
+
+	return c.JSON(resp)
+}
+
+// parseCodeRequest reads the user ID and code. A non-empty message
+// describes why the request is invalid.
+func (h *mfaHandlerImpl) parseCodeRequest(c *fiber.Ctx) (uint, *model.MFACodeRequest, string) {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return 0, nil, "Invalid user ID"
+	}
+
+	var req model.MFACodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return 0, nil, "Invalid request body"
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return 0, nil, err.Error()
+	}
+
+	return uint(id), &req, ""
+}
+
+// mfaError maps MFA service errors to responses. Anything else is treated
+// as an unknown user.
+func mfaError(c *fiber.Ctx, err error) error {
+	status := fiber.StatusNotFound
+	message := "User not found"
+
+	switch {
+	case errors.Is(err, service.ErrInvalidMFACode):
+		status, message = fiber.StatusBadRequest, err.Error()
+	case errors.Is(err, service.ErrMFAAlreadyEnabled), errors.Is(err, service.ErrMFANotEnabled):
+		status, message = fiber.StatusConflict, err.Error()
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"error": message,
+	})
+}
@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+)
+
+// Test Enroll handler
+func (s *HandlerTestSuite) TestMFAEnroll_Success() {
+	s.mfaService.On("Enroll", mock.Anything, uint(1)).Return(&model.MFAEnrollResponse{
+		Secret:     "SECRET",
+		OTPAuthURI: "otpauth://totp/test:test@example.com?secret=SECRET",
+	}, nil)
+
+	app := fiber.New()
+	app.Post("/users/:id/mfa/enroll", s.mfaHandler.Enroll)
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/users/1/mfa/enroll", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result model.MFAEnrollResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), "SECRET", result.Secret)
+	s.mfaService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestMFAEnroll_AlreadyEnabled() {
+	s.mfaService.On("Enroll", mock.Anything, uint(1)).Return(nil, service.ErrMFAAlreadyEnabled)
+
+	app := fiber.New()
+	app.Post("/users/:id/mfa/enroll", s.mfaHandler.Enroll)
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/users/1/mfa/enroll", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusConflict, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestMFAEnroll_UserNotFound() {
+	s.mfaService.On("Enroll", mock.Anything, uint(999)).Return(nil, errors.New("not found"))
+
+	app := fiber.New()
+	app.Post("/users/:id/mfa/enroll", s.mfaHandler.Enroll)
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/users/999/mfa/enroll", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
+
+// Test Confirm handler
+func (s *HandlerTestSuite) TestMFAConfirm_Success() {
+	s.mfaService.On("Confirm", uint(1), &model.MFACodeRequest{Code: "123456"}).Return(&model.MFARecoveryCodesResponse{
+		RecoveryCodes: []string{"abcde-fghij"},
+	}, nil)
+
+	app := fiber.New()
+	app.Post("/users/:id/mfa/confirm", s.mfaHandler.Confirm)
+
+	req := httptest.NewRequest("POST", "/users/1/mfa/confirm", bytes.NewBuffer([]byte(`{"code":"123456"}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result model.MFARecoveryCodesResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), []string{"abcde-fghij"}, result.RecoveryCodes)
+}
+
+func (s *HandlerTestSuite) TestMFAConfirm_InvalidCode() {
+	s.mfaService.On("Confirm", uint(1), &model.MFACodeRequest{Code: "000000"}).Return(nil, service.ErrInvalidMFACode)
+
+	app := fiber.New()
+	app.Post("/users/:id/mfa/confirm", s.mfaHandler.Confirm)
+
+	req := httptest.NewRequest("POST", "/users/1/mfa/confirm", bytes.NewBuffer([]byte(`{"code":"000000"}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestMFAConfirm_MissingCode() {
+	app := fiber.New()
+	app.Post("/users/:id/mfa/confirm", s.mfaHandler.Confirm)
+
+	req := httptest.NewRequest("POST", "/users/1/mfa/confirm", bytes.NewBuffer([]byte(`{}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+	s.mfaService.AssertNotCalled(s.T(), "Confirm", mock.Anything, mock.Anything)
+}
+
+func (s *HandlerTestSuite) TestMFAConfirm_InvalidID() {
+	app := fiber.New()
+	app.Post("/users/:id/mfa/confirm", s.mfaHandler.Confirm)
+
+	req := httptest.NewRequest("POST", "/users/invalid/mfa/confirm", bytes.NewBuffer([]byte(`{"code":"123456"}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// Test Disable handler
+func (s *HandlerTestSuite) TestMFADisable_Success() {
+	s.mfaService.On("Disable", uint(1), &model.MFACodeRequest{Code: "123456"}).Return(nil)
+
+	app := fiber.New()
+	app.Delete("/users/:id/mfa", s.mfaHandler.Disable)
+
+	req := httptest.NewRequest("DELETE", "/users/1/mfa", bytes.NewBuffer([]byte(`{"code":"123456"}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNoContent, resp.StatusCode)
+	s.mfaService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestMFADisable_NotEnabled() {
+	s.mfaService.On("Disable", uint(1), &model.MFACodeRequest{Code: "123456"}).Return(service.ErrMFANotEnabled)
+
+	app := fiber.New()
+	app.Delete("/users/:id/mfa", s.mfaHandler.Disable)
+
+	req := httptest.NewRequest("DELETE", "/users/1/mfa", bytes.NewBuffer([]byte(`{"code":"123456"}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusConflict, resp.StatusCode)
+}
+
+// Test RegenerateRecoveryCodes handler
+func (s *HandlerTestSuite) TestMFARegenerateRecoveryCodes_Success() {
+	s.mfaService.On("RegenerateRecoveryCodes", uint(1), &model.MFACodeRequest{Code: "123456"}).Return(&model.MFARecoveryCodesResponse{
+		RecoveryCodes: []string{"abcde-fghij", "klmno-pqrst"},
+	}, nil)
+
+	app := fiber.New()
+	app.Post("/users/:id/mfa/recovery-codes", s.mfaHandler.RegenerateRecoveryCodes)
+
+	req := httptest.NewRequest("POST", "/users/1/mfa/recovery-codes", bytes.NewBuffer([]byte(`{"code":"123456"}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	s.mfaService.AssertExpectations(s.T())
+}
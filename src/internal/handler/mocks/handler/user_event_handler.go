@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package handler
+
+import (
+	fiber "github.com/gofiber/fiber/v2"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockUserEventHandler is an autogenerated mock type for the UserEventHandler type
+type MockUserEventHandler struct {
+	mock.Mock
+}
+
+// StreamUserEvents provides a mock function with given fields: c
+func (_m *MockUserEventHandler) StreamUserEvents(c *fiber.Ctx) error {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamUserEvents")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*fiber.Ctx) error); ok {
+		r0 = rf(c)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockUserEventHandler creates a new instance of MockUserEventHandler. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUserEventHandler(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUserEventHandler {
+	mock := &MockUserEventHandler{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
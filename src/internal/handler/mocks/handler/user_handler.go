@@ -85,6 +85,24 @@ func (_m *MockUserHandler) GetUserProfile(c *fiber.Ctx) error {
 	return r0
 }
 
+// ListUserHistory provides a mock function with given fields: c
+func (_m *MockUserHandler) ListUserHistory(c *fiber.Ctx) error {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUserHistory")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*fiber.Ctx) error); ok {
+		r0 = rf(c)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ListUsers provides a mock function with given fields: c
 func (_m *MockUserHandler) ListUsers(c *fiber.Ctx) error {
 	ret := _m.Called(c)
@@ -103,6 +121,42 @@ func (_m *MockUserHandler) ListUsers(c *fiber.Ctx) error {
 	return r0
 }
 
+// PatchUser provides a mock function with given fields: c
+func (_m *MockUserHandler) PatchUser(c *fiber.Ctx) error {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PatchUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*fiber.Ctx) error); ok {
+		r0 = rf(c)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevertUser provides a mock function with given fields: c
+func (_m *MockUserHandler) RevertUser(c *fiber.Ctx) error {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevertUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*fiber.Ctx) error); ok {
+		r0 = rf(c)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // UpdateUser provides a mock function with given fields: c
 func (_m *MockUserHandler) UpdateUser(c *fiber.Ctx) error {
 	ret := _m.Called(c)
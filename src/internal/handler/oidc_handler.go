@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+// oidcStateCookie binds a sign-in to the browser that started it, so a
+// callback URL planted on someone else cannot log them into another account.
+const (
+	oidcStateCookie     = "oidc_state"
+	oidcStateCookiePath = "/api/v1/auth/oidc"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=OIDCHandler --output=./mocks/handler --outpkg=handler --filename=oidc_handler.go --structname=MockOIDCHandler --with-expecter=false
+type OIDCHandler interface {
+	ListProviders(c *fiber.Ctx) error
+	Login(c *fiber.Ctx) error
+	Callback(c *fiber.Ctx) error
+	ListIdentities(c *fiber.Ctx) error
+}
+
+type oidcHandlerImpl struct {
+	oidcService service.OIDCLoginService
+	validator   *validator.Validate
+}
+
+func NewOIDCHandler(oidcService service.OIDCLoginService) OIDCHandler {
+	return &oidcHandlerImpl{
+		oidcService: oidcService,
+		validator:   validator.New(),
+	}
+}
+
+// ListProviders lists the configured identity providers
+// @Summary List identity providers
+// @Description List the names of the OpenID Connect providers that users can sign in with
+// @Tags auth
+// @Produce json
+// @Success 200 {array} string
+// @Router /auth/oidc/providers [get]
+func (h *oidcHandlerImpl) ListProviders(c *fiber.Ctx) error {
+	return c.JSON(h.oidcService.Providers())
+}
+
+// Login starts a sign-in with an identity provider
+// @Summary Sign in with an identity provider
+// @Description Redirect the browser to the OpenID Connect provider. The provider sends it back to /auth/oidc/{provider}/callback.
+// @Tags auth
+// @Param provider path string true "Provider name"
+// @Success 302
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/oidc/{provider}/login [get]
+func (h *oidcHandlerImpl) Login(c *fiber.Ctx) error {
+	authorization, err := h.oidcService.Start(c.Params("provider"))
+	if errors.Is(err, service.ErrUnknownOIDCProvider) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start sign-in",
+		})
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     oidcStateCookie,
+		Value:    authorization.State,
+		Path:     oidcStateCookiePath,
+		Expires:  authorization.ExpiresAt,
+		Secure:   c.Protocol() == "https",
+		HTTPOnly: true,
+		// Lax is needed for the cookie to come along on the redirect back
+		SameSite: fiber.CookieSameSiteLaxMode,
+	})
+	return c.Redirect(authorization.AuthorizationURL, fiber.StatusFound)
+}
+
+// Callback completes a sign-in with an identity provider
+// @Summary Identity provider callback
+// @Description Complete the sign-in started at /auth/oidc/{provider}/login and start a new session. The identity is linked to an existing account with the same verified email address on first use; there is no sign-up. For users with MFA enabled the response has mfa_required set and an mfa_token to send to /auth/login/mfa.
+// @Tags auth
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param code query string true "Authorization code"
+// @Param state query string true "State"
+// @Success 200 {object} model.LoginResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/oidc/{provider}/callback [get]
+func (h *oidcHandlerImpl) Callback(c *fiber.Ctx) error {
+	var req model.OIDCCallbackRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid query parameters",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// The state must come from the browser that started the sign-in
+	cookie := c.Cookies(oidcStateCookie)
+	c.Cookie(&fiber.Cookie{
+		Name:     oidcStateCookie,
+		Path:     oidcStateCookiePath,
+		Expires:  time.Unix(0, 0),
+		HTTPOnly: true,
+	})
+	if cookie == "" || subtle.ConstantTimeCompare([]byte(cookie), []byte(req.State)) != 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": service.ErrInvalidOIDCState.Error(),
+		})
+	}
+
+	resp, err := h.oidcService.Callback(c.UserContext(), c.Params("provider"), &req, clientInfo(c))
+	if errors.Is(err, service.ErrUnknownOIDCProvider) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if errors.Is(err, service.ErrInvalidOIDCState) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if errors.Is(err, service.ErrOIDCLoginFailed) {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if errors.Is(err, service.ErrNoLinkedAccount) || errors.Is(err, service.ErrMFAEnrollmentRequired) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to log in",
+		})
+	}
+
+	return c.JSON(resp)
+}
+
+// ListIdentities lists the external identities linked to a user
+// @Summary List linked identities
+// @Description List the identity provider accounts that can sign in as a user. Only the user and members with the users:manage permission see them.
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Success 200 {array} model.ExternalIdentityResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/{id}/identities [get]
+func (h *oidcHandlerImpl) ListIdentities(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	identities, err := h.oidcService.ListIdentities(c.UserContext(), uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	return c.JSON(identities)
+}
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+)
+
+func (s *HandlerTestSuite) oidcApp() *fiber.App {
+	app := fiber.New()
+	app.Get("/api/v1/auth/oidc/:provider/login", s.oidcHandler.Login)
+	app.Get("/api/v1/auth/oidc/:provider/callback", s.oidcHandler.Callback)
+	return app
+}
+
+// Test ListProviders handler
+func (s *HandlerTestSuite) TestListProviders() {
+	s.oidcService.On("Providers").Return([]string{"gitlab", "google"})
+
+	app := fiber.New()
+	app.Get("/auth/oidc/providers", s.oidcHandler.ListProviders)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/auth/oidc/providers", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result []string
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), []string{"gitlab", "google"}, result)
+}
+
+// Test Login handler
+func (s *HandlerTestSuite) TestOIDCLogin_RedirectsWithStateCookie() {
+	s.oidcService.On("Start", "google").Return(&model.OIDCAuthorization{
+		AuthorizationURL: "https://accounts.example.com/authorize?state=state-1",
+		State:            "state-1",
+		ExpiresAt:        time.Now().Add(10 * time.Minute),
+	}, nil)
+
+	resp, err := s.oidcApp().Test(httptest.NewRequest("GET", "/api/v1/auth/oidc/google/login", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusFound, resp.StatusCode)
+	assert.Equal(s.T(), "https://accounts.example.com/authorize?state=state-1", resp.Header.Get("Location"))
+
+	cookies := resp.Cookies()
+	assert.Len(s.T(), cookies, 1)
+	assert.Equal(s.T(), "oidc_state", cookies[0].Name)
+	assert.Equal(s.T(), "state-1", cookies[0].Value)
+	assert.Equal(s.T(), "/api/v1/auth/oidc", cookies[0].Path)
+	assert.True(s.T(), cookies[0].HttpOnly)
+}
+
+func (s *HandlerTestSuite) TestOIDCLogin_UnknownProvider() {
+	s.oidcService.On("Start", "unknown").Return(nil, service.ErrUnknownOIDCProvider)
+
+	resp, err := s.oidcApp().Test(httptest.NewRequest("GET", "/api/v1/auth/oidc/unknown/login", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
+
+// Test Callback handler
+func (s *HandlerTestSuite) TestOIDCCallback_Success() {
+	s.oidcService.On("Callback", mock.Anything, "google", &model.OIDCCallbackRequest{Code: "code-1", State: "state-1"}, mock.AnythingOfType("*model.ClientInfo")).
+		Return(&model.LoginResponse{AccessToken: "token", TokenType: "Bearer"}, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/auth/oidc/google/callback?code=code-1&state=state-1", nil)
+	req.Header.Set("Cookie", "oidc_state=state-1")
+	resp, err := s.oidcApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result model.LoginResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), "token", result.AccessToken)
+
+	// The state cookie is single use
+	cookies := resp.Cookies()
+	assert.Len(s.T(), cookies, 1)
+	assert.Equal(s.T(), "", cookies[0].Value)
+	assert.True(s.T(), cookies[0].Expires.Before(time.Now()))
+}
+
+func (s *HandlerTestSuite) TestOIDCCallback_StateCookieMismatch() {
+	cases := map[string]string{
+		"missing cookie": "",
+		"other state":    "oidc_state=state-2",
+	}
+
+	for name, cookie := range cases {
+		req := httptest.NewRequest("GET", "/api/v1/auth/oidc/google/callback?code=code-1&state=state-1", nil)
+		if cookie != "" {
+			req.Header.Set("Cookie", cookie)
+		}
+		resp, err := s.oidcApp().Test(req)
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode, name)
+	}
+	s.oidcService.AssertNotCalled(s.T(), "Callback", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *HandlerTestSuite) TestOIDCCallback_MissingCode() {
+	req := httptest.NewRequest("GET", "/api/v1/auth/oidc/google/callback?state=state-1", nil)
+	req.Header.Set("Cookie", "oidc_state=state-1")
+	resp, err := s.oidcApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestOIDCCallback_Errors() {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{service.ErrUnknownOIDCProvider, fiber.StatusNotFound},
+		{service.ErrInvalidOIDCState, fiber.StatusBadRequest},
+		{service.ErrOIDCLoginFailed, fiber.StatusUnauthorized},
+		{service.ErrNoLinkedAccount, fiber.StatusForbidden},
+		{service.ErrMFAEnrollmentRequired, fiber.StatusForbidden},
+		{errors.New("db down"), fiber.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		s.oidcService.ExpectedCalls = nil
+		s.oidcService.On("Callback", mock.Anything, "google", mock.Anything, mock.Anything).Return(nil, tc.err)
+
+		req := httptest.NewRequest("GET", "/api/v1/auth/oidc/google/callback?code=code-1&state=state-1", nil)
+		req.Header.Set("Cookie", "oidc_state=state-1")
+		resp, err := s.oidcApp().Test(req)
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), tc.status, resp.StatusCode, tc.err.Error())
+	}
+}
+
+// Test ListIdentities handler
+func (s *HandlerTestSuite) TestListIdentities_Success() {
+	s.oidcService.On("ListIdentities", mock.Anything, uint(1)).Return([]*model.ExternalIdentityResponse{
+		{ID: 7, Provider: "google", Email: "test@example.com"},
+	}, nil)
+
+	app := fiber.New()
+	app.Get("/users/:id/identities", s.oidcHandler.ListIdentities)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/1/identities", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result []model.ExternalIdentityResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Len(s.T(), result, 1)
+	assert.Equal(s.T(), "google", result[0].Provider)
+}
+
+func (s *HandlerTestSuite) TestListIdentities_UserNotFound() {
+	s.oidcService.On("ListIdentities", mock.Anything, uint(999)).Return(nil, errors.New("not found"))
+
+	app := fiber.New()
+	app.Get("/users/:id/identities", s.oidcHandler.ListIdentities)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/999/identities", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
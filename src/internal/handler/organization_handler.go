@@ -0,0 +1,295 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=OrganizationHandler --output=./mocks/handler --outpkg=handler --filename=organization_handler.go --structname=MockOrganizationHandler --with-expecter=false
+type OrganizationHandler interface {
+	CreateOrganization(c *fiber.Ctx) error
+	ListOrganizations(c *fiber.Ctx) error
+	ListMembers(c *fiber.Ctx) error
+	AddMember(c *fiber.Ctx) error
+	UpdateMember(c *fiber.Ctx) error
+	RemoveMember(c *fiber.Ctx) error
+}
+
+type organizationHandlerImpl struct {
+	organizationService service.OrganizationService
+	validator           *validator.Validate
+}
+
+func NewOrganizationHandler(organizationService service.OrganizationService) OrganizationHandler {
+	return &organizationHandlerImpl{
+		organizationService: organizationService,
+		validator:           validator.New(),
+	}
+}
+
+// CreateOrganization creates an organization
+// @Summary Create organization
+// @Description Create an organization owned by the caller. The slug names it in the X-Organization header and, with tenant.base_domain set, as a subdomain.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param organization body model.CreateOrganizationRequest true "Organization"
+// @Success 201 {object} model.OrganizationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /organizations [post]
+func (h *organizationHandlerImpl) CreateOrganization(c *fiber.Ctx) error {
+	var req model.CreateOrganizationRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	organization, err := h.organizationService.Create(middleware.PrincipalFromContext(c), &req)
+	switch {
+	case errors.Is(err, service.ErrInvalidSlug):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrSlugTaken):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create organization",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(organization)
+}
+
+// ListOrganizations lists the caller's organizations
+// @Summary List organizations
+// @Description List the organizations the caller is a member of, with the caller's role in each.
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Success 200 {array} model.OrganizationResponse
+// @Failure 500 {object} map[string]string
+// @Router /organizations [get]
+func (h *organizationHandlerImpl) ListOrganizations(c *fiber.Ctx) error {
+	organizations, err := h.organizationService.ListForUser(c.UserContext(), middleware.PrincipalFromContext(c).UserID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch organizations",
+		})
+	}
+
+	return c.JSON(organizations)
+}
+
+// ListMembers lists the members of an organization
+// @Summary List members
+// @Description List the members of an organization and their roles. Only members can see them.
+// @Tags organizations
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Organization ID"
+// @Success 200 {array} model.MembershipResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /organizations/{id}/members [get]
+func (h *organizationHandlerImpl) ListMembers(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid organization ID",
+		})
+	}
+
+	members, err := h.organizationService.ListMembers(c.UserContext(), middleware.PrincipalFromContext(c), uint(id))
+	if err != nil {
+		return h.organizationError(c, err, "Failed to fetch members")
+	}
+
+	return c.JSON(members)
+}
+
+// AddMember adds a member to an organization
+// @Summary Add member
+// @Description Add a user of any organization as a member with the given role (default member). Owners and admins can add members; only owners can add owners.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Organization ID"
+// @Param member body model.AddMemberRequest true "Member"
+// @Success 201 {object} model.MembershipResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /organizations/{id}/members [post]
+func (h *organizationHandlerImpl) AddMember(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid organization ID",
+		})
+	}
+
+	var req model.AddMemberRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	member, err := h.organizationService.AddMember(c.UserContext(), middleware.PrincipalFromContext(c), uint(id), &req)
+	if err != nil {
+		return h.organizationError(c, err, "Failed to add member")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(member)
+}
+
+// UpdateMember changes the role of a member
+// @Summary Update member
+// @Description Change the role of a member. Only owners can promote to or demote from owner, and the last owner cannot be demoted.
+// @Tags organizations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Organization ID"
+// @Param user_id path int true "User ID"
+// @Param member body model.UpdateMemberRequest true "Role"
+// @Success 200 {object} model.MembershipResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /organizations/{id}/members/{user_id} [put]
+func (h *organizationHandlerImpl) UpdateMember(c *fiber.Ctx) error {
+	id, userID, ok := h.memberParams(c)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid organization or user ID",
+		})
+	}
+
+	var req model.UpdateMemberRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	member, err := h.organizationService.UpdateMember(c.UserContext(), middleware.PrincipalFromContext(c), id, userID, &req)
+	if err != nil {
+		return h.organizationError(c, err, "Failed to update member")
+	}
+
+	return c.JSON(member)
+}
+
+// RemoveMember removes a member from an organization
+// @Summary Remove member
+// @Description Remove a member from an organization. Users cannot be removed from the organization they belong to, and the last owner cannot be removed.
+// @Tags organizations
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Organization ID"
+// @Param user_id path int true "User ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /organizations/{id}/members/{user_id} [delete]
+func (h *organizationHandlerImpl) RemoveMember(c *fiber.Ctx) error {
+	id, userID, ok := h.memberParams(c)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid organization or user ID",
+		})
+	}
+
+	if err := h.organizationService.RemoveMember(c.UserContext(), middleware.PrincipalFromContext(c), id, userID); err != nil {
+		return h.organizationError(c, err, "Failed to remove member")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *organizationHandlerImpl) memberParams(c *fiber.Ctx) (uint, uint, bool) {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+	userID, err := strconv.ParseUint(c.Params("user_id"), 10, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+	return uint(id), uint(userID), true
+}
+
+func (h *organizationHandlerImpl) organizationError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, service.ErrOrganizationNotFound),
+		errors.Is(err, service.ErrMemberNotFound),
+		errors.Is(err, service.ErrMemberUserNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrNotOrganizationMember),
+		errors.Is(err, service.ErrOrganizationAdminRequired),
+		errors.Is(err, service.ErrOrganizationOwnerRequired):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrMemberExists),
+		errors.Is(err, service.ErrLastOwner),
+		errors.Is(err, service.ErrHomeOrganization):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": message,
+		})
+	}
+}
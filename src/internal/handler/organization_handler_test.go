@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+)
+
+// newOrganizationApp serves the organization routes as the given caller.
+func (s *HandlerTestSuite) newOrganizationApp(caller *model.Principal) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("principal", caller)
+		return c.Next()
+	})
+	app.Post("/organizations", s.orgHandler.CreateOrganization)
+	app.Get("/organizations", s.orgHandler.ListOrganizations)
+	app.Get("/organizations/:id/members", s.orgHandler.ListMembers)
+	app.Post("/organizations/:id/members", s.orgHandler.AddMember)
+	app.Put("/organizations/:id/members/:user_id", s.orgHandler.UpdateMember)
+	app.Delete("/organizations/:id/members/:user_id", s.orgHandler.RemoveMember)
+	return app
+}
+
+// Test CreateOrganization handler
+func (s *HandlerTestSuite) TestCreateOrganization_Success() {
+	caller := &model.Principal{UserID: 1, SessionID: "session-1"}
+	req := &model.CreateOrganizationRequest{Name: "Acme", Slug: "acme"}
+	s.organizations.On("Create", caller, req).Return(&model.OrganizationResponse{
+		ID:   5,
+		Name: "Acme",
+		Slug: "acme",
+		Role: model.OrganizationRoleOwner,
+	}, nil)
+
+	body, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest("POST", "/organizations", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.newOrganizationApp(caller).Test(httpReq)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusCreated, resp.StatusCode)
+
+	var result model.OrganizationResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), model.OrganizationRoleOwner, result.Role)
+}
+
+func (s *HandlerTestSuite) TestCreateOrganization_Errors() {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{service.ErrInvalidSlug, fiber.StatusBadRequest},
+		{service.ErrSlugTaken, fiber.StatusConflict},
+		{errors.New("database error"), fiber.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		s.organizations.ExpectedCalls = nil
+		s.organizations.On("Create", mock.Anything, mock.Anything).Return(nil, tc.err)
+
+		body, _ := json.Marshal(map[string]string{"name": "Acme", "slug": "acme"})
+		req := httptest.NewRequest("POST", "/organizations", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.newOrganizationApp(&model.Principal{UserID: 1}).Test(req)
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), tc.status, resp.StatusCode, tc.err.Error())
+	}
+}
+
+// Test ListOrganizations handler
+func (s *HandlerTestSuite) TestListOrganizations_Success() {
+	s.organizations.On("ListForUser", mock.Anything, uint(1)).Return([]*model.OrganizationResponse{{ID: 1, Slug: "default"}, {ID: 5, Slug: "acme"}}, nil)
+
+	resp, err := s.newOrganizationApp(&model.Principal{UserID: 1}).Test(httptest.NewRequest("GET", "/organizations", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result []model.OrganizationResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Len(s.T(), result, 2)
+}
+
+// Test ListMembers handler
+func (s *HandlerTestSuite) TestListMembers_Success() {
+	s.organizations.On("ListMembers", mock.Anything, mock.Anything, uint(5)).Return([]*model.MembershipResponse{{UserID: 1, Role: model.OrganizationRoleOwner}}, nil)
+
+	resp, err := s.newOrganizationApp(&model.Principal{UserID: 1}).Test(httptest.NewRequest("GET", "/organizations/5/members", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestListMembers_NotMember() {
+	s.organizations.On("ListMembers", mock.Anything, mock.Anything, uint(5)).Return(nil, service.ErrNotOrganizationMember)
+
+	resp, err := s.newOrganizationApp(&model.Principal{UserID: 1}).Test(httptest.NewRequest("GET", "/organizations/5/members", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusForbidden, resp.StatusCode)
+}
+
+// Test AddMember handler
+func (s *HandlerTestSuite) TestAddMember_Success() {
+	req := &model.AddMemberRequest{UserID: 2, Role: model.OrganizationRoleAdmin}
+	s.organizations.On("AddMember", mock.Anything, mock.Anything, uint(5), req).Return(&model.MembershipResponse{UserID: 2, Role: model.OrganizationRoleAdmin}, nil)
+
+	body, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest("POST", "/organizations/5/members", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.newOrganizationApp(&model.Principal{UserID: 1}).Test(httpReq)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusCreated, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestAddMember_InvalidRole() {
+	body, _ := json.Marshal(map[string]interface{}{"user_id": 2, "role": "superuser"})
+	req := httptest.NewRequest("POST", "/organizations/5/members", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.newOrganizationApp(&model.Principal{UserID: 1}).Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+	s.organizations.AssertNotCalled(s.T(), "AddMember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *HandlerTestSuite) TestAddMember_Errors() {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{service.ErrOrganizationNotFound, fiber.StatusNotFound},
+		{service.ErrMemberUserNotFound, fiber.StatusNotFound},
+		{service.ErrOrganizationAdminRequired, fiber.StatusForbidden},
+		{service.ErrOrganizationOwnerRequired, fiber.StatusForbidden},
+		{service.ErrMemberExists, fiber.StatusConflict},
+		{errors.New("database error"), fiber.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		s.organizations.ExpectedCalls = nil
+		s.organizations.On("AddMember", mock.Anything, mock.Anything, uint(5), mock.Anything).Return(nil, tc.err)
+
+		body, _ := json.Marshal(map[string]interface{}{"user_id": 2})
+		req := httptest.NewRequest("POST", "/organizations/5/members", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := s.newOrganizationApp(&model.Principal{UserID: 1}).Test(req)
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), tc.status, resp.StatusCode, tc.err.Error())
+	}
+}
+
+// Test UpdateMember handler
+func (s *HandlerTestSuite) TestUpdateMember_Success() {
+	req := &model.UpdateMemberRequest{Role: model.OrganizationRoleOwner}
+	s.organizations.On("UpdateMember", mock.Anything, mock.Anything, uint(5), uint(2), req).Return(&model.MembershipResponse{UserID: 2, Role: model.OrganizationRoleOwner}, nil)
+
+	body, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest("PUT", "/organizations/5/members/2", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.newOrganizationApp(&model.Principal{UserID: 1}).Test(httpReq)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestUpdateMember_LastOwner() {
+	s.organizations.On("UpdateMember", mock.Anything, mock.Anything, uint(5), uint(1), mock.Anything).Return(nil, service.ErrLastOwner)
+
+	body, _ := json.Marshal(map[string]string{"role": "member"})
+	req := httptest.NewRequest("PUT", "/organizations/5/members/1", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.newOrganizationApp(&model.Principal{UserID: 1}).Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusConflict, resp.StatusCode)
+}
+
+// Test RemoveMember handler
+func (s *HandlerTestSuite) TestRemoveMember_Success() {
+	s.organizations.On("RemoveMember", mock.Anything, mock.Anything, uint(5), uint(2)).Return(nil)
+
+	resp, err := s.newOrganizationApp(&model.Principal{UserID: 1}).Test(httptest.NewRequest("DELETE", "/organizations/5/members/2", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNoContent, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestRemoveMember_Errors() {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{service.ErrMemberNotFound, fiber.StatusNotFound},
+		{service.ErrHomeOrganization, fiber.StatusConflict},
+		{service.ErrLastOwner, fiber.StatusConflict},
+	}
+
+	for _, tc := range cases {
+		s.organizations.ExpectedCalls = nil
+		s.organizations.On("RemoveMember", mock.Anything, mock.Anything, uint(5), uint(2)).Return(tc.err)
+
+		resp, err := s.newOrganizationApp(&model.Principal{UserID: 1}).Test(httptest.NewRequest("DELETE", "/organizations/5/members/2", nil))
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), tc.status, resp.StatusCode, tc.err.Error())
+	}
+}
+
+func (s *HandlerTestSuite) TestRemoveMember_InvalidID() {
+	resp, err := s.newOrganizationApp(&model.Principal{UserID: 1}).Test(httptest.NewRequest("DELETE", "/organizations/5/members/abc", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=PasskeyHandler --output=./mocks/handler --outpkg=handler --filename=passkey_handler.go --structname=MockPasskeyHandler --with-expecter=false
+type PasskeyHandler interface {
+	BeginRegistration(c *fiber.Ctx) error
+	FinishRegistration(c *fiber.Ctx) error
+	ListPasskeys(c *fiber.Ctx) error
+	DeletePasskey(c *fiber.Ctx) error
+	BeginLogin(c *fiber.Ctx) error
+	FinishLogin(c *fiber.Ctx) error
+}
+
+type passkeyHandlerImpl struct {
+	passkeyService service.PasskeyService
+	validator      *validator.Validate
+}
+
+func NewPasskeyHandler(passkeyService service.PasskeyService) PasskeyHandler {
+	return &passkeyHandlerImpl{
+		passkeyService: passkeyService,
+		validator:      validator.New(),
+	}
+}
+
+// BeginRegistration starts adding a passkey
+// @Summary Start passkey registration
+// @Description Return the options for navigator.credentials.create(), in the JSON form read by PublicKeyCredential.parseCreationOptionsFromJSON(). The passkeys the user already has are excluded.
+// @Tags passkeys
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/{id}/passkeys/register/begin [post]
+func (h *passkeyHandlerImpl) BeginRegistration(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	options, err := h.passkeyService.BeginRegistration(c.UserContext(), uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	return c.JSON(options)
+}
+
+// FinishRegistration stores a new passkey
+// @Summary Finish passkey registration
+// @Description Verify the credential returned by navigator.credentials.create() and store it under the given name. The authenticator must verify the user.
+// @Tags passkeys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Param request body model.RegisterPasskeyRequest true "Name and credential"
+// @Success 201 {object} model.PasskeyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/{id}/passkeys/register/finish [post]
+func (h *passkeyHandlerImpl) FinishRegistration(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	var req model.RegisterPasskeyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	passkey, err := h.passkeyService.FinishRegistration(uint(id), &req)
+	switch {
+	case errors.Is(err, service.ErrInvalidPasskeyChallenge), errors.Is(err, service.ErrInvalidPasskey):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to register passkey",
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(passkey)
+}
+
+// ListPasskeys lists a user's passkeys
+// @Summary List passkeys
+// @Description List the passkeys a user can log in with.
+// @Tags passkeys
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Success 200 {array} model.PasskeyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/{id}/passkeys [get]
+func (h *passkeyHandlerImpl) ListPasskeys(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	passkeys, err := h.passkeyService.ListPasskeys(c.UserContext(), uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	return c.JSON(passkeys)
+}
+
+// DeletePasskey removes a passkey
+// @Summary Delete passkey
+// @Description Remove a passkey so it can no longer be used to log in. The authenticator keeps it until the user deletes it there too.
+// @Tags passkeys
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Param passkey_id path int true "Passkey ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/{id}/passkeys/{passkey_id} [delete]
+func (h *passkeyHandlerImpl) DeletePasskey(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+	passkeyID, err := strconv.ParseUint(c.Params("passkey_id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid passkey ID",
+		})
+	}
+
+	err = h.passkeyService.DeletePasskey(uint(id), uint(passkeyID))
+	if errors.Is(err, service.ErrPasskeyNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to delete passkey",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// BeginLogin starts a login with a passkey
+// @Summary Start passkey login
+// @Description Return the options for navigator.credentials.get(), in the JSON form read by PublicKeyCredential.parseRequestOptionsFromJSON(). No username is needed; the browser offers the passkeys it has for the site.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Router /auth/passkeys/login/begin [post]
+func (h *passkeyHandlerImpl) BeginLogin(c *fiber.Ctx) error {
+	options, err := h.passkeyService.BeginLogin()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to start passkey login",
+		})
+	}
+
+	return c.JSON(options)
+}
+
+// FinishLogin logs in with a passkey
+// @Summary Finish passkey login
+// @Description Verify the credential returned by navigator.credentials.get() and start a new session, like /auth/login. A passkey counts as both factors, so no MFA step follows.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body model.PasskeyLoginRequest true "Credential"
+// @Success 200 {object} model.LoginResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/passkeys/login/finish [post]
+func (h *passkeyHandlerImpl) FinishLogin(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	var req model.PasskeyLoginRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	result, err := h.passkeyService.FinishLogin(c.UserContext(), &req, clientInfo(c))
+	switch {
+	case errors.Is(err, service.ErrInvalidPasskeyChallenge), errors.Is(err, service.ErrInvalidPasskey):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to log in",
+		})
+	}
+
+	return c.JSON(result)
+}
@@ -0,0 +1,223 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+	"gorm.io/gorm"
+)
+
+func (s *HandlerTestSuite) newPasskeyApp() *fiber.App {
+	app := fiber.New()
+	app.Get("/users/:id/passkeys", s.passkeyHandler.ListPasskeys)
+	app.Post("/users/:id/passkeys/register/begin", s.passkeyHandler.BeginRegistration)
+	app.Post("/users/:id/passkeys/register/finish", s.passkeyHandler.FinishRegistration)
+	app.Delete("/users/:id/passkeys/:passkey_id", s.passkeyHandler.DeletePasskey)
+	app.Post("/auth/passkeys/login/begin", s.passkeyHandler.BeginLogin)
+	app.Post("/auth/passkeys/login/finish", s.passkeyHandler.FinishLogin)
+	return app
+}
+
+// registrationBody is a request as the browser would send it, with the
+// credential serialized by toJSON().
+const registrationBody = `{
+	"name": "Laptop",
+	"credential": {
+		"id": "Y3JlZA",
+		"rawId": "Y3JlZA",
+		"type": "public-key",
+		"response": {"clientDataJSON": "e30", "attestationObject": "oA", "transports": ["internal"]}
+	}
+}`
+
+// Test BeginRegistration handler
+func (s *HandlerTestSuite) TestBeginPasskeyRegistration_Success() {
+	s.passkeys.On("BeginRegistration", mock.Anything, uint(1)).Return(&protocol.PublicKeyCredentialCreationOptions{Challenge: []byte("abc")}, nil)
+
+	req := httptest.NewRequest("POST", "/users/1/passkeys/register/begin", nil)
+	resp, err := s.newPasskeyApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var options map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&options)
+	assert.Equal(s.T(), "YWJj", options["challenge"])
+}
+
+func (s *HandlerTestSuite) TestBeginPasskeyRegistration_UserNotFound() {
+	s.passkeys.On("BeginRegistration", mock.Anything, uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+	req := httptest.NewRequest("POST", "/users/9/passkeys/register/begin", nil)
+	resp, err := s.newPasskeyApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
+
+// Test FinishRegistration handler
+func (s *HandlerTestSuite) TestFinishPasskeyRegistration_Success() {
+	s.passkeys.On("FinishRegistration", uint(1), mock.MatchedBy(func(req *model.RegisterPasskeyRequest) bool {
+		return req.Name == "Laptop" &&
+			string(req.Credential.RawID) == "cred" &&
+			bytes.Equal(req.Credential.AttestationResponse.AttestationObject, []byte{0xa0}) &&
+			req.Credential.AttestationResponse.Transports[0] == "internal"
+	})).Return(&model.PasskeyResponse{ID: 7, Name: "Laptop"}, nil)
+
+	req := httptest.NewRequest("POST", "/users/1/passkeys/register/finish", bytes.NewReader([]byte(registrationBody)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.newPasskeyApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusCreated, resp.StatusCode)
+
+	var result model.PasskeyResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), uint(7), result.ID)
+}
+
+func (s *HandlerTestSuite) TestFinishPasskeyRegistration_MissingName() {
+	body, _ := json.Marshal(map[string]interface{}{"credential": map[string]string{"id": "Y3JlZA"}})
+	req := httptest.NewRequest("POST", "/users/1/passkeys/register/finish", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.newPasskeyApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+	s.passkeys.AssertNotCalled(s.T(), "FinishRegistration", mock.Anything, mock.Anything)
+}
+
+func (s *HandlerTestSuite) TestFinishPasskeyRegistration_Errors() {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{service.ErrInvalidPasskeyChallenge, fiber.StatusBadRequest},
+		{service.ErrInvalidPasskey, fiber.StatusBadRequest},
+		{errors.New("database error"), fiber.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		s.passkeys.ExpectedCalls = nil
+		s.passkeys.On("FinishRegistration", uint(1), mock.Anything).Return(nil, tc.err)
+
+		req := httptest.NewRequest("POST", "/users/1/passkeys/register/finish", bytes.NewReader([]byte(registrationBody)))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := s.newPasskeyApp().Test(req)
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), tc.status, resp.StatusCode, tc.err.Error())
+	}
+}
+
+// Test ListPasskeys handler
+func (s *HandlerTestSuite) TestListPasskeys_Success() {
+	s.passkeys.On("ListPasskeys", mock.Anything, uint(1)).Return([]*model.PasskeyResponse{{ID: 7, Name: "Laptop"}}, nil)
+
+	req := httptest.NewRequest("GET", "/users/1/passkeys", nil)
+	resp, err := s.newPasskeyApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result []model.PasskeyResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Len(s.T(), result, 1)
+	assert.Equal(s.T(), "Laptop", result[0].Name)
+}
+
+// Test DeletePasskey handler
+func (s *HandlerTestSuite) TestDeletePasskey_Success() {
+	s.passkeys.On("DeletePasskey", uint(1), uint(7)).Return(nil)
+
+	req := httptest.NewRequest("DELETE", "/users/1/passkeys/7", nil)
+	resp, err := s.newPasskeyApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNoContent, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestDeletePasskey_NotFound() {
+	s.passkeys.On("DeletePasskey", uint(1), uint(8)).Return(service.ErrPasskeyNotFound)
+
+	req := httptest.NewRequest("DELETE", "/users/1/passkeys/8", nil)
+	resp, err := s.newPasskeyApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestDeletePasskey_InvalidID() {
+	req := httptest.NewRequest("DELETE", "/users/1/passkeys/abc", nil)
+	resp, err := s.newPasskeyApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// Test BeginLogin handler
+func (s *HandlerTestSuite) TestBeginPasskeyLogin_Success() {
+	s.passkeys.On("BeginLogin").Return(&protocol.PublicKeyCredentialRequestOptions{Challenge: []byte("abc"), RelyingPartyID: "localhost"}, nil)
+
+	req := httptest.NewRequest("POST", "/auth/passkeys/login/begin", nil)
+	resp, err := s.newPasskeyApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var options map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&options)
+	assert.Equal(s.T(), "localhost", options["rpId"])
+}
+
+// Test FinishLogin handler
+func (s *HandlerTestSuite) TestFinishPasskeyLogin_Success() {
+	s.passkeys.On("FinishLogin", mock.Anything, mock.MatchedBy(func(req *model.PasskeyLoginRequest) bool {
+		return req.Credential.ID == "Y3JlZA" && string(req.Credential.AssertionResponse.Signature) == "sig"
+	}), mock.AnythingOfType("*model.ClientInfo")).
+		Return(&model.LoginResponse{AccessToken: "access", TokenType: "Bearer"}, nil)
+
+	body := `{"credential": {"id": "Y3JlZA", "rawId": "Y3JlZA", "type": "public-key",
+		"response": {"clientDataJSON": "e30", "authenticatorData": "AA", "signature": "c2ln", "userHandle": "AQ"}}}`
+	req := httptest.NewRequest("POST", "/auth/passkeys/login/finish", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.newPasskeyApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result model.LoginResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), "access", result.AccessToken)
+}
+
+func (s *HandlerTestSuite) TestFinishPasskeyLogin_Errors() {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{service.ErrInvalidPasskeyChallenge, fiber.StatusUnauthorized},
+		{service.ErrInvalidPasskey, fiber.StatusUnauthorized},
+		{errors.New("database error"), fiber.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		s.passkeys.ExpectedCalls = nil
+		s.passkeys.On("FinishLogin", mock.Anything, mock.Anything, mock.Anything).Return(nil, tc.err)
+
+		req := httptest.NewRequest("POST", "/auth/passkeys/login/finish", bytes.NewReader([]byte(`{"credential": {}}`)))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := s.newPasskeyApp().Test(req)
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), tc.status, resp.StatusCode, tc.err.Error())
+	}
+}
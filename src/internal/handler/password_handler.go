@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=PasswordHandler --output=./mocks/handler --outpkg=handler --filename=password_handler.go --structname=MockPasswordHandler --with-expecter=false
+type PasswordHandler interface {
+	ChangePassword(c *fiber.Ctx) error
+	ForgotPassword(c *fiber.Ctx) error
+	ResetPassword(c *fiber.Ctx) error
+}
+
+type passwordHandlerImpl struct {
+	passwordService service.PasswordService
+	validator       *validator.Validate
+}
+
+func NewPasswordHandler(passwordService service.PasswordService) PasswordHandler {
+	return &passwordHandlerImpl{
+		passwordService: passwordService,
+		validator:       validator.New(),
+	}
+}
+
+// ChangePassword changes a user's password
+// @Summary Change password
+// @Description Change the password of the caller. The current password must be provided; wrong ones count as failed logins towards the lockout of the account.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Param password body model.ChangePasswordRequest true "Current and new password"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 429 {object} map[string]string
+// @Router /users/{id}/password [post]
+func (h *passwordHandlerImpl) ChangePassword(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	var req model.ChangePasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	err = h.passwordService.ChangePassword(c.UserContext(), uint(id), &req, clientInfo(c))
+	var throttledErr *service.LoginThrottledError
+	if errors.As(err, &throttledErr) {
+		return loginThrottled(c, throttledErr)
+	}
+	if errors.Is(err, service.ErrIncorrectPassword) {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return passwordError(c, err, fiber.StatusNotFound, "User not found")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ForgotPassword starts a password reset
+// @Summary Request a password reset
+// @Description Email a single-use password reset link. The response is the same whether or not the address belongs to a user.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body model.ForgotPasswordRequest true "Email address"
+// @Success 202
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/password/forgot [post]
+func (h *passwordHandlerImpl) ForgotPassword(c *fiber.Ctx) error {
+	var req model.ForgotPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	if err := h.passwordService.RequestPasswordReset(c.UserContext(), &req); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to request password reset",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusAccepted)
+}
+
+// ResetPassword completes a password reset
+// @Summary Reset password
+// @Description Set a new password with a token from a reset email. All sessions of the user are revoked.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body model.ResetPasswordRequest true "Reset token and new password"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Router /auth/password/reset [post]
+func (h *passwordHandlerImpl) ResetPassword(c *fiber.Ctx) error {
+	var req model.ResetPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	err := h.passwordService.ResetPassword(c.UserContext(), &req)
+	if errors.Is(err, service.ErrInvalidResetToken) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return passwordError(c, err, fiber.StatusInternalServerError, "Failed to reset password")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// passwordError reports password policy violations as 400 and any other
+// error with the given fallback status and message.
+func passwordError(c *fiber.Ctx, err error, status int, message string) error {
+	var policyErr *service.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":      policyErr.Error(),
+			"violations": policyErr.Violations,
+		})
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"error": message,
+	})
+}
@@ -0,0 +1,253 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+)
+
+// Test ChangePassword handler
+func (s *HandlerTestSuite) TestChangePassword_Success() {
+	changeReq := &model.ChangePasswordRequest{CurrentPassword: "old-password", NewPassword: "new-password"}
+
+	s.passwordService.On("ChangePassword", mock.Anything, uint(1), changeReq, mock.AnythingOfType("*model.ClientInfo")).Return(nil)
+
+	app := fiber.New()
+	app.Post("/users/:id/password", s.passwordHandler.ChangePassword)
+
+	body, _ := json.Marshal(changeReq)
+	req := httptest.NewRequest("POST", "/users/1/password", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNoContent, resp.StatusCode)
+	s.passwordService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestChangePassword_InvalidID() {
+	app := fiber.New()
+	app.Post("/users/:id/password", s.passwordHandler.ChangePassword)
+
+	req := httptest.NewRequest("POST", "/users/invalid/password", bytes.NewBuffer([]byte(`{}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestChangePassword_ValidationError() {
+	app := fiber.New()
+	app.Post("/users/:id/password", s.passwordHandler.ChangePassword)
+
+	req := httptest.NewRequest("POST", "/users/1/password", bytes.NewBuffer([]byte(`{"new_password":"new-password"}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestChangePassword_IncorrectCurrentPassword() {
+	changeReq := &model.ChangePasswordRequest{CurrentPassword: "wrong", NewPassword: "new-password"}
+
+	s.passwordService.On("ChangePassword", mock.Anything, uint(1), changeReq, mock.AnythingOfType("*model.ClientInfo")).Return(service.ErrIncorrectPassword)
+
+	app := fiber.New()
+	app.Post("/users/:id/password", s.passwordHandler.ChangePassword)
+
+	body, _ := json.Marshal(changeReq)
+	req := httptest.NewRequest("POST", "/users/1/password", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusForbidden, resp.StatusCode)
+	s.passwordService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestChangePassword_Throttled() {
+	changeReq := &model.ChangePasswordRequest{CurrentPassword: "wrong", NewPassword: "new-password"}
+
+	s.passwordService.On("ChangePassword", mock.Anything, uint(1), changeReq, mock.AnythingOfType("*model.ClientInfo")).Return(&service.LoginThrottledError{RetryAfter: 30 * time.Second})
+
+	app := fiber.New()
+	app.Post("/users/:id/password", s.passwordHandler.ChangePassword)
+
+	body, _ := json.Marshal(changeReq)
+	req := httptest.NewRequest("POST", "/users/1/password", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(s.T(), "30", resp.Header.Get(fiber.HeaderRetryAfter))
+}
+
+func (s *HandlerTestSuite) TestChangePassword_PolicyViolation() {
+	changeReq := &model.ChangePasswordRequest{CurrentPassword: "old-password", NewPassword: "short"}
+
+	s.passwordService.On("ChangePassword", mock.Anything, uint(1), changeReq, mock.AnythingOfType("*model.ClientInfo")).Return(&service.PasswordPolicyError{
+		Violations: []string{"must be at least 8 characters long"},
+	})
+
+	app := fiber.New()
+	app.Post("/users/:id/password", s.passwordHandler.ChangePassword)
+
+	body, _ := json.Marshal(changeReq)
+	req := httptest.NewRequest("POST", "/users/1/password", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+
+	var result map[string]interface{}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), []interface{}{"must be at least 8 characters long"}, result["violations"])
+	s.passwordService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestChangePassword_UserNotFound() {
+	changeReq := &model.ChangePasswordRequest{CurrentPassword: "old-password", NewPassword: "new-password"}
+
+	s.passwordService.On("ChangePassword", mock.Anything, uint(999), changeReq, mock.AnythingOfType("*model.ClientInfo")).Return(errors.New("user not found"))
+
+	app := fiber.New()
+	app.Post("/users/:id/password", s.passwordHandler.ChangePassword)
+
+	body, _ := json.Marshal(changeReq)
+	req := httptest.NewRequest("POST", "/users/999/password", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+	s.passwordService.AssertExpectations(s.T())
+}
+
+// Test ForgotPassword handler
+func (s *HandlerTestSuite) TestForgotPassword_Accepted() {
+	forgotReq := &model.ForgotPasswordRequest{Email: "test@example.com"}
+
+	s.passwordService.On("RequestPasswordReset", mock.Anything, forgotReq).Return(nil)
+
+	app := fiber.New()
+	app.Post("/auth/password/forgot", s.passwordHandler.ForgotPassword)
+
+	body, _ := json.Marshal(forgotReq)
+	req := httptest.NewRequest("POST", "/auth/password/forgot", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusAccepted, resp.StatusCode)
+	s.passwordService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestForgotPassword_InvalidEmail() {
+	app := fiber.New()
+	app.Post("/auth/password/forgot", s.passwordHandler.ForgotPassword)
+
+	req := httptest.NewRequest("POST", "/auth/password/forgot", bytes.NewBuffer([]byte(`{"email":"invalid"}`)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestForgotPassword_ServiceError() {
+	forgotReq := &model.ForgotPasswordRequest{Email: "test@example.com"}
+
+	s.passwordService.On("RequestPasswordReset", mock.Anything, forgotReq).Return(errors.New("mail error"))
+
+	app := fiber.New()
+	app.Post("/auth/password/forgot", s.passwordHandler.ForgotPassword)
+
+	body, _ := json.Marshal(forgotReq)
+	req := httptest.NewRequest("POST", "/auth/password/forgot", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusInternalServerError, resp.StatusCode)
+	s.passwordService.AssertExpectations(s.T())
+}
+
+// Test ResetPassword handler
+func (s *HandlerTestSuite) TestResetPassword_Success() {
+	resetReq := &model.ResetPasswordRequest{Token: "token", NewPassword: "new-password"}
+
+	s.passwordService.On("ResetPassword", mock.Anything, resetReq).Return(nil)
+
+	app := fiber.New()
+	app.Post("/auth/password/reset", s.passwordHandler.ResetPassword)
+
+	body, _ := json.Marshal(resetReq)
+	req := httptest.NewRequest("POST", "/auth/password/reset", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNoContent, resp.StatusCode)
+	s.passwordService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestResetPassword_InvalidToken() {
+	resetReq := &model.ResetPasswordRequest{Token: "token", NewPassword: "new-password"}
+
+	s.passwordService.On("ResetPassword", mock.Anything, resetReq).Return(service.ErrInvalidResetToken)
+
+	app := fiber.New()
+	app.Post("/auth/password/reset", s.passwordHandler.ResetPassword)
+
+	body, _ := json.Marshal(resetReq)
+	req := httptest.NewRequest("POST", "/auth/password/reset", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+	s.passwordService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestResetPassword_ServiceError() {
+	resetReq := &model.ResetPasswordRequest{Token: "token", NewPassword: "new-password"}
+
+	s.passwordService.On("ResetPassword", mock.Anything, resetReq).Return(errors.New("database error"))
+
+	app := fiber.New()
+	app.Post("/auth/password/reset", s.passwordHandler.ResetPassword)
+
+	body, _ := json.Marshal(resetReq)
+	req := httptest.NewRequest("POST", "/auth/password/reset", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusInternalServerError, resp.StatusCode)
+	s.passwordService.AssertExpectations(s.T())
+}
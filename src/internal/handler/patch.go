@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+const (
+	mimeMergePatch = "application/merge-patch+json"
+	mimeJSONPatch  = "application/json-patch+json"
+)
+
+var (
+	errUnsupportedPatchType = errors.New("Content-Type must be application/merge-patch+json or application/json-patch+json")
+	errInvalidPatch         = errors.New("Invalid patch document")
+)
+
+// patchError describes a patch that is well-formed but cannot be applied to
+// the resource, for example because it touches a read-only field.
+type patchError struct {
+	message string
+}
+
+func (e *patchError) Error() string {
+	return e.message
+}
+
+// applyPatch applies an RFC 7396 merge patch or an RFC 6902 JSON Patch to
+// doc depending on contentType.
+func applyPatch(contentType string, doc, patch []byte) ([]byte, error) {
+	switch contentType {
+	case mimeMergePatch:
+		if !json.Valid(patch) {
+			return nil, errInvalidPatch
+		}
+		patched, err := jsonpatch.MergePatch(doc, patch)
+		if err != nil {
+			return nil, errInvalidPatch
+		}
+		return patched, nil
+	case mimeJSONPatch:
+		operations, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, errInvalidPatch
+		}
+		patched, err := operations.Apply(doc)
+		if err != nil {
+			return nil, &patchError{message: fmt.Sprintf("Patch cannot be applied: %s", err.Error())}
+		}
+		return patched, nil
+	default:
+		return nil, errUnsupportedPatchType
+	}
+}
+
+// checkPatchedFields verifies that a patched document only differs from the
+// original in writable fields and has not gained or lost any field.
+func checkPatchedFields(original, patched []byte, writable ...string) error {
+	var before, after map[string]json.RawMessage
+	if err := json.Unmarshal(original, &before); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(patched, &after); err != nil {
+		return &patchError{message: "Patched document must be a JSON object"}
+	}
+
+	isWritable := make(map[string]bool, len(writable))
+	for _, field := range writable {
+		isWritable[field] = true
+	}
+
+	fields := make([]string, 0, len(after))
+	for field := range after {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		if _, ok := before[field]; !ok {
+			return &patchError{message: fmt.Sprintf("Unknown field %q", field)}
+		}
+	}
+
+	fields = fields[:0]
+	for field := range before {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		if isWritable[field] {
+			continue
+		}
+		value, ok := after[field]
+		if !ok || !jsonEqual(before[field], value) {
+			return &patchError{message: fmt.Sprintf("Field %q is read-only", field)}
+		}
+	}
+
+	return nil
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	var left, right interface{}
+	if json.Unmarshal(a, &left) != nil || json.Unmarshal(b, &right) != nil {
+		return false
+	}
+	return reflect.DeepEqual(left, right)
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=PresenceHandler --output=./mocks/handler --outpkg=handler --filename=presence_handler.go --structname=MockPresenceHandler --with-expecter=false
+type PresenceHandler interface {
+	GetPresence(c *fiber.Ctx) error
+}
+
+type presenceHandlerImpl struct {
+	presenceService service.PresenceService
+}
+
+func NewPresenceHandler(presenceService service.PresenceService) PresenceHandler {
+	return &presenceHandlerImpl{
+		presenceService: presenceService,
+	}
+}
+
+// GetPresence gets the presence of a user
+// @Summary Get user presence
+// @Description Tell whether a user is connected to the gateway (online) or not (offline), and when they last were
+// @Tags users
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} model.PresenceResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/{id}/presence [get]
+func (h *presenceHandlerImpl) GetPresence(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	presence, err := h.presenceService.GetPresence(c.UserContext(), uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch presence",
+		})
+	}
+
+	return c.JSON(presence)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/gorm"
+)
+
+func (s *HandlerTestSuite) newPresenceApp() *fiber.App {
+	app := fiber.New()
+	app.Get("/users/:id/presence", s.presenceHandler.GetPresence)
+	return app
+}
+
+// Test GetPresence handler
+func (s *HandlerTestSuite) TestGetPresence_Success() {
+	lastSeenAt := time.Date(2025, time.January, 15, 10, 30, 0, 0, time.UTC)
+	s.presences.On("GetPresence", mock.Anything, uint(1)).Return(&model.PresenceResponse{
+		UserID:     1,
+		Status:     model.PresenceOnline,
+		LastSeenAt: &lastSeenAt,
+	}, nil)
+
+	resp, err := s.newPresenceApp().Test(httptest.NewRequest("GET", "/users/1/presence", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result model.PresenceResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), model.PresenceOnline, result.Status)
+	assert.True(s.T(), lastSeenAt.Equal(*result.LastSeenAt))
+}
+
+func (s *HandlerTestSuite) TestGetPresence_InvalidID() {
+	resp, err := s.newPresenceApp().Test(httptest.NewRequest("GET", "/users/abc/presence", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestGetPresence_NotFound() {
+	s.presences.On("GetPresence", mock.Anything, uint(2)).Return(nil, gorm.ErrRecordNotFound)
+
+	resp, err := s.newPresenceApp().Test(httptest.NewRequest("GET", "/users/2/presence", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestGetPresence_Error() {
+	s.presences.On("GetPresence", mock.Anything, uint(1)).Return(nil, errors.New("store is down"))
+
+	resp, err := s.newPresenceApp().Test(httptest.NewRequest("GET", "/users/1/presence", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusInternalServerError, resp.StatusCode)
+}
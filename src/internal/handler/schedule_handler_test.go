@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+)
+
+func (s *HandlerTestSuite) newScheduleApp() *fiber.App {
+	app := fiber.New()
+	app.Get("/admin/schedules", s.scheduleHandler.ListSchedules)
+	app.Post("/admin/schedules/:name/trigger", s.scheduleHandler.TriggerSchedule)
+	return app
+}
+
+// Test ListSchedules handler
+func (s *HandlerTestSuite) TestListSchedules_Success() {
+	s.schedules.On("ListSchedules", mock.Anything).Return([]*model.ScheduleResponse{
+		{Name: "jobs.retention", Expression: "@daily", LastStatus: model.ScheduleFailed, LastError: "database is down"},
+	}, nil)
+
+	resp, err := s.newScheduleApp().Test(httptest.NewRequest("GET", "/admin/schedules", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result []model.ScheduleResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Len(s.T(), result, 1)
+	assert.Equal(s.T(), "database is down", result[0].LastError)
+}
+
+func (s *HandlerTestSuite) TestListSchedules_Error() {
+	s.schedules.On("ListSchedules", mock.Anything).Return(nil, errors.New("database is down"))
+
+	resp, err := s.newScheduleApp().Test(httptest.NewRequest("GET", "/admin/schedules", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusInternalServerError, resp.StatusCode)
+}
+
+// Test TriggerSchedule handler
+func (s *HandlerTestSuite) TestTriggerSchedule_Success() {
+	s.schedules.On("TriggerSchedule", mock.Anything, "jobs.retention").Return(&model.ScheduleResponse{Name: "jobs.retention"}, nil)
+
+	resp, err := s.newScheduleApp().Test(httptest.NewRequest("POST", "/admin/schedules/jobs.retention/trigger", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusAccepted, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestTriggerSchedule_NotFound() {
+	s.schedules.On("TriggerSchedule", mock.Anything, "nope").Return(nil, service.ErrScheduleNotFound)
+
+	resp, err := s.newScheduleApp().Test(httptest.NewRequest("POST", "/admin/schedules/nope/trigger", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
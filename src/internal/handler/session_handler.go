@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=SessionHandler --output=./mocks/handler --outpkg=handler --filename=session_handler.go --structname=MockSessionHandler --with-expecter=false
+type SessionHandler interface {
+	ListSessions(c *fiber.Ctx) error
+	RevokeSession(c *fiber.Ctx) error
+	RevokeAllSessions(c *fiber.Ctx) error
+	Logout(c *fiber.Ctx) error
+}
+
+type sessionHandlerImpl struct {
+	sessionService service.SessionService
+}
+
+func NewSessionHandler(sessionService service.SessionService) SessionHandler {
+	return &sessionHandlerImpl{
+		sessionService: sessionService,
+	}
+}
+
+// ListSessions lists the active sessions of a user
+// @Summary List sessions
+// @Description List the active sessions of a user with device, IP, user agent and last-seen time, most recently used first
+// @Tags sessions
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Success 200 {array} model.SessionResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /users/{id}/sessions [get]
+func (h *sessionHandlerImpl) ListSessions(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	sessions, err := h.sessionService.ListSessions(c.UserContext(), uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	return c.JSON(sessions)
+}
+
+// RevokeSession revokes one session of a user
+// @Summary Revoke session
+// @Description End one session of a user. Access tokens issued for it stop working.
+// @Tags sessions
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Param sid path string true "Session ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/{id}/sessions/{sid} [delete]
+func (h *sessionHandlerImpl) RevokeSession(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	err = h.sessionService.RevokeUserSession(c.UserContext(), uint(id), c.Params("sid"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+	if errors.Is(err, service.ErrSessionNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to revoke session",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RevokeAllSessions logs a user out everywhere
+// @Summary Revoke all sessions
+// @Description End every session of a user
+// @Tags sessions
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/{id}/sessions [delete]
+func (h *sessionHandlerImpl) RevokeAllSessions(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	err = h.sessionService.RevokeUserSessions(c.UserContext(), uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to revoke sessions",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Logout ends the caller's session
+// @Summary Log out
+// @Description End the session of the access token used for this request
+// @Tags auth
+// @Security BearerAuth
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /auth/logout [post]
+func (h *sessionHandlerImpl) Logout(c *fiber.Ctx) error {
+	principal := middleware.PrincipalFromContext(c)
+	if principal == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Not authenticated",
+		})
+	}
+	if principal.SessionID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "API keys have no session; revoke the key instead",
+		})
+	}
+
+	err := h.sessionService.Revoke(principal.UserID, principal.SessionID)
+	if err != nil && !errors.Is(err, service.ErrSessionNotFound) {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to log out",
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
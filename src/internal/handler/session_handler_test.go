@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+	"gorm.io/gorm"
+)
+
+// Test ListSessions handler
+func (s *HandlerTestSuite) TestListSessions_Success() {
+	s.sessionService.On("ListSessions", mock.Anything, uint(1)).Return([]*model.SessionResponse{
+		{ID: "session-1", Device: "Firefox on Linux", IP: "192.0.2.1"},
+	}, nil)
+
+	app := fiber.New()
+	app.Get("/users/:id/sessions", s.sessionHandler.ListSessions)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/1/sessions", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result []model.SessionResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Len(s.T(), result, 1)
+	assert.Equal(s.T(), "Firefox on Linux", result[0].Device)
+}
+
+func (s *HandlerTestSuite) TestListSessions_UserNotFound() {
+	s.sessionService.On("ListSessions", mock.Anything, uint(999)).Return(nil, errors.New("not found"))
+
+	app := fiber.New()
+	app.Get("/users/:id/sessions", s.sessionHandler.ListSessions)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/999/sessions", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
+
+// Test RevokeSession handler
+func (s *HandlerTestSuite) TestRevokeSession_Success() {
+	s.sessionService.On("RevokeUserSession", mock.Anything, uint(1), "session-1").Return(nil)
+
+	app := fiber.New()
+	app.Delete("/users/:id/sessions/:sid", s.sessionHandler.RevokeSession)
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/users/1/sessions/session-1", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNoContent, resp.StatusCode)
+	s.sessionService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestRevokeSession_NotFound() {
+	s.sessionService.On("RevokeUserSession", mock.Anything, uint(1), "missing").Return(service.ErrSessionNotFound)
+
+	app := fiber.New()
+	app.Delete("/users/:id/sessions/:sid", s.sessionHandler.RevokeSession)
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/users/1/sessions/missing", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
+
+// Test RevokeAllSessions handler
+func (s *HandlerTestSuite) TestRevokeAllSessions_Success() {
+	s.sessionService.On("RevokeUserSessions", mock.Anything, uint(1)).Return(nil)
+
+	app := fiber.New()
+	app.Delete("/users/:id/sessions", s.sessionHandler.RevokeAllSessions)
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/users/1/sessions", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNoContent, resp.StatusCode)
+	s.sessionService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestRevokeAllSessions_OtherOrganization() {
+	s.sessionService.On("RevokeUserSessions", mock.Anything, uint(999)).Return(gorm.ErrRecordNotFound)
+
+	app := fiber.New()
+	app.Delete("/users/:id/sessions", s.sessionHandler.RevokeAllSessions)
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/users/999/sessions", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestRevokeAllSessions_InvalidID() {
+	app := fiber.New()
+	app.Delete("/users/:id/sessions", s.sessionHandler.RevokeAllSessions)
+
+	resp, err := app.Test(httptest.NewRequest("DELETE", "/users/abc/sessions", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// Test Logout handler
+func (s *HandlerTestSuite) TestLogout_RevokesCurrentSession() {
+	s.sessionService.On("Revoke", uint(1), "session-1").Return(nil)
+
+	app := fiber.New()
+	app.Post("/auth/logout", func(c *fiber.Ctx) error {
+		c.Locals("principal", &model.Principal{UserID: 1, SessionID: "session-1"})
+		return c.Next()
+	}, s.sessionHandler.Logout)
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/auth/logout", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNoContent, resp.StatusCode)
+	s.sessionService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestLogout_Unauthenticated() {
+	app := fiber.New()
+	app.Post("/auth/logout", s.sessionHandler.Logout)
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/auth/logout", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusUnauthorized, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestLogout_APIKey() {
+	app := fiber.New()
+	app.Post("/auth/logout", func(c *fiber.Ctx) error {
+		c.Locals("principal", &model.Principal{UserID: 1, APIKeyID: 7})
+		return c.Next()
+	}, s.sessionHandler.Logout)
+
+	resp, err := app.Test(httptest.NewRequest("POST", "/auth/logout", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+	s.sessionService.AssertNotCalled(s.T(), "Revoke", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/events"
+	"github.com/weeranieb/go-kit-base/src/internal/stream"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// userEventTypes are the events a user event stream can carry.
+var userEventTypes = []string{events.UserCreatedType, events.UserUpdatedType, events.UserDeletedType}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=UserEventHandler --output=./mocks/handler --outpkg=handler --filename=user_event_handler.go --structname=MockUserEventHandler --with-expecter=false
+type UserEventHandler interface {
+	StreamUserEvents(c *fiber.Ctx) error
+}
+
+type userEventHandlerImpl struct {
+	hub  *stream.Hub
+	conf config.EventStreamConfig
+}
+
+func NewUserEventHandler(hub *stream.Hub, conf *config.Config) UserEventHandler {
+	return &userEventHandlerImpl{
+		hub:  hub,
+		conf: conf.EventStream,
+	}
+}
+
+// StreamUserEvents streams changes to users
+// @Summary Stream user events
+// @Description Stream the user.created, user.updated and user.deleted events of the organization of the request as server-sent events, each with the event ID as its id and the event as JSON data. A client that reconnects with Last-Event-ID (or last_event_id) gets the events it missed, as long as they are among the latest kept; otherwise it first gets a resync event and should reload the users. A comment is sent as a heartbeat every so often. Clients that fall behind are disconnected, and streams are closed after a while so clients reconnect and are authorized again.
+// @Tags users
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param types query string false "Comma-separated event types to stream, e.g. user.created,user.deleted"
+// @Param Last-Event-ID header string false "ID of the last event received"
+// @Param last_event_id query string false "ID of the last event received, for clients that cannot set headers"
+// @Success 200 {string} string "Event stream"
+// @Failure 400 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /users/events [get]
+func (h *userEventHandlerImpl) StreamUserEvents(c *fiber.Ctx) error {
+	types := userEventTypes
+	if param := c.Query("types"); param != "" {
+		types = strings.Split(param, ",")
+		for _, eventType := range types {
+			if !slices.Contains(userEventTypes, eventType) {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "Types must be user.created, user.updated or user.deleted",
+				})
+			}
+		}
+	}
+
+	lastEventID := c.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	var after uint64
+	if lastEventID != "" {
+		var err error
+		if after, err = strconv.ParseUint(lastEventID, 10, 32); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid last event ID",
+			})
+		}
+	}
+
+	// Subscribers only see the events of the organization of the request
+	organizationID, _ := tenant.OrganizationFromContext(c.UserContext())
+	sub, err := h.hub.Subscribe(stream.Filter{OrganizationID: organizationID, Types: types}, uint(after))
+	if err != nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Event stream unavailable",
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	// Keeps proxies such as nginx from buffering the stream
+	c.Set("X-Accel-Buffering", "no")
+
+	// The writer runs after the handler has returned, so it must not use c
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer h.hub.Unsubscribe(sub)
+		h.stream(w, sub)
+	})
+	return nil
+}
+
+// stream writes the events of the subscription until it ends, the client
+// goes away or the stream has been open for the maximum duration.
+func (h *userEventHandlerImpl) stream(w *bufio.Writer, sub *stream.Subscription) {
+	if sub.Missed {
+		fmt.Fprint(w, "event: resync\ndata: {}\n\n")
+	}
+	for _, msg := range sub.Replay {
+		if err := writeEvent(w, msg); err != nil {
+			return
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.conf.HeartbeatInterval)
+	defer heartbeat.Stop()
+	deadline := time.NewTimer(h.conf.MaxDuration)
+	defer deadline.Stop()
+
+	for {
+		select {
+		case msg, ok := <-sub.Events:
+			if !ok {
+				if errors.Is(sub.Err(), stream.ErrSlowSubscriber) {
+					fmt.Fprint(w, ": disconnected for falling behind\n\n")
+					w.Flush()
+				}
+				return
+			}
+			if err := writeEvent(w, msg); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		case <-deadline.C:
+			return
+		}
+
+		// A client that has gone away shows up as a failed flush
+		if err := w.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w *bufio.Writer, msg *events.Message) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		log.Printf("Failed to encode event %d for streaming: %v", msg.ID, err)
+		return nil
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", msg.ID, msg.Type, data)
+	return err
+}
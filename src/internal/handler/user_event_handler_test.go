@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/events"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	repositoryMocks "github.com/weeranieb/go-kit-base/src/internal/repository/mocks/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/stream"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+)
+
+// newUserEventApp serves the user event stream in organization 1 from a hub
+// that has read the messages, stored after event 10. Streams are closed
+// after 50ms.
+func (s *HandlerTestSuite) newUserEventApp(maxSubscribers int, messages ...*model.OutboxMessage) (*fiber.App, *stream.Hub) {
+	outboxRepo := repositoryMocks.NewMockOutboxRepository(s.T())
+	outboxRepo.On("WithContext", mock.Anything).Return(outboxRepo)
+	outboxRepo.On("LastID").Return(uint(10), nil)
+	outboxRepo.On("ListAfter", uint(10), mock.Anything).Return(messages, nil)
+
+	conf := &config.Config{EventStream: config.EventStreamConfig{
+		BufferSize:        2,
+		GapTimeout:        time.Minute,
+		SubscriberBuffer:  10,
+		MaxSubscribers:    maxSubscribers,
+		HeartbeatInterval: 10 * time.Millisecond,
+		MaxDuration:       50 * time.Millisecond,
+	}}
+	hub := stream.NewHub(outboxRepo, conf)
+	hub.Poll(context.Background())
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.SetUserContext(tenant.WithOrganization(c.UserContext(), 1))
+		return c.Next()
+	})
+	app.Get("/users/events", NewUserEventHandler(hub, conf).StreamUserEvents)
+	return app, hub
+}
+
+func newUserEventMessage(id, organizationID uint, eventType string) *model.OutboxMessage {
+	return &model.OutboxMessage{
+		ID:             id,
+		OrganizationID: organizationID,
+		EventType:      eventType,
+		AggregateType:  events.AggregateUser,
+		AggregateID:    3,
+		Payload:        `{"user":{"id":3}}`,
+	}
+}
+
+// Test StreamUserEvents handler
+func (s *HandlerTestSuite) TestStreamUserEvents_Resume() {
+	app, _ := s.newUserEventApp(10,
+		newUserEventMessage(11, 1, events.UserCreatedType),
+		newUserEventMessage(12, 2, events.UserCreatedType),
+	)
+	req := httptest.NewRequest("GET", "/users/events", nil)
+	req.Header.Set("Last-Event-ID", "10")
+
+	resp, err := app.Test(req, -1)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	assert.Equal(s.T(), "text/event-stream", resp.Header.Get(fiber.HeaderContentType))
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Contains(s.T(), string(body), "id: 11\nevent: user.created\ndata: {\"id\":11,")
+	assert.Contains(s.T(), string(body), `"data":{"user":{"id":3}}`)
+	assert.Contains(s.T(), string(body), ": heartbeat\n\n")
+	// Events of other organizations are not sent
+	assert.NotContains(s.T(), string(body), "id: 12")
+	assert.NotContains(s.T(), string(body), "event: resync")
+}
+
+func (s *HandlerTestSuite) TestStreamUserEvents_Types() {
+	app, _ := s.newUserEventApp(10,
+		newUserEventMessage(11, 1, events.UserCreatedType),
+		newUserEventMessage(12, 1, events.UserDeletedType),
+	)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/events?types=user.deleted&last_event_id=10", nil), -1)
+
+	assert.NoError(s.T(), err)
+	body, _ := io.ReadAll(resp.Body)
+	assert.NotContains(s.T(), string(body), "id: 11")
+	assert.Contains(s.T(), string(body), "id: 12\nevent: user.deleted")
+}
+
+func (s *HandlerTestSuite) TestStreamUserEvents_Resync() {
+	app, _ := s.newUserEventApp(10,
+		newUserEventMessage(11, 1, events.UserCreatedType),
+		newUserEventMessage(12, 1, events.UserUpdatedType),
+		newUserEventMessage(13, 1, events.UserUpdatedType),
+	)
+	req := httptest.NewRequest("GET", "/users/events", nil)
+	req.Header.Set("Last-Event-ID", "10")
+
+	resp, err := app.Test(req, -1)
+
+	assert.NoError(s.T(), err)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Contains(s.T(), string(body), "event: resync\ndata: {}\n\nid: 12\n")
+}
+
+func (s *HandlerTestSuite) TestStreamUserEvents_Invalid() {
+	app, _ := s.newUserEventApp(10)
+
+	for _, target := range []string{"/users/events?types=user.created,group.created", "/users/events?last_event_id=abc"} {
+		resp, err := app.Test(httptest.NewRequest("GET", target, nil))
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode, target)
+	}
+}
+
+func (s *HandlerTestSuite) TestStreamUserEvents_TooManySubscribers() {
+	app, hub := s.newUserEventApp(1)
+	hub.Subscribe(stream.Filter{OrganizationID: 1}, 0)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/events", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusServiceUnavailable, resp.StatusCode)
+}
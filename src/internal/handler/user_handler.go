@@ -1,7 +1,11 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/weeranieb/go-kit-base/src/internal/model"
 	"github.com/weeranieb/go-kit-base/src/internal/service"
@@ -15,8 +19,11 @@ type UserHandler interface {
 	CreateUser(c *fiber.Ctx) error
 	GetUser(c *fiber.Ctx) error
 	UpdateUser(c *fiber.Ctx) error
+	PatchUser(c *fiber.Ctx) error
 	DeleteUser(c *fiber.Ctx) error
 	ListUsers(c *fiber.Ctx) error
+	ListUserHistory(c *fiber.Ctx) error
+	RevertUser(c *fiber.Ctx) error
 	GetUserProfile(c *fiber.Ctx) error
 	UpdateUserProfile(c *fiber.Ctx) error
 }
@@ -35,14 +42,20 @@ func NewUserHandler(userService service.UserService) UserHandler {
 
 // CreateUser creates a new user
 // @Summary Create a new user
-// @Description Create a new user with the provided information
+// @Description Create a new user with the provided information. Anyone can register while auth.self_registration is enabled; otherwise the caller needs the users:write scope.
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param user body model.CreateUserRequest true "User information"
+// @Param Idempotency-Key header string false "Unique key that makes retries of this request safe"
 // @Success 201 {object} model.UserResponse
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
 // @Router /users [post]
 func (h *userHandlerImpl) CreateUser(c *fiber.Ctx) error {
 	var req model.CreateUserRequest
@@ -58,7 +71,14 @@ func (h *userHandlerImpl) CreateUser(c *fiber.Ctx) error {
 		})
 	}
 
-	user, err := h.userService.CreateUser(&req)
+	user, err := h.userService.CreateUser(c.UserContext(), &req)
+	var policyErr *service.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":      policyErr.Error(),
+			"violations": policyErr.Violations,
+		})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
 			"error": err.Error(),
@@ -70,12 +90,18 @@ func (h *userHandlerImpl) CreateUser(c *fiber.Ctx) error {
 
 // GetUser gets a user by ID
 // @Summary Get user by ID
-// @Description Get a user by their ID
+// @Description Get a user by their ID. With as_of, get the user as it was at that time instead; such responses carry no ETag.
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param id path int true "User ID"
+// @Param as_of query string false "Time to view the user at, RFC 3339"
+// @Param If-None-Match header string false "ETag of a cached copy of the user"
 // @Success 200 {object} model.UserResponse
+// @Header 200 {string} ETag "Current version of the user"
+// @Success 304
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Router /users/{id} [get]
@@ -87,13 +113,23 @@ func (h *userHandlerImpl) GetUser(c *fiber.Ctx) error {
 		})
 	}
 
-	user, err := h.userService.GetUser(uint(id))
+	if asOf := c.Query("as_of"); asOf != "" {
+		return h.getUserAsOf(c, uint(id), asOf)
+	}
+
+	user, err := h.userService.GetUser(c.UserContext(), uint(id))
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "User not found",
 		})
 	}
 
+	etag := formatETag(user.Version)
+	c.Set(fiber.HeaderETag, etag)
+	if ifNoneMatch(c, etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
 	return c.JSON(user)
 }
 
@@ -103,12 +139,19 @@ func (h *userHandlerImpl) GetUser(c *fiber.Ctx) error {
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param id path int true "User ID"
 // @Param user body model.UpdateUserRequest true "User information"
+// @Param Idempotency-Key header string false "Unique key that makes retries of this request safe"
+// @Param If-Match header string false "ETag the update is conditional on"
 // @Success 200 {object} model.UserResponse
+// @Header 200 {string} ETag "New version of the user"
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 422 {object} map[string]string
 // @Router /users/{id} [put]
 func (h *userHandlerImpl) UpdateUser(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
@@ -118,6 +161,13 @@ func (h *userHandlerImpl) UpdateUser(c *fiber.Ctx) error {
 		})
 	}
 
+	precondition, err := parseIfMatch(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
 	var req model.UpdateUserRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -131,22 +181,158 @@ func (h *userHandlerImpl) UpdateUser(c *fiber.Ctx) error {
 		})
 	}
 
-	user, err := h.userService.UpdateUser(uint(id), &req)
+	version, err := h.requiredVersion(c, uint(id), precondition)
+	if errors.Is(err, service.ErrVersionMismatch) {
+		return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	user, err := h.userService.UpdateUser(c.UserContext(), uint(id), version, &req)
+	if errors.Is(err, service.ErrVersionMismatch) {
+		return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
 
+	c.Set(fiber.HeaderETag, formatETag(user.Version))
+
 	return c.JSON(user)
 }
 
+// PatchUser partially updates a user by ID
+// @Summary Patch user by ID
+// @Description Partially update a user with an RFC 7396 merge patch or an RFC 6902 JSON Patch. Only username and email are writable.
+// @Tags users
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Param patch body object true "Merge patch or JSON Patch document"
+// @Param Idempotency-Key header string false "Unique key that makes retries of this request safe"
+// @Param If-Match header string false "ETag the patch is conditional on"
+// @Success 200 {object} model.UserResponse
+// @Header 200 {string} ETag "New version of the user"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 415 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Router /users/{id} [patch]
+func (h *userHandlerImpl) PatchUser(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	precondition, err := parseIfMatch(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	contentType := strings.ToLower(strings.TrimSpace(strings.Split(c.Get(fiber.HeaderContentType), ";")[0]))
+	if contentType != mimeMergePatch && contentType != mimeJSONPatch {
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(fiber.Map{
+			"error": errUnsupportedPatchType.Error(),
+		})
+	}
+
+	user, err := h.userService.GetUser(c.UserContext(), uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	if !precondition.matches(user.Version) {
+		return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+			"error": service.ErrVersionMismatch.Error(),
+		})
+	}
+
+	original, err := json.Marshal(user)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to patch user",
+		})
+	}
+
+	patched, err := applyPatch(contentType, original, c.Body())
+	if err == nil {
+		err = checkPatchedFields(original, patched, "username", "email")
+	}
+	var perr *patchError
+	if errors.As(err, &perr) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	var req model.PatchUserRequest
+	if err := json.Unmarshal(patched, &req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": errInvalidPatch.Error(),
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// The update is made against the version the patch was applied to, so a
+	// concurrent write in between is reported instead of being overwritten.
+	updated, err := h.userService.UpdateUser(c.UserContext(), uint(id), user.Version, &model.UpdateUserRequest{
+		Username: req.Username,
+		Email:    req.Email,
+	})
+	if errors.Is(err, service.ErrVersionMismatch) {
+		return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderETag, formatETag(updated.Version))
+
+	return c.JSON(updated)
+}
+
 // DeleteUser deletes a user by ID
 // @Summary Delete user by ID
 // @Description Delete a user by their ID
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param id path int true "User ID"
 // @Success 204
 // @Failure 400 {object} map[string]string
@@ -160,7 +346,7 @@ func (h *userHandlerImpl) DeleteUser(c *fiber.Ctx) error {
 		})
 	}
 
-	err = h.userService.DeleteUser(uint(id))
+	err = h.userService.DeleteUser(c.UserContext(), uint(id))
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "User not found",
@@ -176,6 +362,8 @@ func (h *userHandlerImpl) DeleteUser(c *fiber.Ctx) error {
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param limit query int false "Limit"
 // @Param offset query int false "Offset"
 // @Success 200 {object} map[string]interface{}
@@ -200,7 +388,7 @@ func (h *userHandlerImpl) ListUsers(c *fiber.Ctx) error {
 		}
 	}
 
-	users, err := h.userService.ListUsers(limit, offset)
+	users, err := h.userService.ListUsers(c.UserContext(), limit, offset)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to fetch users",
@@ -214,14 +402,183 @@ func (h *userHandlerImpl) ListUsers(c *fiber.Ctx) error {
 	})
 }
 
+// getUserAsOf responds with the user as it was at the time asOf.
+func (h *userHandlerImpl) getUserAsOf(c *fiber.Ctx, id uint, asOf string) error {
+	at, err := time.Parse(time.RFC3339, asOf)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid as_of, expected an RFC 3339 time",
+		})
+	}
+
+	user, err := h.userService.GetUserAsOf(c.UserContext(), id, at)
+	if errors.Is(err, service.ErrUserVersionNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User did not exist at that time",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	return c.JSON(user)
+}
+
+// ListUserHistory lists the versions of a user
+// @Summary List user history
+// @Description List the versions of a user, newest first. A version is stored on every change to the user.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/{id}/history [get]
+func (h *userHandlerImpl) ListUserHistory(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	limit := 10 // default
+	offset := 0 // default
+
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	if o, err := strconv.Atoi(c.Query("offset")); err == nil && o >= 0 {
+		offset = o
+	}
+
+	versions, err := h.userService.ListUserVersions(c.UserContext(), uint(id), limit, offset)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch user history",
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"versions": versions,
+		"limit":    limit,
+		"offset":   offset,
+	})
+}
+
+// RevertUser restores a previous version of a user
+// @Summary Revert user to a version
+// @Description Restore the username and email of a previous version of the user. The restore is an ordinary update: it is validated like one, a restored email is pending until confirmed, and it creates a new version.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "User ID"
+// @Param version path int true "Version to restore"
+// @Param Idempotency-Key header string false "Unique key that makes retries of this request safe"
+// @Param If-Match header string false "ETag the revert is conditional on"
+// @Success 200 {object} model.UserResponse
+// @Header 200 {string} ETag "New version of the user"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Router /users/{id}/history/{version}/revert [post]
+func (h *userHandlerImpl) RevertUser(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid user ID",
+		})
+	}
+
+	target, err := strconv.ParseUint(c.Params("version"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid version",
+		})
+	}
+
+	precondition, err := parseIfMatch(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	previous, err := h.userService.GetUserVersion(c.UserContext(), uint(id), uint(target))
+	if errors.Is(err, service.ErrUserVersionNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch user version",
+		})
+	}
+
+	req := model.UpdateUserRequest{
+		Username: previous.Username,
+		Email:    previous.Email,
+	}
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	version, err := h.requiredVersion(c, uint(id), precondition)
+	if errors.Is(err, service.ErrVersionMismatch) {
+		return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	user, err := h.userService.UpdateUser(c.UserContext(), uint(id), version, &req)
+	if errors.Is(err, service.ErrVersionMismatch) {
+		return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderETag, formatETag(user.Version))
+
+	return c.JSON(user)
+}
+
 // GetUserProfile gets a user's profile by ID
 // @Summary Get user profile by ID
 // @Description Get a user's profile by their ID
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param id path int true "User ID"
+// @Param If-None-Match header string false "ETag of a cached copy of the profile"
 // @Success 200 {object} map[string]interface{}
+// @Header 200 {string} ETag "Current version of the user"
+// @Success 304
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Router /users/{id}/profile [get]
@@ -233,13 +590,19 @@ func (h *userHandlerImpl) GetUserProfile(c *fiber.Ctx) error {
 		})
 	}
 
-	user, err := h.userService.GetUser(uint(id))
+	user, err := h.userService.GetUser(c.UserContext(), uint(id))
 	if err != nil {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "User not found",
 		})
 	}
 
+	etag := formatETag(user.Version)
+	c.Set(fiber.HeaderETag, etag)
+	if ifNoneMatch(c, etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
 	return c.JSON(fiber.Map{
 		"profile": user,
 	})
@@ -251,12 +614,19 @@ func (h *userHandlerImpl) GetUserProfile(c *fiber.Ctx) error {
 // @Tags users
 // @Accept json
 // @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
 // @Param id path int true "User ID"
 // @Param user body model.UpdateUserRequest true "User profile information"
+// @Param Idempotency-Key header string false "Unique key that makes retries of this request safe"
+// @Param If-Match header string false "ETag the update is conditional on"
 // @Success 200 {object} map[string]interface{}
+// @Header 200 {string} ETag "New version of the user"
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 409 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 422 {object} map[string]string
 // @Router /users/{id}/profile [put]
 func (h *userHandlerImpl) UpdateUserProfile(c *fiber.Ctx) error {
 	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
@@ -266,6 +636,13 @@ func (h *userHandlerImpl) UpdateUserProfile(c *fiber.Ctx) error {
 		})
 	}
 
+	precondition, err := parseIfMatch(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
 	var req model.UpdateUserRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -279,14 +656,58 @@ func (h *userHandlerImpl) UpdateUserProfile(c *fiber.Ctx) error {
 		})
 	}
 
-	user, err := h.userService.UpdateUser(uint(id), &req)
+	version, err := h.requiredVersion(c, uint(id), precondition)
+	if errors.Is(err, service.ErrVersionMismatch) {
+		return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+
+	user, err := h.userService.UpdateUser(c.UserContext(), uint(id), version, &req)
+	if errors.Is(err, service.ErrVersionMismatch) {
+		return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
 
+	c.Set(fiber.HeaderETag, formatETag(user.Version))
+
 	return c.JSON(fiber.Map{
 		"profile": user,
 	})
 }
+
+// requiredVersion returns the version an update of the user must be made
+// against to meet the If-Match header, or 0 for any. A single listed
+// version is checked by the service as it updates; several are resolved
+// against the current version first. ErrVersionMismatch is returned when
+// no listed tag can match.
+func (h *userHandlerImpl) requiredVersion(c *fiber.Ctx, id uint, precondition ifMatch) (uint, error) {
+	switch {
+	case precondition.anyVersion:
+		return 0, nil
+	case len(precondition.versions) == 0:
+		return 0, service.ErrVersionMismatch
+	case len(precondition.versions) == 1:
+		return precondition.versions[0], nil
+	}
+
+	user, err := h.userService.GetUser(c.UserContext(), id)
+	if err != nil {
+		return 0, err
+	}
+	if !precondition.matches(user.Version) {
+		return 0, service.ErrVersionMismatch
+	}
+	return user.Version, nil
+}
@@ -4,12 +4,15 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http/httptest"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
 )
 
 // Test CreateUser handler
@@ -28,7 +31,7 @@ func (s *HandlerTestSuite) TestCreateUser_Success() {
 		UpdatedAt: time.Now(),
 	}
 
-	s.userService.On("CreateUser", createReq).Return(expectedResponse, nil)
+	s.userService.On("CreateUser", mock.Anything, createReq).Return(expectedResponse, nil)
 
 	app := fiber.New()
 	app.Post("/users", s.userHandler.CreateUser)
@@ -84,7 +87,7 @@ func (s *HandlerTestSuite) TestCreateUser_ServiceError() {
 		Password: "password123",
 	}
 
-	s.userService.On("CreateUser", req).Return(nil, errors.New("email already exists"))
+	s.userService.On("CreateUser", mock.Anything, req).Return(nil, errors.New("email already exists"))
 
 	app := fiber.New()
 	app.Post("/users", s.userHandler.CreateUser)
@@ -100,6 +103,31 @@ func (s *HandlerTestSuite) TestCreateUser_ServiceError() {
 	s.userService.AssertExpectations(s.T())
 }
 
+func (s *HandlerTestSuite) TestCreateUser_PasswordPolicyViolation() {
+	req := &model.CreateUserRequest{
+		Username: "testuser",
+		Email:    "test@example.com",
+		Password: "testuser1",
+	}
+
+	s.userService.On("CreateUser", mock.Anything, req).Return(nil, &service.PasswordPolicyError{
+		Violations: []string{"must not contain the username or email address"},
+	})
+
+	app := fiber.New()
+	app.Post("/users", s.userHandler.CreateUser)
+
+	body, _ := json.Marshal(req)
+	reqHTTP := httptest.NewRequest("POST", "/users", bytes.NewBuffer(body))
+	reqHTTP.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(reqHTTP)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+	s.userService.AssertExpectations(s.T())
+}
+
 // Test GetUser handler
 func (s *HandlerTestSuite) TestGetUser_Success() {
 	userID := uint(1)
@@ -111,7 +139,7 @@ func (s *HandlerTestSuite) TestGetUser_Success() {
 		UpdatedAt: time.Now(),
 	}
 
-	s.userService.On("GetUser", userID).Return(expectedResponse, nil)
+	s.userService.On("GetUser", mock.Anything, userID).Return(expectedResponse, nil)
 
 	app := fiber.New()
 	app.Get("/users/:id", s.userHandler.GetUser)
@@ -125,6 +153,77 @@ func (s *HandlerTestSuite) TestGetUser_Success() {
 	s.userService.AssertExpectations(s.T())
 }
 
+func (s *HandlerTestSuite) TestGetUser_SetsETag() {
+	userID := uint(1)
+	expectedResponse := &model.UserResponse{
+		ID:       userID,
+		Username: "testuser",
+		Email:    "test@example.com",
+		Version:  3,
+	}
+
+	s.userService.On("GetUser", mock.Anything, userID).Return(expectedResponse, nil)
+
+	app := fiber.New()
+	app.Get("/users/:id", s.userHandler.GetUser)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/1", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	assert.Equal(s.T(), `"3"`, resp.Header.Get("ETag"))
+	s.userService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestGetUser_NotModified() {
+	userID := uint(1)
+	expectedResponse := &model.UserResponse{
+		ID:       userID,
+		Username: "testuser",
+		Email:    "test@example.com",
+		Version:  3,
+	}
+
+	s.userService.On("GetUser", mock.Anything, userID).Return(expectedResponse, nil)
+
+	app := fiber.New()
+	app.Get("/users/:id", s.userHandler.GetUser)
+
+	req := httptest.NewRequest("GET", "/users/1", nil)
+	req.Header.Set("If-None-Match", `"2", W/"3"`)
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotModified, resp.StatusCode)
+	assert.Equal(s.T(), `"3"`, resp.Header.Get("ETag"))
+	s.userService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestGetUser_ModifiedSinceCachedCopy() {
+	userID := uint(1)
+	expectedResponse := &model.UserResponse{
+		ID:       userID,
+		Username: "testuser",
+		Email:    "test@example.com",
+		Version:  3,
+	}
+
+	s.userService.On("GetUser", mock.Anything, userID).Return(expectedResponse, nil)
+
+	app := fiber.New()
+	app.Get("/users/:id", s.userHandler.GetUser)
+
+	req := httptest.NewRequest("GET", "/users/1", nil)
+	req.Header.Set("If-None-Match", `"2"`)
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	s.userService.AssertExpectations(s.T())
+}
+
 func (s *HandlerTestSuite) TestGetUser_InvalidID() {
 	app := fiber.New()
 	app.Get("/users/:id", s.userHandler.GetUser)
@@ -139,7 +238,7 @@ func (s *HandlerTestSuite) TestGetUser_InvalidID() {
 
 func (s *HandlerTestSuite) TestGetUser_NotFound() {
 	userID := uint(999)
-	s.userService.On("GetUser", userID).Return(nil, errors.New("user not found"))
+	s.userService.On("GetUser", mock.Anything, userID).Return(nil, errors.New("user not found"))
 
 	app := fiber.New()
 	app.Get("/users/:id", s.userHandler.GetUser)
@@ -169,7 +268,7 @@ func (s *HandlerTestSuite) TestUpdateUser_Success() {
 		UpdatedAt: time.Now(),
 	}
 
-	s.userService.On("UpdateUser", userID, req).Return(expectedResponse, nil)
+	s.userService.On("UpdateUser", mock.Anything, userID, uint(0), req).Return(expectedResponse, nil)
 
 	app := fiber.New()
 	app.Put("/users/:id", s.userHandler.UpdateUser)
@@ -235,7 +334,7 @@ func (s *HandlerTestSuite) TestUpdateUser_ServiceError() {
 		Username: "updateduser",
 	}
 
-	s.userService.On("UpdateUser", userID, req).Return(nil, errors.New("username already exists"))
+	s.userService.On("UpdateUser", mock.Anything, userID, uint(0), req).Return(nil, errors.New("username already exists"))
 
 	app := fiber.New()
 	app.Put("/users/:id", s.userHandler.UpdateUser)
@@ -251,10 +350,137 @@ func (s *HandlerTestSuite) TestUpdateUser_ServiceError() {
 	s.userService.AssertExpectations(s.T())
 }
 
+func (s *HandlerTestSuite) TestUpdateUser_IfMatch() {
+	userID := uint(1)
+	req := &model.UpdateUserRequest{
+		Username: "updateduser",
+	}
+
+	expectedResponse := &model.UserResponse{
+		ID:       userID,
+		Username: req.Username,
+		Version:  4,
+	}
+
+	s.userService.On("UpdateUser", mock.Anything, userID, uint(3), req).Return(expectedResponse, nil)
+
+	app := fiber.New()
+	app.Put("/users/:id", s.userHandler.UpdateUser)
+
+	body, _ := json.Marshal(req)
+	reqHTTP := httptest.NewRequest("PUT", "/users/1", bytes.NewBuffer(body))
+	reqHTTP.Header.Set("Content-Type", "application/json")
+	reqHTTP.Header.Set("If-Match", `"3"`)
+
+	resp, err := app.Test(reqHTTP)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	assert.Equal(s.T(), `"4"`, resp.Header.Get("ETag"))
+	s.userService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestUpdateUser_PreconditionFailed() {
+	userID := uint(1)
+	req := &model.UpdateUserRequest{
+		Username: "updateduser",
+	}
+
+	s.userService.On("UpdateUser", mock.Anything, userID, uint(2), req).Return(nil, service.ErrVersionMismatch)
+
+	app := fiber.New()
+	app.Put("/users/:id", s.userHandler.UpdateUser)
+
+	body, _ := json.Marshal(req)
+	reqHTTP := httptest.NewRequest("PUT", "/users/1", bytes.NewBuffer(body))
+	reqHTTP.Header.Set("Content-Type", "application/json")
+	reqHTTP.Header.Set("If-Match", `"2"`)
+
+	resp, err := app.Test(reqHTTP)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusPreconditionFailed, resp.StatusCode)
+	s.userService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestUpdateUser_InvalidIfMatch() {
+	app := fiber.New()
+	app.Put("/users/:id", s.userHandler.UpdateUser)
+
+	reqHTTP := httptest.NewRequest("PUT", "/users/1", bytes.NewBuffer([]byte(`{"username":"updateduser"}`)))
+	reqHTTP.Header.Set("Content-Type", "application/json")
+	reqHTTP.Header.Set("If-Match", `3`)
+
+	resp, err := app.Test(reqHTTP)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+	s.userService.AssertNotCalled(s.T(), "UpdateUser")
+}
+
+func (s *HandlerTestSuite) TestUpdateUser_WeakIfMatch() {
+	app := fiber.New()
+	app.Put("/users/:id", s.userHandler.UpdateUser)
+
+	reqHTTP := httptest.NewRequest("PUT", "/users/1", bytes.NewBuffer([]byte(`{"username":"updateduser"}`)))
+	reqHTTP.Header.Set("Content-Type", "application/json")
+	reqHTTP.Header.Set("If-Match", `W/"3"`)
+
+	resp, err := app.Test(reqHTTP)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusPreconditionFailed, resp.StatusCode)
+	s.userService.AssertNotCalled(s.T(), "UpdateUser")
+}
+
+func (s *HandlerTestSuite) TestUpdateUser_IfMatchList() {
+	userID := uint(1)
+	req := &model.UpdateUserRequest{
+		Username: "updateduser",
+	}
+
+	s.userService.On("GetUser", mock.Anything, userID).Return(&model.UserResponse{ID: userID, Version: 3}, nil)
+	s.userService.On("UpdateUser", mock.Anything, userID, uint(3), req).Return(&model.UserResponse{ID: userID, Version: 4}, nil)
+
+	app := fiber.New()
+	app.Put("/users/:id", s.userHandler.UpdateUser)
+
+	body, _ := json.Marshal(req)
+	reqHTTP := httptest.NewRequest("PUT", "/users/1", bytes.NewBuffer(body))
+	reqHTTP.Header.Set("Content-Type", "application/json")
+	reqHTTP.Header.Set("If-Match", `"2", W/"5", "3"`)
+
+	resp, err := app.Test(reqHTTP)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	assert.Equal(s.T(), `"4"`, resp.Header.Get("ETag"))
+	s.userService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestUpdateUser_IfMatchListMismatch() {
+	userID := uint(1)
+
+	s.userService.On("GetUser", mock.Anything, userID).Return(&model.UserResponse{ID: userID, Version: 4}, nil)
+
+	app := fiber.New()
+	app.Put("/users/:id", s.userHandler.UpdateUser)
+
+	reqHTTP := httptest.NewRequest("PUT", "/users/1", bytes.NewBuffer([]byte(`{"username":"updateduser"}`)))
+	reqHTTP.Header.Set("Content-Type", "application/json")
+	reqHTTP.Header.Set("If-Match", `"2", "3"`)
+
+	resp, err := app.Test(reqHTTP)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusPreconditionFailed, resp.StatusCode)
+	s.userService.AssertNotCalled(s.T(), "UpdateUser")
+}
+
 // Test DeleteUser handler
 func (s *HandlerTestSuite) TestDeleteUser_Success() {
 	userID := uint(1)
-	s.userService.On("DeleteUser", userID).Return(nil)
+	s.userService.On("DeleteUser", mock.Anything, userID).Return(nil)
 
 	app := fiber.New()
 	app.Delete("/users/:id", s.userHandler.DeleteUser)
@@ -282,7 +508,7 @@ func (s *HandlerTestSuite) TestDeleteUser_InvalidID() {
 
 func (s *HandlerTestSuite) TestDeleteUser_NotFound() {
 	userID := uint(999)
-	s.userService.On("DeleteUser", userID).Return(errors.New("user not found"))
+	s.userService.On("DeleteUser", mock.Anything, userID).Return(errors.New("user not found"))
 
 	app := fiber.New()
 	app.Delete("/users/:id", s.userHandler.DeleteUser)
@@ -318,7 +544,7 @@ func (s *HandlerTestSuite) TestListUsers_Success() {
 		},
 	}
 
-	s.userService.On("ListUsers", limit, offset).Return(expectedUsers, nil)
+	s.userService.On("ListUsers", mock.Anything, limit, offset).Return(expectedUsers, nil)
 
 	app := fiber.New()
 	app.Get("/users", s.userHandler.ListUsers)
@@ -346,7 +572,7 @@ func (s *HandlerTestSuite) TestListUsers_WithQueryParams() {
 		},
 	}
 
-	s.userService.On("ListUsers", limit, offset).Return(expectedUsers, nil)
+	s.userService.On("ListUsers", mock.Anything, limit, offset).Return(expectedUsers, nil)
 
 	app := fiber.New()
 	app.Get("/users", s.userHandler.ListUsers)
@@ -365,7 +591,7 @@ func (s *HandlerTestSuite) TestListUsers_InvalidLimit() {
 	offset := 0
 
 	expectedUsers := []*model.UserResponse{}
-	s.userService.On("ListUsers", limit, offset).Return(expectedUsers, nil)
+	s.userService.On("ListUsers", mock.Anything, limit, offset).Return(expectedUsers, nil)
 
 	app := fiber.New()
 	app.Get("/users", s.userHandler.ListUsers)
@@ -383,7 +609,7 @@ func (s *HandlerTestSuite) TestListUsers_ServiceError() {
 	limit := 10
 	offset := 0
 
-	s.userService.On("ListUsers", limit, offset).Return(nil, errors.New("database error"))
+	s.userService.On("ListUsers", mock.Anything, limit, offset).Return(nil, errors.New("database error"))
 
 	app := fiber.New()
 	app.Get("/users", s.userHandler.ListUsers)
@@ -408,7 +634,7 @@ func (s *HandlerTestSuite) TestGetUserProfile_Success() {
 		UpdatedAt: time.Now(),
 	}
 
-	s.userService.On("GetUser", userID).Return(expectedResponse, nil)
+	s.userService.On("GetUser", mock.Anything, userID).Return(expectedResponse, nil)
 
 	app := fiber.New()
 	app.Get("/users/:id/profile", s.userHandler.GetUserProfile)
@@ -422,6 +648,29 @@ func (s *HandlerTestSuite) TestGetUserProfile_Success() {
 	s.userService.AssertExpectations(s.T())
 }
 
+func (s *HandlerTestSuite) TestGetUserProfile_NotModified() {
+	userID := uint(1)
+	expectedResponse := &model.UserResponse{
+		ID:       userID,
+		Username: "testuser",
+		Version:  5,
+	}
+
+	s.userService.On("GetUser", mock.Anything, userID).Return(expectedResponse, nil)
+
+	app := fiber.New()
+	app.Get("/users/:id/profile", s.userHandler.GetUserProfile)
+
+	req := httptest.NewRequest("GET", "/users/1/profile", nil)
+	req.Header.Set("If-None-Match", `"5"`)
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotModified, resp.StatusCode)
+	s.userService.AssertExpectations(s.T())
+}
+
 func (s *HandlerTestSuite) TestGetUserProfile_InvalidID() {
 	app := fiber.New()
 	app.Get("/users/:id/profile", s.userHandler.GetUserProfile)
@@ -436,7 +685,7 @@ func (s *HandlerTestSuite) TestGetUserProfile_InvalidID() {
 
 func (s *HandlerTestSuite) TestGetUserProfile_NotFound() {
 	userID := uint(999)
-	s.userService.On("GetUser", userID).Return(nil, errors.New("user not found"))
+	s.userService.On("GetUser", mock.Anything, userID).Return(nil, errors.New("user not found"))
 
 	app := fiber.New()
 	app.Get("/users/:id/profile", s.userHandler.GetUserProfile)
@@ -466,7 +715,7 @@ func (s *HandlerTestSuite) TestUpdateUserProfile_Success() {
 		UpdatedAt: time.Now(),
 	}
 
-	s.userService.On("UpdateUser", userID, req).Return(expectedResponse, nil)
+	s.userService.On("UpdateUser", mock.Anything, userID, uint(0), req).Return(expectedResponse, nil)
 
 	app := fiber.New()
 	app.Put("/users/:id/profile", s.userHandler.UpdateUserProfile)
@@ -532,7 +781,7 @@ func (s *HandlerTestSuite) TestUpdateUserProfile_ServiceError() {
 		Username: "updateduser",
 	}
 
-	s.userService.On("UpdateUser", userID, req).Return(nil, errors.New("username already exists"))
+	s.userService.On("UpdateUser", mock.Anything, userID, uint(0), req).Return(nil, errors.New("username already exists"))
 
 	app := fiber.New()
 	app.Put("/users/:id/profile", s.userHandler.UpdateUserProfile)
@@ -547,3 +796,353 @@ func (s *HandlerTestSuite) TestUpdateUserProfile_ServiceError() {
 	assert.Equal(s.T(), fiber.StatusConflict, resp.StatusCode)
 	s.userService.AssertExpectations(s.T())
 }
+
+// Test PatchUser handler
+func (s *HandlerTestSuite) patchUser(contentType, body string, headers map[string]string) (*fiber.App, int, map[string]interface{}, string) {
+	app := fiber.New()
+	app.Patch("/users/:id", s.userHandler.PatchUser)
+
+	req := httptest.NewRequest("PATCH", "/users/1", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", contentType)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := app.Test(req)
+	assert.NoError(s.T(), err)
+
+	var result map[string]interface{}
+	respBody, _ := io.ReadAll(resp.Body)
+	json.Unmarshal(respBody, &result)
+
+	return app, resp.StatusCode, result, resp.Header.Get("ETag")
+}
+
+func (s *HandlerTestSuite) existingUser() *model.UserResponse {
+	return &model.UserResponse{
+		ID:        1,
+		Username:  "testuser",
+		Email:     "test@example.com",
+		Version:   2,
+		CreatedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func (s *HandlerTestSuite) TestPatchUser_MergePatch() {
+	userID := uint(1)
+	existing := s.existingUser()
+
+	s.userService.On("GetUser", mock.Anything, userID).Return(existing, nil)
+	s.userService.On("UpdateUser", mock.Anything, userID, uint(2), &model.UpdateUserRequest{
+		Username: "testuser",
+		Email:    "new@example.com",
+	}).Return(&model.UserResponse{ID: userID, Username: "testuser", Email: "new@example.com", Version: 3}, nil)
+
+	_, status, body, etag := s.patchUser("application/merge-patch+json", `{"email":"new@example.com"}`, nil)
+
+	assert.Equal(s.T(), fiber.StatusOK, status)
+	assert.Equal(s.T(), "new@example.com", body["email"])
+	assert.Equal(s.T(), `"3"`, etag)
+	s.userService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestPatchUser_JSONPatch() {
+	userID := uint(1)
+	existing := s.existingUser()
+
+	s.userService.On("GetUser", mock.Anything, userID).Return(existing, nil)
+	s.userService.On("UpdateUser", mock.Anything, userID, uint(2), &model.UpdateUserRequest{
+		Username: "renamed",
+		Email:    "test@example.com",
+	}).Return(&model.UserResponse{ID: userID, Username: "renamed", Email: "test@example.com", Version: 3}, nil)
+
+	patch := `[
+		{"op": "test", "path": "/username", "value": "testuser"},
+		{"op": "replace", "path": "/username", "value": "renamed"}
+	]`
+	_, status, body, _ := s.patchUser("application/json-patch+json", patch, nil)
+
+	assert.Equal(s.T(), fiber.StatusOK, status)
+	assert.Equal(s.T(), "renamed", body["username"])
+	s.userService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestPatchUser_UnsupportedContentType() {
+	_, status, _, _ := s.patchUser("application/json", `{"email":"new@example.com"}`, nil)
+
+	assert.Equal(s.T(), fiber.StatusUnsupportedMediaType, status)
+	s.userService.AssertNotCalled(s.T(), "GetUser")
+}
+
+func (s *HandlerTestSuite) TestPatchUser_InvalidID() {
+	app := fiber.New()
+	app.Patch("/users/:id", s.userHandler.PatchUser)
+
+	req := httptest.NewRequest("PATCH", "/users/invalid", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+
+	resp, err := app.Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestPatchUser_NotFound() {
+	s.userService.On("GetUser", mock.Anything, uint(1)).Return(nil, errors.New("user not found"))
+
+	_, status, _, _ := s.patchUser("application/merge-patch+json", `{"email":"new@example.com"}`, nil)
+
+	assert.Equal(s.T(), fiber.StatusNotFound, status)
+	s.userService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestPatchUser_ReadOnlyField() {
+	s.userService.On("GetUser", mock.Anything, uint(1)).Return(s.existingUser(), nil)
+
+	_, status, body, _ := s.patchUser("application/merge-patch+json", `{"id": 5}`, nil)
+
+	assert.Equal(s.T(), fiber.StatusUnprocessableEntity, status)
+	assert.Equal(s.T(), `Field "id" is read-only`, body["error"])
+	s.userService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestPatchUser_RemoveReadOnlyField() {
+	s.userService.On("GetUser", mock.Anything, uint(1)).Return(s.existingUser(), nil)
+
+	_, status, body, _ := s.patchUser("application/json-patch+json", `[{"op": "remove", "path": "/created_at"}]`, nil)
+
+	assert.Equal(s.T(), fiber.StatusUnprocessableEntity, status)
+	assert.Equal(s.T(), `Field "created_at" is read-only`, body["error"])
+	s.userService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestPatchUser_UnknownField() {
+	s.userService.On("GetUser", mock.Anything, uint(1)).Return(s.existingUser(), nil)
+
+	_, status, body, _ := s.patchUser("application/merge-patch+json", `{"password": "secret"}`, nil)
+
+	assert.Equal(s.T(), fiber.StatusUnprocessableEntity, status)
+	assert.Equal(s.T(), `Unknown field "password"`, body["error"])
+	s.userService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestPatchUser_FailedTestOperation() {
+	s.userService.On("GetUser", mock.Anything, uint(1)).Return(s.existingUser(), nil)
+
+	patch := `[{"op": "test", "path": "/username", "value": "someoneelse"}]`
+	_, status, _, _ := s.patchUser("application/json-patch+json", patch, nil)
+
+	assert.Equal(s.T(), fiber.StatusUnprocessableEntity, status)
+	s.userService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestPatchUser_MalformedPatch() {
+	s.userService.On("GetUser", mock.Anything, uint(1)).Return(s.existingUser(), nil)
+
+	_, status, _, _ := s.patchUser("application/json-patch+json", `{"op": "replace"}`, nil)
+
+	assert.Equal(s.T(), fiber.StatusBadRequest, status)
+	s.userService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestPatchUser_ClearingRequiredFieldFailsValidation() {
+	s.userService.On("GetUser", mock.Anything, uint(1)).Return(s.existingUser(), nil)
+
+	_, status, _, _ := s.patchUser("application/merge-patch+json", `{"username": null}`, nil)
+
+	assert.Equal(s.T(), fiber.StatusBadRequest, status)
+	s.userService.AssertNotCalled(s.T(), "UpdateUser")
+	s.userService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestPatchUser_InvalidEmail() {
+	s.userService.On("GetUser", mock.Anything, uint(1)).Return(s.existingUser(), nil)
+
+	_, status, _, _ := s.patchUser("application/merge-patch+json", `{"email": "not-an-email"}`, nil)
+
+	assert.Equal(s.T(), fiber.StatusBadRequest, status)
+	s.userService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestPatchUser_IfMatchMismatch() {
+	s.userService.On("GetUser", mock.Anything, uint(1)).Return(s.existingUser(), nil)
+
+	_, status, _, _ := s.patchUser("application/merge-patch+json", `{"email":"new@example.com"}`, map[string]string{
+		"If-Match": `"1"`,
+	})
+
+	assert.Equal(s.T(), fiber.StatusPreconditionFailed, status)
+	s.userService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestPatchUser_ConcurrentModification() {
+	userID := uint(1)
+
+	s.userService.On("GetUser", mock.Anything, userID).Return(s.existingUser(), nil)
+	s.userService.On("UpdateUser", mock.Anything, userID, uint(2), &model.UpdateUserRequest{
+		Username: "testuser",
+		Email:    "new@example.com",
+	}).Return(nil, service.ErrVersionMismatch)
+
+	_, status, _, _ := s.patchUser("application/merge-patch+json", `{"email":"new@example.com"}`, nil)
+
+	assert.Equal(s.T(), fiber.StatusPreconditionFailed, status)
+	s.userService.AssertExpectations(s.T())
+}
+
+func (s *HandlerTestSuite) TestPatchUser_ServiceError() {
+	userID := uint(1)
+
+	s.userService.On("GetUser", mock.Anything, userID).Return(s.existingUser(), nil)
+	s.userService.On("UpdateUser", mock.Anything, userID, uint(2), &model.UpdateUserRequest{
+		Username: "taken",
+		Email:    "test@example.com",
+	}).Return(nil, errors.New("username already exists"))
+
+	_, status, _, _ := s.patchUser("application/merge-patch+json", `{"username":"taken"}`, nil)
+
+	assert.Equal(s.T(), fiber.StatusConflict, status)
+	s.userService.AssertExpectations(s.T())
+}
+
+// Test GetUser handler with as_of
+func (s *HandlerTestSuite) TestGetUser_AsOf() {
+	at := time.Date(2025, 11, 15, 0, 0, 0, 0, time.UTC)
+	s.userService.On("GetUserAsOf", mock.Anything, uint(1), at).Return(&model.UserResponse{ID: 1, Username: "earlier", Version: 2}, nil)
+
+	app := fiber.New()
+	app.Get("/users/:id", s.userHandler.GetUser)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/1?as_of=2025-11-15T00:00:00Z", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	assert.Empty(s.T(), resp.Header.Get(fiber.HeaderETag))
+
+	var result model.UserResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), "earlier", result.Username)
+	s.userService.AssertNotCalled(s.T(), "GetUser", mock.Anything, mock.Anything)
+}
+
+func (s *HandlerTestSuite) TestGetUser_AsOfBeforeCreation() {
+	s.userService.On("GetUserAsOf", mock.Anything, uint(1), mock.Anything).Return(nil, service.ErrUserVersionNotFound)
+
+	app := fiber.New()
+	app.Get("/users/:id", s.userHandler.GetUser)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/1?as_of=2020-01-01T00:00:00Z", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestGetUser_InvalidAsOf() {
+	app := fiber.New()
+	app.Get("/users/:id", s.userHandler.GetUser)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/1?as_of=yesterday", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// Test ListUserHistory handler
+func (s *HandlerTestSuite) TestListUserHistory_Success() {
+	s.userService.On("ListUserVersions", mock.Anything, uint(1), 5, 0).Return([]*model.UserVersionResponse{
+		{Version: 2, Username: "renamed"},
+		{Version: 1, Username: "testuser"},
+	}, nil)
+
+	app := fiber.New()
+	app.Get("/users/:id/history", s.userHandler.ListUserHistory)
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/users/1/history?limit=5", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Versions []model.UserVersionResponse `json:"versions"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Len(s.T(), result.Versions, 2)
+}
+
+// Test RevertUser handler
+func (s *HandlerTestSuite) newRevertApp() *fiber.App {
+	app := fiber.New()
+	app.Post("/users/:id/history/:version/revert", s.userHandler.RevertUser)
+	return app
+}
+
+func (s *HandlerTestSuite) TestRevertUser_Success() {
+	s.userService.On("GetUserVersion", mock.Anything, uint(1), uint(2)).Return(&model.UserVersionResponse{
+		Version:  2,
+		Username: "earlier",
+		Email:    "earlier@example.com",
+	}, nil)
+	s.userService.On("UpdateUser", mock.Anything, uint(1), uint(4), &model.UpdateUserRequest{
+		Username: "earlier",
+		Email:    "earlier@example.com",
+	}).Return(&model.UserResponse{ID: 1, Username: "earlier", Version: 5}, nil)
+
+	req := httptest.NewRequest("POST", "/users/1/history/2/revert", nil)
+	req.Header.Set(fiber.HeaderIfMatch, `"4"`)
+
+	resp, err := s.newRevertApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	assert.Equal(s.T(), `"5"`, resp.Header.Get(fiber.HeaderETag))
+}
+
+func (s *HandlerTestSuite) TestRevertUser_UnknownVersion() {
+	s.userService.On("GetUserVersion", mock.Anything, uint(1), uint(9)).Return(nil, service.ErrUserVersionNotFound)
+
+	resp, err := s.newRevertApp().Test(httptest.NewRequest("POST", "/users/1/history/9/revert", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+	s.userService.AssertNotCalled(s.T(), "UpdateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *HandlerTestSuite) TestRevertUser_FailsValidation() {
+	// Versions from before a validation rule was tightened are not restored
+	s.userService.On("GetUserVersion", mock.Anything, uint(1), uint(1)).Return(&model.UserVersionResponse{
+		Version:  1,
+		Username: "ab",
+		Email:    "ab@example.com",
+	}, nil)
+
+	resp, err := s.newRevertApp().Test(httptest.NewRequest("POST", "/users/1/history/1/revert", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+	s.userService.AssertNotCalled(s.T(), "UpdateUser", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *HandlerTestSuite) TestRevertUser_Conflicts() {
+	cases := []struct {
+		err    error
+		status int
+	}{
+		{service.ErrVersionMismatch, fiber.StatusPreconditionFailed},
+		{service.ErrUsernameTaken, fiber.StatusConflict},
+	}
+	for _, tc := range cases {
+		s.userService.ExpectedCalls = nil
+		s.userService.On("GetUserVersion", mock.Anything, uint(1), uint(2)).Return(&model.UserVersionResponse{
+			Version:  2,
+			Username: "earlier",
+			Email:    "earlier@example.com",
+		}, nil)
+		s.userService.On("UpdateUser", mock.Anything, uint(1), uint(0), mock.Anything).Return(nil, tc.err)
+
+		resp, err := s.newRevertApp().Test(httptest.NewRequest("POST", "/users/1/history/2/revert", nil))
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), tc.status, resp.StatusCode, tc.err.Error())
+	}
+}
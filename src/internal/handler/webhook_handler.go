@@ -0,0 +1,348 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=WebhookHandler --output=./mocks/handler --outpkg=handler --filename=webhook_handler.go --structname=MockWebhookHandler --with-expecter=false
+type WebhookHandler interface {
+	CreateWebhook(c *fiber.Ctx) error
+	ListWebhooks(c *fiber.Ctx) error
+	GetWebhook(c *fiber.Ctx) error
+	UpdateWebhook(c *fiber.Ctx) error
+	DeleteWebhook(c *fiber.Ctx) error
+	ListDeliveries(c *fiber.Ctx) error
+	GetDelivery(c *fiber.Ctx) error
+	Redeliver(c *fiber.Ctx) error
+}
+
+type webhookHandlerImpl struct {
+	webhookService service.WebhookService
+	validator      *validator.Validate
+}
+
+func NewWebhookHandler(webhookService service.WebhookService) WebhookHandler {
+	return &webhookHandlerImpl{
+		webhookService: webhookService,
+		validator:      validator.New(),
+	}
+}
+
+// CreateWebhook creates a webhook
+// @Summary Create webhook
+// @Description Subscribe an endpoint of the organization of the request to events, or to all of them with "*". Each delivery is a POST of the event signed with the secret in the X-Webhook-Signature header. A secret is generated unless one is given; either way it is only shown in this response. Needs the webhooks:manage permission.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param webhook body model.CreateWebhookRequest true "Webhook"
+// @Success 201 {object} model.CreateWebhookResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /webhooks [post]
+func (h *webhookHandlerImpl) CreateWebhook(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	var req model.CreateWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(c.UserContext(), &req)
+	if err != nil {
+		return h.webhookError(c, err, "Failed to create webhook")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(webhook)
+}
+
+// ListWebhooks lists webhooks
+// @Summary List webhooks
+// @Description List the webhooks of the organization of the request. Needs the webhooks:manage permission.
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Success 200 {array} model.WebhookResponse
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /webhooks [get]
+func (h *webhookHandlerImpl) ListWebhooks(c *fiber.Ctx) error {
+	webhooks, err := h.webhookService.ListWebhooks(c.UserContext())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch webhooks",
+		})
+	}
+
+	return c.JSON(webhooks)
+}
+
+// GetWebhook gets a webhook
+// @Summary Get webhook
+// @Description Get a webhook. Needs the webhooks:manage permission.
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Webhook ID"
+// @Success 200 {object} model.WebhookResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /webhooks/{id} [get]
+func (h *webhookHandlerImpl) GetWebhook(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid webhook ID",
+		})
+	}
+
+	webhook, err := h.webhookService.GetWebhook(c.UserContext(), uint(id))
+	if err != nil {
+		return h.webhookError(c, err, "Failed to fetch webhook")
+	}
+
+	return c.JSON(webhook)
+}
+
+// UpdateWebhook updates a webhook
+// @Summary Update webhook
+// @Description Replace the URL, event types and description of a webhook. enabled turns it on or off; enabling a webhook that was disabled after failing resumes its pending deliveries. A secret replaces the current one. Needs the webhooks:manage permission.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Webhook ID"
+// @Param webhook body model.UpdateWebhookRequest true "Webhook"
+// @Success 200 {object} model.WebhookResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /webhooks/{id} [put]
+func (h *webhookHandlerImpl) UpdateWebhook(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid webhook ID",
+		})
+	}
+
+	var req model.UpdateWebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(c.UserContext(), uint(id), &req)
+	if err != nil {
+		return h.webhookError(c, err, "Failed to update webhook")
+	}
+
+	return c.JSON(webhook)
+}
+
+// DeleteWebhook deletes a webhook
+// @Summary Delete webhook
+// @Description Delete a webhook with its deliveries. Needs the webhooks:manage permission.
+// @Tags webhooks
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Webhook ID"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /webhooks/{id} [delete]
+func (h *webhookHandlerImpl) DeleteWebhook(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid webhook ID",
+		})
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.UserContext(), uint(id)); err != nil {
+		return h.webhookError(c, err, "Failed to delete webhook")
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// ListDeliveries lists the deliveries of a webhook
+// @Summary List webhook deliveries
+// @Description List the deliveries of a webhook newest first, with the response status and error of their last attempt. Deliveries that failed every attempt have the status failed. Needs the webhooks:manage permission.
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Webhook ID"
+// @Param status query string false "pending, succeeded or failed"
+// @Param limit query int false "Limit"
+// @Param offset query int false "Offset"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /webhooks/{id}/deliveries [get]
+func (h *webhookHandlerImpl) ListDeliveries(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid webhook ID",
+		})
+	}
+
+	filter := &model.WebhookDeliveryFilter{Status: c.Query("status")}
+	switch filter.Status {
+	case "", model.WebhookDeliveryPending, model.WebhookDeliverySucceeded, model.WebhookDeliveryFailed:
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Status must be pending, succeeded or failed",
+		})
+	}
+
+	limit := 10 // default
+	offset := 0 // default
+
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	if o, err := strconv.Atoi(c.Query("offset")); err == nil && o >= 0 {
+		offset = o
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(c.UserContext(), uint(id), filter, limit, offset)
+	if err != nil {
+		return h.webhookError(c, err, "Failed to fetch webhook deliveries")
+	}
+
+	return c.JSON(fiber.Map{
+		"deliveries": deliveries,
+		"limit":      limit,
+		"offset":     offset,
+	})
+}
+
+// GetDelivery gets a webhook delivery
+// @Summary Get webhook delivery
+// @Description Get a delivery of a webhook with the log of its attempts, oldest first. Needs the webhooks:manage permission.
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Webhook ID"
+// @Param delivery_id path int true "Delivery ID"
+// @Success 200 {object} model.WebhookDeliveryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /webhooks/{id}/deliveries/{delivery_id} [get]
+func (h *webhookHandlerImpl) GetDelivery(c *fiber.Ctx) error {
+	webhookID, deliveryID, err := deliveryIDs(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	delivery, err := h.webhookService.GetDelivery(c.UserContext(), webhookID, deliveryID)
+	if err != nil {
+		return h.webhookError(c, err, "Failed to fetch webhook delivery")
+	}
+
+	return c.JSON(delivery)
+}
+
+// Redeliver sends a webhook delivery again
+// @Summary Redeliver webhook delivery
+// @Description Queue a delivery to be sent again, whatever its status, with a fresh set of attempts. Deliveries of a disabled webhook wait until it is enabled. Needs the webhooks:manage permission.
+// @Tags webhooks
+// @Produce json
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Param id path int true "Webhook ID"
+// @Param delivery_id path int true "Delivery ID"
+// @Success 202 {object} model.WebhookDeliveryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (h *webhookHandlerImpl) Redeliver(c *fiber.Ctx) error {
+	webhookID, deliveryID, err := deliveryIDs(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	delivery, err := h.webhookService.Redeliver(c.UserContext(), webhookID, deliveryID)
+	if err != nil {
+		return h.webhookError(c, err, "Failed to redeliver webhook delivery")
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(delivery)
+}
+
+func (h *webhookHandlerImpl) webhookError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, service.ErrWebhookNotFound),
+		errors.Is(err, service.ErrWebhookDeliveryNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case errors.Is(err, service.ErrInvalidWebhookURL):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": message,
+		})
+	}
+}
+
+// deliveryIDs reads the webhook and delivery IDs from the path.
+func deliveryIDs(c *fiber.Ctx) (uint, uint, error) {
+	webhookID, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		return 0, 0, errors.New("Invalid webhook ID")
+	}
+	deliveryID, err := strconv.ParseUint(c.Params("delivery_id"), 10, 32)
+	if err != nil {
+		return 0, 0, errors.New("Invalid delivery ID")
+	}
+	return uint(webhookID), uint(deliveryID), nil
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+)
+
+func (s *HandlerTestSuite) newWebhookApp() *fiber.App {
+	app := fiber.New()
+	app.Post("/webhooks", s.webhookHandler.CreateWebhook)
+	app.Put("/webhooks/:id", s.webhookHandler.UpdateWebhook)
+	app.Delete("/webhooks/:id", s.webhookHandler.DeleteWebhook)
+	app.Get("/webhooks/:id/deliveries", s.webhookHandler.ListDeliveries)
+	app.Get("/webhooks/:id/deliveries/:delivery_id", s.webhookHandler.GetDelivery)
+	app.Post("/webhooks/:id/deliveries/:delivery_id/redeliver", s.webhookHandler.Redeliver)
+	return app
+}
+
+// Test CreateWebhook handler
+func (s *HandlerTestSuite) TestCreateWebhook_Success() {
+	req := model.CreateWebhookRequest{URL: "https://example.com/hooks", EventTypes: []string{"user.created"}}
+	s.webhooks.On("CreateWebhook", mock.Anything, &req).Return(&model.CreateWebhookResponse{
+		WebhookResponse: model.WebhookResponse{ID: 1, URL: req.URL, Enabled: true},
+		Secret:          "generated-secret",
+	}, nil)
+
+	body, _ := json.Marshal(req)
+	httpReq := httptest.NewRequest("POST", "/webhooks", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := s.newWebhookApp().Test(httpReq)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusCreated, resp.StatusCode)
+
+	var result model.CreateWebhookResponse
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Equal(s.T(), "generated-secret", result.Secret)
+}
+
+func (s *HandlerTestSuite) TestCreateWebhook_UnknownEventType() {
+	body := []byte(`{"url":"https://example.com/hooks","event_types":["user.exploded"]}`)
+	httpReq := httptest.NewRequest("POST", "/webhooks", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := s.newWebhookApp().Test(httpReq)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+	s.webhooks.AssertNotCalled(s.T(), "CreateWebhook", mock.Anything, mock.Anything)
+}
+
+func (s *HandlerTestSuite) TestCreateWebhook_InvalidURL() {
+	s.webhooks.On("CreateWebhook", mock.Anything, mock.Anything).Return(nil, service.ErrInvalidWebhookURL)
+
+	body := []byte(`{"url":"ftp://example.com/hooks","event_types":["*"]}`)
+	httpReq := httptest.NewRequest("POST", "/webhooks", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := s.newWebhookApp().Test(httpReq)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// Test UpdateWebhook handler
+func (s *HandlerTestSuite) TestUpdateWebhook_NotFound() {
+	s.webhooks.On("UpdateWebhook", mock.Anything, uint(9), mock.Anything).Return(nil, service.ErrWebhookNotFound)
+
+	body := []byte(`{"url":"https://example.com/hooks","event_types":["*"],"enabled":true}`)
+	httpReq := httptest.NewRequest("PUT", "/webhooks/9", bytes.NewReader(body))
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := s.newWebhookApp().Test(httpReq)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
+
+// Test DeleteWebhook handler
+func (s *HandlerTestSuite) TestDeleteWebhook_Success() {
+	s.webhooks.On("DeleteWebhook", mock.Anything, uint(1)).Return(nil)
+
+	resp, err := s.newWebhookApp().Test(httptest.NewRequest("DELETE", "/webhooks/1", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNoContent, resp.StatusCode)
+}
+
+// Test ListDeliveries handler
+func (s *HandlerTestSuite) TestListDeliveries_Filter() {
+	filter := &model.WebhookDeliveryFilter{Status: model.WebhookDeliveryFailed}
+	s.webhooks.On("ListDeliveries", mock.Anything, uint(1), filter, 20, 0).
+		Return([]*model.WebhookDeliveryResponse{{ID: 4, Status: model.WebhookDeliveryFailed, LastResponseStatus: 500}}, nil)
+
+	resp, err := s.newWebhookApp().Test(httptest.NewRequest("GET", "/webhooks/1/deliveries?status=failed&limit=20", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Deliveries []model.WebhookDeliveryResponse `json:"deliveries"`
+	}
+	json.NewDecoder(resp.Body).Decode(&result)
+	assert.Len(s.T(), result.Deliveries, 1)
+	assert.Equal(s.T(), 500, result.Deliveries[0].LastResponseStatus)
+}
+
+func (s *HandlerTestSuite) TestListDeliveries_InvalidStatus() {
+	resp, err := s.newWebhookApp().Test(httptest.NewRequest("GET", "/webhooks/1/deliveries?status=lost", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// Test GetDelivery handler
+func (s *HandlerTestSuite) TestGetDelivery_InvalidID() {
+	resp, err := s.newWebhookApp().Test(httptest.NewRequest("GET", "/webhooks/1/deliveries/abc", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusBadRequest, resp.StatusCode)
+}
+
+// Test Redeliver handler
+func (s *HandlerTestSuite) TestRedeliver_Success() {
+	s.webhooks.On("Redeliver", mock.Anything, uint(1), uint(4)).
+		Return(&model.WebhookDeliveryResponse{ID: 4, Status: model.WebhookDeliveryPending}, nil)
+
+	resp, err := s.newWebhookApp().Test(httptest.NewRequest("POST", "/webhooks/1/deliveries/4/redeliver", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusAccepted, resp.StatusCode)
+}
+
+func (s *HandlerTestSuite) TestRedeliver_NotFound() {
+	s.webhooks.On("Redeliver", mock.Anything, uint(1), uint(4)).Return(nil, service.ErrWebhookDeliveryNotFound)
+
+	resp, err := s.newWebhookApp().Test(httptest.NewRequest("POST", "/webhooks/1/deliveries/4/redeliver", nil))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
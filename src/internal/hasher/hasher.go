@@ -0,0 +1,98 @@
+package hasher
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+)
+
+// ErrUnknownHashFormat is returned when an encoded hash was not produced by
+// any of the supported algorithms.
+var ErrUnknownHashFormat = errors.New("unknown password hash format")
+
+// PasswordHasher hashes passwords into self-describing PHC strings such as
+// "$argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>" so that hashes produced by
+// different algorithms and parameters can be stored side by side.
+//
+//go:generate go run github.com/vektra/mockery/v2@latest --name=PasswordHasher --output=./mocks/hasher --outpkg=hasher --filename=hasher.go --structname=MockPasswordHasher --with-expecter=false
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, encoded string) (bool, error)
+	NeedsRehash(encoded string) bool
+}
+
+// algorithm is a PasswordHasher for a single scheme that can tell whether
+// an encoded hash belongs to it.
+type algorithm interface {
+	PasswordHasher
+	Matches(encoded string) bool
+}
+
+// NewPasswordHasher returns a hasher that hashes with the algorithm selected
+// by password.hasher.algorithm and verifies hashes of every supported
+// algorithm. Hashes of another algorithm or with outdated parameters are
+// reported by NeedsRehash.
+func NewPasswordHasher(conf *config.Config) (PasswordHasher, error) {
+	hasherConf := conf.Password.Hasher
+
+	bcryptHasher := newBcryptHasher(hasherConf.BcryptCost)
+	argon2idHasher := newArgon2idHasher(Argon2idParams{
+		Memory:      hasherConf.Argon2id.Memory,
+		Iterations:  hasherConf.Argon2id.Iterations,
+		Parallelism: hasherConf.Argon2id.Parallelism,
+		SaltLength:  hasherConf.Argon2id.SaltLength,
+		KeyLength:   hasherConf.Argon2id.KeyLength,
+	})
+
+	var preferred algorithm
+	switch strings.ToLower(hasherConf.Algorithm) {
+	case "bcrypt":
+		preferred = bcryptHasher
+	case "", "argon2id":
+		preferred = argon2idHasher
+	default:
+		return nil, fmt.Errorf("unknown password hashing algorithm %q", hasherConf.Algorithm)
+	}
+
+	return &multiHasher{
+		preferred:  preferred,
+		algorithms: []algorithm{argon2idHasher, bcryptHasher},
+	}, nil
+}
+
+// NewBcryptHasher returns a hasher that only supports bcrypt.
+func NewBcryptHasher(cost int) PasswordHasher {
+	return newBcryptHasher(cost)
+}
+
+// NewArgon2idHasher returns a hasher that only supports argon2id.
+func NewArgon2idHasher(params Argon2idParams) PasswordHasher {
+	return newArgon2idHasher(params)
+}
+
+type multiHasher struct {
+	preferred  algorithm
+	algorithms []algorithm
+}
+
+func (h *multiHasher) Hash(password string) (string, error) {
+	return h.preferred.Hash(password)
+}
+
+func (h *multiHasher) Verify(password, encoded string) (bool, error) {
+	for _, alg := range h.algorithms {
+		if alg.Matches(encoded) {
+			return alg.Verify(password, encoded)
+		}
+	}
+	return false, ErrUnknownHashFormat
+}
+
+func (h *multiHasher) NeedsRehash(encoded string) bool {
+	if !h.preferred.Matches(encoded) {
+		return true
+	}
+	return h.preferred.NeedsRehash(encoded)
+}
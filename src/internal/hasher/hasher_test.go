@@ -0,0 +1,170 @@
+package hasher
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type HasherTestSuite struct {
+	suite.Suite
+	conf *config.Config
+}
+
+func (s *HasherTestSuite) SetupTest() {
+	// Cheap parameters keep the suite fast
+	s.conf = &config.Config{
+		Password: config.PasswordConfig{
+			Hasher: config.PasswordHasherConfig{
+				Algorithm:  "argon2id",
+				BcryptCost: bcrypt.MinCost,
+				Argon2id: config.Argon2idConfig{
+					Memory:      64,
+					Iterations:  1,
+					Parallelism: 1,
+					SaltLength:  16,
+					KeyLength:   32,
+				},
+			},
+		},
+	}
+}
+
+func TestHasherSuite(t *testing.T) {
+	suite.Run(t, new(HasherTestSuite))
+}
+
+func (s *HasherTestSuite) newHasher() PasswordHasher {
+	h, err := NewPasswordHasher(s.conf)
+	s.Require().NoError(err)
+	return h
+}
+
+func (s *HasherTestSuite) TestArgon2id_PHCFormat() {
+	hash, err := s.newHasher().Hash("password123")
+
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$"))
+	assert.Len(s.T(), strings.Split(hash, "$"), 6)
+}
+
+func (s *HasherTestSuite) TestArgon2id_Verify() {
+	h := s.newHasher()
+	hash, _ := h.Hash("password123")
+
+	ok, err := h.Verify("password123", hash)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), ok)
+
+	ok, err = h.Verify("wrong", hash)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), ok)
+}
+
+func (s *HasherTestSuite) TestArgon2id_SaltsDiffer() {
+	h := s.newHasher()
+	first, _ := h.Hash("password123")
+	second, _ := h.Hash("password123")
+
+	assert.NotEqual(s.T(), first, second)
+}
+
+func (s *HasherTestSuite) TestBcrypt_Verify() {
+	s.conf.Password.Hasher.Algorithm = "bcrypt"
+	h := s.newHasher()
+	hash, err := h.Hash("password123")
+
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), strings.HasPrefix(hash, "$2a$04$"))
+	ok, err := h.Verify("password123", hash)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), ok)
+
+	ok, err = h.Verify("wrong", hash)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), ok)
+}
+
+func (s *HasherTestSuite) TestVerify_LegacyBcryptHash() {
+	legacy, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+
+	ok, err := s.newHasher().Verify("password123", string(legacy))
+
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), ok)
+}
+
+func (s *HasherTestSuite) TestVerify_UnknownFormat() {
+	ok, err := s.newHasher().Verify("password123", "plaintext")
+
+	assert.ErrorIs(s.T(), err, ErrUnknownHashFormat)
+	assert.False(s.T(), ok)
+}
+
+func (s *HasherTestSuite) TestVerify_MalformedArgon2id() {
+	ok, err := s.newHasher().Verify("password123", "$argon2id$v=19$m=64$broken")
+
+	assert.ErrorIs(s.T(), err, ErrUnknownHashFormat)
+	assert.False(s.T(), ok)
+}
+
+func (s *HasherTestSuite) TestNeedsRehash_OtherAlgorithm() {
+	legacy, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+
+	assert.True(s.T(), s.newHasher().NeedsRehash(string(legacy)))
+}
+
+func (s *HasherTestSuite) TestNeedsRehash_CurrentParameters() {
+	h := s.newHasher()
+	hash, _ := h.Hash("password123")
+
+	assert.False(s.T(), h.NeedsRehash(hash))
+}
+
+func (s *HasherTestSuite) TestNeedsRehash_OutdatedArgon2idParameters() {
+	hash, _ := s.newHasher().Hash("password123")
+
+	s.conf.Password.Hasher.Argon2id.Iterations = 2
+	h := s.newHasher()
+
+	assert.True(s.T(), h.NeedsRehash(hash))
+	// Old hashes keep verifying until they are upgraded
+	ok, err := h.Verify("password123", hash)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), ok)
+}
+
+func (s *HasherTestSuite) TestNeedsRehash_OutdatedBcryptCost() {
+	s.conf.Password.Hasher.Algorithm = "bcrypt"
+	hash, _ := s.newHasher().Hash("password123")
+
+	s.conf.Password.Hasher.BcryptCost = bcrypt.MinCost + 1
+
+	assert.True(s.T(), s.newHasher().NeedsRehash(hash))
+}
+
+func (s *HasherTestSuite) TestNewPasswordHasher_UnknownAlgorithm() {
+	s.conf.Password.Hasher.Algorithm = "md5"
+
+	h, err := NewPasswordHasher(s.conf)
+
+	assert.Error(s.T(), err)
+	assert.Nil(s.T(), h)
+}
+
+func (s *HasherTestSuite) TestSingleAlgorithmHashers() {
+	bcryptHasher := NewBcryptHasher(bcrypt.MinCost)
+	argon2idHasher := NewArgon2idHasher(Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1})
+
+	hash, _ := argon2idHasher.Hash("password123")
+	_, err := bcryptHasher.Verify("password123", hash)
+	assert.ErrorIs(s.T(), err, ErrUnknownHashFormat)
+
+	hash, _ = bcryptHasher.Hash("password123")
+	_, err = argon2idHasher.Verify("password123", hash)
+	assert.ErrorIs(s.T(), err, ErrUnknownHashFormat)
+}
@@ -0,0 +1,25 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/weeranieb/go-kit-base/src/internal/mailer"
+)
+
+const SendEmailType = "email.send"
+
+// SendEmail sends a plain-text email through the configured mailer, so a
+// request does not wait for the mail server.
+type SendEmail struct {
+	To      string `json:"to"`
+	Subject string `json:"subject"`
+	Body    string `json:"body"`
+}
+
+func (j SendEmail) JobType() string { return SendEmailType }
+
+func NewSendEmailHandler(mail mailer.Mailer) Handler {
+	return HandlerFunc(func(ctx context.Context, job SendEmail) error {
+		return mail.Send(&mailer.Message{To: job.To, Subject: job.Subject, Body: job.Body})
+	})
+}
@@ -0,0 +1,109 @@
+// Package jobs runs work outside of requests. A job is a typed value that
+// is enqueued through service.JobService and stored as JSON; the Worker
+// claims due jobs and hands each to the Handler registered for its type.
+// Handlers are provided to the container in the HandlerGroup value group.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// HandlerGroup is the dig value group the Worker collects its handlers
+// from.
+const HandlerGroup = "job_handlers"
+
+// Job is the payload of a job. Its type selects the handler that runs it.
+type Job interface {
+	JobType() string
+}
+
+// Handler runs the jobs of one type. An error fails the attempt, which is
+// retried with backoff unless the error is Permanent.
+type Handler interface {
+	JobType() string
+	Handle(ctx context.Context, payload []byte) error
+}
+
+// HandlerFunc returns a Handler for jobs of type J that decodes each
+// payload into a J and passes it to fn. A payload that does not decode
+// fails the job without retries.
+func HandlerFunc[J Job](fn func(ctx context.Context, job J) error) Handler {
+	return &handlerFunc[J]{fn: fn}
+}
+
+type handlerFunc[J Job] struct {
+	fn func(ctx context.Context, job J) error
+}
+
+func (h *handlerFunc[J]) JobType() string {
+	var job J
+	return job.JobType()
+}
+
+func (h *handlerFunc[J]) Handle(ctx context.Context, payload []byte) error {
+	var job J
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return Permanent(fmt.Errorf("decode %s payload: %w", job.JobType(), err))
+	}
+	return h.fn(ctx, job)
+}
+
+// Permanent marks err as one that retrying will not fix, so the job fails
+// at once.
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+func isPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// Option changes how a job is enqueued.
+type Option func(job *model.Job)
+
+// Delay runs the job no earlier than d from now.
+func Delay(d time.Duration) Option {
+	return func(job *model.Job) {
+		job.RunAt = time.Now().Add(d)
+	}
+}
+
+// At runs the job no earlier than t.
+func At(t time.Time) Option {
+	return func(job *model.Job) {
+		job.RunAt = t
+	}
+}
+
+// UniqueKey skips enqueuing the job while an unfinished job holds key.
+func UniqueKey(key string) Option {
+	return func(job *model.Job) {
+		job.UniqueKey = &key
+	}
+}
+
+// MaxAttempts replaces the configured number of attempts for the job.
+func MaxAttempts(n int) Option {
+	return func(job *model.Job) {
+		job.MaxAttempts = n
+	}
+}
@@ -0,0 +1,75 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weeranieb/go-kit-base/src/internal/mailer"
+	mailerMocks "github.com/weeranieb/go-kit-base/src/internal/mailer/mocks/mailer"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+func TestHandlerFunc(t *testing.T) {
+	var got SendEmail
+	handler := HandlerFunc(func(ctx context.Context, job SendEmail) error {
+		got = job
+		return nil
+	})
+
+	err := handler.Handle(context.Background(), []byte(`{"to":"test@example.com","subject":"Hi","body":"Hello"}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, SendEmailType, handler.JobType())
+	assert.Equal(t, SendEmail{To: "test@example.com", Subject: "Hi", Body: "Hello"}, got)
+}
+
+func TestHandlerFunc_InvalidPayload(t *testing.T) {
+	handler := HandlerFunc(func(ctx context.Context, job SendEmail) error {
+		t.Fatal("handler ran with an invalid payload")
+		return nil
+	})
+
+	err := handler.Handle(context.Background(), []byte(`{"to":3}`))
+
+	assert.Error(t, err)
+	assert.True(t, isPermanent(err))
+}
+
+func TestPermanent(t *testing.T) {
+	cause := errors.New("unknown recipient")
+
+	err := Permanent(cause)
+
+	assert.ErrorIs(t, err, cause)
+	assert.Equal(t, "unknown recipient", err.Error())
+	assert.True(t, isPermanent(err))
+	assert.False(t, isPermanent(cause))
+}
+
+func TestOptions(t *testing.T) {
+	job := &model.Job{MaxAttempts: 5}
+	at := time.Date(2025, 12, 24, 9, 0, 0, 0, time.UTC)
+
+	for _, opt := range []Option{At(at), UniqueKey("digest:1"), MaxAttempts(1)} {
+		opt(job)
+	}
+
+	assert.Equal(t, at, job.RunAt)
+	assert.Equal(t, "digest:1", *job.UniqueKey)
+	assert.Equal(t, 1, job.MaxAttempts)
+
+	Delay(time.Hour)(job)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), job.RunAt, time.Second)
+}
+
+func TestSendEmailHandler(t *testing.T) {
+	mail := mailerMocks.NewMockMailer(t)
+	mail.On("Send", &mailer.Message{To: "test@example.com", Subject: "Hi", Body: "Hello"}).Return(nil)
+
+	err := NewSendEmailHandler(mail).Handle(context.Background(), []byte(`{"to":"test@example.com","subject":"Hi","body":"Hello"}`))
+
+	assert.NoError(t, err)
+}
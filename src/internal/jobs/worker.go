@@ -0,0 +1,168 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
+	"go.uber.org/dig"
+)
+
+// Worker runs the jobs of the types it has handlers for. A job gets the
+// visibility timeout to finish; one enqueued within an organization runs
+// in a context scoped to it. A failed job is retried with exponential
+// backoff until it runs out of attempts, and a job interrupted by shutdown
+// counts as failed.
+type Worker struct {
+	jobRepo  repository.JobRepository
+	handlers map[string]Handler
+	types    []string
+	conf     config.JobsConfig
+}
+
+type WorkerParams struct {
+	dig.In
+
+	JobRepo  repository.JobRepository
+	Handlers []Handler `group:"job_handlers"`
+	Conf     *config.Config
+}
+
+func NewWorker(params WorkerParams) (*Worker, error) {
+	handlers := make(map[string]Handler, len(params.Handlers))
+	types := make([]string, 0, len(params.Handlers))
+	for _, handler := range params.Handlers {
+		jobType := handler.JobType()
+		if _, ok := handlers[jobType]; ok {
+			return nil, fmt.Errorf("job type %q has more than one handler", jobType)
+		}
+		handlers[jobType] = handler
+		types = append(types, jobType)
+	}
+	sort.Strings(types)
+
+	return &Worker{
+		jobRepo:  params.JobRepo,
+		handlers: handlers,
+		types:    types,
+		conf:     params.Conf.Jobs,
+	}, nil
+}
+
+// Run runs jobs on Concurrency goroutines until ctx is done. Each runs due
+// jobs back to back and checks again every poll interval once there are
+// none.
+func (w *Worker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < w.conf.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.loop(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (w *Worker) loop(ctx context.Context) {
+	ticker := time.NewTicker(w.conf.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			ran, err := w.Work(ctx)
+			if err != nil {
+				log.Printf("Failed to run jobs: %v", err)
+				break
+			}
+			if !ran {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Work claims a due job and runs it. It reports whether there was one.
+func (w *Worker) Work(ctx context.Context) (bool, error) {
+	claimed, err := w.jobRepo.WithContext(tenant.Unscoped(ctx)).Claim(w.types, 1, w.conf.VisibilityTimeout)
+	if err != nil || len(claimed) == 0 {
+		return false, err
+	}
+	job := claimed[0]
+
+	w.record(job, w.run(ctx, job))
+
+	// The outcome is stored even when the worker is shutting down
+	stored, err := w.jobRepo.WithContext(tenant.Unscoped(context.WithoutCancel(ctx))).Finish(job)
+	if err != nil {
+		return true, err
+	}
+	if !stored {
+		log.Printf("Dropped the outcome of job %d, which was cancelled or claimed again", job.ID)
+	}
+	return true, nil
+}
+
+// run hands the job to its handler, turning a panic into an error.
+func (w *Worker) run(ctx context.Context, job *model.Job) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, w.conf.VisibilityTimeout)
+	defer cancel()
+	if job.OrganizationID != 0 {
+		ctx = tenant.WithOrganization(ctx, job.OrganizationID)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return w.handlers[job.Type].Handle(ctx, []byte(job.Payload))
+}
+
+// record sets the state of the job after an attempt that returned err.
+func (w *Worker) record(job *model.Job, err error) {
+	now := time.Now()
+	switch {
+	case err == nil:
+		job.Status = model.JobSucceeded
+		job.LastError = ""
+		job.FinishedAt = &now
+	case isPermanent(err) || job.Attempts >= job.MaxAttempts:
+		log.Printf("Job %d (%s) failed for good after %d attempts: %v", job.ID, job.Type, job.Attempts, err)
+		job.Status = model.JobFailed
+		job.LastError = err.Error()
+		job.FinishedAt = &now
+	default:
+		job.Status = model.JobPending
+		job.LastError = err.Error()
+		job.RunAt = now.Add(w.backoff(job.Attempts))
+	}
+}
+
+// backoff returns how long to wait after the given number of failed
+// attempts: BaseBackoff, doubling with every further attempt up to
+// MaxBackoff.
+func (w *Worker) backoff(attempts int) time.Duration {
+	backoff := w.conf.BaseBackoff
+	for i := 1; i < attempts && backoff < w.conf.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > w.conf.MaxBackoff {
+		backoff = w.conf.MaxBackoff
+	}
+	return backoff
+}
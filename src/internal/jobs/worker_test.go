@@ -0,0 +1,194 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	mocks "github.com/weeranieb/go-kit-base/src/internal/repository/mocks/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+)
+
+// testJob is run by the handler of the suite, which returns s.err.
+type testJob struct {
+	Name string `json:"name"`
+}
+
+func (j testJob) JobType() string { return "test.run" }
+
+type WorkerTestSuite struct {
+	suite.Suite
+	jobRepo *mocks.MockJobRepository
+	worker  *Worker
+	ran     []testJob
+	ctx     context.Context
+	err     error
+	panics  bool
+}
+
+func (s *WorkerTestSuite) SetupTest() {
+	s.jobRepo = mocks.NewMockJobRepository(s.T())
+	s.jobRepo.On("WithContext", mock.Anything).Return(s.jobRepo).Maybe()
+	s.ran = nil
+	s.err = nil
+	s.panics = false
+
+	handler := HandlerFunc(func(ctx context.Context, job testJob) error {
+		if s.panics {
+			panic("boom")
+		}
+		s.ran = append(s.ran, job)
+		s.ctx = ctx
+		return s.err
+	})
+
+	var err error
+	s.worker, err = NewWorker(WorkerParams{
+		JobRepo:  s.jobRepo,
+		Handlers: []Handler{handler},
+		Conf: &config.Config{Jobs: config.JobsConfig{
+			Concurrency:       2,
+			PollInterval:      10 * time.Millisecond,
+			VisibilityTimeout: time.Minute,
+			BaseBackoff:       time.Second,
+			MaxBackoff:        time.Minute,
+		}},
+	})
+	if err != nil {
+		s.T().Fatal("Failed to create worker:", err)
+	}
+}
+
+func TestWorkerSuite(t *testing.T) {
+	suite.Run(t, new(WorkerTestSuite))
+}
+
+func newClaimedJob(attempts, maxAttempts int) *model.Job {
+	return &model.Job{
+		ID:          7,
+		Type:        "test.run",
+		Payload:     `{"name":"nightly"}`,
+		Status:      model.JobRunning,
+		Attempts:    attempts,
+		MaxAttempts: maxAttempts,
+	}
+}
+
+func (s *WorkerTestSuite) TestNewWorker_DuplicateHandler() {
+	handler := HandlerFunc(func(ctx context.Context, job testJob) error { return nil })
+
+	_, err := NewWorker(WorkerParams{JobRepo: s.jobRepo, Handlers: []Handler{handler, handler}, Conf: &config.Config{}})
+
+	assert.Error(s.T(), err)
+}
+
+func (s *WorkerTestSuite) TestWork_Nothing() {
+	s.jobRepo.On("Claim", []string{"test.run"}, 1, time.Minute).Return([]*model.Job{}, nil)
+
+	ran, err := s.worker.Work(context.Background())
+
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), ran)
+}
+
+func (s *WorkerTestSuite) TestWork_Succeeded() {
+	job := newClaimedJob(1, 3)
+	job.OrganizationID = 4
+	s.jobRepo.On("Claim", []string{"test.run"}, 1, time.Minute).Return([]*model.Job{job}, nil)
+	s.jobRepo.On("Finish", mock.MatchedBy(func(job *model.Job) bool {
+		return job.Status == model.JobSucceeded && job.FinishedAt != nil
+	})).Return(true, nil)
+
+	ran, err := s.worker.Work(context.Background())
+
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), ran)
+	assert.Equal(s.T(), []testJob{{Name: "nightly"}}, s.ran)
+	// The job runs in the organization it was enqueued in, within its timeout
+	organizationID, _ := tenant.OrganizationFromContext(s.ctx)
+	assert.Equal(s.T(), uint(4), organizationID)
+	_, hasDeadline := s.ctx.Deadline()
+	assert.True(s.T(), hasDeadline)
+}
+
+func (s *WorkerTestSuite) TestWork_Retried() {
+	s.err = errors.New("mail server unavailable")
+	s.jobRepo.On("Claim", []string{"test.run"}, 1, time.Minute).Return([]*model.Job{newClaimedJob(2, 3)}, nil)
+	s.jobRepo.On("Finish", mock.MatchedBy(func(job *model.Job) bool {
+		return job.Status == model.JobPending &&
+			job.LastError == "mail server unavailable" &&
+			job.FinishedAt == nil &&
+			time.Until(job.RunAt) > time.Second && time.Until(job.RunAt) <= 2*time.Second
+	})).Return(true, nil)
+
+	_, err := s.worker.Work(context.Background())
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *WorkerTestSuite) TestWork_OutOfAttempts() {
+	s.err = errors.New("mail server unavailable")
+	s.jobRepo.On("Claim", []string{"test.run"}, 1, time.Minute).Return([]*model.Job{newClaimedJob(3, 3)}, nil)
+	s.jobRepo.On("Finish", mock.MatchedBy(func(job *model.Job) bool {
+		return job.Status == model.JobFailed && job.FinishedAt != nil
+	})).Return(true, nil)
+
+	_, err := s.worker.Work(context.Background())
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *WorkerTestSuite) TestWork_Permanent() {
+	s.err = Permanent(errors.New("unknown recipient"))
+	s.jobRepo.On("Claim", []string{"test.run"}, 1, time.Minute).Return([]*model.Job{newClaimedJob(1, 3)}, nil)
+	s.jobRepo.On("Finish", mock.MatchedBy(func(job *model.Job) bool {
+		return job.Status == model.JobFailed && job.LastError == "unknown recipient"
+	})).Return(true, nil)
+
+	_, err := s.worker.Work(context.Background())
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *WorkerTestSuite) TestWork_Panic() {
+	s.panics = true
+	s.jobRepo.On("Claim", []string{"test.run"}, 1, time.Minute).Return([]*model.Job{newClaimedJob(1, 3)}, nil)
+	s.jobRepo.On("Finish", mock.MatchedBy(func(job *model.Job) bool {
+		return job.Status == model.JobPending && job.LastError == "job panicked: boom"
+	})).Return(true, nil)
+
+	_, err := s.worker.Work(context.Background())
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *WorkerTestSuite) TestRun_StopsWithContext() {
+	s.jobRepo.On("Claim", []string{"test.run"}, 1, time.Minute).Return([]*model.Job{}, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		s.worker.Run(ctx)
+		close(done)
+	}()
+	time.Sleep(30 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.T().Fatal("worker did not stop")
+	}
+}
+
+func (s *WorkerTestSuite) TestBackoff() {
+	assert.Equal(s.T(), time.Second, s.worker.backoff(1))
+	assert.Equal(s.T(), 4*time.Second, s.worker.backoff(3))
+	assert.Equal(s.T(), time.Minute, s.worker.backoff(20))
+}
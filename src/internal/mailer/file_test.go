@@ -0,0 +1,52 @@
+package mailer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+)
+
+func (s *MailerTestSuite) TestFileMailer_WritesEML() {
+	dir := filepath.Join(s.T().TempDir(), "mail")
+	m, err := NewMailer(&config.Config{Mail: config.MailConfig{Driver: "file", From: "no-reply@example.com", FileDir: dir}})
+	s.Require().NoError(err)
+
+	err = m.Send(&Message{To: "test@example.com", Subject: "Verify your email", Body: "Hello,\nclick the link.\n"})
+
+	assert.NoError(s.T(), err)
+	files, _ := os.ReadDir(dir)
+	s.Require().Len(files, 1)
+	assert.True(s.T(), strings.HasSuffix(files[0].Name(), ".eml"))
+
+	data, _ := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	content := string(data)
+	assert.Contains(s.T(), content, "From: no-reply@example.com\r\n")
+	assert.Contains(s.T(), content, "To: test@example.com\r\n")
+	assert.Contains(s.T(), content, "Subject: Verify your email\r\n")
+	assert.Contains(s.T(), content, "\r\n\r\nHello,\r\nclick the link.\r\n")
+}
+
+func (s *MailerTestSuite) TestFileMailer_OneFilePerMessage() {
+	dir := s.T().TempDir()
+	m := NewFileMailer("no-reply@example.com", dir)
+
+	assert.NoError(s.T(), m.Send(&Message{To: "a@example.com", Subject: "One", Body: "1"}))
+	assert.NoError(s.T(), m.Send(&Message{To: "b@example.com", Subject: "Two", Body: "2"}))
+
+	files, _ := os.ReadDir(dir)
+	assert.Len(s.T(), files, 2)
+}
+
+func (s *MailerTestSuite) TestFileMailer_RejectsHeaderInjection() {
+	dir := s.T().TempDir()
+	m := NewFileMailer("no-reply@example.com", dir)
+
+	err := m.Send(&Message{To: "test@example.com\r\nBcc: victim@example.com", Subject: "Hi", Body: "Hi"})
+
+	assert.ErrorIs(s.T(), err, errHeaderInjection)
+	files, _ := os.ReadDir(dir)
+	assert.Empty(s.T(), files)
+}
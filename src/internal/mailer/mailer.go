@@ -0,0 +1,87 @@
+package mailer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"mime/quotedprintable"
+	"strings"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+)
+
+var errHeaderInjection = errors.New("mail header must not contain line breaks")
+
+// Message is a plain-text email.
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// encode renders the message as an RFC 5322 document with a quoted-printable
+// UTF-8 body.
+func (msg *Message) encode(from string, date time.Time) ([]byte, error) {
+	for _, value := range []string{from, msg.To, msg.Subject} {
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, errHeaderInjection
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+	buf.WriteString("\r\n")
+
+	body := quotedprintable.NewWriter(&buf)
+	if _, err := body.Write([]byte(msg.Body)); err != nil {
+		return nil, err
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=Mailer --output=./mocks/mailer --outpkg=mailer --filename=mailer.go --structname=MockMailer --with-expecter=false
+type Mailer interface {
+	Send(msg *Message) error
+}
+
+// NewMailer returns the Mailer selected by mail.driver.
+func NewMailer(conf *config.Config) (Mailer, error) {
+	switch strings.ToLower(conf.Mail.Driver) {
+	case "", "log":
+		return NewLogMailer(conf.Mail.From), nil
+	case "file":
+		return NewFileMailer(conf.Mail.From, conf.Mail.FileDir), nil
+	case "smtp":
+		return NewSMTPMailer(conf.Mail.From, conf.Mail.SMTP), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", conf.Mail.Driver)
+	}
+}
+
+type logMailer struct {
+	from string
+}
+
+// NewLogMailer returns a Mailer that writes messages to the application log
+// instead of sending them. It is meant for local development.
+func NewLogMailer(from string) Mailer {
+	return &logMailer{from: from}
+}
+
+func (m *logMailer) Send(msg *Message) error {
+	log.Printf("Mail from=%s to=%s subject=%q\n%s", m.from, msg.To, msg.Subject, msg.Body)
+	return nil
+}
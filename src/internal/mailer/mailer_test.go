@@ -0,0 +1,31 @@
+package mailer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+)
+
+type MailerTestSuite struct {
+	suite.Suite
+}
+
+func TestMailerSuite(t *testing.T) {
+	suite.Run(t, new(MailerTestSuite))
+}
+
+func (s *MailerTestSuite) TestNewMailer_Log() {
+	m, err := NewMailer(&config.Config{Mail: config.MailConfig{Driver: "log", From: "no-reply@example.com"}})
+
+	assert.NoError(s.T(), err)
+	assert.NoError(s.T(), m.Send(&Message{To: "test@example.com", Subject: "Hello", Body: "Hi"}))
+}
+
+func (s *MailerTestSuite) TestNewMailer_UnknownDriver() {
+	m, err := NewMailer(&config.Config{Mail: config.MailConfig{Driver: "carrier-pigeon"}})
+
+	assert.Error(s.T(), err)
+	assert.Nil(s.T(), m)
+}
@@ -0,0 +1,40 @@
+package mailer
+
+import (
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+)
+
+type smtpMailer struct {
+	from string
+	addr string
+	auth smtp.Auth
+}
+
+// NewSMTPMailer returns a Mailer that delivers messages to an SMTP server.
+// STARTTLS is used when the server offers it. Credentials are optional.
+func NewSMTPMailer(from string, conf config.SMTPConfig) Mailer {
+	var auth smtp.Auth
+	if conf.Username != "" {
+		auth = smtp.PlainAuth("", conf.Username, conf.Password, conf.Host)
+	}
+
+	return &smtpMailer{
+		from: from,
+		addr: net.JoinHostPort(conf.Host, strconv.Itoa(conf.Port)),
+		auth: auth,
+	}
+}
+
+func (m *smtpMailer) Send(msg *Message) error {
+	data, err := msg.encode(m.from, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, data)
+}
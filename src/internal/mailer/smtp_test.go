@@ -0,0 +1,166 @@
+package mailer
+
+import (
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+)
+
+// fakeSMTPServer is a minimal SMTP server that accepts every message and
+// keeps it for inspection.
+type fakeSMTPServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages []fakeSMTPMessage
+	wg       sync.WaitGroup
+}
+
+type fakeSMTPMessage struct {
+	From string
+	To   []string
+	Data string
+}
+
+func newFakeSMTPServer() (*fakeSMTPServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	server := &fakeSMTPServer{listener: listener}
+	server.wg.Add(1)
+	go server.serve()
+	return server, nil
+}
+
+func (f *fakeSMTPServer) config() config.SMTPConfig {
+	addr := f.listener.Addr().(*net.TCPAddr)
+	return config.SMTPConfig{Host: addr.IP.String(), Port: addr.Port}
+}
+
+func (f *fakeSMTPServer) Close() {
+	f.listener.Close()
+	f.wg.Wait()
+}
+
+func (f *fakeSMTPServer) Messages() []fakeSMTPMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]fakeSMTPMessage(nil), f.messages...)
+}
+
+func (f *fakeSMTPServer) serve() {
+	defer f.wg.Done()
+	for {
+		conn, err := f.listener.Accept()
+		if err != nil {
+			return
+		}
+		f.wg.Add(1)
+		go func() {
+			defer f.wg.Done()
+			defer conn.Close()
+			f.handle(textproto.NewConn(conn))
+		}()
+	}
+}
+
+func (f *fakeSMTPServer) handle(conn *textproto.Conn) {
+	var msg fakeSMTPMessage
+
+	conn.PrintfLine("220 localhost fake SMTP")
+	for {
+		line, err := conn.ReadLine()
+		if err != nil {
+			return
+		}
+
+		verb := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+		switch verb {
+		case "EHLO", "HELO":
+			conn.PrintfLine("250 localhost")
+		case "MAIL":
+			msg = fakeSMTPMessage{From: extractAddress(line)}
+			conn.PrintfLine("250 OK")
+		case "RCPT":
+			msg.To = append(msg.To, extractAddress(line))
+			conn.PrintfLine("250 OK")
+		case "DATA":
+			conn.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			lines, err := conn.ReadDotLines()
+			if err != nil {
+				return
+			}
+			msg.Data = strings.Join(lines, "\n")
+			f.mu.Lock()
+			f.messages = append(f.messages, msg)
+			f.mu.Unlock()
+			conn.PrintfLine("250 OK")
+		case "QUIT":
+			conn.PrintfLine("221 Bye")
+			return
+		default:
+			conn.PrintfLine("250 OK")
+		}
+	}
+}
+
+func extractAddress(line string) string {
+	start := strings.Index(line, "<")
+	end := strings.LastIndex(line, ">")
+	if start < 0 || end < start {
+		return ""
+	}
+	return line[start+1 : end]
+}
+
+func (s *MailerTestSuite) TestSMTPMailer_Send() {
+	server, err := newFakeSMTPServer()
+	s.Require().NoError(err)
+	defer server.Close()
+
+	m, err := NewMailer(&config.Config{Mail: config.MailConfig{Driver: "smtp", From: "no-reply@example.com", SMTP: server.config()}})
+	s.Require().NoError(err)
+
+	err = m.Send(&Message{To: "test@example.com", Subject: "Verify your email", Body: "Hello,\nclick the link.\n"})
+
+	assert.NoError(s.T(), err)
+	messages := server.Messages()
+	s.Require().Len(messages, 1)
+	assert.Equal(s.T(), "no-reply@example.com", messages[0].From)
+	assert.Equal(s.T(), []string{"test@example.com"}, messages[0].To)
+	assert.Contains(s.T(), messages[0].Data, "Subject: Verify your email")
+	assert.Contains(s.T(), messages[0].Data, "Hello,\nclick the link.")
+}
+
+func (s *MailerTestSuite) TestSMTPMailer_EncodesUTF8Subject() {
+	server, err := newFakeSMTPServer()
+	s.Require().NoError(err)
+	defer server.Close()
+
+	m := NewSMTPMailer("no-reply@example.com", server.config())
+
+	err = m.Send(&Message{To: "test@example.com", Subject: "Vérifiez", Body: "Bonjour"})
+
+	assert.NoError(s.T(), err)
+	messages := server.Messages()
+	s.Require().Len(messages, 1)
+	assert.Contains(s.T(), messages[0].Data, "Subject: =?utf-8?q?V=C3=A9rifiez?=")
+}
+
+func (s *MailerTestSuite) TestSMTPMailer_ConnectionRefused() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.Require().NoError(err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	m := NewSMTPMailer("no-reply@example.com", config.SMTPConfig{Host: "127.0.0.1", Port: port})
+
+	err = m.Send(&Message{To: "test@example.com", Subject: "Hi", Body: "Hi"})
+
+	assert.Error(s.T(), err)
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"github.com/weeranieb/go-kit-base/src/internal/audit"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxRequestIDLength is the size of audit_events.request_id. Longer request
+// IDs sent by clients are cut.
+const maxRequestIDLength = 64
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=AuditMiddleware --output=./mocks/middleware --outpkg=middleware --filename=audit.go --structname=MockAuditMiddleware --with-expecter=false
+type AuditMiddleware interface {
+	Handle(c *fiber.Ctx) error
+}
+
+type auditMiddlewareImpl struct{}
+
+func NewAuditMiddleware() AuditMiddleware {
+	return &auditMiddlewareImpl{}
+}
+
+// Handle stores the address and request ID of the request in the user
+// context, from where the repositories record them with the changes it
+// makes. AuthMiddleware adds the caller once it is known. The request ID is
+// the one set by the requestid middleware.
+func (m *auditMiddlewareImpl) Handle(c *fiber.Ctx) error {
+	requestID := c.GetRespHeader(fiber.HeaderXRequestID, c.Get(fiber.HeaderXRequestID))
+	if len(requestID) > maxRequestIDLength {
+		requestID = requestID[:maxRequestIDLength]
+	}
+
+	c.SetUserContext(audit.WithSource(c.UserContext(), audit.Source{
+		IP:        c.IP(),
+		RequestID: requestID,
+	}))
+	return c.Next()
+}
+
+// withAuditActor adds the caller to the audit source of the request.
+func withAuditActor(c *fiber.Ctx, principal *model.Principal) {
+	source := audit.SourceFromContext(c.UserContext())
+	source.UserID = principal.UserID
+	source.APIKeyID = principal.APIKeyID
+	source.Service = principal.Service
+	c.SetUserContext(audit.WithSource(c.UserContext(), source))
+}
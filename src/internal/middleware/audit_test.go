@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
+	"github.com/stretchr/testify/assert"
+	"github.com/weeranieb/go-kit-base/src/internal/audit"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// newAuditApp returns an app that responds with the audit source of the
+// request.
+func (s *MiddlewareTestSuite) newAuditApp() *fiber.App {
+	app := fiber.New()
+	app.Use(requestid.New(), s.auditMiddleware.Handle)
+	app.Get("/source", func(c *fiber.Ctx) error {
+		return c.JSON(audit.SourceFromContext(c.UserContext()))
+	})
+	app.Get("/me/source", s.authMiddleware.Handle, func(c *fiber.Ctx) error {
+		return c.JSON(audit.SourceFromContext(c.UserContext()))
+	})
+	return app
+}
+
+func (s *MiddlewareTestSuite) TestAudit_Source() {
+	req := httptest.NewRequest("GET", "/source", nil)
+	req.Header.Set(fiber.HeaderXRequestID, "req-1")
+
+	resp, err := s.newAuditApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	var source audit.Source
+	json.NewDecoder(resp.Body).Decode(&source)
+	assert.Equal(s.T(), "req-1", source.RequestID)
+	assert.Equal(s.T(), "0.0.0.0", source.IP)
+	assert.Zero(s.T(), source.UserID)
+}
+
+func (s *MiddlewareTestSuite) TestAudit_GeneratedRequestID() {
+	resp, err := s.newAuditApp().Test(httptest.NewRequest("GET", "/source", nil))
+
+	assert.NoError(s.T(), err)
+	var source audit.Source
+	json.NewDecoder(resp.Body).Decode(&source)
+	assert.NotEmpty(s.T(), source.RequestID)
+	assert.Equal(s.T(), resp.Header.Get(fiber.HeaderXRequestID), source.RequestID)
+}
+
+func (s *MiddlewareTestSuite) TestAudit_LongRequestID() {
+	req := httptest.NewRequest("GET", "/source", nil)
+	req.Header.Set(fiber.HeaderXRequestID, strings.Repeat("a", 100))
+
+	resp, err := s.newAuditApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	var source audit.Source
+	json.NewDecoder(resp.Body).Decode(&source)
+	assert.Len(s.T(), source.RequestID, maxRequestIDLength)
+}
+
+func (s *MiddlewareTestSuite) TestAudit_Actor() {
+	s.apiKeyService.On("Authenticate", "gk_key").Return(&model.Principal{APIKeyID: 5, Service: "billing"}, nil)
+
+	req := httptest.NewRequest("GET", "/me/source", nil)
+	req.Header.Set(fiber.HeaderXRequestID, "req-1")
+	req.Header.Set("X-API-Key", "gk_key")
+
+	resp, err := s.newAuditApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	var source audit.Source
+	json.NewDecoder(resp.Body).Decode(&source)
+	assert.Equal(s.T(), uint(5), source.APIKeyID)
+	assert.Equal(s.T(), "billing", source.Service)
+	assert.Equal(s.T(), "req-1", source.RequestID)
+}
@@ -0,0 +1,222 @@
+package middleware
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// localsPrincipal is the fiber.Ctx locals key of the authenticated caller.
+	localsPrincipal = "principal"
+
+	headerAPIKey = "X-API-Key"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=AuthMiddleware --output=./mocks/middleware --outpkg=middleware --filename=auth.go --structname=MockAuthMiddleware --with-expecter=false
+type AuthMiddleware interface {
+	Handle(c *fiber.Ctx) error
+	RequireScope(scope string) fiber.Handler
+	RequirePermission(permission string) fiber.Handler
+	RequireSelfOrPermission(permission string) fiber.Handler
+	RequireSelf(c *fiber.Ctx) error
+}
+
+type authMiddlewareImpl struct {
+	sessionService      service.SessionService
+	apiKeyService       service.APIKeyService
+	organizationService service.OrganizationService
+	groupService        service.GroupService
+}
+
+func NewAuthMiddleware(
+	sessionService service.SessionService,
+	apiKeyService service.APIKeyService,
+	organizationService service.OrganizationService,
+	groupService service.GroupService,
+) AuthMiddleware {
+	return &authMiddlewareImpl{
+		sessionService:      sessionService,
+		apiKeyService:       apiKeyService,
+		organizationService: organizationService,
+		groupService:        groupService,
+	}
+}
+
+// Handle requires a valid access token or API key and stores the caller for
+// PrincipalFromContext. API keys are accepted in the X-API-Key header or as
+// a bearer token; they are told apart from access tokens by their prefix.
+// Users must be members of the organization the request was resolved to by
+// TenantMiddleware.
+func (m *authMiddlewareImpl) Handle(c *fiber.Ctx) error {
+	if key := c.Get(headerAPIKey); key != "" {
+		return m.authenticateAPIKey(c, key)
+	}
+
+	scheme, token, ok := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Missing bearer token",
+		})
+	}
+
+	if strings.HasPrefix(token, model.APIKeyPrefix) {
+		return m.authenticateAPIKey(c, token)
+	}
+
+	principal, err := m.sessionService.Authenticate(token)
+	if err != nil {
+		c.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid or expired access token",
+		})
+	}
+
+	return m.authorize(c, principal)
+}
+
+func (m *authMiddlewareImpl) authenticateAPIKey(c *fiber.Ctx, key string) error {
+	principal, err := m.apiKeyService.Authenticate(key)
+	if err != nil {
+		c.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Invalid, expired or revoked API key",
+		})
+	}
+
+	return m.authorize(c, principal)
+}
+
+// authorize stores the caller after checking that a user is a member of the
+// organization of the request, or that a service API key is bound to it, and
+// adds it to the audit source of the request.
+func (m *authMiddlewareImpl) authorize(c *fiber.Ctx, principal *model.Principal) error {
+	organizationID, ok := tenant.OrganizationFromContext(c.UserContext())
+	if ok && principal.UserID == 0 && principal.OrganizationID != organizationID {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "API key belongs to another organization",
+		})
+	}
+	if ok && principal.UserID != 0 {
+		member, err := m.organizationService.IsMember(c.UserContext(), organizationID, principal.UserID)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to check organization membership",
+			})
+		}
+		if !member {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Not a member of this organization",
+			})
+		}
+	}
+
+	c.Locals(localsPrincipal, principal)
+	withAuditActor(c, principal)
+	return c.Next()
+}
+
+// RequireScope rejects callers without the scope. It must run after Handle.
+func (m *authMiddlewareImpl) RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal := PrincipalFromContext(c)
+		if principal == nil {
+			c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Not authenticated",
+			})
+		}
+		if !principal.HasScope(scope) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Missing scope " + scope,
+			})
+		}
+		return c.Next()
+	}
+}
+
+// RequirePermission rejects users without the permission in the
+// organization of the request, as held through their role or granted to
+// their groups. Service API keys hold every permission in the organization
+// they are bound to, and none in others. It must run after Handle.
+func (m *authMiddlewareImpl) RequirePermission(permission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal := PrincipalFromContext(c)
+		if principal == nil {
+			c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Not authenticated",
+			})
+		}
+		if principal.UserID == 0 {
+			organizationID, ok := tenant.OrganizationFromContext(c.UserContext())
+			if !ok || organizationID != principal.OrganizationID {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error": "Missing permission " + permission,
+				})
+			}
+			return c.Next()
+		}
+
+		allowed, err := m.groupService.HasPermission(c.UserContext(), principal.UserID, permission)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to check permission",
+			})
+		}
+		if !allowed {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error": "Missing permission " + permission,
+			})
+		}
+		return c.Next()
+	}
+}
+
+// RequireSelfOrPermission lets users act on their own account, named by the
+// :id route parameter, and otherwise works like RequirePermission. It must
+// run after Handle.
+func (m *authMiddlewareImpl) RequireSelfOrPermission(permission string) fiber.Handler {
+	requirePermission := m.RequirePermission(permission)
+	return func(c *fiber.Ctx) error {
+		principal := PrincipalFromContext(c)
+		if principal != nil && principal.UserID != 0 {
+			if id, err := strconv.ParseUint(c.Params("id"), 10, 32); err == nil && uint(id) == principal.UserID {
+				return c.Next()
+			}
+		}
+		return requirePermission(c)
+	}
+}
+
+// RequireSelf only lets users act on their own account, named by the :id
+// route parameter. It must run after Handle.
+func (m *authMiddlewareImpl) RequireSelf(c *fiber.Ctx) error {
+	principal := PrincipalFromContext(c)
+	if principal == nil {
+		c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Not authenticated",
+		})
+	}
+
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || principal.UserID == 0 || uint(id) != principal.UserID {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Only allowed on your own account",
+		})
+	}
+	return c.Next()
+}
+
+// PrincipalFromContext returns the caller stored by AuthMiddleware, or nil.
+func PrincipalFromContext(c *fiber.Ctx) *model.Principal {
+	principal, _ := c.Locals(localsPrincipal).(*model.Principal)
+	return principal
+}
@@ -0,0 +1,268 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+)
+
+func (s *MiddlewareTestSuite) newAuthApp() *fiber.App {
+	app := fiber.New()
+	app.Use(s.authMiddleware.Handle)
+	app.Get("/me", func(c *fiber.Ctx) error {
+		principal := PrincipalFromContext(c)
+		return c.JSON(fiber.Map{"user_id": principal.UserID, "session_id": principal.SessionID})
+	})
+	return app
+}
+
+func (s *MiddlewareTestSuite) TestAuth_ValidToken() {
+	s.sessionService.On("Authenticate", "good-token").Return(&model.Principal{UserID: 1, SessionID: "session-1"}, nil)
+
+	req := httptest.NewRequest("GET", "/me", nil)
+	req.Header.Set("Authorization", "Bearer good-token")
+
+	resp, err := s.newAuthApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(s.T(), `{"user_id":1,"session_id":"session-1"}`, string(body))
+}
+
+func (s *MiddlewareTestSuite) TestAuth_MissingToken() {
+	for _, header := range []string{"", "Bearer ", "Basic dXNlcjpwYXNz", "good-token"} {
+		req := httptest.NewRequest("GET", "/me", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+
+		resp, err := s.newAuthApp().Test(req)
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), fiber.StatusUnauthorized, resp.StatusCode, header)
+		assert.Equal(s.T(), "Bearer", resp.Header.Get("WWW-Authenticate"))
+	}
+	s.sessionService.AssertNotCalled(s.T(), "Authenticate")
+}
+
+func (s *MiddlewareTestSuite) TestAuth_InvalidToken() {
+	s.sessionService.On("Authenticate", "revoked-token").Return(nil, service.ErrInvalidAccessToken)
+
+	req := httptest.NewRequest("GET", "/me", nil)
+	req.Header.Set("Authorization", "bearer revoked-token")
+
+	resp, err := s.newAuthApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusUnauthorized, resp.StatusCode)
+	assert.Contains(s.T(), resp.Header.Get("WWW-Authenticate"), "invalid_token")
+}
+
+func (s *MiddlewareTestSuite) TestAuth_APIKeyHeader() {
+	s.apiKeyService.On("Authenticate", "gkb_abcdefgh_secret").Return(&model.Principal{UserID: 1, APIKeyID: 7}, nil)
+
+	req := httptest.NewRequest("GET", "/me", nil)
+	req.Header.Set("X-API-Key", "gkb_abcdefgh_secret")
+
+	resp, err := s.newAuthApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	s.sessionService.AssertNotCalled(s.T(), "Authenticate")
+}
+
+func (s *MiddlewareTestSuite) TestAuth_APIKeyAsBearerToken() {
+	s.apiKeyService.On("Authenticate", "gkb_abcdefgh_secret").Return(&model.Principal{APIKeyID: 7, Service: "billing"}, nil)
+
+	req := httptest.NewRequest("GET", "/me", nil)
+	req.Header.Set("Authorization", "Bearer gkb_abcdefgh_secret")
+
+	resp, err := s.newAuthApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	s.sessionService.AssertNotCalled(s.T(), "Authenticate")
+}
+
+func (s *MiddlewareTestSuite) TestAuth_InvalidAPIKey() {
+	s.apiKeyService.On("Authenticate", "gkb_abcdefgh_revoked").Return(nil, service.ErrInvalidAPIKey)
+
+	req := httptest.NewRequest("GET", "/me", nil)
+	req.Header.Set("X-API-Key", "gkb_abcdefgh_revoked")
+
+	resp, err := s.newAuthApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusUnauthorized, resp.StatusCode)
+}
+
+func (s *MiddlewareTestSuite) TestRequireScope() {
+	s.apiKeyService.On("Authenticate", "gkb_readonly_secret").Return(&model.Principal{APIKeyID: 7, Scopes: model.ScopeList{model.ScopeUsersRead}}, nil)
+	s.sessionService.On("Authenticate", "session-token").Return(&model.Principal{UserID: 1, SessionID: "session-1"}, nil)
+
+	app := fiber.New()
+	app.Use(s.authMiddleware.Handle)
+	app.Get("/users", s.authMiddleware.RequireScope(model.ScopeUsersRead), func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+	app.Delete("/users", s.authMiddleware.RequireScope(model.ScopeUsersWrite), func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusNoContent) })
+
+	cases := []struct {
+		method, header, value string
+		status                int
+	}{
+		{"GET", "X-API-Key", "gkb_readonly_secret", fiber.StatusOK},
+		{"DELETE", "X-API-Key", "gkb_readonly_secret", fiber.StatusForbidden},
+		// Sessions are not restricted by scopes
+		{"DELETE", "Authorization", "Bearer session-token", fiber.StatusNoContent},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest(tc.method, "/users", nil)
+		req.Header.Set(tc.header, tc.value)
+
+		resp, err := app.Test(req)
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), tc.status, resp.StatusCode, tc.method+" "+tc.value)
+	}
+}
+
+func (s *MiddlewareTestSuite) TestRequirePermission() {
+	s.sessionService.On("Authenticate", "manager-token").Return(&model.Principal{UserID: 1, SessionID: "session-1"}, nil)
+	s.sessionService.On("Authenticate", "member-token").Return(&model.Principal{UserID: 2, SessionID: "session-2"}, nil)
+	s.apiKeyService.On("Authenticate", "gkb_service_secret").Return(&model.Principal{APIKeyID: 7, Service: "billing"}, nil)
+	s.groupService.On("HasPermission", mock.Anything, uint(1), model.PermissionGroupsManage).Return(true, nil)
+	s.groupService.On("HasPermission", mock.Anything, uint(2), model.PermissionGroupsManage).Return(false, nil)
+
+	app := fiber.New()
+	app.Use(s.authMiddleware.Handle)
+	app.Post("/groups", s.authMiddleware.RequirePermission(model.PermissionGroupsManage), func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+
+	cases := []struct {
+		header, value string
+		status        int
+	}{
+		{"Authorization", "Bearer manager-token", fiber.StatusCreated},
+		{"Authorization", "Bearer member-token", fiber.StatusForbidden},
+		// Service keys only hold permissions in an organization
+		{"X-API-Key", "gkb_service_secret", fiber.StatusForbidden},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("POST", "/groups", nil)
+		req.Header.Set(tc.header, tc.value)
+
+		resp, err := app.Test(req)
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), tc.status, resp.StatusCode, tc.value)
+	}
+}
+
+func (s *MiddlewareTestSuite) TestRequirePermission_ServiceKey() {
+	s.apiKeyService.On("Authenticate", "gkb_service_secret").Return(&model.Principal{APIKeyID: 7, Service: "billing", OrganizationID: 5}, nil)
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		organizationID, _ := strconv.ParseUint(c.Query("organization"), 10, 32)
+		c.SetUserContext(tenant.WithOrganization(c.UserContext(), uint(organizationID)))
+		return c.Next()
+	})
+	app.Use(s.authMiddleware.Handle)
+	app.Post("/groups", s.authMiddleware.RequirePermission(model.PermissionGroupsManage), func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+
+	cases := []struct {
+		path   string
+		status int
+	}{
+		// Service keys are only limited by their scopes in their organization
+		{"/groups?organization=5", fiber.StatusCreated},
+		{"/groups?organization=6", fiber.StatusForbidden},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("POST", tc.path, nil)
+		req.Header.Set("X-API-Key", "gkb_service_secret")
+
+		resp, err := app.Test(req)
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), tc.status, resp.StatusCode, tc.path)
+	}
+	s.groupService.AssertNotCalled(s.T(), "HasPermission", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *MiddlewareTestSuite) TestRequireSelfOrPermission() {
+	s.sessionService.On("Authenticate", "manager-token").Return(&model.Principal{UserID: 1, SessionID: "session-1"}, nil)
+	s.sessionService.On("Authenticate", "member-token").Return(&model.Principal{UserID: 2, SessionID: "session-2"}, nil)
+	s.groupService.On("HasPermission", mock.Anything, uint(1), model.PermissionUsersManage).Return(true, nil)
+	s.groupService.On("HasPermission", mock.Anything, uint(2), model.PermissionUsersManage).Return(false, nil)
+
+	app := fiber.New()
+	app.Use(s.authMiddleware.Handle)
+	app.Delete("/users/:id/mfa", s.authMiddleware.RequireSelfOrPermission(model.PermissionUsersManage), func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusNoContent) })
+
+	cases := []struct {
+		path, token string
+		status      int
+	}{
+		// Members act on their own account
+		{"/users/2/mfa", "member-token", fiber.StatusNoContent},
+		{"/users/3/mfa", "member-token", fiber.StatusForbidden},
+		{"/users/3/mfa", "manager-token", fiber.StatusNoContent},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("DELETE", tc.path, nil)
+		req.Header.Set("Authorization", "Bearer "+tc.token)
+
+		resp, err := app.Test(req)
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), tc.status, resp.StatusCode, tc.token+" "+tc.path)
+	}
+}
+
+func (s *MiddlewareTestSuite) TestRequireSelf() {
+	s.sessionService.On("Authenticate", "member-token").Return(&model.Principal{UserID: 2, SessionID: "session-2"}, nil)
+	s.apiKeyService.On("Authenticate", "gkb_service_secret").Return(&model.Principal{APIKeyID: 7, Service: "billing"}, nil)
+
+	app := fiber.New()
+	app.Use(s.authMiddleware.Handle)
+	app.Post("/users/:id/passkeys/register/begin", s.authMiddleware.RequireSelf, func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	cases := []struct {
+		path, header, value string
+		status              int
+	}{
+		{"/users/2/passkeys/register/begin", "Authorization", "Bearer member-token", fiber.StatusOK},
+		{"/users/3/passkeys/register/begin", "Authorization", "Bearer member-token", fiber.StatusForbidden},
+		// Service keys have no account of their own
+		{"/users/2/passkeys/register/begin", "X-API-Key", "gkb_service_secret", fiber.StatusForbidden},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("POST", tc.path, nil)
+		req.Header.Set(tc.header, tc.value)
+
+		resp, err := app.Test(req)
+
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), tc.status, resp.StatusCode, tc.value+" "+tc.path)
+	}
+}
+
+func (s *MiddlewareTestSuite) TestPrincipalFromContext_Unauthenticated() {
+	var principal *model.Principal
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		principal = PrincipalFromContext(c)
+		return nil
+	})
+
+	app.Test(httptest.NewRequest("GET", "/", nil))
+
+	assert.Nil(s.T(), principal)
+}
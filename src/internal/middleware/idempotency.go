@@ -0,0 +1,206 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+const (
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotentReplayed  = "Idempotent-Replayed"
+	maxIdempotencyKeyLength   = 255
+	maxIdempotencyLockRetries = 3
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=IdempotencyMiddleware --output=./mocks/middleware --outpkg=middleware --filename=idempotency.go --structname=MockIdempotencyMiddleware --with-expecter=false
+type IdempotencyMiddleware interface {
+	Handle(c *fiber.Ctx) error
+}
+
+type idempotencyMiddlewareImpl struct {
+	idempotencyRepo repository.IdempotencyRepository
+	ttl             time.Duration
+	lockTimeout     time.Duration
+	now             func() time.Time
+}
+
+func NewIdempotencyMiddleware(idempotencyRepo repository.IdempotencyRepository, conf *config.Config) IdempotencyMiddleware {
+	return &idempotencyMiddlewareImpl{
+		idempotencyRepo: idempotencyRepo,
+		ttl:             conf.Idempotency.TTL,
+		lockTimeout:     conf.Idempotency.LockTimeout,
+		now:             time.Now,
+	}
+}
+
+// Handle makes POST, PUT and PATCH requests carrying an Idempotency-Key header safe
+// to retry. The first request with a key is executed and its response stored;
+// later requests with the same key and body get the stored response replayed,
+// while reusing the key with a different body is rejected with 422. Keys are
+// scoped to the organization and credentials of the request, and responses
+// marked Cache-Control: no-store, such as those carrying tokens or secrets,
+// are never stored.
+func (m *idempotencyMiddlewareImpl) Handle(c *fiber.Ctx) error {
+	switch c.Method() {
+	case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch:
+	default:
+		return c.Next()
+	}
+
+	key := c.Get(HeaderIdempotencyKey)
+	if key == "" {
+		return c.Next()
+	}
+
+	if len(key) > maxIdempotencyKeyLength {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Idempotency-Key must not exceed 255 characters",
+		})
+	}
+
+	scope := m.scope(c)
+	fingerprint := m.fingerprint(c)
+
+	for attempt := 0; attempt < maxIdempotencyLockRetries; attempt++ {
+		now := m.now()
+		record := &model.IdempotencyKey{
+			Scope:       scope,
+			Key:         key,
+			LockID:      uuid.NewString(),
+			RequestHash: fingerprint,
+			LockedUntil: now.Add(m.lockTimeout),
+			ExpiresAt:   now.Add(m.ttl),
+		}
+
+		locked, err := m.idempotencyRepo.Lock(record)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to process Idempotency-Key",
+			})
+		}
+		if locked {
+			return m.execute(c, record)
+		}
+
+		existing, err := m.idempotencyRepo.GetByKey(scope, key)
+		if err != nil {
+			// The key was released between Lock and GetByKey; try again.
+			continue
+		}
+
+		if existing.ExpiresAt.Before(now) || (!existing.IsCompleted() && existing.LockedUntil.Before(now)) {
+			// Expired keys and locks abandoned by a crashed request are
+			// reclaimed, unless another request got there first.
+			if err := m.idempotencyRepo.Release(existing); err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error": "Failed to process Idempotency-Key",
+				})
+			}
+			continue
+		}
+
+		if existing.RequestHash != fingerprint {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error": "Idempotency-Key was already used with a different request",
+			})
+		}
+
+		if !existing.IsCompleted() {
+			return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+				"error": "A request with this Idempotency-Key is still being processed",
+			})
+		}
+
+		return m.replay(c, existing)
+	}
+
+	return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+		"error": "A request with this Idempotency-Key is still being processed",
+	})
+}
+
+func (m *idempotencyMiddlewareImpl) execute(c *fiber.Ctx, record *model.IdempotencyKey) error {
+	if err := c.Next(); err != nil {
+		m.release(record)
+		return err
+	}
+
+	status := c.Response().StatusCode()
+	if status >= fiber.StatusInternalServerError {
+		// Server errors are not remembered so that the client can retry.
+		m.release(record)
+		return nil
+	}
+	if strings.Contains(string(c.Response().Header.Peek(fiber.HeaderCacheControl)), "no-store") {
+		m.release(record)
+		return nil
+	}
+
+	completedAt := m.now()
+	record.ResponseStatus = status
+	record.ResponseBody = append([]byte(nil), c.Response().Body()...)
+	record.ContentType = string(c.Response().Header.ContentType())
+	record.CompletedAt = &completedAt
+
+	if err := m.idempotencyRepo.Complete(record); err != nil {
+		log.Printf("Failed to store response of Idempotency-Key: %v", err)
+		m.release(record)
+	}
+
+	return nil
+}
+
+// release frees the key for the next request. A key that cannot be released
+// stays locked until its lock times out.
+func (m *idempotencyMiddlewareImpl) release(record *model.IdempotencyKey) {
+	if err := m.idempotencyRepo.Release(record); err != nil {
+		log.Printf("Failed to release Idempotency-Key: %v", err)
+	}
+}
+
+func (m *idempotencyMiddlewareImpl) replay(c *fiber.Ctx, record *model.IdempotencyKey) error {
+	c.Set(HeaderIdempotentReplayed, "true")
+	if record.ContentType != "" {
+		c.Set(fiber.HeaderContentType, record.ContentType)
+	}
+	return c.Status(record.ResponseStatus).Send(record.ResponseBody)
+}
+
+// scope identifies the caller of a request by its organization and
+// credentials. It runs ahead of authentication, so the credentials are
+// hashed as sent; a stored response is only replayed to a request carrying
+// the same ones.
+func (m *idempotencyMiddlewareImpl) scope(c *fiber.Ctx) string {
+	hash := sha256.New()
+	if organizationID, ok := tenant.OrganizationFromContext(c.UserContext()); ok {
+		hash.Write([]byte(strconv.FormatUint(uint64(organizationID), 10)))
+	}
+	hash.Write([]byte{0})
+	hash.Write([]byte(c.Get(fiber.HeaderAuthorization)))
+	hash.Write([]byte{0})
+	hash.Write([]byte(c.Get(headerAPIKey)))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// fingerprint identifies a request by its method, path and body.
+func (m *idempotencyMiddlewareImpl) fingerprint(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method()))
+	hash.Write([]byte{0})
+	hash.Write([]byte(c.Path()))
+	hash.Write([]byte{0})
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,352 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+)
+
+func (s *MiddlewareTestSuite) newIdempotencyApp(calls *int) *fiber.App {
+	app := fiber.New()
+	app.Use(s.idempotencyMiddleware.Handle)
+	app.Post("/users", func(c *fiber.Ctx) error {
+		*calls++
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": 1})
+	})
+	app.Put("/fail", func(c *fiber.Ctx) error {
+		*calls++
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "boom"})
+	})
+	app.Patch("/users/:id", func(c *fiber.Ctx) error {
+		*calls++
+		return c.JSON(fiber.Map{"id": 1})
+	})
+	app.Post("/tokens", func(c *fiber.Ctx) error {
+		*calls++
+		c.Set(fiber.HeaderCacheControl, "no-store")
+		return c.JSON(fiber.Map{"token": "secret"})
+	})
+	app.Get("/users", func(c *fiber.Ctx) error {
+		*calls++
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app
+}
+
+func (s *MiddlewareTestSuite) request(app *fiber.App, method, path, key, body string) (int, string, string) {
+	req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(HeaderIdempotencyKey, key)
+	}
+
+	resp, err := app.Test(req)
+	assert.NoError(s.T(), err)
+
+	respBody, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(respBody), resp.Header.Get(HeaderIdempotentReplayed)
+}
+
+func (s *MiddlewareTestSuite) scopeOf(organizationID uint, authorization string) string {
+	var scope string
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		if organizationID != 0 {
+			c.SetUserContext(tenant.WithOrganization(c.UserContext(), organizationID))
+		}
+		scope = s.idempotencyMiddleware.(*idempotencyMiddlewareImpl).scope(c)
+		return nil
+	})
+	req := httptest.NewRequest("POST", "/users", nil)
+	if authorization != "" {
+		req.Header.Set(fiber.HeaderAuthorization, authorization)
+	}
+	app.Test(req)
+	return scope
+}
+
+func (s *MiddlewareTestSuite) fingerprintOf(method, path, body string) string {
+	var hash string
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		hash = s.idempotencyMiddleware.(*idempotencyMiddlewareImpl).fingerprint(c)
+		return nil
+	})
+	app.Test(httptest.NewRequest(method, path, bytes.NewBufferString(body)))
+	return hash
+}
+
+func (s *MiddlewareTestSuite) TestIdempotency_WithoutKey() {
+	calls := 0
+	app := s.newIdempotencyApp(&calls)
+
+	status, _, _ := s.request(app, "POST", "/users", "", `{"username":"test"}`)
+
+	assert.Equal(s.T(), fiber.StatusCreated, status)
+	assert.Equal(s.T(), 1, calls)
+	s.idempotencyRepo.AssertNotCalled(s.T(), "Lock", mock.Anything)
+}
+
+func (s *MiddlewareTestSuite) TestIdempotency_IgnoresSafeMethods() {
+	calls := 0
+	app := s.newIdempotencyApp(&calls)
+
+	status, _, _ := s.request(app, "GET", "/users", "key-1", "")
+
+	assert.Equal(s.T(), fiber.StatusOK, status)
+	assert.Equal(s.T(), 1, calls)
+	s.idempotencyRepo.AssertNotCalled(s.T(), "Lock", mock.Anything)
+}
+
+func (s *MiddlewareTestSuite) TestIdempotency_AppliesToPatch() {
+	calls := 0
+	app := s.newIdempotencyApp(&calls)
+
+	s.idempotencyRepo.On("Lock", mock.AnythingOfType("*model.IdempotencyKey")).Return(true, nil)
+	s.idempotencyRepo.On("Complete", mock.AnythingOfType("*model.IdempotencyKey")).Return(nil)
+
+	status, _, _ := s.request(app, "PATCH", "/users/1", "key-1", `{"email":"new@example.com"}`)
+
+	assert.Equal(s.T(), fiber.StatusOK, status)
+	assert.Equal(s.T(), 1, calls)
+	s.idempotencyRepo.AssertExpectations(s.T())
+}
+
+func (s *MiddlewareTestSuite) TestIdempotency_KeyTooLong() {
+	calls := 0
+	app := s.newIdempotencyApp(&calls)
+
+	status, _, _ := s.request(app, "POST", "/users", string(bytes.Repeat([]byte("k"), 256)), `{}`)
+
+	assert.Equal(s.T(), fiber.StatusBadRequest, status)
+	assert.Equal(s.T(), 0, calls)
+}
+
+func (s *MiddlewareTestSuite) TestIdempotency_FirstRequestStoresResponse() {
+	calls := 0
+	app := s.newIdempotencyApp(&calls)
+
+	s.idempotencyRepo.On("Lock", mock.AnythingOfType("*model.IdempotencyKey")).Return(true, nil)
+	s.idempotencyRepo.On("Complete", mock.MatchedBy(func(key *model.IdempotencyKey) bool {
+		return key.Key == "key-1" &&
+			key.ResponseStatus == fiber.StatusCreated &&
+			string(key.ResponseBody) == `{"id":1}` &&
+			key.ContentType == fiber.MIMEApplicationJSON &&
+			key.IsCompleted()
+	})).Return(nil)
+
+	status, body, replayed := s.request(app, "POST", "/users", "key-1", `{"username":"test"}`)
+
+	assert.Equal(s.T(), fiber.StatusCreated, status)
+	assert.Equal(s.T(), `{"id":1}`, body)
+	assert.Empty(s.T(), replayed)
+	assert.Equal(s.T(), 1, calls)
+	s.idempotencyRepo.AssertExpectations(s.T())
+}
+
+func (s *MiddlewareTestSuite) TestIdempotency_ReplaysStoredResponse() {
+	calls := 0
+	app := s.newIdempotencyApp(&calls)
+	completedAt := time.Now()
+
+	stored := &model.IdempotencyKey{
+		Key:            "key-1",
+		RequestHash:    s.fingerprintOf("POST", "/users", `{"username":"test"}`),
+		ResponseStatus: fiber.StatusCreated,
+		ResponseBody:   []byte(`{"id":1}`),
+		ContentType:    fiber.MIMEApplicationJSON,
+		LockedUntil:    time.Now().Add(time.Minute),
+		CompletedAt:    &completedAt,
+		ExpiresAt:      time.Now().Add(time.Hour),
+	}
+
+	s.idempotencyRepo.On("Lock", mock.AnythingOfType("*model.IdempotencyKey")).Return(false, nil)
+	s.idempotencyRepo.On("GetByKey", mock.AnythingOfType("string"), "key-1").Return(stored, nil)
+
+	status, body, replayed := s.request(app, "POST", "/users", "key-1", `{"username":"test"}`)
+
+	assert.Equal(s.T(), fiber.StatusCreated, status)
+	assert.Equal(s.T(), `{"id":1}`, body)
+	assert.Equal(s.T(), "true", replayed)
+	assert.Equal(s.T(), 0, calls)
+	s.idempotencyRepo.AssertExpectations(s.T())
+}
+
+func (s *MiddlewareTestSuite) TestIdempotency_DifferentBodyRejected() {
+	calls := 0
+	app := s.newIdempotencyApp(&calls)
+	completedAt := time.Now()
+
+	stored := &model.IdempotencyKey{
+		Key:         "key-1",
+		RequestHash: s.fingerprintOf("POST", "/users", `{"username":"test"}`),
+		LockedUntil: time.Now().Add(time.Minute),
+		CompletedAt: &completedAt,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+
+	s.idempotencyRepo.On("Lock", mock.AnythingOfType("*model.IdempotencyKey")).Return(false, nil)
+	s.idempotencyRepo.On("GetByKey", mock.AnythingOfType("string"), "key-1").Return(stored, nil)
+
+	status, _, _ := s.request(app, "POST", "/users", "key-1", `{"username":"other"}`)
+
+	assert.Equal(s.T(), fiber.StatusUnprocessableEntity, status)
+	assert.Equal(s.T(), 0, calls)
+	s.idempotencyRepo.AssertExpectations(s.T())
+}
+
+func (s *MiddlewareTestSuite) TestIdempotency_ConcurrentDuplicateRejected() {
+	calls := 0
+	app := s.newIdempotencyApp(&calls)
+
+	inFlight := &model.IdempotencyKey{
+		Key:         "key-1",
+		RequestHash: s.fingerprintOf("POST", "/users", `{"username":"test"}`),
+		LockedUntil: time.Now().Add(time.Minute),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+
+	s.idempotencyRepo.On("Lock", mock.AnythingOfType("*model.IdempotencyKey")).Return(false, nil)
+	s.idempotencyRepo.On("GetByKey", mock.AnythingOfType("string"), "key-1").Return(inFlight, nil)
+
+	status, _, _ := s.request(app, "POST", "/users", "key-1", `{"username":"test"}`)
+
+	assert.Equal(s.T(), fiber.StatusConflict, status)
+	assert.Equal(s.T(), 0, calls)
+	s.idempotencyRepo.AssertExpectations(s.T())
+}
+
+func (s *MiddlewareTestSuite) TestIdempotency_ExpiredKeyIsReclaimed() {
+	calls := 0
+	app := s.newIdempotencyApp(&calls)
+	completedAt := time.Now().Add(-48 * time.Hour)
+
+	expired := &model.IdempotencyKey{
+		Key:         "key-1",
+		RequestHash: "old",
+		LockedUntil: completedAt,
+		CompletedAt: &completedAt,
+		ExpiresAt:   time.Now().Add(-24 * time.Hour),
+	}
+
+	s.idempotencyRepo.On("Lock", mock.AnythingOfType("*model.IdempotencyKey")).Return(false, nil).Once()
+	s.idempotencyRepo.On("GetByKey", mock.AnythingOfType("string"), "key-1").Return(expired, nil).Once()
+	s.idempotencyRepo.On("Release", expired).Return(nil).Once()
+	s.idempotencyRepo.On("Lock", mock.AnythingOfType("*model.IdempotencyKey")).Return(true, nil).Once()
+	s.idempotencyRepo.On("Complete", mock.AnythingOfType("*model.IdempotencyKey")).Return(nil)
+
+	status, _, replayed := s.request(app, "POST", "/users", "key-1", `{"username":"test"}`)
+
+	assert.Equal(s.T(), fiber.StatusCreated, status)
+	assert.Empty(s.T(), replayed)
+	assert.Equal(s.T(), 1, calls)
+	s.idempotencyRepo.AssertExpectations(s.T())
+}
+
+func (s *MiddlewareTestSuite) TestIdempotency_ServerErrorReleasesKey() {
+	calls := 0
+	app := s.newIdempotencyApp(&calls)
+
+	s.idempotencyRepo.On("Lock", mock.AnythingOfType("*model.IdempotencyKey")).Return(true, nil)
+	s.idempotencyRepo.On("Release", mock.MatchedBy(func(key *model.IdempotencyKey) bool {
+		return key.Key == "key-1" && key.LockID != ""
+	})).Return(nil)
+
+	status, _, _ := s.request(app, "PUT", "/fail", "key-1", `{}`)
+
+	assert.Equal(s.T(), fiber.StatusInternalServerError, status)
+	assert.Equal(s.T(), 1, calls)
+	s.idempotencyRepo.AssertExpectations(s.T())
+	s.idempotencyRepo.AssertNotCalled(s.T(), "Complete", mock.Anything)
+}
+
+func (s *MiddlewareTestSuite) TestIdempotency_LockError() {
+	calls := 0
+	app := s.newIdempotencyApp(&calls)
+
+	s.idempotencyRepo.On("Lock", mock.AnythingOfType("*model.IdempotencyKey")).Return(false, errors.New("database error"))
+
+	status, _, _ := s.request(app, "POST", "/users", "key-1", `{}`)
+
+	assert.Equal(s.T(), fiber.StatusInternalServerError, status)
+	assert.Equal(s.T(), 0, calls)
+	s.idempotencyRepo.AssertExpectations(s.T())
+}
+
+func (s *MiddlewareTestSuite) TestIdempotency_ScopedToCaller() {
+	anonymous := s.scopeOf(1, "")
+	alice := s.scopeOf(1, "Bearer alice")
+
+	assert.Equal(s.T(), alice, s.scopeOf(1, "Bearer alice"))
+	assert.NotEqual(s.T(), alice, anonymous)
+	assert.NotEqual(s.T(), alice, s.scopeOf(1, "Bearer bob"))
+	assert.NotEqual(s.T(), alice, s.scopeOf(2, "Bearer alice"))
+	assert.NotEqual(s.T(), anonymous, s.scopeOf(2, ""))
+}
+
+func (s *MiddlewareTestSuite) TestIdempotency_LooksUpKeyInScope() {
+	calls := 0
+	app := s.newIdempotencyApp(&calls)
+
+	s.idempotencyRepo.On("Lock", mock.MatchedBy(func(key *model.IdempotencyKey) bool {
+		return key.Scope == s.scopeOf(0, "")
+	})).Return(false, nil)
+	s.idempotencyRepo.On("GetByKey", s.scopeOf(0, ""), "key-1").Return(&model.IdempotencyKey{
+		Key:         "key-1",
+		RequestHash: s.fingerprintOf("POST", "/users", `{"username":"test"}`),
+		LockedUntil: time.Now().Add(time.Minute),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}, nil)
+
+	status, _, _ := s.request(app, "POST", "/users", "key-1", `{"username":"test"}`)
+
+	assert.Equal(s.T(), fiber.StatusConflict, status)
+	s.idempotencyRepo.AssertExpectations(s.T())
+}
+
+func (s *MiddlewareTestSuite) TestIdempotency_NoStoreResponseNotStored() {
+	calls := 0
+	app := s.newIdempotencyApp(&calls)
+
+	s.idempotencyRepo.On("Lock", mock.AnythingOfType("*model.IdempotencyKey")).Return(true, nil)
+	s.idempotencyRepo.On("Release", mock.AnythingOfType("*model.IdempotencyKey")).Return(nil)
+
+	status, body, _ := s.request(app, "POST", "/tokens", "key-1", `{}`)
+
+	assert.Equal(s.T(), fiber.StatusOK, status)
+	assert.Equal(s.T(), `{"token":"secret"}`, body)
+	assert.Equal(s.T(), 1, calls)
+	s.idempotencyRepo.AssertExpectations(s.T())
+	s.idempotencyRepo.AssertNotCalled(s.T(), "Complete", mock.Anything)
+}
+
+func (s *MiddlewareTestSuite) TestIdempotency_ReclaimError() {
+	calls := 0
+	app := s.newIdempotencyApp(&calls)
+
+	abandoned := &model.IdempotencyKey{
+		Key:         "key-1",
+		LockID:      "lock-1",
+		RequestHash: s.fingerprintOf("POST", "/users", `{"username":"test"}`),
+		LockedUntil: time.Now().Add(-time.Minute),
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+
+	s.idempotencyRepo.On("Lock", mock.AnythingOfType("*model.IdempotencyKey")).Return(false, nil)
+	s.idempotencyRepo.On("GetByKey", mock.AnythingOfType("string"), "key-1").Return(abandoned, nil)
+	s.idempotencyRepo.On("Release", abandoned).Return(errors.New("database error"))
+
+	status, _, _ := s.request(app, "POST", "/users", "key-1", `{"username":"test"}`)
+
+	assert.Equal(s.T(), fiber.StatusInternalServerError, status)
+	assert.Equal(s.T(), 0, calls)
+	s.idempotencyRepo.AssertExpectations(s.T())
+}
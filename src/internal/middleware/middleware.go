@@ -0,0 +1,28 @@
+package middleware
+
+import "go.uber.org/dig"
+
+type Middleware struct {
+	Idempotency IdempotencyMiddleware
+	Auth        AuthMiddleware
+	Tenant      TenantMiddleware
+	Audit       AuditMiddleware
+}
+
+type MiddlewareParams struct {
+	dig.In
+
+	Idempotency IdempotencyMiddleware
+	Auth        AuthMiddleware
+	Tenant      TenantMiddleware
+	Audit       AuditMiddleware
+}
+
+func NewMiddleware(params MiddlewareParams) *Middleware {
+	return &Middleware{
+		Idempotency: params.Idempotency,
+		Auth:        params.Auth,
+		Tenant:      params.Tenant,
+		Audit:       params.Audit,
+	}
+}
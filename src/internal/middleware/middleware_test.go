@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	mocks "github.com/weeranieb/go-kit-base/src/internal/repository/mocks/repository"
+	serviceMocks "github.com/weeranieb/go-kit-base/src/internal/service/mocks/service"
+)
+
+type MiddlewareTestSuite struct {
+	suite.Suite
+	conf                  *config.Config
+	idempotencyRepo       *mocks.MockIdempotencyRepository
+	idempotencyMiddleware IdempotencyMiddleware
+	sessionService        *serviceMocks.MockSessionService
+	apiKeyService         *serviceMocks.MockAPIKeyService
+	organizationService   *serviceMocks.MockOrganizationService
+	groupService          *serviceMocks.MockGroupService
+	authMiddleware        AuthMiddleware
+	tokenService          *serviceMocks.MockTokenService
+	userService           *serviceMocks.MockUserService
+	tenantMiddleware      TenantMiddleware
+	auditMiddleware       AuditMiddleware
+}
+
+func (s *MiddlewareTestSuite) SetupTest() {
+	s.conf = &config.Config{
+		Idempotency: config.IdempotencyConfig{
+			TTL:         24 * time.Hour,
+			LockTimeout: time.Minute,
+		},
+		Tenant: config.TenantConfig{
+			Header:              "X-Organization",
+			BaseDomain:          "example.com",
+			DefaultOrganization: "default",
+		},
+	}
+	s.idempotencyRepo = mocks.NewMockIdempotencyRepository(s.T())
+	s.idempotencyMiddleware = NewIdempotencyMiddleware(s.idempotencyRepo, s.conf)
+	s.sessionService = serviceMocks.NewMockSessionService(s.T())
+	s.apiKeyService = serviceMocks.NewMockAPIKeyService(s.T())
+	s.organizationService = serviceMocks.NewMockOrganizationService(s.T())
+	s.groupService = serviceMocks.NewMockGroupService(s.T())
+	s.authMiddleware = NewAuthMiddleware(s.sessionService, s.apiKeyService, s.organizationService, s.groupService)
+	s.tokenService = serviceMocks.NewMockTokenService(s.T())
+	s.userService = serviceMocks.NewMockUserService(s.T())
+	s.tenantMiddleware = NewTenantMiddleware(s.organizationService, s.tokenService, s.userService, s.conf)
+	s.auditMiddleware = NewAuditMiddleware()
+}
+
+func (s *MiddlewareTestSuite) TearDownTest() {
+	s.idempotencyRepo.ExpectedCalls = nil
+	s.sessionService.ExpectedCalls = nil
+	s.apiKeyService.ExpectedCalls = nil
+	s.organizationService.ExpectedCalls = nil
+	s.groupService.ExpectedCalls = nil
+	s.tokenService.ExpectedCalls = nil
+	s.userService.ExpectedCalls = nil
+}
+
+func TestMiddlewareSuite(t *testing.T) {
+	suite.Run(t, new(MiddlewareTestSuite))
+}
@@ -0,0 +1,124 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package middleware
+
+import (
+	fiber "github.com/gofiber/fiber/v2"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockAuthMiddleware is an autogenerated mock type for the AuthMiddleware type
+type MockAuthMiddleware struct {
+	mock.Mock
+}
+
+// Handle provides a mock function with given fields: c
+func (_m *MockAuthMiddleware) Handle(c *fiber.Ctx) error {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Handle")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*fiber.Ctx) error); ok {
+		r0 = rf(c)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RequirePermission provides a mock function with given fields: permission
+func (_m *MockAuthMiddleware) RequirePermission(permission string) func(*fiber.Ctx) error {
+	ret := _m.Called(permission)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequirePermission")
+	}
+
+	var r0 func(*fiber.Ctx) error
+	if rf, ok := ret.Get(0).(func(string) func(*fiber.Ctx) error); ok {
+		r0 = rf(permission)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(func(*fiber.Ctx) error)
+		}
+	}
+
+	return r0
+}
+
+// RequireScope provides a mock function with given fields: scope
+func (_m *MockAuthMiddleware) RequireScope(scope string) func(*fiber.Ctx) error {
+	ret := _m.Called(scope)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequireScope")
+	}
+
+	var r0 func(*fiber.Ctx) error
+	if rf, ok := ret.Get(0).(func(string) func(*fiber.Ctx) error); ok {
+		r0 = rf(scope)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(func(*fiber.Ctx) error)
+		}
+	}
+
+	return r0
+}
+
+// RequireSelf provides a mock function with given fields: c
+func (_m *MockAuthMiddleware) RequireSelf(c *fiber.Ctx) error {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequireSelf")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*fiber.Ctx) error); ok {
+		r0 = rf(c)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RequireSelfOrPermission provides a mock function with given fields: permission
+func (_m *MockAuthMiddleware) RequireSelfOrPermission(permission string) func(*fiber.Ctx) error {
+	ret := _m.Called(permission)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RequireSelfOrPermission")
+	}
+
+	var r0 func(*fiber.Ctx) error
+	if rf, ok := ret.Get(0).(func(string) func(*fiber.Ctx) error); ok {
+		r0 = rf(permission)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(func(*fiber.Ctx) error)
+		}
+	}
+
+	return r0
+}
+
+// NewMockAuthMiddleware creates a new instance of MockAuthMiddleware. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAuthMiddleware(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAuthMiddleware {
+	mock := &MockAuthMiddleware{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
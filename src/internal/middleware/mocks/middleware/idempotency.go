@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package middleware
+
+import (
+	fiber "github.com/gofiber/fiber/v2"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockIdempotencyMiddleware is an autogenerated mock type for the IdempotencyMiddleware type
+type MockIdempotencyMiddleware struct {
+	mock.Mock
+}
+
+// Handle provides a mock function with given fields: c
+func (_m *MockIdempotencyMiddleware) Handle(c *fiber.Ctx) error {
+	ret := _m.Called(c)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Handle")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*fiber.Ctx) error); ok {
+		r0 = rf(c)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockIdempotencyMiddleware creates a new instance of MockIdempotencyMiddleware. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIdempotencyMiddleware(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIdempotencyMiddleware {
+	mock := &MockIdempotencyMiddleware{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=TenantMiddleware --output=./mocks/middleware --outpkg=middleware --filename=tenant.go --structname=MockTenantMiddleware --with-expecter=false
+type TenantMiddleware interface {
+	Handle(c *fiber.Ctx) error
+	RequireUser(c *fiber.Ctx) error
+}
+
+type tenantMiddlewareImpl struct {
+	organizationService service.OrganizationService
+	tokenService        service.TokenService
+	userService         service.UserService
+	header              string
+	baseDomain          string
+	defaultSlug         string
+}
+
+func NewTenantMiddleware(
+	organizationService service.OrganizationService,
+	tokenService service.TokenService,
+	userService service.UserService,
+	conf *config.Config,
+) TenantMiddleware {
+	return &tenantMiddlewareImpl{
+		organizationService: organizationService,
+		tokenService:        tokenService,
+		userService:         userService,
+		header:              conf.Tenant.Header,
+		baseDomain:          strings.ToLower(conf.Tenant.BaseDomain),
+		defaultSlug:         conf.Tenant.DefaultOrganization,
+	}
+}
+
+// Handle finds the organization of the request and scopes the user context
+// of fiber.Ctx, which handlers pass on to services, to it. The organization
+// is named by the tenant header or the subdomain of tenant.base_domain;
+// otherwise it is the home organization in the access token, or the
+// default organization. Unknown organizations are rejected with 404.
+func (m *tenantMiddlewareImpl) Handle(c *fiber.Ctx) error {
+	organizationID, err := m.resolve(c)
+	if errors.Is(err, service.ErrOrganizationNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Organization not found",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to resolve organization",
+		})
+	}
+
+	c.SetUserContext(tenant.WithOrganization(c.UserContext(), organizationID))
+	return c.Next()
+}
+
+func (m *tenantMiddlewareImpl) resolve(c *fiber.Ctx) (uint, error) {
+	slug := c.Get(m.header)
+	if slug == "" {
+		slug = m.subdomain(c.Hostname())
+	}
+	if slug == "" {
+		if organizationID := m.tokenOrganization(c); organizationID != 0 {
+			return organizationID, nil
+		}
+		slug = m.defaultSlug
+	}
+
+	organization, err := m.organizationService.Resolve(strings.ToLower(slug))
+	if err != nil {
+		return 0, err
+	}
+	return organization.ID, nil
+}
+
+// subdomain returns the label in front of the base domain, or "" if the host
+// is not a direct subdomain of it.
+func (m *tenantMiddlewareImpl) subdomain(host string) string {
+	if m.baseDomain == "" {
+		return ""
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	label, ok := strings.CutSuffix(strings.ToLower(host), "."+m.baseDomain)
+	if !ok || label == "" || strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
+
+// tokenOrganization returns the home organization in a valid bearer access
+// token, or 0. Whether the session is still active is left to
+// AuthMiddleware.
+func (m *tenantMiddlewareImpl) tokenOrganization(c *fiber.Ctx) uint {
+	scheme, token, ok := strings.Cut(c.Get(fiber.HeaderAuthorization), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" || strings.HasPrefix(token, model.APIKeyPrefix) {
+		return 0
+	}
+
+	claims, err := m.tokenService.ParseAccessToken(token)
+	if err != nil {
+		return 0
+	}
+	return claims.OrganizationID
+}
+
+// RequireUser rejects requests for the user in the id parameter with 404
+// unless the user belongs to the organization of the request or is the
+// caller. It must run after Handle and AuthMiddleware.Handle, so that
+// routes acting on a user by ID cannot reach the users of another
+// organization.
+func (m *tenantMiddlewareImpl) RequireUser(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil {
+		// Left to the handler to reject
+		return c.Next()
+	}
+
+	if principal := PrincipalFromContext(c); principal != nil && principal.UserID == uint(id) {
+		return c.Next()
+	}
+
+	_, err = m.userService.GetUser(c.UserContext(), uint(id))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "User not found",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to fetch user",
+		})
+	}
+	return c.Next()
+}
@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+	"gorm.io/gorm"
+)
+
+// newTenantApp answers with the organization a request was resolved to.
+func (s *MiddlewareTestSuite) newTenantApp() *fiber.App {
+	app := fiber.New()
+	app.Use(s.tenantMiddleware.Handle)
+	app.Get("/organization", func(c *fiber.Ctx) error {
+		organizationID, _ := tenant.OrganizationFromContext(c.UserContext())
+		return c.JSON(fiber.Map{"organization_id": organizationID})
+	})
+	return app
+}
+
+func (s *MiddlewareTestSuite) expectOrganization(slug string, id uint) {
+	s.organizationService.On("Resolve", slug).Return(&model.Organization{ID: id, Slug: slug}, nil)
+}
+
+func (s *MiddlewareTestSuite) TestTenant_Header() {
+	s.expectOrganization("acme", 5)
+
+	req := httptest.NewRequest("GET", "/organization", nil)
+	req.Header.Set("X-Organization", "Acme")
+
+	resp, err := s.newTenantApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(s.T(), `{"organization_id":5}`, string(body))
+}
+
+func (s *MiddlewareTestSuite) TestTenant_Subdomain() {
+	s.expectOrganization("acme", 5)
+
+	req := httptest.NewRequest("GET", "http://acme.example.com:8080/organization", nil)
+
+	resp, err := s.newTenantApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(s.T(), `{"organization_id":5}`, string(body))
+}
+
+func (s *MiddlewareTestSuite) TestTenant_TokenClaim() {
+	s.tokenService.On("ParseAccessToken", "good-token").Return(&service.AccessTokenClaims{SessionID: "session-1", OrganizationID: 7}, nil)
+
+	req := httptest.NewRequest("GET", "http://api.other.com/organization", nil)
+	req.Header.Set("Authorization", "Bearer good-token")
+
+	resp, err := s.newTenantApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	body, _ := io.ReadAll(resp.Body)
+	assert.JSONEq(s.T(), `{"organization_id":7}`, string(body))
+	s.organizationService.AssertNotCalled(s.T(), "Resolve", mock.Anything)
+}
+
+func (s *MiddlewareTestSuite) TestTenant_Default() {
+	s.tokenService.On("ParseAccessToken", "expired-token").Return(nil, errors.New("token is expired"))
+	s.expectOrganization("default", 1)
+
+	for _, header := range []string{"", "Bearer expired-token", "Bearer gkb_abcdefgh_secret"} {
+		// Deeper subdomains and the base domain itself name no organization
+		for _, host := range []string{"example.com", "a.b.example.com", "localhost"} {
+			req := httptest.NewRequest("GET", "http://"+host+"/organization", nil)
+			if header != "" {
+				req.Header.Set("Authorization", header)
+			}
+
+			resp, err := s.newTenantApp().Test(req)
+
+			assert.NoError(s.T(), err)
+			body, _ := io.ReadAll(resp.Body)
+			assert.JSONEq(s.T(), `{"organization_id":1}`, string(body), host)
+		}
+	}
+	s.tokenService.AssertNotCalled(s.T(), "ParseAccessToken", "gkb_abcdefgh_secret")
+}
+
+func (s *MiddlewareTestSuite) TestTenant_UnknownOrganization() {
+	s.organizationService.On("Resolve", "globex").Return(nil, service.ErrOrganizationNotFound)
+
+	req := httptest.NewRequest("GET", "/organization", nil)
+	req.Header.Set("X-Organization", "globex")
+
+	resp, err := s.newTenantApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+}
+
+// newTenantAuthApp authenticates requests within the organization in the
+// X-Organization header.
+func (s *MiddlewareTestSuite) newTenantAuthApp() *fiber.App {
+	app := fiber.New()
+	app.Use(s.tenantMiddleware.Handle, s.authMiddleware.Handle)
+	app.Get("/users/:id", s.tenantMiddleware.RequireUser, func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app
+}
+
+func (s *MiddlewareTestSuite) newTenantAuthRequest(path string) *http.Request {
+	req := httptest.NewRequest("GET", path, nil)
+	req.Header.Set("X-Organization", "acme")
+	req.Header.Set("Authorization", "Bearer good-token")
+	return req
+}
+
+func (s *MiddlewareTestSuite) TestAuth_NotMember() {
+	s.expectOrganization("acme", 5)
+	s.sessionService.On("Authenticate", "good-token").Return(&model.Principal{UserID: 1, SessionID: "session-1"}, nil)
+	s.organizationService.On("IsMember", mock.Anything, uint(5), uint(1)).Return(false, nil)
+
+	resp, err := s.newTenantAuthApp().Test(s.newTenantAuthRequest("/users/1"))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusForbidden, resp.StatusCode)
+}
+
+func (s *MiddlewareTestSuite) TestAuth_ServiceKeyInOrganization() {
+	s.expectOrganization("acme", 5)
+	s.apiKeyService.On("Authenticate", "gkb_abcdefgh_secret").Return(&model.Principal{APIKeyID: 7, Service: "billing", OrganizationID: 5}, nil)
+	s.userService.On("GetUser", mock.Anything, uint(2)).Return(&model.UserResponse{ID: 2}, nil)
+
+	req := httptest.NewRequest("GET", "/users/2", nil)
+	req.Header.Set("X-Organization", "acme")
+	req.Header.Set("X-API-Key", "gkb_abcdefgh_secret")
+
+	resp, err := s.newTenantAuthApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	s.organizationService.AssertNotCalled(s.T(), "IsMember", mock.Anything, mock.Anything)
+}
+
+func (s *MiddlewareTestSuite) TestAuth_ServiceKeyOtherOrganization() {
+	s.expectOrganization("acme", 5)
+	s.apiKeyService.On("Authenticate", "gkb_abcdefgh_secret").Return(&model.Principal{APIKeyID: 7, Service: "billing", OrganizationID: 6}, nil)
+
+	req := httptest.NewRequest("GET", "/users/2", nil)
+	req.Header.Set("X-Organization", "acme")
+	req.Header.Set("X-API-Key", "gkb_abcdefgh_secret")
+
+	resp, err := s.newTenantAuthApp().Test(req)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusForbidden, resp.StatusCode)
+	s.userService.AssertNotCalled(s.T(), "GetUser", mock.Anything, mock.Anything)
+}
+
+func (s *MiddlewareTestSuite) TestRequireUser_OtherOrganization() {
+	s.expectOrganization("acme", 5)
+	s.sessionService.On("Authenticate", "good-token").Return(&model.Principal{UserID: 1, SessionID: "session-1"}, nil)
+	s.organizationService.On("IsMember", mock.Anything, uint(5), uint(1)).Return(true, nil)
+	s.userService.On("GetUser", mock.Anything, uint(2)).Return(nil, gorm.ErrRecordNotFound)
+
+	resp, err := s.newTenantAuthApp().Test(s.newTenantAuthRequest("/users/2"))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusNotFound, resp.StatusCode)
+
+	// The lookup is scoped to the organization of the request
+	ctx := s.userService.Calls[0].Arguments.Get(0).(context.Context)
+	organizationID, _ := tenant.OrganizationFromContext(ctx)
+	assert.Equal(s.T(), uint(5), organizationID)
+}
+
+func (s *MiddlewareTestSuite) TestRequireUser_Self() {
+	s.expectOrganization("acme", 5)
+	s.sessionService.On("Authenticate", "good-token").Return(&model.Principal{UserID: 1, SessionID: "session-1"}, nil)
+	s.organizationService.On("IsMember", mock.Anything, uint(5), uint(1)).Return(true, nil)
+
+	resp, err := s.newTenantAuthApp().Test(s.newTenantAuthRequest("/users/1"))
+
+	// Assert: members reach their own account from any of their
+	// organizations
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), fiber.StatusOK, resp.StatusCode)
+	s.userService.AssertNotCalled(s.T(), "GetUser", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Scope and permission for reading the audit log. Owners and admins of an
+// organization can read its log; groups can be granted audit:read.
+const (
+	ScopeAuditRead      = "audit:read"
+	PermissionAuditRead = "audit:read"
+)
+
+// Audited actions.
+const (
+	AuditUserCreated           = "user.created"
+	AuditUserUpdated           = "user.updated"
+	AuditUserDeleted           = "user.deleted"
+	AuditUserRoleChanged       = "user.role_changed"
+	AuditMembershipRoleChanged = "membership.role_changed"
+
+	AuditTargetUser = "user"
+)
+
+// Export formats of the audit log.
+const (
+	AuditFormatCSV    = "csv"
+	AuditFormatNDJSON = "ndjson"
+)
+
+// AuditChange is the value of a field before and after a change. Before is
+// nil for created records and After for deleted ones.
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditChanges maps changed fields to their values, stored as JSON.
+type AuditChanges map[string]AuditChange
+
+func (c AuditChanges) Value() (driver.Value, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (c *AuditChanges) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	case []byte:
+		return json.Unmarshal(v, c)
+	default:
+		return fmt.Errorf("cannot scan %T into AuditChanges", value)
+	}
+}
+
+// AuditEvent records a change in an organization. Events are only ever
+// appended: Hash covers the event and PrevHash, the hash of the event
+// before it in the organization, so that changing or removing an event
+// breaks the chain.
+type AuditEvent struct {
+	ID             uint         `gorm:"primaryKey"`
+	OrganizationID uint         `gorm:"uniqueIndex:idx_audit_events_organization_prev_hash,priority:1;not null"`
+	Action         string       `gorm:"index;not null;size:64"`
+	ActorUserID    *uint        `gorm:"index"`
+	ActorAPIKeyID  *uint        `gorm:"column:actor_api_key_id"`
+	ActorService   string       `gorm:"size:100"`
+	TargetType     string       `gorm:"index:idx_audit_events_target,priority:1;not null;size:32"`
+	TargetID       uint         `gorm:"index:idx_audit_events_target,priority:2;not null"`
+	IP             string       `gorm:"size:45"`
+	RequestID      string       `gorm:"size:64"`
+	Changes        AuditChanges `gorm:"type:text;not null"`
+	PrevHash       string       `gorm:"uniqueIndex:idx_audit_events_organization_prev_hash,priority:2;not null;size:64"`
+	Hash           string       `gorm:"not null;size:64"`
+	CreatedAt      time.Time    `gorm:"index"`
+}
+
+// AuditFilter selects audit events. Zero fields match everything.
+type AuditFilter struct {
+	Action      string
+	ActorUserID uint
+	TargetType  string
+	TargetID    uint
+	From        time.Time
+	To          time.Time
+}
+
+type AuditEventResponse struct {
+	ID            uint         `json:"id"`
+	Action        string       `json:"action"`
+	ActorUserID   *uint        `json:"actor_user_id"`
+	ActorAPIKeyID *uint        `json:"actor_api_key_id"`
+	ActorService  string       `json:"actor_service,omitempty"`
+	TargetType    string       `json:"target_type"`
+	TargetID      uint         `json:"target_id"`
+	IP            string       `json:"ip"`
+	RequestID     string       `json:"request_id"`
+	Changes       AuditChanges `json:"changes"`
+	PrevHash      string       `json:"prev_hash"`
+	Hash          string       `json:"hash"`
+	CreatedAt     time.Time    `json:"created_at"`
+}
+
+// AuditVerificationResponse reports whether the hash chain of an
+// organization's audit log is intact. BrokenAt is the first event whose
+// hash does not match.
+type AuditVerificationResponse struct {
+	Valid    bool  `json:"valid"`
+	Events   int64 `json:"events"`
+	BrokenAt *uint `json:"broken_at,omitempty"`
+}
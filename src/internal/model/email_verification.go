@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// EmailVerificationToken is a single-use token mailed to Email to prove that
+// the user owns it. Only the SHA-256 hash of the token is stored.
+type EmailVerificationToken struct {
+	ID        uint       `gorm:"primaryKey"`
+	UserID    uint       `gorm:"index;not null"`
+	Email     string     `gorm:"not null;size:255"`
+	TokenHash string     `gorm:"uniqueIndex;not null;size:64"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	UsedAt    *time.Time `gorm:""`
+	CreatedAt time.Time
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
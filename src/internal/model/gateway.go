@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// GatewayTicket lets a user open one gateway connection shortly after it
+// was issued, so that browsers, which cannot set headers on WebSocket
+// handshakes, need not put their access token in the URL. Only the SHA-256
+// hash of the ticket is stored.
+type GatewayTicket struct {
+	ID             uint       `gorm:"primaryKey"`
+	OrganizationID uint       `gorm:"index;not null"`
+	UserID         uint       `gorm:"not null"`
+	TokenHash      string     `gorm:"uniqueIndex;not null;size:64"`
+	ExpiresAt      time.Time  `gorm:"index;not null"`
+	UsedAt         *time.Time `gorm:""`
+	CreatedAt      time.Time
+}
+
+type GatewayTicketResponse struct {
+	Ticket    string    `json:"ticket"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
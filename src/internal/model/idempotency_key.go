@@ -0,0 +1,28 @@
+// src/internal/model/idempotency_key.go
+package model
+
+import "time"
+
+// IdempotencyKey stores the fingerprint of a request sent with an
+// Idempotency-Key header together with the response it produced, so that
+// retries of the same request can be answered without executing it again.
+// Keys are scoped to the organization and credentials of the caller, and
+// LockID tells apart the requests that held the same key over time.
+type IdempotencyKey struct {
+	Scope          string     `gorm:"primaryKey;size:64"`
+	Key            string     `gorm:"primaryKey;size:255"`
+	LockID         string     `gorm:"not null;size:36"`
+	RequestHash    string     `gorm:"not null;size:64"`
+	ResponseStatus int        `gorm:"not null;default:0"`
+	ResponseBody   []byte     `gorm:""`
+	ContentType    string     `gorm:"size:255"`
+	LockedUntil    time.Time  `gorm:"not null"`
+	CompletedAt    *time.Time `gorm:""`
+	ExpiresAt      time.Time  `gorm:"index;not null"`
+	CreatedAt      time.Time
+}
+
+// IsCompleted reports whether a response has been recorded for the key.
+func (k *IdempotencyKey) IsCompleted() bool {
+	return k.CompletedAt != nil
+}
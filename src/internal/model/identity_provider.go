@@ -0,0 +1,214 @@
+package model
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+// Scopes a client of the identity provider can request. ScopeOpenID is
+// required in every authorization request.
+const (
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+// Scopes for managing the clients of the identity provider.
+const (
+	ScopeOAuthClientsRead  = "oauth_clients:read"
+	ScopeOAuthClientsWrite = "oauth_clients:write"
+)
+
+// RedirectURIList is a list of redirect URIs stored as a space separated
+// string, like ScopeList.
+type RedirectURIList []string
+
+func (l RedirectURIList) Value() (driver.Value, error) {
+	return ScopeList(l).Value()
+}
+
+func (l *RedirectURIList) Scan(value interface{}) error {
+	return (*ScopeList)(l).Scan(value)
+}
+
+// OAuthClient is an app that signs users in through this service. Public
+// clients have no secret and must use PKCE.
+type OAuthClient struct {
+	ID           uint            `gorm:"primaryKey"`
+	ClientID     string          `gorm:"uniqueIndex;not null;size:64"`
+	SecretHash   string          `gorm:"size:64"`
+	Name         string          `gorm:"not null;size:100"`
+	RedirectURIs RedirectURIList `gorm:"type:text;not null"`
+	Scopes       ScopeList       `gorm:"type:text;not null"`
+	RevokedAt    *time.Time      `gorm:""`
+	CreatedAt    time.Time
+}
+
+// TableName keeps GORM from naming the table "o_auth_clients".
+func (OAuthClient) TableName() string {
+	return "oauth_clients"
+}
+
+// IsPublic reports whether the client cannot keep a secret, such as a
+// single-page or mobile app.
+func (c *OAuthClient) IsPublic() bool {
+	return c.SecretHash == ""
+}
+
+// OAuthConsent records the scopes a user has allowed a client to access.
+type OAuthConsent struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"uniqueIndex:idx_oauth_consents_user_client;not null"`
+	ClientID  uint      `gorm:"uniqueIndex:idx_oauth_consents_user_client;not null"`
+	Scopes    ScopeList `gorm:"type:text;not null"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (OAuthConsent) TableName() string {
+	return "oauth_consents"
+}
+
+// OAuthAuthorizationCode is an issued code that has not been redeemed yet.
+// Only a hash of the code is stored.
+type OAuthAuthorizationCode struct {
+	CodeHash      string    `gorm:"primaryKey;size:64"`
+	ClientID      uint      `gorm:"not null"`
+	UserID        uint      `gorm:"not null"`
+	RedirectURI   string    `gorm:"not null;size:2048"`
+	Scopes        ScopeList `gorm:"type:text;not null"`
+	Nonce         string    `gorm:"size:255"`
+	CodeChallenge string    `gorm:"size:128"`
+	AuthTime      time.Time `gorm:"not null"`
+	ExpiresAt     time.Time `gorm:"index;not null"`
+	CreatedAt     time.Time
+}
+
+func (OAuthAuthorizationCode) TableName() string {
+	return "oauth_authorization_codes"
+}
+
+// SigningKey is an RSA key that signs ID and access tokens. The private key
+// is stored encrypted; ID is published as the key ID.
+type SigningKey struct {
+	ID                  string `gorm:"primaryKey;size:32"`
+	PrivateKeyEncrypted string `gorm:"type:text;not null"`
+	CreatedAt           time.Time
+}
+
+type CreateOAuthClientRequest struct {
+	Name         string   `json:"name" validate:"required,max=100"`
+	RedirectURIs []string `json:"redirect_uris" validate:"required,min=1,dive,url,max=2048"`
+	Scopes       []string `json:"scopes" validate:"required,min=1,dive,oneof=openid profile email"`
+	Public       bool     `json:"public"`
+}
+
+type OAuthClientResponse struct {
+	ID           uint       `json:"id"`
+	ClientID     string     `json:"client_id"`
+	Name         string     `json:"name"`
+	RedirectURIs []string   `json:"redirect_uris"`
+	Scopes       []string   `json:"scopes"`
+	Public       bool       `json:"public"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// CreateOAuthClientResponse carries the client secret, which is only shown
+// once. Public clients have none.
+type CreateOAuthClientResponse struct {
+	OAuthClientResponse
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+type OAuthConsentResponse struct {
+	ClientID   string    `json:"client_id"`
+	ClientName string    `json:"client_name"`
+	Scopes     []string  `json:"scopes"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// AuthorizeRequest is an OpenID Connect authentication request. Consent is
+// the user's answer to a consent prompt; it is only read from POST requests.
+type AuthorizeRequest struct {
+	ResponseType        string `json:"response_type" query:"response_type" form:"response_type"`
+	ClientID            string `json:"client_id" query:"client_id" form:"client_id"`
+	RedirectURI         string `json:"redirect_uri" query:"redirect_uri" form:"redirect_uri"`
+	Scope               string `json:"scope" query:"scope" form:"scope"`
+	State               string `json:"state" query:"state" form:"state"`
+	Nonce               string `json:"nonce" query:"nonce" form:"nonce"`
+	Prompt              string `json:"prompt" query:"prompt" form:"prompt"`
+	CodeChallenge       string `json:"code_challenge" query:"code_challenge" form:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method" query:"code_challenge_method" form:"code_challenge_method"`
+	Consent             *bool  `json:"consent" query:"-" form:"consent"`
+}
+
+// AuthorizeResponse tells the login page what to do next: either send the
+// browser to RedirectTo, or ask the user to allow the client the scopes.
+type AuthorizeResponse struct {
+	RedirectTo      string   `json:"redirect_to,omitempty"`
+	ConsentRequired bool     `json:"consent_required,omitempty"`
+	ClientName      string   `json:"client_name,omitempty"`
+	Scopes          []string `json:"scopes,omitempty"`
+}
+
+// TokenRequest is a request to the token endpoint. Client credentials may
+// also be sent with HTTP basic authentication.
+type TokenRequest struct {
+	GrantType    string `form:"grant_type"`
+	Code         string `form:"code"`
+	RedirectURI  string `form:"redirect_uri"`
+	CodeVerifier string `form:"code_verifier"`
+	ClientID     string `form:"client_id"`
+	ClientSecret string `form:"client_secret"`
+}
+
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	IDToken     string `json:"id_token"`
+	Scope       string `json:"scope"`
+}
+
+// OpenIDConfiguration is the discovery document of the identity provider.
+type OpenIDConfiguration struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+// JSONWebKey is a public RSA key in JWK format (RFC 7517).
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// UserInfo holds the claims about a user that a client may see. The profile
+// and email fields are only set when the matching scope was granted.
+type UserInfo struct {
+	Subject           string `json:"sub"`
+	PreferredUsername string `json:"preferred_username,omitempty"`
+	UpdatedAt         int64  `json:"updated_at,omitempty"`
+	Email             string `json:"email,omitempty"`
+	EmailVerified     *bool  `json:"email_verified,omitempty"`
+}
@@ -0,0 +1,74 @@
+package model
+
+import "time"
+
+// Invitation statuses, derived from the timestamps of an invitation.
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusRevoked  = "revoked"
+	InvitationStatusExpired  = "expired"
+)
+
+// PermissionInvitationsManage lets a member invite people into the
+// organization and manage the invitations. Owners and admins hold it;
+// groups can be granted it.
+const PermissionInvitationsManage = "invitations:manage"
+
+// Invitation lets the owner of Email create an account with Role in the
+// organization that invited them. The link mailed to them carries a
+// single-use token of which only the SHA-256 hash is stored; resending
+// replaces it. InvitedByID is nil for invitations made with a service API
+// key.
+type Invitation struct {
+	ID             uint       `gorm:"primaryKey"`
+	OrganizationID uint       `gorm:"index;not null"`
+	Email          string     `gorm:"index;not null;size:255"`
+	Role           string     `gorm:"not null;size:32"`
+	TokenHash      string     `gorm:"uniqueIndex;not null;size:64"`
+	InvitedByID    *uint      `gorm:"index"`
+	ExpiresAt      time.Time  `gorm:"not null"`
+	AcceptedAt     *time.Time `gorm:""`
+	RevokedAt      *time.Time `gorm:""`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Status reports the state of the invitation at the given time.
+func (i *Invitation) Status(now time.Time) string {
+	switch {
+	case i.AcceptedAt != nil:
+		return InvitationStatusAccepted
+	case i.RevokedAt != nil:
+		return InvitationStatusRevoked
+	case !now.Before(i.ExpiresAt):
+		return InvitationStatusExpired
+	default:
+		return InvitationStatusPending
+	}
+}
+
+type CreateInvitationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"omitempty,oneof=user admin"`
+}
+
+// AcceptInvitationRequest creates the invited account. The email address
+// comes from the invitation.
+type AcceptInvitationRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Username string `json:"username" validate:"required,min=3,max=50"`
+	Password string `json:"password" validate:"required,min=6"`
+}
+
+type InvitationResponse struct {
+	ID          uint       `json:"id"`
+	Email       string     `json:"email"`
+	Role        string     `json:"role"`
+	Status      string     `json:"status"`
+	InvitedByID *uint      `json:"invited_by_id"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	AcceptedAt  *time.Time `json:"accepted_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
@@ -0,0 +1,66 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Scopes and permission for inspecting and managing background jobs.
+// Owners and admins hold jobs:manage; groups can be granted it.
+const (
+	ScopeJobsRead        = "jobs:read"
+	ScopeJobsWrite       = "jobs:write"
+	PermissionJobsManage = "jobs:manage"
+)
+
+// Statuses of a job. A pending job runs once RunAt has passed; a job that
+// failed its last attempt stays failed until it is retried by hand.
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+	JobCancelled = "cancelled"
+)
+
+// Job is a unit of background work of a registered type, with its payload
+// as JSON. A job enqueued within an organization belongs to it; jobs
+// enqueued outside one have OrganizationID 0. Only one unfinished (pending
+// or running) job can hold a UniqueKey. A running job is held by its worker until
+// LockedUntil, after which another worker claims it.
+type Job struct {
+	ID             uint       `gorm:"primaryKey"`
+	OrganizationID uint       `gorm:"index;not null;default:0"`
+	Type           string     `gorm:"index;not null;size:100"`
+	Payload        string     `gorm:"type:text;not null"`
+	Status         string     `gorm:"index:idx_jobs_status_run_at,priority:1;not null;size:16"`
+	UniqueKey      *string    `gorm:"size:255;uniqueIndex:idx_jobs_unique_key,where:finished_at IS NULL"`
+	Attempts       int        `gorm:"not null;default:0"`
+	MaxAttempts    int        `gorm:"not null"`
+	RunAt          time.Time  `gorm:"index:idx_jobs_status_run_at,priority:2;not null"`
+	LockedUntil    *time.Time `gorm:"index"`
+	LastError      string     `gorm:"type:text"`
+	FinishedAt     *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type JobFilter struct {
+	Status string
+	Type   string
+}
+
+type JobResponse struct {
+	ID          uint            `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload" swaggertype:"object"`
+	Status      string          `json:"status"`
+	UniqueKey   *string         `json:"unique_key"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	RunAt       time.Time       `json:"run_at"`
+	LastError   string          `json:"last_error,omitempty"`
+	FinishedAt  *time.Time      `json:"finished_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import "time"
+
+// ClientInfo describes the client a request came from.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+// LoginAttempt is an entry of the login history. UserID is nil when the
+// login did not match any user.
+type LoginAttempt struct {
+	ID        uint      `gorm:"primaryKey"`
+	UserID    *uint     `gorm:"index"`
+	Login     string    `gorm:"not null;size:255"`
+	IP        string    `gorm:"index;size:64"`
+	UserAgent string    `gorm:"size:512"`
+	Success   bool      `gorm:"not null"`
+	Reason    string    `gorm:"size:32"`
+	CreatedAt time.Time `gorm:"index"`
+}
+
+// LoginThrottle counts recent failed logins of a subject, which is either
+// an account or a client IP.
+type LoginThrottle struct {
+	Subject       string     `gorm:"primaryKey;size:320"`
+	Failures      int        `gorm:"not null;default:0"`
+	LastFailureAt time.Time  `gorm:"not null"`
+	LockedUntil   *time.Time `gorm:""`
+	UpdatedAt     time.Time
+}
+
+type LoginAttemptResponse struct {
+	ID        uint      `json:"id"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	Success   bool      `json:"success"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
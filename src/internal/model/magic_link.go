@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// MagicLinkToken is a single-use login token mailed to Email. The address
+// may not belong to a user yet; redeeming the token then creates one. Only
+// the SHA-256 hash of the token is stored.
+type MagicLinkToken struct {
+	ID        uint       `gorm:"primaryKey"`
+	Email     string     `gorm:"index;not null;size:255"`
+	TokenHash string     `gorm:"uniqueIndex;not null;size:64"`
+	ExpiresAt time.Time  `gorm:"not null"`
+	UsedAt    *time.Time `gorm:""`
+	CreatedAt time.Time
+}
+
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type RedeemMagicLinkRequest struct {
+	Token string `json:"token" validate:"required"`
+}
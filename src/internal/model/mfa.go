@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+// Role names. Roles listed in mfa.required_roles must use a second factor.
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// MFACredential is the TOTP second factor of a user. The secret is stored
+// encrypted. LastUsedStep is the time step of the last accepted code so that
+// a code cannot be replayed.
+type MFACredential struct {
+	ID              uint       `gorm:"primaryKey"`
+	UserID          uint       `gorm:"uniqueIndex;not null"`
+	SecretEncrypted string     `gorm:"not null"`
+	ConfirmedAt     *time.Time `gorm:""`
+	LastUsedStep    int64      `gorm:"not null;default:0"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// IsConfirmed reports whether the user has proven possession of the secret.
+func (c *MFACredential) IsConfirmed() bool {
+	return c.ConfirmedAt != nil
+}
+
+// MFARecoveryCode is a single-use code that replaces a TOTP code when the
+// authenticator is lost. Only the SHA-256 hash is stored.
+type MFARecoveryCode struct {
+	ID        uint       `gorm:"primaryKey"`
+	UserID    uint       `gorm:"index;not null"`
+	CodeHash  string     `gorm:"uniqueIndex;not null;size:64"`
+	UsedAt    *time.Time `gorm:""`
+	CreatedAt time.Time
+}
+
+type MFAEnrollResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// MFALoginRequest completes a login that returned an MFA challenge. Code is
+// either a TOTP code or a recovery code.
+type MFALoginRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
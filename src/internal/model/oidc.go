@@ -0,0 +1,47 @@
+// src/internal/model/oidc.go
+package model
+
+import "time"
+
+// ExternalIdentity links a user to an account at an external OpenID Connect
+// provider. Subject is the provider's stable ID of that account.
+type ExternalIdentity struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      uint      `json:"user_id" gorm:"index;not null"`
+	Provider    string    `json:"provider" gorm:"uniqueIndex:idx_external_identities_provider_subject;not null;size:64"`
+	Subject     string    `json:"subject" gorm:"uniqueIndex:idx_external_identities_provider_subject;not null;size:255"`
+	Email       string    `json:"email" gorm:"size:255"`
+	LastLoginAt time.Time `json:"last_login_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// OIDCLoginState is a sign-in that was sent to a provider and has not come
+// back yet. Only a hash of the state parameter is stored.
+type OIDCLoginState struct {
+	StateHash    string    `gorm:"primaryKey;size:64"`
+	Provider     string    `gorm:"not null;size:64"`
+	Nonce        string    `gorm:"not null;size:64"`
+	CodeVerifier string    `gorm:"not null;size:128"`
+	ExpiresAt    time.Time `gorm:"index;not null"`
+	CreatedAt    time.Time
+}
+
+// OIDCAuthorization is where to send the user to sign in at a provider.
+type OIDCAuthorization struct {
+	AuthorizationURL string    `json:"authorization_url"`
+	State            string    `json:"-"`
+	ExpiresAt        time.Time `json:"-"`
+}
+
+type OIDCCallbackRequest struct {
+	Code  string `query:"code" validate:"required,max=2048"`
+	State string `query:"state" validate:"required,max=128"`
+}
+
+type ExternalIdentityResponse struct {
+	ID          uint      `json:"id"`
+	Provider    string    `json:"provider"`
+	Email       string    `json:"email"`
+	LastLoginAt time.Time `json:"last_login_at"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,70 @@
+package model
+
+import "time"
+
+// Roles of a member within an organization. They are separate from the
+// global Role of a user: an owner can manage the members of their
+// organization without being an admin of the service.
+const (
+	OrganizationRoleOwner  = "owner"
+	OrganizationRoleAdmin  = "admin"
+	OrganizationRoleMember = "member"
+)
+
+// Scopes for managing organizations and their members.
+const (
+	ScopeOrganizationsRead  = "organizations:read"
+	ScopeOrganizationsWrite = "organizations:write"
+)
+
+// Organization is a tenant. Users belong to the organization they signed up
+// in, and the data of one organization is invisible to the others. Slug
+// names the organization in subdomains and the tenant header.
+type Organization struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"not null;size:100"`
+	Slug      string `gorm:"uniqueIndex;not null;size:63"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Membership gives a user a role in an organization. Every user is a member
+// of their home organization; owners and admins can add them to others.
+type Membership struct {
+	ID             uint   `gorm:"primaryKey"`
+	OrganizationID uint   `gorm:"uniqueIndex:idx_memberships_organization_user;not null"`
+	UserID         uint   `gorm:"uniqueIndex:idx_memberships_organization_user;index;not null"`
+	Role           string `gorm:"not null;size:32"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+
+	Organization *Organization `gorm:"constraint:OnDelete:CASCADE"`
+}
+
+type CreateOrganizationRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+	Slug string `json:"slug" validate:"required,min=2,max=63"`
+}
+
+type AddMemberRequest struct {
+	UserID uint   `json:"user_id" validate:"required"`
+	Role   string `json:"role" validate:"omitempty,oneof=owner admin member"`
+}
+
+type UpdateMemberRequest struct {
+	Role string `json:"role" validate:"required,oneof=owner admin member"`
+}
+
+type OrganizationResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	Role      string    `json:"role,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type MembershipResponse struct {
+	UserID    uint      `json:"user_id"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// OutboxMessage is a domain event stored in the same transaction as the
+// change it describes, until the relay has published it. Events of one
+// aggregate are published in ID order.
+type OutboxMessage struct {
+	ID             uint   `gorm:"primaryKey"`
+	OrganizationID uint   `gorm:"index;not null"`
+	EventType      string `gorm:"not null;size:64"`
+	AggregateType  string `gorm:"index:idx_outbox_messages_aggregate,priority:1;not null;size:32"`
+	AggregateID    uint   `gorm:"index:idx_outbox_messages_aggregate,priority:2;not null"`
+	// Payload is the event encoded as JSON.
+	Payload  string `gorm:"type:text;not null"`
+	Attempts int    `gorm:"not null;default:0"`
+	// NextAttemptAt is when the relay may next try to publish the event,
+	// after a failure or once the lease of a relay that claimed it expires.
+	NextAttemptAt time.Time `gorm:"not null"`
+	LastError     string    `gorm:"type:text"`
+	PublishedAt   *time.Time
+	CreatedAt     time.Time
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"database/sql/driver"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+)
+
+// Purposes of a passkey challenge.
+const (
+	PasskeyPurposeRegistration = "registration"
+	PasskeyPurposeLogin        = "login"
+)
+
+// TransportList is a list of authenticator transports, such as "usb" or
+// "internal", stored like ScopeList.
+type TransportList []string
+
+func (l TransportList) Value() (driver.Value, error) {
+	return ScopeList(l).Value()
+}
+
+func (l *TransportList) Scan(value interface{}) error {
+	return (*ScopeList)(l).Scan(value)
+}
+
+// Passkey is a WebAuthn credential a user can log in with. CredentialID is
+// the base64url-encoded ID the authenticator chose and PublicKey its
+// COSE_Key. SignCount is the last signature counter seen. BackupEligible
+// tells whether the passkey can be synced to other devices, which must not
+// change; it is nil for passkeys registered before it was stored, until
+// their next login.
+type Passkey struct {
+	ID             uint          `gorm:"primaryKey"`
+	UserID         uint          `gorm:"index;not null"`
+	Name           string        `gorm:"not null;size:100"`
+	CredentialID   string        `gorm:"uniqueIndex;not null;size:1400"`
+	PublicKey      []byte        `gorm:"not null"`
+	SignCount      int64         `gorm:"not null"`
+	BackupEligible *bool         `gorm:""`
+	Transports     TransportList `gorm:"type:text"`
+	LastUsedAt     *time.Time    `gorm:""`
+	CreatedAt      time.Time
+}
+
+// PasskeyChallenge is a registration or login that has been started but not
+// finished. It is stored under the hash of its challenge, which the browser
+// returns in the client data. UserID is set for registrations.
+type PasskeyChallenge struct {
+	ChallengeHash string    `gorm:"primaryKey;size:64"`
+	Purpose       string    `gorm:"not null;size:16"`
+	UserID        *uint     `gorm:""`
+	ExpiresAt     time.Time `gorm:"index;not null"`
+	CreatedAt     time.Time
+}
+
+// RegisterPasskeyRequest finishes a registration. Credential is the result
+// of navigator.credentials.create() serialized with toJSON().
+type RegisterPasskeyRequest struct {
+	Name       string                              `json:"name" validate:"required,max=100"`
+	Credential protocol.CredentialCreationResponse `json:"credential" swaggertype:"object"`
+}
+
+// PasskeyLoginRequest finishes a login. Credential is the result of
+// navigator.credentials.get() serialized with toJSON().
+type PasskeyLoginRequest struct {
+	Credential protocol.CredentialAssertionResponse `json:"credential" swaggertype:"object"`
+}
+
+type PasskeyResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,17 @@
+package model
+
+import "time"
+
+// Presence statuses. A user is online while connected to the gateway.
+const (
+	PresenceOnline  = "online"
+	PresenceOffline = "offline"
+)
+
+// PresenceResponse tells whether a user is connected to the gateway and
+// when they last were. LastSeenAt is nil for users who never connected.
+type PresenceResponse struct {
+	UserID     uint       `json:"user_id"`
+	Status     string     `json:"status"`
+	LastSeenAt *time.Time `json:"last_seen_at"`
+}
@@ -0,0 +1,22 @@
+package model
+
+// Principal is the authenticated caller of a request: a user with a session,
+// or an API key owned by a user or a service. OrganizationID is the
+// organization a service key is bound to.
+type Principal struct {
+	UserID         uint
+	SessionID      string
+	APIKeyID       uint
+	Service        string
+	OrganizationID uint
+	Scopes         ScopeList
+}
+
+// HasScope reports whether the caller may use the scope. Sessions may use
+// every scope; API keys only the ones they were granted.
+func (p *Principal) HasScope(scope string) bool {
+	if p.APIKeyID == 0 {
+		return true
+	}
+	return p.Scopes.Contains(scope)
+}
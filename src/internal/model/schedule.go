@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// Scopes and permission for inspecting and triggering scheduled tasks,
+// which are shared by all organizations. Owners and admins hold
+// schedules:manage; groups can be granted it.
+const (
+	ScopeSchedulesRead        = "schedules:read"
+	ScopeSchedulesWrite       = "schedules:write"
+	PermissionSchedulesManage = "schedules:manage"
+)
+
+// Outcomes of a scheduled run. A run is skipped when it was missed by more
+// than the scheduler allows and its task does not catch up.
+const (
+	ScheduleSucceeded = "succeeded"
+	ScheduleFailed    = "failed"
+	ScheduleSkipped   = "skipped"
+)
+
+// Schedule is the state of a scheduled task, shared by all replicas: when
+// it next runs and how its last run went. RunningSince is set while a run
+// is in progress.
+type Schedule struct {
+	Name           string    `gorm:"primaryKey;size:100"`
+	Expression     string    `gorm:"not null;size:100"`
+	NextRunAt      time.Time `gorm:"not null"`
+	RunningSince   *time.Time
+	LastRunAt      *time.Time
+	LastStatus     string `gorm:"size:16"`
+	LastError      string `gorm:"type:text"`
+	LastDurationMS int64  `gorm:"column:last_duration_ms;not null;default:0"`
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// Lease is a named lock held by one process until it expires. The holder
+// renews it to keep it.
+type Lease struct {
+	Name      string    `gorm:"primaryKey;size:100"`
+	Holder    string    `gorm:"not null;size:255"`
+	ExpiresAt time.Time `gorm:"not null"`
+}
+
+type ScheduleResponse struct {
+	Name           string     `json:"name"`
+	Expression     string     `json:"expression"`
+	NextRunAt      time.Time  `json:"next_run_at"`
+	Running        bool       `json:"running"`
+	LastRunAt      *time.Time `json:"last_run_at"`
+	LastStatus     string     `json:"last_status,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	LastDurationMS int64      `json:"last_duration_ms"`
+}
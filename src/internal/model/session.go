@@ -0,0 +1,49 @@
+// src/internal/model/session.go
+package model
+
+import "time"
+
+// Session is a server-side login session. Access tokens carry the session ID
+// so that revoking the session invalidates every token issued for it.
+type Session struct {
+	ID         string     `json:"id" gorm:"primaryKey;size:36"`
+	UserID     uint       `json:"user_id" gorm:"index;not null"`
+	Device     string     `json:"device" gorm:"size:64"`
+	IP         string     `json:"ip" gorm:"size:64"`
+	UserAgent  string     `json:"user_agent" gorm:"size:512"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// IsActive reports whether the session can still be used at the given time.
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	Device     string    `json:"device"`
+	IP         string    `json:"ip"`
+	UserAgent  string    `json:"user_agent"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+type LoginRequest struct {
+	Login    string `json:"login" validate:"required,max=255"`
+	Password string `json:"password" validate:"required"`
+}
+
+// LoginResponse carries either an access token or, for users with a second
+// factor, an MFA challenge token to be completed at /auth/login/mfa.
+// ExpiresAt is the expiry of whichever token was issued.
+type LoginResponse struct {
+	AccessToken string    `json:"access_token,omitempty"`
+	TokenType   string    `json:"token_type,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at"`
+	MFARequired bool      `json:"mfa_required,omitempty"`
+	MFAToken    string    `json:"mfa_token,omitempty"`
+}
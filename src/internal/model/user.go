@@ -7,14 +7,31 @@ import (
 	"gorm.io/gorm"
 )
 
+// PermissionUsersManage lets a member manage the credentials and sessions
+// of other users of the organization. Owners and admins hold it; groups can
+// be granted it.
+const PermissionUsersManage = "users:manage"
+
+// User is an account. It belongs to the organization it was created in,
+// within which its Username is unique; Email is unique across all of them
+// since it signs the user in.
 type User struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Username  string         `json:"username" gorm:"uniqueIndex;not null;size:50"`
-	Email     string         `json:"email" gorm:"uniqueIndex;not null;size:255"`
-	Password  string         `json:"-" gorm:"not null"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	OrganizationID uint           `json:"organization_id" gorm:"uniqueIndex:idx_users_organization_username,priority:1;not null"`
+	Username       string         `json:"username" gorm:"uniqueIndex:idx_users_organization_username,priority:2;not null;size:50"`
+	Email          string         `json:"email" gorm:"uniqueIndex;not null;size:255"`
+	Password       string         `json:"-" gorm:"not null"`
+	Role           string         `json:"role" gorm:"not null;default:user;size:32"`
+	Version        uint           `json:"version" gorm:"not null;default:1"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// EmailVerifiedAt is set once the owner of Email has confirmed it.
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	// PendingEmail is a requested new address that replaces Email once it
+	// has been confirmed.
+	PendingEmail *string `json:"pending_email" gorm:"size:255"`
 }
 
 type CreateUserRequest struct {
@@ -28,10 +45,23 @@ type UpdateUserRequest struct {
 	Email    string `json:"email" validate:"omitempty,email"`
 }
 
+// PatchUserRequest holds the writable fields of a user after a PATCH document
+// has been applied. Unlike UpdateUserRequest every field is required, since an
+// empty value means the patch cleared it.
+type PatchUserRequest struct {
+	Username string `json:"username" validate:"required,min=3,max=50"`
+	Email    string `json:"email" validate:"required,email"`
+}
+
 type UserResponse struct {
-	ID        uint      `json:"id"`
-	Username  string    `json:"username"`
-	Email     string    `json:"email"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID              uint       `json:"id"`
+	OrganizationID  uint       `json:"organization_id"`
+	Username        string     `json:"username"`
+	Email           string     `json:"email"`
+	Role            string     `json:"role"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	PendingEmail    *string    `json:"pending_email"`
+	Version         uint       `json:"version"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 }
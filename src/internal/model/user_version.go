@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// UserVersion is a user as it was at one version. A version is stored on
+// every change to the user, so the version current at a time is the newest
+// one created before it. Passwords are not kept.
+type UserVersion struct {
+	ID              uint    `gorm:"primaryKey"`
+	OrganizationID  uint    `gorm:"index;not null"`
+	UserID          uint    `gorm:"uniqueIndex:idx_user_versions_user_version,priority:1;not null"`
+	Version         uint    `gorm:"uniqueIndex:idx_user_versions_user_version,priority:2;not null"`
+	Username        string  `gorm:"not null;size:50"`
+	Email           string  `gorm:"not null;size:255"`
+	PendingEmail    *string `gorm:"size:255"`
+	Role            string  `gorm:"not null;size:32"`
+	EmailVerifiedAt *time.Time
+	// CreatedAt is when the version became current.
+	CreatedAt time.Time `gorm:"index"`
+}
+
+type UserVersionResponse struct {
+	Version         uint       `json:"version"`
+	Username        string     `json:"username"`
+	Email           string     `json:"email"`
+	PendingEmail    *string    `json:"pending_email"`
+	Role            string     `json:"role"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
@@ -0,0 +1,173 @@
+package model
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+// Scopes and permission for managing the webhooks of an organization.
+// Owners and admins hold webhooks:manage; groups can be granted it.
+const (
+	ScopeWebhooksRead        = "webhooks:read"
+	ScopeWebhooksWrite       = "webhooks:write"
+	PermissionWebhooksManage = "webhooks:manage"
+)
+
+// WebhookAllEvents subscribes a webhook to every event type.
+const WebhookAllEvents = "*"
+
+// Statuses of a webhook delivery. A delivery that failed its last attempt
+// is dead-lettered: it stays failed until it is redelivered by hand.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// EventTypeList is a list of event types stored as a space separated
+// string, like ScopeList.
+type EventTypeList []string
+
+func (l EventTypeList) Value() (driver.Value, error) {
+	return ScopeList(l).Value()
+}
+
+func (l *EventTypeList) Scan(value interface{}) error {
+	return (*ScopeList)(l).Scan(value)
+}
+
+// Webhook is an endpoint of an organization that receives its events. The
+// secret that signs the deliveries is stored encrypted. A webhook is
+// disabled once too many attempts in a row have failed.
+type Webhook struct {
+	ID                  uint          `gorm:"primaryKey"`
+	OrganizationID      uint          `gorm:"index;not null"`
+	URL                 string        `gorm:"not null;size:2048"`
+	EventTypes          EventTypeList `gorm:"type:text;not null"`
+	Description         string        `gorm:"size:255"`
+	SecretEncrypted     string        `gorm:"type:text;not null"`
+	ConsecutiveFailures int           `gorm:"not null;default:0"`
+	DisabledAt          *time.Time
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// Subscribes reports whether the webhook receives events of the type.
+func (w *Webhook) Subscribes(eventType string) bool {
+	for _, t := range w.EventTypes {
+		if t == WebhookAllEvents || t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is an event to be sent to a webhook. EventID is the ID of
+// the event in the outbox, so an event is delivered to a webhook once.
+type WebhookDelivery struct {
+	ID             uint     `gorm:"primaryKey"`
+	OrganizationID uint     `gorm:"index;not null"`
+	WebhookID      uint     `gorm:"uniqueIndex:idx_webhook_deliveries_webhook_event,priority:1;not null"`
+	Webhook        *Webhook `gorm:"foreignKey:WebhookID"`
+	EventID        uint     `gorm:"uniqueIndex:idx_webhook_deliveries_webhook_event,priority:2;not null"`
+	EventType      string   `gorm:"not null;size:64"`
+	// Payload is the request body, the event encoded as JSON.
+	Payload  string `gorm:"type:text;not null"`
+	Status   string `gorm:"index;not null;size:16"`
+	Attempts int    `gorm:"not null;default:0"`
+	// NextAttemptAt is when a pending delivery is next sent, after a
+	// failure or once the lease of a worker that claimed it expires.
+	NextAttemptAt      time.Time `gorm:"not null"`
+	LastResponseStatus int       `gorm:"not null;default:0"`
+	LastError          string    `gorm:"type:text"`
+	DeliveredAt        *time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// WebhookDeliveryAttempt records one attempt to send a delivery.
+// ResponseStatus is zero if no response was received.
+type WebhookDeliveryAttempt struct {
+	ID             uint   `gorm:"primaryKey"`
+	OrganizationID uint   `gorm:"index;not null"`
+	DeliveryID     uint   `gorm:"index;not null"`
+	ResponseStatus int    `gorm:"not null"`
+	Error          string `gorm:"type:text"`
+	DurationMS     int64  `gorm:"column:duration_ms;not null"`
+	CreatedAt      time.Time
+}
+
+// Succeeded reports whether the endpoint accepted the delivery with a 2xx
+// response.
+func (a *WebhookDeliveryAttempt) Succeeded() bool {
+	return a.Error == "" && a.ResponseStatus >= 200 && a.ResponseStatus < 300
+}
+
+// CreateWebhookRequest subscribes URL to EventTypes, or to all events with
+// "*". A secret is generated unless one is given.
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" validate:"required,url,max=2048"`
+	EventTypes  []string `json:"event_types" validate:"required,min=1,dive,oneof=* user.created user.updated user.deleted"`
+	Description string   `json:"description" validate:"max=255"`
+	Secret      string   `json:"secret" validate:"omitempty,min=16,max=255"`
+}
+
+// UpdateWebhookRequest replaces the settings of a webhook. Enabling a
+// disabled webhook resumes its pending deliveries, and a nil Enabled keeps
+// the webhook as it is. A Secret replaces the current one.
+type UpdateWebhookRequest struct {
+	URL         string   `json:"url" validate:"required,url,max=2048"`
+	EventTypes  []string `json:"event_types" validate:"required,min=1,dive,oneof=* user.created user.updated user.deleted"`
+	Description string   `json:"description" validate:"max=255"`
+	Enabled     *bool    `json:"enabled"`
+	Secret      string   `json:"secret" validate:"omitempty,min=16,max=255"`
+}
+
+type WebhookResponse struct {
+	ID                  uint       `json:"id"`
+	URL                 string     `json:"url"`
+	EventTypes          []string   `json:"event_types"`
+	Description         string     `json:"description"`
+	Enabled             bool       `json:"enabled"`
+	DisabledAt          *time.Time `json:"disabled_at"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// CreateWebhookResponse carries the signing secret, which is only shown
+// once.
+type CreateWebhookResponse struct {
+	WebhookResponse
+	Secret string `json:"secret"`
+}
+
+// WebhookDeliveryFilter selects deliveries of a webhook. An empty Status
+// matches all.
+type WebhookDeliveryFilter struct {
+	Status string
+}
+
+type WebhookDeliveryResponse struct {
+	ID                 uint       `json:"id"`
+	WebhookID          uint       `json:"webhook_id"`
+	EventID            uint       `json:"event_id"`
+	EventType          string     `json:"event_type"`
+	Status             string     `json:"status"`
+	Attempts           int        `json:"attempts"`
+	NextAttemptAt      *time.Time `json:"next_attempt_at,omitempty"`
+	LastResponseStatus int        `json:"last_response_status"`
+	LastError          string     `json:"last_error,omitempty"`
+	DeliveredAt        *time.Time `json:"delivered_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+	// Log lists the attempts, oldest first. It is only part of a single
+	// delivery.
+	Log []*WebhookDeliveryAttemptResponse `json:"log,omitempty"`
+}
+
+type WebhookDeliveryAttemptResponse struct {
+	ResponseStatus int       `json:"response_status"`
+	Error          string    `json:"error,omitempty"`
+	DurationMS     int64     `json:"duration_ms"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,85 @@
+package oidc
+
+import (
+	"encoding/base64"
+	"errors"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *OIDCTestSuite) jwkSet() *jsonWebKeySet {
+	encode := base64.RawURLEncoding.EncodeToString
+	return &jsonWebKeySet{Keys: []jsonWebKey{
+		{Kty: "EC", Kid: "ec", Use: "sig", Crv: "P-256", X: encode(s.ecKey.X.Bytes()), Y: encode(s.ecKey.Y.Bytes())},
+		{Kty: "RSA", Kid: "rsa", N: encode(s.rsaKey.N.Bytes()), E: "AQAB"},
+		{Kty: "RSA", Kid: "enc", Use: "enc", N: encode(s.rsaKey.N.Bytes()), E: "AQAB"},
+		{Kty: "EC", Kid: "off-curve", Crv: "P-256", X: encode([]byte{1}), Y: encode([]byte{2})},
+		{Kty: "oct", Kid: "symmetric"},
+	}}
+}
+
+func (s *OIDCTestSuite) TestParseKeySet() {
+	keys := parseKeySet(s.jwkSet())
+
+	assert.Len(s.T(), keys, 2)
+	assert.True(s.T(), s.ecKey.PublicKey.Equal(keys["ec"]))
+	assert.True(s.T(), s.rsaKey.PublicKey.Equal(keys["rsa"]))
+}
+
+func (s *OIDCTestSuite) TestGet_FetchesOnceForKnownKeys() {
+	fetches := 0
+	set := newKeySet(func() (*jsonWebKeySet, error) {
+		fetches++
+		return s.jwkSet(), nil
+	})
+
+	_, err := set.get("ec")
+	assert.NoError(s.T(), err)
+	_, err = set.get("rsa")
+	assert.NoError(s.T(), err)
+
+	assert.Equal(s.T(), 1, fetches)
+}
+
+func (s *OIDCTestSuite) TestGet_UnknownKeyIsRateLimited() {
+	fetches := 0
+	set := newKeySet(func() (*jsonWebKeySet, error) {
+		fetches++
+		return s.jwkSet(), nil
+	})
+
+	for i := 0; i < 3; i++ {
+		_, err := set.get("missing")
+		assert.ErrorIs(s.T(), err, errUnknownKey)
+	}
+
+	assert.Equal(s.T(), 1, fetches)
+}
+
+func (s *OIDCTestSuite) TestGet_WithoutKeyID() {
+	set := newKeySet(func() (*jsonWebKeySet, error) {
+		return &jsonWebKeySet{Keys: s.jwkSet().Keys[:1]}, nil
+	})
+
+	key, err := set.get("")
+
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), s.ecKey.PublicKey.Equal(key))
+}
+
+func (s *OIDCTestSuite) TestGet_FetchErrorIsRetried() {
+	fetches := 0
+	set := newKeySet(func() (*jsonWebKeySet, error) {
+		fetches++
+		if fetches == 1 {
+			return nil, errors.New("unavailable")
+		}
+		return s.jwkSet(), nil
+	})
+
+	_, err := set.get("ec")
+	assert.Error(s.T(), err)
+	_, err = set.get("ec")
+
+	assert.NoError(s.T(), err)
+}
@@ -0,0 +1,115 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package oidc
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	oidc "github.com/weeranieb/go-kit-base/src/internal/oidc"
+)
+
+// MockProvider is an autogenerated mock type for the Provider type
+type MockProvider struct {
+	mock.Mock
+}
+
+// AuthCodeURL provides a mock function with given fields: state, nonce, codeChallenge
+func (_m *MockProvider) AuthCodeURL(state string, nonce string, codeChallenge string) (string, error) {
+	ret := _m.Called(state, nonce, codeChallenge)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthCodeURL")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string) (string, error)); ok {
+		return rf(state, nonce, codeChallenge)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string) string); ok {
+		r0 = rf(state, nonce, codeChallenge)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string) error); ok {
+		r1 = rf(state, nonce, codeChallenge)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Exchange provides a mock function with given fields: code, codeVerifier
+func (_m *MockProvider) Exchange(code string, codeVerifier string) (*oidc.Token, error) {
+	ret := _m.Called(code, codeVerifier)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Exchange")
+	}
+
+	var r0 *oidc.Token
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*oidc.Token, error)); ok {
+		return rf(code, codeVerifier)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *oidc.Token); ok {
+		r0 = rf(code, codeVerifier)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*oidc.Token)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(code, codeVerifier)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyIDToken provides a mock function with given fields: rawIDToken, nonce
+func (_m *MockProvider) VerifyIDToken(rawIDToken string, nonce string) (*oidc.IDTokenClaims, error) {
+	ret := _m.Called(rawIDToken, nonce)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyIDToken")
+	}
+
+	var r0 *oidc.IDTokenClaims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*oidc.IDTokenClaims, error)); ok {
+		return rf(rawIDToken, nonce)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *oidc.IDTokenClaims); ok {
+		r0 = rf(rawIDToken, nonce)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*oidc.IDTokenClaims)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(rawIDToken, nonce)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockProvider creates a new instance of MockProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockProvider {
+	mock := &MockProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
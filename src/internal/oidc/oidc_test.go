@@ -0,0 +1,92 @@
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/oidc/oidctest"
+)
+
+type OIDCTestSuite struct {
+	suite.Suite
+	ecKey    *ecdsa.PrivateKey
+	rsaKey   *rsa.PrivateKey
+	server   *oidctest.Server
+	conf     config.OIDCProviderConfig
+	provider Provider
+}
+
+func (s *OIDCTestSuite) SetupSuite() {
+	s.ecKey, _ = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.rsaKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+}
+
+func (s *OIDCTestSuite) SetupTest() {
+	s.server = oidctest.NewServer("client-1", "secret&1")
+	s.server.SetIdentity(oidctest.Identity{
+		Subject:       "external-1",
+		Email:         "test@example.com",
+		EmailVerified: true,
+		Name:          "Test User",
+	})
+	s.conf = config.OIDCProviderConfig{
+		Issuer:       s.server.Issuer(),
+		ClientID:     "client-1",
+		ClientSecret: "secret&1",
+		RedirectURL:  "http://localhost/callback",
+		Scopes:       []string{"email", "profile"},
+	}
+	s.provider = NewProvider(s.conf, http.DefaultClient)
+}
+
+func (s *OIDCTestSuite) TearDownTest() {
+	s.server.Close()
+}
+
+func TestOIDCSuite(t *testing.T) {
+	suite.Run(t, new(OIDCTestSuite))
+}
+
+func (s *OIDCTestSuite) TestCodeChallenge_RFC7636Vector() {
+	// Appendix B of RFC 7636
+	assert.Equal(s.T(), "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"))
+}
+
+func (s *OIDCTestSuite) TestNewCodeVerifier() {
+	first, err := NewCodeVerifier()
+	assert.NoError(s.T(), err)
+	second, _ := NewCodeVerifier()
+
+	// RFC 7636 requires 43 to 128 characters
+	assert.Len(s.T(), first, 43)
+	assert.NotEqual(s.T(), first, second)
+}
+
+func (s *OIDCTestSuite) TestNewProviders() {
+	conf := &config.Config{OIDC: config.OIDCConfig{Providers: map[string]config.OIDCProviderConfig{
+		"google": {Issuer: "https://accounts.google.com", ClientID: "id", RedirectURL: "http://localhost/callback"},
+		"gitlab": {Issuer: "https://gitlab.com", ClientID: "id", RedirectURL: "http://localhost/callback"},
+	}}}
+
+	providers, err := NewProviders(conf)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"gitlab", "google"}, providers.Names())
+}
+
+func (s *OIDCTestSuite) TestNewProviders_IncompleteConfig() {
+	conf := &config.Config{OIDC: config.OIDCConfig{Providers: map[string]config.OIDCProviderConfig{
+		"google": {Issuer: "https://accounts.google.com"},
+	}}}
+
+	_, err := NewProviders(conf)
+
+	assert.ErrorContains(s.T(), err, "google")
+}
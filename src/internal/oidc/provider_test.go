@@ -0,0 +1,162 @@
+package oidc
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// signIn runs the authorization code flow up to the token response.
+func (s *OIDCTestSuite) signIn(nonce, verifier string) *Token {
+	authURL, err := s.provider.AuthCodeURL("state-1", nonce, CodeChallenge(verifier))
+	s.Require().NoError(err)
+
+	code, state, err := s.server.Authorize(authURL)
+	s.Require().NoError(err)
+	s.Require().Equal("state-1", state)
+
+	token, err := s.provider.Exchange(code, verifier)
+	s.Require().NoError(err)
+	return token
+}
+
+func (s *OIDCTestSuite) TestAuthCodeURL() {
+	authURL, err := s.provider.AuthCodeURL("state-1", "nonce-1", "challenge-1")
+
+	assert.NoError(s.T(), err)
+	parsed, _ := url.Parse(authURL)
+	query := parsed.Query()
+	assert.Equal(s.T(), s.server.URL+"/authorize", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	assert.Equal(s.T(), "code", query.Get("response_type"))
+	assert.Equal(s.T(), "openid email profile", query.Get("scope"))
+	assert.Equal(s.T(), "http://localhost/callback", query.Get("redirect_uri"))
+	assert.Equal(s.T(), "S256", query.Get("code_challenge_method"))
+	assert.Equal(s.T(), "challenge-1", query.Get("code_challenge"))
+	assert.Equal(s.T(), "nonce-1", query.Get("nonce"))
+}
+
+func (s *OIDCTestSuite) TestFullFlow() {
+	verifier, _ := NewCodeVerifier()
+
+	token := s.signIn("nonce-1", verifier)
+	claims, err := s.provider.VerifyIDToken(token.IDToken, "nonce-1")
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "external-1", claims.Subject)
+	assert.Equal(s.T(), "test@example.com", claims.Email)
+	assert.True(s.T(), claims.EmailVerified)
+	assert.Equal(s.T(), "Test User", claims.Name)
+}
+
+func (s *OIDCTestSuite) TestExchange_WrongVerifier() {
+	verifier, _ := NewCodeVerifier()
+	authURL, _ := s.provider.AuthCodeURL("state-1", "nonce-1", CodeChallenge(verifier))
+	code, _, err := s.server.Authorize(authURL)
+	s.Require().NoError(err)
+
+	_, err = s.provider.Exchange(code, "another-verifier")
+
+	assert.ErrorIs(s.T(), err, ErrExchangeFailed)
+	assert.Contains(s.T(), err.Error(), "invalid_grant")
+}
+
+func (s *OIDCTestSuite) TestExchange_WrongClientSecret() {
+	s.conf.ClientSecret = "wrong"
+	s.provider = NewProvider(s.conf, http.DefaultClient)
+	verifier, _ := NewCodeVerifier()
+	authURL, _ := s.provider.AuthCodeURL("state-1", "nonce-1", CodeChallenge(verifier))
+	code, _, err := s.server.Authorize(authURL)
+	s.Require().NoError(err)
+
+	_, err = s.provider.Exchange(code, verifier)
+
+	assert.ErrorIs(s.T(), err, ErrExchangeFailed)
+}
+
+func (s *OIDCTestSuite) TestVerifyIDToken_NonceMismatch() {
+	verifier, _ := NewCodeVerifier()
+	token := s.signIn("nonce-1", verifier)
+
+	_, err := s.provider.VerifyIDToken(token.IDToken, "nonce-2")
+
+	assert.ErrorIs(s.T(), err, ErrInvalidIDToken)
+}
+
+func (s *OIDCTestSuite) TestVerifyIDToken_BadClaims() {
+	now := time.Now()
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   s.server.Issuer(),
+			"sub":   "external-1",
+			"aud":   "client-1",
+			"iat":   now.Unix(),
+			"exp":   now.Add(time.Hour).Unix(),
+			"nonce": "nonce-1",
+		}
+	}
+	cases := map[string]func(jwt.MapClaims){
+		"wrong issuer":         func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+		"wrong audience":       func(c jwt.MapClaims) { c["aud"] = "client-2" },
+		"expired":              func(c jwt.MapClaims) { c["exp"] = now.Add(-time.Hour).Unix() },
+		"no expiry":            func(c jwt.MapClaims) { delete(c, "exp") },
+		"no subject":           func(c jwt.MapClaims) { delete(c, "sub") },
+		"foreign azp":          func(c jwt.MapClaims) { c["aud"] = []string{"client-1", "client-2"}; c["azp"] = "client-2" },
+		"several audiences":    func(c jwt.MapClaims) { c["aud"] = []string{"client-1", "client-2"} },
+		"issued in the future": func(c jwt.MapClaims) { c["iat"] = now.Add(time.Hour).Unix() },
+	}
+
+	for name, mutate := range cases {
+		claims := valid()
+		mutate(claims)
+
+		_, err := s.provider.VerifyIDToken(s.server.SignIDToken(claims), "nonce-1")
+
+		assert.ErrorIs(s.T(), err, ErrInvalidIDToken, name)
+	}
+
+	_, err := s.provider.VerifyIDToken(s.server.SignIDToken(valid()), "nonce-1")
+	assert.NoError(s.T(), err)
+}
+
+func (s *OIDCTestSuite) TestVerifyIDToken_UnsignedToken() {
+	token := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{
+		"iss":   s.server.Issuer(),
+		"sub":   "external-1",
+		"aud":   "client-1",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"nonce": "nonce-1",
+	})
+	unsigned, _ := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+
+	_, err := s.provider.VerifyIDToken(unsigned, "nonce-1")
+
+	assert.ErrorIs(s.T(), err, ErrInvalidIDToken)
+}
+
+func (s *OIDCTestSuite) TestVerifyIDToken_PicksUpRotatedKey() {
+	verifier, _ := NewCodeVerifier()
+	token := s.signIn("nonce-1", verifier)
+	_, err := s.provider.VerifyIDToken(token.IDToken, "nonce-1")
+	s.Require().NoError(err)
+
+	s.server.RotateKey()
+	// Pretend the cached keys are old enough to be refreshed
+	s.provider.(*provider).keys.fetchedAt = time.Now().Add(-minKeyRefreshInterval)
+	token = s.signIn("nonce-2", verifier)
+	_, err = s.provider.VerifyIDToken(token.IDToken, "nonce-2")
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, s.server.JWKSRequests())
+}
+
+func (s *OIDCTestSuite) TestDiscovery_IssuerMismatch() {
+	s.conf.Issuer = s.server.Issuer() + "/"
+	s.provider = NewProvider(s.conf, http.DefaultClient)
+
+	_, err := s.provider.AuthCodeURL("state-1", "nonce-1", "challenge-1")
+
+	assert.ErrorContains(s.T(), err, "does not match")
+}
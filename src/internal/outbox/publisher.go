@@ -0,0 +1,17 @@
+package outbox
+
+import (
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/events"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+)
+
+// NewPublisher returns the Publisher of the relay: the one selected by
+// outbox.publisher, then the webhooks of the organization of the event.
+func NewPublisher(conf *config.Config, webhooks service.WebhookService) (events.Publisher, error) {
+	publisher, err := events.NewPublisher(conf)
+	if err != nil {
+		return nil, err
+	}
+	return events.Multi(publisher, webhooks), nil
+}
@@ -0,0 +1,105 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/events"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+)
+
+// Relay publishes the events stored in the outbox. An event is only marked
+// published once the publisher has accepted it, so it is delivered at least
+// once; a failed event is retried with exponential backoff, and the events
+// after it of the same aggregate wait for it.
+type Relay struct {
+	outboxRepo repository.OutboxRepository
+	publisher  events.Publisher
+	conf       config.OutboxConfig
+}
+
+func NewRelay(outboxRepo repository.OutboxRepository, publisher events.Publisher, conf *config.Config) *Relay {
+	return &Relay{
+		outboxRepo: outboxRepo,
+		publisher:  publisher,
+		conf:       conf.Outbox,
+	}
+}
+
+// Run relays events every poll interval until ctx is done.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.conf.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Full batches mean there is a backlog, so keep going without waiting
+		for {
+			claimed, err := r.RelayBatch(ctx)
+			if err != nil {
+				log.Printf("Failed to relay events: %v", err)
+				break
+			}
+			if claimed < r.conf.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayBatch claims the events that are due and publishes them. It returns
+// how many events it claimed, whether or not they could be published.
+func (r *Relay) RelayBatch(ctx context.Context) (int, error) {
+	outbox := r.outboxRepo.WithContext(tenant.Unscoped(ctx))
+
+	messages, err := outbox.Claim(r.conf.BatchSize, r.conf.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	// A batch holds at most one event per aggregate, so the events can be
+	// published in any order
+	for _, message := range messages {
+		// Events left over are claimed again once their lease runs out
+		if err := ctx.Err(); err != nil {
+			return len(messages), err
+		}
+
+		if err := r.publisher.Publish(ctx, events.NewMessage(message)); err != nil {
+			retryAt := time.Now().Add(r.backoff(message.Attempts))
+			log.Printf("Failed to publish event %d (%s), attempt %d, retrying at %s: %v",
+				message.ID, message.EventType, message.Attempts, retryAt.Format(time.RFC3339), err)
+			if err := outbox.MarkFailed(message.ID, err, retryAt); err != nil {
+				return len(messages), err
+			}
+			continue
+		}
+		if err := outbox.MarkPublished(message.ID); err != nil {
+			return len(messages), err
+		}
+	}
+
+	return len(messages), nil
+}
+
+// backoff returns how long to wait after the given number of failed
+// attempts: BaseBackoff, doubling with every further attempt up to
+// MaxBackoff.
+func (r *Relay) backoff(attempts int) time.Duration {
+	backoff := r.conf.BaseBackoff
+	for i := 1; i < attempts && backoff < r.conf.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > r.conf.MaxBackoff {
+		backoff = r.conf.MaxBackoff
+	}
+	return backoff
+}
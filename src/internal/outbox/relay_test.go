@@ -0,0 +1,126 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/events"
+	eventMocks "github.com/weeranieb/go-kit-base/src/internal/events/mocks/events"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	mocks "github.com/weeranieb/go-kit-base/src/internal/repository/mocks/repository"
+)
+
+type RelayTestSuite struct {
+	suite.Suite
+	outboxRepo *mocks.MockOutboxRepository
+	publisher  *eventMocks.MockPublisher
+	relay      *Relay
+}
+
+func (s *RelayTestSuite) SetupTest() {
+	s.outboxRepo = mocks.NewMockOutboxRepository(s.T())
+	s.publisher = eventMocks.NewMockPublisher(s.T())
+	s.outboxRepo.On("WithContext", mock.Anything).Return(s.outboxRepo).Maybe()
+
+	s.relay = NewRelay(s.outboxRepo, s.publisher, &config.Config{Outbox: config.OutboxConfig{
+		PollInterval: 10 * time.Millisecond,
+		BatchSize:    10,
+		Lease:        time.Minute,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   time.Minute,
+	}})
+}
+
+func TestRelaySuite(t *testing.T) {
+	suite.Run(t, new(RelayTestSuite))
+}
+
+func newOutboxMessage(id uint, attempts int) *model.OutboxMessage {
+	return &model.OutboxMessage{
+		ID:             id,
+		OrganizationID: 1,
+		EventType:      events.UserCreatedType,
+		AggregateType:  events.AggregateUser,
+		AggregateID:    id,
+		Payload:        `{"user":{"id":1}}`,
+		Attempts:       attempts,
+	}
+}
+
+func (s *RelayTestSuite) TestRelayBatch_Published() {
+	s.outboxRepo.On("Claim", 10, time.Minute).Return([]*model.OutboxMessage{newOutboxMessage(7, 1)}, nil)
+	s.publisher.On("Publish", mock.Anything, mock.MatchedBy(func(msg *events.Message) bool {
+		return msg.ID == 7 && msg.Type == events.UserCreatedType && string(msg.Data) == `{"user":{"id":1}}`
+	})).Return(nil)
+	s.outboxRepo.On("MarkPublished", uint(7)).Return(nil)
+
+	claimed, err := s.relay.RelayBatch(context.Background())
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, claimed)
+	s.outboxRepo.AssertExpectations(s.T())
+}
+
+func (s *RelayTestSuite) TestRelayBatch_FailedIsRetriedLater() {
+	s.outboxRepo.On("Claim", 10, time.Minute).Return([]*model.OutboxMessage{newOutboxMessage(7, 3), newOutboxMessage(8, 1)}, nil)
+	s.publisher.On("Publish", mock.Anything, mock.MatchedBy(func(msg *events.Message) bool { return msg.ID == 7 })).
+		Return(errors.New("connection refused"))
+	s.publisher.On("Publish", mock.Anything, mock.MatchedBy(func(msg *events.Message) bool { return msg.ID == 8 })).Return(nil)
+	s.outboxRepo.On("MarkFailed", uint(7), mock.Anything, mock.AnythingOfType("time.Time")).Return(nil)
+	s.outboxRepo.On("MarkPublished", uint(8)).Return(nil)
+
+	start := time.Now()
+	claimed, err := s.relay.RelayBatch(context.Background())
+
+	// A failure does not hold up the events of other aggregates
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, claimed)
+	s.outboxRepo.AssertExpectations(s.T())
+
+	// Third attempt: the base backoff doubled twice
+	retryAt := s.outboxRepo.Calls[2].Arguments.Get(2).(time.Time)
+	assert.WithinDuration(s.T(), start.Add(4*time.Second), retryAt, time.Second)
+}
+
+func (s *RelayTestSuite) TestRelayBatch_ClaimFails() {
+	s.outboxRepo.On("Claim", 10, time.Minute).Return(nil, errors.New("database is down"))
+
+	_, err := s.relay.RelayBatch(context.Background())
+
+	assert.Error(s.T(), err)
+	s.publisher.AssertNotCalled(s.T(), "Publish", mock.Anything, mock.Anything)
+}
+
+func (s *RelayTestSuite) TestBackoff() {
+	assert.Equal(s.T(), time.Second, s.relay.backoff(1))
+	assert.Equal(s.T(), 2*time.Second, s.relay.backoff(2))
+	assert.Equal(s.T(), 32*time.Second, s.relay.backoff(6))
+	assert.Equal(s.T(), time.Minute, s.relay.backoff(7))
+	assert.Equal(s.T(), time.Minute, s.relay.backoff(100))
+}
+
+func (s *RelayTestSuite) TestRun_StopsWithContext() {
+	s.outboxRepo.On("Claim", 10, time.Minute).Return(nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.relay.Run(ctx)
+		close(done)
+	}()
+	time.Sleep(30 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		s.T().Fatal("Relay did not stop")
+	}
+	s.outboxRepo.AssertCalled(s.T(), "Claim", 10, time.Minute)
+}
@@ -0,0 +1,127 @@
+// Package presence tracks which users are connected to the gateway and when
+// they were last seen. A Store keeps the connections of each user; the
+// memory store sees those of its own process only, while a store shared by
+// the replicas, such as one on Redis, sees them all.
+package presence
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+)
+
+// Status is the presence of a user. LastSeenAt is zero for users who never
+// connected.
+type Status struct {
+	Online     bool
+	LastSeenAt time.Time
+}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=Store --output=./mocks/presence --outpkg=presence --filename=store.go --structname=MockStore --with-expecter=false
+type Store interface {
+	// Connect records a connection of the user and reports whether the user
+	// came online with it.
+	Connect(ctx context.Context, userID uint, connectionID string) (bool, error)
+	// Touch records that the connection is still open. A shared store
+	// should expire connections that are not touched, so the users of a
+	// replica that stopped without disconnecting them go offline.
+	Touch(ctx context.Context, userID uint, connectionID string) error
+	// Disconnect removes the connection and reports whether the user went
+	// offline with it.
+	Disconnect(ctx context.Context, userID uint, connectionID string) (bool, error)
+	Get(ctx context.Context, userID uint) (Status, error)
+}
+
+// NewStore returns the Store selected by gateway.presence.
+func NewStore(conf *config.Config) (Store, error) {
+	switch strings.ToLower(conf.Gateway.Presence) {
+	case "", "memory":
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unknown presence store %q", conf.Gateway.Presence)
+	}
+}
+
+type memoryUser struct {
+	connections map[string]struct{}
+	lastSeenAt  time.Time
+}
+
+type memoryStore struct {
+	mu    sync.Mutex
+	users map[uint]*memoryUser
+}
+
+// NewMemoryStore returns a Store that keeps presence in the process. Last
+// seen times are lost when the process stops.
+func NewMemoryStore() Store {
+	return &memoryStore{users: make(map[uint]*memoryUser)}
+}
+
+func (s *memoryStore) Connect(ctx context.Context, userID uint, connectionID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		user = &memoryUser{}
+		s.users[userID] = user
+	}
+	if user.connections == nil {
+		user.connections = make(map[string]struct{})
+	}
+	user.connections[connectionID] = struct{}{}
+	user.lastSeenAt = time.Now()
+	return len(user.connections) == 1, nil
+}
+
+func (s *memoryStore) Touch(ctx context.Context, userID uint, connectionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if user, ok := s.users[userID]; ok {
+		if _, ok := user.connections[connectionID]; ok {
+			user.lastSeenAt = time.Now()
+		}
+	}
+	return nil
+}
+
+func (s *memoryStore) Disconnect(ctx context.Context, userID uint, connectionID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		return false, nil
+	}
+	if _, ok := user.connections[connectionID]; !ok {
+		return false, nil
+	}
+	delete(user.connections, connectionID)
+	user.lastSeenAt = time.Now()
+	if len(user.connections) > 0 {
+		return false, nil
+	}
+	// Offline users only need their last seen time
+	user.connections = nil
+	return true, nil
+}
+
+func (s *memoryStore) Get(ctx context.Context, userID uint) (Status, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[userID]
+	if !ok {
+		return Status{}, nil
+	}
+	return Status{
+		Online:     len(user.connections) > 0,
+		LastSeenAt: user.lastSeenAt,
+	}, nil
+}
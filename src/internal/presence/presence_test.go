@@ -0,0 +1,98 @@
+package presence
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type MemoryStoreTestSuite struct {
+	suite.Suite
+	ctx   context.Context
+	store Store
+}
+
+func (s *MemoryStoreTestSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.store = NewMemoryStore()
+}
+
+func TestMemoryStoreSuite(t *testing.T) {
+	suite.Run(t, new(MemoryStoreTestSuite))
+}
+
+func (s *MemoryStoreTestSuite) TestGet_NeverConnected() {
+	status, err := s.store.Get(s.ctx, 1)
+	require.NoError(s.T(), err)
+	assert.False(s.T(), status.Online)
+	assert.True(s.T(), status.LastSeenAt.IsZero())
+}
+
+func (s *MemoryStoreTestSuite) TestConnectAndDisconnect() {
+	before := time.Now()
+
+	online, err := s.store.Connect(s.ctx, 1, "a")
+	require.NoError(s.T(), err)
+	assert.True(s.T(), online)
+
+	// A second connection does not bring the user online again
+	online, err = s.store.Connect(s.ctx, 1, "b")
+	require.NoError(s.T(), err)
+	assert.False(s.T(), online)
+
+	status, err := s.store.Get(s.ctx, 1)
+	require.NoError(s.T(), err)
+	assert.True(s.T(), status.Online)
+	assert.False(s.T(), status.LastSeenAt.Before(before))
+
+	offline, err := s.store.Disconnect(s.ctx, 1, "a")
+	require.NoError(s.T(), err)
+	assert.False(s.T(), offline)
+
+	offline, err = s.store.Disconnect(s.ctx, 1, "b")
+	require.NoError(s.T(), err)
+	assert.True(s.T(), offline)
+
+	status, err = s.store.Get(s.ctx, 1)
+	require.NoError(s.T(), err)
+	assert.False(s.T(), status.Online)
+	assert.False(s.T(), status.LastSeenAt.IsZero())
+}
+
+func (s *MemoryStoreTestSuite) TestDisconnect_Unknown() {
+	offline, err := s.store.Disconnect(s.ctx, 1, "a")
+	require.NoError(s.T(), err)
+	assert.False(s.T(), offline)
+
+	s.store.Connect(s.ctx, 1, "a")
+	offline, err = s.store.Disconnect(s.ctx, 1, "b")
+	require.NoError(s.T(), err)
+	assert.False(s.T(), offline)
+}
+
+func (s *MemoryStoreTestSuite) TestTouch() {
+	s.store.Connect(s.ctx, 1, "a")
+	status, _ := s.store.Get(s.ctx, 1)
+
+	time.Sleep(time.Millisecond)
+	require.NoError(s.T(), s.store.Touch(s.ctx, 1, "a"))
+
+	touched, _ := s.store.Get(s.ctx, 1)
+	assert.True(s.T(), touched.LastSeenAt.After(status.LastSeenAt))
+}
+
+func TestNewStore(t *testing.T) {
+	conf := &config.Config{Gateway: config.GatewayConfig{Presence: "memory"}}
+	_, err := NewStore(conf)
+	assert.NoError(t, err)
+
+	conf.Gateway.Presence = "carrier-pigeon"
+	_, err = NewStore(conf)
+	assert.Error(t, err)
+}
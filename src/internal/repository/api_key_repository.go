@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=APIKeyRepository --output=./mocks/repository --outpkg=repository --filename=api_key_repository.go --structname=MockAPIKeyRepository --with-expecter=false
+type APIKeyRepository interface {
+	Create(key *model.APIKey) error
+	GetByID(id uint) (*model.APIKey, error)
+	GetByPrefix(prefix string) (*model.APIKey, error)
+	ListByUser(userID uint) ([]*model.APIKey, error)
+	ListByService(service string) ([]*model.APIKey, error)
+	Revoke(id uint, revokedAt time.Time) (bool, error)
+	RevokeAllForUser(userID uint, revokedAt time.Time) error
+	TouchLastUsed(id uint, lastUsedAt time.Time) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) Create(key *model.APIKey) error {
+	return r.db.Create(key).Error
+}
+
+func (r *apiKeyRepository) GetByID(id uint) (*model.APIKey, error) {
+	var key model.APIKey
+	err := r.db.First(&key, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) GetByPrefix(prefix string) (*model.APIKey, error) {
+	var key model.APIKey
+	err := r.db.Where("prefix = ?", prefix).First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (r *apiKeyRepository) ListByUser(userID uint) ([]*model.APIKey, error) {
+	var keys []*model.APIKey
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC, id DESC").Find(&keys).Error
+	return keys, err
+}
+
+func (r *apiKeyRepository) ListByService(service string) ([]*model.APIKey, error) {
+	var keys []*model.APIKey
+	err := r.db.Where("user_id IS NULL AND service = ?", service).Order("created_at DESC, id DESC").Find(&keys).Error
+	return keys, err
+}
+
+// Revoke revokes the key. It reports false if there is no such key or it was
+// already revoked.
+func (r *apiKeyRepository) Revoke(id uint, revokedAt time.Time) (bool, error) {
+	result := r.db.Model(&model.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *apiKeyRepository) RevokeAllForUser(userID uint, revokedAt time.Time) error {
+	return r.db.Model(&model.APIKey{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", revokedAt).Error
+}
+
+func (r *apiKeyRepository) TouchLastUsed(id uint, lastUsedAt time.Time) error {
+	return r.db.Model(&model.APIKey{}).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", lastUsedAt).Error
+}
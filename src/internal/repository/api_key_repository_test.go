@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepositoryTestSuite struct {
+	suite.Suite
+	db               *gorm.DB
+	apiKeyRepository APIKeyRepository
+}
+
+func (s *APIKeyRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.APIKey{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.apiKeyRepository = NewAPIKeyRepository(s.db)
+}
+
+func (s *APIKeyRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *APIKeyRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM api_keys")
+}
+
+func TestAPIKeyRepositorySuite(t *testing.T) {
+	suite.Run(t, new(APIKeyRepositoryTestSuite))
+}
+
+func (s *APIKeyRepositoryTestSuite) createKey(prefix string, userID *uint, service string) *model.APIKey {
+	key := &model.APIKey{
+		UserID:     userID,
+		Service:    service,
+		Name:       prefix,
+		Prefix:     prefix,
+		SecretHash: "hash-" + prefix,
+		Scopes:     model.ScopeList{model.ScopeUsersRead, model.ScopeUsersWrite},
+	}
+	s.Require().NoError(s.apiKeyRepository.Create(key))
+	return key
+}
+
+func (s *APIKeyRepositoryTestSuite) TestGetByPrefix_RoundTripsScopes() {
+	created := s.createKey("gkb_aaaaaaaa", nil, "billing")
+
+	key, err := s.apiKeyRepository.GetByPrefix("gkb_aaaaaaaa")
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), created.ID, key.ID)
+	assert.Equal(s.T(), model.ScopeList{model.ScopeUsersRead, model.ScopeUsersWrite}, key.Scopes)
+}
+
+func (s *APIKeyRepositoryTestSuite) TestGetByPrefix_NotFound() {
+	_, err := s.apiKeyRepository.GetByPrefix("gkb_missing0")
+
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *APIKeyRepositoryTestSuite) TestCreate_DuplicatePrefix() {
+	s.createKey("gkb_aaaaaaaa", nil, "billing")
+
+	err := s.apiKeyRepository.Create(&model.APIKey{Name: "dup", Prefix: "gkb_aaaaaaaa", SecretHash: "other"})
+
+	assert.Error(s.T(), err)
+}
+
+func (s *APIKeyRepositoryTestSuite) TestListByUserAndService() {
+	userID := uint(1)
+	otherUserID := uint(2)
+	s.createKey("gkb_aaaaaaaa", &userID, "")
+	s.createKey("gkb_bbbbbbbb", &otherUserID, "")
+	s.createKey("gkb_cccccccc", nil, "billing")
+	s.createKey("gkb_dddddddd", nil, "reports")
+
+	userKeys, err := s.apiKeyRepository.ListByUser(1)
+	assert.NoError(s.T(), err)
+	serviceKeys, err := s.apiKeyRepository.ListByService("billing")
+	assert.NoError(s.T(), err)
+
+	if assert.Len(s.T(), userKeys, 1) {
+		assert.Equal(s.T(), "gkb_aaaaaaaa", userKeys[0].Prefix)
+	}
+	if assert.Len(s.T(), serviceKeys, 1) {
+		assert.Equal(s.T(), "gkb_cccccccc", serviceKeys[0].Prefix)
+	}
+}
+
+func (s *APIKeyRepositoryTestSuite) TestRevoke() {
+	key := s.createKey("gkb_aaaaaaaa", nil, "billing")
+
+	revoked, err := s.apiKeyRepository.Revoke(key.ID, time.Now())
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), revoked)
+
+	// A second revocation and an unknown key report false
+	revoked, err = s.apiKeyRepository.Revoke(key.ID, time.Now())
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), revoked)
+	revoked, err = s.apiKeyRepository.Revoke(999, time.Now())
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), revoked)
+
+	stored, _ := s.apiKeyRepository.GetByID(key.ID)
+	assert.NotNil(s.T(), stored.RevokedAt)
+}
+
+func (s *APIKeyRepositoryTestSuite) TestRevokeAllForUser() {
+	userID := uint(1)
+	own := s.createKey("gkb_aaaaaaaa", &userID, "")
+	service := s.createKey("gkb_bbbbbbbb", nil, "billing")
+
+	err := s.apiKeyRepository.RevokeAllForUser(userID, time.Now())
+
+	assert.NoError(s.T(), err)
+	stored, _ := s.apiKeyRepository.GetByID(own.ID)
+	assert.NotNil(s.T(), stored.RevokedAt)
+	stored, _ = s.apiKeyRepository.GetByID(service.ID)
+	assert.Nil(s.T(), stored.RevokedAt)
+}
+
+func (s *APIKeyRepositoryTestSuite) TestTouchLastUsed() {
+	key := s.createKey("gkb_aaaaaaaa", nil, "billing")
+	usedAt := time.Now().Truncate(time.Second)
+
+	err := s.apiKeyRepository.TouchLastUsed(key.ID, usedAt)
+
+	assert.NoError(s.T(), err)
+	stored, _ := s.apiKeyRepository.GetByID(key.ID)
+	if assert.NotNil(s.T(), stored.LastUsedAt) {
+		assert.True(s.T(), usedAt.Equal(*stored.LastUsedAt))
+	}
+}
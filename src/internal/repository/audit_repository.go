@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/weeranieb/go-kit-base/src/internal/audit"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=AuditRepository --output=./mocks/repository --outpkg=repository --filename=audit_repository.go --structname=MockAuditRepository --with-expecter=false
+type AuditRepository interface {
+	// WithContext returns a repository bound to ctx. All methods read the
+	// log of the organization of ctx and return tenant.ErrNoOrganization
+	// without one. Events are written by the repositories that make the
+	// changes, in the same transaction.
+	WithContext(ctx context.Context) AuditRepository
+	List(filter *model.AuditFilter, limit, offset int) ([]*model.AuditEvent, error)
+	ListAfter(filter *model.AuditFilter, afterID uint, limit int) ([]*model.AuditEvent, error)
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) WithContext(ctx context.Context) AuditRepository {
+	return &auditRepository{db: withTransaction(r.db, ctx)}
+}
+
+// List returns a page of the events matching the filter, newest first.
+func (r *auditRepository) List(filter *model.AuditFilter, limit, offset int) ([]*model.AuditEvent, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return nil, err
+	}
+
+	var events []*model.AuditEvent
+	err := r.filter(filter).Order("id DESC").Limit(limit).Offset(offset).Find(&events).Error
+	return events, err
+}
+
+// ListAfter returns up to limit events matching the filter with an ID
+// above afterID, oldest first, for reading the whole log in batches.
+func (r *auditRepository) ListAfter(filter *model.AuditFilter, afterID uint, limit int) ([]*model.AuditEvent, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return nil, err
+	}
+
+	var events []*model.AuditEvent
+	err := r.filter(filter).Where("id > ?", afterID).Order("id").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+func (r *auditRepository) filter(filter *model.AuditFilter) *gorm.DB {
+	query := r.db.Model(&model.AuditEvent{})
+	if filter == nil {
+		return query
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.ActorUserID != 0 {
+		query = query.Where("actor_user_id = ?", filter.ActorUserID)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != 0 {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To.UTC())
+	}
+	return query
+}
+
+// appendAuditEvent records the event in the log of the organization as part
+// of the transaction tx. The organization row is locked until tx ends, so
+// that concurrent changes extend the chain one after another.
+func appendAuditEvent(tx *gorm.DB, organizationID uint, event *model.AuditEvent) error {
+	// Scoped to the organization of the event rather than that of the
+	// request, which may differ for changes to a user's own account
+	tx = tx.WithContext(tenant.WithOrganization(tx.Statement.Context, organizationID))
+
+	var organization model.Organization
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Limit(1).Find(&organization, organizationID).Error
+	if err != nil {
+		return err
+	}
+
+	var last model.AuditEvent
+	err = tx.Select("hash").Order("id DESC").Limit(1).Find(&last).Error
+	if err != nil {
+		return err
+	}
+
+	event.OrganizationID = organizationID
+	event.PrevHash = last.Hash
+	event.Hash, err = audit.Hash(event.PrevHash, event)
+	if err != nil {
+		return err
+	}
+	return tx.Create(event).Error
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/audit"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type AuditRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	ctx  context.Context
+	acme AuditRepository
+	// globex reads the log of the other organization
+	globex     AuditRepository
+	users      UserRepository
+	membership MembershipRepository
+	orgs       []*model.Organization
+}
+
+func (s *AuditRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+	if err := s.db.Use(tenant.Plugin{}); err != nil {
+		s.T().Fatal("Failed to register tenant plugin:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.Organization{}, &model.User{}, &model.Membership{}, &model.AuditEvent{}, &model.UserVersion{}, &model.OutboxMessage{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.orgs = []*model.Organization{{Name: "Acme", Slug: "acme"}, {Name: "Globex", Slug: "globex"}}
+	s.db.Create(s.orgs)
+
+	s.ctx = audit.WithSource(tenant.WithOrganization(context.Background(), s.orgs[0].ID), audit.Source{
+		UserID:    7,
+		IP:        "203.0.113.7",
+		RequestID: "req-1",
+	})
+	repo := NewAuditRepository(s.db)
+	s.acme = repo.WithContext(s.ctx)
+	s.globex = repo.WithContext(tenant.WithOrganization(context.Background(), s.orgs[1].ID))
+	s.users = NewUserRepository(s.db).WithContext(s.ctx)
+	s.membership = NewMembershipRepository(s.db).WithContext(s.ctx)
+}
+
+func (s *AuditRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *AuditRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM users")
+	s.db.Exec("DELETE FROM memberships")
+	s.db.Exec("DELETE FROM audit_events")
+	s.db.Exec("DELETE FROM user_versions")
+	s.db.Exec("DELETE FROM outbox_messages")
+}
+
+func TestAuditRepositorySuite(t *testing.T) {
+	suite.Run(t, new(AuditRepositoryTestSuite))
+}
+
+func (s *AuditRepositoryTestSuite) createUser() *model.User {
+	user := &model.User{Username: "testuser", Email: "test@example.com", Password: "hashed_password", Role: model.RoleUser}
+	if err := s.users.Create(user); err != nil {
+		s.T().Fatal("Failed to create user:", err)
+	}
+	return user
+}
+
+func (s *AuditRepositoryTestSuite) TestCreate_RecordsEvent() {
+	user := s.createUser()
+
+	events, err := s.acme.List(nil, 10, 0)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), events, 1)
+	event := events[0]
+	assert.Equal(s.T(), model.AuditUserCreated, event.Action)
+	assert.Equal(s.T(), user.ID, event.TargetID)
+	assert.Equal(s.T(), uint(7), *event.ActorUserID)
+	assert.Equal(s.T(), "203.0.113.7", event.IP)
+	assert.Equal(s.T(), "req-1", event.RequestID)
+	assert.Equal(s.T(), "test@example.com", event.Changes["email"].After)
+	assert.Equal(s.T(), audit.Redacted, event.Changes["password"].After)
+	assert.Empty(s.T(), event.PrevHash)
+}
+
+func (s *AuditRepositoryTestSuite) TestUpdate_RecordsChangedFields() {
+	user := s.createUser()
+
+	user.Username = "renamed"
+	user.Password = "new_hash"
+	assert.NoError(s.T(), s.users.Update(user))
+
+	events, _ := s.acme.List(&model.AuditFilter{Action: model.AuditUserUpdated}, 10, 0)
+	assert.Len(s.T(), events, 1)
+	assert.Equal(s.T(), model.AuditChanges{
+		"username": {Before: "testuser", After: "renamed"},
+		"password": {Before: audit.Redacted, After: audit.Redacted},
+	}, events[0].Changes)
+}
+
+func (s *AuditRepositoryTestSuite) TestUpdate_RoleChange() {
+	user := s.createUser()
+
+	user.Role = model.RoleAdmin
+	assert.NoError(s.T(), s.users.Update(user))
+
+	events, _ := s.acme.List(&model.AuditFilter{Action: model.AuditUserRoleChanged}, 10, 0)
+	assert.Len(s.T(), events, 1)
+	assert.Equal(s.T(), model.RoleAdmin, events[0].Changes["role"].After)
+}
+
+func (s *AuditRepositoryTestSuite) TestUpdate_NothingChanged() {
+	user := s.createUser()
+
+	assert.NoError(s.T(), s.users.Update(user))
+
+	events, _ := s.acme.List(nil, 10, 0)
+	assert.Len(s.T(), events, 1)
+}
+
+func (s *AuditRepositoryTestSuite) TestUpdate_Conflict() {
+	user := s.createUser()
+	stale := *user
+	user.Username = "first"
+	s.users.Update(user)
+
+	stale.Username = "second"
+	err := s.users.Update(&stale)
+
+	assert.ErrorIs(s.T(), err, ErrVersionConflict)
+	events, _ := s.acme.List(nil, 10, 0)
+	assert.Len(s.T(), events, 2)
+}
+
+func (s *AuditRepositoryTestSuite) TestDelete_RecordsEvent() {
+	user := s.createUser()
+
+	assert.NoError(s.T(), s.users.Delete(user.ID))
+
+	events, _ := s.acme.List(&model.AuditFilter{TargetType: model.AuditTargetUser, TargetID: user.ID}, 10, 0)
+	assert.Len(s.T(), events, 2)
+	assert.Equal(s.T(), model.AuditUserDeleted, events[0].Action)
+	assert.Equal(s.T(), "testuser", events[0].Changes["username"].Before)
+	assert.Nil(s.T(), events[0].Changes["username"].After)
+}
+
+func (s *AuditRepositoryTestSuite) TestUpdateRole_RecordsEvent() {
+	user := s.createUser()
+
+	updated, err := s.membership.UpdateRole(user.ID, model.OrganizationRoleAdmin)
+
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), updated)
+	events, _ := s.acme.List(&model.AuditFilter{Action: model.AuditMembershipRoleChanged}, 10, 0)
+	assert.Len(s.T(), events, 1)
+	assert.Equal(s.T(), model.AuditChange{Before: model.OrganizationRoleMember, After: model.OrganizationRoleAdmin}, events[0].Changes["role"])
+}
+
+func (s *AuditRepositoryTestSuite) TestChain() {
+	user := s.createUser()
+	user.Username = "renamed"
+	s.users.Update(user)
+	s.users.Delete(user.ID)
+
+	events, err := s.acme.ListAfter(nil, 0, 10)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), events, 3)
+	prevHash := ""
+	for _, event := range events {
+		assert.Equal(s.T(), prevHash, event.PrevHash)
+		hash, err := audit.Hash(event.PrevHash, event)
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), hash, event.Hash)
+		prevHash = event.Hash
+	}
+}
+
+func (s *AuditRepositoryTestSuite) TestChangeRolledBackWithoutEvent() {
+	s.db.Exec("ALTER TABLE audit_events RENAME TO audit_events_moved")
+	defer s.db.Exec("ALTER TABLE audit_events_moved RENAME TO audit_events")
+
+	err := s.users.Create(&model.User{Username: "testuser", Email: "test@example.com", Password: "hashed_password"})
+
+	assert.Error(s.T(), err)
+	var count int64
+	s.db.Model(&model.User{}).Count(&count)
+	assert.Zero(s.T(), count)
+}
+
+func (s *AuditRepositoryTestSuite) TestList_Filters() {
+	user := s.createUser()
+	user.Username = "renamed"
+	s.users.Update(user)
+
+	events, _ := s.acme.List(&model.AuditFilter{ActorUserID: 7}, 10, 0)
+	assert.Len(s.T(), events, 2)
+
+	events, _ = s.acme.List(&model.AuditFilter{ActorUserID: 8}, 10, 0)
+	assert.Empty(s.T(), events)
+
+	events, _ = s.acme.List(&model.AuditFilter{From: time.Now().Add(time.Hour)}, 10, 0)
+	assert.Empty(s.T(), events)
+
+	events, _ = s.acme.List(&model.AuditFilter{To: time.Now().Add(time.Hour)}, 1, 0)
+	assert.Len(s.T(), events, 1)
+	assert.Equal(s.T(), model.AuditUserUpdated, events[0].Action)
+}
+
+func (s *AuditRepositoryTestSuite) TestList_OnlyOwnOrganization() {
+	s.createUser()
+
+	events, err := s.globex.List(nil, 10, 0)
+
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), events)
+}
+
+func (s *AuditRepositoryTestSuite) TestNoOrganization() {
+	repo := NewAuditRepository(s.db).WithContext(context.Background())
+
+	_, err := repo.List(nil, 10, 0)
+	assert.ErrorIs(s.T(), err, tenant.ErrNoOrganization)
+
+	_, err = repo.ListAfter(nil, 0, 10)
+	assert.ErrorIs(s.T(), err, tenant.ErrNoOrganization)
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=EmailVerificationRepository --output=./mocks/repository --outpkg=repository --filename=email_verification_repository.go --structname=MockEmailVerificationRepository --with-expecter=false
+type EmailVerificationRepository interface {
+	Create(token *model.EmailVerificationToken) error
+	GetByTokenHash(tokenHash string) (*model.EmailVerificationToken, error)
+	GetLatestForUser(userID uint) (*model.EmailVerificationToken, error)
+	MarkUsed(id uint, usedAt time.Time) (bool, error)
+	InvalidateForUser(userID uint, usedAt time.Time) error
+}
+
+type emailVerificationRepository struct {
+	db *gorm.DB
+}
+
+func NewEmailVerificationRepository(db *gorm.DB) EmailVerificationRepository {
+	return &emailVerificationRepository{db: db}
+}
+
+func (r *emailVerificationRepository) Create(token *model.EmailVerificationToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *emailVerificationRepository) GetByTokenHash(tokenHash string) (*model.EmailVerificationToken, error) {
+	var token model.EmailVerificationToken
+	err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// GetLatestForUser returns the most recently issued token of the user,
+// whether or not it has been used.
+func (r *emailVerificationRepository) GetLatestForUser(userID uint) (*model.EmailVerificationToken, error) {
+	var token model.EmailVerificationToken
+	err := r.db.Where("user_id = ?", userID).Order("created_at DESC, id DESC").First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed consumes the token. It returns false if the token was already
+// used, so that a token cannot be redeemed twice.
+func (r *emailVerificationRepository) MarkUsed(id uint, usedAt time.Time) (bool, error) {
+	result := r.db.Model(&model.EmailVerificationToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// InvalidateForUser marks all outstanding tokens of the user as used.
+func (r *emailVerificationRepository) InvalidateForUser(userID uint, usedAt time.Time) error {
+	return r.db.Model(&model.EmailVerificationToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", usedAt).Error
+}
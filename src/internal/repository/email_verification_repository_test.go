@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type EmailVerificationRepositoryTestSuite struct {
+	suite.Suite
+	db                          *gorm.DB
+	emailVerificationRepository EmailVerificationRepository
+}
+
+func (s *EmailVerificationRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.EmailVerificationToken{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.emailVerificationRepository = NewEmailVerificationRepository(s.db)
+}
+
+func (s *EmailVerificationRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *EmailVerificationRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM email_verification_tokens")
+}
+
+func TestEmailVerificationRepositorySuite(t *testing.T) {
+	suite.Run(t, new(EmailVerificationRepositoryTestSuite))
+}
+
+func (s *EmailVerificationRepositoryTestSuite) TestCreateAndGetByTokenHash() {
+	token := &model.EmailVerificationToken{
+		UserID:    1,
+		Email:     "test@example.com",
+		TokenHash: "hash-1",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	err := s.emailVerificationRepository.Create(token)
+	assert.NoError(s.T(), err)
+	assert.NotZero(s.T(), token.ID)
+
+	result, err := s.emailVerificationRepository.GetByTokenHash("hash-1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), token.ID, result.ID)
+	assert.Equal(s.T(), "test@example.com", result.Email)
+	assert.Nil(s.T(), result.UsedAt)
+}
+
+func (s *EmailVerificationRepositoryTestSuite) TestGetByTokenHash_NotFound() {
+	result, err := s.emailVerificationRepository.GetByTokenHash("missing")
+
+	assert.Equal(s.T(), gorm.ErrRecordNotFound, err)
+	assert.Nil(s.T(), result)
+}
+
+func (s *EmailVerificationRepositoryTestSuite) TestGetLatestForUser() {
+	now := time.Now()
+	s.emailVerificationRepository.Create(&model.EmailVerificationToken{UserID: 1, Email: "a@example.com", TokenHash: "hash-1", ExpiresAt: now.Add(time.Hour), CreatedAt: now.Add(-time.Hour)})
+	s.emailVerificationRepository.Create(&model.EmailVerificationToken{UserID: 1, Email: "b@example.com", TokenHash: "hash-2", ExpiresAt: now.Add(time.Hour), CreatedAt: now})
+	s.emailVerificationRepository.Create(&model.EmailVerificationToken{UserID: 2, Email: "c@example.com", TokenHash: "hash-3", ExpiresAt: now.Add(time.Hour), CreatedAt: now.Add(time.Minute)})
+
+	result, err := s.emailVerificationRepository.GetLatestForUser(1)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "hash-2", result.TokenHash)
+}
+
+func (s *EmailVerificationRepositoryTestSuite) TestGetLatestForUser_NotFound() {
+	result, err := s.emailVerificationRepository.GetLatestForUser(1)
+
+	assert.Equal(s.T(), gorm.ErrRecordNotFound, err)
+	assert.Nil(s.T(), result)
+}
+
+func (s *EmailVerificationRepositoryTestSuite) TestMarkUsed_OnlyOnce() {
+	token := &model.EmailVerificationToken{UserID: 1, Email: "test@example.com", TokenHash: "hash-1", ExpiresAt: time.Now().Add(time.Hour)}
+	s.emailVerificationRepository.Create(token)
+
+	used, err := s.emailVerificationRepository.MarkUsed(token.ID, time.Now())
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), used)
+
+	used, err = s.emailVerificationRepository.MarkUsed(token.ID, time.Now())
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), used)
+}
+
+func (s *EmailVerificationRepositoryTestSuite) TestInvalidateForUser() {
+	s.emailVerificationRepository.Create(&model.EmailVerificationToken{UserID: 1, Email: "a@example.com", TokenHash: "hash-1", ExpiresAt: time.Now().Add(time.Hour)})
+	s.emailVerificationRepository.Create(&model.EmailVerificationToken{UserID: 2, Email: "b@example.com", TokenHash: "hash-2", ExpiresAt: time.Now().Add(time.Hour)})
+
+	err := s.emailVerificationRepository.InvalidateForUser(1, time.Now())
+	assert.NoError(s.T(), err)
+
+	first, _ := s.emailVerificationRepository.GetByTokenHash("hash-1")
+	second, _ := s.emailVerificationRepository.GetByTokenHash("hash-2")
+	assert.NotNil(s.T(), first.UsedAt)
+	assert.Nil(s.T(), second.UsedAt)
+}
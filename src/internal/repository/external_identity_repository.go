@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=ExternalIdentityRepository --output=./mocks/repository --outpkg=repository --filename=external_identity_repository.go --structname=MockExternalIdentityRepository --with-expecter=false
+type ExternalIdentityRepository interface {
+	Create(identity *model.ExternalIdentity) error
+	GetByProviderSubject(provider, subject string) (*model.ExternalIdentity, error)
+	ListByUser(userID uint) ([]*model.ExternalIdentity, error)
+	TouchLastLogin(id uint, at time.Time) error
+}
+
+type externalIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewExternalIdentityRepository(db *gorm.DB) ExternalIdentityRepository {
+	return &externalIdentityRepository{db: db}
+}
+
+func (r *externalIdentityRepository) Create(identity *model.ExternalIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+func (r *externalIdentityRepository) GetByProviderSubject(provider, subject string) (*model.ExternalIdentity, error) {
+	var identity model.ExternalIdentity
+	err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *externalIdentityRepository) ListByUser(userID uint) ([]*model.ExternalIdentity, error) {
+	var identities []*model.ExternalIdentity
+	err := r.db.Where("user_id = ?", userID).Order("provider, id").Find(&identities).Error
+	return identities, err
+}
+
+func (r *externalIdentityRepository) TouchLastLogin(id uint, at time.Time) error {
+	return r.db.Model(&model.ExternalIdentity{}).Where("id = ?", id).Update("last_login_at", at).Error
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type ExternalIdentityRepositoryTestSuite struct {
+	suite.Suite
+	db                         *gorm.DB
+	externalIdentityRepository ExternalIdentityRepository
+}
+
+func (s *ExternalIdentityRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.ExternalIdentity{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.externalIdentityRepository = NewExternalIdentityRepository(s.db)
+}
+
+func (s *ExternalIdentityRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *ExternalIdentityRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM external_identities")
+}
+
+func TestExternalIdentityRepositorySuite(t *testing.T) {
+	suite.Run(t, new(ExternalIdentityRepositoryTestSuite))
+}
+
+func (s *ExternalIdentityRepositoryTestSuite) TestGetByProviderSubject() {
+	identity := &model.ExternalIdentity{UserID: 1, Provider: "google", Subject: "external-1", Email: "test@example.com"}
+	s.Require().NoError(s.externalIdentityRepository.Create(identity))
+
+	found, err := s.externalIdentityRepository.GetByProviderSubject("google", "external-1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), identity.ID, found.ID)
+
+	// The same subject at another provider is a different account
+	_, err = s.externalIdentityRepository.GetByProviderSubject("gitlab", "external-1")
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *ExternalIdentityRepositoryTestSuite) TestCreate_DuplicateSubject() {
+	s.Require().NoError(s.externalIdentityRepository.Create(&model.ExternalIdentity{UserID: 1, Provider: "google", Subject: "external-1"}))
+
+	err := s.externalIdentityRepository.Create(&model.ExternalIdentity{UserID: 2, Provider: "google", Subject: "external-1"})
+
+	assert.Error(s.T(), err)
+}
+
+func (s *ExternalIdentityRepositoryTestSuite) TestListByUser() {
+	s.Require().NoError(s.externalIdentityRepository.Create(&model.ExternalIdentity{UserID: 1, Provider: "google", Subject: "a"}))
+	s.Require().NoError(s.externalIdentityRepository.Create(&model.ExternalIdentity{UserID: 1, Provider: "gitlab", Subject: "b"}))
+	s.Require().NoError(s.externalIdentityRepository.Create(&model.ExternalIdentity{UserID: 2, Provider: "google", Subject: "c"}))
+
+	identities, err := s.externalIdentityRepository.ListByUser(1)
+
+	assert.NoError(s.T(), err)
+	if assert.Len(s.T(), identities, 2) {
+		assert.Equal(s.T(), "gitlab", identities[0].Provider)
+		assert.Equal(s.T(), "google", identities[1].Provider)
+	}
+}
+
+func (s *ExternalIdentityRepositoryTestSuite) TestTouchLastLogin() {
+	identity := &model.ExternalIdentity{UserID: 1, Provider: "google", Subject: "external-1"}
+	s.Require().NoError(s.externalIdentityRepository.Create(identity))
+	at := time.Now().Truncate(time.Second)
+
+	err := s.externalIdentityRepository.TouchLastLogin(identity.ID, at)
+
+	assert.NoError(s.T(), err)
+	found, _ := s.externalIdentityRepository.GetByProviderSubject("google", "external-1")
+	assert.True(s.T(), at.Equal(found.LastLoginAt))
+}
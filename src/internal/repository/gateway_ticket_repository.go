@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=GatewayTicketRepository --output=./mocks/repository --outpkg=repository --filename=gateway_ticket_repository.go --structname=MockGatewayTicketRepository --with-expecter=false
+type GatewayTicketRepository interface {
+	// WithContext returns a repository bound to ctx. Tickets are redeemed
+	// before the organization is known, with an unscoped context.
+	WithContext(ctx context.Context) GatewayTicketRepository
+	Create(ticket *model.GatewayTicket) error
+	GetByTokenHash(tokenHash string) (*model.GatewayTicket, error)
+	MarkUsed(id uint, usedAt time.Time) (bool, error)
+	DeleteExpired(before time.Time) (int64, error)
+}
+
+type gatewayTicketRepository struct {
+	db *gorm.DB
+}
+
+func NewGatewayTicketRepository(db *gorm.DB) GatewayTicketRepository {
+	return &gatewayTicketRepository{db: db}
+}
+
+func (r *gatewayTicketRepository) WithContext(ctx context.Context) GatewayTicketRepository {
+	return &gatewayTicketRepository{db: withTransaction(r.db, ctx)}
+}
+
+func (r *gatewayTicketRepository) Create(ticket *model.GatewayTicket) error {
+	return r.db.Create(ticket).Error
+}
+
+func (r *gatewayTicketRepository) GetByTokenHash(tokenHash string) (*model.GatewayTicket, error) {
+	var ticket model.GatewayTicket
+	err := r.db.Where("token_hash = ?", tokenHash).First(&ticket).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// MarkUsed consumes the ticket. It returns false if the ticket was already
+// used, so that a ticket opens one connection at most.
+func (r *gatewayTicketRepository) MarkUsed(id uint, usedAt time.Time) (bool, error) {
+	result := r.db.Model(&model.GatewayTicket{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// DeleteExpired deletes the tickets that expired before the given time,
+// used or not, and returns how many there were.
+func (r *gatewayTicketRepository) DeleteExpired(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", before).Delete(&model.GatewayTicket{})
+	return result.RowsAffected, result.Error
+}
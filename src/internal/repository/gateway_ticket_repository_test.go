@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type GatewayTicketRepositoryTestSuite struct {
+	suite.Suite
+	db *gorm.DB
+	// tickets are issued in organization 1 and redeemed unscoped
+	tickets  GatewayTicketRepository
+	unscoped GatewayTicketRepository
+}
+
+func (s *GatewayTicketRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+	if err := s.db.Use(tenant.Plugin{}); err != nil {
+		s.T().Fatal("Failed to register tenant plugin:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.GatewayTicket{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	repo := NewGatewayTicketRepository(s.db)
+	s.tickets = repo.WithContext(tenant.WithOrganization(context.Background(), 1))
+	s.unscoped = repo.WithContext(tenant.Unscoped(context.Background()))
+}
+
+func (s *GatewayTicketRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *GatewayTicketRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM gateway_tickets")
+}
+
+func TestGatewayTicketRepositorySuite(t *testing.T) {
+	suite.Run(t, new(GatewayTicketRepositoryTestSuite))
+}
+
+func (s *GatewayTicketRepositoryTestSuite) TestCreateAndGetByTokenHash() {
+	ticket := &model.GatewayTicket{UserID: 7, TokenHash: "hash-1", ExpiresAt: time.Now().Add(time.Minute)}
+
+	err := s.tickets.Create(ticket)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), uint(1), ticket.OrganizationID)
+
+	result, err := s.unscoped.GetByTokenHash("hash-1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), ticket.ID, result.ID)
+	assert.Equal(s.T(), uint(7), result.UserID)
+	assert.Nil(s.T(), result.UsedAt)
+}
+
+func (s *GatewayTicketRepositoryTestSuite) TestGetByTokenHash_NotFound() {
+	result, err := s.unscoped.GetByTokenHash("missing")
+
+	assert.Equal(s.T(), gorm.ErrRecordNotFound, err)
+	assert.Nil(s.T(), result)
+}
+
+func (s *GatewayTicketRepositoryTestSuite) TestCreate_NoOrganization() {
+	err := NewGatewayTicketRepository(s.db).WithContext(context.Background()).
+		Create(&model.GatewayTicket{UserID: 7, TokenHash: "hash-1", ExpiresAt: time.Now().Add(time.Minute)})
+
+	assert.ErrorIs(s.T(), err, tenant.ErrNoOrganization)
+}
+
+func (s *GatewayTicketRepositoryTestSuite) TestMarkUsed_OnlyOnce() {
+	ticket := &model.GatewayTicket{UserID: 7, TokenHash: "hash-1", ExpiresAt: time.Now().Add(time.Minute)}
+	s.tickets.Create(ticket)
+
+	used, err := s.unscoped.MarkUsed(ticket.ID, time.Now())
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), used)
+
+	used, err = s.unscoped.MarkUsed(ticket.ID, time.Now())
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), used)
+}
+
+func (s *GatewayTicketRepositoryTestSuite) TestDeleteExpired() {
+	now := time.Now()
+	s.tickets.Create(&model.GatewayTicket{UserID: 7, TokenHash: "expired", ExpiresAt: now.Add(-time.Minute)})
+	s.tickets.Create(&model.GatewayTicket{UserID: 7, TokenHash: "valid", ExpiresAt: now.Add(time.Minute)})
+
+	deleted, err := s.unscoped.DeleteExpired(now)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(1), deleted)
+	_, err = s.unscoped.GetByTokenHash("expired")
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+	_, err = s.unscoped.GetByTokenHash("valid")
+	assert.NoError(s.T(), err)
+}
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=GroupRepository --output=./mocks/repository --outpkg=repository --filename=group_repository.go --structname=MockGroupRepository --with-expecter=false
+type GroupRepository interface {
+	// WithContext returns a repository bound to ctx. All methods work on the
+	// groups of the organization of ctx and return tenant.ErrNoOrganization
+	// without one.
+	WithContext(ctx context.Context) GroupRepository
+	Create(group *model.Group) error
+	GetByID(id uint) (*model.Group, error)
+	GetByName(name string) (*model.Group, error)
+	List() ([]*model.Group, error)
+	ListByUser(userID uint) ([]*model.Group, error)
+	Update(group *model.Group) (bool, error)
+	Delete(id uint) (bool, error)
+	ListMembers(groupID uint) ([]*model.GroupMember, error)
+	AddMembers(groupID uint, userIDs []uint) (int64, error)
+	RemoveMembers(groupID uint, userIDs []uint) (int64, error)
+	ListPermissions(groupIDs []uint) ([]*model.GroupPermission, error)
+	SetPermissions(groupID uint, permissions []string) error
+}
+
+type groupRepository struct {
+	db *gorm.DB
+}
+
+func NewGroupRepository(db *gorm.DB) GroupRepository {
+	return &groupRepository{db: db}
+}
+
+func (r *groupRepository) WithContext(ctx context.Context) GroupRepository {
+	return &groupRepository{db: withTransaction(r.db, ctx)}
+}
+
+func (r *groupRepository) Create(group *model.Group) error {
+	if err := requireOrganization(r.db); err != nil {
+		return err
+	}
+	return r.db.Create(group).Error
+}
+
+func (r *groupRepository) GetByID(id uint) (*model.Group, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return nil, err
+	}
+
+	var group model.Group
+	err := r.db.First(&group, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+func (r *groupRepository) GetByName(name string) (*model.Group, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return nil, err
+	}
+
+	var group model.Group
+	err := r.db.Where("name = ?", name).First(&group).Error
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// List returns the groups of the organization by name.
+func (r *groupRepository) List() ([]*model.Group, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return nil, err
+	}
+
+	var groups []*model.Group
+	err := r.db.Order("name").Find(&groups).Error
+	return groups, err
+}
+
+// ListByUser returns the groups the user is a direct member of.
+func (r *groupRepository) ListByUser(userID uint) ([]*model.Group, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return nil, err
+	}
+
+	var groups []*model.Group
+	err := r.db.Joins("JOIN group_members ON group_members.group_id = groups.id").
+		Where("group_members.user_id = ?", userID).
+		Order("groups.name").
+		Find(&groups).Error
+	return groups, err
+}
+
+// Update stores the name and parent of the group. It reports false if the
+// group does not exist.
+func (r *groupRepository) Update(group *model.Group) (bool, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return false, err
+	}
+
+	result := r.db.Model(&model.Group{}).
+		Where("id = ?", group.ID).
+		Updates(map[string]interface{}{"name": group.Name, "parent_id": group.ParentID})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Delete removes the group with its members and permissions. Its subgroups
+// move up to its parent. It reports false if the group does not exist.
+func (r *groupRepository) Delete(id uint) (bool, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return false, err
+	}
+
+	deleted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var group model.Group
+		result := tx.Limit(1).Find(&group, id)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		if err := tx.Model(&model.Group{}).Where("parent_id = ?", id).Update("parent_id", group.ParentID).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("group_id = ?", id).Delete(&model.GroupMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("group_id = ?", id).Delete(&model.GroupPermission{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&group).Error; err != nil {
+			return err
+		}
+		deleted = true
+		return nil
+	})
+	return deleted, err
+}
+
+// ListMembers returns the direct members of the group, oldest first.
+func (r *groupRepository) ListMembers(groupID uint) ([]*model.GroupMember, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return nil, err
+	}
+
+	var members []*model.GroupMember
+	err := r.db.Where("group_id = ?", groupID).Order("id").Find(&members).Error
+	return members, err
+}
+
+// AddMembers puts the users in the group and returns how many were not in
+// it already.
+func (r *groupRepository) AddMembers(groupID uint, userIDs []uint) (int64, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return 0, err
+	}
+
+	members := make([]*model.GroupMember, 0, len(userIDs))
+	for _, userID := range userIDs {
+		members = append(members, &model.GroupMember{GroupID: groupID, UserID: userID})
+	}
+
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(members)
+	return result.RowsAffected, result.Error
+}
+
+// RemoveMembers takes the users out of the group and returns how many were
+// in it.
+func (r *groupRepository) RemoveMembers(groupID uint, userIDs []uint) (int64, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return 0, err
+	}
+
+	result := r.db.Where("group_id = ? AND user_id IN ?", groupID, userIDs).Delete(&model.GroupMember{})
+	return result.RowsAffected, result.Error
+}
+
+// ListPermissions returns the permissions granted to the groups.
+func (r *groupRepository) ListPermissions(groupIDs []uint) ([]*model.GroupPermission, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return nil, err
+	}
+	if len(groupIDs) == 0 {
+		return nil, nil
+	}
+
+	var permissions []*model.GroupPermission
+	err := r.db.Where("group_id IN ?", groupIDs).Order("permission").Find(&permissions).Error
+	return permissions, err
+}
+
+// SetPermissions replaces the permissions granted to the group.
+func (r *groupRepository) SetPermissions(groupID uint, permissions []string) error {
+	if err := requireOrganization(r.db); err != nil {
+		return err
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("group_id = ?", groupID).Delete(&model.GroupPermission{}).Error; err != nil {
+			return err
+		}
+		if len(permissions) == 0 {
+			return nil
+		}
+
+		grants := make([]*model.GroupPermission, 0, len(permissions))
+		for _, permission := range permissions {
+			grants = append(grants, &model.GroupPermission{GroupID: groupID, Permission: permission})
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(grants).Error
+	})
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type GroupRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	acme GroupRepository
+	// globex works on the groups of the other organization
+	globex GroupRepository
+	orgs   []*model.Organization
+	// engineering has backend as its subgroup
+	engineering *model.Group
+	backend     *model.Group
+}
+
+func (s *GroupRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+	if err := s.db.Use(tenant.Plugin{}); err != nil {
+		s.T().Fatal("Failed to register tenant plugin:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.Organization{}, &model.Group{}, &model.GroupMember{}, &model.GroupPermission{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.orgs = []*model.Organization{{Name: "Acme", Slug: "acme"}, {Name: "Globex", Slug: "globex"}}
+	s.db.Create(s.orgs)
+
+	repo := NewGroupRepository(s.db)
+	s.acme = repo.WithContext(tenant.WithOrganization(context.Background(), s.orgs[0].ID))
+	s.globex = repo.WithContext(tenant.WithOrganization(context.Background(), s.orgs[1].ID))
+}
+
+func (s *GroupRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *GroupRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM group_permissions")
+	s.db.Exec("DELETE FROM group_members")
+	s.db.Exec("DELETE FROM groups")
+
+	s.engineering = &model.Group{Name: "Engineering"}
+	s.acme.Create(s.engineering)
+	s.backend = &model.Group{Name: "Backend", ParentID: &s.engineering.ID}
+	s.acme.Create(s.backend)
+	s.globex.Create(&model.Group{Name: "Engineering"})
+
+	s.acme.AddMembers(s.engineering.ID, []uint{1, 2})
+	s.acme.AddMembers(s.backend.ID, []uint{2})
+	s.acme.SetPermissions(s.engineering.ID, []string{model.PermissionGroupsManage})
+}
+
+func TestGroupRepositorySuite(t *testing.T) {
+	suite.Run(t, new(GroupRepositoryTestSuite))
+}
+
+func (s *GroupRepositoryTestSuite) TestCreate_DuplicateName() {
+	err := s.acme.Create(&model.Group{Name: "Backend"})
+
+	assert.Error(s.T(), err)
+}
+
+func (s *GroupRepositoryTestSuite) TestGetByID_OtherOrganization() {
+	_, err := s.globex.GetByID(s.engineering.ID)
+
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *GroupRepositoryTestSuite) TestGetByName() {
+	group, err := s.globex.GetByName("Engineering")
+
+	assert.NoError(s.T(), err)
+	assert.NotEqual(s.T(), s.engineering.ID, group.ID)
+	assert.Equal(s.T(), s.orgs[1].ID, group.OrganizationID)
+}
+
+func (s *GroupRepositoryTestSuite) TestList() {
+	groups, err := s.acme.List()
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), groups, 2)
+	assert.Equal(s.T(), "Backend", groups[0].Name)
+	assert.Equal(s.T(), "Engineering", groups[1].Name)
+}
+
+func (s *GroupRepositoryTestSuite) TestListByUser() {
+	groups, err := s.acme.ListByUser(2)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), groups, 2)
+
+	groups, err = s.globex.ListByUser(2)
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), groups)
+}
+
+func (s *GroupRepositoryTestSuite) TestUpdate() {
+	s.backend.Name = "Platform"
+	s.backend.ParentID = nil
+
+	updated, err := s.acme.Update(s.backend)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), updated)
+
+	group, _ := s.acme.GetByID(s.backend.ID)
+	assert.Equal(s.T(), "Platform", group.Name)
+	assert.Nil(s.T(), group.ParentID)
+
+	updated, err = s.globex.Update(s.backend)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), updated)
+}
+
+func (s *GroupRepositoryTestSuite) TestDelete() {
+	deleted, err := s.globex.Delete(s.engineering.ID)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), deleted)
+
+	deleted, err = s.acme.Delete(s.engineering.ID)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), deleted)
+
+	// Assert: subgroups move up, members and permissions go
+	backend, _ := s.acme.GetByID(s.backend.ID)
+	assert.Nil(s.T(), backend.ParentID)
+	members, _ := s.acme.ListMembers(s.engineering.ID)
+	assert.Empty(s.T(), members)
+	permissions, _ := s.acme.ListPermissions([]uint{s.engineering.ID})
+	assert.Empty(s.T(), permissions)
+}
+
+func (s *GroupRepositoryTestSuite) TestAddMembers() {
+	added, err := s.acme.AddMembers(s.backend.ID, []uint{1, 2, 3})
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(2), added)
+	members, _ := s.acme.ListMembers(s.backend.ID)
+	assert.Len(s.T(), members, 3)
+	for _, member := range members {
+		assert.Equal(s.T(), s.orgs[0].ID, member.OrganizationID)
+	}
+}
+
+func (s *GroupRepositoryTestSuite) TestRemoveMembers() {
+	removed, err := s.globex.RemoveMembers(s.engineering.ID, []uint{1, 2})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(0), removed)
+
+	removed, err = s.acme.RemoveMembers(s.engineering.ID, []uint{1, 3})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(1), removed)
+
+	members, _ := s.acme.ListMembers(s.engineering.ID)
+	assert.Len(s.T(), members, 1)
+	assert.Equal(s.T(), uint(2), members[0].UserID)
+}
+
+func (s *GroupRepositoryTestSuite) TestSetPermissions() {
+	err := s.acme.SetPermissions(s.backend.ID, []string{model.PermissionGroupsManage, model.PermissionGroupsManage})
+	assert.NoError(s.T(), err)
+
+	err = s.acme.SetPermissions(s.engineering.ID, nil)
+	assert.NoError(s.T(), err)
+
+	permissions, err := s.acme.ListPermissions([]uint{s.engineering.ID, s.backend.ID})
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), permissions, 1)
+	assert.Equal(s.T(), s.backend.ID, permissions[0].GroupID)
+}
+
+func (s *GroupRepositoryTestSuite) TestNoOrganization() {
+	unbound := NewGroupRepository(s.db)
+
+	_, err := unbound.List()
+	assert.ErrorIs(s.T(), err, tenant.ErrNoOrganization)
+	_, err = unbound.AddMembers(s.engineering.ID, []uint{3})
+	assert.ErrorIs(s.T(), err, tenant.ErrNoOrganization)
+	_, err = unbound.Delete(s.engineering.ID)
+	assert.ErrorIs(s.T(), err, tenant.ErrNoOrganization)
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=IdempotencyRepository --output=./mocks/repository --outpkg=repository --filename=idempotency_repository.go --structname=MockIdempotencyRepository --with-expecter=false
+type IdempotencyRepository interface {
+	Lock(key *model.IdempotencyKey) (bool, error)
+	GetByKey(scope, key string) (*model.IdempotencyKey, error)
+	Complete(key *model.IdempotencyKey) error
+	Release(key *model.IdempotencyKey) error
+	DeleteExpired(now time.Time) (int64, error)
+}
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+// Lock inserts the key if it does not exist yet. It returns false when the
+// key is already held by an earlier request.
+func (r *idempotencyRepository) Lock(key *model.IdempotencyKey) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(key)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *idempotencyRepository) GetByKey(scope, key string) (*model.IdempotencyKey, error) {
+	var record model.IdempotencyKey
+	err := r.db.Where(&model.IdempotencyKey{Scope: scope, Key: key}).First(&record).Error
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Complete stores the response of the request holding the lock of the key.
+// It does nothing once the lock has been reclaimed by another request.
+func (r *idempotencyRepository) Complete(key *model.IdempotencyKey) error {
+	return r.db.Model(&model.IdempotencyKey{}).
+		Where(&model.IdempotencyKey{Scope: key.Scope, Key: key.Key, LockID: key.LockID}).
+		Updates(map[string]interface{}{
+			"response_status": key.ResponseStatus,
+			"response_body":   key.ResponseBody,
+			"content_type":    key.ContentType,
+			"completed_at":    key.CompletedAt,
+		}).Error
+}
+
+// Release deletes the key if it is still held by the same lock, so that a
+// request never deletes a key locked again after it looked at it.
+func (r *idempotencyRepository) Release(key *model.IdempotencyKey) error {
+	return r.db.Where(&model.IdempotencyKey{Scope: key.Scope, Key: key.Key, LockID: key.LockID}).
+		Delete(&model.IdempotencyKey{}).Error
+}
+
+func (r *idempotencyRepository) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at <= ?", now).Delete(&model.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
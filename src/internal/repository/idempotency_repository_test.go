@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type IdempotencyRepositoryTestSuite struct {
+	suite.Suite
+	db                    *gorm.DB
+	idempotencyRepository IdempotencyRepository
+}
+
+func (s *IdempotencyRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.IdempotencyKey{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.idempotencyRepository = NewIdempotencyRepository(s.db)
+}
+
+func (s *IdempotencyRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *IdempotencyRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM idempotency_keys")
+}
+
+func TestIdempotencyRepositorySuite(t *testing.T) {
+	suite.Run(t, new(IdempotencyRepositoryTestSuite))
+}
+
+func (s *IdempotencyRepositoryTestSuite) newKey(key string) *model.IdempotencyKey {
+	now := time.Now()
+	return &model.IdempotencyKey{
+		Scope:       "scope",
+		Key:         key,
+		LockID:      "lock-" + key,
+		RequestHash: "hash",
+		LockedUntil: now.Add(time.Minute),
+		ExpiresAt:   now.Add(time.Hour),
+	}
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestLock_Success() {
+	locked, err := s.idempotencyRepository.Lock(s.newKey("key-1"))
+
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), locked)
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestLock_AlreadyLocked() {
+	locked, err := s.idempotencyRepository.Lock(s.newKey("key-1"))
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), locked)
+
+	second := s.newKey("key-1")
+	second.RequestHash = "other"
+	locked, err = s.idempotencyRepository.Lock(second)
+
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), locked)
+
+	// The original record is kept
+	record, err := s.idempotencyRepository.GetByKey("scope", "key-1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "hash", record.RequestHash)
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestGetByKey_NotFound() {
+	record, err := s.idempotencyRepository.GetByKey("scope", "missing")
+
+	assert.Error(s.T(), err)
+	assert.Nil(s.T(), record)
+	assert.Equal(s.T(), gorm.ErrRecordNotFound, err)
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestComplete_Success() {
+	key := s.newKey("key-1")
+	_, err := s.idempotencyRepository.Lock(key)
+	assert.NoError(s.T(), err)
+
+	completedAt := time.Now()
+	key.ResponseStatus = 201
+	key.ResponseBody = []byte(`{"id":1}`)
+	key.ContentType = "application/json"
+	key.CompletedAt = &completedAt
+
+	err = s.idempotencyRepository.Complete(key)
+	assert.NoError(s.T(), err)
+
+	record, err := s.idempotencyRepository.GetByKey("scope", "key-1")
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), record.IsCompleted())
+	assert.Equal(s.T(), 201, record.ResponseStatus)
+	assert.Equal(s.T(), `{"id":1}`, string(record.ResponseBody))
+	assert.Equal(s.T(), "application/json", record.ContentType)
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestLock_ScopedKeys() {
+	other := s.newKey("key-1")
+	other.Scope = "other"
+	locked, err := s.idempotencyRepository.Lock(other)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), locked)
+
+	locked, err = s.idempotencyRepository.Lock(s.newKey("key-1"))
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), locked)
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestComplete_LockReclaimed() {
+	key := s.newKey("key-1")
+	_, err := s.idempotencyRepository.Lock(key)
+	assert.NoError(s.T(), err)
+
+	stale := *key
+	stale.LockID = "reclaimed"
+	completedAt := time.Now()
+	stale.CompletedAt = &completedAt
+	assert.NoError(s.T(), s.idempotencyRepository.Complete(&stale))
+
+	record, err := s.idempotencyRepository.GetByKey("scope", "key-1")
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), record.IsCompleted())
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestRelease_OtherLockKept() {
+	_, err := s.idempotencyRepository.Lock(s.newKey("key-1"))
+	assert.NoError(s.T(), err)
+
+	stale := s.newKey("key-1")
+	stale.LockID = "reclaimed"
+	assert.NoError(s.T(), s.idempotencyRepository.Release(stale))
+
+	_, err = s.idempotencyRepository.GetByKey("scope", "key-1")
+	assert.NoError(s.T(), err)
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestRelease_Success() {
+	_, err := s.idempotencyRepository.Lock(s.newKey("key-1"))
+	assert.NoError(s.T(), err)
+
+	err = s.idempotencyRepository.Release(s.newKey("key-1"))
+	assert.NoError(s.T(), err)
+
+	_, err = s.idempotencyRepository.GetByKey("scope", "key-1")
+	assert.Equal(s.T(), gorm.ErrRecordNotFound, err)
+
+	// The key can be locked again once released
+	locked, err := s.idempotencyRepository.Lock(s.newKey("key-1"))
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), locked)
+}
+
+func (s *IdempotencyRepositoryTestSuite) TestDeleteExpired() {
+	expired := s.newKey("expired")
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+	s.idempotencyRepository.Lock(expired)
+	s.idempotencyRepository.Lock(s.newKey("active"))
+
+	deleted, err := s.idempotencyRepository.DeleteExpired(time.Now())
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(1), deleted)
+
+	_, err = s.idempotencyRepository.GetByKey("scope", "expired")
+	assert.Error(s.T(), err)
+	_, err = s.idempotencyRepository.GetByKey("scope", "active")
+	assert.NoError(s.T(), err)
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=InvitationRepository --output=./mocks/repository --outpkg=repository --filename=invitation_repository.go --structname=MockInvitationRepository --with-expecter=false
+type InvitationRepository interface {
+	// WithContext returns a repository bound to ctx. Bound to an
+	// organization it only sees and changes that organization's invitations.
+	WithContext(ctx context.Context) InvitationRepository
+	Create(invitation *model.Invitation) error
+	GetByID(id uint) (*model.Invitation, error)
+	GetByTokenHash(tokenHash string) (*model.Invitation, error)
+	HasPending(email string, now time.Time) (bool, error)
+	List(limit, offset int) ([]*model.Invitation, error)
+	Renew(id uint, tokenHash string, expiresAt time.Time) (bool, error)
+	Revoke(id uint, revokedAt time.Time) (bool, error)
+	MarkAccepted(id uint, tokenHash string, acceptedAt time.Time) (bool, error)
+}
+
+type invitationRepository struct {
+	db *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB) InvitationRepository {
+	return &invitationRepository{db: db}
+}
+
+func (r *invitationRepository) WithContext(ctx context.Context) InvitationRepository {
+	return &invitationRepository{db: withTransaction(r.db, ctx)}
+}
+
+func (r *invitationRepository) Create(invitation *model.Invitation) error {
+	return r.db.Create(invitation).Error
+}
+
+func (r *invitationRepository) GetByID(id uint) (*model.Invitation, error) {
+	var invitation model.Invitation
+	err := r.db.First(&invitation, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+func (r *invitationRepository) GetByTokenHash(tokenHash string) (*model.Invitation, error) {
+	var invitation model.Invitation
+	err := r.db.Where("token_hash = ?", tokenHash).First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// HasPending reports whether the address has an invitation that can still
+// be accepted.
+func (r *invitationRepository) HasPending(email string, now time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&model.Invitation{}).
+		Where("email = ? AND accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", email, now).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// List returns invitations newest first.
+func (r *invitationRepository) List(limit, offset int) ([]*model.Invitation, error) {
+	var invitations []*model.Invitation
+	err := r.db.Order("created_at DESC, id DESC").Limit(limit).Offset(offset).Find(&invitations).Error
+	return invitations, err
+}
+
+// Renew replaces the token of an open invitation, so that earlier links stop
+// working, and moves its expiry. It reports false if the invitation does not
+// exist or was accepted or revoked.
+func (r *invitationRepository) Renew(id uint, tokenHash string, expiresAt time.Time) (bool, error) {
+	result := r.db.Model(&model.Invitation{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"token_hash": tokenHash, "expires_at": expiresAt})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Revoke revokes an open invitation. It reports false if the invitation does
+// not exist or was accepted or revoked.
+func (r *invitationRepository) Revoke(id uint, revokedAt time.Time) (bool, error) {
+	result := r.db.Model(&model.Invitation{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// MarkAccepted consumes the invitation. It reports false if it was accepted
+// or revoked in the meantime, or resent with a new token.
+func (r *invitationRepository) MarkAccepted(id uint, tokenHash string, acceptedAt time.Time) (bool, error) {
+	result := r.db.Model(&model.Invitation{}).
+		Where("id = ? AND token_hash = ? AND accepted_at IS NULL AND revoked_at IS NULL", id, tokenHash).
+		Update("accepted_at", acceptedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type InvitationRepositoryTestSuite struct {
+	suite.Suite
+	db                   *gorm.DB
+	invitationRepository InvitationRepository
+}
+
+func (s *InvitationRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	if err := s.db.Use(tenant.Plugin{}); err != nil {
+		s.T().Fatal("Failed to register tenant plugin:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.Invitation{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.invitationRepository = NewInvitationRepository(s.db).WithContext(tenant.WithOrganization(context.Background(), 1))
+}
+
+func (s *InvitationRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *InvitationRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM invitations")
+}
+
+func TestInvitationRepositorySuite(t *testing.T) {
+	suite.Run(t, new(InvitationRepositoryTestSuite))
+}
+
+func (s *InvitationRepositoryTestSuite) newInvitation(email, tokenHash string) *model.Invitation {
+	invitation := &model.Invitation{
+		Email:     email,
+		Role:      model.RoleUser,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	if err := s.invitationRepository.Create(invitation); err != nil {
+		s.T().Fatal("Failed to create invitation:", err)
+	}
+	return invitation
+}
+
+func (s *InvitationRepositoryTestSuite) TestCreateAndGet() {
+	invitation := s.newInvitation("test@example.com", "hash-1")
+	assert.NotZero(s.T(), invitation.ID)
+
+	result, err := s.invitationRepository.GetByID(invitation.ID)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "test@example.com", result.Email)
+
+	result, err = s.invitationRepository.GetByTokenHash("hash-1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), invitation.ID, result.ID)
+	assert.Equal(s.T(), model.InvitationStatusPending, result.Status(time.Now()))
+}
+
+func (s *InvitationRepositoryTestSuite) TestGetByTokenHash_NotFound() {
+	result, err := s.invitationRepository.GetByTokenHash("missing")
+
+	assert.Equal(s.T(), gorm.ErrRecordNotFound, err)
+	assert.Nil(s.T(), result)
+}
+
+func (s *InvitationRepositoryTestSuite) TestHasPending() {
+	now := time.Now()
+	s.newInvitation("pending@example.com", "hash-1")
+	s.invitationRepository.Create(&model.Invitation{Email: "expired@example.com", Role: model.RoleUser, TokenHash: "hash-2", ExpiresAt: now.Add(-time.Minute)})
+	revoked := s.newInvitation("revoked@example.com", "hash-3")
+	s.invitationRepository.Revoke(revoked.ID, now)
+
+	for email, expected := range map[string]bool{
+		"pending@example.com": true,
+		"expired@example.com": false,
+		"revoked@example.com": false,
+		"missing@example.com": false,
+	} {
+		pending, err := s.invitationRepository.HasPending(email, now)
+		assert.NoError(s.T(), err)
+		assert.Equal(s.T(), expected, pending, email)
+	}
+}
+
+func (s *InvitationRepositoryTestSuite) TestList() {
+	first := s.newInvitation("first@example.com", "hash-1")
+	second := s.newInvitation("second@example.com", "hash-2")
+
+	invitations, err := s.invitationRepository.List(10, 0)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), invitations, 2)
+	assert.Equal(s.T(), second.ID, invitations[0].ID)
+
+	invitations, err = s.invitationRepository.List(10, 1)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), invitations, 1)
+	assert.Equal(s.T(), first.ID, invitations[0].ID)
+}
+
+func (s *InvitationRepositoryTestSuite) TestList_OtherOrganization() {
+	invitation := s.newInvitation("first@example.com", "hash-1")
+	other := NewInvitationRepository(s.db).WithContext(tenant.WithOrganization(context.Background(), 2))
+
+	invitations, err := other.List(10, 0)
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), invitations)
+
+	revoked, err := other.Revoke(invitation.ID, time.Now())
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), revoked)
+}
+
+func (s *InvitationRepositoryTestSuite) TestRenew() {
+	invitation := s.newInvitation("test@example.com", "hash-1")
+	expiresAt := time.Now().Add(48 * time.Hour)
+
+	renewed, err := s.invitationRepository.Renew(invitation.ID, "hash-2", expiresAt)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), renewed)
+
+	_, err = s.invitationRepository.GetByTokenHash("hash-1")
+	assert.Equal(s.T(), gorm.ErrRecordNotFound, err)
+	result, _ := s.invitationRepository.GetByTokenHash("hash-2")
+	assert.WithinDuration(s.T(), expiresAt, result.ExpiresAt, time.Second)
+}
+
+func (s *InvitationRepositoryTestSuite) TestRenew_Accepted() {
+	invitation := s.newInvitation("test@example.com", "hash-1")
+	s.invitationRepository.MarkAccepted(invitation.ID, "hash-1", time.Now())
+
+	renewed, err := s.invitationRepository.Renew(invitation.ID, "hash-2", time.Now().Add(time.Hour))
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), renewed)
+}
+
+func (s *InvitationRepositoryTestSuite) TestRevoke() {
+	invitation := s.newInvitation("test@example.com", "hash-1")
+
+	revoked, err := s.invitationRepository.Revoke(invitation.ID, time.Now())
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), revoked)
+
+	revoked, err = s.invitationRepository.Revoke(invitation.ID, time.Now())
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), revoked)
+
+	result, _ := s.invitationRepository.GetByID(invitation.ID)
+	assert.Equal(s.T(), model.InvitationStatusRevoked, result.Status(time.Now()))
+}
+
+func (s *InvitationRepositoryTestSuite) TestMarkAccepted() {
+	invitation := s.newInvitation("test@example.com", "hash-1")
+
+	accepted, err := s.invitationRepository.MarkAccepted(invitation.ID, "hash-1", time.Now())
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), accepted)
+
+	accepted, err = s.invitationRepository.MarkAccepted(invitation.ID, "hash-1", time.Now())
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), accepted)
+
+	result, _ := s.invitationRepository.GetByID(invitation.ID)
+	assert.Equal(s.T(), model.InvitationStatusAccepted, result.Status(time.Now()))
+}
+
+func (s *InvitationRepositoryTestSuite) TestMarkAccepted_Resent() {
+	invitation := s.newInvitation("test@example.com", "hash-1")
+	s.invitationRepository.Renew(invitation.ID, "hash-2", time.Now().Add(time.Hour))
+
+	accepted, err := s.invitationRepository.MarkAccepted(invitation.ID, "hash-1", time.Now())
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), accepted)
+}
@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errJobTimedOut is recorded on a job whose worker held it past its
+// visibility timeout on its last attempt.
+const errJobTimedOut = "job did not finish within the visibility timeout"
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=JobRepository --output=./mocks/repository --outpkg=repository --filename=job_repository.go --structname=MockJobRepository --with-expecter=false
+type JobRepository interface {
+	// WithContext returns a repository bound to ctx. With an organization
+	// in ctx, jobs are enqueued in it and only its jobs are seen; with an
+	// unscoped ctx, as in the worker, all jobs are.
+	WithContext(ctx context.Context) JobRepository
+	Enqueue(job *model.Job) (bool, error)
+	GetByID(id uint) (*model.Job, error)
+	List(filter *model.JobFilter, limit, offset int) ([]*model.Job, error)
+	Claim(types []string, limit int, timeout time.Duration) ([]*model.Job, error)
+	Finish(job *model.Job) (bool, error)
+	Retry(id uint) (bool, error)
+	Cancel(id uint) (bool, error)
+	DeleteFinished(before time.Time) (int64, error)
+}
+
+type jobRepository struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) JobRepository {
+	return &jobRepository{db: db}
+}
+
+func (r *jobRepository) WithContext(ctx context.Context) JobRepository {
+	return &jobRepository{db: withTransaction(r.db, ctx)}
+}
+
+// Enqueue stores a pending job. It reports false, storing nothing, if an
+// unfinished job holds its unique key.
+func (r *jobRepository) Enqueue(job *model.Job) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(job)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *jobRepository) GetByID(id uint) (*model.Job, error) {
+	var job model.Job
+	err := r.db.First(&job, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// List returns a page of jobs, newest first.
+func (r *jobRepository) List(filter *model.JobFilter, limit, offset int) ([]*model.Job, error) {
+	query := r.db
+	if filter != nil {
+		if filter.Status != "" {
+			query = query.Where("status = ?", filter.Status)
+		}
+		if filter.Type != "" {
+			query = query.Where("type = ?", filter.Type)
+		}
+	}
+
+	var jobs []*model.Job
+	err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&jobs).Error
+	return jobs, err
+}
+
+// Claim marks up to limit jobs of the types as running, oldest due first,
+// and holds them for timeout. Pending jobs that are due are claimed, and so
+// are running jobs whose worker held them past their timeout, unless that
+// was their last attempt, in which case they fail. Rows locked by another
+// worker are skipped where the database supports it (Postgres); SQLite
+// serializes writers instead.
+func (r *jobRepository) Claim(types []string, limit int, timeout time.Duration) ([]*model.Job, error) {
+	if len(types) == 0 {
+		return nil, nil
+	}
+
+	now := time.Now()
+	var jobs []*model.Job
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&model.Job{}).
+			Where("type IN ? AND status = ? AND locked_until <= ? AND attempts >= max_attempts", types, model.JobRunning, now).
+			Updates(map[string]interface{}{
+				"status":       model.JobFailed,
+				"last_error":   errJobTimedOut,
+				"locked_until": nil,
+				"finished_at":  now,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("type IN ?", types).
+			Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_until <= ?)", model.JobPending, now, model.JobRunning, now).
+			Order("run_at, id").
+			Limit(limit).
+			Find(&jobs).Error
+		if err != nil || len(jobs) == 0 {
+			return err
+		}
+
+		lockedUntil := now.Add(timeout)
+		ids := make([]uint, 0, len(jobs))
+		for _, job := range jobs {
+			ids = append(ids, job.ID)
+			job.Status = model.JobRunning
+			job.Attempts++
+			job.LockedUntil = &lockedUntil
+		}
+
+		return tx.Model(&model.Job{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":       model.JobRunning,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"locked_until": lockedUntil,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// Finish stores the outcome of the attempt the job was claimed for: its
+// status, error and when it runs again or finished. It reports false if the
+// job has since been cancelled or claimed again, in which case the outcome
+// is dropped.
+func (r *jobRepository) Finish(job *model.Job) (bool, error) {
+	result := r.db.Model(&model.Job{}).
+		Where("id = ? AND status = ? AND attempts = ?", job.ID, model.JobRunning, job.Attempts).
+		Updates(map[string]interface{}{
+			"status":       job.Status,
+			"run_at":       job.RunAt,
+			"last_error":   job.LastError,
+			"locked_until": nil,
+			"finished_at":  job.FinishedAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Retry makes a failed or cancelled job pending and due now with a fresh
+// set of attempts. It reports false if the job is in another state, or if
+// another unfinished job holds its unique key.
+func (r *jobRepository) Retry(id uint) (bool, error) {
+	result := r.db.Model(&model.Job{}).
+		Where("id = ? AND status IN ?", id, []string{model.JobFailed, model.JobCancelled}).
+		Where("unique_key IS NULL OR NOT EXISTS (SELECT 1 FROM jobs other WHERE other.unique_key = jobs.unique_key AND other.finished_at IS NULL)").
+		Updates(map[string]interface{}{
+			"status":      model.JobPending,
+			"attempts":    0,
+			"run_at":      time.Now(),
+			"finished_at": nil,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Cancel stops a pending job from running. It reports false if the job is
+// not pending.
+func (r *jobRepository) Cancel(id uint) (bool, error) {
+	result := r.db.Model(&model.Job{}).
+		Where("id = ? AND status = ?", id, model.JobPending).
+		Updates(map[string]interface{}{
+			"status":      model.JobCancelled,
+			"finished_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// DeleteFinished removes the jobs that finished before the time and returns
+// how many there were.
+func (r *jobRepository) DeleteFinished(before time.Time) (int64, error) {
+	result := r.db.Where("finished_at < ?", before).Delete(&model.Job{})
+	return result.RowsAffected, result.Error
+}
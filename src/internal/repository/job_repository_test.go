@@ -0,0 +1,231 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type JobRepositoryTestSuite struct {
+	suite.Suite
+	db *gorm.DB
+	// repo is bound to an organization; worker works on all jobs
+	repo   JobRepository
+	worker JobRepository
+}
+
+func (s *JobRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+	if err := s.db.Use(tenant.Plugin{}); err != nil {
+		s.T().Fatal("Failed to register tenant plugin:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.Job{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	repo := NewJobRepository(s.db)
+	s.repo = repo.WithContext(tenant.WithOrganization(context.Background(), 1))
+	s.worker = repo.WithContext(tenant.Unscoped(context.Background()))
+}
+
+func (s *JobRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *JobRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM jobs")
+}
+
+func TestJobRepositorySuite(t *testing.T) {
+	suite.Run(t, new(JobRepositoryTestSuite))
+}
+
+func newJob(jobType string, runAt time.Time) *model.Job {
+	return &model.Job{
+		Type:        jobType,
+		Payload:     `{}`,
+		Status:      model.JobPending,
+		MaxAttempts: 3,
+		RunAt:       runAt,
+	}
+}
+
+func newJobWithKey(jobType, key string) *model.Job {
+	job := newJob(jobType, time.Now())
+	job.UniqueKey = &key
+	return job
+}
+
+func (s *JobRepositoryTestSuite) enqueue(job *model.Job) *model.Job {
+	if _, err := s.repo.Enqueue(job); err != nil {
+		s.T().Fatal("Failed to enqueue job:", err)
+	}
+	return job
+}
+
+func (s *JobRepositoryTestSuite) TestEnqueue_Organization() {
+	job := s.enqueue(newJob("email.send", time.Now()))
+	s.worker.Enqueue(newJob("email.send", time.Now()))
+
+	assert.Equal(s.T(), uint(1), job.OrganizationID)
+	// Jobs enqueued outside an organization are only seen without one
+	jobs, _ := s.repo.List(nil, 10, 0)
+	assert.Len(s.T(), jobs, 1)
+	jobs, _ = s.worker.List(nil, 10, 0)
+	assert.Len(s.T(), jobs, 2)
+}
+
+func (s *JobRepositoryTestSuite) TestEnqueue_UniqueKey() {
+	enqueued, err := s.repo.Enqueue(newJobWithKey("email.send", "welcome:3"))
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), enqueued)
+
+	enqueued, err = s.repo.Enqueue(newJobWithKey("email.send", "welcome:3"))
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), enqueued)
+
+	// The key is free again once the job has finished
+	jobs, _ := s.worker.Claim([]string{"email.send"}, 10, time.Minute)
+	now := time.Now()
+	jobs[0].Status = model.JobSucceeded
+	jobs[0].FinishedAt = &now
+	s.worker.Finish(jobs[0])
+
+	enqueued, err = s.repo.Enqueue(newJobWithKey("email.send", "welcome:3"))
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), enqueued)
+}
+
+func (s *JobRepositoryTestSuite) TestClaim_DueJobsOfTypes() {
+	s.enqueue(newJob("email.send", time.Now().Add(-time.Minute)))
+	s.enqueue(newJob("email.send", time.Now().Add(time.Hour)))
+	s.enqueue(newJob("report.export", time.Now()))
+
+	jobs, err := s.worker.Claim([]string{"email.send"}, 10, time.Minute)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), jobs, 1)
+	assert.Equal(s.T(), model.JobRunning, jobs[0].Status)
+	assert.Equal(s.T(), 1, jobs[0].Attempts)
+	assert.NotNil(s.T(), jobs[0].LockedUntil)
+
+	// Claimed jobs are held until the visibility timeout runs out
+	jobs, _ = s.worker.Claim([]string{"email.send"}, 10, time.Minute)
+	assert.Empty(s.T(), jobs)
+}
+
+func (s *JobRepositoryTestSuite) TestClaim_CrashedWorker() {
+	s.enqueue(newJob("email.send", time.Now()))
+	s.worker.Claim([]string{"email.send"}, 10, time.Minute)
+
+	s.db.Exec("UPDATE jobs SET locked_until = ?", time.Now().Add(-time.Second))
+	jobs, err := s.worker.Claim([]string{"email.send"}, 10, time.Minute)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), jobs, 1)
+	assert.Equal(s.T(), 2, jobs[0].Attempts)
+}
+
+func (s *JobRepositoryTestSuite) TestClaim_CrashedOnLastAttempt() {
+	job := s.enqueue(newJob("email.send", time.Now()))
+	s.worker.Claim([]string{"email.send"}, 10, time.Minute)
+	s.db.Exec("UPDATE jobs SET attempts = 3, locked_until = ?", time.Now().Add(-time.Second))
+
+	jobs, err := s.worker.Claim([]string{"email.send"}, 10, time.Minute)
+
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), jobs)
+	stored, _ := s.worker.GetByID(job.ID)
+	assert.Equal(s.T(), model.JobFailed, stored.Status)
+	assert.Equal(s.T(), errJobTimedOut, stored.LastError)
+	assert.NotNil(s.T(), stored.FinishedAt)
+}
+
+func (s *JobRepositoryTestSuite) TestFinish_Reclaimed() {
+	s.enqueue(newJob("email.send", time.Now()))
+	jobs, _ := s.worker.Claim([]string{"email.send"}, 10, time.Minute)
+	stale := jobs[0]
+	s.db.Exec("UPDATE jobs SET locked_until = ?", time.Now().Add(-time.Second))
+	s.worker.Claim([]string{"email.send"}, 10, time.Minute)
+
+	// The first worker's outcome is dropped
+	stale.Status = model.JobSucceeded
+	finished, err := s.worker.Finish(stale)
+
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), finished)
+	stored, _ := s.worker.GetByID(stale.ID)
+	assert.Equal(s.T(), model.JobRunning, stored.Status)
+}
+
+func (s *JobRepositoryTestSuite) TestRetry() {
+	job := s.enqueue(newJob("email.send", time.Now()))
+	s.db.Exec("UPDATE jobs SET status = ?, attempts = 3, finished_at = ?", model.JobFailed, time.Now())
+
+	retried, err := s.repo.Retry(job.ID)
+
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), retried)
+	stored, _ := s.repo.GetByID(job.ID)
+	assert.Equal(s.T(), model.JobPending, stored.Status)
+	assert.Zero(s.T(), stored.Attempts)
+	assert.Nil(s.T(), stored.FinishedAt)
+}
+
+func (s *JobRepositoryTestSuite) TestRetry_UniqueKeyTaken() {
+	job := s.enqueue(newJobWithKey("email.send", "welcome:3"))
+	s.db.Exec("UPDATE jobs SET status = ?, finished_at = ?", model.JobFailed, time.Now())
+	s.enqueue(newJobWithKey("email.send", "welcome:3"))
+
+	retried, err := s.repo.Retry(job.ID)
+
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), retried)
+}
+
+func (s *JobRepositoryTestSuite) TestCancel() {
+	job := s.enqueue(newJob("email.send", time.Now()))
+
+	cancelled, err := s.repo.Cancel(job.ID)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), cancelled)
+
+	jobs, _ := s.worker.Claim([]string{"email.send"}, 10, time.Minute)
+	assert.Empty(s.T(), jobs)
+
+	// Only pending jobs can be cancelled
+	cancelled, err = s.repo.Cancel(job.ID)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), cancelled)
+}
+
+func (s *JobRepositoryTestSuite) TestDeleteFinished() {
+	old := s.enqueue(newJob("email.send", time.Now()))
+	s.enqueue(newJob("email.send", time.Now()))
+	recent := s.enqueue(newJob("email.send", time.Now()))
+	s.db.Exec("UPDATE jobs SET finished_at = ? WHERE id = ?", time.Now().Add(-48*time.Hour), old.ID)
+	s.db.Exec("UPDATE jobs SET finished_at = ? WHERE id = ?", time.Now(), recent.ID)
+
+	deleted, err := s.worker.DeleteFinished(time.Now().Add(-24 * time.Hour))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(1), deleted)
+	jobs, _ := s.worker.List(nil, 10, 0)
+	assert.Len(s.T(), jobs, 2)
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=LeaseRepository --output=./mocks/repository --outpkg=repository --filename=lease_repository.go --structname=MockLeaseRepository --with-expecter=false
+type LeaseRepository interface {
+	WithContext(ctx context.Context) LeaseRepository
+	Acquire(name, holder string, ttl time.Duration) (bool, error)
+	Release(name, holder string) error
+}
+
+type leaseRepository struct {
+	db *gorm.DB
+}
+
+func NewLeaseRepository(db *gorm.DB) LeaseRepository {
+	return &leaseRepository{db: db}
+}
+
+func (r *leaseRepository) WithContext(ctx context.Context) LeaseRepository {
+	return &leaseRepository{db: withTransaction(r.db, ctx)}
+}
+
+// Acquire takes the lease for holder until ttl from now, or extends it if
+// holder has it already. It reports false if another holder has a lease
+// that has not expired.
+func (r *leaseRepository) Acquire(name, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	lease := &model.Lease{Name: name, Holder: holder, ExpiresAt: now.Add(ttl)}
+
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"holder", "expires_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "leases.holder = excluded.holder OR leases.expires_at < ?", Vars: []interface{}{now}},
+		}},
+	}).Create(lease)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Release gives up the lease if holder has it.
+func (r *leaseRepository) Release(name, holder string) error {
+	return r.db.Where("name = ? AND holder = ?", name, holder).Delete(&model.Lease{}).Error
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type LeaseRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo LeaseRepository
+}
+
+func (s *LeaseRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.Lease{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.repo = NewLeaseRepository(s.db).WithContext(context.Background())
+}
+
+func (s *LeaseRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *LeaseRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM leases")
+}
+
+func TestLeaseRepositorySuite(t *testing.T) {
+	suite.Run(t, new(LeaseRepositoryTestSuite))
+}
+
+func (s *LeaseRepositoryTestSuite) TestAcquire() {
+	acquired, err := s.repo.Acquire("scheduler", "a", time.Minute)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), acquired)
+
+	// Held by a until it expires
+	acquired, err = s.repo.Acquire("scheduler", "b", time.Minute)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), acquired)
+
+	// Renewed by its holder
+	acquired, err = s.repo.Acquire("scheduler", "a", time.Minute)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), acquired)
+}
+
+func (s *LeaseRepositoryTestSuite) TestAcquire_Expired() {
+	s.repo.Acquire("scheduler", "a", -time.Second)
+
+	acquired, err := s.repo.Acquire("scheduler", "b", time.Minute)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), acquired)
+
+	var lease model.Lease
+	s.db.First(&lease, "name = ?", "scheduler")
+	assert.Equal(s.T(), "b", lease.Holder)
+}
+
+func (s *LeaseRepositoryTestSuite) TestRelease() {
+	s.repo.Acquire("scheduler", "a", time.Minute)
+
+	// Only the holder can release it
+	assert.NoError(s.T(), s.repo.Release("scheduler", "b"))
+	acquired, _ := s.repo.Acquire("scheduler", "b", time.Minute)
+	assert.False(s.T(), acquired)
+
+	assert.NoError(s.T(), s.repo.Release("scheduler", "a"))
+	acquired, _ = s.repo.Acquire("scheduler", "b", time.Minute)
+	assert.True(s.T(), acquired)
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=LoginAttemptRepository --output=./mocks/repository --outpkg=repository --filename=login_attempt_repository.go --structname=MockLoginAttemptRepository --with-expecter=false
+type LoginAttemptRepository interface {
+	Create(attempt *model.LoginAttempt) error
+	ListByUser(userID uint, limit, offset int) ([]*model.LoginAttempt, error)
+}
+
+type loginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) LoginAttemptRepository {
+	return &loginAttemptRepository{db: db}
+}
+
+func (r *loginAttemptRepository) Create(attempt *model.LoginAttempt) error {
+	return r.db.Create(attempt).Error
+}
+
+// ListByUser returns the attempts of the user, newest first.
+func (r *loginAttemptRepository) ListByUser(userID uint, limit, offset int) ([]*model.LoginAttempt, error) {
+	var attempts []*model.LoginAttempt
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&attempts).Error
+	return attempts, err
+}
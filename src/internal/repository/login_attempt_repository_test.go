@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type LoginAttemptRepositoryTestSuite struct {
+	suite.Suite
+	db                     *gorm.DB
+	loginAttemptRepository LoginAttemptRepository
+}
+
+func (s *LoginAttemptRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.LoginAttempt{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.loginAttemptRepository = NewLoginAttemptRepository(s.db)
+}
+
+func (s *LoginAttemptRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *LoginAttemptRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM login_attempts")
+}
+
+func TestLoginAttemptRepositorySuite(t *testing.T) {
+	suite.Run(t, new(LoginAttemptRepositoryTestSuite))
+}
+
+func (s *LoginAttemptRepositoryTestSuite) TestListByUser_NewestFirst() {
+	userID, otherID := uint(1), uint(2)
+	for _, attempt := range []*model.LoginAttempt{
+		{UserID: &userID, Login: "testuser", Reason: "invalid_credentials"},
+		{UserID: &otherID, Login: "other", Success: true},
+		{Login: "nobody", Reason: "invalid_credentials"},
+		{UserID: &userID, Login: "testuser", Success: true},
+	} {
+		assert.NoError(s.T(), s.loginAttemptRepository.Create(attempt))
+	}
+
+	result, err := s.loginAttemptRepository.ListByUser(1, 10, 0)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result, 2)
+	assert.True(s.T(), result[0].Success)
+	assert.Equal(s.T(), "invalid_credentials", result[1].Reason)
+}
+
+func (s *LoginAttemptRepositoryTestSuite) TestListByUser_Pagination() {
+	userID := uint(1)
+	for i := 0; i < 3; i++ {
+		assert.NoError(s.T(), s.loginAttemptRepository.Create(&model.LoginAttempt{UserID: &userID, Login: "testuser"}))
+	}
+
+	result, err := s.loginAttemptRepository.ListByUser(1, 2, 2)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result, 1)
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=LoginThrottleRepository --output=./mocks/repository --outpkg=repository --filename=login_throttle_repository.go --structname=MockLoginThrottleRepository --with-expecter=false
+type LoginThrottleRepository interface {
+	Get(subject string) (*model.LoginThrottle, error)
+	RecordFailure(subject string, at time.Time, windowStart time.Time) (*model.LoginThrottle, error)
+	Lock(subject string, until time.Time) error
+	Reset(subject string) error
+}
+
+type loginThrottleRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginThrottleRepository(db *gorm.DB) LoginThrottleRepository {
+	return &loginThrottleRepository{db: db}
+}
+
+func (r *loginThrottleRepository) Get(subject string) (*model.LoginThrottle, error) {
+	var throttle model.LoginThrottle
+	err := r.db.Where(&model.LoginThrottle{Subject: subject}).First(&throttle).Error
+	if err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// RecordFailure atomically counts a failed login of the subject and returns
+// the new state. Failures older than windowStart are forgotten, so the count
+// starts over at one.
+func (r *loginThrottleRepository) RecordFailure(subject string, at time.Time, windowStart time.Time) (*model.LoginThrottle, error) {
+	err := r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "subject"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"failures":        gorm.Expr("CASE WHEN login_throttles.last_failure_at < ? THEN 1 ELSE login_throttles.failures + 1 END", windowStart),
+			"last_failure_at": at,
+			"updated_at":      at,
+		}),
+	}).Create(&model.LoginThrottle{
+		Subject:       subject,
+		Failures:      1,
+		LastFailureAt: at,
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+	return r.Get(subject)
+}
+
+// Lock blocks the subject until the given time. The failure count starts
+// over so that the first attempts after the lockout are not delayed.
+func (r *loginThrottleRepository) Lock(subject string, until time.Time) error {
+	return r.db.Model(&model.LoginThrottle{}).
+		Where(&model.LoginThrottle{Subject: subject}).
+		Updates(map[string]interface{}{
+			"locked_until": until,
+			"failures":     0,
+		}).Error
+}
+
+func (r *loginThrottleRepository) Reset(subject string) error {
+	return r.db.Where(&model.LoginThrottle{Subject: subject}).Delete(&model.LoginThrottle{}).Error
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type LoginThrottleRepositoryTestSuite struct {
+	suite.Suite
+	db                      *gorm.DB
+	loginThrottleRepository LoginThrottleRepository
+}
+
+func (s *LoginThrottleRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.LoginThrottle{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.loginThrottleRepository = NewLoginThrottleRepository(s.db)
+}
+
+func (s *LoginThrottleRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *LoginThrottleRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM login_throttles")
+}
+
+func TestLoginThrottleRepositorySuite(t *testing.T) {
+	suite.Run(t, new(LoginThrottleRepositoryTestSuite))
+}
+
+func (s *LoginThrottleRepositoryTestSuite) TestRecordFailure_Counts() {
+	now := time.Now()
+
+	first, err := s.loginThrottleRepository.RecordFailure("user:1", now, now.Add(-time.Hour))
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, first.Failures)
+
+	second, err := s.loginThrottleRepository.RecordFailure("user:1", now.Add(time.Second), now.Add(-time.Hour))
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 2, second.Failures)
+	assert.WithinDuration(s.T(), now.Add(time.Second), second.LastFailureAt, time.Millisecond)
+
+	other, err := s.loginThrottleRepository.RecordFailure("ip:192.0.2.1", now, now.Add(-time.Hour))
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, other.Failures)
+}
+
+func (s *LoginThrottleRepositoryTestSuite) TestRecordFailure_ForgetsOldFailures() {
+	old := time.Now().Add(-time.Hour)
+	_, _ = s.loginThrottleRepository.RecordFailure("user:1", old, old.Add(-time.Hour))
+	_, _ = s.loginThrottleRepository.RecordFailure("user:1", old, old.Add(-time.Hour))
+
+	now := time.Now()
+	result, err := s.loginThrottleRepository.RecordFailure("user:1", now, now.Add(-15*time.Minute))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, result.Failures)
+}
+
+func (s *LoginThrottleRepositoryTestSuite) TestLock_ClearsFailures() {
+	now := time.Now()
+	_, _ = s.loginThrottleRepository.RecordFailure("user:1", now, now.Add(-time.Hour))
+
+	err := s.loginThrottleRepository.Lock("user:1", now.Add(15*time.Minute))
+	assert.NoError(s.T(), err)
+
+	result, err := s.loginThrottleRepository.Get("user:1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 0, result.Failures)
+	if assert.NotNil(s.T(), result.LockedUntil) {
+		assert.WithinDuration(s.T(), now.Add(15*time.Minute), *result.LockedUntil, time.Millisecond)
+	}
+}
+
+func (s *LoginThrottleRepositoryTestSuite) TestReset() {
+	now := time.Now()
+	_, _ = s.loginThrottleRepository.RecordFailure("user:1", now, now.Add(-time.Hour))
+
+	err := s.loginThrottleRepository.Reset("user:1")
+	assert.NoError(s.T(), err)
+
+	_, err = s.loginThrottleRepository.Get("user:1")
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=MagicLinkRepository --output=./mocks/repository --outpkg=repository --filename=magic_link_repository.go --structname=MockMagicLinkRepository --with-expecter=false
+type MagicLinkRepository interface {
+	Create(token *model.MagicLinkToken) error
+	GetByTokenHash(tokenHash string) (*model.MagicLinkToken, error)
+	CountCreatedSince(email string, since time.Time) (int64, error)
+	MarkUsed(id uint, usedAt time.Time) (bool, error)
+	InvalidateForEmail(email string, usedAt time.Time) error
+}
+
+type magicLinkRepository struct {
+	db *gorm.DB
+}
+
+func NewMagicLinkRepository(db *gorm.DB) MagicLinkRepository {
+	return &magicLinkRepository{db: db}
+}
+
+func (r *magicLinkRepository) Create(token *model.MagicLinkToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *magicLinkRepository) GetByTokenHash(tokenHash string) (*model.MagicLinkToken, error) {
+	var token model.MagicLinkToken
+	err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// CountCreatedSince counts the tokens issued for the address since the given
+// time, used or not.
+func (r *magicLinkRepository) CountCreatedSince(email string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&model.MagicLinkToken{}).
+		Where("email = ? AND created_at >= ?", email, since).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkUsed consumes the token. It returns false if the token was already
+// used, so that a link cannot log in twice.
+func (r *magicLinkRepository) MarkUsed(id uint, usedAt time.Time) (bool, error) {
+	result := r.db.Model(&model.MagicLinkToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// InvalidateForEmail marks all outstanding tokens for the address as used.
+func (r *magicLinkRepository) InvalidateForEmail(email string, usedAt time.Time) error {
+	return r.db.Model(&model.MagicLinkToken{}).
+		Where("email = ? AND used_at IS NULL", email).
+		Update("used_at", usedAt).Error
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type MagicLinkRepositoryTestSuite struct {
+	suite.Suite
+	db                  *gorm.DB
+	magicLinkRepository MagicLinkRepository
+}
+
+func (s *MagicLinkRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.MagicLinkToken{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.magicLinkRepository = NewMagicLinkRepository(s.db)
+}
+
+func (s *MagicLinkRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *MagicLinkRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM magic_link_tokens")
+}
+
+func TestMagicLinkRepositorySuite(t *testing.T) {
+	suite.Run(t, new(MagicLinkRepositoryTestSuite))
+}
+
+func (s *MagicLinkRepositoryTestSuite) TestCreateAndGetByTokenHash() {
+	token := &model.MagicLinkToken{
+		Email:     "test@example.com",
+		TokenHash: "hash-1",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	err := s.magicLinkRepository.Create(token)
+	assert.NoError(s.T(), err)
+	assert.NotZero(s.T(), token.ID)
+
+	result, err := s.magicLinkRepository.GetByTokenHash("hash-1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "test@example.com", result.Email)
+	assert.Nil(s.T(), result.UsedAt)
+}
+
+func (s *MagicLinkRepositoryTestSuite) TestGetByTokenHash_NotFound() {
+	result, err := s.magicLinkRepository.GetByTokenHash("missing")
+
+	assert.Equal(s.T(), gorm.ErrRecordNotFound, err)
+	assert.Nil(s.T(), result)
+}
+
+func (s *MagicLinkRepositoryTestSuite) TestCountCreatedSince() {
+	now := time.Now()
+	s.magicLinkRepository.Create(&model.MagicLinkToken{Email: "test@example.com", TokenHash: "hash-1", ExpiresAt: now, CreatedAt: now.Add(-time.Hour)})
+	s.magicLinkRepository.Create(&model.MagicLinkToken{Email: "test@example.com", TokenHash: "hash-2", ExpiresAt: now, CreatedAt: now.Add(-time.Minute)})
+	s.magicLinkRepository.Create(&model.MagicLinkToken{Email: "test@example.com", TokenHash: "hash-3", ExpiresAt: now, CreatedAt: now})
+	s.magicLinkRepository.Create(&model.MagicLinkToken{Email: "other@example.com", TokenHash: "hash-4", ExpiresAt: now, CreatedAt: now})
+
+	count, err := s.magicLinkRepository.CountCreatedSince("test@example.com", now.Add(-15*time.Minute))
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(2), count)
+}
+
+func (s *MagicLinkRepositoryTestSuite) TestMarkUsed_OnlyOnce() {
+	token := &model.MagicLinkToken{Email: "test@example.com", TokenHash: "hash-1", ExpiresAt: time.Now().Add(time.Hour)}
+	s.magicLinkRepository.Create(token)
+
+	used, err := s.magicLinkRepository.MarkUsed(token.ID, time.Now())
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), used)
+
+	used, err = s.magicLinkRepository.MarkUsed(token.ID, time.Now())
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), used)
+}
+
+func (s *MagicLinkRepositoryTestSuite) TestInvalidateForEmail() {
+	first := &model.MagicLinkToken{Email: "test@example.com", TokenHash: "hash-1", ExpiresAt: time.Now().Add(time.Hour)}
+	other := &model.MagicLinkToken{Email: "other@example.com", TokenHash: "hash-2", ExpiresAt: time.Now().Add(time.Hour)}
+	s.magicLinkRepository.Create(first)
+	s.magicLinkRepository.Create(other)
+
+	err := s.magicLinkRepository.InvalidateForEmail("test@example.com", time.Now())
+	assert.NoError(s.T(), err)
+
+	result, _ := s.magicLinkRepository.GetByTokenHash("hash-1")
+	assert.NotNil(s.T(), result.UsedAt)
+
+	result, _ = s.magicLinkRepository.GetByTokenHash("hash-2")
+	assert.Nil(s.T(), result.UsedAt)
+}
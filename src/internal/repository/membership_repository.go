@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/weeranieb/go-kit-base/src/internal/audit"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
+	"gorm.io/gorm"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=MembershipRepository --output=./mocks/repository --outpkg=repository --filename=membership_repository.go --structname=MockMembershipRepository --with-expecter=false
+type MembershipRepository interface {
+	// WithContext returns a repository bound to ctx. All methods but
+	// ListByUser work on the members of the organization of ctx and return
+	// tenant.ErrNoOrganization without one. ListByUser spans organizations
+	// and needs a tenant.Unscoped ctx.
+	WithContext(ctx context.Context) MembershipRepository
+	Create(membership *model.Membership) error
+	Get(userID uint) (*model.Membership, error)
+	List() ([]*model.Membership, error)
+	ListByUser(userID uint) ([]*model.Membership, error)
+	UpdateRole(userID uint, role string) (bool, error)
+	Delete(userID uint) (bool, error)
+	CountByRole(role string) (int64, error)
+}
+
+type membershipRepository struct {
+	db *gorm.DB
+}
+
+func NewMembershipRepository(db *gorm.DB) MembershipRepository {
+	return &membershipRepository{db: db}
+}
+
+func (r *membershipRepository) WithContext(ctx context.Context) MembershipRepository {
+	return &membershipRepository{db: withTransaction(r.db, ctx)}
+}
+
+func (r *membershipRepository) Create(membership *model.Membership) error {
+	if err := requireOrganization(r.db); err != nil {
+		return err
+	}
+	return r.db.Create(membership).Error
+}
+
+func (r *membershipRepository) Get(userID uint) (*model.Membership, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return nil, err
+	}
+
+	var membership model.Membership
+	err := r.db.Where("user_id = ?", userID).First(&membership).Error
+	if err != nil {
+		return nil, err
+	}
+	return &membership, nil
+}
+
+// List returns the members of the organization, oldest first.
+func (r *membershipRepository) List() ([]*model.Membership, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return nil, err
+	}
+
+	var memberships []*model.Membership
+	err := r.db.Order("id").Find(&memberships).Error
+	return memberships, err
+}
+
+// ListByUser returns the memberships of the user in every organization,
+// with the organization loaded.
+func (r *membershipRepository) ListByUser(userID uint) ([]*model.Membership, error) {
+	var memberships []*model.Membership
+	err := r.db.Preload("Organization").Where("user_id = ?", userID).Order("id").Find(&memberships).Error
+	return memberships, err
+}
+
+// UpdateRole changes the role of a member and records the change in the
+// audit log. It reports false if the user is not a member.
+func (r *membershipRepository) UpdateRole(userID uint, role string) (bool, error) {
+	organizationID, ok := tenant.OrganizationFromContext(r.db.Statement.Context)
+	if !ok {
+		return false, tenant.ErrNoOrganization
+	}
+
+	updated := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var membership model.Membership
+		result := tx.Where("user_id = ?", userID).Limit(1).Find(&membership)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		previous := membership.Role
+		result = tx.Model(&membership).Update("role", role)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		updated = true
+
+		if previous == role {
+			return nil
+		}
+		changes := audit.Diff(map[string]interface{}{"role": previous}, map[string]interface{}{"role": role})
+		event := audit.NewEvent(tx.Statement.Context, model.AuditMembershipRoleChanged, model.AuditTargetUser, userID, changes)
+		return appendAuditEvent(tx, organizationID, event)
+	})
+	return updated, err
+}
+
+// Delete removes a member and takes them out of the groups of the
+// organization. It reports false if the user is not a member.
+func (r *membershipRepository) Delete(userID uint) (bool, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return false, err
+	}
+
+	deleted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("user_id = ?", userID).Delete(&model.Membership{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		deleted = true
+		return tx.Where("user_id = ?", userID).Delete(&model.GroupMember{}).Error
+	})
+	return deleted, err
+}
+
+func (r *membershipRepository) CountByRole(role string) (int64, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err := r.db.Model(&model.Membership{}).Where("role = ?", role).Count(&count).Error
+	return count, err
+}
+
+// requireOrganization keeps a repository that is not bound to an
+// organization from touching the rows of all of them.
+func requireOrganization(db *gorm.DB) error {
+	if _, ok := tenant.OrganizationFromContext(db.Statement.Context); !ok {
+		return tenant.ErrNoOrganization
+	}
+	return nil
+}
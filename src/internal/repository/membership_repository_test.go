@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type MembershipRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	acme MembershipRepository
+	// globex works on the members of the other organization
+	globex MembershipRepository
+	orgs   []*model.Organization
+}
+
+func (s *MembershipRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+	if err := s.db.Use(tenant.Plugin{}); err != nil {
+		s.T().Fatal("Failed to register tenant plugin:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.Organization{}, &model.Membership{}, &model.GroupMember{}, &model.AuditEvent{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.orgs = []*model.Organization{{Name: "Acme", Slug: "acme"}, {Name: "Globex", Slug: "globex"}}
+	s.db.Create(s.orgs)
+
+	repo := NewMembershipRepository(s.db)
+	s.acme = repo.WithContext(tenant.WithOrganization(context.Background(), s.orgs[0].ID))
+	s.globex = repo.WithContext(tenant.WithOrganization(context.Background(), s.orgs[1].ID))
+}
+
+func (s *MembershipRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *MembershipRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM memberships")
+	s.db.Exec("DELETE FROM group_members")
+
+	s.acme.Create(&model.Membership{UserID: 1, Role: model.OrganizationRoleOwner})
+	s.acme.Create(&model.Membership{UserID: 2, Role: model.OrganizationRoleMember})
+	s.globex.Create(&model.Membership{UserID: 2, Role: model.OrganizationRoleOwner})
+}
+
+func TestMembershipRepositorySuite(t *testing.T) {
+	suite.Run(t, new(MembershipRepositoryTestSuite))
+}
+
+func (s *MembershipRepositoryTestSuite) TestCreate_Duplicate() {
+	err := s.acme.Create(&model.Membership{UserID: 1, Role: model.OrganizationRoleMember})
+
+	assert.Error(s.T(), err)
+}
+
+func (s *MembershipRepositoryTestSuite) TestGet() {
+	membership, err := s.globex.Get(2)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.OrganizationRoleOwner, membership.Role)
+
+	_, err = s.globex.Get(1)
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *MembershipRepositoryTestSuite) TestList() {
+	memberships, err := s.acme.List()
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), memberships, 2)
+	for _, membership := range memberships {
+		assert.Equal(s.T(), s.orgs[0].ID, membership.OrganizationID)
+	}
+}
+
+func (s *MembershipRepositoryTestSuite) TestListByUser() {
+	memberships, err := NewMembershipRepository(s.db).WithContext(tenant.Unscoped(context.Background())).ListByUser(2)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), memberships, 2)
+	assert.Equal(s.T(), "acme", memberships[0].Organization.Slug)
+	assert.Equal(s.T(), "globex", memberships[1].Organization.Slug)
+}
+
+func (s *MembershipRepositoryTestSuite) TestUpdateRole() {
+	updated, err := s.globex.UpdateRole(1, model.OrganizationRoleAdmin)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), updated)
+
+	updated, err = s.acme.UpdateRole(2, model.OrganizationRoleAdmin)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), updated)
+
+	membership, _ := s.globex.Get(2)
+	assert.Equal(s.T(), model.OrganizationRoleOwner, membership.Role)
+}
+
+func (s *MembershipRepositoryTestSuite) TestDelete() {
+	deleted, err := s.globex.Delete(1)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), deleted)
+
+	deleted, err = s.acme.Delete(2)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), deleted)
+
+	_, err = s.globex.Get(2)
+	assert.NoError(s.T(), err)
+}
+
+func (s *MembershipRepositoryTestSuite) TestDelete_LeavesGroups() {
+	unscoped := s.db.WithContext(tenant.Unscoped(context.Background()))
+	unscoped.Create([]*model.GroupMember{
+		{OrganizationID: s.orgs[0].ID, GroupID: 1, UserID: 2},
+		{OrganizationID: s.orgs[1].ID, GroupID: 2, UserID: 2},
+	})
+
+	deleted, err := s.acme.Delete(2)
+
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), deleted)
+	var groupMembers []*model.GroupMember
+	unscoped.Find(&groupMembers)
+	assert.Len(s.T(), groupMembers, 1)
+	assert.Equal(s.T(), s.orgs[1].ID, groupMembers[0].OrganizationID)
+}
+
+func (s *MembershipRepositoryTestSuite) TestCountByRole() {
+	count, err := s.acme.CountByRole(model.OrganizationRoleOwner)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(1), count)
+}
+
+func (s *MembershipRepositoryTestSuite) TestNoOrganization() {
+	unbound := NewMembershipRepository(s.db)
+
+	_, err := unbound.List()
+	assert.ErrorIs(s.T(), err, tenant.ErrNoOrganization)
+	_, err = unbound.Delete(2)
+	assert.ErrorIs(s.T(), err, tenant.ErrNoOrganization)
+	_, err = unbound.UpdateRole(2, model.OrganizationRoleOwner)
+	assert.ErrorIs(s.T(), err, tenant.ErrNoOrganization)
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=MFARepository --output=./mocks/repository --outpkg=repository --filename=mfa_repository.go --structname=MockMFARepository --with-expecter=false
+type MFARepository interface {
+	GetByUserID(userID uint) (*model.MFACredential, error)
+	Replace(credential *model.MFACredential) error
+	Confirm(id uint, confirmedAt time.Time, step int64) error
+	MarkStepUsed(id uint, step int64) (bool, error)
+	DeleteForUser(userID uint) error
+	ReplaceRecoveryCodes(userID uint, codeHashes []string) error
+	ConsumeRecoveryCode(userID uint, codeHash string, usedAt time.Time) (bool, error)
+}
+
+type mfaRepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) MFARepository {
+	return &mfaRepository{db: db}
+}
+
+func (r *mfaRepository) GetByUserID(userID uint) (*model.MFACredential, error) {
+	var credential model.MFACredential
+	err := r.db.Where("user_id = ?", userID).First(&credential).Error
+	if err != nil {
+		return nil, err
+	}
+	return &credential, nil
+}
+
+// Replace stores a new credential in place of any existing one of the user.
+func (r *mfaRepository) Replace(credential *model.MFACredential) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", credential.UserID).Delete(&model.MFACredential{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(credential).Error
+	})
+}
+
+// Confirm activates the credential and records the step of the code that
+// confirmed it.
+func (r *mfaRepository) Confirm(id uint, confirmedAt time.Time, step int64) error {
+	return r.db.Model(&model.MFACredential{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"confirmed_at":   confirmedAt,
+			"last_used_step": step,
+		}).Error
+}
+
+// MarkStepUsed records that a code of the given step was accepted. It returns
+// false if that step or a later one was already used, so that a code cannot
+// be replayed.
+func (r *mfaRepository) MarkStepUsed(id uint, step int64) (bool, error) {
+	result := r.db.Model(&model.MFACredential{}).
+		Where("id = ? AND last_used_step < ?", id, step).
+		Update("last_used_step", step)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// DeleteForUser removes the credential and recovery codes of the user.
+func (r *mfaRepository) DeleteForUser(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&model.MFACredential{}).Error
+	})
+}
+
+// ReplaceRecoveryCodes drops all recovery codes of the user and stores the
+// given hashes instead.
+func (r *mfaRepository) ReplaceRecoveryCodes(userID uint, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&model.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		codes := make([]*model.MFARecoveryCode, 0, len(codeHashes))
+		for _, hash := range codeHashes {
+			codes = append(codes, &model.MFARecoveryCode{UserID: userID, CodeHash: hash})
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// ConsumeRecoveryCode marks an unused recovery code of the user as used. It
+// returns false if there is no such code.
+func (r *mfaRepository) ConsumeRecoveryCode(userID uint, codeHash string, usedAt time.Time) (bool, error) {
+	result := r.db.Model(&model.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
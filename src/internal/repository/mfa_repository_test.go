@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type MFARepositoryTestSuite struct {
+	suite.Suite
+	db            *gorm.DB
+	mfaRepository MFARepository
+}
+
+func (s *MFARepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.MFACredential{}, &model.MFARecoveryCode{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.mfaRepository = NewMFARepository(s.db)
+}
+
+func (s *MFARepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *MFARepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM mfa_credentials")
+	s.db.Exec("DELETE FROM mfa_recovery_codes")
+}
+
+func TestMFARepositorySuite(t *testing.T) {
+	suite.Run(t, new(MFARepositoryTestSuite))
+}
+
+func (s *MFARepositoryTestSuite) TestReplaceAndGetByUserID() {
+	err := s.mfaRepository.Replace(&model.MFACredential{UserID: 1, SecretEncrypted: "first"})
+	assert.NoError(s.T(), err)
+
+	err = s.mfaRepository.Replace(&model.MFACredential{UserID: 1, SecretEncrypted: "second"})
+	assert.NoError(s.T(), err)
+
+	result, err := s.mfaRepository.GetByUserID(1)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "second", result.SecretEncrypted)
+	assert.False(s.T(), result.IsConfirmed())
+
+	var count int64
+	s.db.Model(&model.MFACredential{}).Count(&count)
+	assert.Equal(s.T(), int64(1), count)
+}
+
+func (s *MFARepositoryTestSuite) TestGetByUserID_NotFound() {
+	result, err := s.mfaRepository.GetByUserID(1)
+
+	assert.Equal(s.T(), gorm.ErrRecordNotFound, err)
+	assert.Nil(s.T(), result)
+}
+
+func (s *MFARepositoryTestSuite) TestConfirm() {
+	credential := &model.MFACredential{UserID: 1, SecretEncrypted: "secret"}
+	s.mfaRepository.Replace(credential)
+
+	err := s.mfaRepository.Confirm(credential.ID, time.Now(), 100)
+
+	assert.NoError(s.T(), err)
+	result, _ := s.mfaRepository.GetByUserID(1)
+	assert.True(s.T(), result.IsConfirmed())
+	assert.Equal(s.T(), int64(100), result.LastUsedStep)
+}
+
+func (s *MFARepositoryTestSuite) TestMarkStepUsed_RejectsReplay() {
+	credential := &model.MFACredential{UserID: 1, SecretEncrypted: "secret", LastUsedStep: 100}
+	s.mfaRepository.Replace(credential)
+
+	used, err := s.mfaRepository.MarkStepUsed(credential.ID, 101)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), used)
+
+	used, err = s.mfaRepository.MarkStepUsed(credential.ID, 101)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), used)
+
+	used, err = s.mfaRepository.MarkStepUsed(credential.ID, 100)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), used)
+}
+
+func (s *MFARepositoryTestSuite) TestRecoveryCodes() {
+	err := s.mfaRepository.ReplaceRecoveryCodes(1, []string{"hash-1", "hash-2"})
+	assert.NoError(s.T(), err)
+
+	used, err := s.mfaRepository.ConsumeRecoveryCode(1, "hash-1", time.Now())
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), used)
+
+	// Single use
+	used, _ = s.mfaRepository.ConsumeRecoveryCode(1, "hash-1", time.Now())
+	assert.False(s.T(), used)
+
+	// Bound to the user
+	used, _ = s.mfaRepository.ConsumeRecoveryCode(2, "hash-2", time.Now())
+	assert.False(s.T(), used)
+
+	// Replacing drops the old codes
+	err = s.mfaRepository.ReplaceRecoveryCodes(1, []string{"hash-3"})
+	assert.NoError(s.T(), err)
+	used, _ = s.mfaRepository.ConsumeRecoveryCode(1, "hash-2", time.Now())
+	assert.False(s.T(), used)
+	used, _ = s.mfaRepository.ConsumeRecoveryCode(1, "hash-3", time.Now())
+	assert.True(s.T(), used)
+}
+
+func (s *MFARepositoryTestSuite) TestDeleteForUser() {
+	s.mfaRepository.Replace(&model.MFACredential{UserID: 1, SecretEncrypted: "secret"})
+	s.mfaRepository.ReplaceRecoveryCodes(1, []string{"hash-1"})
+	s.mfaRepository.Replace(&model.MFACredential{UserID: 2, SecretEncrypted: "other"})
+
+	err := s.mfaRepository.DeleteForUser(1)
+
+	assert.NoError(s.T(), err)
+	_, err = s.mfaRepository.GetByUserID(1)
+	assert.Equal(s.T(), gorm.ErrRecordNotFound, err)
+	used, _ := s.mfaRepository.ConsumeRecoveryCode(1, "hash-1", time.Now())
+	assert.False(s.T(), used)
+	_, err = s.mfaRepository.GetByUserID(2)
+	assert.NoError(s.T(), err)
+}
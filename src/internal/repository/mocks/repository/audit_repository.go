@@ -0,0 +1,111 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repository
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	repository "github.com/weeranieb/go-kit-base/src/internal/repository"
+)
+
+// MockAuditRepository is an autogenerated mock type for the AuditRepository type
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+// List provides a mock function with given fields: filter, limit, offset
+func (_m *MockAuditRepository) List(filter *model.AuditFilter, limit int, offset int) ([]*model.AuditEvent, error) {
+	ret := _m.Called(filter, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*model.AuditEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.AuditFilter, int, int) ([]*model.AuditEvent, error)); ok {
+		return rf(filter, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(*model.AuditFilter, int, int) []*model.AuditEvent); ok {
+		r0 = rf(filter, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.AuditEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.AuditFilter, int, int) error); ok {
+		r1 = rf(filter, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAfter provides a mock function with given fields: filter, afterID, limit
+func (_m *MockAuditRepository) ListAfter(filter *model.AuditFilter, afterID uint, limit int) ([]*model.AuditEvent, error) {
+	ret := _m.Called(filter, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAfter")
+	}
+
+	var r0 []*model.AuditEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.AuditFilter, uint, int) ([]*model.AuditEvent, error)); ok {
+		return rf(filter, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(*model.AuditFilter, uint, int) []*model.AuditEvent); ok {
+		r0 = rf(filter, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.AuditEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.AuditFilter, uint, int) error); ok {
+		r1 = rf(filter, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WithContext provides a mock function with given fields: ctx
+func (_m *MockAuditRepository) WithContext(ctx context.Context) repository.AuditRepository {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 repository.AuditRepository
+	if rf, ok := ret.Get(0).(func(context.Context) repository.AuditRepository); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repository.AuditRepository)
+		}
+	}
+
+	return r0
+}
+
+// NewMockAuditRepository creates a new instance of MockAuditRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAuditRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAuditRepository {
+	mock := &MockAuditRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
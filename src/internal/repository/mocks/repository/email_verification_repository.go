@@ -0,0 +1,153 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repository
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	time "time"
+)
+
+// MockEmailVerificationRepository is an autogenerated mock type for the EmailVerificationRepository type
+type MockEmailVerificationRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: token
+func (_m *MockEmailVerificationRepository) Create(token *model.EmailVerificationToken) error {
+	ret := _m.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.EmailVerificationToken) error); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByTokenHash provides a mock function with given fields: tokenHash
+func (_m *MockEmailVerificationRepository) GetByTokenHash(tokenHash string) (*model.EmailVerificationToken, error) {
+	ret := _m.Called(tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByTokenHash")
+	}
+
+	var r0 *model.EmailVerificationToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.EmailVerificationToken, error)); ok {
+		return rf(tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.EmailVerificationToken); ok {
+		r0 = rf(tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.EmailVerificationToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLatestForUser provides a mock function with given fields: userID
+func (_m *MockEmailVerificationRepository) GetLatestForUser(userID uint) (*model.EmailVerificationToken, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestForUser")
+	}
+
+	var r0 *model.EmailVerificationToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*model.EmailVerificationToken, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *model.EmailVerificationToken); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.EmailVerificationToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InvalidateForUser provides a mock function with given fields: userID, usedAt
+func (_m *MockEmailVerificationRepository) InvalidateForUser(userID uint, usedAt time.Time) error {
+	ret := _m.Called(userID, usedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InvalidateForUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, time.Time) error); ok {
+		r0 = rf(userID, usedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MarkUsed provides a mock function with given fields: id, usedAt
+func (_m *MockEmailVerificationRepository) MarkUsed(id uint, usedAt time.Time) (bool, error) {
+	ret := _m.Called(id, usedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkUsed")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, time.Time) (bool, error)); ok {
+		return rf(id, usedAt)
+	}
+	if rf, ok := ret.Get(0).(func(uint, time.Time) bool); ok {
+		r0 = rf(id, usedAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, time.Time) error); ok {
+		r1 = rf(id, usedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockEmailVerificationRepository creates a new instance of MockEmailVerificationRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockEmailVerificationRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockEmailVerificationRepository {
+	mock := &MockEmailVerificationRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,125 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repository
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	time "time"
+)
+
+// MockExternalIdentityRepository is an autogenerated mock type for the ExternalIdentityRepository type
+type MockExternalIdentityRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: identity
+func (_m *MockExternalIdentityRepository) Create(identity *model.ExternalIdentity) error {
+	ret := _m.Called(identity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.ExternalIdentity) error); ok {
+		r0 = rf(identity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByProviderSubject provides a mock function with given fields: provider, subject
+func (_m *MockExternalIdentityRepository) GetByProviderSubject(provider string, subject string) (*model.ExternalIdentity, error) {
+	ret := _m.Called(provider, subject)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByProviderSubject")
+	}
+
+	var r0 *model.ExternalIdentity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*model.ExternalIdentity, error)); ok {
+		return rf(provider, subject)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *model.ExternalIdentity); ok {
+		r0 = rf(provider, subject)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ExternalIdentity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(provider, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListByUser provides a mock function with given fields: userID
+func (_m *MockExternalIdentityRepository) ListByUser(userID uint) ([]*model.ExternalIdentity, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByUser")
+	}
+
+	var r0 []*model.ExternalIdentity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]*model.ExternalIdentity, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []*model.ExternalIdentity); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.ExternalIdentity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TouchLastLogin provides a mock function with given fields: id, at
+func (_m *MockExternalIdentityRepository) TouchLastLogin(id uint, at time.Time) error {
+	ret := _m.Called(id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TouchLastLogin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, time.Time) error); ok {
+		r0 = rf(id, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockExternalIdentityRepository creates a new instance of MockExternalIdentityRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockExternalIdentityRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockExternalIdentityRepository {
+	mock := &MockExternalIdentityRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
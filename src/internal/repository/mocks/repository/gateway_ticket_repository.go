@@ -0,0 +1,157 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repository
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	repository "github.com/weeranieb/go-kit-base/src/internal/repository"
+
+	time "time"
+)
+
+// MockGatewayTicketRepository is an autogenerated mock type for the GatewayTicketRepository type
+type MockGatewayTicketRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ticket
+func (_m *MockGatewayTicketRepository) Create(ticket *model.GatewayTicket) error {
+	ret := _m.Called(ticket)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.GatewayTicket) error); ok {
+		r0 = rf(ticket)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpired provides a mock function with given fields: before
+func (_m *MockGatewayTicketRepository) DeleteExpired(before time.Time) (int64, error) {
+	ret := _m.Called(before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpired")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int64, error)); ok {
+		return rf(before)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int64); ok {
+		r0 = rf(before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByTokenHash provides a mock function with given fields: tokenHash
+func (_m *MockGatewayTicketRepository) GetByTokenHash(tokenHash string) (*model.GatewayTicket, error) {
+	ret := _m.Called(tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByTokenHash")
+	}
+
+	var r0 *model.GatewayTicket
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.GatewayTicket, error)); ok {
+		return rf(tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.GatewayTicket); ok {
+		r0 = rf(tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.GatewayTicket)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkUsed provides a mock function with given fields: id, usedAt
+func (_m *MockGatewayTicketRepository) MarkUsed(id uint, usedAt time.Time) (bool, error) {
+	ret := _m.Called(id, usedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkUsed")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, time.Time) (bool, error)); ok {
+		return rf(id, usedAt)
+	}
+	if rf, ok := ret.Get(0).(func(uint, time.Time) bool); ok {
+		r0 = rf(id, usedAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, time.Time) error); ok {
+		r1 = rf(id, usedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WithContext provides a mock function with given fields: ctx
+func (_m *MockGatewayTicketRepository) WithContext(ctx context.Context) repository.GatewayTicketRepository {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 repository.GatewayTicketRepository
+	if rf, ok := ret.Get(0).(func(context.Context) repository.GatewayTicketRepository); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repository.GatewayTicketRepository)
+		}
+	}
+
+	return r0
+}
+
+// NewMockGatewayTicketRepository creates a new instance of MockGatewayTicketRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockGatewayTicketRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockGatewayTicketRepository {
+	mock := &MockGatewayTicketRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
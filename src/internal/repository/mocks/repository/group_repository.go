@@ -0,0 +1,379 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repository
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	repository "github.com/weeranieb/go-kit-base/src/internal/repository"
+)
+
+// MockGroupRepository is an autogenerated mock type for the GroupRepository type
+type MockGroupRepository struct {
+	mock.Mock
+}
+
+// AddMembers provides a mock function with given fields: groupID, userIDs
+func (_m *MockGroupRepository) AddMembers(groupID uint, userIDs []uint) (int64, error) {
+	ret := _m.Called(groupID, userIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddMembers")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, []uint) (int64, error)); ok {
+		return rf(groupID, userIDs)
+	}
+	if rf, ok := ret.Get(0).(func(uint, []uint) int64); ok {
+		r0 = rf(groupID, userIDs)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, []uint) error); ok {
+		r1 = rf(groupID, userIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: group
+func (_m *MockGroupRepository) Create(group *model.Group) error {
+	ret := _m.Called(group)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Group) error); ok {
+		r0 = rf(group)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: id
+func (_m *MockGroupRepository) Delete(id uint) (bool, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (bool, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) bool); ok {
+		r0 = rf(id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: id
+func (_m *MockGroupRepository) GetByID(id uint) (*model.Group, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *model.Group
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*model.Group, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *model.Group); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Group)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByName provides a mock function with given fields: name
+func (_m *MockGroupRepository) GetByName(name string) (*model.Group, error) {
+	ret := _m.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByName")
+	}
+
+	var r0 *model.Group
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.Group, error)); ok {
+		return rf(name)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.Group); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Group)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with no fields
+func (_m *MockGroupRepository) List() ([]*model.Group, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*model.Group
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*model.Group, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*model.Group); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Group)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListByUser provides a mock function with given fields: userID
+func (_m *MockGroupRepository) ListByUser(userID uint) ([]*model.Group, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByUser")
+	}
+
+	var r0 []*model.Group
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]*model.Group, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []*model.Group); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Group)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListMembers provides a mock function with given fields: groupID
+func (_m *MockGroupRepository) ListMembers(groupID uint) ([]*model.GroupMember, error) {
+	ret := _m.Called(groupID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListMembers")
+	}
+
+	var r0 []*model.GroupMember
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]*model.GroupMember, error)); ok {
+		return rf(groupID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []*model.GroupMember); ok {
+		r0 = rf(groupID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.GroupMember)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(groupID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPermissions provides a mock function with given fields: groupIDs
+func (_m *MockGroupRepository) ListPermissions(groupIDs []uint) ([]*model.GroupPermission, error) {
+	ret := _m.Called(groupIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPermissions")
+	}
+
+	var r0 []*model.GroupPermission
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]uint) ([]*model.GroupPermission, error)); ok {
+		return rf(groupIDs)
+	}
+	if rf, ok := ret.Get(0).(func([]uint) []*model.GroupPermission); ok {
+		r0 = rf(groupIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.GroupPermission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]uint) error); ok {
+		r1 = rf(groupIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveMembers provides a mock function with given fields: groupID, userIDs
+func (_m *MockGroupRepository) RemoveMembers(groupID uint, userIDs []uint) (int64, error) {
+	ret := _m.Called(groupID, userIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveMembers")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, []uint) (int64, error)); ok {
+		return rf(groupID, userIDs)
+	}
+	if rf, ok := ret.Get(0).(func(uint, []uint) int64); ok {
+		r0 = rf(groupID, userIDs)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, []uint) error); ok {
+		r1 = rf(groupID, userIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetPermissions provides a mock function with given fields: groupID, permissions
+func (_m *MockGroupRepository) SetPermissions(groupID uint, permissions []string) error {
+	ret := _m.Called(groupID, permissions)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPermissions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, []string) error); ok {
+		r0 = rf(groupID, permissions)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: group
+func (_m *MockGroupRepository) Update(group *model.Group) (bool, error) {
+	ret := _m.Called(group)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.Group) (bool, error)); ok {
+		return rf(group)
+	}
+	if rf, ok := ret.Get(0).(func(*model.Group) bool); ok {
+		r0 = rf(group)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.Group) error); ok {
+		r1 = rf(group)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WithContext provides a mock function with given fields: ctx
+func (_m *MockGroupRepository) WithContext(ctx context.Context) repository.GroupRepository {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 repository.GroupRepository
+	if rf, ok := ret.Get(0).(func(context.Context) repository.GroupRepository); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repository.GroupRepository)
+		}
+	}
+
+	return r0
+}
+
+// NewMockGroupRepository creates a new instance of MockGroupRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockGroupRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockGroupRepository {
+	mock := &MockGroupRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
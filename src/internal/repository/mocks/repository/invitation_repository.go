@@ -0,0 +1,273 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repository
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	repository "github.com/weeranieb/go-kit-base/src/internal/repository"
+
+	time "time"
+)
+
+// MockInvitationRepository is an autogenerated mock type for the InvitationRepository type
+type MockInvitationRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: invitation
+func (_m *MockInvitationRepository) Create(invitation *model.Invitation) error {
+	ret := _m.Called(invitation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Invitation) error); ok {
+		r0 = rf(invitation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByID provides a mock function with given fields: id
+func (_m *MockInvitationRepository) GetByID(id uint) (*model.Invitation, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *model.Invitation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*model.Invitation, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *model.Invitation); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Invitation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByTokenHash provides a mock function with given fields: tokenHash
+func (_m *MockInvitationRepository) GetByTokenHash(tokenHash string) (*model.Invitation, error) {
+	ret := _m.Called(tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByTokenHash")
+	}
+
+	var r0 *model.Invitation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.Invitation, error)); ok {
+		return rf(tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.Invitation); ok {
+		r0 = rf(tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Invitation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HasPending provides a mock function with given fields: email, now
+func (_m *MockInvitationRepository) HasPending(email string, now time.Time) (bool, error) {
+	ret := _m.Called(email, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HasPending")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) (bool, error)); ok {
+		return rf(email, now)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time) bool); ok {
+		r0 = rf(email, now)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time) error); ok {
+		r1 = rf(email, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: limit, offset
+func (_m *MockInvitationRepository) List(limit int, offset int) ([]*model.Invitation, error) {
+	ret := _m.Called(limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*model.Invitation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int, int) ([]*model.Invitation, error)); ok {
+		return rf(limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(int, int) []*model.Invitation); ok {
+		r0 = rf(limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Invitation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int, int) error); ok {
+		r1 = rf(limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkAccepted provides a mock function with given fields: id, tokenHash, acceptedAt
+func (_m *MockInvitationRepository) MarkAccepted(id uint, tokenHash string, acceptedAt time.Time) (bool, error) {
+	ret := _m.Called(id, tokenHash, acceptedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkAccepted")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, string, time.Time) (bool, error)); ok {
+		return rf(id, tokenHash, acceptedAt)
+	}
+	if rf, ok := ret.Get(0).(func(uint, string, time.Time) bool); ok {
+		r0 = rf(id, tokenHash, acceptedAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, string, time.Time) error); ok {
+		r1 = rf(id, tokenHash, acceptedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Renew provides a mock function with given fields: id, tokenHash, expiresAt
+func (_m *MockInvitationRepository) Renew(id uint, tokenHash string, expiresAt time.Time) (bool, error) {
+	ret := _m.Called(id, tokenHash, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Renew")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, string, time.Time) (bool, error)); ok {
+		return rf(id, tokenHash, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(uint, string, time.Time) bool); ok {
+		r0 = rf(id, tokenHash, expiresAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, string, time.Time) error); ok {
+		r1 = rf(id, tokenHash, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revoke provides a mock function with given fields: id, revokedAt
+func (_m *MockInvitationRepository) Revoke(id uint, revokedAt time.Time) (bool, error) {
+	ret := _m.Called(id, revokedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revoke")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, time.Time) (bool, error)); ok {
+		return rf(id, revokedAt)
+	}
+	if rf, ok := ret.Get(0).(func(uint, time.Time) bool); ok {
+		r0 = rf(id, revokedAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, time.Time) error); ok {
+		r1 = rf(id, revokedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WithContext provides a mock function with given fields: ctx
+func (_m *MockInvitationRepository) WithContext(ctx context.Context) repository.InvitationRepository {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 repository.InvitationRepository
+	if rf, ok := ret.Get(0).(func(context.Context) repository.InvitationRepository); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repository.InvitationRepository)
+		}
+	}
+
+	return r0
+}
+
+// NewMockInvitationRepository creates a new instance of MockInvitationRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockInvitationRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockInvitationRepository {
+	mock := &MockInvitationRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,97 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repository
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	repository "github.com/weeranieb/go-kit-base/src/internal/repository"
+
+	time "time"
+)
+
+// MockLeaseRepository is an autogenerated mock type for the LeaseRepository type
+type MockLeaseRepository struct {
+	mock.Mock
+}
+
+// Acquire provides a mock function with given fields: name, holder, ttl
+func (_m *MockLeaseRepository) Acquire(name string, holder string, ttl time.Duration) (bool, error) {
+	ret := _m.Called(name, holder, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Acquire")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, time.Duration) (bool, error)); ok {
+		return rf(name, holder, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, time.Duration) bool); ok {
+		r0 = rf(name, holder, ttl)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, time.Duration) error); ok {
+		r1 = rf(name, holder, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Release provides a mock function with given fields: name, holder
+func (_m *MockLeaseRepository) Release(name string, holder string) error {
+	ret := _m.Called(name, holder)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string) error); ok {
+		r0 = rf(name, holder)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WithContext provides a mock function with given fields: ctx
+func (_m *MockLeaseRepository) WithContext(ctx context.Context) repository.LeaseRepository {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 repository.LeaseRepository
+	if rf, ok := ret.Get(0).(func(context.Context) repository.LeaseRepository); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repository.LeaseRepository)
+		}
+	}
+
+	return r0
+}
+
+// NewMockLeaseRepository creates a new instance of MockLeaseRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockLeaseRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockLeaseRepository {
+	mock := &MockLeaseRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,75 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repository
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// MockLoginAttemptRepository is an autogenerated mock type for the LoginAttemptRepository type
+type MockLoginAttemptRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: attempt
+func (_m *MockLoginAttemptRepository) Create(attempt *model.LoginAttempt) error {
+	ret := _m.Called(attempt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.LoginAttempt) error); ok {
+		r0 = rf(attempt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListByUser provides a mock function with given fields: userID, limit, offset
+func (_m *MockLoginAttemptRepository) ListByUser(userID uint, limit int, offset int) ([]*model.LoginAttempt, error) {
+	ret := _m.Called(userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByUser")
+	}
+
+	var r0 []*model.LoginAttempt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, int, int) ([]*model.LoginAttempt, error)); ok {
+		return rf(userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(uint, int, int) []*model.LoginAttempt); ok {
+		r0 = rf(userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.LoginAttempt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, int, int) error); ok {
+		r1 = rf(userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockLoginAttemptRepository creates a new instance of MockLoginAttemptRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockLoginAttemptRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockLoginAttemptRepository {
+	mock := &MockLoginAttemptRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,125 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repository
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	time "time"
+)
+
+// MockLoginThrottleRepository is an autogenerated mock type for the LoginThrottleRepository type
+type MockLoginThrottleRepository struct {
+	mock.Mock
+}
+
+// Get provides a mock function with given fields: subject
+func (_m *MockLoginThrottleRepository) Get(subject string) (*model.LoginThrottle, error) {
+	ret := _m.Called(subject)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *model.LoginThrottle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.LoginThrottle, error)); ok {
+		return rf(subject)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.LoginThrottle); ok {
+		r0 = rf(subject)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LoginThrottle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Lock provides a mock function with given fields: subject, until
+func (_m *MockLoginThrottleRepository) Lock(subject string, until time.Time) error {
+	ret := _m.Called(subject, until)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Lock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) error); ok {
+		r0 = rf(subject, until)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RecordFailure provides a mock function with given fields: subject, at, windowStart
+func (_m *MockLoginThrottleRepository) RecordFailure(subject string, at time.Time, windowStart time.Time) (*model.LoginThrottle, error) {
+	ret := _m.Called(subject, at, windowStart)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordFailure")
+	}
+
+	var r0 *model.LoginThrottle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) (*model.LoginThrottle, error)); ok {
+		return rf(subject, at, windowStart)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time, time.Time) *model.LoginThrottle); ok {
+		r0 = rf(subject, at, windowStart)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LoginThrottle)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time, time.Time) error); ok {
+		r1 = rf(subject, at, windowStart)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reset provides a mock function with given fields: subject
+func (_m *MockLoginThrottleRepository) Reset(subject string) error {
+	ret := _m.Called(subject)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(subject)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockLoginThrottleRepository creates a new instance of MockLoginThrottleRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockLoginThrottleRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockLoginThrottleRepository {
+	mock := &MockLoginThrottleRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,187 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repository
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	time "time"
+)
+
+// MockMFARepository is an autogenerated mock type for the MFARepository type
+type MockMFARepository struct {
+	mock.Mock
+}
+
+// Confirm provides a mock function with given fields: id, confirmedAt, step
+func (_m *MockMFARepository) Confirm(id uint, confirmedAt time.Time, step int64) error {
+	ret := _m.Called(id, confirmedAt, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Confirm")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, time.Time, int64) error); ok {
+		r0 = rf(id, confirmedAt, step)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConsumeRecoveryCode provides a mock function with given fields: userID, codeHash, usedAt
+func (_m *MockMFARepository) ConsumeRecoveryCode(userID uint, codeHash string, usedAt time.Time) (bool, error) {
+	ret := _m.Called(userID, codeHash, usedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConsumeRecoveryCode")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, string, time.Time) (bool, error)); ok {
+		return rf(userID, codeHash, usedAt)
+	}
+	if rf, ok := ret.Get(0).(func(uint, string, time.Time) bool); ok {
+		r0 = rf(userID, codeHash, usedAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, string, time.Time) error); ok {
+		r1 = rf(userID, codeHash, usedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteForUser provides a mock function with given fields: userID
+func (_m *MockMFARepository) DeleteForUser(userID uint) error {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteForUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByUserID provides a mock function with given fields: userID
+func (_m *MockMFARepository) GetByUserID(userID uint) (*model.MFACredential, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByUserID")
+	}
+
+	var r0 *model.MFACredential
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*model.MFACredential, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *model.MFACredential); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.MFACredential)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkStepUsed provides a mock function with given fields: id, step
+func (_m *MockMFARepository) MarkStepUsed(id uint, step int64) (bool, error) {
+	ret := _m.Called(id, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkStepUsed")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, int64) (bool, error)); ok {
+		return rf(id, step)
+	}
+	if rf, ok := ret.Get(0).(func(uint, int64) bool); ok {
+		r0 = rf(id, step)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, int64) error); ok {
+		r1 = rf(id, step)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Replace provides a mock function with given fields: credential
+func (_m *MockMFARepository) Replace(credential *model.MFACredential) error {
+	ret := _m.Called(credential)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Replace")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.MFACredential) error); ok {
+		r0 = rf(credential)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReplaceRecoveryCodes provides a mock function with given fields: userID, codeHashes
+func (_m *MockMFARepository) ReplaceRecoveryCodes(userID uint, codeHashes []string) error {
+	ret := _m.Called(userID, codeHashes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplaceRecoveryCodes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, []string) error); ok {
+		r0 = rf(userID, codeHashes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockMFARepository creates a new instance of MockMFARepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMFARepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMFARepository {
+	mock := &MockMFARepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
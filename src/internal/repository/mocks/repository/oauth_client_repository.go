@@ -0,0 +1,165 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repository
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	time "time"
+)
+
+// MockOAuthClientRepository is an autogenerated mock type for the OAuthClientRepository type
+type MockOAuthClientRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: client
+func (_m *MockOAuthClientRepository) Create(client *model.OAuthClient) error {
+	ret := _m.Called(client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.OAuthClient) error); ok {
+		r0 = rf(client)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByClientID provides a mock function with given fields: clientID
+func (_m *MockOAuthClientRepository) GetByClientID(clientID string) (*model.OAuthClient, error) {
+	ret := _m.Called(clientID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByClientID")
+	}
+
+	var r0 *model.OAuthClient
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.OAuthClient, error)); ok {
+		return rf(clientID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.OAuthClient); ok {
+		r0 = rf(clientID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OAuthClient)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(clientID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByID provides a mock function with given fields: id
+func (_m *MockOAuthClientRepository) GetByID(id uint) (*model.OAuthClient, error) {
+	ret := _m.Called(id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *model.OAuthClient
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (*model.OAuthClient, error)); ok {
+		return rf(id)
+	}
+	if rf, ok := ret.Get(0).(func(uint) *model.OAuthClient); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OAuthClient)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with no fields
+func (_m *MockOAuthClientRepository) List() ([]*model.OAuthClient, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*model.OAuthClient
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*model.OAuthClient, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*model.OAuthClient); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.OAuthClient)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revoke provides a mock function with given fields: id, revokedAt
+func (_m *MockOAuthClientRepository) Revoke(id uint, revokedAt time.Time) (bool, error) {
+	ret := _m.Called(id, revokedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revoke")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, time.Time) (bool, error)); ok {
+		return rf(id, revokedAt)
+	}
+	if rf, ok := ret.Get(0).(func(uint, time.Time) bool); ok {
+		r0 = rf(id, revokedAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, time.Time) error); ok {
+		r1 = rf(id, revokedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockOAuthClientRepository creates a new instance of MockOAuthClientRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOAuthClientRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOAuthClientRepository {
+	mock := &MockOAuthClientRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,95 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repository
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	time "time"
+)
+
+// MockOAuthCodeRepository is an autogenerated mock type for the OAuthCodeRepository type
+type MockOAuthCodeRepository struct {
+	mock.Mock
+}
+
+// Consume provides a mock function with given fields: codeHash
+func (_m *MockOAuthCodeRepository) Consume(codeHash string) (*model.OAuthAuthorizationCode, error) {
+	ret := _m.Called(codeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Consume")
+	}
+
+	var r0 *model.OAuthAuthorizationCode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.OAuthAuthorizationCode, error)); ok {
+		return rf(codeHash)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.OAuthAuthorizationCode); ok {
+		r0 = rf(codeHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OAuthAuthorizationCode)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(codeHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: code
+func (_m *MockOAuthCodeRepository) Create(code *model.OAuthAuthorizationCode) error {
+	ret := _m.Called(code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.OAuthAuthorizationCode) error); ok {
+		r0 = rf(code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpired provides a mock function with given fields: now
+func (_m *MockOAuthCodeRepository) DeleteExpired(now time.Time) error {
+	ret := _m.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpired")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(time.Time) error); ok {
+		r0 = rf(now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockOAuthCodeRepository creates a new instance of MockOAuthCodeRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOAuthCodeRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOAuthCodeRepository {
+	mock := &MockOAuthCodeRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,133 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repository
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// MockOAuthConsentRepository is an autogenerated mock type for the OAuthConsentRepository type
+type MockOAuthConsentRepository struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: userID, clientID
+func (_m *MockOAuthConsentRepository) Delete(userID uint, clientID uint) (bool, error) {
+	ret := _m.Called(userID, clientID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, uint) (bool, error)); ok {
+		return rf(userID, clientID)
+	}
+	if rf, ok := ret.Get(0).(func(uint, uint) bool); ok {
+		r0 = rf(userID, clientID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, uint) error); ok {
+		r1 = rf(userID, clientID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Get provides a mock function with given fields: userID, clientID
+func (_m *MockOAuthConsentRepository) Get(userID uint, clientID uint) (*model.OAuthConsent, error) {
+	ret := _m.Called(userID, clientID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *model.OAuthConsent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, uint) (*model.OAuthConsent, error)); ok {
+		return rf(userID, clientID)
+	}
+	if rf, ok := ret.Get(0).(func(uint, uint) *model.OAuthConsent); ok {
+		r0 = rf(userID, clientID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OAuthConsent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, uint) error); ok {
+		r1 = rf(userID, clientID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListByUser provides a mock function with given fields: userID
+func (_m *MockOAuthConsentRepository) ListByUser(userID uint) ([]*model.OAuthConsent, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByUser")
+	}
+
+	var r0 []*model.OAuthConsent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]*model.OAuthConsent, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []*model.OAuthConsent); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.OAuthConsent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: consent
+func (_m *MockOAuthConsentRepository) Save(consent *model.OAuthConsent) error {
+	ret := _m.Called(consent)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.OAuthConsent) error); ok {
+		r0 = rf(consent)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockOAuthConsentRepository creates a new instance of MockOAuthConsentRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOAuthConsentRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOAuthConsentRepository {
+	mock := &MockOAuthConsentRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,95 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repository
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	time "time"
+)
+
+// MockOIDCStateRepository is an autogenerated mock type for the OIDCStateRepository type
+type MockOIDCStateRepository struct {
+	mock.Mock
+}
+
+// Consume provides a mock function with given fields: stateHash
+func (_m *MockOIDCStateRepository) Consume(stateHash string) (*model.OIDCLoginState, error) {
+	ret := _m.Called(stateHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Consume")
+	}
+
+	var r0 *model.OIDCLoginState
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.OIDCLoginState, error)); ok {
+		return rf(stateHash)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.OIDCLoginState); ok {
+		r0 = rf(stateHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OIDCLoginState)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(stateHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: state
+func (_m *MockOIDCStateRepository) Create(state *model.OIDCLoginState) error {
+	ret := _m.Called(state)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.OIDCLoginState) error); ok {
+		r0 = rf(state)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpired provides a mock function with given fields: now
+func (_m *MockOIDCStateRepository) DeleteExpired(now time.Time) error {
+	ret := _m.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpired")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(time.Time) error); ok {
+		r0 = rf(now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockOIDCStateRepository creates a new instance of MockOIDCStateRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOIDCStateRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOIDCStateRepository {
+	mock := &MockOIDCStateRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
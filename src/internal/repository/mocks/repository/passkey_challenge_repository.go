@@ -0,0 +1,95 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repository
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	time "time"
+)
+
+// MockPasskeyChallengeRepository is an autogenerated mock type for the PasskeyChallengeRepository type
+type MockPasskeyChallengeRepository struct {
+	mock.Mock
+}
+
+// Consume provides a mock function with given fields: challengeHash
+func (_m *MockPasskeyChallengeRepository) Consume(challengeHash string) (*model.PasskeyChallenge, error) {
+	ret := _m.Called(challengeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Consume")
+	}
+
+	var r0 *model.PasskeyChallenge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.PasskeyChallenge, error)); ok {
+		return rf(challengeHash)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.PasskeyChallenge); ok {
+		r0 = rf(challengeHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.PasskeyChallenge)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(challengeHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: challenge
+func (_m *MockPasskeyChallengeRepository) Create(challenge *model.PasskeyChallenge) error {
+	ret := _m.Called(challenge)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.PasskeyChallenge) error); ok {
+		r0 = rf(challenge)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteExpired provides a mock function with given fields: now
+func (_m *MockPasskeyChallengeRepository) DeleteExpired(now time.Time) error {
+	ret := _m.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpired")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(time.Time) error); ok {
+		r0 = rf(now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockPasskeyChallengeRepository creates a new instance of MockPasskeyChallengeRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPasskeyChallengeRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPasskeyChallengeRepository {
+	mock := &MockPasskeyChallengeRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,163 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repository
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	time "time"
+)
+
+// MockPasskeyRepository is an autogenerated mock type for the PasskeyRepository type
+type MockPasskeyRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: passkey
+func (_m *MockPasskeyRepository) Create(passkey *model.Passkey) error {
+	ret := _m.Called(passkey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Passkey) error); ok {
+		r0 = rf(passkey)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: userID, id
+func (_m *MockPasskeyRepository) Delete(userID uint, id uint) (bool, error) {
+	ret := _m.Called(userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, uint) (bool, error)); ok {
+		return rf(userID, id)
+	}
+	if rf, ok := ret.Get(0).(func(uint, uint) bool); ok {
+		r0 = rf(userID, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, uint) error); ok {
+		r1 = rf(userID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByCredentialID provides a mock function with given fields: credentialID
+func (_m *MockPasskeyRepository) GetByCredentialID(credentialID string) (*model.Passkey, error) {
+	ret := _m.Called(credentialID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByCredentialID")
+	}
+
+	var r0 *model.Passkey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.Passkey, error)); ok {
+		return rf(credentialID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.Passkey); ok {
+		r0 = rf(credentialID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Passkey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(credentialID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListByUser provides a mock function with given fields: userID
+func (_m *MockPasskeyRepository) ListByUser(userID uint) ([]*model.Passkey, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByUser")
+	}
+
+	var r0 []*model.Passkey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) ([]*model.Passkey, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) []*model.Passkey); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Passkey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordUse provides a mock function with given fields: id, oldSignCount, newSignCount, backupEligible, usedAt
+func (_m *MockPasskeyRepository) RecordUse(id uint, oldSignCount int64, newSignCount int64, backupEligible bool, usedAt time.Time) (bool, error) {
+	ret := _m.Called(id, oldSignCount, newSignCount, backupEligible, usedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordUse")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, int64, int64, bool, time.Time) (bool, error)); ok {
+		return rf(id, oldSignCount, newSignCount, backupEligible, usedAt)
+	}
+	if rf, ok := ret.Get(0).(func(uint, int64, int64, bool, time.Time) bool); ok {
+		r0 = rf(id, oldSignCount, newSignCount, backupEligible, usedAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, int64, int64, bool, time.Time) error); ok {
+		r1 = rf(id, oldSignCount, newSignCount, backupEligible, usedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockPasskeyRepository creates a new instance of MockPasskeyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPasskeyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPasskeyRepository {
+	mock := &MockPasskeyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,99 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repository
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	repository "github.com/weeranieb/go-kit-base/src/internal/repository"
+)
+
+// MockPasswordHistoryRepository is an autogenerated mock type for the PasswordHistoryRepository type
+type MockPasswordHistoryRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: entry
+func (_m *MockPasswordHistoryRepository) Create(entry *model.PasswordHistory) error {
+	ret := _m.Called(entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.PasswordHistory) error); ok {
+		r0 = rf(entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListRecent provides a mock function with given fields: userID, limit
+func (_m *MockPasswordHistoryRepository) ListRecent(userID uint, limit int) ([]*model.PasswordHistory, error) {
+	ret := _m.Called(userID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListRecent")
+	}
+
+	var r0 []*model.PasswordHistory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, int) ([]*model.PasswordHistory, error)); ok {
+		return rf(userID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(uint, int) []*model.PasswordHistory); ok {
+		r0 = rf(userID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.PasswordHistory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, int) error); ok {
+		r1 = rf(userID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WithContext provides a mock function with given fields: ctx
+func (_m *MockPasswordHistoryRepository) WithContext(ctx context.Context) repository.PasswordHistoryRepository {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 repository.PasswordHistoryRepository
+	if rf, ok := ret.Get(0).(func(context.Context) repository.PasswordHistoryRepository); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repository.PasswordHistoryRepository)
+		}
+	}
+
+	return r0
+}
+
+// NewMockPasswordHistoryRepository creates a new instance of MockPasswordHistoryRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPasswordHistoryRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPasswordHistoryRepository {
+	mock := &MockPasswordHistoryRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,233 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repository
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	repository "github.com/weeranieb/go-kit-base/src/internal/repository"
+
+	time "time"
+)
+
+// MockScheduleRepository is an autogenerated mock type for the ScheduleRepository type
+type MockScheduleRepository struct {
+	mock.Mock
+}
+
+// Finish provides a mock function with given fields: name, status, lastError, startedAt, duration
+func (_m *MockScheduleRepository) Finish(name string, status string, lastError string, startedAt time.Time, duration time.Duration) error {
+	ret := _m.Called(name, status, lastError, startedAt, duration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Finish")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, string, string, time.Time, time.Duration) error); ok {
+		r0 = rf(name, status, lastError, startedAt, duration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByName provides a mock function with given fields: name
+func (_m *MockScheduleRepository) GetByName(name string) (*model.Schedule, error) {
+	ret := _m.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByName")
+	}
+
+	var r0 *model.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.Schedule, error)); ok {
+		return rf(name)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.Schedule); ok {
+		r0 = rf(name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with no fields
+func (_m *MockScheduleRepository) List() ([]*model.Schedule, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*model.Schedule
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*model.Schedule, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*model.Schedule); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.Schedule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Register provides a mock function with given fields: schedule
+func (_m *MockScheduleRepository) Register(schedule *model.Schedule) error {
+	ret := _m.Called(schedule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Register")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.Schedule) error); ok {
+		r0 = rf(schedule)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Skip provides a mock function with given fields: name, next, reason
+func (_m *MockScheduleRepository) Skip(name string, next time.Time, reason string) (bool, error) {
+	ret := _m.Called(name, next, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Skip")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time, string) (bool, error)); ok {
+		return rf(name, next, reason)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time, string) bool); ok {
+		r0 = rf(name, next, reason)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time, string) error); ok {
+		r1 = rf(name, next, reason)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Start provides a mock function with given fields: name, next
+func (_m *MockScheduleRepository) Start(name string, next time.Time) (bool, error) {
+	ret := _m.Called(name, next)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Start")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, time.Time) (bool, error)); ok {
+		return rf(name, next)
+	}
+	if rf, ok := ret.Get(0).(func(string, time.Time) bool); ok {
+		r0 = rf(name, next)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, time.Time) error); ok {
+		r1 = rf(name, next)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Trigger provides a mock function with given fields: name
+func (_m *MockScheduleRepository) Trigger(name string) (bool, error) {
+	ret := _m.Called(name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Trigger")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (bool, error)); ok {
+		return rf(name)
+	}
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(name)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WithContext provides a mock function with given fields: ctx
+func (_m *MockScheduleRepository) WithContext(ctx context.Context) repository.ScheduleRepository {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 repository.ScheduleRepository
+	if rf, ok := ret.Get(0).(func(context.Context) repository.ScheduleRepository); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repository.ScheduleRepository)
+		}
+	}
+
+	return r0
+}
+
+// NewMockScheduleRepository creates a new instance of MockScheduleRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockScheduleRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockScheduleRepository {
+	mock := &MockScheduleRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
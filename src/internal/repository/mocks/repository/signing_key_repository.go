@@ -0,0 +1,95 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repository
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	time "time"
+)
+
+// MockSigningKeyRepository is an autogenerated mock type for the SigningKeyRepository type
+type MockSigningKeyRepository struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: key
+func (_m *MockSigningKeyRepository) Create(key *model.SigningKey) error {
+	ret := _m.Called(key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.SigningKey) error); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteCreatedBefore provides a mock function with given fields: before
+func (_m *MockSigningKeyRepository) DeleteCreatedBefore(before time.Time) error {
+	ret := _m.Called(before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCreatedBefore")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(time.Time) error); ok {
+		r0 = rf(before)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListCreatedSince provides a mock function with given fields: since
+func (_m *MockSigningKeyRepository) ListCreatedSince(since time.Time) ([]*model.SigningKey, error) {
+	ret := _m.Called(since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCreatedSince")
+	}
+
+	var r0 []*model.SigningKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) ([]*model.SigningKey, error)); ok {
+		return rf(since)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) []*model.SigningKey); ok {
+		r0 = rf(since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.SigningKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockSigningKeyRepository creates a new instance of MockSigningKeyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSigningKeyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSigningKeyRepository {
+	mock := &MockSigningKeyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -3,8 +3,12 @@
 package repository
 
 import (
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
 	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	repository "github.com/weeranieb/go-kit-base/src/internal/repository"
 )
 
 // MockUserRepository is an autogenerated mock type for the UserRepository type
@@ -186,6 +190,44 @@ func (_m *MockUserRepository) Update(user *model.User) error {
 	return r0
 }
 
+// UpdatePasswordHash provides a mock function with given fields: id, currentHash, newHash
+func (_m *MockUserRepository) UpdatePasswordHash(id uint, currentHash string, newHash string) error {
+	ret := _m.Called(id, currentHash, newHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePasswordHash")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, string, string) error); ok {
+		r0 = rf(id, currentHash, newHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// WithContext provides a mock function with given fields: ctx
+func (_m *MockUserRepository) WithContext(ctx context.Context) repository.UserRepository {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 repository.UserRepository
+	if rf, ok := ret.Get(0).(func(context.Context) repository.UserRepository); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repository.UserRepository)
+		}
+	}
+
+	return r0
+}
+
 // NewMockUserRepository creates a new instance of MockUserRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockUserRepository(t interface {
@@ -0,0 +1,143 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package repository
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	repository "github.com/weeranieb/go-kit-base/src/internal/repository"
+
+	time "time"
+)
+
+// MockUserVersionRepository is an autogenerated mock type for the UserVersionRepository type
+type MockUserVersionRepository struct {
+	mock.Mock
+}
+
+// Get provides a mock function with given fields: userID, version
+func (_m *MockUserVersionRepository) Get(userID uint, version uint) (*model.UserVersion, error) {
+	ret := _m.Called(userID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *model.UserVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, uint) (*model.UserVersion, error)); ok {
+		return rf(userID, version)
+	}
+	if rf, ok := ret.Get(0).(func(uint, uint) *model.UserVersion); ok {
+		r0 = rf(userID, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UserVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, uint) error); ok {
+		r1 = rf(userID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAsOf provides a mock function with given fields: userID, at
+func (_m *MockUserVersionRepository) GetAsOf(userID uint, at time.Time) (*model.UserVersion, error) {
+	ret := _m.Called(userID, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAsOf")
+	}
+
+	var r0 *model.UserVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, time.Time) (*model.UserVersion, error)); ok {
+		return rf(userID, at)
+	}
+	if rf, ok := ret.Get(0).(func(uint, time.Time) *model.UserVersion); ok {
+		r0 = rf(userID, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UserVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, time.Time) error); ok {
+		r1 = rf(userID, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: userID, limit, offset
+func (_m *MockUserVersionRepository) List(userID uint, limit int, offset int) ([]*model.UserVersion, error) {
+	ret := _m.Called(userID, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*model.UserVersion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, int, int) ([]*model.UserVersion, error)); ok {
+		return rf(userID, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(uint, int, int) []*model.UserVersion); ok {
+		r0 = rf(userID, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.UserVersion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, int, int) error); ok {
+		r1 = rf(userID, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WithContext provides a mock function with given fields: ctx
+func (_m *MockUserVersionRepository) WithContext(ctx context.Context) repository.UserVersionRepository {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 repository.UserVersionRepository
+	if rf, ok := ret.Get(0).(func(context.Context) repository.UserVersionRepository); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repository.UserVersionRepository)
+		}
+	}
+
+	return r0
+}
+
+// NewMockUserVersionRepository creates a new instance of MockUserVersionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUserVersionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUserVersionRepository {
+	mock := &MockUserVersionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=OAuthClientRepository --output=./mocks/repository --outpkg=repository --filename=oauth_client_repository.go --structname=MockOAuthClientRepository --with-expecter=false
+type OAuthClientRepository interface {
+	Create(client *model.OAuthClient) error
+	GetByID(id uint) (*model.OAuthClient, error)
+	GetByClientID(clientID string) (*model.OAuthClient, error)
+	List() ([]*model.OAuthClient, error)
+	Revoke(id uint, revokedAt time.Time) (bool, error)
+}
+
+type oauthClientRepository struct {
+	db *gorm.DB
+}
+
+func NewOAuthClientRepository(db *gorm.DB) OAuthClientRepository {
+	return &oauthClientRepository{db: db}
+}
+
+func (r *oauthClientRepository) Create(client *model.OAuthClient) error {
+	return r.db.Create(client).Error
+}
+
+func (r *oauthClientRepository) GetByID(id uint) (*model.OAuthClient, error) {
+	var client model.OAuthClient
+	err := r.db.First(&client, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (r *oauthClientRepository) GetByClientID(clientID string) (*model.OAuthClient, error) {
+	var client model.OAuthClient
+	err := r.db.Where("client_id = ?", clientID).First(&client).Error
+	if err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+func (r *oauthClientRepository) List() ([]*model.OAuthClient, error) {
+	var clients []*model.OAuthClient
+	err := r.db.Order("created_at DESC, id DESC").Find(&clients).Error
+	return clients, err
+}
+
+// Revoke revokes the client. It reports false if there is no such client or
+// it was already revoked.
+func (r *oauthClientRepository) Revoke(id uint, revokedAt time.Time) (bool, error) {
+	result := r.db.Model(&model.OAuthClient{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type OAuthClientRepositoryTestSuite struct {
+	suite.Suite
+	db                    *gorm.DB
+	oauthClientRepository OAuthClientRepository
+}
+
+func (s *OAuthClientRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.OAuthClient{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.oauthClientRepository = NewOAuthClientRepository(s.db)
+}
+
+func (s *OAuthClientRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *OAuthClientRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM oauth_clients")
+}
+
+func TestOAuthClientRepositorySuite(t *testing.T) {
+	suite.Run(t, new(OAuthClientRepositoryTestSuite))
+}
+
+func (s *OAuthClientRepositoryTestSuite) createClient(clientID string) *model.OAuthClient {
+	client := &model.OAuthClient{
+		ClientID:     clientID,
+		SecretHash:   "hash-" + clientID,
+		Name:         "App " + clientID,
+		RedirectURIs: model.RedirectURIList{"https://app.example.com/callback", "http://localhost:3000/callback"},
+		Scopes:       model.ScopeList{"openid", "email"},
+	}
+	s.Require().NoError(s.oauthClientRepository.Create(client))
+	return client
+}
+
+func (s *OAuthClientRepositoryTestSuite) TestGetByClientID() {
+	created := s.createClient("client-1")
+
+	client, err := s.oauthClientRepository.GetByClientID("client-1")
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), created.ID, client.ID)
+	assert.Equal(s.T(), model.RedirectURIList{"https://app.example.com/callback", "http://localhost:3000/callback"}, client.RedirectURIs)
+	assert.Equal(s.T(), model.ScopeList{"openid", "email"}, client.Scopes)
+}
+
+func (s *OAuthClientRepositoryTestSuite) TestGetByClientID_NotFound() {
+	_, err := s.oauthClientRepository.GetByClientID("missing")
+
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *OAuthClientRepositoryTestSuite) TestList() {
+	s.createClient("client-1")
+	s.createClient("client-2")
+
+	clients, err := s.oauthClientRepository.List()
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), clients, 2)
+}
+
+func (s *OAuthClientRepositoryTestSuite) TestRevoke() {
+	client := s.createClient("client-1")
+
+	revoked, err := s.oauthClientRepository.Revoke(client.ID, time.Now())
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), revoked)
+
+	revoked, err = s.oauthClientRepository.Revoke(client.ID, time.Now())
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), revoked)
+
+	stored, _ := s.oauthClientRepository.GetByID(client.ID)
+	assert.NotNil(s.T(), stored.RevokedAt)
+}
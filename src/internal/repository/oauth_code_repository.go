@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=OAuthCodeRepository --output=./mocks/repository --outpkg=repository --filename=oauth_code_repository.go --structname=MockOAuthCodeRepository --with-expecter=false
+type OAuthCodeRepository interface {
+	Create(code *model.OAuthAuthorizationCode) error
+	Consume(codeHash string) (*model.OAuthAuthorizationCode, error)
+	DeleteExpired(now time.Time) error
+}
+
+type oauthCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewOAuthCodeRepository(db *gorm.DB) OAuthCodeRepository {
+	return &oauthCodeRepository{db: db}
+}
+
+func (r *oauthCodeRepository) Create(code *model.OAuthAuthorizationCode) error {
+	return r.db.Create(code).Error
+}
+
+// Consume deletes the code and returns it, so that each code can be redeemed
+// only once. It returns gorm.ErrRecordNotFound for unknown codes.
+func (r *oauthCodeRepository) Consume(codeHash string) (*model.OAuthAuthorizationCode, error) {
+	var codes []model.OAuthAuthorizationCode
+	result := r.db.Clauses(clause.Returning{}).
+		Where("code_hash = ?", codeHash).
+		Delete(&codes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(codes) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &codes[0], nil
+}
+
+func (r *oauthCodeRepository) DeleteExpired(now time.Time) error {
+	return r.db.Where("expires_at <= ?", now).Delete(&model.OAuthAuthorizationCode{}).Error
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type OAuthCodeRepositoryTestSuite struct {
+	suite.Suite
+	db                  *gorm.DB
+	oauthCodeRepository OAuthCodeRepository
+}
+
+func (s *OAuthCodeRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.OAuthAuthorizationCode{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.oauthCodeRepository = NewOAuthCodeRepository(s.db)
+}
+
+func (s *OAuthCodeRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *OAuthCodeRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM oauth_authorization_codes")
+}
+
+func TestOAuthCodeRepositorySuite(t *testing.T) {
+	suite.Run(t, new(OAuthCodeRepositoryTestSuite))
+}
+
+func (s *OAuthCodeRepositoryTestSuite) createCode(hash string, expiresAt time.Time) {
+	s.Require().NoError(s.oauthCodeRepository.Create(&model.OAuthAuthorizationCode{
+		CodeHash:    hash,
+		ClientID:    2,
+		UserID:      1,
+		RedirectURI: "https://app.example.com/callback",
+		Scopes:      model.ScopeList{"openid", "email"},
+		Nonce:       "nonce-" + hash,
+		AuthTime:    time.Now(),
+		ExpiresAt:   expiresAt,
+	}))
+}
+
+func (s *OAuthCodeRepositoryTestSuite) TestConsume_OnlyOnce() {
+	s.createCode("code-1", time.Now().Add(time.Minute))
+
+	code, err := s.oauthCodeRepository.Consume("code-1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), uint(1), code.UserID)
+	assert.Equal(s.T(), "nonce-code-1", code.Nonce)
+	assert.Equal(s.T(), model.ScopeList{"openid", "email"}, code.Scopes)
+
+	_, err = s.oauthCodeRepository.Consume("code-1")
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *OAuthCodeRepositoryTestSuite) TestDeleteExpired() {
+	s.createCode("expired", time.Now().Add(-time.Minute))
+	s.createCode("pending", time.Now().Add(time.Minute))
+
+	err := s.oauthCodeRepository.DeleteExpired(time.Now())
+
+	assert.NoError(s.T(), err)
+	_, err = s.oauthCodeRepository.Consume("expired")
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+	_, err = s.oauthCodeRepository.Consume("pending")
+	assert.NoError(s.T(), err)
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=OAuthConsentRepository --output=./mocks/repository --outpkg=repository --filename=oauth_consent_repository.go --structname=MockOAuthConsentRepository --with-expecter=false
+type OAuthConsentRepository interface {
+	Get(userID, clientID uint) (*model.OAuthConsent, error)
+	Save(consent *model.OAuthConsent) error
+	ListByUser(userID uint) ([]*model.OAuthConsent, error)
+	Delete(userID, clientID uint) (bool, error)
+}
+
+type oauthConsentRepository struct {
+	db *gorm.DB
+}
+
+func NewOAuthConsentRepository(db *gorm.DB) OAuthConsentRepository {
+	return &oauthConsentRepository{db: db}
+}
+
+func (r *oauthConsentRepository) Get(userID, clientID uint) (*model.OAuthConsent, error) {
+	var consent model.OAuthConsent
+	err := r.db.Where("user_id = ? AND client_id = ?", userID, clientID).First(&consent).Error
+	if err != nil {
+		return nil, err
+	}
+	return &consent, nil
+}
+
+// Save creates the consent of the user for the client, or replaces its
+// scopes if there already is one.
+func (r *oauthConsentRepository) Save(consent *model.OAuthConsent) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "client_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"scopes", "updated_at"}),
+	}).Create(consent).Error
+}
+
+func (r *oauthConsentRepository) ListByUser(userID uint) ([]*model.OAuthConsent, error) {
+	var consents []*model.OAuthConsent
+	err := r.db.Where("user_id = ?", userID).Order("created_at, id").Find(&consents).Error
+	return consents, err
+}
+
+// Delete withdraws the consent. It reports false if there was none.
+func (r *oauthConsentRepository) Delete(userID, clientID uint) (bool, error) {
+	result := r.db.Where("user_id = ? AND client_id = ?", userID, clientID).Delete(&model.OAuthConsent{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type OAuthConsentRepositoryTestSuite struct {
+	suite.Suite
+	db                     *gorm.DB
+	oauthConsentRepository OAuthConsentRepository
+}
+
+func (s *OAuthConsentRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.OAuthConsent{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.oauthConsentRepository = NewOAuthConsentRepository(s.db)
+}
+
+func (s *OAuthConsentRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *OAuthConsentRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM oauth_consents")
+}
+
+func TestOAuthConsentRepositorySuite(t *testing.T) {
+	suite.Run(t, new(OAuthConsentRepositoryTestSuite))
+}
+
+func (s *OAuthConsentRepositoryTestSuite) TestSave_ReplacesScopes() {
+	s.Require().NoError(s.oauthConsentRepository.Save(&model.OAuthConsent{UserID: 1, ClientID: 2, Scopes: model.ScopeList{"openid"}}))
+
+	err := s.oauthConsentRepository.Save(&model.OAuthConsent{UserID: 1, ClientID: 2, Scopes: model.ScopeList{"openid", "email"}})
+
+	assert.NoError(s.T(), err)
+	consent, err := s.oauthConsentRepository.Get(1, 2)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.ScopeList{"openid", "email"}, consent.Scopes)
+	var count int64
+	s.db.Model(&model.OAuthConsent{}).Count(&count)
+	assert.Equal(s.T(), int64(1), count)
+}
+
+func (s *OAuthConsentRepositoryTestSuite) TestGet_NotFound() {
+	_, err := s.oauthConsentRepository.Get(1, 2)
+
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *OAuthConsentRepositoryTestSuite) TestListByUser() {
+	s.Require().NoError(s.oauthConsentRepository.Save(&model.OAuthConsent{UserID: 1, ClientID: 2, Scopes: model.ScopeList{"openid"}}))
+	s.Require().NoError(s.oauthConsentRepository.Save(&model.OAuthConsent{UserID: 1, ClientID: 3, Scopes: model.ScopeList{"openid"}}))
+	s.Require().NoError(s.oauthConsentRepository.Save(&model.OAuthConsent{UserID: 4, ClientID: 2, Scopes: model.ScopeList{"openid"}}))
+
+	consents, err := s.oauthConsentRepository.ListByUser(1)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), consents, 2)
+}
+
+func (s *OAuthConsentRepositoryTestSuite) TestDelete() {
+	s.Require().NoError(s.oauthConsentRepository.Save(&model.OAuthConsent{UserID: 1, ClientID: 2, Scopes: model.ScopeList{"openid"}}))
+
+	deleted, err := s.oauthConsentRepository.Delete(1, 2)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), deleted)
+
+	deleted, err = s.oauthConsentRepository.Delete(1, 2)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), deleted)
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=OIDCStateRepository --output=./mocks/repository --outpkg=repository --filename=oidc_state_repository.go --structname=MockOIDCStateRepository --with-expecter=false
+type OIDCStateRepository interface {
+	Create(state *model.OIDCLoginState) error
+	Consume(stateHash string) (*model.OIDCLoginState, error)
+	DeleteExpired(now time.Time) error
+}
+
+type oidcStateRepository struct {
+	db *gorm.DB
+}
+
+func NewOIDCStateRepository(db *gorm.DB) OIDCStateRepository {
+	return &oidcStateRepository{db: db}
+}
+
+func (r *oidcStateRepository) Create(state *model.OIDCLoginState) error {
+	return r.db.Create(state).Error
+}
+
+// Consume deletes the state and returns it, so that each state can complete
+// only one sign-in. It returns gorm.ErrRecordNotFound for unknown states.
+func (r *oidcStateRepository) Consume(stateHash string) (*model.OIDCLoginState, error) {
+	var states []model.OIDCLoginState
+	result := r.db.Clauses(clause.Returning{}).
+		Where("state_hash = ?", stateHash).
+		Delete(&states)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(states) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &states[0], nil
+}
+
+func (r *oidcStateRepository) DeleteExpired(now time.Time) error {
+	return r.db.Where("expires_at <= ?", now).Delete(&model.OIDCLoginState{}).Error
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type OIDCStateRepositoryTestSuite struct {
+	suite.Suite
+	db                  *gorm.DB
+	oidcStateRepository OIDCStateRepository
+}
+
+func (s *OIDCStateRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.OIDCLoginState{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.oidcStateRepository = NewOIDCStateRepository(s.db)
+}
+
+func (s *OIDCStateRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *OIDCStateRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM oidc_login_states")
+}
+
+func TestOIDCStateRepositorySuite(t *testing.T) {
+	suite.Run(t, new(OIDCStateRepositoryTestSuite))
+}
+
+func (s *OIDCStateRepositoryTestSuite) createState(hash string, expiresAt time.Time) {
+	s.Require().NoError(s.oidcStateRepository.Create(&model.OIDCLoginState{
+		StateHash:    hash,
+		Provider:     "google",
+		Nonce:        "nonce-" + hash,
+		CodeVerifier: "verifier-" + hash,
+		ExpiresAt:    expiresAt,
+	}))
+}
+
+func (s *OIDCStateRepositoryTestSuite) TestConsume_OnlyOnce() {
+	s.createState("state-1", time.Now().Add(time.Minute))
+
+	state, err := s.oidcStateRepository.Consume("state-1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "google", state.Provider)
+	assert.Equal(s.T(), "nonce-state-1", state.Nonce)
+	assert.Equal(s.T(), "verifier-state-1", state.CodeVerifier)
+
+	_, err = s.oidcStateRepository.Consume("state-1")
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *OIDCStateRepositoryTestSuite) TestConsume_Unknown() {
+	_, err := s.oidcStateRepository.Consume("missing")
+
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *OIDCStateRepositoryTestSuite) TestDeleteExpired() {
+	s.createState("expired", time.Now().Add(-time.Minute))
+	s.createState("pending", time.Now().Add(time.Minute))
+
+	err := s.oidcStateRepository.DeleteExpired(time.Now())
+
+	assert.NoError(s.T(), err)
+	var count int64
+	s.db.Model(&model.OIDCLoginState{}).Count(&count)
+	assert.Equal(s.T(), int64(1), count)
+	_, err = s.oidcStateRepository.Consume("pending")
+	assert.NoError(s.T(), err)
+}
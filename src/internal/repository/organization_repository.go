@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
+	"gorm.io/gorm"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=OrganizationRepository --output=./mocks/repository --outpkg=repository --filename=organization_repository.go --structname=MockOrganizationRepository --with-expecter=false
+type OrganizationRepository interface {
+	Create(organization *model.Organization, ownerID uint) error
+	GetByID(id uint) (*model.Organization, error)
+	GetBySlug(slug string) (*model.Organization, error)
+}
+
+type organizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) OrganizationRepository {
+	return &organizationRepository{db: db}
+}
+
+// Create stores the organization and makes ownerID its owner. A zero
+// ownerID creates it without members.
+func (r *organizationRepository) Create(organization *model.Organization, ownerID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(organization).Error; err != nil {
+			return err
+		}
+		if ownerID == 0 {
+			return nil
+		}
+		// Scoped to the new organization rather than that of the request
+		tx = tx.WithContext(tenant.WithOrganization(tx.Statement.Context, organization.ID))
+		return tx.Create(&model.Membership{
+			OrganizationID: organization.ID,
+			UserID:         ownerID,
+			Role:           model.OrganizationRoleOwner,
+		}).Error
+	})
+}
+
+func (r *organizationRepository) GetByID(id uint) (*model.Organization, error) {
+	var organization model.Organization
+	err := r.db.First(&organization, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &organization, nil
+}
+
+func (r *organizationRepository) GetBySlug(slug string) (*model.Organization, error) {
+	var organization model.Organization
+	err := r.db.Where("slug = ?", slug).First(&organization).Error
+	if err != nil {
+		return nil, err
+	}
+	return &organization, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type OrganizationRepositoryTestSuite struct {
+	suite.Suite
+	db                     *gorm.DB
+	organizationRepository OrganizationRepository
+}
+
+func (s *OrganizationRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.Organization{}, &model.Membership{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.organizationRepository = NewOrganizationRepository(s.db)
+}
+
+func (s *OrganizationRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *OrganizationRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM memberships")
+	s.db.Exec("DELETE FROM organizations")
+}
+
+func TestOrganizationRepositorySuite(t *testing.T) {
+	suite.Run(t, new(OrganizationRepositoryTestSuite))
+}
+
+func (s *OrganizationRepositoryTestSuite) TestCreate_WithOwner() {
+	organization := &model.Organization{Name: "Acme", Slug: "acme"}
+
+	err := s.organizationRepository.Create(organization, 7)
+
+	assert.NoError(s.T(), err)
+	assert.NotZero(s.T(), organization.ID)
+	var membership model.Membership
+	s.db.Where("organization_id = ?", organization.ID).First(&membership)
+	assert.Equal(s.T(), uint(7), membership.UserID)
+	assert.Equal(s.T(), model.OrganizationRoleOwner, membership.Role)
+}
+
+func (s *OrganizationRepositoryTestSuite) TestCreate_WithoutOwner() {
+	organization := &model.Organization{Name: "Acme", Slug: "acme"}
+
+	err := s.organizationRepository.Create(organization, 0)
+
+	assert.NoError(s.T(), err)
+	var count int64
+	s.db.Model(&model.Membership{}).Count(&count)
+	assert.Zero(s.T(), count)
+}
+
+func (s *OrganizationRepositoryTestSuite) TestCreate_DuplicateSlug() {
+	s.organizationRepository.Create(&model.Organization{Name: "Acme", Slug: "acme"}, 7)
+
+	err := s.organizationRepository.Create(&model.Organization{Name: "Acme 2", Slug: "acme"}, 8)
+
+	assert.Error(s.T(), err)
+	var count int64
+	s.db.Model(&model.Membership{}).Count(&count)
+	assert.Equal(s.T(), int64(1), count)
+}
+
+func (s *OrganizationRepositoryTestSuite) TestGetBySlug() {
+	organization := &model.Organization{Name: "Acme", Slug: "acme"}
+	s.organizationRepository.Create(organization, 0)
+
+	found, err := s.organizationRepository.GetBySlug("acme")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), organization.ID, found.ID)
+
+	found, err = s.organizationRepository.GetByID(organization.ID)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "Acme", found.Name)
+
+	_, err = s.organizationRepository.GetBySlug("globex")
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
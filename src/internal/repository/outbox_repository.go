@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/events"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=OutboxRepository --output=./mocks/repository --outpkg=repository --filename=outbox_repository.go --structname=MockOutboxRepository --with-expecter=false
+type OutboxRepository interface {
+	// WithContext returns a repository bound to ctx. Add stores events in
+	// the organization of ctx and returns tenant.ErrNoOrganization without
+	// one; the relay works on all organizations through a repository bound
+	// to a tenant.Unscoped context.
+	WithContext(ctx context.Context) OutboxRepository
+	Add(event events.Event) error
+	Claim(limit int, lease time.Duration) ([]*model.OutboxMessage, error)
+	MarkPublished(id uint) error
+	MarkFailed(id uint, cause error, retryAt time.Time) error
+	ListAfter(afterID uint, limit int) ([]*model.OutboxMessage, error)
+	LastID() (uint, error)
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) WithContext(ctx context.Context) OutboxRepository {
+	return &outboxRepository{db: withTransaction(r.db, ctx)}
+}
+
+// Add stores the event for the relay. Bound to a context from
+// Transactor.Transaction, the event is only stored if the transaction
+// commits.
+func (r *outboxRepository) Add(event events.Event) error {
+	if err := requireOrganization(r.db); err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return r.db.Create(&model.OutboxMessage{
+		EventType:     event.EventType(),
+		AggregateType: event.AggregateType(),
+		AggregateID:   event.AggregateID(),
+		Payload:       string(payload),
+		NextAttemptAt: time.Now(),
+	}).Error
+}
+
+// addOutboxEvent stores the event in the transaction of tx, scoped to the
+// organization of the aggregate, as for audit events.
+func addOutboxEvent(tx *gorm.DB, organizationID uint, event events.Event) error {
+	tx = tx.WithContext(tenant.WithOrganization(tx.Statement.Context, organizationID))
+	return (&outboxRepository{db: tx}).Add(event)
+}
+
+// Claim returns up to limit events that are due, oldest first, and holds
+// them for lease. Only the oldest unpublished event of each aggregate is
+// due, so the events of an aggregate are published in order even by
+// several relays.
+func (r *outboxRepository) Claim(limit int, lease time.Duration) ([]*model.OutboxMessage, error) {
+	now := time.Now()
+	var messages []*model.OutboxMessage
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL AND next_attempt_at <= ?", now).
+			Where(`NOT EXISTS (SELECT 1 FROM outbox_messages earlier
+				WHERE earlier.aggregate_type = outbox_messages.aggregate_type
+				AND earlier.aggregate_id = outbox_messages.aggregate_id
+				AND earlier.published_at IS NULL
+				AND earlier.id < outbox_messages.id)`).
+			Order("id").
+			Limit(limit).
+			Find(&messages).Error
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+
+		ids := make([]uint, 0, len(messages))
+		for _, message := range messages {
+			ids = append(ids, message.ID)
+			message.Attempts++
+			message.NextAttemptAt = now.Add(lease)
+		}
+		return tx.Model(&model.OutboxMessage{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": now.Add(lease),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (r *outboxRepository) MarkPublished(id uint) error {
+	return r.db.Model(&model.OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"published_at": time.Now(),
+			"last_error":   "",
+		}).Error
+}
+
+// MarkFailed records why publishing the event failed and when to retry it.
+// An event another relay has published in the meantime is left alone.
+func (r *outboxRepository) MarkFailed(id uint, cause error, retryAt time.Time) error {
+	return r.db.Model(&model.OutboxMessage{}).
+		Where("id = ? AND published_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"last_error":      cause.Error(),
+			"next_attempt_at": retryAt,
+		}).Error
+}
+
+// ListAfter returns up to limit events stored after the one with afterID,
+// in ID order, whether or not they have been published.
+func (r *outboxRepository) ListAfter(afterID uint, limit int) ([]*model.OutboxMessage, error) {
+	var messages []*model.OutboxMessage
+	err := r.db.Where("id > ?", afterID).Order("id").Limit(limit).Find(&messages).Error
+	return messages, err
+}
+
+// LastID returns the ID of the latest event, or 0 if there are none.
+func (r *outboxRepository) LastID() (uint, error) {
+	var id uint
+	err := r.db.Model(&model.OutboxMessage{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error
+	return id, err
+}
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/events"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type OutboxRepositoryTestSuite struct {
+	suite.Suite
+	db  *gorm.DB
+	ctx context.Context
+	// repo is bound to an organization; relay works on all of them
+	repo       OutboxRepository
+	relay      OutboxRepository
+	transactor Transactor
+	users      UserRepository
+}
+
+func (s *OutboxRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+	if err := s.db.Use(tenant.Plugin{}); err != nil {
+		s.T().Fatal("Failed to register tenant plugin:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.Organization{}, &model.User{}, &model.Membership{}, &model.AuditEvent{}, &model.UserVersion{}, &model.OutboxMessage{}, &model.Invitation{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	organization := &model.Organization{Name: "Acme", Slug: "acme"}
+	s.db.Create(organization)
+
+	s.ctx = tenant.WithOrganization(context.Background(), organization.ID)
+	repo := NewOutboxRepository(s.db)
+	s.repo = repo.WithContext(s.ctx)
+	s.relay = repo.WithContext(tenant.Unscoped(context.Background()))
+	s.transactor = NewTransactor(s.db)
+	s.users = NewUserRepository(s.db)
+}
+
+func (s *OutboxRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *OutboxRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM users")
+	s.db.Exec("DELETE FROM memberships")
+	s.db.Exec("DELETE FROM audit_events")
+	s.db.Exec("DELETE FROM user_versions")
+	s.db.Exec("DELETE FROM outbox_messages")
+	s.db.Exec("DELETE FROM invitations")
+}
+
+func TestOutboxRepositorySuite(t *testing.T) {
+	suite.Run(t, new(OutboxRepositoryTestSuite))
+}
+
+func (s *OutboxRepositoryTestSuite) add(event events.Event) {
+	if err := s.repo.Add(event); err != nil {
+		s.T().Fatal("Failed to add event:", err)
+	}
+}
+
+func (s *OutboxRepositoryTestSuite) TestAdd() {
+	s.add(events.UserCreated{User: events.UserSnapshot{ID: 3, Username: "testuser"}})
+
+	var message model.OutboxMessage
+	s.db.WithContext(s.ctx).First(&message)
+	assert.NotZero(s.T(), message.OrganizationID)
+	assert.Equal(s.T(), events.UserCreatedType, message.EventType)
+	assert.Equal(s.T(), events.AggregateUser, message.AggregateType)
+	assert.Equal(s.T(), uint(3), message.AggregateID)
+	assert.JSONEq(s.T(), `{"user":{"id":3,"organization_id":0,"username":"testuser","email":"","pending_email":null,"role":"","email_verified_at":null,"version":0}}`, message.Payload)
+	assert.Nil(s.T(), message.PublishedAt)
+}
+
+func (s *OutboxRepositoryTestSuite) TestAdd_NoOrganization() {
+	err := s.relay.Add(events.UserDeleted{UserID: 3})
+
+	assert.ErrorIs(s.T(), err, tenant.ErrNoOrganization)
+}
+
+func (s *OutboxRepositoryTestSuite) TestClaim_OldestOfEachAggregate() {
+	s.add(events.UserCreated{User: events.UserSnapshot{ID: 1}})
+	s.add(events.UserUpdated{User: events.UserSnapshot{ID: 1}})
+	s.add(events.UserCreated{User: events.UserSnapshot{ID: 2}})
+
+	messages, err := s.relay.Claim(10, time.Minute)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), messages, 2)
+	assert.Equal(s.T(), events.UserCreatedType, messages[0].EventType)
+	assert.Equal(s.T(), uint(1), messages[0].AggregateID)
+	assert.Equal(s.T(), uint(2), messages[1].AggregateID)
+	assert.Equal(s.T(), 1, messages[0].Attempts)
+
+	// The update waits until the creation is published
+	assert.NoError(s.T(), s.relay.MarkPublished(messages[0].ID))
+	messages, _ = s.relay.Claim(10, time.Minute)
+	assert.Len(s.T(), messages, 1)
+	assert.Equal(s.T(), events.UserUpdatedType, messages[0].EventType)
+}
+
+func (s *OutboxRepositoryTestSuite) TestClaim_Lease() {
+	s.add(events.UserCreated{User: events.UserSnapshot{ID: 1}})
+
+	messages, _ := s.relay.Claim(10, time.Minute)
+	assert.Len(s.T(), messages, 1)
+
+	// Claimed events are held until the lease runs out
+	messages, _ = s.relay.Claim(10, time.Minute)
+	assert.Empty(s.T(), messages)
+
+	s.db.Exec("UPDATE outbox_messages SET next_attempt_at = ?", time.Now().Add(-time.Second))
+	messages, _ = s.relay.Claim(10, time.Minute)
+	assert.Len(s.T(), messages, 1)
+	assert.Equal(s.T(), 2, messages[0].Attempts)
+}
+
+func (s *OutboxRepositoryTestSuite) TestClaim_Limit() {
+	for id := uint(1); id <= 3; id++ {
+		s.add(events.UserCreated{User: events.UserSnapshot{ID: id}})
+	}
+
+	messages, err := s.relay.Claim(2, time.Minute)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), messages, 2)
+}
+
+func (s *OutboxRepositoryTestSuite) TestMarkFailed() {
+	s.add(events.UserCreated{User: events.UserSnapshot{ID: 1}})
+	messages, _ := s.relay.Claim(10, time.Minute)
+
+	retryAt := time.Now().Add(time.Hour)
+	assert.NoError(s.T(), s.relay.MarkFailed(messages[0].ID, errors.New("connection refused"), retryAt))
+
+	var message model.OutboxMessage
+	s.db.WithContext(s.ctx).First(&message, messages[0].ID)
+	assert.Equal(s.T(), "connection refused", message.LastError)
+	assert.WithinDuration(s.T(), retryAt, message.NextAttemptAt, time.Second)
+	messages, _ = s.relay.Claim(10, time.Minute)
+	assert.Empty(s.T(), messages)
+}
+
+func (s *OutboxRepositoryTestSuite) TestMarkFailed_AlreadyPublished() {
+	s.add(events.UserCreated{User: events.UserSnapshot{ID: 1}})
+	messages, _ := s.relay.Claim(10, time.Minute)
+	s.relay.MarkPublished(messages[0].ID)
+
+	assert.NoError(s.T(), s.relay.MarkFailed(messages[0].ID, errors.New("timeout"), time.Now()))
+
+	var message model.OutboxMessage
+	s.db.WithContext(s.ctx).First(&message, messages[0].ID)
+	assert.NotNil(s.T(), message.PublishedAt)
+	assert.Empty(s.T(), message.LastError)
+}
+
+func (s *OutboxRepositoryTestSuite) TestTransaction_Commit() {
+	user := &model.User{Username: "testuser", Email: "test@example.com", Password: "hashed_password"}
+
+	err := s.transactor.Transaction(s.ctx, func(ctx context.Context) error {
+		return s.users.WithContext(ctx).Create(user)
+	})
+
+	assert.NoError(s.T(), err)
+	var message model.OutboxMessage
+	assert.NoError(s.T(), s.db.WithContext(s.ctx).First(&message).Error)
+	assert.Equal(s.T(), events.UserCreatedType, message.EventType)
+	assert.Equal(s.T(), user.ID, message.AggregateID)
+}
+
+func (s *OutboxRepositoryTestSuite) TestTransaction_Rollback() {
+	err := s.transactor.Transaction(s.ctx, func(ctx context.Context) error {
+		user := &model.User{Username: "testuser", Email: "test@example.com", Password: "hashed_password"}
+		if err := s.users.WithContext(ctx).Create(user); err != nil {
+			return err
+		}
+		return errors.New("failed after the write")
+	})
+
+	assert.Error(s.T(), err)
+	var users, messages int64
+	s.db.WithContext(s.ctx).Model(&model.User{}).Count(&users)
+	s.db.WithContext(s.ctx).Model(&model.OutboxMessage{}).Count(&messages)
+	assert.Zero(s.T(), users)
+	assert.Zero(s.T(), messages)
+}
+
+// Accepting an invitation creates the user outside the user service; the
+// event still has to be stored with it.
+func (s *OutboxRepositoryTestSuite) TestTransaction_AcceptInvitation() {
+	invitations := NewInvitationRepository(s.db)
+	invitation := &model.Invitation{Email: "test@example.com", Role: model.RoleUser, TokenHash: "hash", ExpiresAt: time.Now().Add(time.Hour)}
+	assert.NoError(s.T(), invitations.WithContext(s.ctx).Create(invitation))
+	user := &model.User{Username: "testuser", Email: "test@example.com", Password: "hashed_password"}
+
+	err := s.transactor.Transaction(s.ctx, func(ctx context.Context) error {
+		if _, err := invitations.WithContext(ctx).MarkAccepted(invitation.ID, "hash", time.Now()); err != nil {
+			return err
+		}
+		return s.users.WithContext(ctx).Create(user)
+	})
+
+	assert.NoError(s.T(), err)
+	messages, _ := s.relay.Claim(10, time.Minute)
+	assert.Len(s.T(), messages, 1)
+	assert.Equal(s.T(), events.UserCreatedType, messages[0].EventType)
+	assert.Equal(s.T(), user.ID, messages[0].AggregateID)
+	assert.Equal(s.T(), user.OrganizationID, messages[0].OrganizationID)
+}
+
+func (s *OutboxRepositoryTestSuite) TestListAfter() {
+	lastID, err := s.relay.LastID()
+	assert.NoError(s.T(), err)
+
+	s.add(events.UserCreated{User: events.UserSnapshot{ID: 1}})
+	s.add(events.UserUpdated{User: events.UserSnapshot{ID: 1}})
+	s.add(events.UserDeleted{UserID: 1})
+
+	messages, err := s.relay.ListAfter(lastID, 2)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), messages, 2)
+	assert.Equal(s.T(), events.UserCreatedType, messages[0].EventType)
+
+	messages, err = s.relay.ListAfter(messages[1].ID, 10)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), messages, 1)
+	assert.Equal(s.T(), events.UserDeletedType, messages[0].EventType)
+
+	lastID, err = s.relay.LastID()
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), messages[0].ID, lastID)
+}
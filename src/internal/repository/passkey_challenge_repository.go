@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=PasskeyChallengeRepository --output=./mocks/repository --outpkg=repository --filename=passkey_challenge_repository.go --structname=MockPasskeyChallengeRepository --with-expecter=false
+type PasskeyChallengeRepository interface {
+	Create(challenge *model.PasskeyChallenge) error
+	Consume(challengeHash string) (*model.PasskeyChallenge, error)
+	DeleteExpired(now time.Time) error
+}
+
+type passkeyChallengeRepository struct {
+	db *gorm.DB
+}
+
+func NewPasskeyChallengeRepository(db *gorm.DB) PasskeyChallengeRepository {
+	return &passkeyChallengeRepository{db: db}
+}
+
+func (r *passkeyChallengeRepository) Create(challenge *model.PasskeyChallenge) error {
+	return r.db.Create(challenge).Error
+}
+
+// Consume deletes the challenge and returns it, so that each challenge can
+// be answered only once. It returns gorm.ErrRecordNotFound for unknown
+// challenges.
+func (r *passkeyChallengeRepository) Consume(challengeHash string) (*model.PasskeyChallenge, error) {
+	var challenges []model.PasskeyChallenge
+	result := r.db.Clauses(clause.Returning{}).
+		Where("challenge_hash = ?", challengeHash).
+		Delete(&challenges)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(challenges) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &challenges[0], nil
+}
+
+func (r *passkeyChallengeRepository) DeleteExpired(now time.Time) error {
+	return r.db.Where("expires_at <= ?", now).Delete(&model.PasskeyChallenge{}).Error
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type PasskeyChallengeRepositoryTestSuite struct {
+	suite.Suite
+	db                         *gorm.DB
+	passkeyChallengeRepository PasskeyChallengeRepository
+}
+
+func (s *PasskeyChallengeRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.PasskeyChallenge{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.passkeyChallengeRepository = NewPasskeyChallengeRepository(s.db)
+}
+
+func (s *PasskeyChallengeRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *PasskeyChallengeRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM passkey_challenges")
+}
+
+func TestPasskeyChallengeRepositorySuite(t *testing.T) {
+	suite.Run(t, new(PasskeyChallengeRepositoryTestSuite))
+}
+
+func (s *PasskeyChallengeRepositoryTestSuite) createChallenge(hash string, userID *uint, expiresAt time.Time) {
+	s.Require().NoError(s.passkeyChallengeRepository.Create(&model.PasskeyChallenge{
+		ChallengeHash: hash,
+		Purpose:       model.PasskeyPurposeRegistration,
+		UserID:        userID,
+		ExpiresAt:     expiresAt,
+	}))
+}
+
+func (s *PasskeyChallengeRepositoryTestSuite) TestConsume_OnlyOnce() {
+	userID := uint(1)
+	s.createChallenge("challenge-1", &userID, time.Now().Add(time.Minute))
+
+	challenge, err := s.passkeyChallengeRepository.Consume("challenge-1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.PasskeyPurposeRegistration, challenge.Purpose)
+	assert.Equal(s.T(), &userID, challenge.UserID)
+
+	_, err = s.passkeyChallengeRepository.Consume("challenge-1")
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *PasskeyChallengeRepositoryTestSuite) TestDeleteExpired() {
+	s.createChallenge("expired", nil, time.Now().Add(-time.Minute))
+	s.createChallenge("pending", nil, time.Now().Add(time.Minute))
+
+	err := s.passkeyChallengeRepository.DeleteExpired(time.Now())
+
+	assert.NoError(s.T(), err)
+	_, err = s.passkeyChallengeRepository.Consume("expired")
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+	_, err = s.passkeyChallengeRepository.Consume("pending")
+	assert.NoError(s.T(), err)
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=PasskeyRepository --output=./mocks/repository --outpkg=repository --filename=passkey_repository.go --structname=MockPasskeyRepository --with-expecter=false
+type PasskeyRepository interface {
+	Create(passkey *model.Passkey) error
+	GetByCredentialID(credentialID string) (*model.Passkey, error)
+	ListByUser(userID uint) ([]*model.Passkey, error)
+	RecordUse(id uint, oldSignCount, newSignCount int64, backupEligible bool, usedAt time.Time) (bool, error)
+	Delete(userID, id uint) (bool, error)
+}
+
+type passkeyRepository struct {
+	db *gorm.DB
+}
+
+func NewPasskeyRepository(db *gorm.DB) PasskeyRepository {
+	return &passkeyRepository{db: db}
+}
+
+func (r *passkeyRepository) Create(passkey *model.Passkey) error {
+	return r.db.Create(passkey).Error
+}
+
+func (r *passkeyRepository) GetByCredentialID(credentialID string) (*model.Passkey, error) {
+	var passkey model.Passkey
+	err := r.db.Where("credential_id = ?", credentialID).First(&passkey).Error
+	if err != nil {
+		return nil, err
+	}
+	return &passkey, nil
+}
+
+func (r *passkeyRepository) ListByUser(userID uint) ([]*model.Passkey, error) {
+	var passkeys []*model.Passkey
+	err := r.db.Where("user_id = ?", userID).Order("created_at, id").Find(&passkeys).Error
+	return passkeys, err
+}
+
+// RecordUse stores the signature counter and backup eligibility of a login.
+// It returns false if the counter is no longer oldSignCount, which means
+// another login with the same counter value got there first.
+func (r *passkeyRepository) RecordUse(id uint, oldSignCount, newSignCount int64, backupEligible bool, usedAt time.Time) (bool, error) {
+	result := r.db.Model(&model.Passkey{}).
+		Where("id = ? AND sign_count = ?", id, oldSignCount).
+		Updates(map[string]interface{}{"sign_count": newSignCount, "backup_eligible": backupEligible, "last_used_at": usedAt})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Delete removes a passkey of the user. It reports false if there was none.
+func (r *passkeyRepository) Delete(userID, id uint) (bool, error) {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&model.Passkey{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
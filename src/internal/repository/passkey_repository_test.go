@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type PasskeyRepositoryTestSuite struct {
+	suite.Suite
+	db                *gorm.DB
+	passkeyRepository PasskeyRepository
+}
+
+func (s *PasskeyRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.Passkey{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.passkeyRepository = NewPasskeyRepository(s.db)
+}
+
+func (s *PasskeyRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *PasskeyRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM passkeys")
+}
+
+func TestPasskeyRepositorySuite(t *testing.T) {
+	suite.Run(t, new(PasskeyRepositoryTestSuite))
+}
+
+func (s *PasskeyRepositoryTestSuite) createPasskey(userID uint, credentialID string) *model.Passkey {
+	passkey := &model.Passkey{
+		UserID:       userID,
+		Name:         "Laptop",
+		CredentialID: credentialID,
+		PublicKey:    []byte{0xa5, 0x01, 0x02},
+		SignCount:    3,
+		Transports:   model.TransportList{"internal", "hybrid"},
+	}
+	s.Require().NoError(s.passkeyRepository.Create(passkey))
+	return passkey
+}
+
+func (s *PasskeyRepositoryTestSuite) TestGetByCredentialID() {
+	s.createPasskey(1, "cred-1")
+
+	passkey, err := s.passkeyRepository.GetByCredentialID("cred-1")
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), uint(1), passkey.UserID)
+	assert.Equal(s.T(), []byte{0xa5, 0x01, 0x02}, passkey.PublicKey)
+	assert.Equal(s.T(), model.TransportList{"internal", "hybrid"}, passkey.Transports)
+
+	_, err = s.passkeyRepository.GetByCredentialID("unknown")
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *PasskeyRepositoryTestSuite) TestCreate_DuplicateCredentialID() {
+	s.createPasskey(1, "cred-1")
+
+	err := s.passkeyRepository.Create(&model.Passkey{UserID: 2, Name: "Phone", CredentialID: "cred-1", PublicKey: []byte{1}})
+
+	assert.Error(s.T(), err)
+}
+
+func (s *PasskeyRepositoryTestSuite) TestListByUser() {
+	s.createPasskey(1, "cred-1")
+	s.createPasskey(1, "cred-2")
+	s.createPasskey(2, "cred-3")
+
+	passkeys, err := s.passkeyRepository.ListByUser(1)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), passkeys, 2)
+	assert.Equal(s.T(), "cred-1", passkeys[0].CredentialID)
+}
+
+func (s *PasskeyRepositoryTestSuite) TestRecordUse() {
+	passkey := s.createPasskey(1, "cred-1")
+	usedAt := time.Now()
+
+	recorded, err := s.passkeyRepository.RecordUse(passkey.ID, 3, 4, true, usedAt)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), recorded)
+
+	// A second login that read the same counter loses
+	recorded, err = s.passkeyRepository.RecordUse(passkey.ID, 3, 4, true, usedAt)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), recorded)
+
+	stored, _ := s.passkeyRepository.GetByCredentialID("cred-1")
+	assert.Equal(s.T(), int64(4), stored.SignCount)
+	assert.True(s.T(), *stored.BackupEligible)
+	assert.WithinDuration(s.T(), usedAt, *stored.LastUsedAt, time.Second)
+}
+
+func (s *PasskeyRepositoryTestSuite) TestDelete() {
+	passkey := s.createPasskey(1, "cred-1")
+
+	// Another user's passkey is not touched
+	deleted, err := s.passkeyRepository.Delete(2, passkey.ID)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), deleted)
+
+	deleted, err = s.passkeyRepository.Delete(1, passkey.ID)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), deleted)
+
+	_, err = s.passkeyRepository.GetByCredentialID("cred-1")
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=PasswordHistoryRepository --output=./mocks/repository --outpkg=repository --filename=password_history_repository.go --structname=MockPasswordHistoryRepository --with-expecter=false
+type PasswordHistoryRepository interface {
+	WithContext(ctx context.Context) PasswordHistoryRepository
+	Create(entry *model.PasswordHistory) error
+	ListRecent(userID uint, limit int) ([]*model.PasswordHistory, error)
+}
+
+type passwordHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordHistoryRepository(db *gorm.DB) PasswordHistoryRepository {
+	return &passwordHistoryRepository{db: db}
+}
+
+func (r *passwordHistoryRepository) WithContext(ctx context.Context) PasswordHistoryRepository {
+	return &passwordHistoryRepository{db: withTransaction(r.db, ctx)}
+}
+
+func (r *passwordHistoryRepository) Create(entry *model.PasswordHistory) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *passwordHistoryRepository) ListRecent(userID uint, limit int) ([]*model.PasswordHistory, error) {
+	var entries []*model.PasswordHistory
+	err := r.db.Where("user_id = ?", userID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
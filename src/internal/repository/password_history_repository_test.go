@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type PasswordHistoryRepositoryTestSuite struct {
+	suite.Suite
+	db                        *gorm.DB
+	passwordHistoryRepository PasswordHistoryRepository
+}
+
+func (s *PasswordHistoryRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.PasswordHistory{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.passwordHistoryRepository = NewPasswordHistoryRepository(s.db)
+}
+
+func (s *PasswordHistoryRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *PasswordHistoryRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM password_histories")
+}
+
+func TestPasswordHistoryRepositorySuite(t *testing.T) {
+	suite.Run(t, new(PasswordHistoryRepositoryTestSuite))
+}
+
+func (s *PasswordHistoryRepositoryTestSuite) TestListRecent_NewestFirst() {
+	base := time.Now().Add(-time.Hour)
+	for i, hash := range []string{"oldest", "older", "newest"} {
+		err := s.passwordHistoryRepository.Create(&model.PasswordHistory{
+			UserID:    1,
+			Hash:      hash,
+			CreatedAt: base.Add(time.Duration(i) * time.Minute),
+		})
+		assert.NoError(s.T(), err)
+	}
+	s.passwordHistoryRepository.Create(&model.PasswordHistory{UserID: 2, Hash: "other"})
+
+	entries, err := s.passwordHistoryRepository.ListRecent(1, 2)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), entries, 2)
+	assert.Equal(s.T(), "newest", entries[0].Hash)
+	assert.Equal(s.T(), "older", entries[1].Hash)
+}
+
+func (s *PasswordHistoryRepositoryTestSuite) TestListRecent_Empty() {
+	entries, err := s.passwordHistoryRepository.ListRecent(1, 5)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), entries, 0)
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=PasswordResetRepository --output=./mocks/repository --outpkg=repository --filename=password_reset_repository.go --structname=MockPasswordResetRepository --with-expecter=false
+type PasswordResetRepository interface {
+	WithContext(ctx context.Context) PasswordResetRepository
+	Create(token *model.PasswordResetToken) error
+	GetByTokenHash(tokenHash string) (*model.PasswordResetToken, error)
+	MarkUsed(id uint, usedAt time.Time) (bool, error)
+	InvalidateForUser(userID uint, usedAt time.Time) error
+}
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+func (r *passwordResetRepository) WithContext(ctx context.Context) PasswordResetRepository {
+	return &passwordResetRepository{db: withTransaction(r.db, ctx)}
+}
+
+func (r *passwordResetRepository) Create(token *model.PasswordResetToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *passwordResetRepository) GetByTokenHash(tokenHash string) (*model.PasswordResetToken, error) {
+	var token model.PasswordResetToken
+	err := r.db.Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkUsed consumes the token. It returns false if the token was already
+// used, so that two concurrent resets cannot both succeed.
+func (r *passwordResetRepository) MarkUsed(id uint, usedAt time.Time) (bool, error) {
+	result := r.db.Model(&model.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", usedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// InvalidateForUser marks all outstanding tokens of the user as used.
+func (r *passwordResetRepository) InvalidateForUser(userID uint, usedAt time.Time) error {
+	return r.db.Model(&model.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", usedAt).Error
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type PasswordResetRepositoryTestSuite struct {
+	suite.Suite
+	db                      *gorm.DB
+	passwordResetRepository PasswordResetRepository
+}
+
+func (s *PasswordResetRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.PasswordResetToken{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.passwordResetRepository = NewPasswordResetRepository(s.db)
+}
+
+func (s *PasswordResetRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *PasswordResetRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM password_reset_tokens")
+}
+
+func TestPasswordResetRepositorySuite(t *testing.T) {
+	suite.Run(t, new(PasswordResetRepositoryTestSuite))
+}
+
+func (s *PasswordResetRepositoryTestSuite) TestCreateAndGetByTokenHash() {
+	token := &model.PasswordResetToken{
+		UserID:    1,
+		TokenHash: "hash-1",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	err := s.passwordResetRepository.Create(token)
+	assert.NoError(s.T(), err)
+	assert.NotZero(s.T(), token.ID)
+
+	result, err := s.passwordResetRepository.GetByTokenHash("hash-1")
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), token.ID, result.ID)
+	assert.Nil(s.T(), result.UsedAt)
+}
+
+func (s *PasswordResetRepositoryTestSuite) TestGetByTokenHash_NotFound() {
+	result, err := s.passwordResetRepository.GetByTokenHash("missing")
+
+	assert.Equal(s.T(), gorm.ErrRecordNotFound, err)
+	assert.Nil(s.T(), result)
+}
+
+func (s *PasswordResetRepositoryTestSuite) TestMarkUsed_OnlyOnce() {
+	token := &model.PasswordResetToken{UserID: 1, TokenHash: "hash-1", ExpiresAt: time.Now().Add(time.Hour)}
+	s.passwordResetRepository.Create(token)
+
+	used, err := s.passwordResetRepository.MarkUsed(token.ID, time.Now())
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), used)
+
+	used, err = s.passwordResetRepository.MarkUsed(token.ID, time.Now())
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), used)
+}
+
+func (s *PasswordResetRepositoryTestSuite) TestInvalidateForUser() {
+	first := &model.PasswordResetToken{UserID: 1, TokenHash: "hash-1", ExpiresAt: time.Now().Add(time.Hour)}
+	other := &model.PasswordResetToken{UserID: 2, TokenHash: "hash-2", ExpiresAt: time.Now().Add(time.Hour)}
+	s.passwordResetRepository.Create(first)
+	s.passwordResetRepository.Create(other)
+
+	err := s.passwordResetRepository.InvalidateForUser(1, time.Now())
+	assert.NoError(s.T(), err)
+
+	result, _ := s.passwordResetRepository.GetByTokenHash("hash-1")
+	assert.NotNil(s.T(), result.UsedAt)
+
+	result, _ = s.passwordResetRepository.GetByTokenHash("hash-2")
+	assert.Nil(s.T(), result.UsedAt)
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=ScheduleRepository --output=./mocks/repository --outpkg=repository --filename=schedule_repository.go --structname=MockScheduleRepository --with-expecter=false
+type ScheduleRepository interface {
+	WithContext(ctx context.Context) ScheduleRepository
+	List() ([]*model.Schedule, error)
+	GetByName(name string) (*model.Schedule, error)
+	Register(schedule *model.Schedule) error
+	Start(name string, next time.Time) (bool, error)
+	Finish(name, status, lastError string, startedAt time.Time, duration time.Duration) error
+	Skip(name string, next time.Time, reason string) (bool, error)
+	Trigger(name string) (bool, error)
+}
+
+type scheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduleRepository(db *gorm.DB) ScheduleRepository {
+	return &scheduleRepository{db: db}
+}
+
+func (r *scheduleRepository) WithContext(ctx context.Context) ScheduleRepository {
+	return &scheduleRepository{db: withTransaction(r.db, ctx)}
+}
+
+// List returns the schedules by name.
+func (r *scheduleRepository) List() ([]*model.Schedule, error) {
+	var schedules []*model.Schedule
+	err := r.db.Order("name").Find(&schedules).Error
+	return schedules, err
+}
+
+func (r *scheduleRepository) GetByName(name string) (*model.Schedule, error) {
+	var schedule model.Schedule
+	err := r.db.Where("name = ?", name).First(&schedule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+// Register stores the schedule of a task unless it is stored already. A
+// stored schedule keeps its next run unless its expression has changed, in
+// which case it takes the one given. Either way a run left in progress,
+// by a replica that has since stopped scheduling, is cleared.
+func (r *scheduleRepository) Register(schedule *model.Schedule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(schedule)
+		if result.Error != nil || result.RowsAffected == 1 {
+			return result.Error
+		}
+
+		err := tx.Model(&model.Schedule{}).
+			Where("name = ? AND expression <> ?", schedule.Name, schedule.Expression).
+			Updates(map[string]interface{}{
+				"expression":  schedule.Expression,
+				"next_run_at": schedule.NextRunAt,
+			}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&model.Schedule{}).
+			Where("name = ? AND running_since IS NOT NULL", schedule.Name).
+			Update("running_since", nil).Error
+	})
+}
+
+// Start marks a due schedule as running and moves its next run to next. It
+// reports false if the schedule is not due or is running already.
+func (r *scheduleRepository) Start(name string, next time.Time) (bool, error) {
+	now := time.Now()
+	result := r.db.Model(&model.Schedule{}).
+		Where("name = ? AND next_run_at <= ? AND running_since IS NULL", name, now).
+		Updates(map[string]interface{}{
+			"running_since": now,
+			"next_run_at":   next,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Finish records the outcome of the run started at startedAt.
+func (r *scheduleRepository) Finish(name, status, lastError string, startedAt time.Time, duration time.Duration) error {
+	return r.db.Model(&model.Schedule{}).
+		Where("name = ?", name).
+		Updates(map[string]interface{}{
+			"running_since":    nil,
+			"last_run_at":      startedAt,
+			"last_status":      status,
+			"last_error":       lastError,
+			"last_duration_ms": duration.Milliseconds(),
+		}).Error
+}
+
+// Skip records a due run as skipped for reason and moves the next run to
+// next. It reports false if the schedule is not due or is running.
+func (r *scheduleRepository) Skip(name string, next time.Time, reason string) (bool, error) {
+	now := time.Now()
+	result := r.db.Model(&model.Schedule{}).
+		Where("name = ? AND next_run_at <= ? AND running_since IS NULL", name, now).
+		Updates(map[string]interface{}{
+			"next_run_at":      next,
+			"last_run_at":      now,
+			"last_status":      model.ScheduleSkipped,
+			"last_error":       reason,
+			"last_duration_ms": 0,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Trigger makes the schedule due now. A running schedule runs again once it
+// finishes. It reports false if there is no such schedule.
+func (r *scheduleRepository) Trigger(name string) (bool, error) {
+	result := r.db.Model(&model.Schedule{}).
+		Where("name = ?", name).
+		Update("next_run_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
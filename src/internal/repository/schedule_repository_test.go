@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type ScheduleRepositoryTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo ScheduleRepository
+}
+
+func (s *ScheduleRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.Schedule{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.repo = NewScheduleRepository(s.db).WithContext(context.Background())
+}
+
+func (s *ScheduleRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *ScheduleRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM schedules")
+}
+
+func TestScheduleRepositorySuite(t *testing.T) {
+	suite.Run(t, new(ScheduleRepositoryTestSuite))
+}
+
+func (s *ScheduleRepositoryTestSuite) register(name, expression string, nextRunAt time.Time) {
+	err := s.repo.Register(&model.Schedule{Name: name, Expression: expression, NextRunAt: nextRunAt})
+	if err != nil {
+		s.T().Fatal("Failed to register schedule:", err)
+	}
+}
+
+func (s *ScheduleRepositoryTestSuite) TestRegister_KeepsNextRun() {
+	due := time.Now().Add(-time.Minute)
+	s.register("jobs.retention", "@daily", due)
+
+	// Registering again, as a new leader does, keeps a run that is due
+	s.register("jobs.retention", "@daily", time.Now().Add(time.Hour))
+
+	schedule, err := s.repo.GetByName("jobs.retention")
+	assert.NoError(s.T(), err)
+	assert.WithinDuration(s.T(), due, schedule.NextRunAt, time.Millisecond)
+}
+
+func (s *ScheduleRepositoryTestSuite) TestRegister_ExpressionChanged() {
+	s.register("jobs.retention", "@daily", time.Now().Add(-time.Minute))
+	next := time.Now().Add(time.Hour)
+
+	s.register("jobs.retention", "@hourly", next)
+
+	schedule, _ := s.repo.GetByName("jobs.retention")
+	assert.Equal(s.T(), "@hourly", schedule.Expression)
+	assert.WithinDuration(s.T(), next, schedule.NextRunAt, time.Millisecond)
+}
+
+func (s *ScheduleRepositoryTestSuite) TestRegister_ClearsAbandonedRun() {
+	s.register("jobs.retention", "@daily", time.Now().Add(-time.Minute))
+	s.repo.Start("jobs.retention", time.Now().Add(time.Hour))
+
+	s.register("jobs.retention", "@daily", time.Now())
+
+	schedule, _ := s.repo.GetByName("jobs.retention")
+	assert.Nil(s.T(), schedule.RunningSince)
+}
+
+func (s *ScheduleRepositoryTestSuite) TestStart() {
+	s.register("jobs.retention", "@daily", time.Now().Add(-time.Minute))
+	next := time.Now().Add(time.Hour)
+
+	started, err := s.repo.Start("jobs.retention", next)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), started)
+
+	schedule, _ := s.repo.GetByName("jobs.retention")
+	assert.NotNil(s.T(), schedule.RunningSince)
+	assert.WithinDuration(s.T(), next, schedule.NextRunAt, time.Millisecond)
+
+	// Not due any more, and running
+	started, err = s.repo.Start("jobs.retention", next)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), started)
+}
+
+func (s *ScheduleRepositoryTestSuite) TestStart_NotDue() {
+	s.register("jobs.retention", "@daily", time.Now().Add(time.Hour))
+
+	started, err := s.repo.Start("jobs.retention", time.Now().Add(2*time.Hour))
+
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), started)
+}
+
+func (s *ScheduleRepositoryTestSuite) TestFinish() {
+	s.register("jobs.retention", "@daily", time.Now().Add(-time.Minute))
+	s.repo.Start("jobs.retention", time.Now().Add(time.Hour))
+	startedAt := time.Now()
+
+	err := s.repo.Finish("jobs.retention", model.ScheduleFailed, "database is down", startedAt, 1500*time.Millisecond)
+	assert.NoError(s.T(), err)
+
+	schedule, _ := s.repo.GetByName("jobs.retention")
+	assert.Nil(s.T(), schedule.RunningSince)
+	assert.WithinDuration(s.T(), startedAt, *schedule.LastRunAt, time.Millisecond)
+	assert.Equal(s.T(), model.ScheduleFailed, schedule.LastStatus)
+	assert.Equal(s.T(), "database is down", schedule.LastError)
+	assert.Equal(s.T(), int64(1500), schedule.LastDurationMS)
+}
+
+func (s *ScheduleRepositoryTestSuite) TestSkip() {
+	s.register("jobs.retention", "@daily", time.Now().Add(-time.Hour))
+	next := time.Now().Add(time.Hour)
+
+	skipped, err := s.repo.Skip("jobs.retention", next, "run was missed by 1h0m0s")
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), skipped)
+
+	schedule, _ := s.repo.GetByName("jobs.retention")
+	assert.Equal(s.T(), model.ScheduleSkipped, schedule.LastStatus)
+	assert.Equal(s.T(), "run was missed by 1h0m0s", schedule.LastError)
+	assert.WithinDuration(s.T(), next, schedule.NextRunAt, time.Millisecond)
+}
+
+func (s *ScheduleRepositoryTestSuite) TestTrigger() {
+	s.register("jobs.retention", "@daily", time.Now().Add(time.Hour))
+
+	triggered, err := s.repo.Trigger("jobs.retention")
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), triggered)
+
+	started, _ := s.repo.Start("jobs.retention", time.Now().Add(time.Hour))
+	assert.True(s.T(), started)
+
+	triggered, err = s.repo.Trigger("nope")
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), triggered)
+}
+
+func (s *ScheduleRepositoryTestSuite) TestList() {
+	s.register("jobs.retention", "@daily", time.Now())
+	s.register("idempotency.cleanup", "@hourly", time.Now())
+
+	schedules, err := s.repo.List()
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), schedules, 2)
+	assert.Equal(s.T(), "idempotency.cleanup", schedules[0].Name)
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=SessionRepository --output=./mocks/repository --outpkg=repository --filename=session_repository.go --structname=MockSessionRepository --with-expecter=false
+type SessionRepository interface {
+	Create(session *model.Session) error
+	GetByID(id string) (*model.Session, error)
+	ListActiveForUser(userID uint, now time.Time) ([]*model.Session, error)
+	Touch(id string, lastSeenAt time.Time) error
+	Revoke(id string, userID uint, revokedAt time.Time) (bool, error)
+	RevokeAllForUser(userID uint, revokedAt time.Time) error
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) Create(session *model.Session) error {
+	return r.db.Create(session).Error
+}
+
+func (r *sessionRepository) GetByID(id string) (*model.Session, error) {
+	var session model.Session
+	err := r.db.Where("id = ?", id).First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// ListActiveForUser returns the sessions of the user that are neither revoked
+// nor expired, most recently used first.
+func (r *sessionRepository) ListActiveForUser(userID uint, now time.Time) ([]*model.Session, error) {
+	var sessions []*model.Session
+	err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC, created_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *sessionRepository) Touch(id string, lastSeenAt time.Time) error {
+	return r.db.Model(&model.Session{}).
+		Where("id = ?", id).
+		UpdateColumn("last_seen_at", lastSeenAt).Error
+}
+
+// Revoke revokes one session of the user. It reports false if the user has
+// no such session or it was already revoked.
+func (r *sessionRepository) Revoke(id string, userID uint, revokedAt time.Time) (bool, error) {
+	result := r.db.Model(&model.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", revokedAt)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *sessionRepository) RevokeAllForUser(userID uint, revokedAt time.Time) error {
+	return r.db.Model(&model.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", revokedAt).Error
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type SessionRepositoryTestSuite struct {
+	suite.Suite
+	db                *gorm.DB
+	sessionRepository SessionRepository
+}
+
+func (s *SessionRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.Session{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.sessionRepository = NewSessionRepository(s.db)
+}
+
+func (s *SessionRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *SessionRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM sessions")
+}
+
+func TestSessionRepositorySuite(t *testing.T) {
+	suite.Run(t, new(SessionRepositoryTestSuite))
+}
+
+func (s *SessionRepositoryTestSuite) TestCreate_Success() {
+	session := &model.Session{
+		ID:        "session-1",
+		UserID:    1,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	err := s.sessionRepository.Create(session)
+
+	assert.NoError(s.T(), err)
+	assert.NotZero(s.T(), session.CreatedAt)
+}
+
+func (s *SessionRepositoryTestSuite) TestGetByID_Success() {
+	session := &model.Session{ID: "session-1", UserID: 1, ExpiresAt: time.Now().Add(time.Hour)}
+	s.sessionRepository.Create(session)
+
+	result, err := s.sessionRepository.GetByID("session-1")
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), uint(1), result.UserID)
+	assert.True(s.T(), result.IsActive(time.Now()))
+}
+
+func (s *SessionRepositoryTestSuite) TestGetByID_NotFound() {
+	result, err := s.sessionRepository.GetByID("missing")
+
+	assert.Error(s.T(), err)
+	assert.Nil(s.T(), result)
+	assert.Equal(s.T(), gorm.ErrRecordNotFound, err)
+}
+
+func (s *SessionRepositoryTestSuite) TestRevokeAllForUser() {
+	s.sessionRepository.Create(&model.Session{ID: "a", UserID: 1, ExpiresAt: time.Now().Add(time.Hour)})
+	s.sessionRepository.Create(&model.Session{ID: "b", UserID: 1, ExpiresAt: time.Now().Add(time.Hour)})
+	s.sessionRepository.Create(&model.Session{ID: "c", UserID: 2, ExpiresAt: time.Now().Add(time.Hour)})
+
+	err := s.sessionRepository.RevokeAllForUser(1, time.Now())
+	assert.NoError(s.T(), err)
+
+	for _, id := range []string{"a", "b"} {
+		session, err := s.sessionRepository.GetByID(id)
+		assert.NoError(s.T(), err)
+		assert.NotNil(s.T(), session.RevokedAt)
+		assert.False(s.T(), session.IsActive(time.Now()))
+	}
+
+	// Sessions of other users are untouched
+	other, err := s.sessionRepository.GetByID("c")
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), other.RevokedAt)
+}
+
+func (s *SessionRepositoryTestSuite) TestListActiveForUser() {
+	now := time.Now()
+	revokedAt := now.Add(-time.Minute)
+	s.sessionRepository.Create(&model.Session{ID: "old", UserID: 1, LastSeenAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)})
+	s.sessionRepository.Create(&model.Session{ID: "recent", UserID: 1, LastSeenAt: now, ExpiresAt: now.Add(time.Hour)})
+	s.sessionRepository.Create(&model.Session{ID: "revoked", UserID: 1, LastSeenAt: now, ExpiresAt: now.Add(time.Hour), RevokedAt: &revokedAt})
+	s.sessionRepository.Create(&model.Session{ID: "expired", UserID: 1, LastSeenAt: now, ExpiresAt: now.Add(-time.Minute)})
+	s.sessionRepository.Create(&model.Session{ID: "other", UserID: 2, LastSeenAt: now, ExpiresAt: now.Add(time.Hour)})
+
+	result, err := s.sessionRepository.ListActiveForUser(1, now)
+
+	assert.NoError(s.T(), err)
+	if assert.Len(s.T(), result, 2) {
+		assert.Equal(s.T(), "recent", result[0].ID)
+		assert.Equal(s.T(), "old", result[1].ID)
+	}
+}
+
+func (s *SessionRepositoryTestSuite) TestTouch() {
+	now := time.Now()
+	s.sessionRepository.Create(&model.Session{ID: "a", UserID: 1, LastSeenAt: now.Add(-time.Hour), ExpiresAt: now.Add(time.Hour)})
+
+	err := s.sessionRepository.Touch("a", now)
+	assert.NoError(s.T(), err)
+
+	session, _ := s.sessionRepository.GetByID("a")
+	assert.WithinDuration(s.T(), now, session.LastSeenAt, time.Millisecond)
+}
+
+func (s *SessionRepositoryTestSuite) TestRevoke() {
+	s.sessionRepository.Create(&model.Session{ID: "a", UserID: 1, ExpiresAt: time.Now().Add(time.Hour)})
+
+	// Another user's ID does not match
+	revoked, err := s.sessionRepository.Revoke("a", 2, time.Now())
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), revoked)
+
+	revoked, err = s.sessionRepository.Revoke("a", 1, time.Now())
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), revoked)
+
+	// Already revoked
+	revoked, err = s.sessionRepository.Revoke("a", 1, time.Now())
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), revoked)
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=SigningKeyRepository --output=./mocks/repository --outpkg=repository --filename=signing_key_repository.go --structname=MockSigningKeyRepository --with-expecter=false
+type SigningKeyRepository interface {
+	Create(key *model.SigningKey) error
+	ListCreatedSince(since time.Time) ([]*model.SigningKey, error)
+	DeleteCreatedBefore(before time.Time) error
+}
+
+type signingKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewSigningKeyRepository(db *gorm.DB) SigningKeyRepository {
+	return &signingKeyRepository{db: db}
+}
+
+func (r *signingKeyRepository) Create(key *model.SigningKey) error {
+	return r.db.Create(key).Error
+}
+
+// ListCreatedSince returns the keys created at or after since, newest first.
+func (r *signingKeyRepository) ListCreatedSince(since time.Time) ([]*model.SigningKey, error) {
+	var keys []*model.SigningKey
+	err := r.db.Where("created_at >= ?", since).Order("created_at DESC, id").Find(&keys).Error
+	return keys, err
+}
+
+func (r *signingKeyRepository) DeleteCreatedBefore(before time.Time) error {
+	return r.db.Where("created_at < ?", before).Delete(&model.SigningKey{}).Error
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type SigningKeyRepositoryTestSuite struct {
+	suite.Suite
+	db                   *gorm.DB
+	signingKeyRepository SigningKeyRepository
+}
+
+func (s *SigningKeyRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.SigningKey{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.signingKeyRepository = NewSigningKeyRepository(s.db)
+}
+
+func (s *SigningKeyRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *SigningKeyRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM signing_keys")
+}
+
+func TestSigningKeyRepositorySuite(t *testing.T) {
+	suite.Run(t, new(SigningKeyRepositoryTestSuite))
+}
+
+func (s *SigningKeyRepositoryTestSuite) createKey(id string, createdAt time.Time) {
+	s.Require().NoError(s.signingKeyRepository.Create(&model.SigningKey{
+		ID:                  id,
+		PrivateKeyEncrypted: "encrypted-" + id,
+		CreatedAt:           createdAt,
+	}))
+}
+
+func (s *SigningKeyRepositoryTestSuite) TestListCreatedSince_NewestFirst() {
+	now := time.Now()
+	s.createKey("old", now.Add(-48*time.Hour))
+	s.createKey("previous", now.Add(-time.Hour))
+	s.createKey("current", now)
+
+	keys, err := s.signingKeyRepository.ListCreatedSince(now.Add(-24 * time.Hour))
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), keys, 2)
+	assert.Equal(s.T(), "current", keys[0].ID)
+	assert.Equal(s.T(), "previous", keys[1].ID)
+}
+
+func (s *SigningKeyRepositoryTestSuite) TestDeleteCreatedBefore() {
+	now := time.Now()
+	s.createKey("old", now.Add(-48*time.Hour))
+	s.createKey("current", now)
+
+	err := s.signingKeyRepository.DeleteCreatedBefore(now.Add(-24 * time.Hour))
+
+	assert.NoError(s.T(), err)
+	keys, _ := s.signingKeyRepository.ListCreatedSince(time.Time{})
+	assert.Len(s.T(), keys, 1)
+	assert.Equal(s.T(), "current", keys[0].ID)
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type transactionKey struct{}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=Transactor --output=./mocks/repository --outpkg=repository --filename=transactor.go --structname=MockTransactor --with-expecter=false
+type Transactor interface {
+	// Transaction runs fn in a database transaction, committed if fn
+	// returns nil. Repositories bound with WithContext to the context fn is
+	// given take part in the transaction; transactions they start
+	// themselves become savepoints in it.
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type transactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return &transactor{db: db}
+}
+
+func (t *transactor) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withTransaction(t.db, ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, transactionKey{}, tx))
+	})
+}
+
+// withTransaction binds db to ctx, inside the transaction of ctx if it has
+// one.
+func withTransaction(db *gorm.DB, ctx context.Context) *gorm.DB {
+	if tx, ok := ctx.Value(transactionKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -1,18 +1,34 @@
 package repository
 
 import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/audit"
+	"github.com/weeranieb/go-kit-base/src/internal/events"
 	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
 
 	"gorm.io/gorm"
 )
 
+// ErrVersionConflict is returned by Update when the stored record no longer
+// has the version the caller read.
+var ErrVersionConflict = errors.New("record was modified by another request")
+
 //go:generate go run github.com/vektra/mockery/v2@latest --name=UserRepository --output=./mocks/repository --outpkg=repository --filename=user_repository.go --structname=MockUserRepository --with-expecter=false
 type UserRepository interface {
+	// WithContext returns a repository bound to ctx. Bound to an
+	// organization it only sees and changes the users of that organization;
+	// bound to a tenant.Unscoped context it sees the users of all of them.
+	WithContext(ctx context.Context) UserRepository
 	Create(user *model.User) error
 	GetByID(id uint) (*model.User, error)
 	GetByEmail(email string) (*model.User, error)
 	GetByUsername(username string) (*model.User, error)
 	Update(user *model.User) error
+	UpdatePasswordHash(id uint, currentHash, newHash string) error
 	Delete(id uint) error
 	List(limit, offset int) ([]*model.User, error)
 }
@@ -25,8 +41,53 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
+func (r *userRepository) WithContext(ctx context.Context) UserRepository {
+	return &userRepository{db: withTransaction(r.db, ctx)}
+}
+
+// Create stores the user in its organization, or in that of the context if
+// it has none, and makes it a member there. The creation is recorded in the
+// audit log of the organization and as the first version of the user, and
+// UserCreated is added to the outbox.
 func (r *userRepository) Create(user *model.User) error {
-	return r.db.Create(user).Error
+	if user.OrganizationID == 0 {
+		organizationID, ok := tenant.OrganizationFromContext(r.db.Statement.Context)
+		if !ok {
+			return tenant.ErrNoOrganization
+		}
+		user.OrganizationID = organizationID
+	}
+
+	role := model.OrganizationRoleMember
+	if user.Role == model.RoleAdmin {
+		role = model.OrganizationRoleAdmin
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		err := tx.Create(&model.Membership{
+			OrganizationID: user.OrganizationID,
+			UserID:         user.ID,
+			Role:           role,
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := recordUserVersion(tx, user); err != nil {
+			return err
+		}
+
+		if err := addOutboxEvent(tx, user.OrganizationID, events.UserCreated{User: events.NewUserSnapshot(user)}); err != nil {
+			return err
+		}
+
+		changes := audit.Diff(nil, auditedUserFields(user))
+		audit.Redact(changes, "password")
+		event := audit.NewEvent(tx.Statement.Context, model.AuditUserCreated, model.AuditTargetUser, user.ID, changes)
+		return appendAuditEvent(tx, user.OrganizationID, event)
+	})
 }
 
 func (r *userRepository) GetByID(id uint) (*model.User, error) {
@@ -56,16 +117,120 @@ func (r *userRepository) GetByUsername(username string) (*model.User, error) {
 	return &user, nil
 }
 
+// Update writes all fields of the user with a conditional
+// UPDATE ... WHERE version = ? and bumps the version on success. The new
+// version is stored in the user's history and UserUpdated is added to the
+// outbox, and the changed fields are recorded in the audit log of the
+// user's organization, as a role change if the role is among them.
 func (r *userRepository) Update(user *model.User) error {
-	return r.db.Save(user).Error
+	currentVersion := user.Version
+	user.Version = currentVersion + 1
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var before model.User
+		err := tx.Where("version = ?", currentVersion).Limit(1).Find(&before, user.ID).Error
+		if err != nil {
+			return err
+		}
+		if before.ID == 0 {
+			return ErrVersionConflict
+		}
+
+		result := tx.Model(user).
+			Where("version = ?", currentVersion).
+			Select("*").
+			Omit("id", "created_at", "deleted_at").
+			Updates(user)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+		if err := recordUserVersion(tx, user); err != nil {
+			return err
+		}
+		if err := addOutboxEvent(tx, before.OrganizationID, events.UserUpdated{User: events.NewUserSnapshot(user)}); err != nil {
+			return err
+		}
+
+		changes := audit.Diff(auditedUserFields(&before), auditedUserFields(user))
+		if len(changes) == 0 {
+			return nil
+		}
+		audit.Redact(changes, "password")
+
+		action := model.AuditUserUpdated
+		if _, ok := changes["role"]; ok {
+			action = model.AuditUserRoleChanged
+		}
+		event := audit.NewEvent(tx.Statement.Context, action, model.AuditTargetUser, user.ID, changes)
+		return appendAuditEvent(tx, before.OrganizationID, event)
+	})
+	if err != nil {
+		user.Version = currentVersion
+	}
+	return err
 }
 
+// UpdatePasswordHash replaces the stored hash only if it is still
+// currentHash. It does not bump the version: the password is the same, only
+// its encoding changes, so it is not recorded in the audit log either.
+func (r *userRepository) UpdatePasswordHash(id uint, currentHash, newHash string) error {
+	return r.db.Model(&model.User{}).
+		Where("id = ? AND password = ?", id, currentHash).
+		UpdateColumn("password", newHash).Error
+}
+
+// Delete removes the user, records the removal in the audit log of its
+// organization and adds UserDeleted to the outbox.
 func (r *userRepository) Delete(id uint) error {
-	return r.db.Delete(&model.User{}, id).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var user model.User
+		result := tx.Limit(1).Find(&user, id)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		if err := tx.Delete(&user).Error; err != nil {
+			return err
+		}
+		if err := addOutboxEvent(tx, user.OrganizationID, events.UserDeleted{UserID: id, OrganizationID: user.OrganizationID}); err != nil {
+			return err
+		}
+
+		changes := audit.Diff(auditedUserFields(&user), nil)
+		audit.Redact(changes, "password")
+		event := audit.NewEvent(tx.Statement.Context, model.AuditUserDeleted, model.AuditTargetUser, id, changes)
+		return appendAuditEvent(tx, user.OrganizationID, event)
+	})
 }
 
+// List returns a page of the users of the organization the repository is
+// bound to.
 func (r *userRepository) List(limit, offset int) ([]*model.User, error) {
+	if _, ok := tenant.OrganizationFromContext(r.db.Statement.Context); !ok {
+		return nil, tenant.ErrNoOrganization
+	}
+
 	var users []*model.User
 	err := r.db.Limit(limit).Offset(offset).Find(&users).Error
 	return users, err
 }
+
+// auditedUserFields returns the fields of the user recorded in the audit
+// log, in the form they are stored there.
+func auditedUserFields(user *model.User) map[string]interface{} {
+	fields := map[string]interface{}{
+		"organization_id":   user.OrganizationID,
+		"username":          user.Username,
+		"email":             user.Email,
+		"pending_email":     user.PendingEmail,
+		"password":          user.Password,
+		"role":              user.Role,
+		"email_verified_at": nil,
+	}
+	if user.EmailVerifiedAt != nil {
+		fields["email_verified_at"] = user.EmailVerifiedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano)
+	}
+	return fields
+}
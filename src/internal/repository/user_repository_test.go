@@ -1,12 +1,15 @@
 package repository
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/events"
 	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 )
@@ -14,7 +17,11 @@ import (
 type UserRepositoryTestSuite struct {
 	suite.Suite
 	db             *gorm.DB
+	unscoped       *gorm.DB
 	userRepository UserRepository
+	// otherOrganization sees the users of organization 2; userRepository
+	// those of organization 1.
+	otherOrganization UserRepository
 }
 
 func (s *UserRepositoryTestSuite) SetupSuite() {
@@ -25,13 +32,20 @@ func (s *UserRepositoryTestSuite) SetupSuite() {
 		s.T().Fatal("Failed to connect to test database:", err)
 	}
 
+	if err := s.db.Use(tenant.Plugin{}); err != nil {
+		s.T().Fatal("Failed to register tenant plugin:", err)
+	}
+
 	// Auto-migrate the schema
-	err = s.db.AutoMigrate(&model.User{})
+	err = s.db.AutoMigrate(&model.User{}, &model.Membership{}, &model.Organization{}, &model.AuditEvent{}, &model.UserVersion{}, &model.OutboxMessage{})
 	if err != nil {
 		s.T().Fatal("Failed to migrate database:", err)
 	}
 
-	s.userRepository = NewUserRepository(s.db)
+	s.unscoped = s.db.WithContext(tenant.Unscoped(context.Background()))
+	repo := NewUserRepository(s.db)
+	s.userRepository = repo.WithContext(tenant.WithOrganization(context.Background(), 1))
+	s.otherOrganization = repo.WithContext(tenant.WithOrganization(context.Background(), 2))
 }
 
 func (s *UserRepositoryTestSuite) TearDownSuite() {
@@ -44,6 +58,9 @@ func (s *UserRepositoryTestSuite) TearDownSuite() {
 func (s *UserRepositoryTestSuite) SetupTest() {
 	// Clean up before each test
 	s.db.Exec("DELETE FROM users")
+	s.db.Exec("DELETE FROM memberships")
+	s.db.Exec("DELETE FROM user_versions")
+	s.db.Exec("DELETE FROM outbox_messages")
 }
 
 func (s *UserRepositoryTestSuite) TearDownTest() {
@@ -92,7 +109,7 @@ func (s *UserRepositoryTestSuite) TestCreate_DuplicateEmail() {
 	assert.Error(s.T(), err)
 	// Verify the duplicate wasn't created
 	count := int64(0)
-	s.db.Model(&model.User{}).Where("email = ?", "test@example.com").Count(&count)
+	s.unscoped.Model(&model.User{}).Where("email = ?", "test@example.com").Count(&count)
 	assert.Equal(s.T(), int64(1), count)
 }
 
@@ -116,7 +133,7 @@ func (s *UserRepositoryTestSuite) TestCreate_DuplicateUsername() {
 	assert.Error(s.T(), err)
 	// Verify the duplicate wasn't created
 	count := int64(0)
-	s.db.Model(&model.User{}).Where("username = ?", "testuser").Count(&count)
+	s.unscoped.Model(&model.User{}).Where("username = ?", "testuser").Count(&count)
 	assert.Equal(s.T(), int64(1), count)
 }
 
@@ -278,16 +295,102 @@ func (s *UserRepositoryTestSuite) TestUpdate_NonExistent() {
 		Username: "testuser",
 		Email:    "test@example.com",
 		Password: "password",
+		Version:  1,
 	}
 
-	// GORM Save will create if not found, so this might not error
+	// Update is conditional, so it never creates missing records
 	err := s.userRepository.Update(user)
 
-	// GORM Save creates if ID doesn't exist, so we verify it was created
-	assert.NoError(s.T(), err)
+	assert.ErrorIs(s.T(), err, ErrVersionConflict)
 	result, err := s.userRepository.GetByID(999)
+	assert.Error(s.T(), err)
+	assert.Nil(s.T(), result)
+}
+
+func (s *UserRepositoryTestSuite) TestUpdate_BumpsVersion() {
+	user := &model.User{
+		Username: "testuser",
+		Email:    "test@example.com",
+		Password: "password",
+	}
+	err := s.userRepository.Create(user)
 	assert.NoError(s.T(), err)
-	assert.NotNil(s.T(), result)
+	assert.Equal(s.T(), uint(1), user.Version)
+
+	user.Username = "updateduser"
+	err = s.userRepository.Update(user)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), uint(2), user.Version)
+
+	updated, err := s.userRepository.GetByID(user.ID)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), uint(2), updated.Version)
+}
+
+func (s *UserRepositoryTestSuite) TestUpdate_StaleVersion() {
+	user := &model.User{
+		Username: "testuser",
+		Email:    "test@example.com",
+		Password: "password",
+	}
+	err := s.userRepository.Create(user)
+	assert.NoError(s.T(), err)
+
+	// Two writers read the same version
+	first, _ := s.userRepository.GetByID(user.ID)
+	second, _ := s.userRepository.GetByID(user.ID)
+
+	first.Username = "first"
+	err = s.userRepository.Update(first)
+	assert.NoError(s.T(), err)
+
+	second.Username = "second"
+	err = s.userRepository.Update(second)
+
+	assert.ErrorIs(s.T(), err, ErrVersionConflict)
+	assert.Equal(s.T(), uint(1), second.Version)
+
+	// The first write is not overwritten
+	stored, err := s.userRepository.GetByID(user.ID)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "first", stored.Username)
+	assert.Equal(s.T(), uint(2), stored.Version)
+}
+
+func (s *UserRepositoryTestSuite) TestUpdatePasswordHash_Success() {
+	user := &model.User{
+		Username: "testuser",
+		Email:    "test@example.com",
+		Password: "old-hash",
+	}
+	err := s.userRepository.Create(user)
+	assert.NoError(s.T(), err)
+
+	err = s.userRepository.UpdatePasswordHash(user.ID, "old-hash", "new-hash")
+
+	assert.NoError(s.T(), err)
+	stored, err := s.userRepository.GetByID(user.ID)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "new-hash", stored.Password)
+	assert.Equal(s.T(), uint(1), stored.Version)
+}
+
+func (s *UserRepositoryTestSuite) TestUpdatePasswordHash_HashChanged() {
+	user := &model.User{
+		Username: "testuser",
+		Email:    "test@example.com",
+		Password: "changed-hash",
+	}
+	err := s.userRepository.Create(user)
+	assert.NoError(s.T(), err)
+
+	err = s.userRepository.UpdatePasswordHash(user.ID, "old-hash", "new-hash")
+
+	assert.NoError(s.T(), err)
+	stored, err := s.userRepository.GetByID(user.ID)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "changed-hash", stored.Password)
 }
 
 // Test Delete operations
@@ -313,7 +416,7 @@ func (s *UserRepositoryTestSuite) TestDelete_Success() {
 
 	// Verify it's soft deleted (still in DB but with DeletedAt set)
 	var deletedUser model.User
-	s.db.Unscoped().First(&deletedUser, userID)
+	s.unscoped.Unscoped().First(&deletedUser, userID)
 	assert.NotZero(s.T(), deletedUser.DeletedAt)
 }
 
@@ -536,3 +639,137 @@ func (s *UserRepositoryTestSuite) TestMultipleOperations() {
 	_, err = s.userRepository.GetByID(user.ID)
 	assert.Error(s.T(), err)
 }
+
+// Test tenant isolation
+func (s *UserRepositoryTestSuite) TestCreate_AddsMembership() {
+	user := &model.User{Username: "admin", Email: "admin@example.com", Password: "password", Role: model.RoleAdmin}
+
+	err := s.userRepository.Create(user)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), uint(1), user.OrganizationID)
+	var membership model.Membership
+	s.unscoped.Where("user_id = ?", user.ID).First(&membership)
+	assert.Equal(s.T(), uint(1), membership.OrganizationID)
+	assert.Equal(s.T(), model.OrganizationRoleAdmin, membership.Role)
+}
+
+func (s *UserRepositoryTestSuite) TestCreate_NoOrganization() {
+	user := &model.User{Username: "testuser", Email: "test@example.com", Password: "password"}
+
+	err := NewUserRepository(s.db).Create(user)
+
+	assert.ErrorIs(s.T(), err, tenant.ErrNoOrganization)
+}
+
+func (s *UserRepositoryTestSuite) TestCreate_SameUsernameInOtherOrganization() {
+	err := s.userRepository.Create(&model.User{Username: "testuser", Email: "a@example.com", Password: "password"})
+	assert.NoError(s.T(), err)
+
+	err = s.otherOrganization.Create(&model.User{Username: "testuser", Email: "b@example.com", Password: "password"})
+
+	assert.NoError(s.T(), err)
+	count := int64(0)
+	s.unscoped.Model(&model.User{}).Where("username = ?", "testuser").Count(&count)
+	assert.Equal(s.T(), int64(2), count)
+}
+
+func (s *UserRepositoryTestSuite) TestList_OnlyOwnOrganization() {
+	s.userRepository.Create(&model.User{Username: "user1", Email: "user1@example.com", Password: "password"})
+	s.userRepository.Create(&model.User{Username: "user2", Email: "user2@example.com", Password: "password"})
+	s.otherOrganization.Create(&model.User{Username: "user3", Email: "user3@example.com", Password: "password"})
+
+	users, err := s.userRepository.List(10, 0)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), users, 2)
+	for _, user := range users {
+		assert.Equal(s.T(), uint(1), user.OrganizationID)
+	}
+
+	users, err = s.otherOrganization.List(10, 0)
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), users, 1)
+	assert.Equal(s.T(), "user3", users[0].Username)
+}
+
+func (s *UserRepositoryTestSuite) TestList_NoOrganization() {
+	s.userRepository.Create(&model.User{Username: "user1", Email: "user1@example.com", Password: "password"})
+
+	users, err := NewUserRepository(s.db).List(10, 0)
+
+	assert.ErrorIs(s.T(), err, tenant.ErrNoOrganization)
+	assert.Nil(s.T(), users)
+}
+
+func (s *UserRepositoryTestSuite) TestGetByID_OtherOrganization() {
+	user := &model.User{Username: "testuser", Email: "test@example.com", Password: "password"}
+	s.userRepository.Create(user)
+
+	found, err := s.otherOrganization.GetByID(user.ID)
+
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+	assert.Nil(s.T(), found)
+
+	// Unscoped lookups span organizations; unbound ones fail
+	found, err = NewUserRepository(s.db).WithContext(tenant.Unscoped(context.Background())).GetByID(user.ID)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), user.ID, found.ID)
+	_, err = NewUserRepository(s.db).GetByID(user.ID)
+	assert.ErrorIs(s.T(), err, tenant.ErrNoOrganization)
+}
+
+func (s *UserRepositoryTestSuite) TestGetByUsername_OtherOrganization() {
+	s.userRepository.Create(&model.User{Username: "testuser", Email: "test@example.com", Password: "password"})
+
+	found, err := s.otherOrganization.GetByUsername("testuser")
+
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+	assert.Nil(s.T(), found)
+}
+
+func (s *UserRepositoryTestSuite) TestUpdate_OtherOrganization() {
+	user := &model.User{Username: "testuser", Email: "test@example.com", Password: "password"}
+	s.userRepository.Create(user)
+
+	user.Username = "hijacked"
+	err := s.otherOrganization.Update(user)
+
+	assert.ErrorIs(s.T(), err, ErrVersionConflict)
+	stored, _ := s.userRepository.GetByID(user.ID)
+	assert.Equal(s.T(), "testuser", stored.Username)
+}
+
+func (s *UserRepositoryTestSuite) TestDelete_OtherOrganization() {
+	user := &model.User{Username: "testuser", Email: "test@example.com", Password: "password"}
+	s.userRepository.Create(user)
+
+	err := s.otherOrganization.Delete(user.ID)
+
+	assert.NoError(s.T(), err)
+	stored, err := s.userRepository.GetByID(user.ID)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), user.ID, stored.ID)
+}
+
+func (s *UserRepositoryTestSuite) TestOutboxEvents() {
+	user := &model.User{Username: "testuser", Email: "test@example.com", Password: "password"}
+	s.userRepository.Create(user)
+	user.Username = "renamed"
+	s.userRepository.Update(user)
+	// Rehashing the password is not a change of the user
+	s.userRepository.UpdatePasswordHash(user.ID, "password", "rehashed")
+	s.userRepository.Delete(user.ID)
+
+	var messages []model.OutboxMessage
+	s.unscoped.Order("id").Find(&messages)
+	if assert.Len(s.T(), messages, 3) {
+		assert.Equal(s.T(), events.UserCreatedType, messages[0].EventType)
+		assert.Equal(s.T(), events.UserUpdatedType, messages[1].EventType)
+		assert.Contains(s.T(), messages[1].Payload, `"username":"renamed"`)
+		assert.Equal(s.T(), events.UserDeletedType, messages[2].EventType)
+		for _, message := range messages {
+			assert.Equal(s.T(), user.ID, message.AggregateID)
+			assert.Equal(s.T(), uint(1), message.OrganizationID)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
+	"gorm.io/gorm"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=UserVersionRepository --output=./mocks/repository --outpkg=repository --filename=user_version_repository.go --structname=MockUserVersionRepository --with-expecter=false
+type UserVersionRepository interface {
+	// WithContext returns a repository bound to ctx. All methods read the
+	// versions of the users of the organization of ctx and return
+	// tenant.ErrNoOrganization without one. Versions are written by
+	// UserRepository, in the same transaction as the change.
+	WithContext(ctx context.Context) UserVersionRepository
+	List(userID uint, limit, offset int) ([]*model.UserVersion, error)
+	Get(userID, version uint) (*model.UserVersion, error)
+	GetAsOf(userID uint, at time.Time) (*model.UserVersion, error)
+}
+
+type userVersionRepository struct {
+	db *gorm.DB
+}
+
+func NewUserVersionRepository(db *gorm.DB) UserVersionRepository {
+	return &userVersionRepository{db: db}
+}
+
+func (r *userVersionRepository) WithContext(ctx context.Context) UserVersionRepository {
+	return &userVersionRepository{db: withTransaction(r.db, ctx)}
+}
+
+// List returns a page of the versions of the user, newest first.
+func (r *userVersionRepository) List(userID uint, limit, offset int) ([]*model.UserVersion, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return nil, err
+	}
+
+	var versions []*model.UserVersion
+	err := r.db.Where("user_id = ?", userID).Order("version DESC").Limit(limit).Offset(offset).Find(&versions).Error
+	return versions, err
+}
+
+func (r *userVersionRepository) Get(userID, version uint) (*model.UserVersion, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return nil, err
+	}
+
+	var userVersion model.UserVersion
+	err := r.db.Where("user_id = ? AND version = ?", userID, version).First(&userVersion).Error
+	if err != nil {
+		return nil, err
+	}
+	return &userVersion, nil
+}
+
+// GetAsOf returns the version of the user that was current at the time, or
+// gorm.ErrRecordNotFound if the user did not exist yet.
+func (r *userVersionRepository) GetAsOf(userID uint, at time.Time) (*model.UserVersion, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return nil, err
+	}
+
+	var userVersion model.UserVersion
+	err := r.db.Where("user_id = ? AND created_at <= ?", userID, at.UTC()).
+		Order("version DESC").
+		First(&userVersion).Error
+	if err != nil {
+		return nil, err
+	}
+	return &userVersion, nil
+}
+
+// recordUserVersion stores the user as it is now as part of the
+// transaction tx.
+func recordUserVersion(tx *gorm.DB, user *model.User) error {
+	// Scoped to the organization of the user, as for audit events
+	tx = tx.WithContext(tenant.WithOrganization(tx.Statement.Context, user.OrganizationID))
+
+	return tx.Create(&model.UserVersion{
+		OrganizationID:  user.OrganizationID,
+		UserID:          user.ID,
+		Version:         user.Version,
+		Username:        user.Username,
+		Email:           user.Email,
+		PendingEmail:    user.PendingEmail,
+		Role:            user.Role,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		CreatedAt:       user.UpdatedAt.UTC(),
+	}).Error
+}
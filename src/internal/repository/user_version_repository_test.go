@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type UserVersionRepositoryTestSuite struct {
+	suite.Suite
+	db    *gorm.DB
+	acme  UserVersionRepository
+	users UserRepository
+	// globex reads the versions of the other organization
+	globex UserVersionRepository
+	orgs   []*model.Organization
+}
+
+func (s *UserVersionRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+	if err := s.db.Use(tenant.Plugin{}); err != nil {
+		s.T().Fatal("Failed to register tenant plugin:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.Organization{}, &model.User{}, &model.Membership{}, &model.AuditEvent{}, &model.UserVersion{}, &model.OutboxMessage{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.orgs = []*model.Organization{{Name: "Acme", Slug: "acme"}, {Name: "Globex", Slug: "globex"}}
+	s.db.Create(s.orgs)
+
+	ctx := tenant.WithOrganization(context.Background(), s.orgs[0].ID)
+	repo := NewUserVersionRepository(s.db)
+	s.acme = repo.WithContext(ctx)
+	s.globex = repo.WithContext(tenant.WithOrganization(context.Background(), s.orgs[1].ID))
+	s.users = NewUserRepository(s.db).WithContext(ctx)
+}
+
+func (s *UserVersionRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *UserVersionRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM users")
+	s.db.Exec("DELETE FROM memberships")
+	s.db.Exec("DELETE FROM audit_events")
+	s.db.Exec("DELETE FROM user_versions")
+	s.db.Exec("DELETE FROM outbox_messages")
+}
+
+func TestUserVersionRepositorySuite(t *testing.T) {
+	suite.Run(t, new(UserVersionRepositoryTestSuite))
+}
+
+// createRenamedUser creates a user and renames it twice, leaving three
+// versions.
+func (s *UserVersionRepositoryTestSuite) createRenamedUser() *model.User {
+	user := &model.User{Username: "first", Email: "test@example.com", Password: "hashed_password", Role: model.RoleUser}
+	if err := s.users.Create(user); err != nil {
+		s.T().Fatal("Failed to create user:", err)
+	}
+	for _, username := range []string{"second", "third"} {
+		user.Username = username
+		if err := s.users.Update(user); err != nil {
+			s.T().Fatal("Failed to update user:", err)
+		}
+	}
+	return user
+}
+
+func (s *UserVersionRepositoryTestSuite) TestList() {
+	user := s.createRenamedUser()
+
+	versions, err := s.acme.List(user.ID, 10, 0)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), versions, 3)
+	assert.Equal(s.T(), uint(3), versions[0].Version)
+	assert.Equal(s.T(), "third", versions[0].Username)
+	assert.Equal(s.T(), uint(1), versions[2].Version)
+	assert.Equal(s.T(), "first", versions[2].Username)
+
+	versions, _ = s.acme.List(user.ID, 1, 1)
+	assert.Len(s.T(), versions, 1)
+	assert.Equal(s.T(), "second", versions[0].Username)
+}
+
+func (s *UserVersionRepositoryTestSuite) TestGet() {
+	user := s.createRenamedUser()
+
+	version, err := s.acme.Get(user.ID, 2)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "second", version.Username)
+
+	_, err = s.acme.Get(user.ID, 4)
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *UserVersionRepositoryTestSuite) TestGetAsOf() {
+	user := s.createRenamedUser()
+	// Spread the versions out a day apart
+	start := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	for version := 1; version <= 3; version++ {
+		s.db.Exec("UPDATE user_versions SET created_at = ? WHERE user_id = ? AND version = ?",
+			start.AddDate(0, 0, version-1), user.ID, version)
+	}
+
+	version, err := s.acme.GetAsOf(user.ID, start.Add(36*time.Hour))
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "second", version.Username)
+
+	version, err = s.acme.GetAsOf(user.ID, start.AddDate(0, 0, 2))
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "third", version.Username)
+
+	_, err = s.acme.GetAsOf(user.ID, start.Add(-time.Second))
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *UserVersionRepositoryTestSuite) TestUpdateConflict_NoVersion() {
+	user := s.createRenamedUser()
+	stale := *user
+	stale.Version = 1
+	stale.Username = "stale"
+
+	err := s.users.Update(&stale)
+
+	assert.ErrorIs(s.T(), err, ErrVersionConflict)
+	versions, _ := s.acme.List(user.ID, 10, 0)
+	assert.Len(s.T(), versions, 3)
+}
+
+func (s *UserVersionRepositoryTestSuite) TestOnlyOwnOrganization() {
+	user := s.createRenamedUser()
+
+	versions, err := s.globex.List(user.ID, 10, 0)
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), versions)
+
+	_, err = s.globex.GetAsOf(user.ID, time.Now())
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *UserVersionRepositoryTestSuite) TestNoOrganization() {
+	repo := NewUserVersionRepository(s.db).WithContext(context.Background())
+
+	_, err := repo.List(1, 10, 0)
+	assert.ErrorIs(s.T(), err, tenant.ErrNoOrganization)
+
+	_, err = repo.Get(1, 1)
+	assert.ErrorIs(s.T(), err, tenant.ErrNoOrganization)
+
+	_, err = repo.GetAsOf(1, time.Now())
+	assert.ErrorIs(s.T(), err, tenant.ErrNoOrganization)
+}
@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=WebhookRepository --output=./mocks/repository --outpkg=repository --filename=webhook_repository.go --structname=MockWebhookRepository --with-expecter=false
+type WebhookRepository interface {
+	// WithContext returns a repository bound to ctx. All methods but
+	// ClaimDeliveries and RecordAttempt work on the webhooks of the
+	// organization of ctx and return tenant.ErrNoOrganization without one;
+	// the worker sends the deliveries of all organizations through a
+	// repository bound to a tenant.Unscoped context.
+	WithContext(ctx context.Context) WebhookRepository
+	Create(webhook *model.Webhook) error
+	GetByID(id uint) (*model.Webhook, error)
+	List() ([]*model.Webhook, error)
+	ListEnabled() ([]*model.Webhook, error)
+	Update(webhook *model.Webhook) (bool, error)
+	Delete(id uint) (bool, error)
+	AddDeliveries(deliveries []*model.WebhookDelivery) error
+	ListDeliveries(webhookID uint, filter *model.WebhookDeliveryFilter, limit, offset int) ([]*model.WebhookDelivery, error)
+	GetDelivery(webhookID, id uint) (*model.WebhookDelivery, error)
+	ListAttempts(deliveryID uint) ([]*model.WebhookDeliveryAttempt, error)
+	Redeliver(webhookID, id uint) (bool, error)
+	ClaimDeliveries(limit int, lease time.Duration) ([]*model.WebhookDelivery, error)
+	RecordAttempt(delivery *model.WebhookDelivery, attempt *model.WebhookDeliveryAttempt, disableAfter int) (bool, error)
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) WithContext(ctx context.Context) WebhookRepository {
+	return &webhookRepository{db: withTransaction(r.db, ctx)}
+}
+
+func (r *webhookRepository) Create(webhook *model.Webhook) error {
+	if err := requireOrganization(r.db); err != nil {
+		return err
+	}
+	return r.db.Create(webhook).Error
+}
+
+func (r *webhookRepository) GetByID(id uint) (*model.Webhook, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return nil, err
+	}
+
+	var webhook model.Webhook
+	err := r.db.First(&webhook, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// List returns the webhooks of the organization, oldest first.
+func (r *webhookRepository) List() ([]*model.Webhook, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return nil, err
+	}
+
+	var webhooks []*model.Webhook
+	err := r.db.Order("id").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// ListEnabled returns the webhooks of the organization that have not been
+// disabled.
+func (r *webhookRepository) ListEnabled() ([]*model.Webhook, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return nil, err
+	}
+
+	var webhooks []*model.Webhook
+	err := r.db.Where("disabled_at IS NULL").Order("id").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// Update stores the settings of the webhook, including whether it is
+// disabled. It reports false if the webhook does not exist.
+func (r *webhookRepository) Update(webhook *model.Webhook) (bool, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return false, err
+	}
+
+	result := r.db.Model(&model.Webhook{}).
+		Where("id = ?", webhook.ID).
+		Updates(map[string]interface{}{
+			"url":                  webhook.URL,
+			"event_types":          webhook.EventTypes,
+			"description":          webhook.Description,
+			"secret_encrypted":     webhook.SecretEncrypted,
+			"consecutive_failures": webhook.ConsecutiveFailures,
+			"disabled_at":          webhook.DisabledAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Delete removes the webhook with its deliveries and their attempts. It
+// reports false if the webhook does not exist.
+func (r *webhookRepository) Delete(id uint) (bool, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return false, err
+	}
+
+	deleted := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var webhook model.Webhook
+		result := tx.Limit(1).Find(&webhook, id)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		deliveries := tx.Model(&model.WebhookDelivery{}).Select("id").Where("webhook_id = ?", id)
+		if err := tx.Where("delivery_id IN (?)", deliveries).Delete(&model.WebhookDeliveryAttempt{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("webhook_id = ?", id).Delete(&model.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&webhook).Error; err != nil {
+			return err
+		}
+		deleted = true
+		return nil
+	})
+	return deleted, err
+}
+
+// AddDeliveries stores pending deliveries. A delivery of an event the
+// webhook already has is skipped, so handing over an event again is safe.
+func (r *webhookRepository) AddDeliveries(deliveries []*model.WebhookDelivery) error {
+	if err := requireOrganization(r.db); err != nil {
+		return err
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(deliveries).Error
+}
+
+// ListDeliveries returns a page of the deliveries of the webhook, newest
+// first.
+func (r *webhookRepository) ListDeliveries(webhookID uint, filter *model.WebhookDeliveryFilter, limit, offset int) ([]*model.WebhookDelivery, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return nil, err
+	}
+
+	query := r.db.Where("webhook_id = ?", webhookID)
+	if filter != nil && filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	var deliveries []*model.WebhookDelivery
+	err := query.Order("id DESC").Limit(limit).Offset(offset).Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (r *webhookRepository) GetDelivery(webhookID, id uint) (*model.WebhookDelivery, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return nil, err
+	}
+
+	var delivery model.WebhookDelivery
+	err := r.db.Where("webhook_id = ?", webhookID).First(&delivery, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// ListAttempts returns the attempts to send the delivery, oldest first.
+func (r *webhookRepository) ListAttempts(deliveryID uint) ([]*model.WebhookDeliveryAttempt, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return nil, err
+	}
+
+	var attempts []*model.WebhookDeliveryAttempt
+	err := r.db.Where("delivery_id = ?", deliveryID).Order("id").Find(&attempts).Error
+	return attempts, err
+}
+
+// Redeliver makes the delivery pending and due now with a fresh set of
+// attempts, whatever its status. It reports false if the delivery does not
+// exist.
+func (r *webhookRepository) Redeliver(webhookID, id uint) (bool, error) {
+	if err := requireOrganization(r.db); err != nil {
+		return false, err
+	}
+
+	result := r.db.Model(&model.WebhookDelivery{}).
+		Where("id = ? AND webhook_id = ?", id, webhookID).
+		Updates(map[string]interface{}{
+			"status":          model.WebhookDeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ClaimDeliveries returns up to limit pending deliveries of enabled webhooks
+// that are due, oldest first, with their webhooks, and holds them for lease.
+func (r *webhookRepository) ClaimDeliveries(limit int, lease time.Duration) ([]*model.WebhookDelivery, error) {
+	now := time.Now()
+	var deliveries []*model.WebhookDelivery
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "webhook_deliveries"}, Options: "SKIP LOCKED"}).
+			Joins("JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id AND webhooks.disabled_at IS NULL").
+			Where("webhook_deliveries.status = ? AND webhook_deliveries.next_attempt_at <= ?", model.WebhookDeliveryPending, now).
+			Order("webhook_deliveries.id").
+			Limit(limit).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]uint, 0, len(deliveries))
+		webhookIDs := make([]uint, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.ID)
+			webhookIDs = append(webhookIDs, delivery.WebhookID)
+			delivery.Attempts++
+			delivery.NextAttemptAt = now.Add(lease)
+		}
+
+		var webhooks []*model.Webhook
+		if err := tx.Where("id IN ?", webhookIDs).Find(&webhooks).Error; err != nil {
+			return err
+		}
+		byID := make(map[uint]*model.Webhook, len(webhooks))
+		for _, webhook := range webhooks {
+			byID[webhook.ID] = webhook
+		}
+		for _, delivery := range deliveries {
+			delivery.Webhook = byID[delivery.WebhookID]
+		}
+
+		return tx.Model(&model.WebhookDelivery{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": now.Add(lease),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// RecordAttempt stores the attempt and the resulting state of the delivery.
+// A success resets the count of failed attempts in a row of the webhook; a
+// failure adds to it, and disables the webhook once it reaches
+// disableAfter. It reports whether the webhook was disabled.
+func (r *webhookRepository) RecordAttempt(delivery *model.WebhookDelivery, attempt *model.WebhookDeliveryAttempt, disableAfter int) (bool, error) {
+	disabled := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		attempt.OrganizationID = delivery.OrganizationID
+		attempt.DeliveryID = delivery.ID
+		if err := tx.Create(attempt).Error; err != nil {
+			return err
+		}
+
+		err := tx.Model(&model.WebhookDelivery{}).
+			Where("id = ?", delivery.ID).
+			Updates(map[string]interface{}{
+				"status":               delivery.Status,
+				"next_attempt_at":      delivery.NextAttemptAt,
+				"last_response_status": delivery.LastResponseStatus,
+				"last_error":           delivery.LastError,
+				"delivered_at":         delivery.DeliveredAt,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		webhooks := tx.Model(&model.Webhook{}).Where("id = ?", delivery.WebhookID)
+		if attempt.Succeeded() {
+			return webhooks.Update("consecutive_failures", 0).Error
+		}
+		if err := webhooks.Update("consecutive_failures", gorm.Expr("consecutive_failures + 1")).Error; err != nil {
+			return err
+		}
+		result := tx.Model(&model.Webhook{}).
+			Where("id = ? AND disabled_at IS NULL AND consecutive_failures >= ?", delivery.WebhookID, disableAfter).
+			Update("disabled_at", time.Now())
+		disabled = result.RowsAffected == 1
+		return result.Error
+	})
+	return disabled, err
+}
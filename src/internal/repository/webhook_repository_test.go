@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type WebhookRepositoryTestSuite struct {
+	suite.Suite
+	db       *gorm.DB
+	unscoped *gorm.DB
+	// repo is bound to an organization; worker works on all of them
+	repo   WebhookRepository
+	worker WebhookRepository
+}
+
+func (s *WebhookRepositoryTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+	if err := s.db.Use(tenant.Plugin{}); err != nil {
+		s.T().Fatal("Failed to register tenant plugin:", err)
+	}
+
+	err = s.db.AutoMigrate(&model.Organization{}, &model.Webhook{}, &model.WebhookDelivery{}, &model.WebhookDeliveryAttempt{})
+	if err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	organization := &model.Organization{Name: "Acme", Slug: "acme"}
+	s.db.Create(organization)
+
+	repo := NewWebhookRepository(s.db)
+	s.repo = repo.WithContext(tenant.WithOrganization(context.Background(), organization.ID))
+	s.worker = repo.WithContext(tenant.Unscoped(context.Background()))
+	s.unscoped = s.db.WithContext(tenant.Unscoped(context.Background()))
+}
+
+func (s *WebhookRepositoryTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *WebhookRepositoryTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM webhooks")
+	s.db.Exec("DELETE FROM webhook_deliveries")
+	s.db.Exec("DELETE FROM webhook_delivery_attempts")
+}
+
+func TestWebhookRepositorySuite(t *testing.T) {
+	suite.Run(t, new(WebhookRepositoryTestSuite))
+}
+
+func (s *WebhookRepositoryTestSuite) createWebhook() *model.Webhook {
+	webhook := &model.Webhook{
+		URL:             "https://example.com/hooks",
+		EventTypes:      model.EventTypeList{model.WebhookAllEvents},
+		SecretEncrypted: "encrypted",
+	}
+	if err := s.repo.Create(webhook); err != nil {
+		s.T().Fatal("Failed to create webhook:", err)
+	}
+	return webhook
+}
+
+func (s *WebhookRepositoryTestSuite) addDelivery(webhook *model.Webhook, eventID uint) *model.WebhookDelivery {
+	delivery := &model.WebhookDelivery{
+		WebhookID:     webhook.ID,
+		EventID:       eventID,
+		EventType:     "user.created",
+		Payload:       `{"type":"user.created"}`,
+		Status:        model.WebhookDeliveryPending,
+		NextAttemptAt: time.Now(),
+	}
+	if err := s.repo.AddDeliveries([]*model.WebhookDelivery{delivery}); err != nil {
+		s.T().Fatal("Failed to add delivery:", err)
+	}
+	return delivery
+}
+
+func (s *WebhookRepositoryTestSuite) TestCreate_NoOrganization() {
+	err := s.worker.Create(&model.Webhook{URL: "https://example.com/hooks"})
+
+	assert.ErrorIs(s.T(), err, tenant.ErrNoOrganization)
+}
+
+func (s *WebhookRepositoryTestSuite) TestAddDeliveries_SkipsDuplicates() {
+	webhook := s.createWebhook()
+	s.addDelivery(webhook, 1)
+	s.addDelivery(webhook, 1)
+
+	var count int64
+	s.unscoped.Model(&model.WebhookDelivery{}).Count(&count)
+	assert.Equal(s.T(), int64(1), count)
+}
+
+func (s *WebhookRepositoryTestSuite) TestClaimDeliveries() {
+	webhook := s.createWebhook()
+	s.addDelivery(webhook, 1)
+	s.addDelivery(webhook, 2)
+
+	deliveries, err := s.worker.ClaimDeliveries(10, time.Minute)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), deliveries, 2)
+	assert.Equal(s.T(), uint(1), deliveries[0].EventID)
+	assert.Equal(s.T(), 1, deliveries[0].Attempts)
+	assert.Equal(s.T(), webhook.URL, deliveries[0].Webhook.URL)
+
+	// Claimed deliveries are held until the lease runs out
+	deliveries, _ = s.worker.ClaimDeliveries(10, time.Minute)
+	assert.Empty(s.T(), deliveries)
+}
+
+func (s *WebhookRepositoryTestSuite) TestClaimDeliveries_DisabledWebhook() {
+	webhook := s.createWebhook()
+	s.addDelivery(webhook, 1)
+	s.unscoped.Model(&model.Webhook{}).Where("id = ?", webhook.ID).Update("disabled_at", time.Now())
+
+	deliveries, err := s.worker.ClaimDeliveries(10, time.Minute)
+
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), deliveries)
+}
+
+func (s *WebhookRepositoryTestSuite) TestRecordAttempt_Success() {
+	webhook := s.createWebhook()
+	s.unscoped.Model(&model.Webhook{}).Where("id = ?", webhook.ID).Update("consecutive_failures", 2)
+	s.addDelivery(webhook, 1)
+	deliveries, _ := s.worker.ClaimDeliveries(10, time.Minute)
+
+	now := time.Now()
+	delivery := deliveries[0]
+	delivery.Status = model.WebhookDeliverySucceeded
+	delivery.LastResponseStatus = 204
+	delivery.DeliveredAt = &now
+	disabled, err := s.worker.RecordAttempt(delivery, &model.WebhookDeliveryAttempt{ResponseStatus: 204}, 3)
+
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), disabled)
+	stored, _ := s.repo.GetDelivery(webhook.ID, delivery.ID)
+	assert.Equal(s.T(), model.WebhookDeliverySucceeded, stored.Status)
+	assert.Equal(s.T(), 204, stored.LastResponseStatus)
+	attempts, _ := s.repo.ListAttempts(delivery.ID)
+	assert.Len(s.T(), attempts, 1)
+	assert.NotZero(s.T(), attempts[0].OrganizationID)
+	updated, _ := s.repo.GetByID(webhook.ID)
+	assert.Zero(s.T(), updated.ConsecutiveFailures)
+}
+
+func (s *WebhookRepositoryTestSuite) TestRecordAttempt_DisablesAfterFailures() {
+	webhook := s.createWebhook()
+	s.addDelivery(webhook, 1)
+	s.addDelivery(webhook, 2)
+	deliveries, _ := s.worker.ClaimDeliveries(10, time.Minute)
+
+	disabled, err := s.worker.RecordAttempt(deliveries[0], &model.WebhookDeliveryAttempt{ResponseStatus: 500, Error: "endpoint responded with 500"}, 2)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), disabled)
+
+	disabled, err = s.worker.RecordAttempt(deliveries[1], &model.WebhookDeliveryAttempt{Error: "connection refused"}, 2)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), disabled)
+
+	stored, _ := s.repo.GetByID(webhook.ID)
+	assert.Equal(s.T(), 2, stored.ConsecutiveFailures)
+	assert.NotNil(s.T(), stored.DisabledAt)
+	enabled, _ := s.repo.ListEnabled()
+	assert.Empty(s.T(), enabled)
+}
+
+func (s *WebhookRepositoryTestSuite) TestRedeliver() {
+	webhook := s.createWebhook()
+	delivery := s.addDelivery(webhook, 1)
+	s.unscoped.Model(&model.WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(map[string]interface{}{
+		"status":   model.WebhookDeliveryFailed,
+		"attempts": 10,
+	})
+
+	redelivered, err := s.repo.Redeliver(webhook.ID, delivery.ID)
+
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), redelivered)
+	deliveries, _ := s.worker.ClaimDeliveries(10, time.Minute)
+	assert.Len(s.T(), deliveries, 1)
+	assert.Equal(s.T(), 1, deliveries[0].Attempts)
+}
+
+func (s *WebhookRepositoryTestSuite) TestRedeliver_OtherWebhook() {
+	webhook := s.createWebhook()
+	delivery := s.addDelivery(webhook, 1)
+
+	redelivered, err := s.repo.Redeliver(webhook.ID+1, delivery.ID)
+
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), redelivered)
+}
+
+func (s *WebhookRepositoryTestSuite) TestDelete() {
+	webhook := s.createWebhook()
+	s.addDelivery(webhook, 1)
+	deliveries, _ := s.worker.ClaimDeliveries(10, time.Minute)
+	s.worker.RecordAttempt(deliveries[0], &model.WebhookDeliveryAttempt{ResponseStatus: 500}, 10)
+
+	deleted, err := s.repo.Delete(webhook.ID)
+
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), deleted)
+	var count int64
+	s.unscoped.Model(&model.WebhookDeliveryAttempt{}).Count(&count)
+	assert.Zero(s.T(), count)
+	s.unscoped.Model(&model.WebhookDelivery{}).Count(&count)
+	assert.Zero(s.T(), count)
+}
@@ -0,0 +1,26 @@
+package router
+
+import (
+	"github.com/weeranieb/go-kit-base/src/internal/handler"
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type APIKeyRouter struct {
+	group fiber.Router
+}
+
+func NewAPIKeyRouter(group fiber.Router) *APIKeyRouter {
+	return &APIKeyRouter{group: group}
+}
+
+func (ar *APIKeyRouter) SetupAPIKeyRoutes(apiKeyHandler handler.APIKeyHandler, auth middleware.AuthMiddleware) {
+	// API key routes
+	apiKeys := ar.group.Group("/api-keys", auth.Handle)
+
+	apiKeys.Post("", auth.RequireScope(model.ScopeAPIKeysWrite), apiKeyHandler.CreateAPIKey)
+	apiKeys.Get("", auth.RequireScope(model.ScopeAPIKeysRead), apiKeyHandler.ListAPIKeys)
+	apiKeys.Delete("/:id", auth.RequireScope(model.ScopeAPIKeysWrite), apiKeyHandler.RevokeAPIKey)
+}
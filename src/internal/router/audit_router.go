@@ -0,0 +1,26 @@
+package router
+
+import (
+	"github.com/weeranieb/go-kit-base/src/internal/handler"
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuditRouter struct {
+	group fiber.Router
+}
+
+func NewAuditRouter(group fiber.Router) *AuditRouter {
+	return &AuditRouter{group: group}
+}
+
+func (ar *AuditRouter) SetupAuditRoutes(auditHandler handler.AuditHandler, auth middleware.AuthMiddleware) {
+	// Audit log routes, read-only
+	audit := ar.group.Group("/audit", auth.Handle, auth.RequireScope(model.ScopeAuditRead), auth.RequirePermission(model.PermissionAuditRead))
+
+	audit.Get("", auditHandler.ListEvents)
+	audit.Get("/export", auditHandler.ExportEvents)
+	audit.Get("/verify", auditHandler.VerifyLog)
+}
@@ -0,0 +1,57 @@
+package router
+
+import (
+	"github.com/weeranieb/go-kit-base/src/internal/handler"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AuthRouter struct {
+	group fiber.Router
+}
+
+func NewAuthRouter(group fiber.Router) *AuthRouter {
+	return &AuthRouter{group: group}
+}
+
+func (ar *AuthRouter) SetupAuthRoutes(
+	authHandler handler.AuthHandler,
+	passwordHandler handler.PasswordHandler,
+	emailVerificationHandler handler.EmailVerificationHandler,
+	sessionHandler handler.SessionHandler,
+	oidcHandler handler.OIDCHandler,
+	magicLinkHandler handler.MagicLinkHandler,
+	passkeyHandler handler.PasskeyHandler,
+	invitationHandler handler.InvitationHandler,
+	requireAuth fiber.Handler,
+) {
+	// Auth routes
+	auth := ar.group.Group("/auth")
+
+	auth.Post("/login", authHandler.Login)
+	auth.Post("/login/mfa", authHandler.LoginMFA)
+	auth.Post("/logout", requireAuth, sessionHandler.Logout)
+
+	// Password reset
+	auth.Post("/password/forgot", passwordHandler.ForgotPassword)
+	auth.Post("/password/reset", passwordHandler.ResetPassword)
+
+	// Email verification
+	auth.Post("/email/verify", emailVerificationHandler.VerifyEmail)
+
+	// Passwordless login
+	auth.Post("/magic-link", magicLinkHandler.SendLink)
+	auth.Post("/magic-link/verify", magicLinkHandler.Redeem)
+
+	// Passkey login
+	auth.Post("/passkeys/login/begin", passkeyHandler.BeginLogin)
+	auth.Post("/passkeys/login/finish", passkeyHandler.FinishLogin)
+
+	// Invitations
+	auth.Post("/invitations/accept", invitationHandler.AcceptInvitation)
+
+	// Sign-in with OpenID Connect providers
+	auth.Get("/oidc/providers", oidcHandler.ListProviders)
+	auth.Get("/oidc/:provider/login", oidcHandler.Login)
+	auth.Get("/oidc/:provider/callback", oidcHandler.Callback)
+}
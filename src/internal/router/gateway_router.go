@@ -0,0 +1,25 @@
+package router
+
+import (
+	"github.com/weeranieb/go-kit-base/src/internal/handler"
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type GatewayRouter struct {
+	group fiber.Router
+}
+
+func NewGatewayRouter(group fiber.Router) *GatewayRouter {
+	return &GatewayRouter{group: group}
+}
+
+func (gr *GatewayRouter) SetupGatewayRoutes(gatewayHandler handler.GatewayHandler, auth middleware.AuthMiddleware) {
+	// WebSocket gateway; presence channels show the users of the organization.
+	// Browsers cannot set headers on the handshake, so it carries a
+	// single-use ticket instead of credentials
+	gr.group.Post("/ws/ticket", auth.Handle, auth.RequireScope(model.ScopeUsersRead), gatewayHandler.IssueTicket)
+	gr.group.Get("/ws", gatewayHandler.Connect)
+}
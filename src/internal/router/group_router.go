@@ -0,0 +1,40 @@
+package router
+
+import (
+	"github.com/weeranieb/go-kit-base/src/internal/handler"
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type GroupRouter struct {
+	group fiber.Router
+}
+
+func NewGroupRouter(group fiber.Router) *GroupRouter {
+	return &GroupRouter{group: group}
+}
+
+func (gr *GroupRouter) SetupGroupRoutes(groupHandler handler.GroupHandler, auth middleware.AuthMiddleware) {
+	// Group routes; the groups of a user are a user route
+	groups := gr.group.Group("/groups", auth.Handle)
+
+	canRead := auth.RequireScope(model.ScopeGroupsRead)
+	canWrite := auth.RequireScope(model.ScopeGroupsWrite)
+	canManage := auth.RequirePermission(model.PermissionGroupsManage)
+
+	groups.Post("", canWrite, canManage, groupHandler.CreateGroup)
+	groups.Get("", canRead, groupHandler.ListGroups)
+	groups.Get("/:id", canRead, groupHandler.GetGroup)
+	groups.Put("/:id", canWrite, canManage, groupHandler.UpdateGroup)
+	groups.Delete("/:id", canWrite, canManage, groupHandler.DeleteGroup)
+
+	// Members
+	groups.Get("/:id/members", canRead, groupHandler.ListMembers)
+	groups.Post("/:id/members", canWrite, canManage, groupHandler.AddMembers)
+	groups.Delete("/:id/members", canWrite, canManage, groupHandler.RemoveMembers)
+
+	// Permissions
+	groups.Put("/:id/permissions", canWrite, canManage, groupHandler.SetPermissions)
+}
@@ -0,0 +1,27 @@
+package router
+
+import (
+	"github.com/weeranieb/go-kit-base/src/internal/handler"
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type InvitationRouter struct {
+	group fiber.Router
+}
+
+func NewInvitationRouter(group fiber.Router) *InvitationRouter {
+	return &InvitationRouter{group: group}
+}
+
+func (ir *InvitationRouter) SetupInvitationRoutes(invitationHandler handler.InvitationHandler, auth middleware.AuthMiddleware) {
+	// Invitation routes; accepting one is an auth route
+	invitations := ir.group.Group("/invitations", auth.Handle, auth.RequirePermission(model.PermissionInvitationsManage))
+
+	invitations.Post("", auth.RequireScope(model.ScopeInvitationsWrite), invitationHandler.CreateInvitation)
+	invitations.Get("", auth.RequireScope(model.ScopeInvitationsRead), invitationHandler.ListInvitations)
+	invitations.Post("/:id/resend", auth.RequireScope(model.ScopeInvitationsWrite), invitationHandler.ResendInvitation)
+	invitations.Delete("/:id", auth.RequireScope(model.ScopeInvitationsWrite), invitationHandler.RevokeInvitation)
+}
@@ -0,0 +1,31 @@
+package router
+
+import (
+	"github.com/weeranieb/go-kit-base/src/internal/handler"
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type JobRouter struct {
+	group fiber.Router
+}
+
+func NewJobRouter(group fiber.Router) *JobRouter {
+	return &JobRouter{group: group}
+}
+
+func (jr *JobRouter) SetupJobRoutes(jobHandler handler.JobHandler, auth middleware.AuthMiddleware) {
+	// Background job routes; jobs are enqueued by the application, not
+	// through the API
+	jobs := jr.group.Group("/jobs", auth.Handle, auth.RequirePermission(model.PermissionJobsManage))
+
+	canRead := auth.RequireScope(model.ScopeJobsRead)
+	canWrite := auth.RequireScope(model.ScopeJobsWrite)
+
+	jobs.Get("", canRead, jobHandler.ListJobs)
+	jobs.Get("/:id", canRead, jobHandler.GetJob)
+	jobs.Post("/:id/retry", canWrite, jobHandler.RetryJob)
+	jobs.Post("/:id/cancel", canWrite, jobHandler.CancelJob)
+}
@@ -0,0 +1,36 @@
+package router
+
+import (
+	"github.com/weeranieb/go-kit-base/src/internal/handler"
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type OAuthRouter struct {
+	group fiber.Router
+}
+
+func NewOAuthRouter(group fiber.Router) *OAuthRouter {
+	return &OAuthRouter{group: group}
+}
+
+func (or *OAuthRouter) SetupOAuthRoutes(idpHandler handler.IdentityProviderHandler, auth middleware.AuthMiddleware) {
+	// OpenID Connect provider routes
+	oauth := or.group.Group("/oauth")
+
+	oauth.Get("/jwks", idpHandler.JWKS)
+	oauth.Post("/token", idpHandler.Token)
+	oauth.Get("/userinfo", idpHandler.UserInfo)
+	oauth.Post("/userinfo", idpHandler.UserInfo)
+
+	// Called by the login page on behalf of the signed-in user
+	oauth.Get("/authorize", auth.Handle, idpHandler.Authorize)
+	oauth.Post("/authorize", auth.Handle, idpHandler.Authorize)
+
+	// Client management
+	oauth.Post("/clients", auth.Handle, auth.RequireScope(model.ScopeOAuthClientsWrite), idpHandler.CreateClient)
+	oauth.Get("/clients", auth.Handle, auth.RequireScope(model.ScopeOAuthClientsRead), idpHandler.ListClients)
+	oauth.Delete("/clients/:id", auth.Handle, auth.RequireScope(model.ScopeOAuthClientsWrite), idpHandler.RevokeClient)
+}
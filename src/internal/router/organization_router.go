@@ -0,0 +1,34 @@
+package router
+
+import (
+	"github.com/weeranieb/go-kit-base/src/internal/handler"
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type OrganizationRouter struct {
+	group fiber.Router
+}
+
+func NewOrganizationRouter(group fiber.Router) *OrganizationRouter {
+	return &OrganizationRouter{group: group}
+}
+
+func (or *OrganizationRouter) SetupOrganizationRoutes(organizationHandler handler.OrganizationHandler, auth middleware.AuthMiddleware) {
+	// Organization routes
+	organizations := or.group.Group("/organizations", auth.Handle)
+
+	canRead := auth.RequireScope(model.ScopeOrganizationsRead)
+	canWrite := auth.RequireScope(model.ScopeOrganizationsWrite)
+
+	organizations.Post("", canWrite, organizationHandler.CreateOrganization)
+	organizations.Get("", canRead, organizationHandler.ListOrganizations)
+
+	// Members
+	organizations.Get("/:id/members", canRead, organizationHandler.ListMembers)
+	organizations.Post("/:id/members", canWrite, organizationHandler.AddMember)
+	organizations.Put("/:id/members/:user_id", canWrite, organizationHandler.UpdateMember)
+	organizations.Delete("/:id/members/:user_id", canWrite, organizationHandler.RemoveMember)
+}
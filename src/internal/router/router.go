@@ -3,11 +3,13 @@ package router
 import (
 	"github.com/weeranieb/go-kit-base/src/internal/config"
 	"github.com/weeranieb/go-kit-base/src/internal/handler"
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/gofiber/fiber/v2/middleware/requestid"
 	fiberSwagger "github.com/swaggo/fiber-swagger"
 )
 
@@ -15,6 +17,7 @@ func NewRouter() *fiber.App {
 	app := fiber.New()
 
 	// Middleware
+	app.Use(requestid.New())
 	app.Use(logger.New())
 	app.Use(recover.New())
 	app.Use(cors.New())
@@ -22,7 +25,7 @@ func NewRouter() *fiber.App {
 	return app
 }
 
-func SetupRoutes(app *fiber.App, conf *config.Config, handler *handler.Handler) {
+func SetupRoutes(app *fiber.App, conf *config.Config, handler *handler.Handler, middleware *middleware.Middleware) {
 	// Swagger documentation
 	app.Get("/swagger/*", fiberSwagger.WrapHandler)
 
@@ -31,10 +34,58 @@ func SetupRoutes(app *fiber.App, conf *config.Config, handler *handler.Handler)
 		return c.JSON(fiber.Map{"status": "ok"})
 	})
 
-	// API routes
-	api := app.Group("/api/v1")
+	// API routes, scoped to the organization of the request
+	api := app.Group("/api/v1", middleware.Audit.Handle, middleware.Tenant.Handle, middleware.Idempotency.Handle)
 
 	// Setup user routes
 	userRouter := NewUserRouter(api)
-	userRouter.SetupUserRoutes(handler.UserHandler)
+	userRouter.SetupUserRoutes(handler.UserHandler, handler.PasswordHandler, handler.EmailVerificationHandler, handler.MFAHandler, handler.LockoutHandler, handler.SessionHandler, handler.OIDCHandler, handler.IdentityProviderHandler, handler.PasskeyHandler, handler.GroupHandler, handler.UserEventHandler, handler.PresenceHandler, middleware.Auth, middleware.Tenant, conf.Auth.SelfRegistration)
+
+	// Setup auth routes
+	authRouter := NewAuthRouter(api)
+	authRouter.SetupAuthRoutes(handler.AuthHandler, handler.PasswordHandler, handler.EmailVerificationHandler, handler.SessionHandler, handler.OIDCHandler, handler.MagicLinkHandler, handler.PasskeyHandler, handler.InvitationHandler, middleware.Auth.Handle)
+
+	// Setup API key routes
+	apiKeyRouter := NewAPIKeyRouter(api)
+	apiKeyRouter.SetupAPIKeyRoutes(handler.APIKeyHandler, middleware.Auth)
+
+	// Setup invitation routes
+	invitationRouter := NewInvitationRouter(api)
+	invitationRouter.SetupInvitationRoutes(handler.InvitationHandler, middleware.Auth)
+
+	// Setup organization routes
+	organizationRouter := NewOrganizationRouter(api)
+	organizationRouter.SetupOrganizationRoutes(handler.OrganizationHandler, middleware.Auth)
+
+	// Setup group routes
+	groupRouter := NewGroupRouter(api)
+	groupRouter.SetupGroupRoutes(handler.GroupHandler, middleware.Auth)
+
+	// Setup audit log routes
+	auditRouter := NewAuditRouter(api)
+	auditRouter.SetupAuditRoutes(handler.AuditHandler, middleware.Auth)
+
+	// Setup webhook routes
+	webhookRouter := NewWebhookRouter(api)
+	webhookRouter.SetupWebhookRoutes(handler.WebhookHandler, middleware.Auth)
+
+	// Setup job routes
+	jobRouter := NewJobRouter(api)
+	jobRouter.SetupJobRoutes(handler.JobHandler, middleware.Auth)
+
+	// Setup scheduled task routes
+	scheduleRouter := NewScheduleRouter(api)
+	scheduleRouter.SetupScheduleRoutes(handler.ScheduleHandler, middleware.Auth)
+
+	// Setup WebSocket gateway routes
+	gatewayRouter := NewGatewayRouter(api)
+	gatewayRouter.SetupGatewayRoutes(handler.GatewayHandler, middleware.Auth)
+
+	// Setup OpenID Connect provider routes when an issuer is configured
+	if conf.IdentityProvider.Issuer != "" {
+		app.Get("/.well-known/openid-configuration", handler.IdentityProviderHandler.Discovery)
+
+		oauthRouter := NewOAuthRouter(api)
+		oauthRouter.SetupOAuthRoutes(handler.IdentityProviderHandler, middleware.Auth)
+	}
 }
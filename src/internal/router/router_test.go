@@ -0,0 +1,105 @@
+package router
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	handlerMocks "github.com/weeranieb/go-kit-base/src/internal/handler/mocks/handler"
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
+	middlewareMocks "github.com/weeranieb/go-kit-base/src/internal/middleware/mocks/middleware"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	serviceMocks "github.com/weeranieb/go-kit-base/src/internal/service/mocks/service"
+)
+
+// Callers of the routes under test. Managers hold every permission, members
+// none; neither is a global admin.
+const (
+	managerToken = "manager-token"
+	memberToken  = "member-token"
+	managerID    = uint(1)
+	memberID     = uint(2)
+)
+
+// RouterTestSuite runs the routes with the real AuthMiddleware over mocked
+// services, to check which callers reach the handlers.
+type RouterTestSuite struct {
+	suite.Suite
+	sessionService           *serviceMocks.MockSessionService
+	apiKeyService            *serviceMocks.MockAPIKeyService
+	organizationService      *serviceMocks.MockOrganizationService
+	groupService             *serviceMocks.MockGroupService
+	auth                     middleware.AuthMiddleware
+	tenant                   *middlewareMocks.MockTenantMiddleware
+	userHandler              *handlerMocks.MockUserHandler
+	passwordHandler          *handlerMocks.MockPasswordHandler
+	emailVerificationHandler *handlerMocks.MockEmailVerificationHandler
+	mfaHandler               *handlerMocks.MockMFAHandler
+	lockoutHandler           *handlerMocks.MockLockoutHandler
+	sessionHandler           *handlerMocks.MockSessionHandler
+	oidcHandler              *handlerMocks.MockOIDCHandler
+	idpHandler               *handlerMocks.MockIdentityProviderHandler
+	passkeyHandler           *handlerMocks.MockPasskeyHandler
+	groupHandler             *handlerMocks.MockGroupHandler
+	userEventHandler         *handlerMocks.MockUserEventHandler
+	presenceHandler          *handlerMocks.MockPresenceHandler
+}
+
+func (s *RouterTestSuite) SetupTest() {
+	s.sessionService = serviceMocks.NewMockSessionService(s.T())
+	s.apiKeyService = serviceMocks.NewMockAPIKeyService(s.T())
+	s.organizationService = serviceMocks.NewMockOrganizationService(s.T())
+	s.groupService = serviceMocks.NewMockGroupService(s.T())
+	s.auth = middleware.NewAuthMiddleware(s.sessionService, s.apiKeyService, s.organizationService, s.groupService)
+	s.tenant = middlewareMocks.NewMockTenantMiddleware(s.T())
+	s.userHandler = handlerMocks.NewMockUserHandler(s.T())
+	s.passwordHandler = handlerMocks.NewMockPasswordHandler(s.T())
+	s.emailVerificationHandler = handlerMocks.NewMockEmailVerificationHandler(s.T())
+	s.mfaHandler = handlerMocks.NewMockMFAHandler(s.T())
+	s.lockoutHandler = handlerMocks.NewMockLockoutHandler(s.T())
+	s.sessionHandler = handlerMocks.NewMockSessionHandler(s.T())
+	s.oidcHandler = handlerMocks.NewMockOIDCHandler(s.T())
+	s.idpHandler = handlerMocks.NewMockIdentityProviderHandler(s.T())
+	s.passkeyHandler = handlerMocks.NewMockPasskeyHandler(s.T())
+	s.groupHandler = handlerMocks.NewMockGroupHandler(s.T())
+	s.userEventHandler = handlerMocks.NewMockUserEventHandler(s.T())
+	s.presenceHandler = handlerMocks.NewMockPresenceHandler(s.T())
+
+	s.sessionService.On("Authenticate", managerToken).Return(&model.Principal{UserID: managerID, SessionID: "session-1"}, nil).Maybe()
+	s.sessionService.On("Authenticate", memberToken).Return(&model.Principal{UserID: memberID, SessionID: "session-2"}, nil).Maybe()
+	s.groupService.On("HasPermission", mock.Anything, managerID, mock.AnythingOfType("string")).Return(true, nil).Maybe()
+	s.groupService.On("HasPermission", mock.Anything, memberID, mock.AnythingOfType("string")).Return(false, nil).Maybe()
+	s.tenant.On("RequireUser", mock.Anything).Return(func(c *fiber.Ctx) error { return c.Next() }).Maybe()
+}
+
+func (s *RouterTestSuite) newUserApp() *fiber.App {
+	app := fiber.New()
+	NewUserRouter(app.Group("/api/v1")).SetupUserRoutes(
+		s.userHandler, s.passwordHandler, s.emailVerificationHandler, s.mfaHandler, s.lockoutHandler, s.sessionHandler,
+		s.oidcHandler, s.idpHandler, s.passkeyHandler, s.groupHandler, s.userEventHandler, s.presenceHandler,
+		s.auth, s.tenant, true,
+	)
+	return app
+}
+
+// request sends an empty request with the access token and returns the
+// status of the response.
+func (s *RouterTestSuite) request(app *fiber.App, method, path, token string) int {
+	req := httptest.NewRequest(method, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := app.Test(req)
+	s.Require().NoError(err)
+	return resp.StatusCode
+}
+
+// reached makes a mocked handler answer with 200 OK.
+func reached(c *fiber.Ctx) error {
+	return c.SendStatus(fiber.StatusOK)
+}
+
+func TestRouterSuite(t *testing.T) {
+	suite.Run(t, new(RouterTestSuite))
+}
@@ -0,0 +1,25 @@
+package router
+
+import (
+	"github.com/weeranieb/go-kit-base/src/internal/handler"
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ScheduleRouter struct {
+	group fiber.Router
+}
+
+func NewScheduleRouter(group fiber.Router) *ScheduleRouter {
+	return &ScheduleRouter{group: group}
+}
+
+func (sr *ScheduleRouter) SetupScheduleRoutes(scheduleHandler handler.ScheduleHandler, auth middleware.AuthMiddleware) {
+	// Scheduled task routes; tasks are registered by the application
+	schedules := sr.group.Group("/admin/schedules", auth.Handle, auth.RequirePermission(model.PermissionSchedulesManage))
+
+	schedules.Get("", auth.RequireScope(model.ScopeSchedulesRead), scheduleHandler.ListSchedules)
+	schedules.Post("/:name/trigger", auth.RequireScope(model.ScopeSchedulesWrite), scheduleHandler.TriggerSchedule)
+}
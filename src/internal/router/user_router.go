@@ -2,6 +2,8 @@ package router
 
 import (
 	"github.com/weeranieb/go-kit-base/src/internal/handler"
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -14,17 +16,99 @@ func NewUserRouter(group fiber.Router) *UserRouter {
 	return &UserRouter{group: group}
 }
 
-func (ur *UserRouter) SetupUserRoutes(userHandler handler.UserHandler) {
+func (ur *UserRouter) SetupUserRoutes(
+	userHandler handler.UserHandler,
+	passwordHandler handler.PasswordHandler,
+	emailVerificationHandler handler.EmailVerificationHandler,
+	mfaHandler handler.MFAHandler,
+	lockoutHandler handler.LockoutHandler,
+	sessionHandler handler.SessionHandler,
+	oidcHandler handler.OIDCHandler,
+	idpHandler handler.IdentityProviderHandler,
+	passkeyHandler handler.PasskeyHandler,
+	groupHandler handler.GroupHandler,
+	userEventHandler handler.UserEventHandler,
+	presenceHandler handler.PresenceHandler,
+	auth middleware.AuthMiddleware,
+	tenant middleware.TenantMiddleware,
+	selfRegistration bool,
+) {
 	// User routes
 	users := ur.group.Group("/users")
 
+	canRead := auth.RequireScope(model.ScopeUsersRead)
+	canWrite := auth.RequireScope(model.ScopeUsersWrite)
+
+	// Registration is open unless self-registration is disabled, in which
+	// case only admins create accounts; everything else needs a session or
+	// an API key
+	if selfRegistration {
+		users.Post("", userHandler.CreateUser)
+	} else {
+		users.Post("", auth.Handle, canWrite, userHandler.CreateUser)
+	}
+
+	users.Use(auth.Handle)
+
+	// Live changes to the users of the organization
+	users.Get("/events", canRead, userEventHandler.StreamUserEvents)
+
+	// Routes on a single user only reach the users of the organization
+	users.Use("/:id", tenant.RequireUser)
+
 	// User CRUD operations
-	users.Post("", userHandler.CreateUser)
-	users.Get("/:id", userHandler.GetUser)
-	users.Put("/:id", userHandler.UpdateUser)
-	users.Delete("/:id", userHandler.DeleteUser)
-	users.Get("", userHandler.ListUsers)
-
-	users.Get("/:id/profile", userHandler.GetUserProfile)
-	users.Put("/:id/profile", userHandler.UpdateUserProfile)
+	users.Get("/:id", canRead, userHandler.GetUser)
+	users.Put("/:id", canWrite, userHandler.UpdateUser)
+	users.Patch("/:id", canWrite, userHandler.PatchUser)
+	users.Delete("/:id", canWrite, userHandler.DeleteUser)
+	users.Get("", canRead, userHandler.ListUsers)
+
+	// History of changes
+	users.Get("/:id/history", canRead, userHandler.ListUserHistory)
+	users.Post("/:id/history/:version/revert", canWrite, userHandler.RevertUser)
+
+	users.Get("/:id/profile", canRead, userHandler.GetUserProfile)
+	users.Put("/:id/profile", canWrite, userHandler.UpdateUserProfile)
+
+	// Only users change their own password, as it takes the current one
+	users.Post("/:id/password", canWrite, auth.RequireSelf, passwordHandler.ChangePassword)
+	users.Post("/:id/email/verification", canWrite, emailVerificationHandler.ResendVerification)
+
+	// Credentials are managed by the user or by those who manage users
+	selfOrManager := auth.RequireSelfOrPermission(model.PermissionUsersManage)
+
+	// MFA
+	users.Post("/:id/mfa/enroll", canWrite, selfOrManager, mfaHandler.Enroll)
+	users.Post("/:id/mfa/confirm", canWrite, selfOrManager, mfaHandler.Confirm)
+	users.Post("/:id/mfa/recovery-codes", canWrite, selfOrManager, mfaHandler.RegenerateRecoveryCodes)
+	users.Delete("/:id/mfa", canWrite, selfOrManager, mfaHandler.Disable)
+
+	// Login lockout, for those who manage users
+	manageUsers := auth.RequirePermission(model.PermissionUsersManage)
+	users.Post("/:id/unlock", canWrite, manageUsers, lockoutHandler.Unlock)
+	users.Get("/:id/login-attempts", canRead, manageUsers, lockoutHandler.ListLoginAttempts)
+
+	// Sessions
+	users.Get("/:id/sessions", canRead, selfOrManager, sessionHandler.ListSessions)
+	users.Delete("/:id/sessions", canWrite, selfOrManager, sessionHandler.RevokeAllSessions)
+	users.Delete("/:id/sessions/:sid", canWrite, selfOrManager, sessionHandler.RevokeSession)
+
+	// Linked identity provider accounts
+	users.Get("/:id/identities", canRead, selfOrManager, oidcHandler.ListIdentities)
+
+	// Clients the user has consented to
+	users.Get("/:id/consents", canRead, selfOrManager, idpHandler.ListConsents)
+	users.Delete("/:id/consents/:client_id", canWrite, selfOrManager, idpHandler.RevokeConsent)
+
+	// Passkeys are registered and removed by their user only
+	users.Get("/:id/passkeys", canRead, selfOrManager, passkeyHandler.ListPasskeys)
+	users.Post("/:id/passkeys/register/begin", canWrite, auth.RequireSelf, passkeyHandler.BeginRegistration)
+	users.Post("/:id/passkeys/register/finish", canWrite, auth.RequireSelf, passkeyHandler.FinishRegistration)
+	users.Delete("/:id/passkeys/:passkey_id", canWrite, auth.RequireSelf, passkeyHandler.DeletePasskey)
+
+	// Groups, including those inherited through subgroups
+	users.Get("/:id/groups", auth.RequireScope(model.ScopeGroupsRead), groupHandler.ListUserGroups)
+
+	// Whether the user is connected to the gateway
+	users.Get("/:id/presence", canRead, presenceHandler.GetPresence)
 }
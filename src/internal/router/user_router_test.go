@@ -0,0 +1,51 @@
+package router
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func (s *RouterTestSuite) TestChangePassword_OnlyOwnAccount() {
+	s.passwordHandler.On("ChangePassword", mock.Anything).Return(reached)
+	app := s.newUserApp()
+
+	assert.Equal(s.T(), fiber.StatusOK, s.request(app, "POST", "/api/v1/users/2/password", memberToken))
+	assert.Equal(s.T(), fiber.StatusForbidden, s.request(app, "POST", "/api/v1/users/1/password", memberToken))
+	// Managing users does not give access to their password
+	assert.Equal(s.T(), fiber.StatusForbidden, s.request(app, "POST", "/api/v1/users/2/password", managerToken))
+	s.passwordHandler.AssertNumberOfCalls(s.T(), "ChangePassword", 1)
+}
+
+func (s *RouterTestSuite) TestLockout_NeedsUsersManage() {
+	s.lockoutHandler.On("Unlock", mock.Anything).Return(reached)
+	s.lockoutHandler.On("ListLoginAttempts", mock.Anything).Return(reached)
+	app := s.newUserApp()
+
+	// Not even on their own account, or members could lift their lockout
+	assert.Equal(s.T(), fiber.StatusForbidden, s.request(app, "POST", "/api/v1/users/2/unlock", memberToken))
+	assert.Equal(s.T(), fiber.StatusForbidden, s.request(app, "GET", "/api/v1/users/2/login-attempts", memberToken))
+	assert.Equal(s.T(), fiber.StatusOK, s.request(app, "POST", "/api/v1/users/2/unlock", managerToken))
+	assert.Equal(s.T(), fiber.StatusOK, s.request(app, "GET", "/api/v1/users/2/login-attempts", managerToken))
+}
+
+func (s *RouterTestSuite) TestListIdentities_SelfOrManager() {
+	s.oidcHandler.On("ListIdentities", mock.Anything).Return(reached)
+	app := s.newUserApp()
+
+	assert.Equal(s.T(), fiber.StatusOK, s.request(app, "GET", "/api/v1/users/2/identities", memberToken))
+	assert.Equal(s.T(), fiber.StatusForbidden, s.request(app, "GET", "/api/v1/users/1/identities", memberToken))
+	assert.Equal(s.T(), fiber.StatusOK, s.request(app, "GET", "/api/v1/users/2/identities", managerToken))
+}
+
+func (s *RouterTestSuite) TestConsents_SelfOrManager() {
+	s.idpHandler.On("ListConsents", mock.Anything).Return(reached)
+	s.idpHandler.On("RevokeConsent", mock.Anything).Return(reached)
+	app := s.newUserApp()
+
+	assert.Equal(s.T(), fiber.StatusOK, s.request(app, "GET", "/api/v1/users/2/consents", memberToken))
+	assert.Equal(s.T(), fiber.StatusOK, s.request(app, "DELETE", "/api/v1/users/2/consents/client-1", memberToken))
+	assert.Equal(s.T(), fiber.StatusForbidden, s.request(app, "GET", "/api/v1/users/1/consents", memberToken))
+	assert.Equal(s.T(), fiber.StatusForbidden, s.request(app, "DELETE", "/api/v1/users/1/consents/client-1", memberToken))
+	assert.Equal(s.T(), fiber.StatusOK, s.request(app, "DELETE", "/api/v1/users/2/consents/client-1", managerToken))
+}
@@ -0,0 +1,37 @@
+package router
+
+import (
+	"github.com/weeranieb/go-kit-base/src/internal/handler"
+	"github.com/weeranieb/go-kit-base/src/internal/middleware"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type WebhookRouter struct {
+	group fiber.Router
+}
+
+func NewWebhookRouter(group fiber.Router) *WebhookRouter {
+	return &WebhookRouter{group: group}
+}
+
+func (wr *WebhookRouter) SetupWebhookRoutes(webhookHandler handler.WebhookHandler, auth middleware.AuthMiddleware) {
+	// Webhook routes; webhooks reveal where events go, so reading them
+	// needs the permission too
+	webhooks := wr.group.Group("/webhooks", auth.Handle, auth.RequirePermission(model.PermissionWebhooksManage))
+
+	canRead := auth.RequireScope(model.ScopeWebhooksRead)
+	canWrite := auth.RequireScope(model.ScopeWebhooksWrite)
+
+	webhooks.Post("", canWrite, webhookHandler.CreateWebhook)
+	webhooks.Get("", canRead, webhookHandler.ListWebhooks)
+	webhooks.Get("/:id", canRead, webhookHandler.GetWebhook)
+	webhooks.Put("/:id", canWrite, webhookHandler.UpdateWebhook)
+	webhooks.Delete("/:id", canWrite, webhookHandler.DeleteWebhook)
+
+	// Delivery log
+	webhooks.Get("/:id/deliveries", canRead, webhookHandler.ListDeliveries)
+	webhooks.Get("/:id/deliveries/:delivery_id", canRead, webhookHandler.GetDelivery)
+	webhooks.Post("/:id/deliveries/:delivery_id/redeliver", canWrite, webhookHandler.Redeliver)
+}
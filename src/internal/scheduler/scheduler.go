@@ -0,0 +1,297 @@
+// Package scheduler runs periodic tasks on cron schedules. Tasks are
+// provided to the container in the TaskGroup value group. Every replica
+// runs a Scheduler, but only the one holding the scheduler lease runs
+// tasks, so each run happens once however many replicas there are. The
+// schedules are stored, so a new leader picks up where the last one left
+// off, and the last run of each task and its outcome can be inspected.
+package scheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	mathrand "math/rand/v2"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/cron"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+
+	"go.uber.org/dig"
+)
+
+// TaskGroup is the dig value group the Scheduler collects its tasks from.
+const TaskGroup = "scheduled_tasks"
+
+// leaseName is the lease held by the replica that runs the tasks.
+const leaseName = "scheduler"
+
+// MissedRunPolicy decides what happens to a run that is due while no
+// replica is running tasks, such as during a deploy.
+type MissedRunPolicy int
+
+const (
+	// RunOnce runs a missed task as soon as possible, once however many
+	// runs it missed.
+	RunOnce MissedRunPolicy = iota
+	// Skip records a run missed by more than the scheduler allows as
+	// skipped and waits for the next one.
+	Skip
+)
+
+// Task is work run on a schedule. Schedule is a cron expression, matched in
+// UTC; each run starts up to Jitter after its scheduled time, so tasks on
+// the same schedule do not all start at once. A run is not started while
+// the last one is still going.
+type Task struct {
+	Name      string
+	Schedule  string
+	Jitter    time.Duration
+	MissedRun MissedRunPolicy
+	Run       func(ctx context.Context) error
+}
+
+// Scheduler runs tasks when they are due while it holds the scheduler
+// lease.
+type Scheduler struct {
+	scheduleRepo repository.ScheduleRepository
+	leaseRepo    repository.LeaseRepository
+	tasks        map[string]*task
+	holder       string
+	conf         config.SchedulerConfig
+
+	// leader is cancelled when the lease is lost, stopping the runs
+	// started under it; it is nil while another replica holds the lease
+	leader context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+type task struct {
+	Task
+	schedule *cron.Schedule
+}
+
+type SchedulerParams struct {
+	dig.In
+
+	ScheduleRepo repository.ScheduleRepository
+	LeaseRepo    repository.LeaseRepository
+	Tasks        []Task `group:"scheduled_tasks"`
+	Conf         *config.Config
+}
+
+func NewScheduler(params SchedulerParams) (*Scheduler, error) {
+	tasks := make(map[string]*task, len(params.Tasks))
+	for _, t := range params.Tasks {
+		if _, ok := tasks[t.Name]; ok {
+			return nil, fmt.Errorf("scheduled task %q is provided more than once", t.Name)
+		}
+		schedule, err := cron.Parse(t.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("scheduled task %q: %w", t.Name, err)
+		}
+		if schedule.Next(time.Now()).IsZero() {
+			return nil, fmt.Errorf("scheduled task %q: %q never matches", t.Name, t.Schedule)
+		}
+		tasks[t.Name] = &task{Task: t, schedule: schedule}
+	}
+
+	holder, err := newHolder()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Scheduler{
+		scheduleRepo: params.ScheduleRepo,
+		leaseRepo:    params.LeaseRepo,
+		tasks:        tasks,
+		holder:       holder,
+		conf:         params.Conf.Scheduler,
+	}, nil
+}
+
+// newHolder names this process in the lease: its host name, told apart
+// from other processes on the host by a random suffix.
+func newHolder() (string, error) {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	return host + "-" + hex.EncodeToString(suffix), nil
+}
+
+// Run checks for due tasks every poll interval until ctx is done. It then
+// gives up the lease, so another replica can take over at once, and waits
+// for the runs in progress, which are cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.conf.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Tick(ctx); err != nil {
+			log.Printf("Failed to run scheduled tasks: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.stop(context.WithoutCancel(ctx))
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Tick renews or takes the scheduler lease and, while it is held, starts
+// the tasks that are due. Runs go on in the background.
+func (s *Scheduler) Tick(ctx context.Context) error {
+	acquired, err := s.leaseRepo.WithContext(ctx).Acquire(leaseName, s.holder, s.conf.LeaseTTL)
+	if err != nil {
+		// The lease may run out before it can be renewed, so stop as if
+		// it had
+		s.demote()
+		return err
+	}
+	if !acquired {
+		s.demote()
+		return nil
+	}
+
+	if s.leader == nil {
+		if err := s.register(ctx); err != nil {
+			return err
+		}
+		log.Printf("Scheduler %s is running scheduled tasks", s.holder)
+		s.leader, s.cancel = context.WithCancel(context.WithoutCancel(ctx))
+	}
+
+	schedules, err := s.scheduleRepo.WithContext(ctx).List()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, schedule := range schedules {
+		t, ok := s.tasks[schedule.Name]
+		if !ok || schedule.RunningSince != nil || schedule.NextRunAt.After(now) {
+			continue
+		}
+		if err := s.dispatch(ctx, t, now.Sub(schedule.NextRunAt)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// register stores the schedules of the tasks, on becoming the leader.
+func (s *Scheduler) register(ctx context.Context) error {
+	now := time.Now()
+	for _, t := range s.tasks {
+		err := s.scheduleRepo.WithContext(ctx).Register(&model.Schedule{
+			Name:       t.Name,
+			Expression: t.Schedule,
+			NextRunAt:  s.next(t, now),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dispatch starts a run of a due task, or skips it if it is too late.
+func (s *Scheduler) dispatch(ctx context.Context, t *task, late time.Duration) error {
+	scheduleRepo := s.scheduleRepo.WithContext(ctx)
+	next := s.next(t, time.Now())
+
+	if t.MissedRun == Skip && late > s.conf.MissedAfter {
+		skipped, err := scheduleRepo.Skip(t.Name, next, fmt.Sprintf("run was missed by %s", late.Round(time.Second)))
+		if err == nil && skipped {
+			log.Printf("Skipped a missed run of scheduled task %s", t.Name)
+		}
+		return err
+	}
+
+	started, err := scheduleRepo.Start(t.Name, next)
+	if err != nil || !started {
+		return err
+	}
+
+	leader := s.leader
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run(leader, t)
+	}()
+	return nil
+}
+
+// run runs the task and records the outcome, turning a panic into a
+// failure.
+func (s *Scheduler) run(ctx context.Context, t *task) {
+	startedAt := time.Now()
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("task panicked: %v", r)
+			}
+		}()
+		return t.Run(ctx)
+	}()
+	duration := time.Since(startedAt)
+
+	status, lastError := model.ScheduleSucceeded, ""
+	if err != nil {
+		log.Printf("Scheduled task %s failed: %v", t.Name, err)
+		status, lastError = model.ScheduleFailed, err.Error()
+	}
+
+	// The outcome is stored even when the run was cancelled
+	err = s.scheduleRepo.WithContext(context.WithoutCancel(ctx)).Finish(t.Name, status, lastError, startedAt, duration)
+	if err != nil {
+		log.Printf("Failed to record the run of scheduled task %s: %v", t.Name, err)
+	}
+}
+
+// next returns when the task next runs after now, with jitter.
+func (s *Scheduler) next(t *task, now time.Time) time.Time {
+	next := t.schedule.Next(now)
+	if t.Jitter > 0 {
+		next = next.Add(mathrand.N(t.Jitter))
+	}
+	return next
+}
+
+// demote cancels the runs started while holding the lease, which another
+// replica may now hold.
+func (s *Scheduler) demote() {
+	if s.leader == nil {
+		return
+	}
+	log.Printf("Scheduler %s lost the lease and stopped running scheduled tasks", s.holder)
+	s.cancel()
+	s.leader, s.cancel = nil, nil
+}
+
+// stop cancels the runs in progress, waits for them and gives up the lease.
+func (s *Scheduler) stop(ctx context.Context) {
+	if s.leader == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+	s.leader, s.cancel = nil, nil
+
+	if err := s.leaseRepo.WithContext(ctx).Release(leaseName, s.holder); err != nil {
+		log.Printf("Failed to release the scheduler lease: %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	mocks "github.com/weeranieb/go-kit-base/src/internal/repository/mocks/repository"
+)
+
+type SchedulerTestSuite struct {
+	suite.Suite
+	scheduleRepo *mocks.MockScheduleRepository
+	leaseRepo    *mocks.MockLeaseRepository
+	conf         *config.Config
+	// run is the Run of the "test.run" task
+	run func(ctx context.Context) error
+}
+
+func (s *SchedulerTestSuite) SetupTest() {
+	s.scheduleRepo = mocks.NewMockScheduleRepository(s.T())
+	s.scheduleRepo.On("WithContext", mock.Anything).Return(s.scheduleRepo).Maybe()
+	s.leaseRepo = mocks.NewMockLeaseRepository(s.T())
+	s.leaseRepo.On("WithContext", mock.Anything).Return(s.leaseRepo).Maybe()
+	s.conf = &config.Config{Scheduler: config.SchedulerConfig{
+		PollInterval: 10 * time.Millisecond,
+		LeaseTTL:     time.Minute,
+		MissedAfter:  5 * time.Minute,
+	}}
+	s.run = func(ctx context.Context) error { return nil }
+}
+
+func TestSchedulerSuite(t *testing.T) {
+	suite.Run(t, new(SchedulerTestSuite))
+}
+
+func (s *SchedulerTestSuite) newScheduler(policy MissedRunPolicy) *Scheduler {
+	scheduler, err := NewScheduler(SchedulerParams{
+		ScheduleRepo: s.scheduleRepo,
+		LeaseRepo:    s.leaseRepo,
+		Tasks: []Task{{
+			Name:      "test.run",
+			Schedule:  "@hourly",
+			MissedRun: policy,
+			Run:       func(ctx context.Context) error { return s.run(ctx) },
+		}},
+		Conf: s.conf,
+	})
+	if err != nil {
+		s.T().Fatal("Failed to create scheduler:", err)
+	}
+	return scheduler
+}
+
+// lead makes the scheduler the leader with the test task due since due.
+func (s *SchedulerTestSuite) lead(due time.Time) {
+	s.leaseRepo.On("Acquire", "scheduler", mock.Anything, time.Minute).Return(true, nil).Once()
+	s.scheduleRepo.On("Register", mock.MatchedBy(func(schedule *model.Schedule) bool {
+		return schedule.Name == "test.run" && schedule.Expression == "@hourly"
+	})).Return(nil).Once()
+	s.scheduleRepo.On("List").Return([]*model.Schedule{{Name: "test.run", NextRunAt: due}}, nil).Once()
+}
+
+func (s *SchedulerTestSuite) TestNewScheduler_Invalid() {
+	run := func(ctx context.Context) error { return nil }
+	for _, tasks := range [][]Task{
+		{{Name: "a", Schedule: "@hourly", Run: run}, {Name: "a", Schedule: "@daily", Run: run}},
+		{{Name: "a", Schedule: "61 * * * *", Run: run}},
+		{{Name: "a", Schedule: "0 0 31 2 *", Run: run}},
+	} {
+		_, err := NewScheduler(SchedulerParams{ScheduleRepo: s.scheduleRepo, LeaseRepo: s.leaseRepo, Tasks: tasks, Conf: s.conf})
+		assert.Error(s.T(), err)
+	}
+}
+
+func (s *SchedulerTestSuite) TestTick_NotLeader() {
+	scheduler := s.newScheduler(RunOnce)
+	s.leaseRepo.On("Acquire", "scheduler", scheduler.holder, time.Minute).Return(false, nil)
+
+	err := scheduler.Tick(context.Background())
+
+	assert.NoError(s.T(), err)
+	s.scheduleRepo.AssertNotCalled(s.T(), "List")
+}
+
+func (s *SchedulerTestSuite) TestTick_RunsDueTask() {
+	scheduler := s.newScheduler(RunOnce)
+	s.lead(time.Now().Add(-time.Second))
+	s.scheduleRepo.On("Start", "test.run", mock.MatchedBy(func(next time.Time) bool {
+		return next.After(time.Now()) && next.Minute() == 0
+	})).Return(true, nil)
+	s.scheduleRepo.On("Finish", "test.run", model.ScheduleSucceeded, "", mock.Anything, mock.Anything).Return(nil)
+	ran := false
+	s.run = func(ctx context.Context) error {
+		ran = true
+		return nil
+	}
+
+	err := scheduler.Tick(context.Background())
+	scheduler.wg.Wait()
+
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), ran)
+}
+
+func (s *SchedulerTestSuite) TestTick_SkipsRunningAndNotDue() {
+	scheduler := s.newScheduler(RunOnce)
+	s.leaseRepo.On("Acquire", "scheduler", scheduler.holder, time.Minute).Return(true, nil)
+	s.scheduleRepo.On("Register", mock.Anything).Return(nil)
+	now := time.Now()
+	s.scheduleRepo.On("List").Return([]*model.Schedule{
+		{Name: "test.run", NextRunAt: now.Add(time.Minute)},
+		{Name: "test.run", NextRunAt: now.Add(-time.Minute), RunningSince: &now},
+		// No longer provided by the application
+		{Name: "old.task", NextRunAt: now.Add(-time.Minute)},
+	}, nil)
+
+	err := scheduler.Tick(context.Background())
+
+	assert.NoError(s.T(), err)
+	s.scheduleRepo.AssertNotCalled(s.T(), "Start", mock.Anything, mock.Anything)
+}
+
+func (s *SchedulerTestSuite) TestTick_Failed() {
+	scheduler := s.newScheduler(RunOnce)
+	s.lead(time.Now().Add(-time.Hour))
+	s.scheduleRepo.On("Start", "test.run", mock.Anything).Return(true, nil)
+	s.scheduleRepo.On("Finish", "test.run", model.ScheduleFailed, "task panicked: boom", mock.Anything, mock.Anything).Return(nil)
+	s.run = func(ctx context.Context) error { panic("boom") }
+
+	err := scheduler.Tick(context.Background())
+	scheduler.wg.Wait()
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *SchedulerTestSuite) TestTick_SkipsMissedRun() {
+	scheduler := s.newScheduler(Skip)
+	s.lead(time.Now().Add(-time.Hour))
+	s.scheduleRepo.On("Skip", "test.run", mock.Anything, mock.MatchedBy(func(reason string) bool {
+		return reason == "run was missed by 1h0m0s"
+	})).Return(true, nil)
+	s.run = func(ctx context.Context) error {
+		s.T().Error("Missed run was not skipped")
+		return nil
+	}
+
+	err := scheduler.Tick(context.Background())
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *SchedulerTestSuite) TestTick_LostLease() {
+	scheduler := s.newScheduler(RunOnce)
+	s.lead(time.Now().Add(-time.Second))
+	s.scheduleRepo.On("Start", "test.run", mock.Anything).Return(true, nil)
+	s.scheduleRepo.On("Finish", "test.run", model.ScheduleFailed, context.Canceled.Error(), mock.Anything, mock.Anything).Return(nil)
+	started := make(chan struct{})
+	s.run = func(ctx context.Context) error {
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	assert.NoError(s.T(), scheduler.Tick(context.Background()))
+	<-started
+
+	// Another replica took over, so the run is cancelled
+	s.leaseRepo.On("Acquire", "scheduler", scheduler.holder, time.Minute).Return(false, nil).Once()
+	assert.NoError(s.T(), scheduler.Tick(context.Background()))
+	scheduler.wg.Wait()
+}
+
+func (s *SchedulerTestSuite) TestTick_LeaseError() {
+	scheduler := s.newScheduler(RunOnce)
+	s.leaseRepo.On("Acquire", "scheduler", scheduler.holder, time.Minute).Return(false, errors.New("database is down"))
+
+	err := scheduler.Tick(context.Background())
+
+	assert.Error(s.T(), err)
+	assert.Nil(s.T(), scheduler.leader)
+}
+
+func (s *SchedulerTestSuite) TestRun_ReleasesLease() {
+	scheduler := s.newScheduler(RunOnce)
+	s.leaseRepo.On("Acquire", "scheduler", scheduler.holder, time.Minute).Return(true, nil)
+	s.scheduleRepo.On("Register", mock.Anything).Return(nil)
+	s.scheduleRepo.On("List").Return([]*model.Schedule{}, nil)
+	s.leaseRepo.On("Release", "scheduler", scheduler.holder).Return(nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	scheduler.Run(ctx)
+
+	s.leaseRepo.AssertCalled(s.T(), "Release", "scheduler", scheduler.holder)
+}
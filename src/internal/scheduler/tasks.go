@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+)
+
+// NewIdempotencyCleanupTask returns a task that deletes expired idempotency
+// keys every hour.
+func NewIdempotencyCleanupTask(idempotencyRepo repository.IdempotencyRepository) Task {
+	return Task{
+		Name:     "idempotency.cleanup",
+		Schedule: "@hourly",
+		Jitter:   time.Minute,
+		Run: func(context.Context) error {
+			deleted, err := idempotencyRepo.DeleteExpired(time.Now())
+			if err != nil {
+				return err
+			}
+			log.Printf("Deleted %d expired idempotency keys", deleted)
+			return nil
+		},
+	}
+}
+
+// NewJobRetentionTask returns a task that deletes the jobs that finished
+// longer ago than the job retention, every day.
+func NewJobRetentionTask(jobRepo repository.JobRepository, conf *config.Config) Task {
+	retention := conf.Jobs.Retention
+	return Task{
+		Name:     "jobs.retention",
+		Schedule: "@daily",
+		Jitter:   5 * time.Minute,
+		Run: func(ctx context.Context) error {
+			deleted, err := jobRepo.WithContext(tenant.Unscoped(ctx)).DeleteFinished(time.Now().Add(-retention))
+			if err != nil {
+				return err
+			}
+			log.Printf("Deleted %d finished jobs older than %s", deleted, retention)
+			return nil
+		},
+	}
+}
+
+// NewGatewayTicketCleanupTask returns a task that deletes expired gateway
+// tickets every hour.
+func NewGatewayTicketCleanupTask(ticketRepo repository.GatewayTicketRepository) Task {
+	return Task{
+		Name:     "gateway_tickets.cleanup",
+		Schedule: "@hourly",
+		Jitter:   time.Minute,
+		Run: func(ctx context.Context) error {
+			deleted, err := ticketRepo.WithContext(tenant.Unscoped(ctx)).DeleteExpired(time.Now())
+			if err != nil {
+				return err
+			}
+			log.Printf("Deleted %d expired gateway tickets", deleted)
+			return nil
+		},
+	}
+}
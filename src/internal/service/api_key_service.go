@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidAPIKey       = errors.New("invalid, expired or revoked API key")
+	ErrAPIKeyNotFound      = errors.New("API key not found")
+	ErrAPIKeyOwnerNotFound = errors.New("user not found")
+	ErrAPIKeyExpiry        = errors.New("expires_at must be in the future")
+	ErrScopeNotGranted     = errors.New("cannot grant scopes the caller does not have")
+	ErrAPIKeyForbidden     = errors.New("not allowed to manage the API keys of this owner")
+)
+
+// apiKeyPrefixEncoding encodes the random part of a key prefix.
+var apiKeyPrefixEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=APIKeyService --output=./mocks/service --outpkg=service --filename=api_key_service.go --structname=MockAPIKeyService --with-expecter=false
+type APIKeyService interface {
+	Create(ctx context.Context, caller *model.Principal, req *model.CreateAPIKeyRequest) (*model.CreateAPIKeyResponse, error)
+	ListForUser(ctx context.Context, caller *model.Principal, userID uint) ([]*model.APIKeyResponse, error)
+	ListForService(ctx context.Context, caller *model.Principal, service string) ([]*model.APIKeyResponse, error)
+	Revoke(ctx context.Context, caller *model.Principal, id uint) error
+	RevokeAllForUser(userID uint) error
+	Authenticate(key string) (*model.Principal, error)
+}
+
+type apiKeyService struct {
+	apiKeyRepo       repository.APIKeyRepository
+	userRepo         repository.UserRepository
+	groupService     GroupService
+	lastUsedInterval time.Duration
+}
+
+func NewAPIKeyService(
+	apiKeyRepo repository.APIKeyRepository,
+	userRepo repository.UserRepository,
+	groupService GroupService,
+	conf *config.Config,
+) APIKeyService {
+	return &apiKeyService{
+		apiKeyRepo:       apiKeyRepo,
+		userRepo:         userRepo,
+		groupService:     groupService,
+		lastUsedInterval: conf.Auth.LastSeenInterval,
+	}
+}
+
+// Create issues a new key. The full key is only part of the response; the
+// caller cannot grant scopes it does not have itself, and only issues keys
+// to owners it may manage, see authorize.
+func (s *apiKeyService) Create(ctx context.Context, caller *model.Principal, req *model.CreateAPIKeyRequest) (*model.CreateAPIKeyResponse, error) {
+	for _, scope := range req.Scopes {
+		if !caller.HasScope(scope) {
+			return nil, ErrScopeNotGranted
+		}
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrAPIKeyExpiry
+	}
+
+	if err := s.authorize(ctx, caller, req.UserID); err != nil {
+		return nil, err
+	}
+
+	prefix, err := newAPIKeyPrefix()
+	if err != nil {
+		return nil, err
+	}
+	secret, secretHash, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &model.APIKey{
+		UserID:     req.UserID,
+		Name:       req.Name,
+		Prefix:     prefix,
+		SecretHash: secretHash,
+		Scopes:     model.ScopeList(req.Scopes),
+		ExpiresAt:  req.ExpiresAt,
+	}
+	if req.UserID == nil {
+		// Service keys only act in the organization they are created in
+		organizationID, ok := tenant.OrganizationFromContext(ctx)
+		if !ok {
+			return nil, tenant.ErrNoOrganization
+		}
+		key.Service = req.Service
+		key.ServiceOrganizationID = &organizationID
+	}
+
+	if err := s.apiKeyRepo.Create(key); err != nil {
+		return nil, err
+	}
+
+	return &model.CreateAPIKeyResponse{
+		APIKeyResponse: *toAPIKeyResponse(key),
+		Key:            prefix + "_" + secret,
+	}, nil
+}
+
+func (s *apiKeyService) ListForUser(ctx context.Context, caller *model.Principal, userID uint) ([]*model.APIKeyResponse, error) {
+	if err := s.authorize(ctx, caller, &userID); err != nil {
+		return nil, err
+	}
+
+	keys, err := s.apiKeyRepo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	return toAPIKeyResponses(keys), nil
+}
+
+func (s *apiKeyService) ListForService(ctx context.Context, caller *model.Principal, service string) ([]*model.APIKeyResponse, error) {
+	if err := s.authorize(ctx, caller, nil); err != nil {
+		return nil, err
+	}
+
+	keys, err := s.apiKeyRepo.ListByService(service)
+	if err != nil {
+		return nil, err
+	}
+
+	// Service keys only see the keys of their own organization
+	if caller.UserID == 0 {
+		visible := keys[:0]
+		for _, key := range keys {
+			if boundTo(key, caller.OrganizationID) {
+				visible = append(visible, key)
+			}
+		}
+		keys = visible
+	}
+	return toAPIKeyResponses(keys), nil
+}
+
+// Revoke revokes a key of an owner the caller may manage. Keys of users of
+// other organizations are not found, nor are the service keys of other
+// organizations when the caller is a service key.
+func (s *apiKeyService) Revoke(ctx context.Context, caller *model.Principal, id uint) error {
+	key, err := s.apiKeyRepo.GetByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if caller.UserID == 0 && key.UserID == nil && !boundTo(key, caller.OrganizationID) {
+		return ErrAPIKeyNotFound
+	}
+
+	err = s.authorize(ctx, caller, key.UserID)
+	if errors.Is(err, ErrAPIKeyOwnerNotFound) {
+		return ErrAPIKeyNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	revoked, err := s.apiKeyRepo.Revoke(id, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (s *apiKeyService) RevokeAllForUser(userID uint) error {
+	return s.apiKeyRepo.RevokeAllForUser(userID, time.Now())
+}
+
+// Authenticate checks a key of the form <prefix>_<secret> and records its
+// use now and then.
+func (s *apiKeyService) Authenticate(key string) (*model.Principal, error) {
+	if !strings.HasPrefix(key, model.APIKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+	prefixPart, secret, ok := strings.Cut(strings.TrimPrefix(key, model.APIKeyPrefix), "_")
+	if !ok || secret == "" {
+		return nil, ErrInvalidAPIKey
+	}
+
+	apiKey, err := s.apiKeyRepo.GetByPrefix(model.APIKeyPrefix + prefixPart)
+	if err != nil {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(apiKey.SecretHash)) != 1 {
+		return nil, ErrInvalidAPIKey
+	}
+
+	now := time.Now()
+	if !apiKey.IsActive(now) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= s.lastUsedInterval {
+		// Losing a last-used update is harmless; the next request retries.
+		if err := s.apiKeyRepo.TouchLastUsed(apiKey.ID, now); err != nil {
+			log.Printf("Failed to update last used time of API key %d: %v", apiKey.ID, err)
+		}
+	}
+
+	principal := &model.Principal{
+		APIKeyID: apiKey.ID,
+		Service:  apiKey.Service,
+		Scopes:   apiKey.Scopes,
+	}
+	if apiKey.UserID != nil {
+		principal.UserID = *apiKey.UserID
+	}
+	if apiKey.ServiceOrganizationID != nil {
+		principal.OrganizationID = *apiKey.ServiceOrganizationID
+	}
+	return principal, nil
+}
+
+// authorize checks that the caller may manage the keys of the owner: a user
+// of the organization of ctx, or a service if owner is nil. Users manage
+// their own keys; the keys of other users need PermissionAPIKeysManage and
+// service keys the admin role. Service keys, limited by their scopes only,
+// may manage the keys of their organization.
+func (s *apiKeyService) authorize(ctx context.Context, caller *model.Principal, owner *uint) error {
+	if owner != nil {
+		if _, err := s.userRepo.WithContext(ctx).GetByID(*owner); err != nil {
+			return ErrAPIKeyOwnerNotFound
+		}
+	}
+
+	if caller.UserID == 0 {
+		return nil
+	}
+
+	if owner == nil {
+		// The caller may act in another organization than its own
+		user, err := s.userRepo.WithContext(tenant.Unscoped(ctx)).GetByID(caller.UserID)
+		if err != nil {
+			return err
+		}
+		if user.Role != model.RoleAdmin {
+			return ErrAPIKeyForbidden
+		}
+		return nil
+	}
+
+	if *owner == caller.UserID {
+		return nil
+	}
+	allowed, err := s.groupService.HasPermission(ctx, caller.UserID, model.PermissionAPIKeysManage)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return ErrAPIKeyForbidden
+	}
+	return nil
+}
+
+// newAPIKeyPrefix returns the public part of a new key, such as
+// "gkb_k3j5x2qa".
+func newAPIKeyPrefix() (string, error) {
+	buf := make([]byte, 5)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return model.APIKeyPrefix + apiKeyPrefixEncoding.EncodeToString(buf), nil
+}
+
+// boundTo reports whether the service key is bound to the organization.
+func boundTo(key *model.APIKey, organizationID uint) bool {
+	return key.ServiceOrganizationID != nil && *key.ServiceOrganizationID == organizationID
+}
+
+func toAPIKeyResponses(keys []*model.APIKey) []*model.APIKeyResponse {
+	responses := make([]*model.APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		responses = append(responses, toAPIKeyResponse(key))
+	}
+	return responses
+}
+
+func toAPIKeyResponse(key *model.APIKey) *model.APIKeyResponse {
+	scopes := []string(key.Scopes)
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	return &model.APIKeyResponse{
+		ID:             key.ID,
+		UserID:         key.UserID,
+		Service:        key.Service,
+		OrganizationID: key.ServiceOrganizationID,
+		Name:           key.Name,
+		Prefix:         key.Prefix,
+		Scopes:         scopes,
+		ExpiresAt:      key.ExpiresAt,
+		LastUsedAt:     key.LastUsedAt,
+		RevokedAt:      key.RevokedAt,
+		CreatedAt:      key.CreatedAt,
+	}
+}
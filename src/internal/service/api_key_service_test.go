@@ -0,0 +1,337 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/gorm"
+)
+
+// newStoredAPIKey returns a service key of organization 1 and the full key
+// that matches it.
+func (s *ServiceTestSuite) newStoredAPIKey() (*model.APIKey, string) {
+	secret, secretHash, _ := newSecretToken()
+	organizationID := uint(1)
+	key := &model.APIKey{
+		ID:                    7,
+		Service:               "billing",
+		ServiceOrganizationID: &organizationID,
+		Name:                  "billing sync",
+		Prefix:                "gkb_abcdefgh",
+		SecretHash:            secretHash,
+		Scopes:                model.ScopeList{model.ScopeUsersRead},
+	}
+	return key, key.Prefix + "_" + secret
+}
+
+func (s *ServiceTestSuite) TestCreateAPIKey_ForUser() {
+	userID := uint(1)
+	user := s.newUserWithPassword("password123")
+
+	s.userRepo.On("GetByID", userID).Return(user, nil)
+	s.apiKeyRepo.On("Create", mock.AnythingOfType("*model.APIKey")).Return(nil)
+
+	// Execute
+	resp, err := s.apiKeyService.Create(s.ctx, &model.Principal{UserID: 1, SessionID: "session-1"}, &model.CreateAPIKeyRequest{
+		Name:   "ci",
+		UserID: &userID,
+		Scopes: []string{model.ScopeUsersRead, model.ScopeUsersWrite},
+	})
+
+	// Assert: only a hash of the secret is stored
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), strings.HasPrefix(resp.Key, resp.Prefix+"_"))
+	assert.Len(s.T(), resp.Prefix, len(model.APIKeyPrefix)+8)
+	assert.Equal(s.T(), []string{model.ScopeUsersRead, model.ScopeUsersWrite}, resp.Scopes)
+
+	stored := s.apiKeyRepo.Calls[0].Arguments.Get(0).(*model.APIKey)
+	assert.Equal(s.T(), resp.Prefix, stored.Prefix)
+	assert.Equal(s.T(), hashSecret(strings.TrimPrefix(resp.Key, resp.Prefix+"_")), stored.SecretHash)
+}
+
+func (s *ServiceTestSuite) TestCreateAPIKey_ScopeNotGranted() {
+	caller := &model.Principal{APIKeyID: 7, Service: "billing", Scopes: model.ScopeList{model.ScopeAPIKeysWrite}}
+
+	// Execute
+	_, err := s.apiKeyService.Create(s.ctx, caller, &model.CreateAPIKeyRequest{
+		Name:    "escalation",
+		Service: "billing",
+		Scopes:  []string{model.ScopeUsersWrite},
+	})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrScopeNotGranted)
+	s.apiKeyRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestCreateAPIKey_ForOtherUser() {
+	userID := uint(2)
+
+	s.userRepo.On("GetByID", userID).Return(&model.User{ID: 2}, nil)
+	s.membershipRepo.On("WithContext", mock.Anything).Return(s.membershipRepo)
+	s.membershipRepo.On("Get", uint(1)).Return(&model.Membership{UserID: 1, Role: model.OrganizationRoleMember}, nil)
+	s.groupRepo.On("ListByUser", uint(1)).Return([]*model.Group{}, nil)
+
+	// Execute
+	_, err := s.apiKeyService.Create(s.ctx, &model.Principal{UserID: 1, SessionID: "session-1"}, &model.CreateAPIKeyRequest{
+		Name:   "ci",
+		UserID: &userID,
+		Scopes: []string{model.ScopeUsersRead},
+	})
+
+	// Assert: members without api_keys:manage only issue keys to themselves
+	assert.ErrorIs(s.T(), err, ErrAPIKeyForbidden)
+	s.apiKeyRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestCreateAPIKey_ForOtherUserAsAdmin() {
+	userID := uint(2)
+
+	s.userRepo.On("GetByID", userID).Return(&model.User{ID: 2}, nil)
+	s.membershipRepo.On("WithContext", mock.Anything).Return(s.membershipRepo)
+	s.membershipRepo.On("Get", uint(1)).Return(&model.Membership{UserID: 1, Role: model.OrganizationRoleAdmin}, nil)
+	s.apiKeyRepo.On("Create", mock.AnythingOfType("*model.APIKey")).Return(nil)
+
+	// Execute
+	resp, err := s.apiKeyService.Create(s.ctx, &model.Principal{UserID: 1, SessionID: "session-1"}, &model.CreateAPIKeyRequest{
+		Name:   "ci",
+		UserID: &userID,
+		Scopes: []string{model.ScopeUsersRead},
+	})
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), &userID, resp.UserID)
+}
+
+func (s *ServiceTestSuite) TestCreateAPIKey_ServiceKeyNeedsAdminRole() {
+	s.userRepo.On("GetByID", uint(1)).Return(s.newUserWithPassword("password123"), nil)
+
+	// Execute
+	_, err := s.apiKeyService.Create(s.ctx, &model.Principal{UserID: 1, SessionID: "session-1"}, &model.CreateAPIKeyRequest{
+		Name:    "billing sync",
+		Service: "billing",
+		Scopes:  []string{model.ScopeUsersRead},
+	})
+
+	// Assert: being an owner or admin of an organization is not enough
+	assert.ErrorIs(s.T(), err, ErrAPIKeyForbidden)
+	s.apiKeyRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestCreateAPIKey_ServiceKeyBoundToOrganization() {
+	admin := s.newUserWithPassword("password123")
+	admin.Role = model.RoleAdmin
+
+	s.userRepo.On("GetByID", uint(1)).Return(admin, nil)
+	s.apiKeyRepo.On("Create", mock.AnythingOfType("*model.APIKey")).Return(nil)
+
+	// Execute
+	resp, err := s.apiKeyService.Create(s.ctx, &model.Principal{UserID: 1, SessionID: "session-1"}, &model.CreateAPIKeyRequest{
+		Name:    "billing sync",
+		Service: "billing",
+		Scopes:  []string{model.ScopeUsersRead},
+	})
+
+	// Assert: the key acts in the organization of the request only
+	assert.NoError(s.T(), err)
+	stored := s.apiKeyRepo.Calls[0].Arguments.Get(0).(*model.APIKey)
+	if assert.NotNil(s.T(), stored.ServiceOrganizationID) {
+		assert.Equal(s.T(), uint(1), *stored.ServiceOrganizationID)
+	}
+	assert.Equal(s.T(), stored.ServiceOrganizationID, resp.OrganizationID)
+}
+
+func (s *ServiceTestSuite) TestCreateAPIKey_ExpiryInThePast() {
+	expiresAt := time.Now().Add(-time.Minute)
+
+	// Execute
+	_, err := s.apiKeyService.Create(s.ctx, &model.Principal{UserID: 1, SessionID: "session-1"}, &model.CreateAPIKeyRequest{
+		Name:      "expired",
+		Service:   "billing",
+		Scopes:    []string{model.ScopeUsersRead},
+		ExpiresAt: &expiresAt,
+	})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrAPIKeyExpiry)
+}
+
+func (s *ServiceTestSuite) TestCreateAPIKey_UnknownUser() {
+	userID := uint(999)
+
+	s.userRepo.On("GetByID", userID).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	_, err := s.apiKeyService.Create(s.ctx, &model.Principal{UserID: 1, SessionID: "session-1"}, &model.CreateAPIKeyRequest{
+		Name:   "ci",
+		UserID: &userID,
+		Scopes: []string{model.ScopeUsersRead},
+	})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrAPIKeyOwnerNotFound)
+}
+
+func (s *ServiceTestSuite) TestAuthenticateAPIKey_Success() {
+	key, fullKey := s.newStoredAPIKey()
+
+	s.apiKeyRepo.On("GetByPrefix", "gkb_abcdefgh").Return(key, nil)
+	s.apiKeyRepo.On("TouchLastUsed", uint(7), mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Execute
+	principal, err := s.apiKeyService.Authenticate(fullKey)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), &model.Principal{APIKeyID: 7, Service: "billing", OrganizationID: 1, Scopes: model.ScopeList{model.ScopeUsersRead}}, principal)
+	assert.True(s.T(), principal.HasScope(model.ScopeUsersRead))
+	assert.False(s.T(), principal.HasScope(model.ScopeUsersWrite))
+	s.apiKeyRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestAuthenticateAPIKey_RecentlyUsed() {
+	key, fullKey := s.newStoredAPIKey()
+	lastUsedAt := time.Now().Add(-time.Second)
+	key.LastUsedAt = &lastUsedAt
+
+	s.apiKeyRepo.On("GetByPrefix", "gkb_abcdefgh").Return(key, nil)
+
+	// Execute
+	_, err := s.apiKeyService.Authenticate(fullKey)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	s.apiKeyRepo.AssertNotCalled(s.T(), "TouchLastUsed", mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestAuthenticateAPIKey_WrongSecret() {
+	key, _ := s.newStoredAPIKey()
+
+	s.apiKeyRepo.On("GetByPrefix", "gkb_abcdefgh").Return(key, nil)
+
+	// Execute
+	_, err := s.apiKeyService.Authenticate("gkb_abcdefgh_wrong-secret")
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidAPIKey)
+}
+
+func (s *ServiceTestSuite) TestAuthenticateAPIKey_RevokedOrExpired() {
+	key, fullKey := s.newStoredAPIKey()
+	past := time.Now().Add(-time.Minute)
+
+	for _, mutate := range []func(){
+		func() { key.RevokedAt = &past },
+		func() { key.RevokedAt, key.ExpiresAt = nil, &past },
+	} {
+		mutate()
+		s.apiKeyRepo.On("GetByPrefix", "gkb_abcdefgh").Return(key, nil).Once()
+
+		// Execute
+		_, err := s.apiKeyService.Authenticate(fullKey)
+
+		// Assert
+		assert.ErrorIs(s.T(), err, ErrInvalidAPIKey)
+	}
+	s.apiKeyRepo.AssertNotCalled(s.T(), "TouchLastUsed", mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestAuthenticateAPIKey_Malformed() {
+	for _, key := range []string{"", "not-a-key", "gkb_", "gkb_abcdefgh", "gkb_abcdefgh_"} {
+		// Execute
+		_, err := s.apiKeyService.Authenticate(key)
+
+		// Assert
+		assert.ErrorIs(s.T(), err, ErrInvalidAPIKey, key)
+	}
+	s.apiKeyRepo.AssertNotCalled(s.T(), "GetByPrefix", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestRevokeAPIKey_Own() {
+	userID := uint(1)
+	s.apiKeyRepo.On("GetByID", uint(7)).Return(&model.APIKey{ID: 7, UserID: &userID}, nil)
+	s.userRepo.On("GetByID", userID).Return(s.newUserWithPassword("password123"), nil)
+	s.apiKeyRepo.On("Revoke", uint(7), mock.AnythingOfType("time.Time")).Return(true, nil)
+
+	// Execute
+	err := s.apiKeyService.Revoke(s.ctx, &model.Principal{UserID: 1, SessionID: "session-1"}, 7)
+
+	// Assert
+	assert.NoError(s.T(), err)
+}
+
+func (s *ServiceTestSuite) TestRevokeAPIKey_NotFound() {
+	s.apiKeyRepo.On("GetByID", uint(999)).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	err := s.apiKeyService.Revoke(s.ctx, &model.Principal{UserID: 1, SessionID: "session-1"}, 999)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrAPIKeyNotFound)
+}
+
+func (s *ServiceTestSuite) TestRevokeAPIKey_OtherOrganization() {
+	userID := uint(5)
+	s.apiKeyRepo.On("GetByID", uint(7)).Return(&model.APIKey{ID: 7, UserID: &userID}, nil)
+	s.userRepo.On("GetByID", userID).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	err := s.apiKeyService.Revoke(s.ctx, &model.Principal{UserID: 1, SessionID: "session-1"}, 7)
+
+	// Assert: keys of users of other organizations are not found
+	assert.ErrorIs(s.T(), err, ErrAPIKeyNotFound)
+	s.apiKeyRepo.AssertNotCalled(s.T(), "Revoke", mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestListAPIKeysForService() {
+	key, _ := s.newStoredAPIKey()
+
+	admin := s.newUserWithPassword("password123")
+	admin.Role = model.RoleAdmin
+
+	s.userRepo.On("GetByID", uint(1)).Return(admin, nil)
+	s.apiKeyRepo.On("ListByService", "billing").Return([]*model.APIKey{key}, nil)
+
+	// Execute
+	keys, err := s.apiKeyService.ListForService(s.ctx, &model.Principal{UserID: 1, SessionID: "session-1"}, "billing")
+
+	// Assert
+	assert.NoError(s.T(), err)
+	if assert.Len(s.T(), keys, 1) {
+		assert.Equal(s.T(), "gkb_abcdefgh", keys[0].Prefix)
+		assert.Equal(s.T(), "billing", keys[0].Service)
+	}
+}
+
+func (s *ServiceTestSuite) TestRevokeAPIKey_ServiceKeyOfOtherOrganization() {
+	key, _ := s.newStoredAPIKey()
+	s.apiKeyRepo.On("GetByID", uint(7)).Return(key, nil)
+
+	// Execute
+	err := s.apiKeyService.Revoke(s.ctx, &model.Principal{APIKeyID: 4, Service: "provisioning", OrganizationID: 2}, 7)
+
+	// Assert: service keys do not see the service keys of other organizations
+	assert.ErrorIs(s.T(), err, ErrAPIKeyNotFound)
+	s.apiKeyRepo.AssertNotCalled(s.T(), "Revoke", mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestListAPIKeysForService_AsServiceKey() {
+	key, _ := s.newStoredAPIKey()
+	otherOrganizationID := uint(2)
+	other := &model.APIKey{ID: 8, Service: "billing", ServiceOrganizationID: &otherOrganizationID, Prefix: "gkb_ijklmnop"}
+
+	s.apiKeyRepo.On("ListByService", "billing").Return([]*model.APIKey{key, other}, nil)
+
+	// Execute
+	keys, err := s.apiKeyService.ListForService(s.ctx, &model.Principal{APIKeyID: 4, Service: "provisioning", OrganizationID: 1}, "billing")
+
+	// Assert
+	assert.NoError(s.T(), err)
+	if assert.Len(s.T(), keys, 1) {
+		assert.Equal(s.T(), "gkb_abcdefgh", keys[0].Prefix)
+	}
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/audit"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+)
+
+// auditBatchSize is how many events Export and Verify read at a time.
+const auditBatchSize = 500
+
+var ErrUnsupportedAuditFormat = errors.New("unsupported export format")
+
+// auditCSVHeader names the columns of CSV exports.
+var auditCSVHeader = []string{
+	"id", "created_at", "action", "actor_user_id", "actor_api_key_id", "actor_service",
+	"target_type", "target_id", "ip", "request_id", "changes", "prev_hash", "hash",
+}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=AuditService --output=./mocks/service --outpkg=service --filename=audit_service.go --structname=MockAuditService --with-expecter=false
+type AuditService interface {
+	ListEvents(ctx context.Context, filter *model.AuditFilter, limit, offset int) ([]*model.AuditEventResponse, error)
+	Export(ctx context.Context, filter *model.AuditFilter, format string, w io.Writer) error
+	Verify(ctx context.Context) (*model.AuditVerificationResponse, error)
+}
+
+type auditService struct {
+	auditRepo repository.AuditRepository
+}
+
+func NewAuditService(auditRepo repository.AuditRepository) AuditService {
+	return &auditService{auditRepo: auditRepo}
+}
+
+// ListEvents returns a page of the audit log of the organization of ctx,
+// newest first.
+func (s *auditService) ListEvents(ctx context.Context, filter *model.AuditFilter, limit, offset int) ([]*model.AuditEventResponse, error) {
+	events, err := s.auditRepo.WithContext(ctx).List(filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*model.AuditEventResponse, 0, len(events))
+	for _, event := range events {
+		responses = append(responses, toAuditEventResponse(event))
+	}
+	return responses, nil
+}
+
+// Export writes the events matching the filter to w, oldest first, as CSV
+// with a header row or as one JSON object per line. Events are read in
+// batches, so the log does not have to fit in memory.
+func (s *auditService) Export(ctx context.Context, filter *model.AuditFilter, format string, w io.Writer) error {
+	var write func(event *model.AuditEvent) error
+	var flush func() error
+	switch format {
+	case model.AuditFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(auditCSVHeader); err != nil {
+			return err
+		}
+		write = func(event *model.AuditEvent) error {
+			record, err := auditCSVRecord(event)
+			if err != nil {
+				return err
+			}
+			return writer.Write(record)
+		}
+		flush = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+	case model.AuditFormatNDJSON:
+		encoder := json.NewEncoder(w)
+		write = func(event *model.AuditEvent) error {
+			return encoder.Encode(toAuditEventResponse(event))
+		}
+		flush = func() error { return nil }
+	default:
+		return ErrUnsupportedAuditFormat
+	}
+
+	if err := s.each(ctx, filter, write); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// Verify recomputes the hash chain of the audit log of the organization of
+// ctx. The chain breaks at the first event whose hash does not match its
+// content, or that does not follow the event before it, as happens when
+// events are changed or removed.
+func (s *auditService) Verify(ctx context.Context) (*model.AuditVerificationResponse, error) {
+	result := &model.AuditVerificationResponse{Valid: true}
+	prevHash := ""
+	err := s.each(ctx, nil, func(event *model.AuditEvent) error {
+		result.Events++
+		if !result.Valid {
+			return nil
+		}
+
+		hash, err := audit.Hash(prevHash, event)
+		if err != nil {
+			return err
+		}
+		if event.PrevHash != prevHash || event.Hash != hash {
+			result.Valid = false
+			result.BrokenAt = &event.ID
+		}
+		prevHash = event.Hash
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// each calls fn with the events matching the filter, oldest first.
+func (s *auditService) each(ctx context.Context, filter *model.AuditFilter, fn func(event *model.AuditEvent) error) error {
+	events := s.auditRepo.WithContext(ctx)
+
+	var afterID uint
+	for {
+		batch, err := events.ListAfter(filter, afterID, auditBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, event := range batch {
+			if err := fn(event); err != nil {
+				return err
+			}
+		}
+		if len(batch) < auditBatchSize {
+			return nil
+		}
+		afterID = batch[len(batch)-1].ID
+	}
+}
+
+func auditCSVRecord(event *model.AuditEvent) ([]string, error) {
+	changes, err := json.Marshal(event.Changes)
+	if err != nil {
+		return nil, err
+	}
+	return []string{
+		strconv.FormatUint(uint64(event.ID), 10),
+		event.CreatedAt.UTC().Format(time.RFC3339Nano),
+		event.Action,
+		formatOptionalID(event.ActorUserID),
+		formatOptionalID(event.ActorAPIKeyID),
+		event.ActorService,
+		event.TargetType,
+		strconv.FormatUint(uint64(event.TargetID), 10),
+		event.IP,
+		event.RequestID,
+		string(changes),
+		event.PrevHash,
+		event.Hash,
+	}, nil
+}
+
+func formatOptionalID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
+
+func toAuditEventResponse(event *model.AuditEvent) *model.AuditEventResponse {
+	return &model.AuditEventResponse{
+		ID:            event.ID,
+		Action:        event.Action,
+		ActorUserID:   event.ActorUserID,
+		ActorAPIKeyID: event.ActorAPIKeyID,
+		ActorService:  event.ActorService,
+		TargetType:    event.TargetType,
+		TargetID:      event.TargetID,
+		IP:            event.IP,
+		RequestID:     event.RequestID,
+		Changes:       event.Changes,
+		PrevHash:      event.PrevHash,
+		Hash:          event.Hash,
+		CreatedAt:     event.CreatedAt,
+	}
+}
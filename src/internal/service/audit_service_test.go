@@ -0,0 +1,135 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/audit"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// auditChain returns n chained events of organization 1, oldest first.
+func auditChain(n int) []*model.AuditEvent {
+	events := make([]*model.AuditEvent, 0, n)
+	prevHash := ""
+	for i := 1; i <= n; i++ {
+		event := &model.AuditEvent{
+			ID:             uint(i),
+			OrganizationID: 1,
+			Action:         model.AuditUserUpdated,
+			TargetType:     model.AuditTargetUser,
+			TargetID:       uint(i),
+			Changes:        model.AuditChanges{"username": {Before: "old", After: "new"}},
+			PrevHash:       prevHash,
+			CreatedAt:      time.Date(2025, 11, 30, 9, 0, i, 0, time.UTC),
+		}
+		event.Hash, _ = audit.Hash(prevHash, event)
+		prevHash = event.Hash
+		events = append(events, event)
+	}
+	return events
+}
+
+// Test ListEvents
+func (s *ServiceTestSuite) TestListEvents_Success() {
+	filter := &model.AuditFilter{Action: model.AuditUserUpdated}
+	s.auditRepo.On("List", filter, 10, 0).Return(auditChain(2), nil)
+
+	events, err := s.audit.ListEvents(s.ctx, filter, 10, 0)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), events, 2)
+	assert.Equal(s.T(), "new", events[0].Changes["username"].After)
+}
+
+// Test Export
+func (s *ServiceTestSuite) TestExport_CSV() {
+	s.auditRepo.On("ListAfter", (*model.AuditFilter)(nil), uint(0), auditBatchSize).Return(auditChain(2), nil)
+
+	var buf bytes.Buffer
+	err := s.audit.Export(s.ctx, nil, model.AuditFormatCSV, &buf)
+
+	assert.NoError(s.T(), err)
+	records, err := csv.NewReader(&buf).ReadAll()
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), records, 3)
+	assert.Equal(s.T(), auditCSVHeader, records[0])
+	assert.Equal(s.T(), "1", records[1][0])
+	assert.Equal(s.T(), `{"username":{"before":"old","after":"new"}}`, records[1][10])
+}
+
+func (s *ServiceTestSuite) TestExport_NDJSON() {
+	s.auditRepo.On("ListAfter", (*model.AuditFilter)(nil), uint(0), auditBatchSize).Return(auditChain(2), nil)
+
+	var buf bytes.Buffer
+	err := s.audit.Export(s.ctx, nil, model.AuditFormatNDJSON, &buf)
+
+	assert.NoError(s.T(), err)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(s.T(), lines, 2)
+	var event model.AuditEventResponse
+	assert.NoError(s.T(), json.Unmarshal([]byte(lines[1]), &event))
+	assert.Equal(s.T(), uint(2), event.ID)
+}
+
+func (s *ServiceTestSuite) TestExport_Batches() {
+	first := make([]*model.AuditEvent, auditBatchSize)
+	for i := range first {
+		first[i] = &model.AuditEvent{ID: uint(i + 1)}
+	}
+	s.auditRepo.On("ListAfter", mock.Anything, uint(0), auditBatchSize).Return(first, nil).Once()
+	s.auditRepo.On("ListAfter", mock.Anything, uint(auditBatchSize), auditBatchSize).Return([]*model.AuditEvent{{ID: auditBatchSize + 1}}, nil).Once()
+
+	var buf bytes.Buffer
+	err := s.audit.Export(s.ctx, nil, model.AuditFormatNDJSON, &buf)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), auditBatchSize+1, strings.Count(buf.String(), "\n"))
+}
+
+func (s *ServiceTestSuite) TestExport_UnsupportedFormat() {
+	err := s.audit.Export(s.ctx, nil, "xml", &bytes.Buffer{})
+
+	assert.ErrorIs(s.T(), err, ErrUnsupportedAuditFormat)
+	s.auditRepo.AssertNotCalled(s.T(), "ListAfter", mock.Anything, mock.Anything, mock.Anything)
+}
+
+// Test Verify
+func (s *ServiceTestSuite) TestVerify_Valid() {
+	s.auditRepo.On("ListAfter", (*model.AuditFilter)(nil), uint(0), auditBatchSize).Return(auditChain(3), nil)
+
+	result, err := s.audit.Verify(s.ctx)
+
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), result.Valid)
+	assert.Equal(s.T(), int64(3), result.Events)
+	assert.Nil(s.T(), result.BrokenAt)
+}
+
+func (s *ServiceTestSuite) TestVerify_ChangedEvent() {
+	events := auditChain(3)
+	events[1].Changes = model.AuditChanges{"username": {Before: "old", After: "forged"}}
+	s.auditRepo.On("ListAfter", (*model.AuditFilter)(nil), uint(0), auditBatchSize).Return(events, nil)
+
+	result, err := s.audit.Verify(s.ctx)
+
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), result.Valid)
+	assert.Equal(s.T(), uint(2), *result.BrokenAt)
+}
+
+func (s *ServiceTestSuite) TestVerify_RemovedEvent() {
+	events := auditChain(3)
+	s.auditRepo.On("ListAfter", (*model.AuditFilter)(nil), uint(0), auditBatchSize).Return([]*model.AuditEvent{events[0], events[2]}, nil)
+
+	result, err := s.audit.Verify(s.ctx)
+
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), result.Valid)
+	assert.Equal(s.T(), uint(3), *result.BrokenAt)
+}
@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/hasher"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidCredentials    = errors.New("invalid credentials")
+	ErrInvalidMFAChallenge   = errors.New("invalid or expired MFA challenge")
+	ErrMFAEnrollmentRequired = errors.New("MFA must be enabled for this account before logging in")
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=AuthService --output=./mocks/service --outpkg=service --filename=auth_service.go --structname=MockAuthService --with-expecter=false
+type AuthService interface {
+	Login(ctx context.Context, req *model.LoginRequest, client *model.ClientInfo) (*model.LoginResponse, error)
+	CompleteMFALogin(ctx context.Context, req *model.MFALoginRequest, client *model.ClientInfo) (*model.LoginResponse, error)
+	CompleteExternalLogin(user *model.User, client *model.ClientInfo) (*model.LoginResponse, error)
+	CompletePasskeyLogin(user *model.User, client *model.ClientInfo) (*model.LoginResponse, error)
+}
+
+type authService struct {
+	userRepo       repository.UserRepository
+	sessions       SessionService
+	tokenService   TokenService
+	passwordHasher hasher.PasswordHasher
+	mfaService     MFAService
+	lockout        LockoutService
+	challengeTTL   time.Duration
+
+	// dummyHash is verified against when the login matches no user, so that
+	// unknown and existing accounts take about as long to reject.
+	dummyHash string
+}
+
+func NewAuthService(
+	userRepo repository.UserRepository,
+	sessions SessionService,
+	tokenService TokenService,
+	passwordHasher hasher.PasswordHasher,
+	mfaService MFAService,
+	lockout LockoutService,
+	conf *config.Config,
+) AuthService {
+	dummyHash, err := passwordHasher.Hash(uuid.NewString())
+	if err != nil {
+		log.Printf("Failed to create dummy password hash: %v", err)
+	}
+
+	return &authService{
+		userRepo:       userRepo,
+		sessions:       sessions,
+		tokenService:   tokenService,
+		passwordHasher: passwordHasher,
+		mfaService:     mfaService,
+		lockout:        lockout,
+		challengeTTL:   conf.MFA.ChallengeTTL,
+		dummyHash:      dummyHash,
+	}
+}
+
+// Login checks the credentials, starts a new session and returns an access
+// token for it. req.Login may be either the email address or the username
+// of a user of the organization of ctx.
+// Users with a second factor get an MFA challenge token instead, which is
+// redeemed with CompleteMFALogin. Repeated failures are throttled by
+// LockoutService; unknown logins fail the same way as wrong passwords.
+func (s *authService) Login(ctx context.Context, req *model.LoginRequest, client *model.ClientInfo) (*model.LoginResponse, error) {
+	user := s.findUser(ctx, req.Login)
+
+	if err := s.lockout.Check(user, req.Login, client); err != nil {
+		return nil, err
+	}
+
+	if !s.verifyPassword(user, req.Password) {
+		if err := s.lockout.RecordFailure(user, req.Login, client, LoginReasonInvalidCredentials); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	s.rehashPassword(ctx, user, req.Password)
+
+	mfaEnabled, err := s.mfaService.IsEnabled(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if mfaEnabled {
+		// Failures are only cleared once the second factor is passed, so
+		// that the password cannot be used to reset the MFA throttle.
+		return s.issueMFAChallenge(user)
+	}
+	if s.mfaService.IsRequired(user) {
+		return nil, ErrMFAEnrollmentRequired
+	}
+
+	if err := s.lockout.RecordSuccess(user, req.Login, client); err != nil {
+		return nil, err
+	}
+
+	return s.startSession(user, client)
+}
+
+// CompleteMFALogin finishes a login with the challenge token from Login and
+// a TOTP or recovery code. Wrong codes count as failed logins of the user.
+func (s *authService) CompleteMFALogin(ctx context.Context, req *model.MFALoginRequest, client *model.ClientInfo) (*model.LoginResponse, error) {
+	userID, err := s.tokenService.ParseMFAChallenge(req.MFAToken)
+	if err != nil {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	// The challenge names the user, whatever the organization of the request
+	user, err := s.userRepo.WithContext(tenant.Unscoped(ctx)).GetByID(userID)
+	if err != nil {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	if err := s.lockout.Check(user, user.Username, client); err != nil {
+		return nil, err
+	}
+
+	err = s.mfaService.VerifyCode(userID, req.Code)
+	if errors.Is(err, ErrInvalidMFACode) || errors.Is(err, ErrMFANotEnabled) {
+		if err := s.lockout.RecordFailure(user, user.Username, client, LoginReasonInvalidMFACode); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidMFACode
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.lockout.RecordSuccess(user, user.Username, client); err != nil {
+		return nil, err
+	}
+
+	return s.startSession(user, client)
+}
+
+// CompleteExternalLogin finishes a login for a user whom an external identity
+// provider has authenticated. The second factor is still required.
+func (s *authService) CompleteExternalLogin(user *model.User, client *model.ClientInfo) (*model.LoginResponse, error) {
+	mfaEnabled, err := s.mfaService.IsEnabled(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if mfaEnabled {
+		return s.issueMFAChallenge(user)
+	}
+	if s.mfaService.IsRequired(user) {
+		return nil, ErrMFAEnrollmentRequired
+	}
+
+	if err := s.lockout.RecordSuccess(user, user.Username, client); err != nil {
+		return nil, err
+	}
+
+	return s.startSession(user, client)
+}
+
+// CompletePasskeyLogin finishes a login with a passkey. The authenticator
+// has verified the user with a PIN or biometric, so the passkey counts as
+// both factors and no TOTP code is asked for.
+func (s *authService) CompletePasskeyLogin(user *model.User, client *model.ClientInfo) (*model.LoginResponse, error) {
+	if err := s.lockout.RecordSuccess(user, user.Username, client); err != nil {
+		return nil, err
+	}
+
+	return s.startSession(user, client)
+}
+
+// findUser looks up the user by email address, which is unique across
+// organizations, or by username within the organization of ctx, or returns
+// nil.
+func (s *authService) findUser(ctx context.Context, login string) *model.User {
+	var user *model.User
+	var err error
+	if strings.Contains(login, "@") {
+		user, err = s.userRepo.WithContext(tenant.Unscoped(ctx)).GetByEmail(login)
+	} else {
+		user, err = s.userRepo.WithContext(ctx).GetByUsername(login)
+	}
+	if err != nil {
+		return nil
+	}
+	return user
+}
+
+// verifyPassword checks the password of the user. Without a user it still
+// verifies against a dummy hash to keep the timing uniform.
+func (s *authService) verifyPassword(user *model.User, password string) bool {
+	if user == nil {
+		_, _ = s.passwordHasher.Verify(password, s.dummyHash)
+		return false
+	}
+
+	ok, err := s.passwordHasher.Verify(password, user.Password)
+	return err == nil && ok
+}
+
+func (s *authService) issueMFAChallenge(user *model.User) (*model.LoginResponse, error) {
+	expiresAt := time.Now().Add(s.challengeTTL)
+
+	mfaToken, err := s.tokenService.IssueMFAChallenge(user, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.LoginResponse{
+		ExpiresAt:   expiresAt,
+		MFARequired: true,
+		MFAToken:    mfaToken,
+	}, nil
+}
+
+func (s *authService) startSession(user *model.User, client *model.ClientInfo) (*model.LoginResponse, error) {
+	session, err := s.sessions.Create(user, client)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.tokenService.IssueAccessToken(user, session)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.LoginResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresAt:   session.ExpiresAt,
+	}, nil
+}
+
+// rehashPassword upgrades a hash made with another algorithm or outdated
+// parameters while the plaintext is at hand. Failures are only logged; the
+// old hash keeps working.
+func (s *authService) rehashPassword(ctx context.Context, user *model.User, password string) {
+	if !s.passwordHasher.NeedsRehash(user.Password) {
+		return
+	}
+
+	newHash, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		log.Printf("Failed to rehash password for user %d: %v", user.ID, err)
+		return
+	}
+
+	users := s.userRepo.WithContext(tenant.WithOrganization(ctx, user.OrganizationID))
+	if err := users.UpdatePasswordHash(user.ID, user.Password, newHash); err != nil {
+		log.Printf("Failed to store rehashed password for user %d: %v", user.ID, err)
+		return
+	}
+	user.Password = newHash
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/hasher"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/totp"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+func (s *ServiceTestSuite) newUserWithPassword(password string) *model.User {
+	hash, _ := s.passwordHasher.Hash(password)
+	return &model.User{
+		ID:       1,
+		Username: "testuser",
+		Email:    "test@example.com",
+		Password: hash,
+		Version:  1,
+	}
+}
+
+func (s *ServiceTestSuite) TestLogin_WithUsername() {
+	user := s.newUserWithPassword("password123")
+
+	s.userRepo.On("GetByUsername", "testuser").Return(user, nil)
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	s.sessionRepo.On("Create", mock.AnythingOfType("*model.Session")).Return(nil)
+	s.allowLogins()
+
+	// Execute
+	result, err := s.authService.Login(s.ctx, &model.LoginRequest{Login: "testuser", Password: "password123"}, testClient)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "Bearer", result.TokenType)
+	assert.WithinDuration(s.T(), time.Now().Add(time.Hour), result.ExpiresAt, time.Minute)
+
+	// The token is bound to the created session
+	session := s.sessionRepo.Calls[0].Arguments.Get(0).(*model.Session)
+	claims := &AccessTokenClaims{}
+	_, err = jwt.ParseWithClaims(result.AccessToken, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte("test-secret"), nil
+	})
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), session.ID, claims.SessionID)
+	assert.Equal(s.T(), "1", claims.Subject)
+	assert.Equal(s.T(), "test", claims.Issuer)
+	s.userRepo.AssertExpectations(s.T())
+	s.sessionRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestLogin_WithEmail() {
+	user := s.newUserWithPassword("password123")
+
+	s.userRepo.On("GetByEmail", "test@example.com").Return(user, nil)
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	s.sessionRepo.On("Create", mock.AnythingOfType("*model.Session")).Return(nil)
+	s.allowLogins()
+
+	// Execute
+	result, err := s.authService.Login(s.ctx, &model.LoginRequest{Login: "test@example.com", Password: "password123"}, testClient)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), result.AccessToken)
+	s.userRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestLogin_UnknownUser() {
+	s.userRepo.On("GetByUsername", "nobody").Return(nil, errors.New("not found"))
+	s.allowLogins()
+
+	// Execute
+	result, err := s.authService.Login(s.ctx, &model.LoginRequest{Login: "nobody", Password: "password123"}, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidCredentials)
+	assert.Nil(s.T(), result)
+	s.sessionRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestLogin_WrongPassword() {
+	user := s.newUserWithPassword("password123")
+
+	s.userRepo.On("GetByUsername", "testuser").Return(user, nil)
+	s.allowLogins()
+
+	// Execute
+	result, err := s.authService.Login(s.ctx, &model.LoginRequest{Login: "testuser", Password: "wrong"}, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidCredentials)
+	assert.Nil(s.T(), result)
+	s.sessionRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestLogin_SessionError() {
+	user := s.newUserWithPassword("password123")
+
+	s.userRepo.On("GetByUsername", "testuser").Return(user, nil)
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	s.sessionRepo.On("Create", mock.AnythingOfType("*model.Session")).Return(errors.New("database error"))
+	s.allowLogins()
+
+	// Execute
+	result, err := s.authService.Login(s.ctx, &model.LoginRequest{Login: "testuser", Password: "password123"}, testClient)
+
+	// Assert
+	assert.Error(s.T(), err)
+	assert.NotErrorIs(s.T(), err, ErrInvalidCredentials)
+	assert.Nil(s.T(), result)
+}
+
+func (s *ServiceTestSuite) TestLogin_RehashesOutdatedHash() {
+	// Stored with bcrypt, while the configured algorithm is argon2id
+	user := s.newUserWithPassword("password123")
+	oldHash := user.Password
+
+	s.conf.Password.Hasher.Algorithm = "argon2id"
+	passwordHasher, _ := hasher.NewPasswordHasher(s.conf)
+	authService := NewAuthService(s.userRepo, s.sessionService, s.tokenService, passwordHasher, s.mfaService, s.lockoutService, s.conf)
+
+	s.userRepo.On("GetByUsername", "testuser").Return(user, nil)
+	s.userRepo.On("UpdatePasswordHash", uint(1), oldHash, mock.AnythingOfType("string")).Return(nil)
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	s.sessionRepo.On("Create", mock.AnythingOfType("*model.Session")).Return(nil)
+	s.allowLogins()
+
+	// Execute
+	result, err := authService.Login(s.ctx, &model.LoginRequest{Login: "testuser", Password: "password123"}, testClient)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), result)
+	newHash := s.userRepo.Calls[3].Arguments.String(2)
+	assert.Contains(s.T(), newHash, "$argon2id$")
+	ok, err := passwordHasher.Verify("password123", newHash)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), ok)
+	assert.False(s.T(), passwordHasher.NeedsRehash(newHash))
+}
+
+func (s *ServiceTestSuite) TestLogin_RehashFailureDoesNotFailLogin() {
+	user := s.newUserWithPassword("password123")
+
+	s.conf.Password.Hasher.BcryptCost = bcrypt.MinCost + 1
+	passwordHasher, _ := hasher.NewPasswordHasher(s.conf)
+	authService := NewAuthService(s.userRepo, s.sessionService, s.tokenService, passwordHasher, s.mfaService, s.lockoutService, s.conf)
+
+	s.userRepo.On("GetByUsername", "testuser").Return(user, nil)
+	s.userRepo.On("UpdatePasswordHash", uint(1), user.Password, mock.AnythingOfType("string")).Return(errors.New("database error"))
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	s.sessionRepo.On("Create", mock.AnythingOfType("*model.Session")).Return(nil)
+	s.allowLogins()
+
+	// Execute
+	result, err := authService.Login(s.ctx, &model.LoginRequest{Login: "testuser", Password: "password123"}, testClient)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), result)
+	s.userRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestLogin_CurrentHashIsNotRehashed() {
+	user := s.newUserWithPassword("password123")
+
+	s.userRepo.On("GetByUsername", "testuser").Return(user, nil)
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	s.sessionRepo.On("Create", mock.AnythingOfType("*model.Session")).Return(nil)
+	s.allowLogins()
+
+	// Execute
+	_, err := s.authService.Login(s.ctx, &model.LoginRequest{Login: "testuser", Password: "password123"}, testClient)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	s.userRepo.AssertNotCalled(s.T(), "UpdatePasswordHash", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestLogin_MFAEnabledReturnsChallenge() {
+	user := s.newUserWithPassword("password123")
+	credential, _ := s.newMFACredential(true)
+
+	s.userRepo.On("GetByUsername", "testuser").Return(user, nil)
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(credential, nil)
+	s.allowLogins()
+
+	// Execute
+	result, err := s.authService.Login(s.ctx, &model.LoginRequest{Login: "testuser", Password: "password123"}, testClient)
+
+	// Assert: no session yet, only a challenge for the second step
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), result.MFARequired)
+	assert.NotEmpty(s.T(), result.MFAToken)
+	assert.Empty(s.T(), result.AccessToken)
+	assert.WithinDuration(s.T(), time.Now().Add(5*time.Minute), result.ExpiresAt, time.Minute)
+	s.sessionRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+
+	userID, err := s.tokenService.ParseMFAChallenge(result.MFAToken)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), uint(1), userID)
+}
+
+func (s *ServiceTestSuite) TestLogin_MFARequiredForRole() {
+	user := s.newUserWithPassword("password123")
+	user.Role = model.RoleAdmin
+
+	s.userRepo.On("GetByUsername", "testuser").Return(user, nil)
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	s.allowLogins()
+
+	// Execute
+	result, err := s.authService.Login(s.ctx, &model.LoginRequest{Login: "testuser", Password: "password123"}, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrMFAEnrollmentRequired)
+	assert.Nil(s.T(), result)
+	s.sessionRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestCompleteMFALogin_Success() {
+	user := s.newUserWithPassword("password123")
+	credential, secret := s.newMFACredential(true)
+	step := totp.Step(time.Now())
+	challenge, _ := s.tokenService.IssueMFAChallenge(user, time.Now().Add(time.Minute))
+
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(credential, nil)
+	s.mfaRepo.On("MarkStepUsed", uint(5), step).Return(true, nil)
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.sessionRepo.On("Create", mock.AnythingOfType("*model.Session")).Return(nil)
+	s.allowLogins()
+
+	// Execute
+	result, err := s.authService.CompleteMFALogin(s.ctx, &model.MFALoginRequest{MFAToken: challenge, Code: totp.Code(secret, step)}, testClient)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), result.AccessToken)
+	assert.False(s.T(), result.MFARequired)
+	s.sessionRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestCompleteMFALogin_WrongCode() {
+	user := s.newUserWithPassword("password123")
+	credential, secret := s.newMFACredential(true)
+	challenge, _ := s.tokenService.IssueMFAChallenge(user, time.Now().Add(time.Minute))
+
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(credential, nil)
+	s.allowLogins()
+
+	// Execute
+	result, err := s.authService.CompleteMFALogin(s.ctx, &model.MFALoginRequest{MFAToken: challenge, Code: totp.Code(secret, totp.Step(time.Now())+10)}, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidMFACode)
+	assert.Nil(s.T(), result)
+	s.sessionRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestCompleteMFALogin_ExpiredChallenge() {
+	user := s.newUserWithPassword("password123")
+	challenge, _ := s.tokenService.IssueMFAChallenge(user, time.Now().Add(-time.Minute))
+
+	// Execute
+	result, err := s.authService.CompleteMFALogin(s.ctx, &model.MFALoginRequest{MFAToken: challenge, Code: "123456"}, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidMFAChallenge)
+	assert.Nil(s.T(), result)
+}
+
+func (s *ServiceTestSuite) TestCompleteMFALogin_AccessTokenIsNotAChallenge() {
+	user := s.newUserWithPassword("password123")
+	accessToken, _ := s.tokenService.IssueAccessToken(user, &model.Session{ID: "session", ExpiresAt: time.Now().Add(time.Hour)})
+
+	// Execute
+	result, err := s.authService.CompleteMFALogin(s.ctx, &model.MFALoginRequest{MFAToken: accessToken, Code: "123456"}, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidMFAChallenge)
+	assert.Nil(s.T(), result)
+}
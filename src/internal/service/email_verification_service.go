@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/mailer"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+)
+
+var (
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification token")
+	ErrEmailAlreadyVerified     = errors.New("email is already verified")
+	ErrEmailTaken               = errors.New("email already exists")
+)
+
+// ResendThrottledError is returned when a verification email was sent too
+// recently to send another one.
+type ResendThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ResendThrottledError) Error() string {
+	return fmt.Sprintf("verification email was sent recently, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=EmailVerificationService --output=./mocks/service --outpkg=service --filename=email_verification_service.go --structname=MockEmailVerificationService --with-expecter=false
+type EmailVerificationService interface {
+	SendVerification(user *model.User) error
+	VerifyEmail(ctx context.Context, req *model.VerifyEmailRequest) error
+	ResendVerification(ctx context.Context, id uint) error
+}
+
+type emailVerificationService struct {
+	userRepo         repository.UserRepository
+	verificationRepo repository.EmailVerificationRepository
+	mailer           mailer.Mailer
+	conf             config.EmailVerificationConfig
+}
+
+func NewEmailVerificationService(
+	userRepo repository.UserRepository,
+	verificationRepo repository.EmailVerificationRepository,
+	mailer mailer.Mailer,
+	conf *config.Config,
+) EmailVerificationService {
+	return &emailVerificationService{
+		userRepo:         userRepo,
+		verificationRepo: verificationRepo,
+		mailer:           mailer,
+		conf:             conf.EmailVerification,
+	}
+}
+
+// SendVerification mails a verification link to the user's pending email,
+// or to the current one if no change is pending. Earlier links stop working.
+func (s *emailVerificationService) SendVerification(user *model.User) error {
+	email := user.Email
+	if user.PendingEmail != nil {
+		email = *user.PendingEmail
+	}
+
+	now := time.Now()
+
+	if err := s.verificationRepo.InvalidateForUser(user.ID, now); err != nil {
+		return err
+	}
+
+	token, tokenHash, err := newSecretToken()
+	if err != nil {
+		return err
+	}
+
+	err = s.verificationRepo.Create(&model.EmailVerificationToken{
+		UserID:    user.ID,
+		Email:     email,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(s.conf.TokenTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(&mailer.Message{
+		To:      email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nUse the link below to confirm %s. It expires in %s.\n\n%s?token=%s\n\nIf you did not ask for this, you can ignore this email.\n",
+			user.Username, email, s.conf.TokenTTL, s.conf.VerifyURL, token,
+		),
+	})
+}
+
+// VerifyEmail consumes a verification token. A token for the current email
+// marks it as verified; a token for the pending email makes it the user's
+// email.
+func (s *emailVerificationService) VerifyEmail(ctx context.Context, req *model.VerifyEmailRequest) error {
+	verification, err := s.verificationRepo.GetByTokenHash(hashSecret(req.Token))
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+
+	now := time.Now()
+	if verification.UsedAt != nil || !now.Before(verification.ExpiresAt) {
+		return ErrInvalidVerificationToken
+	}
+
+	// The token names the user, whatever the organization of the request
+	user, err := s.userRepo.WithContext(tenant.Unscoped(ctx)).GetByID(verification.UserID)
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+
+	previousEmail := user.Email
+	changed := false
+	switch {
+	case user.PendingEmail != nil && *user.PendingEmail == verification.Email:
+		// The address may have been taken since the change was requested
+		existingUser, _ := s.userRepo.WithContext(tenant.Unscoped(ctx)).GetByEmail(verification.Email)
+		if existingUser != nil && existingUser.ID != user.ID {
+			return ErrEmailTaken
+		}
+		user.Email = verification.Email
+		user.PendingEmail = nil
+		changed = true
+	case user.PendingEmail == nil && user.Email == verification.Email:
+	default:
+		// The token was issued for an address the user no longer wants
+		return ErrInvalidVerificationToken
+	}
+
+	consumed, err := s.verificationRepo.MarkUsed(verification.ID, now)
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return ErrInvalidVerificationToken
+	}
+
+	user.EmailVerifiedAt = &now
+	if err := s.userRepo.WithContext(tenant.WithOrganization(ctx, user.OrganizationID)).Update(user); err != nil {
+		return err
+	}
+
+	if changed {
+		s.notifyEmailChanged(user, previousEmail)
+	}
+
+	return nil
+}
+
+// ResendVerification sends a new verification link unless the email is
+// already verified or the last link was sent less than resend_interval ago.
+func (s *emailVerificationService) ResendVerification(ctx context.Context, id uint) error {
+	user, err := s.userRepo.WithContext(ctx).GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if user.PendingEmail == nil && user.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+
+	latest, err := s.verificationRepo.GetLatestForUser(id)
+	if err == nil {
+		if wait := time.Until(latest.CreatedAt.Add(s.conf.ResendInterval)); wait > 0 {
+			return &ResendThrottledError{RetryAfter: wait}
+		}
+	}
+
+	return s.SendVerification(user)
+}
+
+// notifyEmailChanged tells the previous address about the change so that
+// its owner notices if it was not them. Failures are only logged.
+func (s *emailVerificationService) notifyEmailChanged(user *model.User, previousEmail string) {
+	err := s.mailer.Send(&mailer.Message{
+		To:      previousEmail,
+		Subject: "Your email address was changed",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nThe email address of your account was changed to %s.\n\nIf you did not do this, please contact support.\n",
+			user.Username, user.Email,
+		),
+	})
+	if err != nil {
+		log.Printf("Failed to notify %s about email change of user %d: %v", previousEmail, user.ID, err)
+	}
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/mailer"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+func (s *ServiceTestSuite) newVerificationToken(email string) *model.EmailVerificationToken {
+	return &model.EmailVerificationToken{
+		ID:        7,
+		UserID:    1,
+		Email:     email,
+		TokenHash: hashSecret("raw-token"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+}
+
+func (s *ServiceTestSuite) TestSendVerification_SendsLinkToPendingEmail() {
+	user := s.newUserWithPassword("password123")
+	pendingEmail := "new@example.com"
+	user.PendingEmail = &pendingEmail
+	var storedHash string
+
+	s.verifyRepo.On("InvalidateForUser", uint(1), mock.AnythingOfType("time.Time")).Return(nil)
+	s.verifyRepo.On("Create", mock.AnythingOfType("*model.EmailVerificationToken")).Return(nil).Run(func(args mock.Arguments) {
+		token := args.Get(0).(*model.EmailVerificationToken)
+		storedHash = token.TokenHash
+		assert.Equal(s.T(), pendingEmail, token.Email)
+		assert.WithinDuration(s.T(), time.Now().Add(24*time.Hour), token.ExpiresAt, time.Minute)
+	})
+	s.mailer.On("Send", mock.AnythingOfType("*mailer.Message")).Return(nil)
+
+	// Execute
+	err := s.verification.SendVerification(user)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	msg := s.mailer.Calls[0].Arguments.Get(0).(*mailer.Message)
+	assert.Equal(s.T(), pendingEmail, msg.To)
+	assert.Contains(s.T(), msg.Body, "http://localhost/verify-email?token=")
+
+	// The mailed token hashes to the stored hash
+	token := msg.Body[strings.Index(msg.Body, "?token=")+len("?token="):]
+	token = strings.Fields(token)[0]
+	assert.Equal(s.T(), storedHash, hashSecret(token))
+	s.verifyRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestVerifyEmail_CurrentEmail() {
+	user := s.newUserWithPassword("password123")
+
+	s.verifyRepo.On("GetByTokenHash", hashSecret("raw-token")).Return(s.newVerificationToken("test@example.com"), nil)
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.verifyRepo.On("MarkUsed", uint(7), mock.AnythingOfType("time.Time")).Return(true, nil)
+	s.userRepo.On("Update", mock.AnythingOfType("*model.User")).Return(nil)
+
+	// Execute
+	err := s.verification.VerifyEmail(s.ctx, &model.VerifyEmailRequest{Token: "raw-token"})
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), user.EmailVerifiedAt)
+	assert.Equal(s.T(), "test@example.com", user.Email)
+	s.mailer.AssertNotCalled(s.T(), "Send", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestVerifyEmail_PendingEmailBecomesEmail() {
+	user := s.newUserWithPassword("password123")
+	pendingEmail := "new@example.com"
+	user.PendingEmail = &pendingEmail
+
+	s.verifyRepo.On("GetByTokenHash", hashSecret("raw-token")).Return(s.newVerificationToken(pendingEmail), nil)
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.userRepo.On("GetByEmail", pendingEmail).Return(nil, errors.New("not found"))
+	s.verifyRepo.On("MarkUsed", uint(7), mock.AnythingOfType("time.Time")).Return(true, nil)
+	s.userRepo.On("Update", mock.AnythingOfType("*model.User")).Return(nil)
+	s.mailer.On("Send", mock.AnythingOfType("*mailer.Message")).Return(nil)
+
+	// Execute
+	err := s.verification.VerifyEmail(s.ctx, &model.VerifyEmailRequest{Token: "raw-token"})
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), pendingEmail, user.Email)
+	assert.Nil(s.T(), user.PendingEmail)
+	assert.NotNil(s.T(), user.EmailVerifiedAt)
+
+	// The previous address is told about the change
+	msg := s.mailer.Calls[0].Arguments.Get(0).(*mailer.Message)
+	assert.Equal(s.T(), "test@example.com", msg.To)
+	assert.Contains(s.T(), msg.Body, pendingEmail)
+}
+
+func (s *ServiceTestSuite) TestVerifyEmail_PendingEmailTaken() {
+	user := s.newUserWithPassword("password123")
+	pendingEmail := "new@example.com"
+	user.PendingEmail = &pendingEmail
+
+	s.verifyRepo.On("GetByTokenHash", hashSecret("raw-token")).Return(s.newVerificationToken(pendingEmail), nil)
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.userRepo.On("GetByEmail", pendingEmail).Return(&model.User{ID: 2, Email: pendingEmail}, nil)
+
+	// Execute
+	err := s.verification.VerifyEmail(s.ctx, &model.VerifyEmailRequest{Token: "raw-token"})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrEmailTaken)
+	assert.Equal(s.T(), "test@example.com", user.Email)
+	s.verifyRepo.AssertNotCalled(s.T(), "MarkUsed", mock.Anything, mock.Anything)
+	s.userRepo.AssertNotCalled(s.T(), "Update", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestVerifyEmail_SupersededPendingEmail() {
+	user := s.newUserWithPassword("password123")
+	pendingEmail := "newer@example.com"
+	user.PendingEmail = &pendingEmail
+
+	s.verifyRepo.On("GetByTokenHash", hashSecret("raw-token")).Return(s.newVerificationToken("new@example.com"), nil)
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+
+	// Execute
+	err := s.verification.VerifyEmail(s.ctx, &model.VerifyEmailRequest{Token: "raw-token"})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidVerificationToken)
+	s.userRepo.AssertNotCalled(s.T(), "Update", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestVerifyEmail_UnknownToken() {
+	s.verifyRepo.On("GetByTokenHash", hashSecret("raw-token")).Return(nil, errors.New("not found"))
+
+	// Execute
+	err := s.verification.VerifyEmail(s.ctx, &model.VerifyEmailRequest{Token: "raw-token"})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidVerificationToken)
+}
+
+func (s *ServiceTestSuite) TestVerifyEmail_ExpiredToken() {
+	token := s.newVerificationToken("test@example.com")
+	token.ExpiresAt = time.Now().Add(-time.Minute)
+
+	s.verifyRepo.On("GetByTokenHash", hashSecret("raw-token")).Return(token, nil)
+
+	// Execute
+	err := s.verification.VerifyEmail(s.ctx, &model.VerifyEmailRequest{Token: "raw-token"})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidVerificationToken)
+	s.userRepo.AssertNotCalled(s.T(), "GetByID", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestVerifyEmail_AlreadyConsumed() {
+	user := s.newUserWithPassword("password123")
+
+	s.verifyRepo.On("GetByTokenHash", hashSecret("raw-token")).Return(s.newVerificationToken("test@example.com"), nil)
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.verifyRepo.On("MarkUsed", uint(7), mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	// Execute
+	err := s.verification.VerifyEmail(s.ctx, &model.VerifyEmailRequest{Token: "raw-token"})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidVerificationToken)
+	s.userRepo.AssertNotCalled(s.T(), "Update", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestResendVerification_Success() {
+	user := s.newUserWithPassword("password123")
+	lastToken := s.newVerificationToken("test@example.com")
+	lastToken.CreatedAt = time.Now().Add(-2 * time.Minute)
+
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.verifyRepo.On("GetLatestForUser", uint(1)).Return(lastToken, nil)
+	s.verifyRepo.On("InvalidateForUser", uint(1), mock.AnythingOfType("time.Time")).Return(nil)
+	s.verifyRepo.On("Create", mock.AnythingOfType("*model.EmailVerificationToken")).Return(nil)
+	s.mailer.On("Send", mock.AnythingOfType("*mailer.Message")).Return(nil)
+
+	// Execute
+	err := s.verification.ResendVerification(s.ctx, 1)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	s.mailer.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestResendVerification_Throttled() {
+	user := s.newUserWithPassword("password123")
+	lastToken := s.newVerificationToken("test@example.com")
+	lastToken.CreatedAt = time.Now().Add(-20 * time.Second)
+
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.verifyRepo.On("GetLatestForUser", uint(1)).Return(lastToken, nil)
+
+	// Execute
+	err := s.verification.ResendVerification(s.ctx, 1)
+
+	// Assert
+	var throttledErr *ResendThrottledError
+	assert.ErrorAs(s.T(), err, &throttledErr)
+	assert.InDelta(s.T(), 40*time.Second, throttledErr.RetryAfter, float64(5*time.Second))
+	s.mailer.AssertNotCalled(s.T(), "Send", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestResendVerification_AlreadyVerified() {
+	user := s.newUserWithPassword("password123")
+	verifiedAt := time.Now()
+	user.EmailVerifiedAt = &verifiedAt
+
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+
+	// Execute
+	err := s.verification.ResendVerification(s.ctx, 1)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrEmailAlreadyVerified)
+	s.verifyRepo.AssertNotCalled(s.T(), "GetLatestForUser", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestResendVerification_UserNotFound() {
+	s.userRepo.On("GetByID", uint(1)).Return(nil, errors.New("not found"))
+
+	// Execute
+	err := s.verification.ResendVerification(s.ctx, 1)
+
+	// Assert
+	assert.Error(s.T(), err)
+	s.mailer.AssertNotCalled(s.T(), "Send", mock.Anything)
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+)
+
+var ErrInvalidGatewayTicket = errors.New("invalid or expired gateway ticket")
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=GatewayTicketService --output=./mocks/service --outpkg=service --filename=gateway_ticket_service.go --structname=MockGatewayTicketService --with-expecter=false
+type GatewayTicketService interface {
+	IssueTicket(ctx context.Context, userID uint) (*model.GatewayTicketResponse, error)
+	RedeemTicket(ctx context.Context, ticket string) (*model.GatewayTicket, error)
+}
+
+type gatewayTicketService struct {
+	ticketRepo repository.GatewayTicketRepository
+	ttl        time.Duration
+}
+
+func NewGatewayTicketService(ticketRepo repository.GatewayTicketRepository, conf *config.Config) GatewayTicketService {
+	return &gatewayTicketService{
+		ticketRepo: ticketRepo,
+		ttl:        conf.Gateway.TicketTTL,
+	}
+}
+
+// IssueTicket returns a ticket that lets the user connect to the gateway
+// once, in the organization of ctx, within the ticket TTL.
+func (s *gatewayTicketService) IssueTicket(ctx context.Context, userID uint) (*model.GatewayTicketResponse, error) {
+	token, tokenHash, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+
+	ticket := &model.GatewayTicket{
+		UserID:    userID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(s.ttl),
+	}
+	if err := s.ticketRepo.WithContext(ctx).Create(ticket); err != nil {
+		return nil, err
+	}
+
+	return &model.GatewayTicketResponse{
+		Ticket:    token,
+		ExpiresAt: ticket.ExpiresAt,
+	}, nil
+}
+
+// RedeemTicket consumes a ticket and returns it, with the user and the
+// organization it was issued for. Unknown, used and expired tickets are
+// rejected with ErrInvalidGatewayTicket.
+func (s *gatewayTicketService) RedeemTicket(ctx context.Context, token string) (*model.GatewayTicket, error) {
+	if token == "" {
+		return nil, ErrInvalidGatewayTicket
+	}
+
+	// The ticket names the organization, whatever that of the request
+	tickets := s.ticketRepo.WithContext(tenant.Unscoped(ctx))
+	ticket, err := tickets.GetByTokenHash(hashSecret(token))
+	if err != nil {
+		return nil, ErrInvalidGatewayTicket
+	}
+
+	now := time.Now()
+	if ticket.UsedAt != nil || !now.Before(ticket.ExpiresAt) {
+		return nil, ErrInvalidGatewayTicket
+	}
+
+	consumed, err := tickets.MarkUsed(ticket.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, ErrInvalidGatewayTicket
+	}
+	ticket.UsedAt = &now
+	return ticket, nil
+}
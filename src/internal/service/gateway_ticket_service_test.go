@@ -0,0 +1,96 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/gorm"
+)
+
+// Test IssueTicket
+func (s *ServiceTestSuite) TestIssueTicket() {
+	var stored *model.GatewayTicket
+	s.ticketRepo.On("Create", mock.AnythingOfType("*model.GatewayTicket")).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*model.GatewayTicket)
+	}).Return(nil)
+
+	resp, err := s.tickets.IssueTicket(s.ctx, 7)
+
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), resp.Ticket)
+	assert.WithinDuration(s.T(), time.Now().Add(30*time.Second), resp.ExpiresAt, time.Second)
+	assert.Equal(s.T(), uint(7), stored.UserID)
+	// Only the hash is stored
+	assert.Equal(s.T(), hashSecret(resp.Ticket), stored.TokenHash)
+	assert.Equal(s.T(), resp.ExpiresAt, stored.ExpiresAt)
+}
+
+func (s *ServiceTestSuite) TestIssueTicket_CreateFails() {
+	s.ticketRepo.On("Create", mock.Anything).Return(errors.New("db down"))
+
+	resp, err := s.tickets.IssueTicket(s.ctx, 7)
+
+	assert.Error(s.T(), err)
+	assert.Nil(s.T(), resp)
+}
+
+// Test RedeemTicket
+func (s *ServiceTestSuite) TestRedeemTicket() {
+	ticket := &model.GatewayTicket{ID: 3, OrganizationID: 2, UserID: 7, ExpiresAt: time.Now().Add(time.Minute)}
+	s.ticketRepo.On("GetByTokenHash", hashSecret("ticket-1")).Return(ticket, nil)
+	s.ticketRepo.On("MarkUsed", uint(3), mock.AnythingOfType("time.Time")).Return(true, nil)
+
+	redeemed, err := s.tickets.RedeemTicket(s.ctx, "ticket-1")
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), uint(7), redeemed.UserID)
+	assert.Equal(s.T(), uint(2), redeemed.OrganizationID)
+	assert.NotNil(s.T(), redeemed.UsedAt)
+}
+
+func (s *ServiceTestSuite) TestRedeemTicket_Empty() {
+	_, err := s.tickets.RedeemTicket(s.ctx, "")
+
+	assert.ErrorIs(s.T(), err, ErrInvalidGatewayTicket)
+}
+
+func (s *ServiceTestSuite) TestRedeemTicket_Unknown() {
+	s.ticketRepo.On("GetByTokenHash", hashSecret("ticket-1")).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := s.tickets.RedeemTicket(s.ctx, "ticket-1")
+
+	assert.ErrorIs(s.T(), err, ErrInvalidGatewayTicket)
+}
+
+func (s *ServiceTestSuite) TestRedeemTicket_Expired() {
+	ticket := &model.GatewayTicket{ID: 3, OrganizationID: 1, UserID: 7, ExpiresAt: time.Now().Add(-time.Second)}
+	s.ticketRepo.On("GetByTokenHash", hashSecret("ticket-1")).Return(ticket, nil)
+
+	_, err := s.tickets.RedeemTicket(s.ctx, "ticket-1")
+
+	assert.ErrorIs(s.T(), err, ErrInvalidGatewayTicket)
+	s.ticketRepo.AssertNotCalled(s.T(), "MarkUsed", mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestRedeemTicket_AlreadyUsed() {
+	usedAt := time.Now()
+	ticket := &model.GatewayTicket{ID: 3, OrganizationID: 1, UserID: 7, ExpiresAt: time.Now().Add(time.Minute), UsedAt: &usedAt}
+	s.ticketRepo.On("GetByTokenHash", hashSecret("ticket-1")).Return(ticket, nil)
+
+	_, err := s.tickets.RedeemTicket(s.ctx, "ticket-1")
+
+	assert.ErrorIs(s.T(), err, ErrInvalidGatewayTicket)
+}
+
+func (s *ServiceTestSuite) TestRedeemTicket_UsedConcurrently() {
+	ticket := &model.GatewayTicket{ID: 3, OrganizationID: 1, UserID: 7, ExpiresAt: time.Now().Add(time.Minute)}
+	s.ticketRepo.On("GetByTokenHash", hashSecret("ticket-1")).Return(ticket, nil)
+	s.ticketRepo.On("MarkUsed", uint(3), mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	_, err := s.tickets.RedeemTicket(s.ctx, "ticket-1")
+
+	assert.ErrorIs(s.T(), err, ErrInvalidGatewayTicket)
+}
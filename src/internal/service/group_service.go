@@ -0,0 +1,409 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrGroupNotFound       = errors.New("group not found")
+	ErrGroupNameTaken      = errors.New("group name already exists")
+	ErrParentGroupNotFound = errors.New("parent group not found")
+	ErrGroupCycle          = errors.New("a group cannot be nested in itself or its subgroups")
+	ErrGroupUserNotMember  = errors.New("user is not a member of this organization")
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=GroupService --output=./mocks/service --outpkg=service --filename=group_service.go --structname=MockGroupService --with-expecter=false
+type GroupService interface {
+	CreateGroup(ctx context.Context, req *model.CreateGroupRequest) (*model.GroupResponse, error)
+	ListGroups(ctx context.Context) ([]*model.GroupResponse, error)
+	GetGroup(ctx context.Context, id uint) (*model.GroupResponse, error)
+	UpdateGroup(ctx context.Context, id uint, req *model.UpdateGroupRequest) (*model.GroupResponse, error)
+	DeleteGroup(ctx context.Context, id uint) error
+	ListMembers(ctx context.Context, id uint) ([]*model.GroupMemberResponse, error)
+	AddMembers(ctx context.Context, id uint, req *model.GroupMembersRequest) ([]*model.GroupMemberResponse, error)
+	RemoveMembers(ctx context.Context, id uint, req *model.GroupMembersRequest) error
+	SetPermissions(ctx context.Context, id uint, req *model.GroupPermissionsRequest) (*model.GroupResponse, error)
+	ListUserGroups(ctx context.Context, userID uint) (*model.UserGroupsResponse, error)
+	HasPermission(ctx context.Context, userID uint, permission string) (bool, error)
+}
+
+type groupService struct {
+	groupRepo      repository.GroupRepository
+	membershipRepo repository.MembershipRepository
+}
+
+func NewGroupService(groupRepo repository.GroupRepository, membershipRepo repository.MembershipRepository) GroupService {
+	return &groupService{
+		groupRepo:      groupRepo,
+		membershipRepo: membershipRepo,
+	}
+}
+
+// CreateGroup creates a group in the organization of ctx, optionally nested
+// in another one.
+func (s *groupService) CreateGroup(ctx context.Context, req *model.CreateGroupRequest) (*model.GroupResponse, error) {
+	groups := s.groupRepo.WithContext(ctx)
+
+	if err := s.checkName(groups, 0, req.Name); err != nil {
+		return nil, err
+	}
+	if err := s.checkParent(groups, 0, req.ParentID); err != nil {
+		return nil, err
+	}
+
+	group := &model.Group{
+		Name:     req.Name,
+		ParentID: req.ParentID,
+	}
+	if err := groups.Create(group); err != nil {
+		return nil, err
+	}
+	return toGroupResponse(group, nil), nil
+}
+
+func (s *groupService) ListGroups(ctx context.Context) ([]*model.GroupResponse, error) {
+	groups := s.groupRepo.WithContext(ctx)
+
+	all, err := groups.List()
+	if err != nil {
+		return nil, err
+	}
+	return s.toGroupResponses(groups, all)
+}
+
+func (s *groupService) GetGroup(ctx context.Context, id uint) (*model.GroupResponse, error) {
+	groups := s.groupRepo.WithContext(ctx)
+
+	group, err := s.getGroup(groups, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toGroupResponse(groups, group)
+}
+
+// UpdateGroup renames a group and moves it under another parent, or to the
+// top level. A group cannot be moved into itself or one of its subgroups.
+func (s *groupService) UpdateGroup(ctx context.Context, id uint, req *model.UpdateGroupRequest) (*model.GroupResponse, error) {
+	groups := s.groupRepo.WithContext(ctx)
+
+	group, err := s.getGroup(groups, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkName(groups, id, req.Name); err != nil {
+		return nil, err
+	}
+	if err := s.checkParent(groups, id, req.ParentID); err != nil {
+		return nil, err
+	}
+
+	group.Name = req.Name
+	group.ParentID = req.ParentID
+	updated, err := groups.Update(group)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrGroupNotFound
+	}
+	return s.toGroupResponse(groups, group)
+}
+
+// DeleteGroup deletes a group. Its subgroups move up to its parent.
+func (s *groupService) DeleteGroup(ctx context.Context, id uint) error {
+	deleted, err := s.groupRepo.WithContext(ctx).Delete(id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrGroupNotFound
+	}
+	return nil
+}
+
+// ListMembers lists the direct members of a group.
+func (s *groupService) ListMembers(ctx context.Context, id uint) ([]*model.GroupMemberResponse, error) {
+	groups := s.groupRepo.WithContext(ctx)
+
+	if _, err := s.getGroup(groups, id); err != nil {
+		return nil, err
+	}
+
+	members, err := groups.ListMembers(id)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*model.GroupMemberResponse, 0, len(members))
+	for _, member := range members {
+		responses = append(responses, &model.GroupMemberResponse{
+			UserID:    member.UserID,
+			CreatedAt: member.CreatedAt,
+		})
+	}
+	return responses, nil
+}
+
+// AddMembers puts members of the organization in a group and returns its
+// members. Users already in the group are skipped; if any user is not a
+// member of the organization none are added.
+func (s *groupService) AddMembers(ctx context.Context, id uint, req *model.GroupMembersRequest) ([]*model.GroupMemberResponse, error) {
+	groups := s.groupRepo.WithContext(ctx)
+
+	if _, err := s.getGroup(groups, id); err != nil {
+		return nil, err
+	}
+
+	memberships, err := s.membershipRepo.WithContext(ctx).List()
+	if err != nil {
+		return nil, err
+	}
+	members := make(map[uint]bool, len(memberships))
+	for _, membership := range memberships {
+		members[membership.UserID] = true
+	}
+	for _, userID := range req.UserIDs {
+		if !members[userID] {
+			return nil, ErrGroupUserNotMember
+		}
+	}
+
+	if _, err := groups.AddMembers(id, req.UserIDs); err != nil {
+		return nil, err
+	}
+	return s.ListMembers(ctx, id)
+}
+
+// RemoveMembers takes users out of a group. Users not in it are ignored.
+func (s *groupService) RemoveMembers(ctx context.Context, id uint, req *model.GroupMembersRequest) error {
+	groups := s.groupRepo.WithContext(ctx)
+
+	if _, err := s.getGroup(groups, id); err != nil {
+		return err
+	}
+
+	_, err := groups.RemoveMembers(id, req.UserIDs)
+	return err
+}
+
+// SetPermissions replaces the permissions granted to a group.
+func (s *groupService) SetPermissions(ctx context.Context, id uint, req *model.GroupPermissionsRequest) (*model.GroupResponse, error) {
+	groups := s.groupRepo.WithContext(ctx)
+
+	group, err := s.getGroup(groups, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := groups.SetPermissions(id, req.Permissions); err != nil {
+		return nil, err
+	}
+	return s.toGroupResponse(groups, group)
+}
+
+// ListUserGroups returns the groups the user is in, directly or through a
+// subgroup, and the permissions granted to them.
+func (s *groupService) ListUserGroups(ctx context.Context, userID uint) (*model.UserGroupsResponse, error) {
+	groups := s.groupRepo.WithContext(ctx)
+
+	effective, err := s.effectiveGroups(groups, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := s.toGroupResponses(groups, effective)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	permissions := make([]string, 0)
+	for _, response := range responses {
+		for _, permission := range response.Permissions {
+			if !seen[permission] {
+				seen[permission] = true
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+	return &model.UserGroupsResponse{Groups: responses, Permissions: permissions}, nil
+}
+
+// HasPermission reports whether a user holds a permission in the
+// organization of ctx. Owners and admins hold every permission; other
+// members hold the ones granted to their groups and the groups above them.
+func (s *groupService) HasPermission(ctx context.Context, userID uint, permission string) (bool, error) {
+	membership, err := s.membershipRepo.WithContext(ctx).Get(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if membership.Role == model.OrganizationRoleOwner || membership.Role == model.OrganizationRoleAdmin {
+		return true, nil
+	}
+
+	groups := s.groupRepo.WithContext(ctx)
+	effective, err := s.effectiveGroups(groups, userID)
+	if err != nil || len(effective) == 0 {
+		return false, err
+	}
+
+	grants, err := groups.ListPermissions(groupIDs(effective))
+	if err != nil {
+		return false, err
+	}
+	for _, grant := range grants {
+		if grant.Permission == permission {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// effectiveGroups returns the groups the user is a direct member of and all
+// groups above them, by name.
+func (s *groupService) effectiveGroups(groups repository.GroupRepository, userID uint) ([]*model.Group, error) {
+	direct, err := groups.ListByUser(userID)
+	if err != nil || len(direct) == 0 {
+		return nil, err
+	}
+
+	all, err := groups.List()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*model.Group, len(all))
+	for _, group := range all {
+		byID[group.ID] = group
+	}
+
+	in := make(map[uint]bool)
+	for _, group := range direct {
+		// Stops at groups already seen, so a cycle cannot loop forever
+		for current := byID[group.ID]; current != nil && !in[current.ID]; {
+			in[current.ID] = true
+			if current.ParentID == nil {
+				break
+			}
+			current = byID[*current.ParentID]
+		}
+	}
+
+	effective := make([]*model.Group, 0, len(in))
+	for _, group := range all {
+		if in[group.ID] {
+			effective = append(effective, group)
+		}
+	}
+	return effective, nil
+}
+
+func (s *groupService) getGroup(groups repository.GroupRepository, id uint) (*model.Group, error) {
+	group, err := groups.GetByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrGroupNotFound
+	}
+	return group, err
+}
+
+// checkName checks that no group but the one with id has the name.
+func (s *groupService) checkName(groups repository.GroupRepository, id uint, name string) error {
+	existing, err := groups.GetByName(name)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if existing != nil && existing.ID != id {
+		return ErrGroupNameTaken
+	}
+	return nil
+}
+
+// checkParent checks that the parent exists and, for an existing group, is
+// neither the group itself nor one of its subgroups.
+func (s *groupService) checkParent(groups repository.GroupRepository, id uint, parentID *uint) error {
+	if parentID == nil {
+		return nil
+	}
+
+	seen := make(map[uint]bool)
+	for current := *parentID; ; {
+		if id != 0 && current == id {
+			return ErrGroupCycle
+		}
+		if seen[current] {
+			// An existing cycle that does not involve the group
+			return nil
+		}
+		seen[current] = true
+
+		group, err := groups.GetByID(current)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if current == *parentID {
+				return ErrParentGroupNotFound
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if group.ParentID == nil {
+			return nil
+		}
+		current = *group.ParentID
+	}
+}
+
+func (s *groupService) toGroupResponse(groups repository.GroupRepository, group *model.Group) (*model.GroupResponse, error) {
+	responses, err := s.toGroupResponses(groups, []*model.Group{group})
+	if err != nil {
+		return nil, err
+	}
+	return responses[0], nil
+}
+
+// toGroupResponses converts groups with the permissions granted to them.
+func (s *groupService) toGroupResponses(groups repository.GroupRepository, list []*model.Group) ([]*model.GroupResponse, error) {
+	grants, err := groups.ListPermissions(groupIDs(list))
+	if err != nil {
+		return nil, err
+	}
+	permissions := make(map[uint][]string)
+	for _, grant := range grants {
+		permissions[grant.GroupID] = append(permissions[grant.GroupID], grant.Permission)
+	}
+
+	responses := make([]*model.GroupResponse, 0, len(list))
+	for _, group := range list {
+		responses = append(responses, toGroupResponse(group, permissions[group.ID]))
+	}
+	return responses, nil
+}
+
+func groupIDs(groups []*model.Group) []uint {
+	ids := make([]uint, 0, len(groups))
+	for _, group := range groups {
+		ids = append(ids, group.ID)
+	}
+	return ids
+}
+
+func toGroupResponse(group *model.Group, permissions []string) *model.GroupResponse {
+	if permissions == nil {
+		permissions = []string{}
+	}
+	return &model.GroupResponse{
+		ID:          group.ID,
+		Name:        group.Name,
+		ParentID:    group.ParentID,
+		Permissions: permissions,
+		CreatedAt:   group.CreatedAt,
+		UpdatedAt:   group.UpdatedAt,
+	}
+}
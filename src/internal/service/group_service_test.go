@@ -0,0 +1,228 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/gorm"
+)
+
+func groupID(id uint) *uint {
+	return &id
+}
+
+// expectGroupTree expects the groups of the organization: Engineering (1)
+// with Backend (2) under it and Platform (3) under Backend, and Sales (4).
+// Engineering grants groups:manage.
+func (s *ServiceTestSuite) expectGroupTree() {
+	tree := []*model.Group{
+		{ID: 2, Name: "Backend", ParentID: groupID(1)},
+		{ID: 1, Name: "Engineering"},
+		{ID: 3, Name: "Platform", ParentID: groupID(2)},
+		{ID: 4, Name: "Sales"},
+	}
+	for _, group := range tree {
+		s.groupRepo.On("GetByID", group.ID).Return(group, nil).Maybe()
+	}
+	s.groupRepo.On("GetByID", mock.Anything).Return(nil, gorm.ErrRecordNotFound).Maybe()
+	s.groupRepo.On("List").Return(tree, nil).Maybe()
+	s.groupRepo.On("ListPermissions", mock.Anything).Return(func(ids []uint) []*model.GroupPermission {
+		for _, id := range ids {
+			if id == 1 {
+				return []*model.GroupPermission{{GroupID: 1, Permission: model.PermissionGroupsManage}}
+			}
+		}
+		return nil
+	}, nil).Maybe()
+}
+
+// Test CreateGroup
+func (s *ServiceTestSuite) TestCreateGroup_Success() {
+	s.expectGroupTree()
+	s.groupRepo.On("GetByName", "QA").Return(nil, gorm.ErrRecordNotFound)
+	s.groupRepo.On("Create", mock.MatchedBy(func(group *model.Group) bool {
+		return group.Name == "QA" && *group.ParentID == 1
+	})).Return(nil)
+
+	// Execute
+	result, err := s.groups.CreateGroup(s.ctx, &model.CreateGroupRequest{Name: "QA", ParentID: groupID(1)})
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "QA", result.Name)
+	assert.Empty(s.T(), result.Permissions)
+}
+
+func (s *ServiceTestSuite) TestCreateGroup_NameTaken() {
+	s.groupRepo.On("GetByName", "Sales").Return(&model.Group{ID: 4, Name: "Sales"}, nil)
+
+	// Execute
+	_, err := s.groups.CreateGroup(s.ctx, &model.CreateGroupRequest{Name: "Sales"})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrGroupNameTaken)
+	s.groupRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestCreateGroup_ParentNotFound() {
+	s.expectGroupTree()
+	s.groupRepo.On("GetByName", "QA").Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	_, err := s.groups.CreateGroup(s.ctx, &model.CreateGroupRequest{Name: "QA", ParentID: groupID(9)})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrParentGroupNotFound)
+}
+
+// Test UpdateGroup
+func (s *ServiceTestSuite) TestUpdateGroup_Move() {
+	s.expectGroupTree()
+	s.groupRepo.On("GetByName", "Platform").Return(&model.Group{ID: 3, Name: "Platform"}, nil)
+	s.groupRepo.On("Update", mock.MatchedBy(func(group *model.Group) bool {
+		return group.ID == 3 && *group.ParentID == 4
+	})).Return(true, nil)
+
+	// Execute
+	result, err := s.groups.UpdateGroup(s.ctx, 3, &model.UpdateGroupRequest{Name: "Platform", ParentID: groupID(4)})
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), uint(4), *result.ParentID)
+}
+
+func (s *ServiceTestSuite) TestUpdateGroup_Cycle() {
+	s.expectGroupTree()
+	s.groupRepo.On("GetByName", "Engineering").Return(&model.Group{ID: 1, Name: "Engineering"}, nil)
+
+	// Execute: into itself, and under its grandchild
+	for _, parentID := range []uint{1, 3} {
+		_, err := s.groups.UpdateGroup(s.ctx, 1, &model.UpdateGroupRequest{Name: "Engineering", ParentID: groupID(parentID)})
+
+		// Assert
+		assert.ErrorIs(s.T(), err, ErrGroupCycle)
+	}
+	s.groupRepo.AssertNotCalled(s.T(), "Update", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestUpdateGroup_NotFound() {
+	s.expectGroupTree()
+
+	// Execute
+	_, err := s.groups.UpdateGroup(s.ctx, 9, &model.UpdateGroupRequest{Name: "QA"})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrGroupNotFound)
+}
+
+// Test DeleteGroup
+func (s *ServiceTestSuite) TestDeleteGroup_NotFound() {
+	s.groupRepo.On("Delete", uint(9)).Return(false, nil)
+
+	// Execute
+	err := s.groups.DeleteGroup(s.ctx, 9)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrGroupNotFound)
+}
+
+// Test AddMembers
+func (s *ServiceTestSuite) TestAddMembers_Success() {
+	s.expectGroupTree()
+	s.membershipRepo.On("WithContext", mock.Anything).Return(s.membershipRepo)
+	s.membershipRepo.On("List").Return([]*model.Membership{{UserID: 1}, {UserID: 2}}, nil)
+	s.groupRepo.On("AddMembers", uint(4), []uint{1, 2}).Return(int64(2), nil)
+	s.groupRepo.On("ListMembers", uint(4)).Return([]*model.GroupMember{{GroupID: 4, UserID: 1}, {GroupID: 4, UserID: 2}}, nil)
+
+	// Execute
+	result, err := s.groups.AddMembers(s.ctx, 4, &model.GroupMembersRequest{UserIDs: []uint{1, 2}})
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result, 2)
+}
+
+func (s *ServiceTestSuite) TestAddMembers_NotOrganizationMember() {
+	s.expectGroupTree()
+	s.membershipRepo.On("WithContext", mock.Anything).Return(s.membershipRepo)
+	s.membershipRepo.On("List").Return([]*model.Membership{{UserID: 1}}, nil)
+
+	// Execute
+	_, err := s.groups.AddMembers(s.ctx, 4, &model.GroupMembersRequest{UserIDs: []uint{1, 2}})
+
+	// Assert: none are added
+	assert.ErrorIs(s.T(), err, ErrGroupUserNotMember)
+	s.groupRepo.AssertNotCalled(s.T(), "AddMembers", mock.Anything, mock.Anything)
+}
+
+// Test ListUserGroups
+func (s *ServiceTestSuite) TestListUserGroups_Nested() {
+	s.expectGroupTree()
+	s.groupRepo.On("ListByUser", uint(2)).Return([]*model.Group{{ID: 3, Name: "Platform", ParentID: groupID(2)}}, nil)
+
+	// Execute
+	result, err := s.groups.ListUserGroups(s.ctx, 2)
+
+	// Assert: membership of Platform extends to the groups above it
+	assert.NoError(s.T(), err)
+	names := make([]string, 0, len(result.Groups))
+	for _, group := range result.Groups {
+		names = append(names, group.Name)
+	}
+	assert.Equal(s.T(), []string{"Backend", "Engineering", "Platform"}, names)
+	assert.Equal(s.T(), []string{model.PermissionGroupsManage}, result.Permissions)
+}
+
+// Test HasPermission
+func (s *ServiceTestSuite) TestHasPermission_ThroughGroup() {
+	s.expectGroupTree()
+	s.membershipRepo.On("WithContext", mock.Anything).Return(s.membershipRepo)
+	s.membershipRepo.On("Get", uint(2)).Return(&model.Membership{UserID: 2, Role: model.OrganizationRoleMember}, nil)
+	s.groupRepo.On("ListByUser", uint(2)).Return([]*model.Group{{ID: 3, Name: "Platform", ParentID: groupID(2)}}, nil)
+
+	// Execute
+	allowed, err := s.groups.HasPermission(s.ctx, 2, model.PermissionGroupsManage)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), allowed)
+}
+
+func (s *ServiceTestSuite) TestHasPermission_NoGroup() {
+	s.expectGroupTree()
+	s.membershipRepo.On("WithContext", mock.Anything).Return(s.membershipRepo)
+	s.membershipRepo.On("Get", uint(2)).Return(&model.Membership{UserID: 2, Role: model.OrganizationRoleMember}, nil)
+	s.groupRepo.On("ListByUser", uint(2)).Return([]*model.Group{{ID: 4, Name: "Sales"}}, nil)
+
+	// Execute
+	allowed, err := s.groups.HasPermission(s.ctx, 2, model.PermissionGroupsManage)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), allowed)
+}
+
+func (s *ServiceTestSuite) TestHasPermission_Admin() {
+	s.membershipRepo.On("WithContext", mock.Anything).Return(s.membershipRepo)
+	s.membershipRepo.On("Get", uint(1)).Return(&model.Membership{UserID: 1, Role: model.OrganizationRoleAdmin}, nil)
+
+	// Execute
+	allowed, err := s.groups.HasPermission(s.ctx, 1, model.PermissionGroupsManage)
+
+	// Assert: admins need no group
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), allowed)
+	s.groupRepo.AssertNotCalled(s.T(), "ListByUser", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestHasPermission_NotMember() {
+	s.membershipRepo.On("WithContext", mock.Anything).Return(s.membershipRepo)
+	s.membershipRepo.On("Get", uint(3)).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	allowed, err := s.groups.HasPermission(s.ctx, 3, model.PermissionGroupsManage)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), allowed)
+}
@@ -0,0 +1,515 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/oidc"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
+)
+
+// Error codes of RFC 6749 and OpenID Connect Core.
+const (
+	OAuthInvalidRequest          = "invalid_request"
+	OAuthInvalidClient           = "invalid_client"
+	OAuthInvalidGrant            = "invalid_grant"
+	OAuthInvalidScope            = "invalid_scope"
+	OAuthInvalidToken            = "invalid_token"
+	OAuthAccessDenied            = "access_denied"
+	OAuthUnsupportedResponseType = "unsupported_response_type"
+	OAuthUnsupportedGrantType    = "unsupported_grant_type"
+	OAuthConsentRequired         = "consent_required"
+	OAuthServerError             = "server_error"
+)
+
+// Token types set in the typ header, so that an ID token cannot be used as
+// an access token.
+const (
+	accessTokenType = "at+jwt"
+	idTokenType     = "JWT"
+)
+
+var (
+	ErrSessionRequired    = errors.New("authorization requires a user session")
+	ErrConsentNotFound    = errors.New("consent not found")
+	errInvalidAccessToken = &OAuthError{Code: OAuthInvalidToken, Description: "The access token is invalid or expired"}
+)
+
+// OAuthError is an error response of the authorization or token endpoint.
+type OAuthError struct {
+	Code        string
+	Description string
+}
+
+func (e *OAuthError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+// providerAccessTokenClaims are the claims of an access token issued to a
+// client (RFC 9068).
+type providerAccessTokenClaims struct {
+	ClientID string `json:"client_id"`
+	Scope    string `json:"scope"`
+	jwt.RegisteredClaims
+}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=IdentityProviderService --output=./mocks/service --outpkg=service --filename=identity_provider_service.go --structname=MockIdentityProviderService --with-expecter=false
+type IdentityProviderService interface {
+	Discovery() *model.OpenIDConfiguration
+	JWKS() (*model.JSONWebKeySet, error)
+	Authorize(caller *model.Principal, req *model.AuthorizeRequest) (*model.AuthorizeResponse, error)
+	Token(ctx context.Context, req *model.TokenRequest) (*model.TokenResponse, error)
+	UserInfo(ctx context.Context, accessToken string) (*model.UserInfo, error)
+	ListConsents(ctx context.Context, userID uint) ([]*model.OAuthConsentResponse, error)
+	RevokeConsent(userID uint, clientID string) error
+}
+
+type identityProviderService struct {
+	clientRepo  repository.OAuthClientRepository
+	consentRepo repository.OAuthConsentRepository
+	codeRepo    repository.OAuthCodeRepository
+	userRepo    repository.UserRepository
+	sessionRepo repository.SessionRepository
+	keys        SigningKeyService
+	issuer      string
+	loginURL    string
+	codeTTL     time.Duration
+	tokenTTL    time.Duration
+}
+
+func NewIdentityProviderService(
+	clientRepo repository.OAuthClientRepository,
+	consentRepo repository.OAuthConsentRepository,
+	codeRepo repository.OAuthCodeRepository,
+	userRepo repository.UserRepository,
+	sessionRepo repository.SessionRepository,
+	keys SigningKeyService,
+	conf *config.Config,
+) IdentityProviderService {
+	idp := conf.IdentityProvider
+	return &identityProviderService{
+		clientRepo:  clientRepo,
+		consentRepo: consentRepo,
+		codeRepo:    codeRepo,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		keys:        keys,
+		issuer:      strings.TrimSuffix(idp.Issuer, "/"),
+		loginURL:    idp.LoginURL,
+		codeTTL:     idp.CodeTTL,
+		tokenTTL:    idp.TokenTTL,
+	}
+}
+
+func (s *identityProviderService) Discovery() *model.OpenIDConfiguration {
+	authorizationEndpoint := s.loginURL
+	if authorizationEndpoint == "" {
+		authorizationEndpoint = s.issuer + "/api/v1/oauth/authorize"
+	}
+
+	return &model.OpenIDConfiguration{
+		Issuer:                            s.issuer,
+		AuthorizationEndpoint:             authorizationEndpoint,
+		TokenEndpoint:                     s.issuer + "/api/v1/oauth/token",
+		UserInfoEndpoint:                  s.issuer + "/api/v1/oauth/userinfo",
+		JWKSURI:                           s.issuer + "/api/v1/oauth/jwks",
+		ScopesSupported:                   []string{model.ScopeOpenID, model.ScopeProfile, model.ScopeEmail},
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{"authorization_code"},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{jwt.SigningMethodRS256.Alg()},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{"S256"},
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "auth_time", "nonce", "preferred_username", "updated_at", "email", "email_verified"},
+	}
+}
+
+func (s *identityProviderService) JWKS() (*model.JSONWebKeySet, error) {
+	return s.keys.PublicKeys()
+}
+
+// Authorize handles an authentication request on behalf of the signed-in
+// user. Requests with an unknown client or redirect URI fail with an
+// *OAuthError; every other outcome, including errors, is a redirect back to
+// the client unless the user first has to consent.
+func (s *identityProviderService) Authorize(caller *model.Principal, req *model.AuthorizeRequest) (*model.AuthorizeResponse, error) {
+	if caller.SessionID == "" {
+		return nil, ErrSessionRequired
+	}
+
+	client, err := s.clientRepo.GetByClientID(req.ClientID)
+	if err != nil || client.RevokedAt != nil {
+		return nil, &OAuthError{Code: OAuthInvalidRequest, Description: "Unknown client"}
+	}
+	if !model.ScopeList(client.RedirectURIs).Contains(req.RedirectURI) {
+		return nil, &OAuthError{Code: OAuthInvalidRequest, Description: "Unregistered redirect_uri"}
+	}
+
+	fail := func(code, description string) (*model.AuthorizeResponse, error) {
+		return &model.AuthorizeResponse{
+			RedirectTo: redirectWithParams(req.RedirectURI, map[string]string{
+				"error":             code,
+				"error_description": description,
+				"state":             req.State,
+			}),
+		}, nil
+	}
+
+	if req.ResponseType != "code" {
+		return fail(OAuthUnsupportedResponseType, "Only the code response type is supported")
+	}
+
+	scopes := uniqueFields(req.Scope)
+	if !scopes.Contains(model.ScopeOpenID) {
+		return fail(OAuthInvalidScope, "The openid scope is required")
+	}
+	for _, scope := range scopes {
+		if !client.Scopes.Contains(scope) {
+			return fail(OAuthInvalidScope, "The client may not request the scope "+scope)
+		}
+	}
+
+	if req.CodeChallenge != "" && req.CodeChallengeMethod != "S256" {
+		return fail(OAuthInvalidRequest, "Only the S256 code challenge method is supported")
+	}
+	if req.CodeChallenge == "" && client.IsPublic() {
+		return fail(OAuthInvalidRequest, "Public clients must use PKCE")
+	}
+	if len(req.Nonce) > 255 || len(req.CodeChallenge) > 128 {
+		return fail(OAuthInvalidRequest, "nonce or code_challenge is too long")
+	}
+
+	session, err := s.sessionRepo.GetByID(caller.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	consent, err := s.consentRepo.Get(caller.UserID, client.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	switch {
+	case req.Consent != nil && !*req.Consent:
+		return fail(OAuthAccessDenied, "The user denied the request")
+	case req.Consent != nil:
+		granted := scopes
+		if consent != nil {
+			granted = uniqueFields(strings.Join(append(consent.Scopes, scopes...), " "))
+		}
+		err := s.consentRepo.Save(&model.OAuthConsent{
+			UserID:   caller.UserID,
+			ClientID: client.ID,
+			Scopes:   granted,
+		})
+		if err != nil {
+			return nil, err
+		}
+	case consent == nil || !containsAll(consent.Scopes, scopes) || req.Prompt == "consent":
+		if req.Prompt == "none" {
+			return fail(OAuthConsentRequired, "The user has not consented to the requested scopes")
+		}
+		return &model.AuthorizeResponse{
+			ConsentRequired: true,
+			ClientName:      client.Name,
+			Scopes:          scopes,
+		}, nil
+	}
+
+	code, codeHash, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	// Unredeemed codes would otherwise pile up.
+	if err := s.codeRepo.DeleteExpired(now); err != nil {
+		log.Printf("Failed to delete expired authorization codes: %v", err)
+	}
+
+	err = s.codeRepo.Create(&model.OAuthAuthorizationCode{
+		CodeHash:      codeHash,
+		ClientID:      client.ID,
+		UserID:        caller.UserID,
+		RedirectURI:   req.RedirectURI,
+		Scopes:        scopes,
+		Nonce:         req.Nonce,
+		CodeChallenge: req.CodeChallenge,
+		AuthTime:      session.CreatedAt,
+		ExpiresAt:     now.Add(s.codeTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.AuthorizeResponse{
+		RedirectTo: redirectWithParams(req.RedirectURI, map[string]string{
+			"code":  code,
+			"state": req.State,
+		}),
+	}, nil
+}
+
+// Token redeems an authorization code for an access token and an ID token.
+func (s *identityProviderService) Token(ctx context.Context, req *model.TokenRequest) (*model.TokenResponse, error) {
+	client, err := s.clientRepo.GetByClientID(req.ClientID)
+	if err != nil || client.RevokedAt != nil {
+		return nil, &OAuthError{Code: OAuthInvalidClient, Description: "Client authentication failed"}
+	}
+	if client.IsPublic() {
+		if req.ClientSecret != "" {
+			return nil, &OAuthError{Code: OAuthInvalidClient, Description: "Client authentication failed"}
+		}
+	} else if subtle.ConstantTimeCompare([]byte(hashSecret(req.ClientSecret)), []byte(client.SecretHash)) != 1 {
+		return nil, &OAuthError{Code: OAuthInvalidClient, Description: "Client authentication failed"}
+	}
+
+	if req.GrantType != "authorization_code" {
+		return nil, &OAuthError{Code: OAuthUnsupportedGrantType, Description: "Only the authorization_code grant is supported"}
+	}
+
+	invalidGrant := &OAuthError{Code: OAuthInvalidGrant, Description: "The authorization code is invalid or expired"}
+	code, err := s.codeRepo.Consume(hashSecret(req.Code))
+	if err != nil {
+		return nil, invalidGrant
+	}
+
+	now := time.Now()
+	if code.ClientID != client.ID || !now.Before(code.ExpiresAt) || code.RedirectURI != req.RedirectURI {
+		return nil, invalidGrant
+	}
+	if code.CodeChallenge == "" && req.CodeVerifier != "" {
+		return nil, invalidGrant
+	}
+	if code.CodeChallenge != "" &&
+		subtle.ConstantTimeCompare([]byte(oidc.CodeChallenge(req.CodeVerifier)), []byte(code.CodeChallenge)) != 1 {
+		return nil, invalidGrant
+	}
+
+	// The code names the user, whatever the organization of the request
+	user, err := s.userRepo.WithContext(tenant.Unscoped(ctx)).GetByID(code.UserID)
+	if err != nil {
+		return nil, invalidGrant
+	}
+
+	expiresAt := now.Add(s.tokenTTL)
+	subject := strconv.FormatUint(uint64(user.ID), 10)
+	scope := strings.Join(code.Scopes, " ")
+
+	jti, _, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+	accessToken, err := s.keys.Sign(providerAccessTokenClaims{
+		ClientID: client.ClientID,
+		Scope:    scope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			Subject:   subject,
+			Audience:  jwt.ClaimStrings{client.ClientID},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			ID:        jti,
+		},
+	}, accessTokenType)
+	if err != nil {
+		return nil, err
+	}
+
+	idClaims := jwt.MapClaims{
+		"iss":       s.issuer,
+		"sub":       subject,
+		"aud":       client.ClientID,
+		"iat":       now.Unix(),
+		"exp":       expiresAt.Unix(),
+		"auth_time": code.AuthTime.Unix(),
+		"at_hash":   accessTokenHash(accessToken),
+	}
+	if code.Nonce != "" {
+		idClaims["nonce"] = code.Nonce
+	}
+	addUserInfoClaims(idClaims, newUserInfo(toUserResponse(user), code.Scopes))
+
+	idToken, err := s.keys.Sign(idClaims, idTokenType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.TokenResponse{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(s.tokenTTL.Seconds()),
+		IDToken:     idToken,
+		Scope:       scope,
+	}, nil
+}
+
+// UserInfo returns the claims an access token grants. Tokens stop working
+// once the client is revoked or the user withdraws consent.
+func (s *identityProviderService) UserInfo(ctx context.Context, accessToken string) (*model.UserInfo, error) {
+	claims := &providerAccessTokenClaims{}
+	token, err := jwt.ParseWithClaims(accessToken, claims, func(token *jwt.Token) (interface{}, error) {
+		keyID, _ := token.Header["kid"].(string)
+		return s.keys.PublicKey(keyID)
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || token.Header["typ"] != accessTokenType {
+		return nil, errInvalidAccessToken
+	}
+
+	userID, err := strconv.ParseUint(claims.Subject, 10, 32)
+	if err != nil {
+		return nil, errInvalidAccessToken
+	}
+
+	client, err := s.clientRepo.GetByClientID(claims.ClientID)
+	if err != nil || client.RevokedAt != nil {
+		return nil, errInvalidAccessToken
+	}
+	if _, err := s.consentRepo.Get(uint(userID), client.ID); err != nil {
+		return nil, errInvalidAccessToken
+	}
+
+	user, err := s.userRepo.WithContext(tenant.Unscoped(ctx)).GetByID(uint(userID))
+	if err != nil {
+		return nil, errInvalidAccessToken
+	}
+
+	return newUserInfo(toUserResponse(user), strings.Fields(claims.Scope)), nil
+}
+
+func (s *identityProviderService) ListConsents(ctx context.Context, userID uint) ([]*model.OAuthConsentResponse, error) {
+	if _, err := s.userRepo.WithContext(ctx).GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	consents, err := s.consentRepo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*model.OAuthConsentResponse, 0, len(consents))
+	for _, consent := range consents {
+		client, err := s.clientRepo.GetByID(consent.ClientID)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, &model.OAuthConsentResponse{
+			ClientID:   client.ClientID,
+			ClientName: client.Name,
+			Scopes:     consent.Scopes,
+			CreatedAt:  consent.CreatedAt,
+			UpdatedAt:  consent.UpdatedAt,
+		})
+	}
+	return responses, nil
+}
+
+// RevokeConsent withdraws the user's consent for the client, which also
+// invalidates the access tokens issued to it for the user.
+func (s *identityProviderService) RevokeConsent(userID uint, clientID string) error {
+	client, err := s.clientRepo.GetByClientID(clientID)
+	if err != nil {
+		return ErrConsentNotFound
+	}
+
+	deleted, err := s.consentRepo.Delete(userID, client.ID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrConsentNotFound
+	}
+	return nil
+}
+
+// newUserInfo derives the claims about a user that the scopes grant.
+func newUserInfo(user *model.UserResponse, scopes model.ScopeList) *model.UserInfo {
+	info := &model.UserInfo{
+		Subject: strconv.FormatUint(uint64(user.ID), 10),
+	}
+	if scopes.Contains(model.ScopeProfile) {
+		info.PreferredUsername = user.Username
+		info.UpdatedAt = user.UpdatedAt.Unix()
+	}
+	if scopes.Contains(model.ScopeEmail) {
+		verified := user.EmailVerifiedAt != nil
+		info.Email = user.Email
+		info.EmailVerified = &verified
+	}
+	return info
+}
+
+// addUserInfoClaims copies the set user info claims into an ID token.
+func addUserInfoClaims(claims jwt.MapClaims, info *model.UserInfo) {
+	if info.PreferredUsername != "" {
+		claims["preferred_username"] = info.PreferredUsername
+		claims["updated_at"] = info.UpdatedAt
+	}
+	if info.Email != "" {
+		claims["email"] = info.Email
+		claims["email_verified"] = *info.EmailVerified
+	}
+}
+
+// accessTokenHash returns the at_hash claim for an RS256 signed token: the
+// left half of its SHA-256 hash.
+func accessTokenHash(accessToken string) string {
+	sum := sha256.Sum256([]byte(accessToken))
+	return base64.RawURLEncoding.EncodeToString(sum[:len(sum)/2])
+}
+
+// redirectWithParams adds the non-empty parameters to the query of a
+// registered redirect URI.
+func redirectWithParams(redirectURI string, params map[string]string) string {
+	parsed, err := url.Parse(redirectURI)
+	if err != nil {
+		return redirectURI
+	}
+
+	query := parsed.Query()
+	for name, value := range params {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
+
+// uniqueFields splits a space separated list and drops repeated entries.
+func uniqueFields(list string) model.ScopeList {
+	var fields model.ScopeList
+	for _, field := range strings.Fields(list) {
+		if !fields.Contains(field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+func containsAll(list model.ScopeList, wanted []string) bool {
+	for _, entry := range wanted {
+		if !list.Contains(entry) {
+			return false
+		}
+	}
+	return true
+}
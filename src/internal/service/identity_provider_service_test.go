@@ -0,0 +1,544 @@
+package service
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/oidc"
+	"gorm.io/gorm"
+)
+
+var sessionCaller = &model.Principal{UserID: 1, SessionID: "session-1"}
+
+func (s *ServiceTestSuite) newOAuthClient() *model.OAuthClient {
+	return &model.OAuthClient{
+		ID:           3,
+		ClientID:     "client-1",
+		SecretHash:   hashSecret("secret-1"),
+		Name:         "Dashboard",
+		RedirectURIs: model.RedirectURIList{"https://app.example.com/callback"},
+		Scopes:       model.ScopeList{"openid", "profile", "email"},
+	}
+}
+
+func (s *ServiceTestSuite) newAuthorizeRequest() *model.AuthorizeRequest {
+	return &model.AuthorizeRequest{
+		ResponseType:        "code",
+		ClientID:            "client-1",
+		RedirectURI:         "https://app.example.com/callback",
+		Scope:               "openid email",
+		State:               "state-1",
+		Nonce:               "nonce-1",
+		CodeChallenge:       oidc.CodeChallenge("verifier-1"),
+		CodeChallengeMethod: "S256",
+	}
+}
+
+// redirectParams returns the query of a redirect back to the client.
+func (s *ServiceTestSuite) redirectParams(redirectTo string) url.Values {
+	parsed, err := url.Parse(redirectTo)
+	s.Require().NoError(err)
+	s.Require().Equal("https://app.example.com/callback", parsed.Scheme+"://"+parsed.Host+parsed.Path)
+	return parsed.Query()
+}
+
+// newAuthorizationCode returns a code the suite's client can redeem.
+func (s *ServiceTestSuite) newAuthorizationCode() *model.OAuthAuthorizationCode {
+	return &model.OAuthAuthorizationCode{
+		CodeHash:      hashSecret("code-1"),
+		ClientID:      3,
+		UserID:        1,
+		RedirectURI:   "https://app.example.com/callback",
+		Scopes:        model.ScopeList{"openid", "email"},
+		Nonce:         "nonce-1",
+		CodeChallenge: oidc.CodeChallenge("verifier-1"),
+		AuthTime:      time.Now().Add(-time.Hour),
+		ExpiresAt:     time.Now().Add(time.Minute),
+	}
+}
+
+func (s *ServiceTestSuite) newTokenRequest() *model.TokenRequest {
+	return &model.TokenRequest{
+		GrantType:    "authorization_code",
+		Code:         "code-1",
+		RedirectURI:  "https://app.example.com/callback",
+		CodeVerifier: "verifier-1",
+		ClientID:     "client-1",
+		ClientSecret: "secret-1",
+	}
+}
+
+func (s *ServiceTestSuite) useSigningKey() {
+	s.signingKeyRepo.On("ListCreatedSince", mock.AnythingOfType("time.Time")).
+		Return([]*model.SigningKey{s.storedSigningKey("key-1", time.Now())}, nil)
+}
+
+func (s *ServiceTestSuite) parseProviderToken(signed string) jwt.MapClaims {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(signed, claims, func(*jwt.Token) (interface{}, error) {
+		return &s.rsaKey.PublicKey, nil
+	})
+	s.Require().NoError(err)
+	return claims
+}
+
+func (s *ServiceTestSuite) TestDiscovery() {
+	// Execute
+	result := s.idpService.Discovery()
+
+	// Assert
+	assert.Equal(s.T(), "http://localhost:8080", result.Issuer)
+	assert.Equal(s.T(), "http://localhost:8080/api/v1/oauth/authorize", result.AuthorizationEndpoint)
+	assert.Equal(s.T(), "http://localhost:8080/api/v1/oauth/token", result.TokenEndpoint)
+	assert.Equal(s.T(), "http://localhost:8080/api/v1/oauth/jwks", result.JWKSURI)
+	assert.Equal(s.T(), []string{"S256"}, result.CodeChallengeMethodsSupported)
+}
+
+func (s *ServiceTestSuite) TestDiscovery_LoginURL() {
+	s.conf.IdentityProvider.LoginURL = "https://login.example.com/authorize"
+	idpService := NewIdentityProviderService(s.clientRepo, s.consentRepo, s.codeRepo, s.userRepo, s.sessionRepo, s.signingKeys, s.conf)
+
+	// Execute
+	result := idpService.Discovery()
+
+	// Assert
+	assert.Equal(s.T(), "https://login.example.com/authorize", result.AuthorizationEndpoint)
+}
+
+func (s *ServiceTestSuite) TestAuthorize_RequiresSession() {
+	// Execute
+	result, err := s.idpService.Authorize(&model.Principal{UserID: 1, APIKeyID: 2}, s.newAuthorizeRequest())
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrSessionRequired)
+	assert.Nil(s.T(), result)
+}
+
+func (s *ServiceTestSuite) TestAuthorize_UnregisteredRedirectURI() {
+	req := s.newAuthorizeRequest()
+	req.RedirectURI = "https://evil.example.com/callback"
+	s.clientRepo.On("GetByClientID", "client-1").Return(s.newOAuthClient(), nil)
+
+	// Execute
+	result, err := s.idpService.Authorize(sessionCaller, req)
+
+	// Assert: never redirect to an unregistered URI
+	var oauthErr *OAuthError
+	assert.ErrorAs(s.T(), err, &oauthErr)
+	assert.Equal(s.T(), OAuthInvalidRequest, oauthErr.Code)
+	assert.Nil(s.T(), result)
+}
+
+func (s *ServiceTestSuite) TestAuthorize_RevokedClient() {
+	client := s.newOAuthClient()
+	revokedAt := time.Now()
+	client.RevokedAt = &revokedAt
+	s.clientRepo.On("GetByClientID", "client-1").Return(client, nil)
+
+	// Execute
+	_, err := s.idpService.Authorize(sessionCaller, s.newAuthorizeRequest())
+
+	// Assert
+	var oauthErr *OAuthError
+	assert.ErrorAs(s.T(), err, &oauthErr)
+}
+
+func (s *ServiceTestSuite) TestAuthorize_InvalidRequestsRedirectWithError() {
+	cases := map[string]struct {
+		mutate func(*model.AuthorizeRequest)
+		code   string
+	}{
+		"no openid scope":   {func(r *model.AuthorizeRequest) { r.Scope = "email" }, OAuthInvalidScope},
+		"scope not allowed": {func(r *model.AuthorizeRequest) { r.Scope = "openid admin" }, OAuthInvalidScope},
+		"token flow":        {func(r *model.AuthorizeRequest) { r.ResponseType = "token" }, OAuthUnsupportedResponseType},
+		"plain challenge":   {func(r *model.AuthorizeRequest) { r.CodeChallengeMethod = "plain" }, OAuthInvalidRequest},
+	}
+	s.clientRepo.On("GetByClientID", "client-1").Return(s.newOAuthClient(), nil)
+
+	for name, tc := range cases {
+		req := s.newAuthorizeRequest()
+		tc.mutate(req)
+
+		// Execute
+		result, err := s.idpService.Authorize(sessionCaller, req)
+
+		// Assert
+		assert.NoError(s.T(), err, name)
+		params := s.redirectParams(result.RedirectTo)
+		assert.Equal(s.T(), tc.code, params.Get("error"), name)
+		assert.Equal(s.T(), "state-1", params.Get("state"), name)
+		assert.Empty(s.T(), params.Get("code"), name)
+	}
+	s.codeRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestAuthorize_PublicClientRequiresPKCE() {
+	client := s.newOAuthClient()
+	client.SecretHash = ""
+	req := s.newAuthorizeRequest()
+	req.CodeChallenge = ""
+	req.CodeChallengeMethod = ""
+	s.clientRepo.On("GetByClientID", "client-1").Return(client, nil)
+
+	// Execute
+	result, err := s.idpService.Authorize(sessionCaller, req)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), OAuthInvalidRequest, s.redirectParams(result.RedirectTo).Get("error"))
+}
+
+func (s *ServiceTestSuite) TestAuthorize_AsksForConsent() {
+	s.clientRepo.On("GetByClientID", "client-1").Return(s.newOAuthClient(), nil)
+	s.sessionRepo.On("GetByID", "session-1").Return(&model.Session{ID: "session-1", CreatedAt: time.Now()}, nil)
+	s.consentRepo.On("Get", uint(1), uint(3)).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	result, err := s.idpService.Authorize(sessionCaller, s.newAuthorizeRequest())
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), result.ConsentRequired)
+	assert.Equal(s.T(), "Dashboard", result.ClientName)
+	assert.Equal(s.T(), []string{"openid", "email"}, result.Scopes)
+	assert.Empty(s.T(), result.RedirectTo)
+}
+
+func (s *ServiceTestSuite) TestAuthorize_NarrowerConsentAsksAgain() {
+	s.clientRepo.On("GetByClientID", "client-1").Return(s.newOAuthClient(), nil)
+	s.sessionRepo.On("GetByID", "session-1").Return(&model.Session{ID: "session-1", CreatedAt: time.Now()}, nil)
+	s.consentRepo.On("Get", uint(1), uint(3)).Return(&model.OAuthConsent{Scopes: model.ScopeList{"openid"}}, nil)
+
+	// Execute
+	result, err := s.idpService.Authorize(sessionCaller, s.newAuthorizeRequest())
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), result.ConsentRequired)
+}
+
+func (s *ServiceTestSuite) TestAuthorize_PromptNoneWithoutConsent() {
+	req := s.newAuthorizeRequest()
+	req.Prompt = "none"
+	s.clientRepo.On("GetByClientID", "client-1").Return(s.newOAuthClient(), nil)
+	s.sessionRepo.On("GetByID", "session-1").Return(&model.Session{ID: "session-1", CreatedAt: time.Now()}, nil)
+	s.consentRepo.On("Get", uint(1), uint(3)).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	result, err := s.idpService.Authorize(sessionCaller, req)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), OAuthConsentRequired, s.redirectParams(result.RedirectTo).Get("error"))
+}
+
+func (s *ServiceTestSuite) TestAuthorize_ConsentDenied() {
+	req := s.newAuthorizeRequest()
+	denied := false
+	req.Consent = &denied
+	s.clientRepo.On("GetByClientID", "client-1").Return(s.newOAuthClient(), nil)
+	s.sessionRepo.On("GetByID", "session-1").Return(&model.Session{ID: "session-1", CreatedAt: time.Now()}, nil)
+	s.consentRepo.On("Get", uint(1), uint(3)).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	result, err := s.idpService.Authorize(sessionCaller, req)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), OAuthAccessDenied, s.redirectParams(result.RedirectTo).Get("error"))
+	s.consentRepo.AssertNotCalled(s.T(), "Save", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestAuthorize_ConsentGrantedIssuesCode() {
+	req := s.newAuthorizeRequest()
+	granted := true
+	req.Consent = &granted
+	loginAt := time.Now().Add(-time.Hour)
+	s.clientRepo.On("GetByClientID", "client-1").Return(s.newOAuthClient(), nil)
+	s.sessionRepo.On("GetByID", "session-1").Return(&model.Session{ID: "session-1", CreatedAt: loginAt}, nil)
+	s.consentRepo.On("Get", uint(1), uint(3)).Return(&model.OAuthConsent{Scopes: model.ScopeList{"openid", "profile"}}, nil)
+	s.consentRepo.On("Save", mock.AnythingOfType("*model.OAuthConsent")).Return(nil)
+	s.codeRepo.On("DeleteExpired", mock.AnythingOfType("time.Time")).Return(nil)
+	s.codeRepo.On("Create", mock.AnythingOfType("*model.OAuthAuthorizationCode")).Return(nil)
+
+	// Execute
+	result, err := s.idpService.Authorize(sessionCaller, req)
+
+	// Assert: the consent keeps earlier scopes
+	assert.NoError(s.T(), err)
+	consent := s.consentRepo.Calls[1].Arguments.Get(0).(*model.OAuthConsent)
+	assert.Equal(s.T(), model.ScopeList{"openid", "profile", "email"}, consent.Scopes)
+
+	params := s.redirectParams(result.RedirectTo)
+	assert.Equal(s.T(), "state-1", params.Get("state"))
+	code := s.codeRepo.Calls[1].Arguments.Get(0).(*model.OAuthAuthorizationCode)
+	assert.Equal(s.T(), hashSecret(params.Get("code")), code.CodeHash)
+	assert.Equal(s.T(), model.ScopeList{"openid", "email"}, code.Scopes)
+	assert.Equal(s.T(), "nonce-1", code.Nonce)
+	assert.Equal(s.T(), loginAt, code.AuthTime)
+	assert.WithinDuration(s.T(), time.Now().Add(time.Minute), code.ExpiresAt, 5*time.Second)
+}
+
+func (s *ServiceTestSuite) TestAuthorize_ExistingConsentSkipsPrompt() {
+	s.clientRepo.On("GetByClientID", "client-1").Return(s.newOAuthClient(), nil)
+	s.sessionRepo.On("GetByID", "session-1").Return(&model.Session{ID: "session-1", CreatedAt: time.Now()}, nil)
+	s.consentRepo.On("Get", uint(1), uint(3)).Return(&model.OAuthConsent{Scopes: model.ScopeList{"openid", "email"}}, nil)
+	s.codeRepo.On("DeleteExpired", mock.AnythingOfType("time.Time")).Return(nil)
+	s.codeRepo.On("Create", mock.AnythingOfType("*model.OAuthAuthorizationCode")).Return(nil)
+
+	// Execute
+	result, err := s.idpService.Authorize(sessionCaller, s.newAuthorizeRequest())
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), s.redirectParams(result.RedirectTo).Get("code"))
+	s.consentRepo.AssertNotCalled(s.T(), "Save", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestToken_IssuesTokens() {
+	user := s.newVerifiedUser()
+	code := s.newAuthorizationCode()
+	s.clientRepo.On("GetByClientID", "client-1").Return(s.newOAuthClient(), nil)
+	s.codeRepo.On("Consume", hashSecret("code-1")).Return(code, nil)
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.useSigningKey()
+
+	// Execute
+	result, err := s.idpService.Token(s.ctx, s.newTokenRequest())
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "Bearer", result.TokenType)
+	assert.Equal(s.T(), 3600, result.ExpiresIn)
+	assert.Equal(s.T(), "openid email", result.Scope)
+
+	idClaims := s.parseProviderToken(result.IDToken)
+	assert.Equal(s.T(), "http://localhost:8080", idClaims["iss"])
+	assert.Equal(s.T(), "1", idClaims["sub"])
+	assert.Equal(s.T(), "client-1", idClaims["aud"])
+	assert.Equal(s.T(), "nonce-1", idClaims["nonce"])
+	assert.Equal(s.T(), float64(code.AuthTime.Unix()), idClaims["auth_time"])
+	assert.Equal(s.T(), accessTokenHash(result.AccessToken), idClaims["at_hash"])
+	assert.Equal(s.T(), "test@example.com", idClaims["email"])
+	assert.Equal(s.T(), true, idClaims["email_verified"])
+	// No profile scope, no profile claims
+	assert.NotContains(s.T(), idClaims, "preferred_username")
+
+	accessClaims := s.parseProviderToken(result.AccessToken)
+	assert.Equal(s.T(), "client-1", accessClaims["client_id"])
+	assert.Equal(s.T(), "openid email", accessClaims["scope"])
+}
+
+func (s *ServiceTestSuite) TestToken_InvalidClient() {
+	cases := map[string]func(*model.TokenRequest){
+		"wrong secret":   func(r *model.TokenRequest) { r.ClientSecret = "wrong" },
+		"no secret":      func(r *model.TokenRequest) { r.ClientSecret = "" },
+		"unknown client": func(r *model.TokenRequest) { r.ClientID = "client-2" },
+	}
+	s.clientRepo.On("GetByClientID", "client-1").Return(s.newOAuthClient(), nil)
+	s.clientRepo.On("GetByClientID", "client-2").Return(nil, gorm.ErrRecordNotFound)
+
+	for name, mutate := range cases {
+		req := s.newTokenRequest()
+		mutate(req)
+
+		// Execute
+		_, err := s.idpService.Token(s.ctx, req)
+
+		// Assert
+		var oauthErr *OAuthError
+		assert.ErrorAs(s.T(), err, &oauthErr, name)
+		assert.Equal(s.T(), OAuthInvalidClient, oauthErr.Code, name)
+	}
+	s.codeRepo.AssertNotCalled(s.T(), "Consume", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestToken_InvalidGrant() {
+	cases := map[string]struct {
+		mutateRequest func(*model.TokenRequest)
+		mutateCode    func(*model.OAuthAuthorizationCode)
+	}{
+		"wrong verifier":     {func(r *model.TokenRequest) { r.CodeVerifier = "verifier-2" }, nil},
+		"no verifier":        {func(r *model.TokenRequest) { r.CodeVerifier = "" }, nil},
+		"other redirect URI": {func(r *model.TokenRequest) { r.RedirectURI = "https://app.example.com/other" }, nil},
+		"other client":       {nil, func(c *model.OAuthAuthorizationCode) { c.ClientID = 4 }},
+		"expired":            {nil, func(c *model.OAuthAuthorizationCode) { c.ExpiresAt = time.Now().Add(-time.Second) }},
+	}
+	s.clientRepo.On("GetByClientID", "client-1").Return(s.newOAuthClient(), nil)
+
+	for name, tc := range cases {
+		req := s.newTokenRequest()
+		code := s.newAuthorizationCode()
+		if tc.mutateRequest != nil {
+			tc.mutateRequest(req)
+		}
+		if tc.mutateCode != nil {
+			tc.mutateCode(code)
+		}
+		s.codeRepo.ExpectedCalls = nil
+		s.codeRepo.On("Consume", hashSecret("code-1")).Return(code, nil)
+
+		// Execute
+		_, err := s.idpService.Token(s.ctx, req)
+
+		// Assert
+		var oauthErr *OAuthError
+		assert.ErrorAs(s.T(), err, &oauthErr, name)
+		assert.Equal(s.T(), OAuthInvalidGrant, oauthErr.Code, name)
+	}
+	s.userRepo.AssertNotCalled(s.T(), "GetByID", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestToken_UnknownCode() {
+	s.clientRepo.On("GetByClientID", "client-1").Return(s.newOAuthClient(), nil)
+	s.codeRepo.On("Consume", hashSecret("code-1")).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	_, err := s.idpService.Token(s.ctx, s.newTokenRequest())
+
+	// Assert
+	var oauthErr *OAuthError
+	assert.ErrorAs(s.T(), err, &oauthErr)
+	assert.Equal(s.T(), OAuthInvalidGrant, oauthErr.Code)
+}
+
+func (s *ServiceTestSuite) TestToken_UnsupportedGrantType() {
+	req := s.newTokenRequest()
+	req.GrantType = "password"
+	s.clientRepo.On("GetByClientID", "client-1").Return(s.newOAuthClient(), nil)
+
+	// Execute
+	_, err := s.idpService.Token(s.ctx, req)
+
+	// Assert
+	var oauthErr *OAuthError
+	assert.ErrorAs(s.T(), err, &oauthErr)
+	assert.Equal(s.T(), OAuthUnsupportedGrantType, oauthErr.Code)
+}
+
+// issueProviderAccessToken signs an access token like Token does.
+func (s *ServiceTestSuite) issueProviderAccessToken(scope string) string {
+	s.useSigningKey()
+	token, err := s.signingKeys.Sign(providerAccessTokenClaims{
+		ClientID: "client-1",
+		Scope:    scope,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "http://localhost:8080",
+			Subject:   "1",
+			Audience:  jwt.ClaimStrings{"client-1"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}, accessTokenType)
+	s.Require().NoError(err)
+	return token
+}
+
+func (s *ServiceTestSuite) TestUserInfo() {
+	token := s.issueProviderAccessToken("openid profile email")
+	user := s.newVerifiedUser()
+	s.clientRepo.On("GetByClientID", "client-1").Return(s.newOAuthClient(), nil)
+	s.consentRepo.On("Get", uint(1), uint(3)).Return(&model.OAuthConsent{}, nil)
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+
+	// Execute
+	result, err := s.idpService.UserInfo(s.ctx, token)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "1", result.Subject)
+	assert.Equal(s.T(), "testuser", result.PreferredUsername)
+	assert.Equal(s.T(), "test@example.com", result.Email)
+	assert.True(s.T(), *result.EmailVerified)
+}
+
+func (s *ServiceTestSuite) TestUserInfo_OnlyGrantedClaims() {
+	token := s.issueProviderAccessToken("openid")
+	s.clientRepo.On("GetByClientID", "client-1").Return(s.newOAuthClient(), nil)
+	s.consentRepo.On("Get", uint(1), uint(3)).Return(&model.OAuthConsent{}, nil)
+	s.userRepo.On("GetByID", uint(1)).Return(s.newVerifiedUser(), nil)
+
+	// Execute
+	result, err := s.idpService.UserInfo(s.ctx, token)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), &model.UserInfo{Subject: "1"}, result)
+}
+
+func (s *ServiceTestSuite) TestUserInfo_ConsentWithdrawn() {
+	token := s.issueProviderAccessToken("openid email")
+	s.clientRepo.On("GetByClientID", "client-1").Return(s.newOAuthClient(), nil)
+	s.consentRepo.On("Get", uint(1), uint(3)).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	result, err := s.idpService.UserInfo(s.ctx, token)
+
+	// Assert
+	var oauthErr *OAuthError
+	assert.ErrorAs(s.T(), err, &oauthErr)
+	assert.Equal(s.T(), OAuthInvalidToken, oauthErr.Code)
+	assert.Nil(s.T(), result)
+}
+
+func (s *ServiceTestSuite) TestUserInfo_RejectsIDToken() {
+	s.useSigningKey()
+	idToken, _ := s.signingKeys.Sign(jwt.MapClaims{
+		"iss": "http://localhost:8080",
+		"sub": "1",
+		"aud": "client-1",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}, idTokenType)
+
+	// Execute
+	_, err := s.idpService.UserInfo(s.ctx, idToken)
+
+	// Assert
+	var oauthErr *OAuthError
+	assert.ErrorAs(s.T(), err, &oauthErr)
+	s.clientRepo.AssertNotCalled(s.T(), "GetByClientID", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestListConsents() {
+	s.userRepo.On("GetByID", uint(1)).Return(s.newVerifiedUser(), nil)
+	s.consentRepo.On("ListByUser", uint(1)).Return([]*model.OAuthConsent{
+		{UserID: 1, ClientID: 3, Scopes: model.ScopeList{"openid", "email"}},
+	}, nil)
+	s.clientRepo.On("GetByID", uint(3)).Return(s.newOAuthClient(), nil)
+
+	// Execute
+	result, err := s.idpService.ListConsents(s.ctx, 1)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result, 1)
+	assert.Equal(s.T(), "client-1", result[0].ClientID)
+	assert.Equal(s.T(), "Dashboard", result[0].ClientName)
+	assert.Equal(s.T(), []string{"openid", "email"}, result[0].Scopes)
+}
+
+func (s *ServiceTestSuite) TestRevokeConsent() {
+	s.clientRepo.On("GetByClientID", "client-1").Return(s.newOAuthClient(), nil)
+	s.consentRepo.On("Delete", uint(1), uint(3)).Return(true, nil)
+
+	// Execute
+	err := s.idpService.RevokeConsent(1, "client-1")
+
+	// Assert
+	assert.NoError(s.T(), err)
+	s.consentRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestRevokeConsent_NotFound() {
+	s.clientRepo.On("GetByClientID", "client-1").Return(s.newOAuthClient(), nil)
+	s.consentRepo.On("Delete", uint(1), uint(3)).Return(false, nil)
+
+	// Execute
+	err := s.idpService.RevokeConsent(1, "client-1")
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrConsentNotFound)
+}
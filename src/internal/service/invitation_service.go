@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/hasher"
+	"github.com/weeranieb/go-kit-base/src/internal/mailer"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidInvitation        = errors.New("invalid or expired invitation")
+	ErrInvitationNotFound       = errors.New("invitation not found")
+	ErrInvitationClosed         = errors.New("invitation was already accepted or revoked")
+	ErrInvitationPending        = errors.New("a pending invitation for this email already exists")
+	ErrInvitationRoleNotAllowed = errors.New("only owners and admins of the organization can invite admins")
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=InvitationService --output=./mocks/service --outpkg=service --filename=invitation_service.go --structname=MockInvitationService --with-expecter=false
+type InvitationService interface {
+	CreateInvitation(ctx context.Context, principal *model.Principal, req *model.CreateInvitationRequest) (*model.InvitationResponse, error)
+	ListInvitations(ctx context.Context, limit, offset int) ([]*model.InvitationResponse, error)
+	ResendInvitation(ctx context.Context, id uint) (*model.InvitationResponse, error)
+	RevokeInvitation(ctx context.Context, id uint) error
+	AcceptInvitation(ctx context.Context, req *model.AcceptInvitationRequest) (*model.UserResponse, error)
+}
+
+type invitationService struct {
+	invitationRepo repository.InvitationRepository
+	userRepo       repository.UserRepository
+	membershipRepo repository.MembershipRepository
+	transactor     repository.Transactor
+	passwordPolicy PasswordPolicy
+	passwordHasher hasher.PasswordHasher
+	mailer         mailer.Mailer
+	conf           config.InvitationConfig
+}
+
+func NewInvitationService(
+	invitationRepo repository.InvitationRepository,
+	userRepo repository.UserRepository,
+	membershipRepo repository.MembershipRepository,
+	transactor repository.Transactor,
+	passwordPolicy PasswordPolicy,
+	passwordHasher hasher.PasswordHasher,
+	mailer mailer.Mailer,
+	conf *config.Config,
+) InvitationService {
+	return &invitationService{
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		membershipRepo: membershipRepo,
+		transactor:     transactor,
+		passwordPolicy: passwordPolicy,
+		passwordHasher: passwordHasher,
+		mailer:         mailer,
+		conf:           conf.Invitation,
+	}
+}
+
+// CreateInvitation invites the owner of the address into the organization
+// of ctx and mails them the link. Only owners and admins of the
+// organization may invite admins; service API keys are trusted with any
+// role. A failure to send the mail is only logged
+// since the invitation can be resent.
+func (s *invitationService) CreateInvitation(ctx context.Context, principal *model.Principal, req *model.CreateInvitationRequest) (*model.InvitationResponse, error) {
+	invitations := s.invitationRepo.WithContext(ctx)
+
+	role := req.Role
+	if role == "" {
+		role = model.RoleUser
+	}
+
+	var invitedByID *uint
+	if principal.UserID != 0 {
+		if role == model.RoleAdmin {
+			membership, err := s.membershipRepo.WithContext(ctx).Get(principal.UserID)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, err
+			}
+			if membership == nil || (membership.Role != model.OrganizationRoleOwner && membership.Role != model.OrganizationRoleAdmin) {
+				return nil, ErrInvitationRoleNotAllowed
+			}
+		}
+		inviterID := principal.UserID
+		invitedByID = &inviterID
+	}
+
+	existing, err := s.userRepo.WithContext(tenant.Unscoped(ctx)).GetByEmail(req.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrEmailTaken
+	}
+
+	now := time.Now()
+	pending, err := invitations.HasPending(req.Email, now)
+	if err != nil {
+		return nil, err
+	}
+	if pending {
+		return nil, ErrInvitationPending
+	}
+
+	token, tokenHash, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+
+	invitation := &model.Invitation{
+		Email:       req.Email,
+		Role:        role,
+		TokenHash:   tokenHash,
+		InvitedByID: invitedByID,
+		ExpiresAt:   now.Add(s.conf.TokenTTL),
+	}
+	if err := invitations.Create(invitation); err != nil {
+		return nil, err
+	}
+
+	if err := s.sendInvitation(invitation, token); err != nil {
+		log.Printf("Failed to send invitation %d: %v", invitation.ID, err)
+	}
+
+	return toInvitationResponse(invitation, now), nil
+}
+
+func (s *invitationService) ListInvitations(ctx context.Context, limit, offset int) ([]*model.InvitationResponse, error) {
+	invitations, err := s.invitationRepo.WithContext(ctx).List(limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	responses := make([]*model.InvitationResponse, 0, len(invitations))
+	for _, invitation := range invitations {
+		responses = append(responses, toInvitationResponse(invitation, now))
+	}
+	return responses, nil
+}
+
+// ResendInvitation mails a new link, which replaces the previous one and
+// restarts the expiry. Expired invitations can be resent; accepted and
+// revoked ones cannot.
+func (s *invitationService) ResendInvitation(ctx context.Context, id uint) (*model.InvitationResponse, error) {
+	invitations := s.invitationRepo.WithContext(ctx)
+	invitation, err := s.getInvitation(invitations, id)
+	if err != nil {
+		return nil, err
+	}
+
+	token, tokenHash, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.conf.TokenTTL)
+	renewed, err := invitations.Renew(invitation.ID, tokenHash, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if !renewed {
+		return nil, ErrInvitationClosed
+	}
+	invitation.TokenHash = tokenHash
+	invitation.ExpiresAt = expiresAt
+
+	if err := s.sendInvitation(invitation, token); err != nil {
+		return nil, err
+	}
+
+	return toInvitationResponse(invitation, now), nil
+}
+
+func (s *invitationService) RevokeInvitation(ctx context.Context, id uint) error {
+	invitations := s.invitationRepo.WithContext(ctx)
+	invitation, err := s.getInvitation(invitations, id)
+	if err != nil {
+		return err
+	}
+
+	revoked, err := invitations.Revoke(invitation.ID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrInvitationClosed
+	}
+	return nil
+}
+
+// AcceptInvitation creates the invited account with the chosen username and
+// password in the organization of the invitation. Following the link proves
+// the address, so it is verified.
+func (s *invitationService) AcceptInvitation(ctx context.Context, req *model.AcceptInvitationRequest) (*model.UserResponse, error) {
+	tokenHash := hashSecret(req.Token)
+	// The token names the invitation, whatever the organization of the
+	// request
+	invitation, err := s.invitationRepo.WithContext(tenant.Unscoped(ctx)).GetByTokenHash(tokenHash)
+	if err != nil {
+		return nil, ErrInvalidInvitation
+	}
+
+	now := time.Now()
+	if invitation.Status(now) != model.InvitationStatusPending {
+		return nil, ErrInvalidInvitation
+	}
+
+	ctx = tenant.WithOrganization(ctx, invitation.OrganizationID)
+	users := s.userRepo.WithContext(ctx)
+
+	// Validate before consuming the invitation so that a taken username or
+	// a rejected password can be corrected.
+	if existing, _ := s.userRepo.WithContext(tenant.Unscoped(ctx)).GetByEmail(invitation.Email); existing != nil {
+		return nil, ErrEmailTaken
+	}
+	if existing, _ := users.GetByUsername(req.Username); existing != nil {
+		return nil, ErrUsernameTaken
+	}
+
+	user := &model.User{
+		OrganizationID:  invitation.OrganizationID,
+		Username:        req.Username,
+		Email:           invitation.Email,
+		Role:            invitation.Role,
+		EmailVerifiedAt: &now,
+	}
+	if err := s.passwordPolicy.Validate(req.Password, user); err != nil {
+		return nil, err
+	}
+	user.Password, err = s.passwordHasher.Hash(req.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	// The invitation is only used up if the account is created
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		accepted, err := s.invitationRepo.WithContext(ctx).MarkAccepted(invitation.ID, tokenHash, now)
+		if err != nil {
+			return err
+		}
+		if !accepted {
+			return ErrInvalidInvitation
+		}
+
+		return s.userRepo.WithContext(ctx).Create(user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toUserResponse(user), nil
+}
+
+func (s *invitationService) getInvitation(invitations repository.InvitationRepository, id uint) (*model.Invitation, error) {
+	invitation, err := invitations.GetByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvitationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if invitation.AcceptedAt != nil || invitation.RevokedAt != nil {
+		return nil, ErrInvitationClosed
+	}
+	return invitation, nil
+}
+
+func (s *invitationService) sendInvitation(invitation *model.Invitation, token string) error {
+	return s.mailer.Send(&mailer.Message{
+		To:      invitation.Email,
+		Subject: "You have been invited",
+		Body: fmt.Sprintf(
+			"Hello,\n\nYou have been invited to create an account. Use the link below to choose a username and password. It can be used once and expires in %s.\n\n%s?token=%s\n\nIf you were not expecting this, you can ignore this email.\n",
+			s.conf.TokenTTL, s.conf.AcceptURL, token,
+		),
+	})
+}
+
+func toInvitationResponse(invitation *model.Invitation, now time.Time) *model.InvitationResponse {
+	return &model.InvitationResponse{
+		ID:          invitation.ID,
+		Email:       invitation.Email,
+		Role:        invitation.Role,
+		Status:      invitation.Status(now),
+		InvitedByID: invitation.InvitedByID,
+		ExpiresAt:   invitation.ExpiresAt,
+		AcceptedAt:  invitation.AcceptedAt,
+		RevokedAt:   invitation.RevokedAt,
+		CreatedAt:   invitation.CreatedAt,
+	}
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/mailer"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+	"gorm.io/gorm"
+)
+
+func (s *ServiceTestSuite) newInvitation() *model.Invitation {
+	invitedByID := uint(1)
+	return &model.Invitation{
+		ID:          3,
+		Email:       "new@example.com",
+		Role:        model.RoleUser,
+		TokenHash:   hashSecret("invite-token"),
+		InvitedByID: &invitedByID,
+		ExpiresAt:   time.Now().Add(time.Hour),
+	}
+}
+
+// mailedToken returns the token in the link of the last mailed invitation.
+func (s *ServiceTestSuite) mailedToken() string {
+	message := s.mailer.Calls[len(s.mailer.Calls)-1].Arguments.Get(0).(*mailer.Message)
+	_, token, found := strings.Cut(message.Body, "http://localhost/accept-invitation?token=")
+	assert.True(s.T(), found)
+	token, _, _ = strings.Cut(token, "\n")
+	return token
+}
+
+// Test CreateInvitation
+func (s *ServiceTestSuite) TestCreateInvitation_Success() {
+	s.membershipRepo.On("WithContext", mock.Anything).Return(s.membershipRepo)
+	s.membershipRepo.On("Get", uint(1)).Return(&model.Membership{UserID: 1, Role: model.OrganizationRoleAdmin}, nil)
+	s.userRepo.On("GetByEmail", "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+	s.invitationRepo.On("HasPending", "new@example.com", mock.AnythingOfType("time.Time")).Return(false, nil)
+	s.invitationRepo.On("Create", mock.AnythingOfType("*model.Invitation")).Return(nil)
+	s.mailer.On("Send", mock.AnythingOfType("*mailer.Message")).Return(nil)
+
+	// Execute
+	result, err := s.invitations.CreateInvitation(s.ctx, &model.Principal{UserID: 1}, &model.CreateInvitationRequest{
+		Email: "new@example.com",
+		Role:  model.RoleAdmin,
+	})
+
+	// Assert: the mailed token matches the stored hash
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.InvitationStatusPending, result.Status)
+	assert.Equal(s.T(), model.RoleAdmin, result.Role)
+	assert.Equal(s.T(), uint(1), *result.InvitedByID)
+
+	stored := s.invitationRepo.Calls[2].Arguments.Get(0).(*model.Invitation)
+	assert.WithinDuration(s.T(), time.Now().Add(7*24*time.Hour), stored.ExpiresAt, 5*time.Second)
+	assert.Equal(s.T(), stored.TokenHash, hashSecret(s.mailedToken()))
+}
+
+func (s *ServiceTestSuite) TestCreateInvitation_DefaultRole() {
+	s.userRepo.On("GetByEmail", "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+	s.invitationRepo.On("HasPending", "new@example.com", mock.AnythingOfType("time.Time")).Return(false, nil)
+	s.invitationRepo.On("Create", mock.AnythingOfType("*model.Invitation")).Return(nil)
+	s.mailer.On("Send", mock.AnythingOfType("*mailer.Message")).Return(nil)
+
+	// Execute: a service API key has no user
+	result, err := s.invitations.CreateInvitation(s.ctx, &model.Principal{APIKeyID: 4, Service: "crm"}, &model.CreateInvitationRequest{
+		Email: "new@example.com",
+	})
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.RoleUser, result.Role)
+	assert.Nil(s.T(), result.InvitedByID)
+}
+
+func (s *ServiceTestSuite) TestCreateInvitation_AdminByNonAdmin() {
+	// An admin by global role is only a member of this organization
+	s.membershipRepo.On("WithContext", mock.Anything).Return(s.membershipRepo)
+	s.membershipRepo.On("Get", uint(1)).Return(&model.Membership{UserID: 1, Role: model.OrganizationRoleMember}, nil)
+
+	// Execute
+	result, err := s.invitations.CreateInvitation(s.ctx, &model.Principal{UserID: 1}, &model.CreateInvitationRequest{
+		Email: "new@example.com",
+		Role:  model.RoleAdmin,
+	})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvitationRoleNotAllowed)
+	assert.Nil(s.T(), result)
+	s.invitationRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestCreateInvitation_ExistingUser() {
+	s.userRepo.On("GetByEmail", "test@example.com").Return(s.newVerifiedUser(), nil)
+
+	// Execute
+	_, err := s.invitations.CreateInvitation(s.ctx, &model.Principal{UserID: 1}, &model.CreateInvitationRequest{
+		Email: "test@example.com",
+	})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrEmailTaken)
+}
+
+func (s *ServiceTestSuite) TestCreateInvitation_AlreadyPending() {
+	s.userRepo.On("GetByEmail", "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+	s.invitationRepo.On("HasPending", "new@example.com", mock.AnythingOfType("time.Time")).Return(true, nil)
+
+	// Execute
+	_, err := s.invitations.CreateInvitation(s.ctx, &model.Principal{UserID: 1}, &model.CreateInvitationRequest{
+		Email: "new@example.com",
+	})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvitationPending)
+	s.invitationRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestCreateInvitation_MailFails() {
+	s.userRepo.On("GetByEmail", "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+	s.invitationRepo.On("HasPending", "new@example.com", mock.AnythingOfType("time.Time")).Return(false, nil)
+	s.invitationRepo.On("Create", mock.AnythingOfType("*model.Invitation")).Return(nil)
+	s.mailer.On("Send", mock.AnythingOfType("*mailer.Message")).Return(errors.New("smtp down"))
+
+	// Execute
+	result, err := s.invitations.CreateInvitation(s.ctx, &model.Principal{UserID: 1}, &model.CreateInvitationRequest{
+		Email: "new@example.com",
+	})
+
+	// Assert: the invitation stays and can be resent
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), result)
+}
+
+// Test ListInvitations
+func (s *ServiceTestSuite) TestListInvitations() {
+	expired := s.newInvitation()
+	expired.ExpiresAt = time.Now().Add(-time.Minute)
+	s.invitationRepo.On("List", 10, 0).Return([]*model.Invitation{s.newInvitation(), expired}, nil)
+
+	// Execute
+	result, err := s.invitations.ListInvitations(s.ctx, 10, 0)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result, 2)
+	assert.Equal(s.T(), model.InvitationStatusPending, result[0].Status)
+	assert.Equal(s.T(), model.InvitationStatusExpired, result[1].Status)
+}
+
+// Test ResendInvitation
+func (s *ServiceTestSuite) TestResendInvitation_Expired() {
+	invitation := s.newInvitation()
+	invitation.ExpiresAt = time.Now().Add(-time.Minute)
+	s.invitationRepo.On("GetByID", uint(3)).Return(invitation, nil)
+	s.invitationRepo.On("Renew", uint(3), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(true, nil)
+	s.mailer.On("Send", mock.AnythingOfType("*mailer.Message")).Return(nil)
+
+	// Execute
+	result, err := s.invitations.ResendInvitation(s.ctx, 3)
+
+	// Assert: a new token replaces the old one
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.InvitationStatusPending, result.Status)
+	tokenHash := s.invitationRepo.Calls[2].Arguments.String(1)
+	assert.NotEqual(s.T(), hashSecret("invite-token"), tokenHash)
+	assert.Equal(s.T(), tokenHash, hashSecret(s.mailedToken()))
+}
+
+func (s *ServiceTestSuite) TestResendInvitation_Accepted() {
+	invitation := s.newInvitation()
+	acceptedAt := time.Now()
+	invitation.AcceptedAt = &acceptedAt
+	s.invitationRepo.On("GetByID", uint(3)).Return(invitation, nil)
+
+	// Execute
+	_, err := s.invitations.ResendInvitation(s.ctx, 3)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvitationClosed)
+	s.mailer.AssertNotCalled(s.T(), "Send", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestResendInvitation_NotFound() {
+	s.invitationRepo.On("GetByID", uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	_, err := s.invitations.ResendInvitation(s.ctx, 9)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvitationNotFound)
+}
+
+// Test RevokeInvitation
+func (s *ServiceTestSuite) TestRevokeInvitation_Success() {
+	s.invitationRepo.On("GetByID", uint(3)).Return(s.newInvitation(), nil)
+	s.invitationRepo.On("Revoke", uint(3), mock.AnythingOfType("time.Time")).Return(true, nil)
+
+	// Execute
+	err := s.invitations.RevokeInvitation(s.ctx, 3)
+
+	// Assert
+	assert.NoError(s.T(), err)
+}
+
+func (s *ServiceTestSuite) TestRevokeInvitation_AcceptedMeanwhile() {
+	s.invitationRepo.On("GetByID", uint(3)).Return(s.newInvitation(), nil)
+	s.invitationRepo.On("Revoke", uint(3), mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	// Execute
+	err := s.invitations.RevokeInvitation(s.ctx, 3)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvitationClosed)
+}
+
+// Test AcceptInvitation
+func (s *ServiceTestSuite) TestAcceptInvitation_Success() {
+	invitation := s.newInvitation()
+	invitation.Role = model.RoleAdmin
+	invitation.OrganizationID = 2
+	s.invitationRepo.On("GetByTokenHash", hashSecret("invite-token")).Return(invitation, nil)
+	s.userRepo.On("GetByEmail", "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+	s.userRepo.On("GetByUsername", "newuser").Return(nil, gorm.ErrRecordNotFound)
+	s.invitationRepo.On("MarkAccepted", uint(3), hashSecret("invite-token"), mock.AnythingOfType("time.Time")).Return(true, nil)
+	s.userRepo.On("Create", mock.AnythingOfType("*model.User")).Return(nil)
+
+	// Execute
+	result, err := s.invitations.AcceptInvitation(s.ctx, &model.AcceptInvitationRequest{
+		Token:    "invite-token",
+		Username: "newuser",
+		Password: "Sup3rSecret!",
+	})
+
+	// Assert: the invitation decides the email and role
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "new@example.com", result.Email)
+	assert.Equal(s.T(), model.RoleAdmin, result.Role)
+	assert.NotNil(s.T(), result.EmailVerifiedAt)
+
+	created := s.userRepo.Calls[5].Arguments.Get(0).(*model.User)
+	ok, _ := s.passwordHasher.Verify("Sup3rSecret!", created.Password)
+	assert.True(s.T(), ok)
+
+	// The account is created in the inviting organization
+	assert.Equal(s.T(), uint(2), created.OrganizationID)
+	ctx := s.userRepo.Calls[0].Arguments.Get(0).(context.Context)
+	organizationID, _ := tenant.OrganizationFromContext(ctx)
+	assert.Equal(s.T(), uint(2), organizationID)
+}
+
+func (s *ServiceTestSuite) TestAcceptInvitation_CreateFails() {
+	s.invitationRepo.On("GetByTokenHash", hashSecret("invite-token")).Return(s.newInvitation(), nil)
+	s.userRepo.On("GetByEmail", "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+	s.userRepo.On("GetByUsername", "newuser").Return(nil, gorm.ErrRecordNotFound)
+	s.invitationRepo.On("MarkAccepted", uint(3), hashSecret("invite-token"), mock.AnythingOfType("time.Time")).Return(true, nil)
+	s.userRepo.On("Create", mock.AnythingOfType("*model.User")).Return(errors.New("db down"))
+
+	// Execute
+	_, err := s.invitations.AcceptInvitation(s.ctx, &model.AcceptInvitationRequest{
+		Token:    "invite-token",
+		Username: "newuser",
+		Password: "Sup3rSecret!",
+	})
+
+	// Assert: accepting and creating the account are one transaction, so
+	// the invitation is not used up
+	assert.EqualError(s.T(), err, "db down")
+	s.transactor.AssertCalled(s.T(), "Transaction", mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestAcceptInvitation_InvalidToken() {
+	s.invitationRepo.On("GetByTokenHash", hashSecret("wrong")).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	_, err := s.invitations.AcceptInvitation(s.ctx, &model.AcceptInvitationRequest{
+		Token:    "wrong",
+		Username: "newuser",
+		Password: "Sup3rSecret!",
+	})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidInvitation)
+}
+
+func (s *ServiceTestSuite) TestAcceptInvitation_Closed() {
+	revoked := s.newInvitation()
+	revokedAt := time.Now()
+	revoked.RevokedAt = &revokedAt
+	expired := s.newInvitation()
+	expired.ExpiresAt = time.Now().Add(-time.Second)
+
+	for _, invitation := range []*model.Invitation{revoked, expired} {
+		s.invitationRepo.ExpectedCalls = nil
+		s.invitationRepo.On("WithContext", mock.Anything).Return(s.invitationRepo)
+		s.invitationRepo.On("GetByTokenHash", hashSecret("invite-token")).Return(invitation, nil)
+
+		// Execute
+		_, err := s.invitations.AcceptInvitation(s.ctx, &model.AcceptInvitationRequest{
+			Token:    "invite-token",
+			Username: "newuser",
+			Password: "Sup3rSecret!",
+		})
+
+		// Assert
+		assert.ErrorIs(s.T(), err, ErrInvalidInvitation)
+	}
+	s.userRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestAcceptInvitation_UsernameTaken() {
+	s.invitationRepo.On("GetByTokenHash", hashSecret("invite-token")).Return(s.newInvitation(), nil)
+	s.userRepo.On("GetByEmail", "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+	s.userRepo.On("GetByUsername", "testuser").Return(s.newVerifiedUser(), nil)
+
+	// Execute
+	_, err := s.invitations.AcceptInvitation(s.ctx, &model.AcceptInvitationRequest{
+		Token:    "invite-token",
+		Username: "testuser",
+		Password: "Sup3rSecret!",
+	})
+
+	// Assert: the invitation can still be used with another username
+	assert.ErrorIs(s.T(), err, ErrUsernameTaken)
+	s.invitationRepo.AssertNotCalled(s.T(), "MarkAccepted", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestAcceptInvitation_WeakPassword() {
+	s.invitationRepo.On("GetByTokenHash", hashSecret("invite-token")).Return(s.newInvitation(), nil)
+	s.userRepo.On("GetByEmail", "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+	s.userRepo.On("GetByUsername", "newuser").Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	_, err := s.invitations.AcceptInvitation(s.ctx, &model.AcceptInvitationRequest{
+		Token:    "invite-token",
+		Username: "newuser",
+		Password: "short",
+	})
+
+	// Assert
+	var policyErr *PasswordPolicyError
+	assert.ErrorAs(s.T(), err, &policyErr)
+	s.invitationRepo.AssertNotCalled(s.T(), "MarkAccepted", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestAcceptInvitation_AcceptedConcurrently() {
+	s.invitationRepo.On("GetByTokenHash", hashSecret("invite-token")).Return(s.newInvitation(), nil)
+	s.userRepo.On("GetByEmail", "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+	s.userRepo.On("GetByUsername", "newuser").Return(nil, gorm.ErrRecordNotFound)
+	s.invitationRepo.On("MarkAccepted", uint(3), hashSecret("invite-token"), mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	// Execute
+	_, err := s.invitations.AcceptInvitation(s.ctx, &model.AcceptInvitationRequest{
+		Token:    "invite-token",
+		Username: "newuser",
+		Password: "Sup3rSecret!",
+	})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidInvitation)
+	s.userRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
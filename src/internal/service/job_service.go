@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/jobs"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrJobNotFound       = errors.New("job not found")
+	ErrJobNotRetryable   = errors.New("only failed or cancelled jobs can be retried")
+	ErrJobNotCancellable = errors.New("only pending jobs can be cancelled")
+	ErrJobUniqueKeyTaken = errors.New("another unfinished job holds the unique key of the job")
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=JobService --output=./mocks/service --outpkg=service --filename=job_service.go --structname=MockJobService --with-expecter=false
+type JobService interface {
+	// Enqueue stores the job to be run by a worker. Within a transaction
+	// of ctx it is only stored if the transaction commits. It reports
+	// false if an unfinished job holds the unique key given as an option.
+	Enqueue(ctx context.Context, job jobs.Job, opts ...jobs.Option) (bool, error)
+	ListJobs(ctx context.Context, filter *model.JobFilter, limit, offset int) ([]*model.JobResponse, error)
+	GetJob(ctx context.Context, id uint) (*model.JobResponse, error)
+	RetryJob(ctx context.Context, id uint) (*model.JobResponse, error)
+	CancelJob(ctx context.Context, id uint) (*model.JobResponse, error)
+}
+
+type jobService struct {
+	jobRepo repository.JobRepository
+	conf    config.JobsConfig
+}
+
+func NewJobService(jobRepo repository.JobRepository, conf *config.Config) JobService {
+	return &jobService{
+		jobRepo: jobRepo,
+		conf:    conf.Jobs,
+	}
+}
+
+func (s *jobService) Enqueue(ctx context.Context, job jobs.Job, opts ...jobs.Option) (bool, error) {
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return false, err
+	}
+
+	stored := &model.Job{
+		Type:        job.JobType(),
+		Payload:     string(payload),
+		Status:      model.JobPending,
+		MaxAttempts: s.conf.MaxAttempts,
+		RunAt:       time.Now(),
+	}
+	for _, opt := range opts {
+		opt(stored)
+	}
+	return s.jobRepo.WithContext(ctx).Enqueue(stored)
+}
+
+// ListJobs returns a page of jobs, newest first.
+func (s *jobService) ListJobs(ctx context.Context, filter *model.JobFilter, limit, offset int) ([]*model.JobResponse, error) {
+	stored, err := s.jobRepo.WithContext(ctx).List(filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*model.JobResponse, 0, len(stored))
+	for _, job := range stored {
+		responses = append(responses, toJobResponse(job))
+	}
+	return responses, nil
+}
+
+func (s *jobService) GetJob(ctx context.Context, id uint) (*model.JobResponse, error) {
+	job, err := s.getJob(s.jobRepo.WithContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+	return toJobResponse(job), nil
+}
+
+// RetryJob runs a failed or cancelled job again as soon as a worker gets to
+// it, with a fresh set of attempts.
+func (s *jobService) RetryJob(ctx context.Context, id uint) (*model.JobResponse, error) {
+	jobRepo := s.jobRepo.WithContext(ctx)
+
+	job, err := s.getJob(jobRepo, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != model.JobFailed && job.Status != model.JobCancelled {
+		return nil, ErrJobNotRetryable
+	}
+
+	retried, err := jobRepo.Retry(id)
+	if err != nil {
+		return nil, err
+	}
+	if !retried {
+		return nil, ErrJobUniqueKeyTaken
+	}
+	return s.GetJob(ctx, id)
+}
+
+// CancelJob stops a pending job from running. Running jobs cannot be
+// cancelled.
+func (s *jobService) CancelJob(ctx context.Context, id uint) (*model.JobResponse, error) {
+	jobRepo := s.jobRepo.WithContext(ctx)
+
+	if _, err := s.getJob(jobRepo, id); err != nil {
+		return nil, err
+	}
+
+	cancelled, err := jobRepo.Cancel(id)
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, ErrJobNotCancellable
+	}
+	return s.GetJob(ctx, id)
+}
+
+func (s *jobService) getJob(jobRepo repository.JobRepository, id uint) (*model.Job, error) {
+	job, err := jobRepo.GetByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrJobNotFound
+	}
+	return job, err
+}
+
+func toJobResponse(job *model.Job) *model.JobResponse {
+	return &model.JobResponse{
+		ID:          job.ID,
+		Type:        job.Type,
+		Payload:     json.RawMessage(job.Payload),
+		Status:      job.Status,
+		UniqueKey:   job.UniqueKey,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt,
+		LastError:   job.LastError,
+		FinishedAt:  job.FinishedAt,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+	}
+}
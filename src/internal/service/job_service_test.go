@@ -0,0 +1,91 @@
+package service
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/jobs"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/gorm"
+)
+
+// Test Enqueue
+func (s *ServiceTestSuite) TestEnqueue_Defaults() {
+	s.jobRepo.On("Enqueue", mock.MatchedBy(func(job *model.Job) bool {
+		return job.Type == jobs.SendEmailType &&
+			job.Payload == `{"to":"test@example.com","subject":"Hi","body":"Hello"}` &&
+			job.Status == model.JobPending &&
+			job.MaxAttempts == 5 &&
+			job.UniqueKey == nil &&
+			time.Since(job.RunAt) < time.Second
+	})).Return(true, nil)
+
+	enqueued, err := s.jobs.Enqueue(s.ctx, jobs.SendEmail{To: "test@example.com", Subject: "Hi", Body: "Hello"})
+
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), enqueued)
+}
+
+func (s *ServiceTestSuite) TestEnqueue_Options() {
+	s.jobRepo.On("Enqueue", mock.MatchedBy(func(job *model.Job) bool {
+		return job.MaxAttempts == 1 &&
+			*job.UniqueKey == "welcome:3" &&
+			time.Until(job.RunAt) > 59*time.Minute
+	})).Return(false, nil)
+
+	enqueued, err := s.jobs.Enqueue(s.ctx, jobs.SendEmail{To: "test@example.com"},
+		jobs.Delay(time.Hour), jobs.UniqueKey("welcome:3"), jobs.MaxAttempts(1))
+
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), enqueued)
+}
+
+// Test GetJob
+func (s *ServiceTestSuite) TestGetJob_NotFound() {
+	s.jobRepo.On("GetByID", uint(7)).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := s.jobs.GetJob(s.ctx, 7)
+
+	assert.ErrorIs(s.T(), err, ErrJobNotFound)
+}
+
+// Test RetryJob
+func (s *ServiceTestSuite) TestRetryJob_Success() {
+	s.jobRepo.On("GetByID", uint(7)).Return(&model.Job{ID: 7, Status: model.JobFailed, Payload: `{}`}, nil).Once()
+	s.jobRepo.On("Retry", uint(7)).Return(true, nil)
+	s.jobRepo.On("GetByID", uint(7)).Return(&model.Job{ID: 7, Status: model.JobPending, Payload: `{}`}, nil).Once()
+
+	job, err := s.jobs.RetryJob(s.ctx, 7)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.JobPending, job.Status)
+}
+
+func (s *ServiceTestSuite) TestRetryJob_NotFailed() {
+	s.jobRepo.On("GetByID", uint(7)).Return(&model.Job{ID: 7, Status: model.JobRunning}, nil)
+
+	_, err := s.jobs.RetryJob(s.ctx, 7)
+
+	assert.ErrorIs(s.T(), err, ErrJobNotRetryable)
+	s.jobRepo.AssertNotCalled(s.T(), "Retry", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestRetryJob_UniqueKeyTaken() {
+	s.jobRepo.On("GetByID", uint(7)).Return(&model.Job{ID: 7, Status: model.JobCancelled}, nil)
+	s.jobRepo.On("Retry", uint(7)).Return(false, nil)
+
+	_, err := s.jobs.RetryJob(s.ctx, 7)
+
+	assert.ErrorIs(s.T(), err, ErrJobUniqueKeyTaken)
+}
+
+// Test CancelJob
+func (s *ServiceTestSuite) TestCancelJob_NotPending() {
+	s.jobRepo.On("GetByID", uint(7)).Return(&model.Job{ID: 7, Status: model.JobRunning}, nil)
+	s.jobRepo.On("Cancel", uint(7)).Return(false, nil)
+
+	_, err := s.jobs.CancelJob(s.ctx, 7)
+
+	assert.ErrorIs(s.T(), err, ErrJobNotCancellable)
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+// Reasons recorded with login attempts.
+const (
+	LoginReasonInvalidCredentials = "invalid_credentials"
+	LoginReasonInvalidMFACode     = "invalid_mfa_code"
+	LoginReasonIncorrectPassword  = "incorrect_password"
+	LoginReasonThrottled          = "throttled"
+)
+
+// LoginThrottledError is returned when an account or client address has too
+// many recent failed logins. It reads the same whether or not the account
+// exists.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=LockoutService --output=./mocks/service --outpkg=service --filename=lockout_service.go --structname=MockLockoutService --with-expecter=false
+type LockoutService interface {
+	Check(user *model.User, login string, client *model.ClientInfo) error
+	RecordFailure(user *model.User, login string, client *model.ClientInfo, reason string) error
+	RecordSuccess(user *model.User, login string, client *model.ClientInfo) error
+	Unlock(ctx context.Context, id uint) error
+	ListLoginAttempts(ctx context.Context, id uint, limit, offset int) ([]*model.LoginAttemptResponse, error)
+}
+
+type lockoutService struct {
+	userRepo     repository.UserRepository
+	attemptRepo  repository.LoginAttemptRepository
+	throttleRepo repository.LoginThrottleRepository
+	conf         config.LockoutConfig
+}
+
+func NewLockoutService(
+	userRepo repository.UserRepository,
+	attemptRepo repository.LoginAttemptRepository,
+	throttleRepo repository.LoginThrottleRepository,
+	conf *config.Config,
+) LockoutService {
+	return &lockoutService{
+		userRepo:     userRepo,
+		attemptRepo:  attemptRepo,
+		throttleRepo: throttleRepo,
+		conf:         conf.Lockout,
+	}
+}
+
+// Check returns a *LoginThrottledError if the account or the client address
+// may not try to log in yet, and records the rejected attempt. user is nil
+// when the login does not match any user; the login name is throttled
+// instead so that unknown accounts behave like existing ones.
+func (s *lockoutService) Check(user *model.User, login string, client *model.ClientInfo) error {
+	now := time.Now()
+
+	retryAfter, err := s.accountRetryAfter(accountSubject(user, login), now)
+	if err != nil {
+		return err
+	}
+
+	ipRetryAfter, err := s.lockedFor(ipSubject(client), now)
+	if err != nil {
+		return err
+	}
+	if ipRetryAfter > retryAfter {
+		retryAfter = ipRetryAfter
+	}
+
+	if retryAfter <= 0 {
+		return nil
+	}
+
+	if err := s.recordAttempt(user, login, client, false, LoginReasonThrottled); err != nil {
+		return err
+	}
+	return &LoginThrottledError{RetryAfter: retryAfter}
+}
+
+// RecordFailure records a failed attempt and locks the account or the
+// client address once it reaches its threshold.
+func (s *lockoutService) RecordFailure(user *model.User, login string, client *model.ClientInfo, reason string) error {
+	if err := s.recordAttempt(user, login, client, false, reason); err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	if err := s.countFailure(accountSubject(user, login), now, s.conf.MaxFailures, s.conf.Duration); err != nil {
+		return err
+	}
+
+	if subject := ipSubject(client); subject != "" {
+		return s.countFailure(subject, now, s.conf.IPMaxFailures, s.conf.IPDuration)
+	}
+	return nil
+}
+
+// RecordSuccess records a successful attempt and clears the failures of the
+// account. Failures from the client address are kept, so one valid account
+// cannot be used to keep guessing at others.
+func (s *lockoutService) RecordSuccess(user *model.User, login string, client *model.ClientInfo) error {
+	if err := s.recordAttempt(user, login, client, true, ""); err != nil {
+		return err
+	}
+	return s.throttleRepo.Reset(accountSubject(user, login))
+}
+
+// Unlock lifts a lockout of the user and clears its failed attempts.
+func (s *lockoutService) Unlock(ctx context.Context, id uint) error {
+	user, err := s.userRepo.WithContext(ctx).GetByID(id)
+	if err != nil {
+		return err
+	}
+	return s.throttleRepo.Reset(accountSubject(user, ""))
+}
+
+func (s *lockoutService) ListLoginAttempts(ctx context.Context, id uint, limit, offset int) ([]*model.LoginAttemptResponse, error) {
+	if _, err := s.userRepo.WithContext(ctx).GetByID(id); err != nil {
+		return nil, err
+	}
+
+	attempts, err := s.attemptRepo.ListByUser(id, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*model.LoginAttemptResponse, 0, len(attempts))
+	for _, attempt := range attempts {
+		responses = append(responses, &model.LoginAttemptResponse{
+			ID:        attempt.ID,
+			IP:        attempt.IP,
+			UserAgent: attempt.UserAgent,
+			Success:   attempt.Success,
+			Reason:    attempt.Reason,
+			CreatedAt: attempt.CreatedAt,
+		})
+	}
+
+	return responses, nil
+}
+
+// accountRetryAfter returns how long the account has to wait, either for a
+// lockout to end or for the progressive delay after its last failure.
+func (s *lockoutService) accountRetryAfter(subject string, now time.Time) (time.Duration, error) {
+	throttle, err := s.getThrottle(subject)
+	if err != nil || throttle == nil {
+		return 0, err
+	}
+
+	if throttle.LockedUntil != nil && now.Before(*throttle.LockedUntil) {
+		return throttle.LockedUntil.Sub(now), nil
+	}
+
+	if throttle.Failures == 0 || throttle.LastFailureAt.Before(now.Add(-s.conf.FailureWindow)) {
+		return 0, nil
+	}
+
+	return throttle.LastFailureAt.Add(s.delay(throttle.Failures)).Sub(now), nil
+}
+
+// lockedFor returns how long the subject stays locked, or zero.
+func (s *lockoutService) lockedFor(subject string, now time.Time) (time.Duration, error) {
+	if subject == "" {
+		return 0, nil
+	}
+
+	throttle, err := s.getThrottle(subject)
+	if err != nil || throttle == nil {
+		return 0, err
+	}
+
+	if throttle.LockedUntil != nil && now.Before(*throttle.LockedUntil) {
+		return throttle.LockedUntil.Sub(now), nil
+	}
+	return 0, nil
+}
+
+func (s *lockoutService) getThrottle(subject string) (*model.LoginThrottle, error) {
+	throttle, err := s.throttleRepo.Get(subject)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return throttle, err
+}
+
+func (s *lockoutService) countFailure(subject string, now time.Time, maxFailures int, duration time.Duration) error {
+	throttle, err := s.throttleRepo.RecordFailure(subject, now, now.Add(-s.conf.FailureWindow))
+	if err != nil {
+		return err
+	}
+
+	if maxFailures > 0 && throttle.Failures >= maxFailures {
+		return s.throttleRepo.Lock(subject, now.Add(duration))
+	}
+	return nil
+}
+
+// delay is the wait after the given number of consecutive failures:
+// BaseDelay, doubling with every further failure up to MaxDelay.
+func (s *lockoutService) delay(failures int) time.Duration {
+	delay := s.conf.BaseDelay
+	for i := 1; i < failures && delay < s.conf.MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > s.conf.MaxDelay {
+		delay = s.conf.MaxDelay
+	}
+	return delay
+}
+
+func (s *lockoutService) recordAttempt(user *model.User, login string, client *model.ClientInfo, success bool, reason string) error {
+	attempt := &model.LoginAttempt{
+		Login:   login,
+		Success: success,
+		Reason:  reason,
+	}
+	if user != nil {
+		attempt.UserID = &user.ID
+	}
+	if client != nil {
+		attempt.IP = client.IP
+		attempt.UserAgent = client.UserAgent
+	}
+	return s.attemptRepo.Create(attempt)
+}
+
+// accountSubject identifies the throttle of an account. Logins that do not
+// match a user are throttled by name.
+func accountSubject(user *model.User, login string) string {
+	if user != nil {
+		return fmt.Sprintf("user:%d", user.ID)
+	}
+	return "login:" + strings.ToLower(strings.TrimSpace(login))
+}
+
+func ipSubject(client *model.ClientInfo) string {
+	if client == nil || client.IP == "" {
+		return ""
+	}
+	return "ip:" + client.IP
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"errors"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/gorm"
+)
+
+var testClient = &model.ClientInfo{IP: "192.0.2.1", UserAgent: "test-agent"}
+
+// allowLogins lets every login pass the lockout checks.
+func (s *ServiceTestSuite) allowLogins() {
+	s.throttleRepo.On("Get", mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound).Maybe()
+	s.throttleRepo.On("RecordFailure", mock.AnythingOfType("string"), mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(&model.LoginThrottle{Failures: 1}, nil).Maybe()
+	s.throttleRepo.On("Reset", mock.AnythingOfType("string")).Return(nil).Maybe()
+	s.attemptRepo.On("Create", mock.AnythingOfType("*model.LoginAttempt")).Return(nil).Maybe()
+}
+
+func (s *ServiceTestSuite) TestLogin_FailureIsRecorded() {
+	user := s.newUserWithPassword("password123")
+
+	s.userRepo.On("GetByUsername", "testuser").Return(user, nil)
+	s.throttleRepo.On("Get", mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound)
+	s.attemptRepo.On("Create", mock.MatchedBy(func(attempt *model.LoginAttempt) bool {
+		return attempt.UserID != nil && *attempt.UserID == 1 &&
+			!attempt.Success &&
+			attempt.Reason == LoginReasonInvalidCredentials &&
+			attempt.IP == "192.0.2.1" &&
+			attempt.UserAgent == "test-agent"
+	})).Return(nil)
+	s.throttleRepo.On("RecordFailure", "user:1", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(&model.LoginThrottle{Failures: 1}, nil)
+	s.throttleRepo.On("RecordFailure", "ip:192.0.2.1", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(&model.LoginThrottle{Failures: 1}, nil)
+
+	// Execute
+	_, err := s.authService.Login(s.ctx, &model.LoginRequest{Login: "testuser", Password: "wrong"}, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidCredentials)
+	s.attemptRepo.AssertExpectations(s.T())
+	s.throttleRepo.AssertExpectations(s.T())
+	s.throttleRepo.AssertNotCalled(s.T(), "Lock", mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestLogin_UnknownUserIsThrottledByName() {
+	s.userRepo.On("GetByUsername", "Nobody").Return(nil, errors.New("not found"))
+	s.throttleRepo.On("Get", mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound)
+	s.attemptRepo.On("Create", mock.MatchedBy(func(attempt *model.LoginAttempt) bool {
+		return attempt.UserID == nil && attempt.Login == "Nobody" && !attempt.Success
+	})).Return(nil)
+	s.throttleRepo.On("RecordFailure", "login:nobody", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(&model.LoginThrottle{Failures: 1}, nil)
+	s.throttleRepo.On("RecordFailure", "ip:192.0.2.1", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(&model.LoginThrottle{Failures: 1}, nil)
+
+	// Execute
+	_, err := s.authService.Login(s.ctx, &model.LoginRequest{Login: "Nobody", Password: "password123"}, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidCredentials)
+	s.throttleRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestLogin_LocksAccountAtThreshold() {
+	user := s.newUserWithPassword("password123")
+
+	s.userRepo.On("GetByUsername", "testuser").Return(user, nil)
+	s.throttleRepo.On("Get", mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound)
+	s.attemptRepo.On("Create", mock.AnythingOfType("*model.LoginAttempt")).Return(nil)
+	s.throttleRepo.On("RecordFailure", "user:1", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(&model.LoginThrottle{Failures: 5}, nil)
+	s.throttleRepo.On("RecordFailure", "ip:192.0.2.1", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(&model.LoginThrottle{Failures: 5}, nil)
+	s.throttleRepo.On("Lock", "user:1", mock.MatchedBy(func(until time.Time) bool {
+		return until.Sub(time.Now().Add(15*time.Minute)).Abs() < time.Minute
+	})).Return(nil)
+
+	// Execute
+	_, err := s.authService.Login(s.ctx, &model.LoginRequest{Login: "testuser", Password: "wrong"}, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidCredentials)
+	s.throttleRepo.AssertExpectations(s.T())
+	s.throttleRepo.AssertNotCalled(s.T(), "Lock", "ip:192.0.2.1", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestLogin_LocksIPAtThreshold() {
+	s.userRepo.On("GetByUsername", "nobody").Return(nil, errors.New("not found"))
+	s.throttleRepo.On("Get", mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound)
+	s.attemptRepo.On("Create", mock.AnythingOfType("*model.LoginAttempt")).Return(nil)
+	s.throttleRepo.On("RecordFailure", "login:nobody", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(&model.LoginThrottle{Failures: 1}, nil)
+	s.throttleRepo.On("RecordFailure", "ip:192.0.2.1", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(&model.LoginThrottle{Failures: 20}, nil)
+	s.throttleRepo.On("Lock", "ip:192.0.2.1", mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Execute
+	_, err := s.authService.Login(s.ctx, &model.LoginRequest{Login: "nobody", Password: "password123"}, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidCredentials)
+	s.throttleRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestLogin_LockedAccount() {
+	user := s.newUserWithPassword("password123")
+	lockedUntil := time.Now().Add(10 * time.Minute)
+
+	s.userRepo.On("GetByUsername", "testuser").Return(user, nil)
+	s.throttleRepo.On("Get", "user:1").Return(&model.LoginThrottle{Subject: "user:1", LockedUntil: &lockedUntil}, nil)
+	s.throttleRepo.On("Get", "ip:192.0.2.1").Return(nil, gorm.ErrRecordNotFound)
+	s.attemptRepo.On("Create", mock.MatchedBy(func(attempt *model.LoginAttempt) bool {
+		return !attempt.Success && attempt.Reason == LoginReasonThrottled
+	})).Return(nil)
+
+	// Execute: even the right password is rejected
+	result, err := s.authService.Login(s.ctx, &model.LoginRequest{Login: "testuser", Password: "password123"}, testClient)
+
+	// Assert
+	var throttledErr *LoginThrottledError
+	assert.ErrorAs(s.T(), err, &throttledErr)
+	assert.InDelta(s.T(), (10 * time.Minute).Seconds(), throttledErr.RetryAfter.Seconds(), 5)
+	assert.Nil(s.T(), result)
+	s.throttleRepo.AssertNotCalled(s.T(), "RecordFailure", mock.Anything, mock.Anything, mock.Anything)
+	s.sessionRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestLogin_LockedUnknownLoginLooksTheSame() {
+	lockedUntil := time.Now().Add(10 * time.Minute)
+
+	s.userRepo.On("GetByUsername", "nobody").Return(nil, errors.New("not found"))
+	s.throttleRepo.On("Get", "login:nobody").Return(&model.LoginThrottle{Subject: "login:nobody", LockedUntil: &lockedUntil}, nil)
+	s.throttleRepo.On("Get", "ip:192.0.2.1").Return(nil, gorm.ErrRecordNotFound)
+	s.attemptRepo.On("Create", mock.AnythingOfType("*model.LoginAttempt")).Return(nil)
+
+	// Execute
+	_, err := s.authService.Login(s.ctx, &model.LoginRequest{Login: "nobody", Password: "password123"}, testClient)
+
+	// Assert
+	var throttledErr *LoginThrottledError
+	assert.ErrorAs(s.T(), err, &throttledErr)
+}
+
+func (s *ServiceTestSuite) TestLogin_LockedIP() {
+	user := s.newUserWithPassword("password123")
+	lockedUntil := time.Now().Add(time.Minute)
+
+	s.userRepo.On("GetByUsername", "testuser").Return(user, nil)
+	s.throttleRepo.On("Get", "user:1").Return(nil, gorm.ErrRecordNotFound)
+	s.throttleRepo.On("Get", "ip:192.0.2.1").Return(&model.LoginThrottle{Subject: "ip:192.0.2.1", LockedUntil: &lockedUntil}, nil)
+	s.attemptRepo.On("Create", mock.AnythingOfType("*model.LoginAttempt")).Return(nil)
+
+	// Execute
+	_, err := s.authService.Login(s.ctx, &model.LoginRequest{Login: "testuser", Password: "password123"}, testClient)
+
+	// Assert
+	var throttledErr *LoginThrottledError
+	assert.ErrorAs(s.T(), err, &throttledErr)
+}
+
+func (s *ServiceTestSuite) TestLogin_ProgressiveDelay() {
+	user := s.newUserWithPassword("password123")
+
+	s.userRepo.On("GetByUsername", "testuser").Return(user, nil)
+	s.throttleRepo.On("Get", "user:1").Return(&model.LoginThrottle{Subject: "user:1", Failures: 3, LastFailureAt: time.Now()}, nil)
+	s.throttleRepo.On("Get", "ip:192.0.2.1").Return(nil, gorm.ErrRecordNotFound)
+	s.attemptRepo.On("Create", mock.AnythingOfType("*model.LoginAttempt")).Return(nil)
+
+	// Execute
+	_, err := s.authService.Login(s.ctx, &model.LoginRequest{Login: "testuser", Password: "password123"}, testClient)
+
+	// Assert: the third failure doubled the one second delay twice
+	var throttledErr *LoginThrottledError
+	assert.ErrorAs(s.T(), err, &throttledErr)
+	assert.InDelta(s.T(), 4, throttledErr.RetryAfter.Seconds(), 0.5)
+}
+
+func (s *ServiceTestSuite) TestLogin_DelayHasPassed() {
+	user := s.newUserWithPassword("password123")
+
+	s.userRepo.On("GetByUsername", "testuser").Return(user, nil)
+	s.throttleRepo.On("Get", "user:1").Return(&model.LoginThrottle{Subject: "user:1", Failures: 3, LastFailureAt: time.Now().Add(-5 * time.Second)}, nil)
+	s.throttleRepo.On("Get", "ip:192.0.2.1").Return(nil, gorm.ErrRecordNotFound)
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	s.attemptRepo.On("Create", mock.MatchedBy(func(attempt *model.LoginAttempt) bool {
+		return attempt.Success && attempt.Reason == ""
+	})).Return(nil)
+	s.throttleRepo.On("Reset", "user:1").Return(nil)
+	s.sessionRepo.On("Create", mock.AnythingOfType("*model.Session")).Return(nil)
+
+	// Execute
+	result, err := s.authService.Login(s.ctx, &model.LoginRequest{Login: "testuser", Password: "password123"}, testClient)
+
+	// Assert: success clears the failures of the account
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), result.AccessToken)
+	s.throttleRepo.AssertExpectations(s.T())
+	s.attemptRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestLogin_MFAChallengeKeepsFailures() {
+	user := s.newUserWithPassword("password123")
+	credential, _ := s.newMFACredential(true)
+
+	s.userRepo.On("GetByUsername", "testuser").Return(user, nil)
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(credential, nil)
+	s.throttleRepo.On("Get", mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	result, err := s.authService.Login(s.ctx, &model.LoginRequest{Login: "testuser", Password: "password123"}, testClient)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), result.MFARequired)
+	s.throttleRepo.AssertNotCalled(s.T(), "Reset", mock.Anything)
+	s.attemptRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestLockoutDelay_IsCapped() {
+	lockout := s.lockoutService.(*lockoutService)
+
+	assert.Equal(s.T(), time.Second, lockout.delay(1))
+	assert.Equal(s.T(), 16*time.Second, lockout.delay(5))
+	assert.Equal(s.T(), 30*time.Second, lockout.delay(6))
+	assert.Equal(s.T(), 30*time.Second, lockout.delay(100))
+}
+
+func (s *ServiceTestSuite) TestUnlock_Success() {
+	user := s.newUserWithPassword("password123")
+
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.throttleRepo.On("Reset", "user:1").Return(nil)
+
+	// Execute
+	err := s.lockoutService.Unlock(s.ctx, 1)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	s.throttleRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestUnlock_UserNotFound() {
+	s.userRepo.On("GetByID", uint(999)).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	err := s.lockoutService.Unlock(s.ctx, 999)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+	s.throttleRepo.AssertNotCalled(s.T(), "Reset", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestListLoginAttempts_Success() {
+	user := s.newUserWithPassword("password123")
+	userID := user.ID
+
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.attemptRepo.On("ListByUser", uint(1), 20, 0).Return([]*model.LoginAttempt{
+		{ID: 2, UserID: &userID, IP: "192.0.2.1", Success: true},
+		{ID: 1, UserID: &userID, IP: "192.0.2.1", Reason: LoginReasonInvalidCredentials},
+	}, nil)
+
+	// Execute
+	attempts, err := s.lockoutService.ListLoginAttempts(s.ctx, 1, 20, 0)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), attempts, 2)
+	assert.True(s.T(), attempts[0].Success)
+	assert.Equal(s.T(), LoginReasonInvalidCredentials, attempts[1].Reason)
+}
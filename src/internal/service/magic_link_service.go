@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/mailer"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvalidMagicLink = errors.New("invalid or expired login link")
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=MagicLinkService --output=./mocks/service --outpkg=service --filename=magic_link_service.go --structname=MockMagicLinkService --with-expecter=false
+type MagicLinkService interface {
+	SendLink(ctx context.Context, req *model.MagicLinkRequest) error
+	Redeem(ctx context.Context, req *model.RedeemMagicLinkRequest, client *model.ClientInfo) (*model.LoginResponse, error)
+}
+
+type magicLinkService struct {
+	magicLinkRepo    repository.MagicLinkRepository
+	userRepo         repository.UserRepository
+	auth             AuthService
+	mailer           mailer.Mailer
+	conf             config.MagicLinkConfig
+	selfRegistration bool
+}
+
+func NewMagicLinkService(
+	magicLinkRepo repository.MagicLinkRepository,
+	userRepo repository.UserRepository,
+	auth AuthService,
+	mailer mailer.Mailer,
+	conf *config.Config,
+) MagicLinkService {
+	return &magicLinkService{
+		magicLinkRepo:    magicLinkRepo,
+		userRepo:         userRepo,
+		auth:             auth,
+		mailer:           mailer,
+		conf:             conf.MagicLink,
+		selfRegistration: conf.Auth.SelfRegistration,
+	}
+}
+
+// SendLink mails a login link to the address. Addresses without an account
+// only get one if self-registration is enabled, and at most MaxPerWindow
+// links are sent to an address per window. Both cases are silently ignored
+// so that callers cannot probe for accounts.
+func (s *magicLinkService) SendLink(ctx context.Context, req *model.MagicLinkRequest) error {
+	user, err := s.userRepo.WithContext(tenant.Unscoped(ctx)).GetByEmail(req.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if user == nil && !s.selfRegistration {
+		return nil
+	}
+
+	now := time.Now()
+
+	sent, err := s.magicLinkRepo.CountCreatedSince(req.Email, now.Add(-s.conf.Window))
+	if err != nil {
+		return err
+	}
+	if sent >= int64(s.conf.MaxPerWindow) {
+		log.Printf("Not sending login link to %s: %d sent in the last %s", req.Email, sent, s.conf.Window)
+		return nil
+	}
+
+	// Only the most recent link is valid.
+	if err := s.magicLinkRepo.InvalidateForEmail(req.Email, now); err != nil {
+		return err
+	}
+
+	token, tokenHash, err := newSecretToken()
+	if err != nil {
+		return err
+	}
+
+	err = s.magicLinkRepo.Create(&model.MagicLinkToken{
+		Email:     req.Email,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(s.conf.TokenTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	greeting, action := "Hello,", "create your account and log in"
+	if user != nil {
+		greeting, action = "Hello "+user.Username+",", "log in"
+	}
+	return s.mailer.Send(&mailer.Message{
+		To:      req.Email,
+		Subject: "Your login link",
+		Body: fmt.Sprintf(
+			"%s\n\nUse the link below to %s. It can be used once and expires in %s.\n\n%s?token=%s\n\nIf you did not ask for this, you can ignore this email.\n",
+			greeting, action, s.conf.TokenTTL, s.conf.LoginURL, token,
+		),
+	})
+}
+
+// Redeem consumes a login link and logs the owner of the address in like
+// CompleteExternalLogin, including the second factor. Following the link
+// proves the address, so it is marked as verified. Without an account one
+// is created in the organization of ctx if self-registration is enabled; it
+// has no password until the user sets one through a password reset.
+func (s *magicLinkService) Redeem(ctx context.Context, req *model.RedeemMagicLinkRequest, client *model.ClientInfo) (*model.LoginResponse, error) {
+	token, err := s.magicLinkRepo.GetByTokenHash(hashSecret(req.Token))
+	if err != nil {
+		return nil, ErrInvalidMagicLink
+	}
+
+	now := time.Now()
+	if token.UsedAt != nil || !now.Before(token.ExpiresAt) {
+		return nil, ErrInvalidMagicLink
+	}
+
+	// Consume the link first so that two concurrent redemptions cannot both
+	// create an account.
+	consumed, err := s.magicLinkRepo.MarkUsed(token.ID, now)
+	if err != nil {
+		return nil, err
+	}
+	if !consumed {
+		return nil, ErrInvalidMagicLink
+	}
+
+	// Addresses are unique across organizations; an existing account is
+	// logged in whatever the organization of the request
+	user, err := s.userRepo.WithContext(tenant.Unscoped(ctx)).GetByEmail(token.Email)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if !s.selfRegistration {
+			return nil, ErrInvalidMagicLink
+		}
+		user, err = s.createUser(s.userRepo.WithContext(ctx), token.Email, now)
+		if err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	case user.EmailVerifiedAt == nil:
+		user.EmailVerifiedAt = &now
+		if err := s.userRepo.WithContext(tenant.WithOrganization(ctx, user.OrganizationID)).Update(user); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.auth.CompleteExternalLogin(user, client)
+}
+
+func (s *magicLinkService) createUser(users repository.UserRepository, email string, verifiedAt time.Time) (*model.User, error) {
+	username, err := s.newUsername(users, email)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &model.User{
+		Username:        username,
+		Email:           email,
+		Role:            model.RoleUser,
+		EmailVerifiedAt: &verifiedAt,
+	}
+	if err := users.Create(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// newUsername derives a free username from the local part of the address,
+// adding a random suffix if it is taken or too short.
+func (s *magicLinkService) newUsername(users repository.UserRepository, email string) (string, error) {
+	local, _, _ := strings.Cut(strings.ToLower(email), "@")
+	base := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '.' || r == '_' || r == '-' {
+			return r
+		}
+		return -1
+	}, local)
+	if base == "" {
+		base = "user"
+	}
+	if len(base) > 40 {
+		base = base[:40]
+	}
+
+	candidate := base
+	for i := 0; i < 5; i++ {
+		if len(candidate) >= 3 {
+			if existing, _ := users.GetByUsername(candidate); existing == nil {
+				return candidate, nil
+			}
+		}
+
+		suffix := make([]byte, 3)
+		if _, err := rand.Read(suffix); err != nil {
+			return "", err
+		}
+		candidate = base + "-" + hex.EncodeToString(suffix)
+	}
+	return "", errors.New("no free username found for " + email)
+}
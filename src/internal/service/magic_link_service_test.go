@@ -0,0 +1,221 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/mailer"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/gorm"
+)
+
+func (s *ServiceTestSuite) newMagicLinkToken(email string) *model.MagicLinkToken {
+	return &model.MagicLinkToken{
+		ID:        5,
+		Email:     email,
+		TokenHash: hashSecret("magic-token"),
+		ExpiresAt: time.Now().Add(time.Minute),
+	}
+}
+
+func (s *ServiceTestSuite) TestSendMagicLink_ExistingUser() {
+	s.userRepo.On("GetByEmail", "test@example.com").Return(s.newVerifiedUser(), nil)
+	s.magicLinkRepo.On("CountCreatedSince", "test@example.com", mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+	s.magicLinkRepo.On("InvalidateForEmail", "test@example.com", mock.AnythingOfType("time.Time")).Return(nil)
+	s.magicLinkRepo.On("Create", mock.AnythingOfType("*model.MagicLinkToken")).Return(nil)
+	s.mailer.On("Send", mock.AnythingOfType("*mailer.Message")).Return(nil)
+
+	// Execute
+	err := s.magicLinks.SendLink(s.ctx, &model.MagicLinkRequest{Email: "test@example.com"})
+
+	// Assert: the mailed token matches the stored hash
+	assert.NoError(s.T(), err)
+	stored := s.magicLinkRepo.Calls[2].Arguments.Get(0).(*model.MagicLinkToken)
+	assert.WithinDuration(s.T(), time.Now().Add(15*time.Minute), stored.ExpiresAt, 5*time.Second)
+
+	message := s.mailer.Calls[0].Arguments.Get(0).(*mailer.Message)
+	assert.Equal(s.T(), "test@example.com", message.To)
+	assert.Contains(s.T(), message.Body, "Hello testuser,")
+	_, token, found := strings.Cut(message.Body, "http://localhost/magic-link?token=")
+	assert.True(s.T(), found)
+	token, _, _ = strings.Cut(token, "\n")
+	assert.Equal(s.T(), stored.TokenHash, hashSecret(token))
+}
+
+func (s *ServiceTestSuite) TestSendMagicLink_NewAddress() {
+	s.userRepo.On("GetByEmail", "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+	s.magicLinkRepo.On("CountCreatedSince", "new@example.com", mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+	s.magicLinkRepo.On("InvalidateForEmail", "new@example.com", mock.AnythingOfType("time.Time")).Return(nil)
+	s.magicLinkRepo.On("Create", mock.AnythingOfType("*model.MagicLinkToken")).Return(nil)
+	s.mailer.On("Send", mock.AnythingOfType("*mailer.Message")).Return(nil)
+
+	// Execute
+	err := s.magicLinks.SendLink(s.ctx, &model.MagicLinkRequest{Email: "new@example.com"})
+
+	// Assert
+	assert.NoError(s.T(), err)
+	message := s.mailer.Calls[0].Arguments.Get(0).(*mailer.Message)
+	assert.Contains(s.T(), message.Body, "create your account")
+}
+
+func (s *ServiceTestSuite) TestSendMagicLink_NewAddressWithoutSelfRegistration() {
+	s.conf.Auth.SelfRegistration = false
+	magicLinks := NewMagicLinkService(s.magicLinkRepo, s.userRepo, s.authService, s.mailer, s.conf)
+	s.userRepo.On("GetByEmail", "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	err := magicLinks.SendLink(s.ctx, &model.MagicLinkRequest{Email: "new@example.com"})
+
+	// Assert: ignored like an unknown address in a password reset
+	assert.NoError(s.T(), err)
+	s.magicLinkRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+	s.mailer.AssertNotCalled(s.T(), "Send", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestSendMagicLink_Throttled() {
+	s.userRepo.On("GetByEmail", "test@example.com").Return(s.newVerifiedUser(), nil)
+	s.magicLinkRepo.On("CountCreatedSince", "test@example.com", mock.AnythingOfType("time.Time")).Return(int64(3), nil)
+
+	// Execute
+	err := s.magicLinks.SendLink(s.ctx, &model.MagicLinkRequest{Email: "test@example.com"})
+
+	// Assert
+	assert.NoError(s.T(), err)
+	since := s.magicLinkRepo.Calls[0].Arguments.Get(1).(time.Time)
+	assert.WithinDuration(s.T(), time.Now().Add(-15*time.Minute), since, 5*time.Second)
+	s.magicLinkRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+	s.mailer.AssertNotCalled(s.T(), "Send", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestRedeemMagicLink_ExistingUser() {
+	user := s.newVerifiedUser()
+	s.magicLinkRepo.On("GetByTokenHash", hashSecret("magic-token")).Return(s.newMagicLinkToken("test@example.com"), nil)
+	s.magicLinkRepo.On("MarkUsed", uint(5), mock.AnythingOfType("time.Time")).Return(true, nil)
+	s.userRepo.On("GetByEmail", "test@example.com").Return(user, nil)
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	s.sessionRepo.On("Create", mock.AnythingOfType("*model.Session")).Return(nil)
+	s.allowLogins()
+
+	// Execute
+	result, err := s.magicLinks.Redeem(s.ctx, &model.RedeemMagicLinkRequest{Token: "magic-token"}, testClient)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), result.AccessToken)
+	s.userRepo.AssertNotCalled(s.T(), "Update", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestRedeemMagicLink_VerifiesEmail() {
+	user := s.newVerifiedUser()
+	user.EmailVerifiedAt = nil
+	s.magicLinkRepo.On("GetByTokenHash", hashSecret("magic-token")).Return(s.newMagicLinkToken("test@example.com"), nil)
+	s.magicLinkRepo.On("MarkUsed", uint(5), mock.AnythingOfType("time.Time")).Return(true, nil)
+	s.userRepo.On("GetByEmail", "test@example.com").Return(user, nil)
+	s.userRepo.On("Update", user).Return(nil)
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	s.sessionRepo.On("Create", mock.AnythingOfType("*model.Session")).Return(nil)
+	s.allowLogins()
+
+	// Execute
+	_, err := s.magicLinks.Redeem(s.ctx, &model.RedeemMagicLinkRequest{Token: "magic-token"}, testClient)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), user.EmailVerifiedAt)
+}
+
+func (s *ServiceTestSuite) TestRedeemMagicLink_CreatesUser() {
+	s.magicLinkRepo.On("GetByTokenHash", hashSecret("magic-token")).Return(s.newMagicLinkToken("New.User+tag@example.com"), nil)
+	s.magicLinkRepo.On("MarkUsed", uint(5), mock.AnythingOfType("time.Time")).Return(true, nil)
+	s.userRepo.On("GetByEmail", "New.User+tag@example.com").Return(nil, gorm.ErrRecordNotFound)
+	s.userRepo.On("GetByUsername", "new.usertag").Return(&model.User{ID: 2}, nil)
+	s.userRepo.On("GetByUsername", mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound)
+	s.userRepo.On("Create", mock.AnythingOfType("*model.User")).Run(func(args mock.Arguments) {
+		args.Get(0).(*model.User).ID = 3
+	}).Return(nil)
+	s.mfaRepo.On("GetByUserID", uint(3)).Return(nil, gorm.ErrRecordNotFound)
+	s.sessionRepo.On("Create", mock.AnythingOfType("*model.Session")).Return(nil)
+	s.allowLogins()
+
+	// Execute
+	result, err := s.magicLinks.Redeem(s.ctx, &model.RedeemMagicLinkRequest{Token: "magic-token"}, testClient)
+
+	// Assert: the taken username gets a suffix
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), result.AccessToken)
+	var created *model.User
+	for _, call := range s.userRepo.Calls {
+		if call.Method == "Create" {
+			created = call.Arguments.Get(0).(*model.User)
+		}
+	}
+	assert.Regexp(s.T(), `^new\.usertag-[0-9a-f]{6}$`, created.Username)
+	assert.Equal(s.T(), "New.User+tag@example.com", created.Email)
+	assert.Equal(s.T(), model.RoleUser, created.Role)
+	assert.NotNil(s.T(), created.EmailVerifiedAt)
+	// No password until the user sets one
+	assert.Empty(s.T(), created.Password)
+}
+
+func (s *ServiceTestSuite) TestRedeemMagicLink_NewAddressWithoutSelfRegistration() {
+	s.conf.Auth.SelfRegistration = false
+	magicLinks := NewMagicLinkService(s.magicLinkRepo, s.userRepo, s.authService, s.mailer, s.conf)
+	s.magicLinkRepo.On("GetByTokenHash", hashSecret("magic-token")).Return(s.newMagicLinkToken("new@example.com"), nil)
+	s.magicLinkRepo.On("MarkUsed", uint(5), mock.AnythingOfType("time.Time")).Return(true, nil)
+	s.userRepo.On("GetByEmail", "new@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	result, err := magicLinks.Redeem(s.ctx, &model.RedeemMagicLinkRequest{Token: "magic-token"}, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidMagicLink)
+	assert.Nil(s.T(), result)
+	s.userRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestRedeemMagicLink_InvalidToken() {
+	used := time.Now()
+	cases := map[string]func(*model.MagicLinkToken){
+		"used":    func(t *model.MagicLinkToken) { t.UsedAt = &used },
+		"expired": func(t *model.MagicLinkToken) { t.ExpiresAt = time.Now().Add(-time.Second) },
+	}
+
+	for name, mutate := range cases {
+		token := s.newMagicLinkToken("test@example.com")
+		mutate(token)
+		s.magicLinkRepo.ExpectedCalls = nil
+		s.magicLinkRepo.On("GetByTokenHash", hashSecret("magic-token")).Return(token, nil)
+
+		// Execute
+		_, err := s.magicLinks.Redeem(s.ctx, &model.RedeemMagicLinkRequest{Token: "magic-token"}, testClient)
+
+		// Assert
+		assert.ErrorIs(s.T(), err, ErrInvalidMagicLink, name)
+	}
+	s.magicLinkRepo.AssertNotCalled(s.T(), "MarkUsed", mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestRedeemMagicLink_AlreadyRedeemedConcurrently() {
+	s.magicLinkRepo.On("GetByTokenHash", hashSecret("magic-token")).Return(s.newMagicLinkToken("test@example.com"), nil)
+	s.magicLinkRepo.On("MarkUsed", uint(5), mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	// Execute
+	_, err := s.magicLinks.Redeem(s.ctx, &model.RedeemMagicLinkRequest{Token: "magic-token"}, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidMagicLink)
+	s.userRepo.AssertNotCalled(s.T(), "GetByEmail", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestRedeemMagicLink_UnknownToken() {
+	s.magicLinkRepo.On("GetByTokenHash", hashSecret("unknown")).Return(nil, errors.New("record not found"))
+
+	// Execute
+	_, err := s.magicLinks.Redeem(s.ctx, &model.RedeemMagicLinkRequest{Token: "unknown"}, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidMagicLink)
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/totp"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrMFAAlreadyEnabled = errors.New("MFA is already enabled")
+	ErrMFANotEnabled     = errors.New("MFA is not enabled")
+	ErrInvalidMFACode    = errors.New("invalid MFA code")
+)
+
+// totpSkew is the number of time steps a code may be off by to allow for
+// clock drift between server and authenticator.
+const totpSkew = 1
+
+const recoveryCodeAlphabet = "abcdefghijklmnopqrstuvwxyz234567"
+
+var totpCodePattern = regexp.MustCompile(`^\d{6}$`)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=MFAService --output=./mocks/service --outpkg=service --filename=mfa_service.go --structname=MockMFAService --with-expecter=false
+type MFAService interface {
+	Enroll(ctx context.Context, userID uint) (*model.MFAEnrollResponse, error)
+	Confirm(userID uint, req *model.MFACodeRequest) (*model.MFARecoveryCodesResponse, error)
+	Disable(userID uint, req *model.MFACodeRequest) error
+	RegenerateRecoveryCodes(userID uint, req *model.MFACodeRequest) (*model.MFARecoveryCodesResponse, error)
+	IsEnabled(userID uint) (bool, error)
+	IsRequired(user *model.User) bool
+	VerifyCode(userID uint, code string) error
+}
+
+type mfaService struct {
+	userRepo repository.UserRepository
+	mfaRepo  repository.MFARepository
+	cipher   *secretCipher
+	conf     config.MFAConfig
+}
+
+func NewMFAService(
+	userRepo repository.UserRepository,
+	mfaRepo repository.MFARepository,
+	conf *config.Config,
+) (MFAService, error) {
+	secretCipher, err := newSecretCipher(conf.MFA.EncryptionKey)
+	if err != nil {
+		return nil, errors.New("mfa.encryption_key must be set")
+	}
+
+	return &mfaService{
+		userRepo: userRepo,
+		mfaRepo:  mfaRepo,
+		cipher:   secretCipher,
+		conf:     conf.MFA,
+	}, nil
+}
+
+// Enroll creates a new unconfirmed TOTP secret for the user, replacing an
+// earlier unconfirmed one. It becomes active once Confirm succeeds.
+func (s *mfaService) Enroll(ctx context.Context, userID uint) (*model.MFAEnrollResponse, error) {
+	user, err := s.userRepo.WithContext(ctx).GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.getCredential(userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.IsConfirmed() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := s.cipher.Encrypt(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.mfaRepo.Replace(&model.MFACredential{
+		UserID:          userID,
+		SecretEncrypted: encrypted,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.MFAEnrollResponse{
+		Secret:     totp.EncodeSecret(secret),
+		OTPAuthURI: totp.URI(s.conf.Issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm activates the pending secret with a first code from the
+// authenticator and returns a fresh set of recovery codes.
+func (s *mfaService) Confirm(userID uint, req *model.MFACodeRequest) (*model.MFARecoveryCodesResponse, error) {
+	credential, err := s.getCredential(userID)
+	if err != nil {
+		return nil, err
+	}
+	if credential == nil {
+		return nil, ErrMFANotEnabled
+	}
+	if credential.IsConfirmed() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := s.cipher.Decrypt(credential.SecretEncrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	step, ok := totp.Validate(secret, req.Code, now, totpSkew)
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	if err := s.mfaRepo.Confirm(credential.ID, now, step); err != nil {
+		return nil, err
+	}
+
+	return s.newRecoveryCodes(userID)
+}
+
+// Disable removes the second factor after checking a current code.
+func (s *mfaService) Disable(userID uint, req *model.MFACodeRequest) error {
+	if err := s.VerifyCode(userID, req.Code); err != nil {
+		return err
+	}
+	return s.mfaRepo.DeleteForUser(userID)
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after checking a
+// current code.
+func (s *mfaService) RegenerateRecoveryCodes(userID uint, req *model.MFACodeRequest) (*model.MFARecoveryCodesResponse, error) {
+	if err := s.VerifyCode(userID, req.Code); err != nil {
+		return nil, err
+	}
+	return s.newRecoveryCodes(userID)
+}
+
+func (s *mfaService) IsEnabled(userID uint) (bool, error) {
+	credential, err := s.getCredential(userID)
+	if err != nil {
+		return false, err
+	}
+	return credential != nil && credential.IsConfirmed(), nil
+}
+
+// IsRequired reports whether the user's role must use a second factor.
+func (s *mfaService) IsRequired(user *model.User) bool {
+	return slices.Contains(s.conf.RequiredRoles, user.Role)
+}
+
+// VerifyCode accepts a TOTP code that has not been used before or an unused
+// recovery code, which is consumed.
+func (s *mfaService) VerifyCode(userID uint, code string) error {
+	credential, err := s.getCredential(userID)
+	if err != nil {
+		return err
+	}
+	if credential == nil || !credential.IsConfirmed() {
+		return ErrMFANotEnabled
+	}
+
+	code = strings.TrimSpace(code)
+	if !totpCodePattern.MatchString(code) {
+		return s.consumeRecoveryCode(userID, code)
+	}
+
+	secret, err := s.cipher.Decrypt(credential.SecretEncrypted)
+	if err != nil {
+		return err
+	}
+
+	step, ok := totp.Validate(secret, code, time.Now(), totpSkew)
+	if !ok {
+		return ErrInvalidMFACode
+	}
+
+	fresh, err := s.mfaRepo.MarkStepUsed(credential.ID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+func (s *mfaService) consumeRecoveryCode(userID uint, code string) error {
+	consumed, err := s.mfaRepo.ConsumeRecoveryCode(userID, hashSecret(normalizeRecoveryCode(code)), time.Now())
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+// getCredential returns the user's credential, or nil if there is none.
+func (s *mfaService) getCredential(userID uint) (*model.MFACredential, error) {
+	credential, err := s.mfaRepo.GetByUserID(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return credential, nil
+}
+
+// newRecoveryCodes generates and stores a new set of recovery codes in the
+// form "abcde-fghij" and returns them. Only their hashes are kept.
+func (s *mfaService) newRecoveryCodes(userID uint) (*model.MFARecoveryCodesResponse, error) {
+	codes := make([]string, 0, s.conf.RecoveryCodes)
+	hashes := make([]string, 0, s.conf.RecoveryCodes)
+
+	for i := 0; i < s.conf.RecoveryCodes; i++ {
+		buf := make([]byte, 10)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		for j := range buf {
+			buf[j] = recoveryCodeAlphabet[int(buf[j])%len(recoveryCodeAlphabet)]
+		}
+
+		code := string(buf[:5]) + "-" + string(buf[5:])
+		codes = append(codes, code)
+		hashes = append(hashes, hashSecret(normalizeRecoveryCode(code)))
+	}
+
+	if err := s.mfaRepo.ReplaceRecoveryCodes(userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return &model.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// normalizeRecoveryCode makes recovery codes case-insensitive and ignores
+// separators.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/totp"
+	"gorm.io/gorm"
+)
+
+// newMFACredential returns a credential for user 1 with a fresh secret,
+// encrypted the way the service stores it.
+func (s *ServiceTestSuite) newMFACredential(confirmed bool) (*model.MFACredential, []byte) {
+	secret, _ := totp.GenerateSecret()
+	encrypted, _ := s.mfaService.(*mfaService).cipher.Encrypt(secret)
+
+	credential := &model.MFACredential{ID: 5, UserID: 1, SecretEncrypted: encrypted}
+	if confirmed {
+		confirmedAt := time.Now()
+		credential.ConfirmedAt = &confirmedAt
+	}
+	return credential, secret
+}
+
+func (s *ServiceTestSuite) TestNewMFAService_RequiresEncryptionKey() {
+	service, err := NewMFAService(s.userRepo, s.mfaRepo, &config.Config{})
+
+	assert.Error(s.T(), err)
+	assert.Nil(s.T(), service)
+}
+
+func (s *ServiceTestSuite) TestEnroll_Success() {
+	user := s.newUserWithPassword("password123")
+	var stored *model.MFACredential
+
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	s.mfaRepo.On("Replace", mock.AnythingOfType("*model.MFACredential")).Return(nil).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*model.MFACredential)
+	})
+
+	// Execute
+	result, err := s.mfaService.Enroll(s.ctx, 1)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), result.Secret)
+	assert.False(s.T(), stored.IsConfirmed())
+
+	// The secret is stored encrypted
+	assert.NotContains(s.T(), stored.SecretEncrypted, result.Secret)
+	secret, err := s.mfaService.(*mfaService).cipher.Decrypt(stored.SecretEncrypted)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), result.Secret, totp.EncodeSecret(secret))
+
+	uri, err := url.Parse(result.OTPAuthURI)
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "otpauth", uri.Scheme)
+	assert.Equal(s.T(), result.Secret, uri.Query().Get("secret"))
+	assert.Equal(s.T(), "test", uri.Query().Get("issuer"))
+}
+
+func (s *ServiceTestSuite) TestEnroll_AlreadyEnabled() {
+	credential, _ := s.newMFACredential(true)
+
+	s.userRepo.On("GetByID", uint(1)).Return(s.newUserWithPassword("password123"), nil)
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(credential, nil)
+
+	// Execute
+	result, err := s.mfaService.Enroll(s.ctx, 1)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrMFAAlreadyEnabled)
+	assert.Nil(s.T(), result)
+	s.mfaRepo.AssertNotCalled(s.T(), "Replace", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestEnroll_UserNotFound() {
+	s.userRepo.On("GetByID", uint(1)).Return(nil, errors.New("not found"))
+
+	// Execute
+	result, err := s.mfaService.Enroll(s.ctx, 1)
+
+	// Assert
+	assert.Error(s.T(), err)
+	assert.Nil(s.T(), result)
+}
+
+func (s *ServiceTestSuite) TestConfirm_Success() {
+	credential, secret := s.newMFACredential(false)
+	var storedHashes []string
+
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(credential, nil)
+	s.mfaRepo.On("Confirm", uint(5), mock.AnythingOfType("time.Time"), totp.Step(time.Now())).Return(nil)
+	s.mfaRepo.On("ReplaceRecoveryCodes", uint(1), mock.AnythingOfType("[]string")).Return(nil).Run(func(args mock.Arguments) {
+		storedHashes = args.Get(1).([]string)
+	})
+
+	// Execute
+	result, err := s.mfaService.Confirm(1, &model.MFACodeRequest{Code: totp.Code(secret, totp.Step(time.Now()))})
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result.RecoveryCodes, 3)
+	assert.Regexp(s.T(), `^[a-z2-7]{5}-[a-z2-7]{5}$`, result.RecoveryCodes[0])
+
+	// Only hashes of the codes are stored
+	assert.Len(s.T(), storedHashes, 3)
+	for i, code := range result.RecoveryCodes {
+		assert.Equal(s.T(), hashSecret(normalizeRecoveryCode(code)), storedHashes[i])
+	}
+	s.mfaRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestConfirm_WrongCode() {
+	credential, secret := s.newMFACredential(false)
+	wrong := totp.Code(secret, totp.Step(time.Now())+10)
+
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(credential, nil)
+
+	// Execute
+	result, err := s.mfaService.Confirm(1, &model.MFACodeRequest{Code: wrong})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidMFACode)
+	assert.Nil(s.T(), result)
+	s.mfaRepo.AssertNotCalled(s.T(), "Confirm", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestConfirm_NotEnrolled() {
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	_, err := s.mfaService.Confirm(1, &model.MFACodeRequest{Code: "123456"})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrMFANotEnabled)
+}
+
+func (s *ServiceTestSuite) TestVerifyCode_TOTP() {
+	credential, secret := s.newMFACredential(true)
+	step := totp.Step(time.Now())
+
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(credential, nil)
+	s.mfaRepo.On("MarkStepUsed", uint(5), step).Return(true, nil)
+
+	// Execute
+	err := s.mfaService.VerifyCode(1, totp.Code(secret, step))
+
+	// Assert
+	assert.NoError(s.T(), err)
+	s.mfaRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestVerifyCode_ReplayedTOTP() {
+	credential, secret := s.newMFACredential(true)
+	step := totp.Step(time.Now())
+
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(credential, nil)
+	s.mfaRepo.On("MarkStepUsed", uint(5), step).Return(false, nil)
+
+	// Execute
+	err := s.mfaService.VerifyCode(1, totp.Code(secret, step))
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidMFACode)
+}
+
+func (s *ServiceTestSuite) TestVerifyCode_RecoveryCode() {
+	credential, _ := s.newMFACredential(true)
+
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(credential, nil)
+	s.mfaRepo.On("ConsumeRecoveryCode", uint(1), hashSecret("abcdefghij"), mock.AnythingOfType("time.Time")).Return(true, nil)
+
+	// Execute: case and separators do not matter
+	err := s.mfaService.VerifyCode(1, " ABCDE-fghij ")
+
+	// Assert
+	assert.NoError(s.T(), err)
+	s.mfaRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestVerifyCode_UnknownRecoveryCode() {
+	credential, _ := s.newMFACredential(true)
+
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(credential, nil)
+	s.mfaRepo.On("ConsumeRecoveryCode", uint(1), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	// Execute
+	err := s.mfaService.VerifyCode(1, "abcde-fghij")
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidMFACode)
+}
+
+func (s *ServiceTestSuite) TestVerifyCode_UnconfirmedCredential() {
+	credential, secret := s.newMFACredential(false)
+
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(credential, nil)
+
+	// Execute
+	err := s.mfaService.VerifyCode(1, totp.Code(secret, totp.Step(time.Now())))
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrMFANotEnabled)
+}
+
+func (s *ServiceTestSuite) TestIsEnabled_RepositoryError() {
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(nil, errors.New("database error"))
+
+	// Execute
+	enabled, err := s.mfaService.IsEnabled(1)
+
+	// Assert: errors are not mistaken for "no second factor"
+	assert.Error(s.T(), err)
+	assert.False(s.T(), enabled)
+}
+
+func (s *ServiceTestSuite) TestIsRequired() {
+	assert.True(s.T(), s.mfaService.IsRequired(&model.User{Role: model.RoleAdmin}))
+	assert.False(s.T(), s.mfaService.IsRequired(&model.User{Role: model.RoleUser}))
+}
+
+func (s *ServiceTestSuite) TestDisable_Success() {
+	credential, secret := s.newMFACredential(true)
+	step := totp.Step(time.Now())
+
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(credential, nil)
+	s.mfaRepo.On("MarkStepUsed", uint(5), step).Return(true, nil)
+	s.mfaRepo.On("DeleteForUser", uint(1)).Return(nil)
+
+	// Execute
+	err := s.mfaService.Disable(1, &model.MFACodeRequest{Code: totp.Code(secret, step)})
+
+	// Assert
+	assert.NoError(s.T(), err)
+	s.mfaRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestDisable_WrongCode() {
+	credential, secret := s.newMFACredential(true)
+
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(credential, nil)
+
+	// Execute
+	err := s.mfaService.Disable(1, &model.MFACodeRequest{Code: totp.Code(secret, totp.Step(time.Now())+10)})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidMFACode)
+	s.mfaRepo.AssertNotCalled(s.T(), "DeleteForUser", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestRegenerateRecoveryCodes_Success() {
+	credential, _ := s.newMFACredential(true)
+
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(credential, nil)
+	s.mfaRepo.On("ConsumeRecoveryCode", uint(1), mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(true, nil)
+	s.mfaRepo.On("ReplaceRecoveryCodes", uint(1), mock.AnythingOfType("[]string")).Return(nil)
+
+	// Execute
+	result, err := s.mfaService.RegenerateRecoveryCodes(1, &model.MFACodeRequest{Code: "abcde-fghij"})
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result.RecoveryCodes, 3)
+}
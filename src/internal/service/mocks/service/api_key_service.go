@@ -0,0 +1,185 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// MockAPIKeyService is an autogenerated mock type for the APIKeyService type
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: key
+func (_m *MockAPIKeyService) Authenticate(key string) (*model.Principal, error) {
+	ret := _m.Called(key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authenticate")
+	}
+
+	var r0 *model.Principal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.Principal, error)); ok {
+		return rf(key)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.Principal); ok {
+		r0 = rf(key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Principal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, caller, req
+func (_m *MockAPIKeyService) Create(ctx context.Context, caller *model.Principal, req *model.CreateAPIKeyRequest) (*model.CreateAPIKeyResponse, error) {
+	ret := _m.Called(ctx, caller, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *model.CreateAPIKeyResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Principal, *model.CreateAPIKeyRequest) (*model.CreateAPIKeyResponse, error)); ok {
+		return rf(ctx, caller, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Principal, *model.CreateAPIKeyRequest) *model.CreateAPIKeyResponse); ok {
+		r0 = rf(ctx, caller, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.CreateAPIKeyResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Principal, *model.CreateAPIKeyRequest) error); ok {
+		r1 = rf(ctx, caller, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListForService provides a mock function with given fields: ctx, caller, _a2
+func (_m *MockAPIKeyService) ListForService(ctx context.Context, caller *model.Principal, _a2 string) ([]*model.APIKeyResponse, error) {
+	ret := _m.Called(ctx, caller, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListForService")
+	}
+
+	var r0 []*model.APIKeyResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Principal, string) ([]*model.APIKeyResponse, error)); ok {
+		return rf(ctx, caller, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Principal, string) []*model.APIKeyResponse); ok {
+		r0 = rf(ctx, caller, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.APIKeyResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Principal, string) error); ok {
+		r1 = rf(ctx, caller, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListForUser provides a mock function with given fields: ctx, caller, userID
+func (_m *MockAPIKeyService) ListForUser(ctx context.Context, caller *model.Principal, userID uint) ([]*model.APIKeyResponse, error) {
+	ret := _m.Called(ctx, caller, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListForUser")
+	}
+
+	var r0 []*model.APIKeyResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Principal, uint) ([]*model.APIKeyResponse, error)); ok {
+		return rf(ctx, caller, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Principal, uint) []*model.APIKeyResponse); ok {
+		r0 = rf(ctx, caller, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.APIKeyResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Principal, uint) error); ok {
+		r1 = rf(ctx, caller, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revoke provides a mock function with given fields: ctx, caller, id
+func (_m *MockAPIKeyService) Revoke(ctx context.Context, caller *model.Principal, id uint) error {
+	ret := _m.Called(ctx, caller, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revoke")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Principal, uint) error); ok {
+		r0 = rf(ctx, caller, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeAllForUser provides a mock function with given fields: userID
+func (_m *MockAPIKeyService) RevokeAllForUser(userID uint) error {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAllForUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockAPIKeyService creates a new instance of MockAPIKeyService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAPIKeyService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAPIKeyService {
+	mock := &MockAPIKeyService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,109 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// MockAuditService is an autogenerated mock type for the AuditService type
+type MockAuditService struct {
+	mock.Mock
+}
+
+// Export provides a mock function with given fields: ctx, filter, format, w
+func (_m *MockAuditService) Export(ctx context.Context, filter *model.AuditFilter, format string, w io.Writer) error {
+	ret := _m.Called(ctx, filter, format, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Export")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.AuditFilter, string, io.Writer) error); ok {
+		r0 = rf(ctx, filter, format, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListEvents provides a mock function with given fields: ctx, filter, limit, offset
+func (_m *MockAuditService) ListEvents(ctx context.Context, filter *model.AuditFilter, limit int, offset int) ([]*model.AuditEventResponse, error) {
+	ret := _m.Called(ctx, filter, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListEvents")
+	}
+
+	var r0 []*model.AuditEventResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.AuditFilter, int, int) ([]*model.AuditEventResponse, error)); ok {
+		return rf(ctx, filter, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.AuditFilter, int, int) []*model.AuditEventResponse); ok {
+		r0 = rf(ctx, filter, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.AuditEventResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.AuditFilter, int, int) error); ok {
+		r1 = rf(ctx, filter, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Verify provides a mock function with given fields: ctx
+func (_m *MockAuditService) Verify(ctx context.Context) (*model.AuditVerificationResponse, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Verify")
+	}
+
+	var r0 *model.AuditVerificationResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*model.AuditVerificationResponse, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *model.AuditVerificationResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AuditVerificationResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockAuditService creates a new instance of MockAuditService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAuditService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAuditService {
+	mock := &MockAuditService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,149 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// MockAuthService is an autogenerated mock type for the AuthService type
+type MockAuthService struct {
+	mock.Mock
+}
+
+// CompleteExternalLogin provides a mock function with given fields: user, client
+func (_m *MockAuthService) CompleteExternalLogin(user *model.User, client *model.ClientInfo) (*model.LoginResponse, error) {
+	ret := _m.Called(user, client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteExternalLogin")
+	}
+
+	var r0 *model.LoginResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.User, *model.ClientInfo) (*model.LoginResponse, error)); ok {
+		return rf(user, client)
+	}
+	if rf, ok := ret.Get(0).(func(*model.User, *model.ClientInfo) *model.LoginResponse); ok {
+		r0 = rf(user, client)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LoginResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.User, *model.ClientInfo) error); ok {
+		r1 = rf(user, client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompleteMFALogin provides a mock function with given fields: ctx, req, client
+func (_m *MockAuthService) CompleteMFALogin(ctx context.Context, req *model.MFALoginRequest, client *model.ClientInfo) (*model.LoginResponse, error) {
+	ret := _m.Called(ctx, req, client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteMFALogin")
+	}
+
+	var r0 *model.LoginResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.MFALoginRequest, *model.ClientInfo) (*model.LoginResponse, error)); ok {
+		return rf(ctx, req, client)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.MFALoginRequest, *model.ClientInfo) *model.LoginResponse); ok {
+		r0 = rf(ctx, req, client)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LoginResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.MFALoginRequest, *model.ClientInfo) error); ok {
+		r1 = rf(ctx, req, client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompletePasskeyLogin provides a mock function with given fields: user, client
+func (_m *MockAuthService) CompletePasskeyLogin(user *model.User, client *model.ClientInfo) (*model.LoginResponse, error) {
+	ret := _m.Called(user, client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompletePasskeyLogin")
+	}
+
+	var r0 *model.LoginResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.User, *model.ClientInfo) (*model.LoginResponse, error)); ok {
+		return rf(user, client)
+	}
+	if rf, ok := ret.Get(0).(func(*model.User, *model.ClientInfo) *model.LoginResponse); ok {
+		r0 = rf(user, client)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LoginResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.User, *model.ClientInfo) error); ok {
+		r1 = rf(user, client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Login provides a mock function with given fields: ctx, req, client
+func (_m *MockAuthService) Login(ctx context.Context, req *model.LoginRequest, client *model.ClientInfo) (*model.LoginResponse, error) {
+	ret := _m.Called(ctx, req, client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Login")
+	}
+
+	var r0 *model.LoginResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.LoginRequest, *model.ClientInfo) (*model.LoginResponse, error)); ok {
+		return rf(ctx, req, client)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.LoginRequest, *model.ClientInfo) *model.LoginResponse); ok {
+		r0 = rf(ctx, req, client)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LoginResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.LoginRequest, *model.ClientInfo) error); ok {
+		r1 = rf(ctx, req, client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockAuthService creates a new instance of MockAuthService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAuthService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAuthService {
+	mock := &MockAuthService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,89 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// MockGatewayTicketService is an autogenerated mock type for the GatewayTicketService type
+type MockGatewayTicketService struct {
+	mock.Mock
+}
+
+// IssueTicket provides a mock function with given fields: ctx, userID
+func (_m *MockGatewayTicketService) IssueTicket(ctx context.Context, userID uint) (*model.GatewayTicketResponse, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueTicket")
+	}
+
+	var r0 *model.GatewayTicketResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*model.GatewayTicketResponse, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *model.GatewayTicketResponse); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.GatewayTicketResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RedeemTicket provides a mock function with given fields: ctx, ticket
+func (_m *MockGatewayTicketService) RedeemTicket(ctx context.Context, ticket string) (*model.GatewayTicket, error) {
+	ret := _m.Called(ctx, ticket)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RedeemTicket")
+	}
+
+	var r0 *model.GatewayTicket
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.GatewayTicket, error)); ok {
+		return rf(ctx, ticket)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.GatewayTicket); ok {
+		r0 = rf(ctx, ticket)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.GatewayTicket)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, ticket)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockGatewayTicketService creates a new instance of MockGatewayTicketService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockGatewayTicketService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockGatewayTicketService {
+	mock := &MockGatewayTicketService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,333 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// MockGroupService is an autogenerated mock type for the GroupService type
+type MockGroupService struct {
+	mock.Mock
+}
+
+// AddMembers provides a mock function with given fields: ctx, id, req
+func (_m *MockGroupService) AddMembers(ctx context.Context, id uint, req *model.GroupMembersRequest) ([]*model.GroupMemberResponse, error) {
+	ret := _m.Called(ctx, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddMembers")
+	}
+
+	var r0 []*model.GroupMemberResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *model.GroupMembersRequest) ([]*model.GroupMemberResponse, error)); ok {
+		return rf(ctx, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *model.GroupMembersRequest) []*model.GroupMemberResponse); ok {
+		r0 = rf(ctx, id, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.GroupMemberResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, *model.GroupMembersRequest) error); ok {
+		r1 = rf(ctx, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateGroup provides a mock function with given fields: ctx, req
+func (_m *MockGroupService) CreateGroup(ctx context.Context, req *model.CreateGroupRequest) (*model.GroupResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateGroup")
+	}
+
+	var r0 *model.GroupResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.CreateGroupRequest) (*model.GroupResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.CreateGroupRequest) *model.GroupResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.GroupResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.CreateGroupRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteGroup provides a mock function with given fields: ctx, id
+func (_m *MockGroupService) DeleteGroup(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteGroup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetGroup provides a mock function with given fields: ctx, id
+func (_m *MockGroupService) GetGroup(ctx context.Context, id uint) (*model.GroupResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetGroup")
+	}
+
+	var r0 *model.GroupResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*model.GroupResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *model.GroupResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.GroupResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// HasPermission provides a mock function with given fields: ctx, userID, permission
+func (_m *MockGroupService) HasPermission(ctx context.Context, userID uint, permission string) (bool, error) {
+	ret := _m.Called(ctx, userID, permission)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HasPermission")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) (bool, error)); ok {
+		return rf(ctx, userID, permission)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) bool); ok {
+		r0 = rf(ctx, userID, permission)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string) error); ok {
+		r1 = rf(ctx, userID, permission)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListGroups provides a mock function with given fields: ctx
+func (_m *MockGroupService) ListGroups(ctx context.Context) ([]*model.GroupResponse, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListGroups")
+	}
+
+	var r0 []*model.GroupResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.GroupResponse, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.GroupResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.GroupResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListMembers provides a mock function with given fields: ctx, id
+func (_m *MockGroupService) ListMembers(ctx context.Context, id uint) ([]*model.GroupMemberResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListMembers")
+	}
+
+	var r0 []*model.GroupMemberResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*model.GroupMemberResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*model.GroupMemberResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.GroupMemberResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUserGroups provides a mock function with given fields: ctx, userID
+func (_m *MockGroupService) ListUserGroups(ctx context.Context, userID uint) (*model.UserGroupsResponse, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUserGroups")
+	}
+
+	var r0 *model.UserGroupsResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*model.UserGroupsResponse, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *model.UserGroupsResponse); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UserGroupsResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveMembers provides a mock function with given fields: ctx, id, req
+func (_m *MockGroupService) RemoveMembers(ctx context.Context, id uint, req *model.GroupMembersRequest) error {
+	ret := _m.Called(ctx, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveMembers")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *model.GroupMembersRequest) error); ok {
+		r0 = rf(ctx, id, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPermissions provides a mock function with given fields: ctx, id, req
+func (_m *MockGroupService) SetPermissions(ctx context.Context, id uint, req *model.GroupPermissionsRequest) (*model.GroupResponse, error) {
+	ret := _m.Called(ctx, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPermissions")
+	}
+
+	var r0 *model.GroupResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *model.GroupPermissionsRequest) (*model.GroupResponse, error)); ok {
+		return rf(ctx, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *model.GroupPermissionsRequest) *model.GroupResponse); ok {
+		r0 = rf(ctx, id, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.GroupResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, *model.GroupPermissionsRequest) error); ok {
+		r1 = rf(ctx, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateGroup provides a mock function with given fields: ctx, id, req
+func (_m *MockGroupService) UpdateGroup(ctx context.Context, id uint, req *model.UpdateGroupRequest) (*model.GroupResponse, error) {
+	ret := _m.Called(ctx, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateGroup")
+	}
+
+	var r0 *model.GroupResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *model.UpdateGroupRequest) (*model.GroupResponse, error)); ok {
+		return rf(ctx, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *model.UpdateGroupRequest) *model.GroupResponse); ok {
+		r0 = rf(ctx, id, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.GroupResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, *model.UpdateGroupRequest) error); ok {
+		r1 = rf(ctx, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockGroupService creates a new instance of MockGroupService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockGroupService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockGroupService {
+	mock := &MockGroupService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
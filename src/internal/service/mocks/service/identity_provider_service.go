@@ -0,0 +1,217 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// MockIdentityProviderService is an autogenerated mock type for the IdentityProviderService type
+type MockIdentityProviderService struct {
+	mock.Mock
+}
+
+// Authorize provides a mock function with given fields: caller, req
+func (_m *MockIdentityProviderService) Authorize(caller *model.Principal, req *model.AuthorizeRequest) (*model.AuthorizeResponse, error) {
+	ret := _m.Called(caller, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authorize")
+	}
+
+	var r0 *model.AuthorizeResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.Principal, *model.AuthorizeRequest) (*model.AuthorizeResponse, error)); ok {
+		return rf(caller, req)
+	}
+	if rf, ok := ret.Get(0).(func(*model.Principal, *model.AuthorizeRequest) *model.AuthorizeResponse); ok {
+		r0 = rf(caller, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.AuthorizeResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.Principal, *model.AuthorizeRequest) error); ok {
+		r1 = rf(caller, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Discovery provides a mock function with no fields
+func (_m *MockIdentityProviderService) Discovery() *model.OpenIDConfiguration {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Discovery")
+	}
+
+	var r0 *model.OpenIDConfiguration
+	if rf, ok := ret.Get(0).(func() *model.OpenIDConfiguration); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OpenIDConfiguration)
+		}
+	}
+
+	return r0
+}
+
+// JWKS provides a mock function with no fields
+func (_m *MockIdentityProviderService) JWKS() (*model.JSONWebKeySet, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for JWKS")
+	}
+
+	var r0 *model.JSONWebKeySet
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (*model.JSONWebKeySet, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() *model.JSONWebKeySet); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.JSONWebKeySet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListConsents provides a mock function with given fields: ctx, userID
+func (_m *MockIdentityProviderService) ListConsents(ctx context.Context, userID uint) ([]*model.OAuthConsentResponse, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListConsents")
+	}
+
+	var r0 []*model.OAuthConsentResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*model.OAuthConsentResponse, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*model.OAuthConsentResponse); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.OAuthConsentResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeConsent provides a mock function with given fields: userID, clientID
+func (_m *MockIdentityProviderService) RevokeConsent(userID uint, clientID string) error {
+	ret := _m.Called(userID, clientID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeConsent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, string) error); ok {
+		r0 = rf(userID, clientID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Token provides a mock function with given fields: ctx, req
+func (_m *MockIdentityProviderService) Token(ctx context.Context, req *model.TokenRequest) (*model.TokenResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Token")
+	}
+
+	var r0 *model.TokenResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.TokenRequest) (*model.TokenResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.TokenRequest) *model.TokenResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.TokenResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.TokenRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserInfo provides a mock function with given fields: ctx, accessToken
+func (_m *MockIdentityProviderService) UserInfo(ctx context.Context, accessToken string) (*model.UserInfo, error) {
+	ret := _m.Called(ctx, accessToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UserInfo")
+	}
+
+	var r0 *model.UserInfo
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.UserInfo, error)); ok {
+		return rf(ctx, accessToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.UserInfo); ok {
+		r0 = rf(ctx, accessToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UserInfo)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, accessToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockIdentityProviderService creates a new instance of MockIdentityProviderService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIdentityProviderService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIdentityProviderService {
+	mock := &MockIdentityProviderService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
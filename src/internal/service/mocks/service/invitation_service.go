@@ -0,0 +1,167 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// MockInvitationService is an autogenerated mock type for the InvitationService type
+type MockInvitationService struct {
+	mock.Mock
+}
+
+// AcceptInvitation provides a mock function with given fields: ctx, req
+func (_m *MockInvitationService) AcceptInvitation(ctx context.Context, req *model.AcceptInvitationRequest) (*model.UserResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AcceptInvitation")
+	}
+
+	var r0 *model.UserResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.AcceptInvitationRequest) (*model.UserResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.AcceptInvitationRequest) *model.UserResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UserResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.AcceptInvitationRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateInvitation provides a mock function with given fields: ctx, principal, req
+func (_m *MockInvitationService) CreateInvitation(ctx context.Context, principal *model.Principal, req *model.CreateInvitationRequest) (*model.InvitationResponse, error) {
+	ret := _m.Called(ctx, principal, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateInvitation")
+	}
+
+	var r0 *model.InvitationResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Principal, *model.CreateInvitationRequest) (*model.InvitationResponse, error)); ok {
+		return rf(ctx, principal, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Principal, *model.CreateInvitationRequest) *model.InvitationResponse); ok {
+		r0 = rf(ctx, principal, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.InvitationResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Principal, *model.CreateInvitationRequest) error); ok {
+		r1 = rf(ctx, principal, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListInvitations provides a mock function with given fields: ctx, limit, offset
+func (_m *MockInvitationService) ListInvitations(ctx context.Context, limit int, offset int) ([]*model.InvitationResponse, error) {
+	ret := _m.Called(ctx, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListInvitations")
+	}
+
+	var r0 []*model.InvitationResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]*model.InvitationResponse, error)); ok {
+		return rf(ctx, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*model.InvitationResponse); ok {
+		r0 = rf(ctx, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.InvitationResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ResendInvitation provides a mock function with given fields: ctx, id
+func (_m *MockInvitationService) ResendInvitation(ctx context.Context, id uint) (*model.InvitationResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResendInvitation")
+	}
+
+	var r0 *model.InvitationResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*model.InvitationResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *model.InvitationResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.InvitationResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeInvitation provides a mock function with given fields: ctx, id
+func (_m *MockInvitationService) RevokeInvitation(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeInvitation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockInvitationService creates a new instance of MockInvitationService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockInvitationService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockInvitationService {
+	mock := &MockInvitationService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,186 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	jobs "github.com/weeranieb/go-kit-base/src/internal/jobs"
+
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// MockJobService is an autogenerated mock type for the JobService type
+type MockJobService struct {
+	mock.Mock
+}
+
+// CancelJob provides a mock function with given fields: ctx, id
+func (_m *MockJobService) CancelJob(ctx context.Context, id uint) (*model.JobResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelJob")
+	}
+
+	var r0 *model.JobResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*model.JobResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *model.JobResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.JobResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Enqueue provides a mock function with given fields: ctx, job, opts
+func (_m *MockJobService) Enqueue(ctx context.Context, job jobs.Job, opts ...jobs.Option) (bool, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, job)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enqueue")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, jobs.Job, ...jobs.Option) (bool, error)); ok {
+		return rf(ctx, job, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, jobs.Job, ...jobs.Option) bool); ok {
+		r0 = rf(ctx, job, opts...)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, jobs.Job, ...jobs.Option) error); ok {
+		r1 = rf(ctx, job, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetJob provides a mock function with given fields: ctx, id
+func (_m *MockJobService) GetJob(ctx context.Context, id uint) (*model.JobResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetJob")
+	}
+
+	var r0 *model.JobResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*model.JobResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *model.JobResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.JobResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListJobs provides a mock function with given fields: ctx, filter, limit, offset
+func (_m *MockJobService) ListJobs(ctx context.Context, filter *model.JobFilter, limit int, offset int) ([]*model.JobResponse, error) {
+	ret := _m.Called(ctx, filter, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListJobs")
+	}
+
+	var r0 []*model.JobResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.JobFilter, int, int) ([]*model.JobResponse, error)); ok {
+		return rf(ctx, filter, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.JobFilter, int, int) []*model.JobResponse); ok {
+		r0 = rf(ctx, filter, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.JobResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.JobFilter, int, int) error); ok {
+		r1 = rf(ctx, filter, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RetryJob provides a mock function with given fields: ctx, id
+func (_m *MockJobService) RetryJob(ctx context.Context, id uint) (*model.JobResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RetryJob")
+	}
+
+	var r0 *model.JobResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*model.JobResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *model.JobResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.JobResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockJobService creates a new instance of MockJobService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockJobService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockJobService {
+	mock := &MockJobService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
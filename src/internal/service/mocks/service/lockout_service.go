@@ -0,0 +1,131 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// MockLockoutService is an autogenerated mock type for the LockoutService type
+type MockLockoutService struct {
+	mock.Mock
+}
+
+// Check provides a mock function with given fields: user, login, client
+func (_m *MockLockoutService) Check(user *model.User, login string, client *model.ClientInfo) error {
+	ret := _m.Called(user, login, client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Check")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.User, string, *model.ClientInfo) error); ok {
+		r0 = rf(user, login, client)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ListLoginAttempts provides a mock function with given fields: ctx, id, limit, offset
+func (_m *MockLockoutService) ListLoginAttempts(ctx context.Context, id uint, limit int, offset int) ([]*model.LoginAttemptResponse, error) {
+	ret := _m.Called(ctx, id, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListLoginAttempts")
+	}
+
+	var r0 []*model.LoginAttemptResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int, int) ([]*model.LoginAttemptResponse, error)); ok {
+		return rf(ctx, id, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int, int) []*model.LoginAttemptResponse); ok {
+		r0 = rf(ctx, id, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.LoginAttemptResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, int, int) error); ok {
+		r1 = rf(ctx, id, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordFailure provides a mock function with given fields: user, login, client, reason
+func (_m *MockLockoutService) RecordFailure(user *model.User, login string, client *model.ClientInfo, reason string) error {
+	ret := _m.Called(user, login, client, reason)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordFailure")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.User, string, *model.ClientInfo, string) error); ok {
+		r0 = rf(user, login, client, reason)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RecordSuccess provides a mock function with given fields: user, login, client
+func (_m *MockLockoutService) RecordSuccess(user *model.User, login string, client *model.ClientInfo) error {
+	ret := _m.Called(user, login, client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordSuccess")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*model.User, string, *model.ClientInfo) error); ok {
+		r0 = rf(user, login, client)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Unlock provides a mock function with given fields: ctx, id
+func (_m *MockLockoutService) Unlock(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unlock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockLockoutService creates a new instance of MockLockoutService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockLockoutService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockLockoutService {
+	mock := &MockLockoutService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,77 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// MockMagicLinkService is an autogenerated mock type for the MagicLinkService type
+type MockMagicLinkService struct {
+	mock.Mock
+}
+
+// Redeem provides a mock function with given fields: ctx, req, client
+func (_m *MockMagicLinkService) Redeem(ctx context.Context, req *model.RedeemMagicLinkRequest, client *model.ClientInfo) (*model.LoginResponse, error) {
+	ret := _m.Called(ctx, req, client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Redeem")
+	}
+
+	var r0 *model.LoginResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.RedeemMagicLinkRequest, *model.ClientInfo) (*model.LoginResponse, error)); ok {
+		return rf(ctx, req, client)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.RedeemMagicLinkRequest, *model.ClientInfo) *model.LoginResponse); ok {
+		r0 = rf(ctx, req, client)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LoginResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.RedeemMagicLinkRequest, *model.ClientInfo) error); ok {
+		r1 = rf(ctx, req, client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SendLink provides a mock function with given fields: ctx, req
+func (_m *MockMagicLinkService) SendLink(ctx context.Context, req *model.MagicLinkRequest) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendLink")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.MagicLinkRequest) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockMagicLinkService creates a new instance of MockMagicLinkService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMagicLinkService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMagicLinkService {
+	mock := &MockMagicLinkService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,201 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// MockMFAService is an autogenerated mock type for the MFAService type
+type MockMFAService struct {
+	mock.Mock
+}
+
+// Confirm provides a mock function with given fields: userID, req
+func (_m *MockMFAService) Confirm(userID uint, req *model.MFACodeRequest) (*model.MFARecoveryCodesResponse, error) {
+	ret := _m.Called(userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Confirm")
+	}
+
+	var r0 *model.MFARecoveryCodesResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, *model.MFACodeRequest) (*model.MFARecoveryCodesResponse, error)); ok {
+		return rf(userID, req)
+	}
+	if rf, ok := ret.Get(0).(func(uint, *model.MFACodeRequest) *model.MFARecoveryCodesResponse); ok {
+		r0 = rf(userID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.MFARecoveryCodesResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, *model.MFACodeRequest) error); ok {
+		r1 = rf(userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Disable provides a mock function with given fields: userID, req
+func (_m *MockMFAService) Disable(userID uint, req *model.MFACodeRequest) error {
+	ret := _m.Called(userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Disable")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, *model.MFACodeRequest) error); ok {
+		r0 = rf(userID, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Enroll provides a mock function with given fields: ctx, userID
+func (_m *MockMFAService) Enroll(ctx context.Context, userID uint) (*model.MFAEnrollResponse, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enroll")
+	}
+
+	var r0 *model.MFAEnrollResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*model.MFAEnrollResponse, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *model.MFAEnrollResponse); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.MFAEnrollResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsEnabled provides a mock function with given fields: userID
+func (_m *MockMFAService) IsEnabled(userID uint) (bool, error) {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsEnabled")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint) (bool, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(uint) bool); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(uint) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsRequired provides a mock function with given fields: user
+func (_m *MockMFAService) IsRequired(user *model.User) bool {
+	ret := _m.Called(user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsRequired")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(*model.User) bool); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// RegenerateRecoveryCodes provides a mock function with given fields: userID, req
+func (_m *MockMFAService) RegenerateRecoveryCodes(userID uint, req *model.MFACodeRequest) (*model.MFARecoveryCodesResponse, error) {
+	ret := _m.Called(userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegenerateRecoveryCodes")
+	}
+
+	var r0 *model.MFARecoveryCodesResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, *model.MFACodeRequest) (*model.MFARecoveryCodesResponse, error)); ok {
+		return rf(userID, req)
+	}
+	if rf, ok := ret.Get(0).(func(uint, *model.MFACodeRequest) *model.MFARecoveryCodesResponse); ok {
+		r0 = rf(userID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.MFARecoveryCodesResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, *model.MFACodeRequest) error); ok {
+		r1 = rf(userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyCode provides a mock function with given fields: userID, code
+func (_m *MockMFAService) VerifyCode(userID uint, code string) error {
+	ret := _m.Called(userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, string) error); ok {
+		r0 = rf(userID, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockMFAService creates a new instance of MockMFAService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMFAService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMFAService {
+	mock := &MockMFAService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
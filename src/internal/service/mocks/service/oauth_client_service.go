@@ -0,0 +1,107 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// MockOAuthClientService is an autogenerated mock type for the OAuthClientService type
+type MockOAuthClientService struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, caller, req
+func (_m *MockOAuthClientService) Create(ctx context.Context, caller *model.Principal, req *model.CreateOAuthClientRequest) (*model.CreateOAuthClientResponse, error) {
+	ret := _m.Called(ctx, caller, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *model.CreateOAuthClientResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Principal, *model.CreateOAuthClientRequest) (*model.CreateOAuthClientResponse, error)); ok {
+		return rf(ctx, caller, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Principal, *model.CreateOAuthClientRequest) *model.CreateOAuthClientResponse); ok {
+		r0 = rf(ctx, caller, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.CreateOAuthClientResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Principal, *model.CreateOAuthClientRequest) error); ok {
+		r1 = rf(ctx, caller, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, caller
+func (_m *MockOAuthClientService) List(ctx context.Context, caller *model.Principal) ([]*model.OAuthClientResponse, error) {
+	ret := _m.Called(ctx, caller)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []*model.OAuthClientResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Principal) ([]*model.OAuthClientResponse, error)); ok {
+		return rf(ctx, caller)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Principal) []*model.OAuthClientResponse); ok {
+		r0 = rf(ctx, caller)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.OAuthClientResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Principal) error); ok {
+		r1 = rf(ctx, caller)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revoke provides a mock function with given fields: ctx, caller, id
+func (_m *MockOAuthClientService) Revoke(ctx context.Context, caller *model.Principal, id uint) error {
+	ret := _m.Called(ctx, caller, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revoke")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Principal, uint) error); ok {
+		r0 = rf(ctx, caller, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockOAuthClientService creates a new instance of MockOAuthClientService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOAuthClientService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOAuthClientService {
+	mock := &MockOAuthClientService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,139 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// MockOIDCLoginService is an autogenerated mock type for the OIDCLoginService type
+type MockOIDCLoginService struct {
+	mock.Mock
+}
+
+// Callback provides a mock function with given fields: ctx, provider, req, client
+func (_m *MockOIDCLoginService) Callback(ctx context.Context, provider string, req *model.OIDCCallbackRequest, client *model.ClientInfo) (*model.LoginResponse, error) {
+	ret := _m.Called(ctx, provider, req, client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Callback")
+	}
+
+	var r0 *model.LoginResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *model.OIDCCallbackRequest, *model.ClientInfo) (*model.LoginResponse, error)); ok {
+		return rf(ctx, provider, req, client)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, *model.OIDCCallbackRequest, *model.ClientInfo) *model.LoginResponse); ok {
+		r0 = rf(ctx, provider, req, client)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LoginResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, *model.OIDCCallbackRequest, *model.ClientInfo) error); ok {
+		r1 = rf(ctx, provider, req, client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListIdentities provides a mock function with given fields: ctx, userID
+func (_m *MockOIDCLoginService) ListIdentities(ctx context.Context, userID uint) ([]*model.ExternalIdentityResponse, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListIdentities")
+	}
+
+	var r0 []*model.ExternalIdentityResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*model.ExternalIdentityResponse, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*model.ExternalIdentityResponse); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.ExternalIdentityResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Providers provides a mock function with no fields
+func (_m *MockOIDCLoginService) Providers() []string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Providers")
+	}
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// Start provides a mock function with given fields: provider
+func (_m *MockOIDCLoginService) Start(provider string) (*model.OIDCAuthorization, error) {
+	ret := _m.Called(provider)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Start")
+	}
+
+	var r0 *model.OIDCAuthorization
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.OIDCAuthorization, error)); ok {
+		return rf(provider)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.OIDCAuthorization); ok {
+		r0 = rf(provider)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OIDCAuthorization)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(provider)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockOIDCLoginService creates a new instance of MockOIDCLoginService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOIDCLoginService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOIDCLoginService {
+	mock := &MockOIDCLoginService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
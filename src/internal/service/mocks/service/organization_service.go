@@ -0,0 +1,255 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// MockOrganizationService is an autogenerated mock type for the OrganizationService type
+type MockOrganizationService struct {
+	mock.Mock
+}
+
+// AddMember provides a mock function with given fields: ctx, principal, organizationID, req
+func (_m *MockOrganizationService) AddMember(ctx context.Context, principal *model.Principal, organizationID uint, req *model.AddMemberRequest) (*model.MembershipResponse, error) {
+	ret := _m.Called(ctx, principal, organizationID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddMember")
+	}
+
+	var r0 *model.MembershipResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Principal, uint, *model.AddMemberRequest) (*model.MembershipResponse, error)); ok {
+		return rf(ctx, principal, organizationID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Principal, uint, *model.AddMemberRequest) *model.MembershipResponse); ok {
+		r0 = rf(ctx, principal, organizationID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.MembershipResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Principal, uint, *model.AddMemberRequest) error); ok {
+		r1 = rf(ctx, principal, organizationID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: principal, req
+func (_m *MockOrganizationService) Create(principal *model.Principal, req *model.CreateOrganizationRequest) (*model.OrganizationResponse, error) {
+	ret := _m.Called(principal, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *model.OrganizationResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.Principal, *model.CreateOrganizationRequest) (*model.OrganizationResponse, error)); ok {
+		return rf(principal, req)
+	}
+	if rf, ok := ret.Get(0).(func(*model.Principal, *model.CreateOrganizationRequest) *model.OrganizationResponse); ok {
+		r0 = rf(principal, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.OrganizationResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.Principal, *model.CreateOrganizationRequest) error); ok {
+		r1 = rf(principal, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsMember provides a mock function with given fields: ctx, organizationID, userID
+func (_m *MockOrganizationService) IsMember(ctx context.Context, organizationID uint, userID uint) (bool, error) {
+	ret := _m.Called(ctx, organizationID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsMember")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) (bool, error)); ok {
+		return rf(ctx, organizationID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) bool); ok {
+		r0 = rf(ctx, organizationID, userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uint) error); ok {
+		r1 = rf(ctx, organizationID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListForUser provides a mock function with given fields: ctx, userID
+func (_m *MockOrganizationService) ListForUser(ctx context.Context, userID uint) ([]*model.OrganizationResponse, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListForUser")
+	}
+
+	var r0 []*model.OrganizationResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*model.OrganizationResponse, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*model.OrganizationResponse); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.OrganizationResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListMembers provides a mock function with given fields: ctx, principal, organizationID
+func (_m *MockOrganizationService) ListMembers(ctx context.Context, principal *model.Principal, organizationID uint) ([]*model.MembershipResponse, error) {
+	ret := _m.Called(ctx, principal, organizationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListMembers")
+	}
+
+	var r0 []*model.MembershipResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Principal, uint) ([]*model.MembershipResponse, error)); ok {
+		return rf(ctx, principal, organizationID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Principal, uint) []*model.MembershipResponse); ok {
+		r0 = rf(ctx, principal, organizationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.MembershipResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Principal, uint) error); ok {
+		r1 = rf(ctx, principal, organizationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveMember provides a mock function with given fields: ctx, principal, organizationID, userID
+func (_m *MockOrganizationService) RemoveMember(ctx context.Context, principal *model.Principal, organizationID uint, userID uint) error {
+	ret := _m.Called(ctx, principal, organizationID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveMember")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Principal, uint, uint) error); ok {
+		r0 = rf(ctx, principal, organizationID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Resolve provides a mock function with given fields: slug
+func (_m *MockOrganizationService) Resolve(slug string) (*model.Organization, error) {
+	ret := _m.Called(slug)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resolve")
+	}
+
+	var r0 *model.Organization
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*model.Organization, error)); ok {
+		return rf(slug)
+	}
+	if rf, ok := ret.Get(0).(func(string) *model.Organization); ok {
+		r0 = rf(slug)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.Organization)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(slug)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateMember provides a mock function with given fields: ctx, principal, organizationID, userID, req
+func (_m *MockOrganizationService) UpdateMember(ctx context.Context, principal *model.Principal, organizationID uint, userID uint, req *model.UpdateMemberRequest) (*model.MembershipResponse, error) {
+	ret := _m.Called(ctx, principal, organizationID, userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateMember")
+	}
+
+	var r0 *model.MembershipResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Principal, uint, uint, *model.UpdateMemberRequest) (*model.MembershipResponse, error)); ok {
+		return rf(ctx, principal, organizationID, userID, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.Principal, uint, uint, *model.UpdateMemberRequest) *model.MembershipResponse); ok {
+		r0 = rf(ctx, principal, organizationID, userID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.MembershipResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.Principal, uint, uint, *model.UpdateMemberRequest) error); ok {
+		r1 = rf(ctx, principal, organizationID, userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockOrganizationService creates a new instance of MockOrganizationService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockOrganizationService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockOrganizationService {
+	mock := &MockOrganizationService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,199 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	protocol "github.com/go-webauthn/webauthn/protocol"
+)
+
+// MockPasskeyService is an autogenerated mock type for the PasskeyService type
+type MockPasskeyService struct {
+	mock.Mock
+}
+
+// BeginLogin provides a mock function with no fields
+func (_m *MockPasskeyService) BeginLogin() (*protocol.PublicKeyCredentialRequestOptions, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for BeginLogin")
+	}
+
+	var r0 *protocol.PublicKeyCredentialRequestOptions
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (*protocol.PublicKeyCredentialRequestOptions, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() *protocol.PublicKeyCredentialRequestOptions); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*protocol.PublicKeyCredentialRequestOptions)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BeginRegistration provides a mock function with given fields: ctx, userID
+func (_m *MockPasskeyService) BeginRegistration(ctx context.Context, userID uint) (*protocol.PublicKeyCredentialCreationOptions, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BeginRegistration")
+	}
+
+	var r0 *protocol.PublicKeyCredentialCreationOptions
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*protocol.PublicKeyCredentialCreationOptions, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *protocol.PublicKeyCredentialCreationOptions); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*protocol.PublicKeyCredentialCreationOptions)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeletePasskey provides a mock function with given fields: userID, id
+func (_m *MockPasskeyService) DeletePasskey(userID uint, id uint) error {
+	ret := _m.Called(userID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeletePasskey")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(uint, uint) error); ok {
+		r0 = rf(userID, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FinishLogin provides a mock function with given fields: ctx, req, client
+func (_m *MockPasskeyService) FinishLogin(ctx context.Context, req *model.PasskeyLoginRequest, client *model.ClientInfo) (*model.LoginResponse, error) {
+	ret := _m.Called(ctx, req, client)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishLogin")
+	}
+
+	var r0 *model.LoginResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.PasskeyLoginRequest, *model.ClientInfo) (*model.LoginResponse, error)); ok {
+		return rf(ctx, req, client)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.PasskeyLoginRequest, *model.ClientInfo) *model.LoginResponse); ok {
+		r0 = rf(ctx, req, client)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.LoginResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.PasskeyLoginRequest, *model.ClientInfo) error); ok {
+		r1 = rf(ctx, req, client)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FinishRegistration provides a mock function with given fields: userID, req
+func (_m *MockPasskeyService) FinishRegistration(userID uint, req *model.RegisterPasskeyRequest) (*model.PasskeyResponse, error) {
+	ret := _m.Called(userID, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishRegistration")
+	}
+
+	var r0 *model.PasskeyResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(uint, *model.RegisterPasskeyRequest) (*model.PasskeyResponse, error)); ok {
+		return rf(userID, req)
+	}
+	if rf, ok := ret.Get(0).(func(uint, *model.RegisterPasskeyRequest) *model.PasskeyResponse); ok {
+		r0 = rf(userID, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.PasskeyResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(uint, *model.RegisterPasskeyRequest) error); ok {
+		r1 = rf(userID, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListPasskeys provides a mock function with given fields: ctx, userID
+func (_m *MockPasskeyService) ListPasskeys(ctx context.Context, userID uint) ([]*model.PasskeyResponse, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPasskeys")
+	}
+
+	var r0 []*model.PasskeyResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]*model.PasskeyResponse, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []*model.PasskeyResponse); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.PasskeyResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockPasskeyService creates a new instance of MockPasskeyService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPasskeyService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPasskeyService {
+	mock := &MockPasskeyService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
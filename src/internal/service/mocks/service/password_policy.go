@@ -0,0 +1,45 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// MockPasswordPolicy is an autogenerated mock type for the PasswordPolicy type
+type MockPasswordPolicy struct {
+	mock.Mock
+}
+
+// Validate provides a mock function with given fields: password, user
+func (_m *MockPasswordPolicy) Validate(password string, user *model.User) error {
+	ret := _m.Called(password, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Validate")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *model.User) error); ok {
+		r0 = rf(password, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewMockPasswordPolicy creates a new instance of MockPasswordPolicy. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPasswordPolicy(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPasswordPolicy {
+	mock := &MockPasswordPolicy{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
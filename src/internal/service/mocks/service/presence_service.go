@@ -0,0 +1,59 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// MockPresenceService is an autogenerated mock type for the PresenceService type
+type MockPresenceService struct {
+	mock.Mock
+}
+
+// GetPresence provides a mock function with given fields: ctx, userID
+func (_m *MockPresenceService) GetPresence(ctx context.Context, userID uint) (*model.PresenceResponse, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPresence")
+	}
+
+	var r0 *model.PresenceResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*model.PresenceResponse, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *model.PresenceResponse); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.PresenceResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockPresenceService creates a new instance of MockPresenceService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPresenceService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPresenceService {
+	mock := &MockPresenceService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,89 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// MockScheduleService is an autogenerated mock type for the ScheduleService type
+type MockScheduleService struct {
+	mock.Mock
+}
+
+// ListSchedules provides a mock function with given fields: ctx
+func (_m *MockScheduleService) ListSchedules(ctx context.Context) ([]*model.ScheduleResponse, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSchedules")
+	}
+
+	var r0 []*model.ScheduleResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.ScheduleResponse, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.ScheduleResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.ScheduleResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TriggerSchedule provides a mock function with given fields: ctx, name
+func (_m *MockScheduleService) TriggerSchedule(ctx context.Context, name string) (*model.ScheduleResponse, error) {
+	ret := _m.Called(ctx, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TriggerSchedule")
+	}
+
+	var r0 *model.ScheduleResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*model.ScheduleResponse, error)); ok {
+		return rf(ctx, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *model.ScheduleResponse); ok {
+		r0 = rf(ctx, name)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.ScheduleResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockScheduleService creates a new instance of MockScheduleService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockScheduleService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockScheduleService {
+	mock := &MockScheduleService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,119 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	jwt "github.com/golang-jwt/jwt/v5"
+	mock "github.com/stretchr/testify/mock"
+
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	rsa "crypto/rsa"
+)
+
+// MockSigningKeyService is an autogenerated mock type for the SigningKeyService type
+type MockSigningKeyService struct {
+	mock.Mock
+}
+
+// PublicKey provides a mock function with given fields: keyID
+func (_m *MockSigningKeyService) PublicKey(keyID string) (*rsa.PublicKey, error) {
+	ret := _m.Called(keyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublicKey")
+	}
+
+	var r0 *rsa.PublicKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*rsa.PublicKey, error)); ok {
+		return rf(keyID)
+	}
+	if rf, ok := ret.Get(0).(func(string) *rsa.PublicKey); ok {
+		r0 = rf(keyID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*rsa.PublicKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(keyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublicKeys provides a mock function with no fields
+func (_m *MockSigningKeyService) PublicKeys() (*model.JSONWebKeySet, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublicKeys")
+	}
+
+	var r0 *model.JSONWebKeySet
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (*model.JSONWebKeySet, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() *model.JSONWebKeySet); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.JSONWebKeySet)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Sign provides a mock function with given fields: claims, tokenType
+func (_m *MockSigningKeyService) Sign(claims jwt.Claims, tokenType string) (string, error) {
+	ret := _m.Called(claims, tokenType)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Sign")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(jwt.Claims, string) (string, error)); ok {
+		return rf(claims, tokenType)
+	}
+	if rf, ok := ret.Get(0).(func(jwt.Claims, string) string); ok {
+		r0 = rf(claims, tokenType)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(jwt.Claims, string) error); ok {
+		r1 = rf(claims, tokenType)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockSigningKeyService creates a new instance of MockSigningKeyService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSigningKeyService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSigningKeyService {
+	mock := &MockSigningKeyService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,144 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+	service "github.com/weeranieb/go-kit-base/src/internal/service"
+
+	time "time"
+)
+
+// MockTokenService is an autogenerated mock type for the TokenService type
+type MockTokenService struct {
+	mock.Mock
+}
+
+// IssueAccessToken provides a mock function with given fields: user, session
+func (_m *MockTokenService) IssueAccessToken(user *model.User, session *model.Session) (string, error) {
+	ret := _m.Called(user, session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueAccessToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.User, *model.Session) (string, error)); ok {
+		return rf(user, session)
+	}
+	if rf, ok := ret.Get(0).(func(*model.User, *model.Session) string); ok {
+		r0 = rf(user, session)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.User, *model.Session) error); ok {
+		r1 = rf(user, session)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IssueMFAChallenge provides a mock function with given fields: user, expiresAt
+func (_m *MockTokenService) IssueMFAChallenge(user *model.User, expiresAt time.Time) (string, error) {
+	ret := _m.Called(user, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueMFAChallenge")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*model.User, time.Time) (string, error)); ok {
+		return rf(user, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(*model.User, time.Time) string); ok {
+		r0 = rf(user, expiresAt)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*model.User, time.Time) error); ok {
+		r1 = rf(user, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ParseAccessToken provides a mock function with given fields: token
+func (_m *MockTokenService) ParseAccessToken(token string) (*service.AccessTokenClaims, error) {
+	ret := _m.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ParseAccessToken")
+	}
+
+	var r0 *service.AccessTokenClaims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*service.AccessTokenClaims, error)); ok {
+		return rf(token)
+	}
+	if rf, ok := ret.Get(0).(func(string) *service.AccessTokenClaims); ok {
+		r0 = rf(token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*service.AccessTokenClaims)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ParseMFAChallenge provides a mock function with given fields: token
+func (_m *MockTokenService) ParseMFAChallenge(token string) (uint, error) {
+	ret := _m.Called(token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ParseMFAChallenge")
+	}
+
+	var r0 uint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (uint, error)); ok {
+		return rf(token)
+	}
+	if rf, ok := ret.Get(0).(func(string) uint); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Get(0).(uint)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockTokenService creates a new instance of MockTokenService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockTokenService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockTokenService {
+	mock := &MockTokenService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
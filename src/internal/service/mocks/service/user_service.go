@@ -3,8 +3,12 @@
 package service
 
 import (
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
 	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	time "time"
 )
 
 // MockUserService is an autogenerated mock type for the UserService type
@@ -12,9 +16,9 @@ type MockUserService struct {
 	mock.Mock
 }
 
-// CreateUser provides a mock function with given fields: req
-func (_m *MockUserService) CreateUser(req *model.CreateUserRequest) (*model.UserResponse, error) {
-	ret := _m.Called(req)
+// CreateUser provides a mock function with given fields: ctx, req
+func (_m *MockUserService) CreateUser(ctx context.Context, req *model.CreateUserRequest) (*model.UserResponse, error) {
+	ret := _m.Called(ctx, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateUser")
@@ -22,19 +26,19 @@ func (_m *MockUserService) CreateUser(req *model.CreateUserRequest) (*model.User
 
 	var r0 *model.UserResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(*model.CreateUserRequest) (*model.UserResponse, error)); ok {
-		return rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.CreateUserRequest) (*model.UserResponse, error)); ok {
+		return rf(ctx, req)
 	}
-	if rf, ok := ret.Get(0).(func(*model.CreateUserRequest) *model.UserResponse); ok {
-		r0 = rf(req)
+	if rf, ok := ret.Get(0).(func(context.Context, *model.CreateUserRequest) *model.UserResponse); ok {
+		r0 = rf(ctx, req)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.UserResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(*model.CreateUserRequest) error); ok {
-		r1 = rf(req)
+	if rf, ok := ret.Get(1).(func(context.Context, *model.CreateUserRequest) error); ok {
+		r1 = rf(ctx, req)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -42,17 +46,17 @@ func (_m *MockUserService) CreateUser(req *model.CreateUserRequest) (*model.User
 	return r0, r1
 }
 
-// DeleteUser provides a mock function with given fields: id
-func (_m *MockUserService) DeleteUser(id uint) error {
-	ret := _m.Called(id)
+// DeleteUser provides a mock function with given fields: ctx, id
+func (_m *MockUserService) DeleteUser(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteUser")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(uint) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -60,9 +64,9 @@ func (_m *MockUserService) DeleteUser(id uint) error {
 	return r0
 }
 
-// GetUser provides a mock function with given fields: id
-func (_m *MockUserService) GetUser(id uint) (*model.UserResponse, error) {
-	ret := _m.Called(id)
+// GetUser provides a mock function with given fields: ctx, id
+func (_m *MockUserService) GetUser(ctx context.Context, id uint) (*model.UserResponse, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUser")
@@ -70,19 +74,109 @@ func (_m *MockUserService) GetUser(id uint) (*model.UserResponse, error) {
 
 	var r0 *model.UserResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint) (*model.UserResponse, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*model.UserResponse, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(uint) *model.UserResponse); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *model.UserResponse); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.UserResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserAsOf provides a mock function with given fields: ctx, id, at
+func (_m *MockUserService) GetUserAsOf(ctx context.Context, id uint, at time.Time) (*model.UserResponse, error) {
+	ret := _m.Called(ctx, id, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserAsOf")
+	}
+
+	var r0 *model.UserResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, time.Time) (*model.UserResponse, error)); ok {
+		return rf(ctx, id, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, time.Time) *model.UserResponse); ok {
+		r0 = rf(ctx, id, at)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UserResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, time.Time) error); ok {
+		r1 = rf(ctx, id, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUserVersion provides a mock function with given fields: ctx, id, version
+func (_m *MockUserService) GetUserVersion(ctx context.Context, id uint, version uint) (*model.UserVersionResponse, error) {
+	ret := _m.Called(ctx, id, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserVersion")
+	}
+
+	var r0 *model.UserVersionResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) (*model.UserVersionResponse, error)); ok {
+		return rf(ctx, id, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) *model.UserVersionResponse); ok {
+		r0 = rf(ctx, id, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.UserVersionResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uint) error); ok {
+		r1 = rf(ctx, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUserVersions provides a mock function with given fields: ctx, id, limit, offset
+func (_m *MockUserService) ListUserVersions(ctx context.Context, id uint, limit int, offset int) ([]*model.UserVersionResponse, error) {
+	ret := _m.Called(ctx, id, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUserVersions")
+	}
+
+	var r0 []*model.UserVersionResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int, int) ([]*model.UserVersionResponse, error)); ok {
+		return rf(ctx, id, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int, int) []*model.UserVersionResponse); ok {
+		r0 = rf(ctx, id, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.UserVersionResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, int, int) error); ok {
+		r1 = rf(ctx, id, limit, offset)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -90,9 +184,9 @@ func (_m *MockUserService) GetUser(id uint) (*model.UserResponse, error) {
 	return r0, r1
 }
 
-// ListUsers provides a mock function with given fields: limit, offset
-func (_m *MockUserService) ListUsers(limit int, offset int) ([]*model.UserResponse, error) {
-	ret := _m.Called(limit, offset)
+// ListUsers provides a mock function with given fields: ctx, limit, offset
+func (_m *MockUserService) ListUsers(ctx context.Context, limit int, offset int) ([]*model.UserResponse, error) {
+	ret := _m.Called(ctx, limit, offset)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListUsers")
@@ -100,19 +194,19 @@ func (_m *MockUserService) ListUsers(limit int, offset int) ([]*model.UserRespon
 
 	var r0 []*model.UserResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int, int) ([]*model.UserResponse, error)); ok {
-		return rf(limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]*model.UserResponse, error)); ok {
+		return rf(ctx, limit, offset)
 	}
-	if rf, ok := ret.Get(0).(func(int, int) []*model.UserResponse); ok {
-		r0 = rf(limit, offset)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []*model.UserResponse); ok {
+		r0 = rf(ctx, limit, offset)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*model.UserResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int, int) error); ok {
-		r1 = rf(limit, offset)
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, limit, offset)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -120,9 +214,9 @@ func (_m *MockUserService) ListUsers(limit int, offset int) ([]*model.UserRespon
 	return r0, r1
 }
 
-// UpdateUser provides a mock function with given fields: id, req
-func (_m *MockUserService) UpdateUser(id uint, req *model.UpdateUserRequest) (*model.UserResponse, error) {
-	ret := _m.Called(id, req)
+// UpdateUser provides a mock function with given fields: ctx, id, version, req
+func (_m *MockUserService) UpdateUser(ctx context.Context, id uint, version uint, req *model.UpdateUserRequest) (*model.UserResponse, error) {
+	ret := _m.Called(ctx, id, version, req)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateUser")
@@ -130,19 +224,19 @@ func (_m *MockUserService) UpdateUser(id uint, req *model.UpdateUserRequest) (*m
 
 	var r0 *model.UserResponse
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint, *model.UpdateUserRequest) (*model.UserResponse, error)); ok {
-		return rf(id, req)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, *model.UpdateUserRequest) (*model.UserResponse, error)); ok {
+		return rf(ctx, id, version, req)
 	}
-	if rf, ok := ret.Get(0).(func(uint, *model.UpdateUserRequest) *model.UserResponse); ok {
-		r0 = rf(id, req)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint, *model.UpdateUserRequest) *model.UserResponse); ok {
+		r0 = rf(ctx, id, version, req)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*model.UserResponse)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(uint, *model.UpdateUserRequest) error); ok {
-		r1 = rf(id, req)
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uint, *model.UpdateUserRequest) error); ok {
+		r1 = rf(ctx, id, version, req)
 	} else {
 		r1 = ret.Error(1)
 	}
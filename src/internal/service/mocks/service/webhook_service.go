@@ -0,0 +1,327 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package service
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	events "github.com/weeranieb/go-kit-base/src/internal/events"
+
+	model "github.com/weeranieb/go-kit-base/src/internal/model"
+
+	service "github.com/weeranieb/go-kit-base/src/internal/service"
+)
+
+// MockWebhookService is an autogenerated mock type for the WebhookService type
+type MockWebhookService struct {
+	mock.Mock
+}
+
+// ClaimDeliveries provides a mock function with given fields: ctx, limit
+func (_m *MockWebhookService) ClaimDeliveries(ctx context.Context, limit int) ([]*service.WebhookDispatch, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimDeliveries")
+	}
+
+	var r0 []*service.WebhookDispatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]*service.WebhookDispatch, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*service.WebhookDispatch); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*service.WebhookDispatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateWebhook provides a mock function with given fields: ctx, req
+func (_m *MockWebhookService) CreateWebhook(ctx context.Context, req *model.CreateWebhookRequest) (*model.CreateWebhookResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWebhook")
+	}
+
+	var r0 *model.CreateWebhookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.CreateWebhookRequest) (*model.CreateWebhookResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *model.CreateWebhookRequest) *model.CreateWebhookResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.CreateWebhookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *model.CreateWebhookRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteWebhook provides a mock function with given fields: ctx, id
+func (_m *MockWebhookService) DeleteWebhook(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetDelivery provides a mock function with given fields: ctx, webhookID, id
+func (_m *MockWebhookService) GetDelivery(ctx context.Context, webhookID uint, id uint) (*model.WebhookDeliveryResponse, error) {
+	ret := _m.Called(ctx, webhookID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDelivery")
+	}
+
+	var r0 *model.WebhookDeliveryResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) (*model.WebhookDeliveryResponse, error)); ok {
+		return rf(ctx, webhookID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) *model.WebhookDeliveryResponse); ok {
+		r0 = rf(ctx, webhookID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WebhookDeliveryResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uint) error); ok {
+		r1 = rf(ctx, webhookID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWebhook provides a mock function with given fields: ctx, id
+func (_m *MockWebhookService) GetWebhook(ctx context.Context, id uint) (*model.WebhookResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWebhook")
+	}
+
+	var r0 *model.WebhookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*model.WebhookResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *model.WebhookResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WebhookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDeliveries provides a mock function with given fields: ctx, webhookID, filter, limit, offset
+func (_m *MockWebhookService) ListDeliveries(ctx context.Context, webhookID uint, filter *model.WebhookDeliveryFilter, limit int, offset int) ([]*model.WebhookDeliveryResponse, error) {
+	ret := _m.Called(ctx, webhookID, filter, limit, offset)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListDeliveries")
+	}
+
+	var r0 []*model.WebhookDeliveryResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *model.WebhookDeliveryFilter, int, int) ([]*model.WebhookDeliveryResponse, error)); ok {
+		return rf(ctx, webhookID, filter, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *model.WebhookDeliveryFilter, int, int) []*model.WebhookDeliveryResponse); ok {
+		r0 = rf(ctx, webhookID, filter, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.WebhookDeliveryResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, *model.WebhookDeliveryFilter, int, int) error); ok {
+		r1 = rf(ctx, webhookID, filter, limit, offset)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListWebhooks provides a mock function with given fields: ctx
+func (_m *MockWebhookService) ListWebhooks(ctx context.Context) ([]*model.WebhookResponse, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListWebhooks")
+	}
+
+	var r0 []*model.WebhookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]*model.WebhookResponse, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []*model.WebhookResponse); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*model.WebhookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Publish provides a mock function with given fields: ctx, msg
+func (_m *MockWebhookService) Publish(ctx context.Context, msg *events.Message) error {
+	ret := _m.Called(ctx, msg)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *events.Message) error); ok {
+		r0 = rf(ctx, msg)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RecordAttempt provides a mock function with given fields: ctx, delivery, attempt
+func (_m *MockWebhookService) RecordAttempt(ctx context.Context, delivery *model.WebhookDelivery, attempt *model.WebhookDeliveryAttempt) error {
+	ret := _m.Called(ctx, delivery, attempt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordAttempt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *model.WebhookDelivery, *model.WebhookDeliveryAttempt) error); ok {
+		r0 = rf(ctx, delivery, attempt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Redeliver provides a mock function with given fields: ctx, webhookID, id
+func (_m *MockWebhookService) Redeliver(ctx context.Context, webhookID uint, id uint) (*model.WebhookDeliveryResponse, error) {
+	ret := _m.Called(ctx, webhookID, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Redeliver")
+	}
+
+	var r0 *model.WebhookDeliveryResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) (*model.WebhookDeliveryResponse, error)); ok {
+		return rf(ctx, webhookID, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, uint) *model.WebhookDeliveryResponse); ok {
+		r0 = rf(ctx, webhookID, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WebhookDeliveryResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, uint) error); ok {
+		r1 = rf(ctx, webhookID, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateWebhook provides a mock function with given fields: ctx, id, req
+func (_m *MockWebhookService) UpdateWebhook(ctx context.Context, id uint, req *model.UpdateWebhookRequest) (*model.WebhookResponse, error) {
+	ret := _m.Called(ctx, id, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateWebhook")
+	}
+
+	var r0 *model.WebhookResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *model.UpdateWebhookRequest) (*model.WebhookResponse, error)); ok {
+		return rf(ctx, id, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, *model.UpdateWebhookRequest) *model.WebhookResponse); ok {
+		r0 = rf(ctx, id, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*model.WebhookResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, *model.UpdateWebhookRequest) error); ok {
+		r1 = rf(ctx, id, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMockWebhookService creates a new instance of MockWebhookService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockWebhookService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockWebhookService {
+	mock := &MockWebhookService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"net/netip"
+	"net/url"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+)
+
+var (
+	ErrOAuthClientNotFound  = errors.New("client not found")
+	ErrInvalidRedirectURI   = errors.New("redirect URIs must use https, or http on a loopback address, and have no fragment")
+	ErrOpenIDScopeRequired  = errors.New("scopes must include openid")
+	ErrOAuthClientForbidden = errors.New("only admins can manage clients")
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=OAuthClientService --output=./mocks/service --outpkg=service --filename=oauth_client_service.go --structname=MockOAuthClientService --with-expecter=false
+type OAuthClientService interface {
+	Create(ctx context.Context, caller *model.Principal, req *model.CreateOAuthClientRequest) (*model.CreateOAuthClientResponse, error)
+	List(ctx context.Context, caller *model.Principal) ([]*model.OAuthClientResponse, error)
+	Revoke(ctx context.Context, caller *model.Principal, id uint) error
+}
+
+type oauthClientService struct {
+	clientRepo repository.OAuthClientRepository
+	userRepo   repository.UserRepository
+}
+
+func NewOAuthClientService(clientRepo repository.OAuthClientRepository, userRepo repository.UserRepository) OAuthClientService {
+	return &oauthClientService{
+		clientRepo: clientRepo,
+		userRepo:   userRepo,
+	}
+}
+
+// Create registers a client. The secret of a confidential client is only
+// part of the response.
+func (s *oauthClientService) Create(ctx context.Context, caller *model.Principal, req *model.CreateOAuthClientRequest) (*model.CreateOAuthClientResponse, error) {
+	if err := s.authorize(ctx, caller); err != nil {
+		return nil, err
+	}
+
+	for _, redirectURI := range req.RedirectURIs {
+		if !validRedirectURI(redirectURI) {
+			return nil, ErrInvalidRedirectURI
+		}
+	}
+	if !model.ScopeList(req.Scopes).Contains(model.ScopeOpenID) {
+		return nil, ErrOpenIDScopeRequired
+	}
+
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+
+	client := &model.OAuthClient{
+		ClientID:     apiKeyPrefixEncoding.EncodeToString(buf),
+		Name:         req.Name,
+		RedirectURIs: model.RedirectURIList(req.RedirectURIs),
+		Scopes:       model.ScopeList(req.Scopes),
+	}
+
+	var secret string
+	if !req.Public {
+		var secretHash string
+		var err error
+		secret, secretHash, err = newSecretToken()
+		if err != nil {
+			return nil, err
+		}
+		client.SecretHash = secretHash
+	}
+
+	if err := s.clientRepo.Create(client); err != nil {
+		return nil, err
+	}
+
+	return &model.CreateOAuthClientResponse{
+		OAuthClientResponse: *toOAuthClientResponse(client),
+		ClientSecret:        secret,
+	}, nil
+}
+
+func (s *oauthClientService) List(ctx context.Context, caller *model.Principal) ([]*model.OAuthClientResponse, error) {
+	if err := s.authorize(ctx, caller); err != nil {
+		return nil, err
+	}
+
+	clients, err := s.clientRepo.List()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*model.OAuthClientResponse, 0, len(clients))
+	for _, client := range clients {
+		responses = append(responses, toOAuthClientResponse(client))
+	}
+	return responses, nil
+}
+
+// Revoke stops the client from signing users in. Access tokens issued to it
+// stop working at the userinfo endpoint.
+func (s *oauthClientService) Revoke(ctx context.Context, caller *model.Principal, id uint) error {
+	if err := s.authorize(ctx, caller); err != nil {
+		return err
+	}
+
+	revoked, err := s.clientRepo.Revoke(id, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrOAuthClientNotFound
+	}
+	return nil
+}
+
+// authorize lets users with the admin role manage clients, which sign in
+// users of every organization. Service keys, limited by their scopes only,
+// may manage them too.
+func (s *oauthClientService) authorize(ctx context.Context, caller *model.Principal) error {
+	if caller.UserID == 0 {
+		return nil
+	}
+
+	// The caller may act in another organization than its own
+	user, err := s.userRepo.WithContext(tenant.Unscoped(ctx)).GetByID(caller.UserID)
+	if err != nil {
+		return err
+	}
+	if user.Role != model.RoleAdmin {
+		return ErrOAuthClientForbidden
+	}
+	return nil
+}
+
+// validRedirectURI accepts absolute https URIs without a fragment, and http
+// ones on a loopback address for native apps and development. Other schemes
+// are refused, as a javascript: or custom scheme URI would hand the code to
+// whatever handles it.
+func validRedirectURI(redirectURI string) bool {
+	parsed, err := url.Parse(redirectURI)
+	if err != nil || parsed.Host == "" || parsed.Fragment != "" {
+		return false
+	}
+
+	switch parsed.Scheme {
+	case "https":
+		return true
+	case "http":
+		host := parsed.Hostname()
+		if host == "localhost" {
+			return true
+		}
+		ip, err := netip.ParseAddr(host)
+		return err == nil && ip.IsLoopback()
+	default:
+		return false
+	}
+}
+
+func toOAuthClientResponse(client *model.OAuthClient) *model.OAuthClientResponse {
+	return &model.OAuthClientResponse{
+		ID:           client.ID,
+		ClientID:     client.ClientID,
+		Name:         client.Name,
+		RedirectURIs: client.RedirectURIs,
+		Scopes:       client.Scopes,
+		Public:       client.IsPublic(),
+		RevokedAt:    client.RevokedAt,
+		CreatedAt:    client.CreatedAt,
+	}
+}
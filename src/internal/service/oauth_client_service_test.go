@@ -0,0 +1,154 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+func (s *ServiceTestSuite) newCreateOAuthClientRequest() *model.CreateOAuthClientRequest {
+	return &model.CreateOAuthClientRequest{
+		Name:         "Dashboard",
+		RedirectURIs: []string{"https://app.example.com/callback"},
+		Scopes:       []string{"openid", "email"},
+	}
+}
+
+// asAdmin makes user 1 an admin and returns it as the caller.
+func (s *ServiceTestSuite) asAdmin() *model.Principal {
+	admin := s.newUserWithPassword("password123")
+	admin.Role = model.RoleAdmin
+	s.userRepo.On("GetByID", uint(1)).Return(admin, nil)
+	return &model.Principal{UserID: 1, SessionID: "session-1"}
+}
+
+func (s *ServiceTestSuite) TestCreateOAuthClient_Confidential() {
+	caller := s.asAdmin()
+	s.clientRepo.On("Create", mock.AnythingOfType("*model.OAuthClient")).Return(nil)
+
+	// Execute
+	result, err := s.oauthClients.Create(s.ctx, caller, s.newCreateOAuthClientRequest())
+
+	// Assert: only a hash of the secret is stored
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), result.ClientID)
+	assert.NotEmpty(s.T(), result.ClientSecret)
+	assert.False(s.T(), result.Public)
+	stored := s.clientRepo.Calls[0].Arguments.Get(0).(*model.OAuthClient)
+	assert.Equal(s.T(), hashSecret(result.ClientSecret), stored.SecretHash)
+	assert.Equal(s.T(), model.RedirectURIList{"https://app.example.com/callback"}, stored.RedirectURIs)
+}
+
+func (s *ServiceTestSuite) TestCreateOAuthClient_Public() {
+	caller := s.asAdmin()
+	req := s.newCreateOAuthClientRequest()
+	req.Public = true
+	s.clientRepo.On("Create", mock.AnythingOfType("*model.OAuthClient")).Return(nil)
+
+	// Execute
+	result, err := s.oauthClients.Create(s.ctx, caller, req)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), result.ClientSecret)
+	assert.True(s.T(), result.Public)
+}
+
+func (s *ServiceTestSuite) TestCreateOAuthClient_RequiresOpenIDScope() {
+	caller := s.asAdmin()
+	req := s.newCreateOAuthClientRequest()
+	req.Scopes = []string{"email"}
+
+	// Execute
+	result, err := s.oauthClients.Create(s.ctx, caller, req)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrOpenIDScopeRequired)
+	assert.Nil(s.T(), result)
+}
+
+func (s *ServiceTestSuite) TestCreateOAuthClient_RedirectURIs() {
+	caller := s.asAdmin()
+	s.clientRepo.On("Create", mock.AnythingOfType("*model.OAuthClient")).Return(nil)
+
+	for _, redirectURI := range []string{
+		"https://app.example.com/callback",
+		"http://localhost:8080/callback",
+		"http://127.0.0.1:5000/callback",
+		"http://[::1]/callback",
+	} {
+		req := s.newCreateOAuthClientRequest()
+		req.RedirectURIs = []string{redirectURI}
+
+		// Execute
+		_, err := s.oauthClients.Create(s.ctx, caller, req)
+
+		// Assert
+		assert.NoError(s.T(), err, redirectURI)
+	}
+}
+
+func (s *ServiceTestSuite) TestCreateOAuthClient_InvalidRedirectURI() {
+	caller := s.asAdmin()
+
+	for _, redirectURI := range []string{
+		"/callback",
+		"https://app.example.com/callback#fragment",
+		"http://app.example.com/callback",
+		"javascript:alert(document.cookie)",
+		"com.example.app://callback",
+		"data:text/html,<script>alert(1)</script>",
+	} {
+		req := s.newCreateOAuthClientRequest()
+		req.RedirectURIs = []string{redirectURI}
+
+		// Execute
+		_, err := s.oauthClients.Create(s.ctx, caller, req)
+
+		// Assert
+		assert.ErrorIs(s.T(), err, ErrInvalidRedirectURI, redirectURI)
+	}
+	s.clientRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestOAuthClients_NotAdmin() {
+	// The owner of the organization is not an admin of the service
+	s.userRepo.On("GetByID", uint(1)).Return(s.newUserWithPassword("password123"), nil)
+	caller := &model.Principal{UserID: 1, SessionID: "session-1"}
+
+	// Execute
+	_, createErr := s.oauthClients.Create(s.ctx, caller, s.newCreateOAuthClientRequest())
+	_, listErr := s.oauthClients.List(s.ctx, caller)
+	revokeErr := s.oauthClients.Revoke(s.ctx, caller, 3)
+
+	// Assert
+	assert.ErrorIs(s.T(), createErr, ErrOAuthClientForbidden)
+	assert.ErrorIs(s.T(), listErr, ErrOAuthClientForbidden)
+	assert.ErrorIs(s.T(), revokeErr, ErrOAuthClientForbidden)
+	s.clientRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+	s.clientRepo.AssertNotCalled(s.T(), "List")
+	s.clientRepo.AssertNotCalled(s.T(), "Revoke", mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestOAuthClients_ServiceKey() {
+	s.clientRepo.On("List").Return([]*model.OAuthClient{s.newOAuthClient()}, nil)
+
+	// Execute
+	clients, err := s.oauthClients.List(s.ctx, &model.Principal{APIKeyID: 7, Service: "billing"})
+
+	// Assert: service keys are limited by their scopes only
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), clients, 1)
+	s.userRepo.AssertNotCalled(s.T(), "GetByID", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestRevokeOAuthClient_NotFound() {
+	caller := s.asAdmin()
+	s.clientRepo.On("Revoke", uint(999), mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	// Execute
+	err := s.oauthClients.Revoke(s.ctx, caller, 999)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrOAuthClientNotFound)
+}
@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/oidc"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnknownOIDCProvider = errors.New("unknown identity provider")
+	ErrInvalidOIDCState    = errors.New("invalid or expired sign-in state")
+	ErrOIDCLoginFailed     = errors.New("sign-in with the identity provider failed")
+	ErrNoLinkedAccount     = errors.New("no account is linked to this identity")
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=OIDCLoginService --output=./mocks/service --outpkg=service --filename=oidc_login_service.go --structname=MockOIDCLoginService --with-expecter=false
+type OIDCLoginService interface {
+	Providers() []string
+	Start(provider string) (*model.OIDCAuthorization, error)
+	Callback(ctx context.Context, provider string, req *model.OIDCCallbackRequest, client *model.ClientInfo) (*model.LoginResponse, error)
+	ListIdentities(ctx context.Context, userID uint) ([]*model.ExternalIdentityResponse, error)
+}
+
+type oidcLoginService struct {
+	providers    oidc.Providers
+	stateRepo    repository.OIDCStateRepository
+	identityRepo repository.ExternalIdentityRepository
+	userRepo     repository.UserRepository
+	authService  AuthService
+	stateTTL     time.Duration
+}
+
+func NewOIDCLoginService(
+	providers oidc.Providers,
+	stateRepo repository.OIDCStateRepository,
+	identityRepo repository.ExternalIdentityRepository,
+	userRepo repository.UserRepository,
+	authService AuthService,
+	conf *config.Config,
+) OIDCLoginService {
+	return &oidcLoginService{
+		providers:    providers,
+		stateRepo:    stateRepo,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		authService:  authService,
+		stateTTL:     conf.OIDC.StateTTL,
+	}
+}
+
+func (s *oidcLoginService) Providers() []string {
+	return s.providers.Names()
+}
+
+// Start begins a sign-in at the provider. The returned state must come back
+// with the callback; the nonce and PKCE verifier stay on the server.
+func (s *oidcLoginService) Start(providerName string) (*model.OIDCAuthorization, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, ErrUnknownOIDCProvider
+	}
+
+	state, stateHash, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+	nonce, _, err := newSecretToken()
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	authURL, err := provider.AuthCodeURL(state, nonce, oidc.CodeChallenge(verifier))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.stateTTL)
+	// Abandoned sign-ins would otherwise pile up.
+	if err := s.stateRepo.DeleteExpired(now); err != nil {
+		log.Printf("Failed to delete expired OIDC states: %v", err)
+	}
+
+	err = s.stateRepo.Create(&model.OIDCLoginState{
+		StateHash:    stateHash,
+		Provider:     providerName,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.OIDCAuthorization{
+		AuthorizationURL: authURL,
+		State:            state,
+		ExpiresAt:        expiresAt,
+	}, nil
+}
+
+// Callback completes a sign-in: it redeems the code, verifies the ID token
+// and logs in the user linked to the external identity.
+func (s *oidcLoginService) Callback(ctx context.Context, providerName string, req *model.OIDCCallbackRequest, client *model.ClientInfo) (*model.LoginResponse, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, ErrUnknownOIDCProvider
+	}
+
+	state, err := s.stateRepo.Consume(hashSecret(req.State))
+	if err != nil {
+		return nil, ErrInvalidOIDCState
+	}
+	if state.Provider != providerName || !time.Now().Before(state.ExpiresAt) {
+		return nil, ErrInvalidOIDCState
+	}
+
+	token, err := provider.Exchange(req.Code, state.CodeVerifier)
+	if err != nil {
+		log.Printf("OIDC code exchange with %s failed: %v", providerName, err)
+		return nil, ErrOIDCLoginFailed
+	}
+
+	claims, err := provider.VerifyIDToken(token.IDToken, state.Nonce)
+	if err != nil {
+		log.Printf("OIDC ID token from %s rejected: %v", providerName, err)
+		return nil, ErrOIDCLoginFailed
+	}
+
+	// The identity names the user, whatever the organization of the request
+	user, err := s.linkedUser(tenant.Unscoped(ctx), providerName, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.authService.CompleteExternalLogin(user, client)
+}
+
+func (s *oidcLoginService) ListIdentities(ctx context.Context, userID uint) ([]*model.ExternalIdentityResponse, error) {
+	if _, err := s.userRepo.WithContext(ctx).GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	identities, err := s.identityRepo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*model.ExternalIdentityResponse, 0, len(identities))
+	for _, identity := range identities {
+		responses = append(responses, &model.ExternalIdentityResponse{
+			ID:          identity.ID,
+			Provider:    identity.Provider,
+			Email:       identity.Email,
+			LastLoginAt: identity.LastLoginAt,
+			CreatedAt:   identity.CreatedAt,
+		})
+	}
+	return responses, nil
+}
+
+// linkedUser returns the user linked to the external identity. An identity
+// seen for the first time is linked to the user with the same email address
+// if both the provider and this service have verified it; otherwise whoever
+// registered the address first could take over the account.
+func (s *oidcLoginService) linkedUser(ctx context.Context, providerName string, claims *oidc.IDTokenClaims) (*model.User, error) {
+	now := time.Now()
+	users := s.userRepo.WithContext(ctx)
+
+	identity, err := s.identityRepo.GetByProviderSubject(providerName, claims.Subject)
+	if err == nil {
+		user, err := users.GetByID(identity.UserID)
+		if err != nil {
+			return nil, ErrNoLinkedAccount
+		}
+		if err := s.identityRepo.TouchLastLogin(identity.ID, now); err != nil {
+			log.Printf("Failed to update last login of external identity %d: %v", identity.ID, err)
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, ErrNoLinkedAccount
+	}
+	user, err := users.GetByEmail(claims.Email)
+	if err != nil || user.EmailVerifiedAt == nil || !strings.EqualFold(user.Email, claims.Email) {
+		return nil, ErrNoLinkedAccount
+	}
+
+	err = s.identityRepo.Create(&model.ExternalIdentity{
+		UserID:      user.ID,
+		Provider:    providerName,
+		Subject:     claims.Subject,
+		Email:       claims.Email,
+		LastLoginAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
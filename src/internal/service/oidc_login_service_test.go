@@ -0,0 +1,249 @@
+package service
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/oidc/oidctest"
+	"gorm.io/gorm"
+)
+
+// startOIDCLogin starts a sign-in at the mock provider and returns the
+// callback request it redirects back with. The stored state is handed back
+// when the callback consumes it.
+func (s *ServiceTestSuite) startOIDCLogin(identity oidctest.Identity) *model.OIDCCallbackRequest {
+	s.oidcServer.SetIdentity(identity)
+
+	var stored *model.OIDCLoginState
+	s.stateRepo.On("DeleteExpired", mock.AnythingOfType("time.Time")).Return(nil)
+	s.stateRepo.On("Create", mock.AnythingOfType("*model.OIDCLoginState")).
+		Run(func(args mock.Arguments) { stored = args.Get(0).(*model.OIDCLoginState) }).
+		Return(nil)
+
+	authorization, err := s.oidcService.Start("test")
+	s.Require().NoError(err)
+
+	code, state, err := s.oidcServer.Authorize(authorization.AuthorizationURL)
+	s.Require().NoError(err)
+	s.Require().Equal(authorization.State, state)
+
+	s.stateRepo.On("Consume", hashSecret(state)).Return(func(string) *model.OIDCLoginState { return stored }, nil)
+	return &model.OIDCCallbackRequest{Code: code, State: state}
+}
+
+func (s *ServiceTestSuite) newVerifiedUser() *model.User {
+	verifiedAt := time.Now().Add(-time.Hour)
+	return &model.User{
+		ID:              1,
+		Username:        "testuser",
+		Email:           "test@example.com",
+		EmailVerifiedAt: &verifiedAt,
+		Version:         1,
+	}
+}
+
+var testIdentity = oidctest.Identity{
+	Subject:       "external-1",
+	Email:         "Test@Example.com",
+	EmailVerified: true,
+	Name:          "Test User",
+}
+
+func (s *ServiceTestSuite) TestOIDCStart_StoresHashedState() {
+	s.stateRepo.On("DeleteExpired", mock.AnythingOfType("time.Time")).Return(nil)
+	s.stateRepo.On("Create", mock.AnythingOfType("*model.OIDCLoginState")).Return(nil)
+
+	// Execute
+	result, err := s.oidcService.Start("test")
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Contains(s.T(), result.AuthorizationURL, s.oidcServer.Issuer()+"/authorize?")
+	stored := s.stateRepo.Calls[1].Arguments.Get(0).(*model.OIDCLoginState)
+	assert.Equal(s.T(), hashSecret(result.State), stored.StateHash)
+	assert.Equal(s.T(), "test", stored.Provider)
+	assert.NotEmpty(s.T(), stored.Nonce)
+	assert.NotEmpty(s.T(), stored.CodeVerifier)
+	assert.WithinDuration(s.T(), time.Now().Add(10*time.Minute), stored.ExpiresAt, time.Minute)
+}
+
+func (s *ServiceTestSuite) TestOIDCStart_UnknownProvider() {
+	// Execute
+	result, err := s.oidcService.Start("unknown")
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrUnknownOIDCProvider)
+	assert.Nil(s.T(), result)
+	s.stateRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestOIDCCallback_LinksVerifiedEmail() {
+	user := s.newVerifiedUser()
+	req := s.startOIDCLogin(testIdentity)
+
+	s.identityRepo.On("GetByProviderSubject", "test", "external-1").Return(nil, gorm.ErrRecordNotFound)
+	s.userRepo.On("GetByEmail", "Test@Example.com").Return(user, nil)
+	s.identityRepo.On("Create", mock.AnythingOfType("*model.ExternalIdentity")).Return(nil)
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	s.sessionRepo.On("Create", mock.AnythingOfType("*model.Session")).Return(nil)
+	s.allowLogins()
+
+	// Execute
+	result, err := s.oidcService.Callback(s.ctx, "test", req, testClient)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), result.AccessToken)
+	identity := s.identityRepo.Calls[1].Arguments.Get(0).(*model.ExternalIdentity)
+	assert.Equal(s.T(), uint(1), identity.UserID)
+	assert.Equal(s.T(), "test", identity.Provider)
+	assert.Equal(s.T(), "external-1", identity.Subject)
+	s.sessionRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestOIDCCallback_ExistingIdentity() {
+	user := s.newVerifiedUser()
+	req := s.startOIDCLogin(oidctest.Identity{Subject: "external-1"})
+
+	s.identityRepo.On("GetByProviderSubject", "test", "external-1").Return(&model.ExternalIdentity{ID: 7, UserID: 1}, nil)
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.identityRepo.On("TouchLastLogin", uint(7), mock.AnythingOfType("time.Time")).Return(nil)
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+	s.sessionRepo.On("Create", mock.AnythingOfType("*model.Session")).Return(nil)
+	s.allowLogins()
+
+	// Execute
+	result, err := s.oidcService.Callback(s.ctx, "test", req, testClient)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), result.AccessToken)
+	s.identityRepo.AssertExpectations(s.T())
+	s.userRepo.AssertNotCalled(s.T(), "GetByEmail", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestOIDCCallback_UnverifiedLocalEmail() {
+	user := s.newVerifiedUser()
+	user.EmailVerifiedAt = nil
+	req := s.startOIDCLogin(testIdentity)
+
+	s.identityRepo.On("GetByProviderSubject", "test", "external-1").Return(nil, gorm.ErrRecordNotFound)
+	s.userRepo.On("GetByEmail", "Test@Example.com").Return(user, nil)
+
+	// Execute
+	result, err := s.oidcService.Callback(s.ctx, "test", req, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrNoLinkedAccount)
+	assert.Nil(s.T(), result)
+	s.identityRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+	s.sessionRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestOIDCCallback_UnverifiedProviderEmail() {
+	identity := testIdentity
+	identity.EmailVerified = false
+	req := s.startOIDCLogin(identity)
+
+	s.identityRepo.On("GetByProviderSubject", "test", "external-1").Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	result, err := s.oidcService.Callback(s.ctx, "test", req, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrNoLinkedAccount)
+	assert.Nil(s.T(), result)
+	s.userRepo.AssertNotCalled(s.T(), "GetByEmail", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestOIDCCallback_MFAEnabledReturnsChallenge() {
+	user := s.newVerifiedUser()
+	credential, _ := s.newMFACredential(true)
+	req := s.startOIDCLogin(oidctest.Identity{Subject: "external-1"})
+
+	s.identityRepo.On("GetByProviderSubject", "test", "external-1").Return(&model.ExternalIdentity{ID: 7, UserID: 1}, nil)
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.identityRepo.On("TouchLastLogin", uint(7), mock.AnythingOfType("time.Time")).Return(nil)
+	s.mfaRepo.On("GetByUserID", uint(1)).Return(credential, nil)
+	s.allowLogins()
+
+	// Execute
+	result, err := s.oidcService.Callback(s.ctx, "test", req, testClient)
+
+	// Assert: the second factor still applies
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), result.MFARequired)
+	assert.Empty(s.T(), result.AccessToken)
+	s.sessionRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestOIDCCallback_InvalidState() {
+	s.stateRepo.On("Consume", hashSecret("unknown")).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	result, err := s.oidcService.Callback(s.ctx, "test", &model.OIDCCallbackRequest{Code: "code", State: "unknown"}, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidOIDCState)
+	assert.Nil(s.T(), result)
+}
+
+func (s *ServiceTestSuite) TestOIDCCallback_ExpiredState() {
+	s.stateRepo.On("Consume", hashSecret("state-1")).Return(&model.OIDCLoginState{
+		Provider:  "test",
+		ExpiresAt: time.Now().Add(-time.Second),
+	}, nil)
+
+	// Execute
+	result, err := s.oidcService.Callback(s.ctx, "test", &model.OIDCCallbackRequest{Code: "code", State: "state-1"}, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidOIDCState)
+	assert.Nil(s.T(), result)
+}
+
+func (s *ServiceTestSuite) TestOIDCCallback_StateFromAnotherProvider() {
+	s.stateRepo.On("Consume", hashSecret("state-1")).Return(&model.OIDCLoginState{
+		Provider:  "other",
+		ExpiresAt: time.Now().Add(time.Minute),
+	}, nil)
+
+	// Execute
+	result, err := s.oidcService.Callback(s.ctx, "test", &model.OIDCCallbackRequest{Code: "code", State: "state-1"}, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidOIDCState)
+	assert.Nil(s.T(), result)
+}
+
+func (s *ServiceTestSuite) TestOIDCCallback_ExchangeFails() {
+	req := s.startOIDCLogin(testIdentity)
+	req.Code = "forged"
+
+	// Execute
+	result, err := s.oidcService.Callback(s.ctx, "test", req, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrOIDCLoginFailed)
+	assert.Nil(s.T(), result)
+	s.identityRepo.AssertNotCalled(s.T(), "GetByProviderSubject", mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestListIdentities() {
+	lastLogin := time.Now()
+	s.userRepo.On("GetByID", uint(1)).Return(s.newVerifiedUser(), nil)
+	s.identityRepo.On("ListByUser", uint(1)).Return([]*model.ExternalIdentity{
+		{ID: 7, UserID: 1, Provider: "test", Subject: "external-1", Email: "test@example.com", LastLoginAt: lastLogin},
+	}, nil)
+
+	// Execute
+	result, err := s.oidcService.ListIdentities(s.ctx, 1)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result, 1)
+	assert.Equal(s.T(), "test", result[0].Provider)
+	assert.Equal(s.T(), lastLogin, result[0].LastLoginAt)
+}
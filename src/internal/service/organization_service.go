@@ -0,0 +1,347 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOrganizationNotFound      = errors.New("organization not found")
+	ErrSlugTaken                 = errors.New("slug already exists")
+	ErrInvalidSlug               = errors.New("slug may only contain lowercase letters, digits and inner hyphens")
+	ErrNotOrganizationMember     = errors.New("not a member of this organization")
+	ErrOrganizationAdminRequired = errors.New("only owners and admins can manage members")
+	ErrOrganizationOwnerRequired = errors.New("only owners can manage owners")
+	ErrLastOwner                 = errors.New("an organization must keep at least one owner")
+	ErrMemberExists              = errors.New("user is already a member")
+	ErrMemberNotFound            = errors.New("member not found")
+	ErrMemberUserNotFound        = errors.New("user not found")
+	ErrHomeOrganization          = errors.New("users cannot be removed from the organization they belong to")
+)
+
+// slugPattern matches slugs that are valid DNS labels, so that they can be
+// used as subdomains.
+var slugPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=OrganizationService --output=./mocks/service --outpkg=service --filename=organization_service.go --structname=MockOrganizationService --with-expecter=false
+type OrganizationService interface {
+	Create(principal *model.Principal, req *model.CreateOrganizationRequest) (*model.OrganizationResponse, error)
+	Resolve(slug string) (*model.Organization, error)
+	ListForUser(ctx context.Context, userID uint) ([]*model.OrganizationResponse, error)
+	IsMember(ctx context.Context, organizationID, userID uint) (bool, error)
+	ListMembers(ctx context.Context, principal *model.Principal, organizationID uint) ([]*model.MembershipResponse, error)
+	AddMember(ctx context.Context, principal *model.Principal, organizationID uint, req *model.AddMemberRequest) (*model.MembershipResponse, error)
+	UpdateMember(ctx context.Context, principal *model.Principal, organizationID, userID uint, req *model.UpdateMemberRequest) (*model.MembershipResponse, error)
+	RemoveMember(ctx context.Context, principal *model.Principal, organizationID, userID uint) error
+}
+
+type organizationService struct {
+	organizationRepo repository.OrganizationRepository
+	membershipRepo   repository.MembershipRepository
+	userRepo         repository.UserRepository
+}
+
+func NewOrganizationService(
+	organizationRepo repository.OrganizationRepository,
+	membershipRepo repository.MembershipRepository,
+	userRepo repository.UserRepository,
+) OrganizationService {
+	return &organizationService{
+		organizationRepo: organizationRepo,
+		membershipRepo:   membershipRepo,
+		userRepo:         userRepo,
+	}
+}
+
+// Create creates an organization owned by the caller. Organizations created
+// with a service API key start without members.
+func (s *organizationService) Create(principal *model.Principal, req *model.CreateOrganizationRequest) (*model.OrganizationResponse, error) {
+	if !slugPattern.MatchString(req.Slug) {
+		return nil, ErrInvalidSlug
+	}
+
+	existing, err := s.organizationRepo.GetBySlug(req.Slug)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrSlugTaken
+	}
+
+	organization := &model.Organization{
+		Name: req.Name,
+		Slug: req.Slug,
+	}
+	if err := s.organizationRepo.Create(organization, principal.UserID); err != nil {
+		return nil, err
+	}
+
+	response := toOrganizationResponse(organization)
+	if principal.UserID != 0 {
+		response.Role = model.OrganizationRoleOwner
+	}
+	return response, nil
+}
+
+// Resolve finds the organization with the slug, for tenant resolution.
+func (s *organizationService) Resolve(slug string) (*model.Organization, error) {
+	organization, err := s.organizationRepo.GetBySlug(slug)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrOrganizationNotFound
+	}
+	return organization, err
+}
+
+// ListForUser returns the organizations the user is a member of, with the
+// role of the user in each.
+func (s *organizationService) ListForUser(ctx context.Context, userID uint) ([]*model.OrganizationResponse, error) {
+	memberships, err := s.membershipRepo.WithContext(tenant.Unscoped(ctx)).ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*model.OrganizationResponse, 0, len(memberships))
+	for _, membership := range memberships {
+		if membership.Organization == nil {
+			continue
+		}
+		response := toOrganizationResponse(membership.Organization)
+		response.Role = membership.Role
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+func (s *organizationService) IsMember(ctx context.Context, organizationID, userID uint) (bool, error) {
+	_, err := s.members(ctx, organizationID).Get(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ListMembers lists the members of an organization to its members.
+func (s *organizationService) ListMembers(ctx context.Context, principal *model.Principal, organizationID uint) ([]*model.MembershipResponse, error) {
+	members, _, err := s.authorize(ctx, principal, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	memberships, err := members.List()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*model.MembershipResponse, 0, len(memberships))
+	for _, membership := range memberships {
+		responses = append(responses, toMembershipResponse(membership))
+	}
+	return responses, nil
+}
+
+// AddMember adds a user of the organization of the request with the given
+// role, member by default. Owners and admins can add members; only owners
+// can add owners.
+func (s *organizationService) AddMember(ctx context.Context, principal *model.Principal, organizationID uint, req *model.AddMemberRequest) (*model.MembershipResponse, error) {
+	role := req.Role
+	if role == "" {
+		role = model.OrganizationRoleMember
+	}
+
+	members, callerRole, err := s.authorize(ctx, principal, organizationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkManage(callerRole, role); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.userRepo.WithContext(ctx).GetByID(req.UserID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMemberUserNotFound
+		}
+		return nil, err
+	}
+
+	existing, err := members.Get(req.UserID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrMemberExists
+	}
+
+	membership := &model.Membership{
+		UserID: req.UserID,
+		Role:   role,
+	}
+	if err := members.Create(membership); err != nil {
+		return nil, err
+	}
+	return toMembershipResponse(membership), nil
+}
+
+// UpdateMember changes the role of a member. Only owners can promote to or
+// demote from owner, and the last owner cannot be demoted.
+func (s *organizationService) UpdateMember(ctx context.Context, principal *model.Principal, organizationID, userID uint, req *model.UpdateMemberRequest) (*model.MembershipResponse, error) {
+	members, callerRole, err := s.authorize(ctx, principal, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	membership, err := s.getMember(members, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkManage(callerRole, membership.Role); err != nil {
+		return nil, err
+	}
+	if err := checkManage(callerRole, req.Role); err != nil {
+		return nil, err
+	}
+	if membership.Role == model.OrganizationRoleOwner && req.Role != model.OrganizationRoleOwner {
+		if err := s.checkNotLastOwner(members); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := members.UpdateRole(userID, req.Role)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrMemberNotFound
+	}
+	membership.Role = req.Role
+	return toMembershipResponse(membership), nil
+}
+
+// RemoveMember removes a member from an organization other than the one the
+// user belongs to. The last owner cannot be removed.
+func (s *organizationService) RemoveMember(ctx context.Context, principal *model.Principal, organizationID, userID uint) error {
+	members, callerRole, err := s.authorize(ctx, principal, organizationID)
+	if err != nil {
+		return err
+	}
+
+	membership, err := s.getMember(members, userID)
+	if err != nil {
+		return err
+	}
+	if err := checkManage(callerRole, membership.Role); err != nil {
+		return err
+	}
+	if membership.Role == model.OrganizationRoleOwner {
+		if err := s.checkNotLastOwner(members); err != nil {
+			return err
+		}
+	}
+
+	// Only users who belong to the organization are found in it
+	_, err = s.userRepo.WithContext(tenant.WithOrganization(ctx, organizationID)).GetByID(userID)
+	if err == nil {
+		return ErrHomeOrganization
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	deleted, err := members.Delete(userID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrMemberNotFound
+	}
+	return nil
+}
+
+// members returns the membership repository bound to the organization.
+func (s *organizationService) members(ctx context.Context, organizationID uint) repository.MembershipRepository {
+	return s.membershipRepo.WithContext(tenant.WithOrganization(ctx, organizationID))
+}
+
+// authorize checks that the organization exists and the caller is a member
+// of it, and returns its members and the role of the caller. Service API
+// keys act as owners of the organization they are bound to only.
+func (s *organizationService) authorize(ctx context.Context, principal *model.Principal, organizationID uint) (repository.MembershipRepository, string, error) {
+	if _, err := s.organizationRepo.GetByID(organizationID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrOrganizationNotFound
+		}
+		return nil, "", err
+	}
+
+	members := s.members(ctx, organizationID)
+	if principal.UserID == 0 {
+		if principal.OrganizationID != organizationID {
+			return nil, "", ErrNotOrganizationMember
+		}
+		return members, model.OrganizationRoleOwner, nil
+	}
+
+	caller, err := members.Get(principal.UserID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", ErrNotOrganizationMember
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return members, caller.Role, nil
+}
+
+func (s *organizationService) getMember(members repository.MembershipRepository, userID uint) (*model.Membership, error) {
+	membership, err := members.Get(userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrMemberNotFound
+	}
+	return membership, err
+}
+
+func (s *organizationService) checkNotLastOwner(members repository.MembershipRepository) error {
+	owners, err := members.CountByRole(model.OrganizationRoleOwner)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return ErrLastOwner
+	}
+	return nil
+}
+
+// checkManage reports whether a member with callerRole may grant or change
+// the role.
+func checkManage(callerRole, role string) error {
+	switch {
+	case callerRole != model.OrganizationRoleOwner && callerRole != model.OrganizationRoleAdmin:
+		return ErrOrganizationAdminRequired
+	case role == model.OrganizationRoleOwner && callerRole != model.OrganizationRoleOwner:
+		return ErrOrganizationOwnerRequired
+	default:
+		return nil
+	}
+}
+
+func toOrganizationResponse(organization *model.Organization) *model.OrganizationResponse {
+	return &model.OrganizationResponse{
+		ID:        organization.ID,
+		Name:      organization.Name,
+		Slug:      organization.Slug,
+		CreatedAt: organization.CreatedAt,
+	}
+}
+
+func toMembershipResponse(membership *model.Membership) *model.MembershipResponse {
+	return &model.MembershipResponse{
+		UserID:    membership.UserID,
+		Role:      membership.Role,
+		CreatedAt: membership.CreatedAt,
+	}
+}
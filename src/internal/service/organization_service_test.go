@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+	"gorm.io/gorm"
+)
+
+// expectMembers expects the members of organization 5, in which the
+// caller, user 1, has the given role.
+func (s *ServiceTestSuite) expectMembers(callerRole string) {
+	s.orgRepo.On("GetByID", uint(5)).Return(&model.Organization{ID: 5, Name: "Acme", Slug: "acme"}, nil)
+	s.membershipRepo.On("WithContext", mock.MatchedBy(func(ctx context.Context) bool {
+		organizationID, _ := tenant.OrganizationFromContext(ctx)
+		return organizationID == 5
+	})).Return(s.membershipRepo)
+	s.membershipRepo.On("Get", uint(1)).Return(&model.Membership{OrganizationID: 5, UserID: 1, Role: callerRole}, nil).Maybe()
+}
+
+// Test Create
+func (s *ServiceTestSuite) TestCreateOrganization_Success() {
+	s.orgRepo.On("GetBySlug", "acme").Return(nil, gorm.ErrRecordNotFound)
+	s.orgRepo.On("Create", mock.AnythingOfType("*model.Organization"), uint(1)).Return(nil)
+
+	// Execute
+	result, err := s.organizations.Create(&model.Principal{UserID: 1}, &model.CreateOrganizationRequest{Name: "Acme", Slug: "acme"})
+
+	// Assert: the creator owns the organization
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "acme", result.Slug)
+	assert.Equal(s.T(), model.OrganizationRoleOwner, result.Role)
+}
+
+func (s *ServiceTestSuite) TestCreateOrganization_InvalidSlug() {
+	for _, slug := range []string{"Acme", "-acme", "acme-", "ac me", "acme.io"} {
+		// Execute
+		_, err := s.organizations.Create(&model.Principal{UserID: 1}, &model.CreateOrganizationRequest{Name: "Acme", Slug: slug})
+
+		// Assert
+		assert.ErrorIs(s.T(), err, ErrInvalidSlug, slug)
+	}
+	s.orgRepo.AssertNotCalled(s.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestCreateOrganization_SlugTaken() {
+	s.orgRepo.On("GetBySlug", "acme").Return(&model.Organization{ID: 5, Slug: "acme"}, nil)
+
+	// Execute
+	_, err := s.organizations.Create(&model.Principal{UserID: 1}, &model.CreateOrganizationRequest{Name: "Acme", Slug: "acme"})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrSlugTaken)
+}
+
+// Test Resolve
+func (s *ServiceTestSuite) TestResolveOrganization_NotFound() {
+	s.orgRepo.On("GetBySlug", "globex").Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	_, err := s.organizations.Resolve("globex")
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrOrganizationNotFound)
+}
+
+// Test ListForUser
+func (s *ServiceTestSuite) TestListOrganizationsForUser() {
+	s.membershipRepo.On("WithContext", mock.Anything).Return(s.membershipRepo)
+	s.membershipRepo.On("ListByUser", uint(1)).Return([]*model.Membership{
+		{OrganizationID: 1, Role: model.OrganizationRoleMember, Organization: &model.Organization{ID: 1, Slug: "default"}},
+		{OrganizationID: 5, Role: model.OrganizationRoleOwner, Organization: &model.Organization{ID: 5, Slug: "acme"}},
+	}, nil)
+
+	// Execute
+	result, err := s.organizations.ListForUser(s.ctx, 1)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result, 2)
+	assert.Equal(s.T(), "acme", result[1].Slug)
+	assert.Equal(s.T(), model.OrganizationRoleOwner, result[1].Role)
+}
+
+// Test ListMembers
+func (s *ServiceTestSuite) TestListMembers_Success() {
+	s.expectMembers(model.OrganizationRoleMember)
+	s.membershipRepo.On("List").Return([]*model.Membership{
+		{UserID: 1, Role: model.OrganizationRoleMember, CreatedAt: time.Now()},
+		{UserID: 2, Role: model.OrganizationRoleOwner, CreatedAt: time.Now()},
+	}, nil)
+
+	// Execute
+	result, err := s.organizations.ListMembers(s.ctx, &model.Principal{UserID: 1}, 5)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result, 2)
+}
+
+func (s *ServiceTestSuite) TestListMembers_NotMember() {
+	s.orgRepo.On("GetByID", uint(5)).Return(&model.Organization{ID: 5}, nil)
+	s.membershipRepo.On("WithContext", mock.Anything).Return(s.membershipRepo)
+	s.membershipRepo.On("Get", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	_, err := s.organizations.ListMembers(s.ctx, &model.Principal{UserID: 1}, 5)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrNotOrganizationMember)
+	s.membershipRepo.AssertNotCalled(s.T(), "List")
+}
+
+func (s *ServiceTestSuite) TestListMembers_OrganizationNotFound() {
+	s.orgRepo.On("GetByID", uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	_, err := s.organizations.ListMembers(s.ctx, &model.Principal{UserID: 1}, 9)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrOrganizationNotFound)
+}
+
+// Test AddMember
+func (s *ServiceTestSuite) TestAddMember_Success() {
+	s.expectMembers(model.OrganizationRoleAdmin)
+	s.userRepo.On("GetByID", uint(2)).Return(&model.User{ID: 2, OrganizationID: 1}, nil)
+	s.membershipRepo.On("Get", uint(2)).Return(nil, gorm.ErrRecordNotFound)
+	s.membershipRepo.On("Create", mock.AnythingOfType("*model.Membership")).Return(nil)
+
+	// Execute
+	result, err := s.organizations.AddMember(s.ctx, &model.Principal{UserID: 1}, 5, &model.AddMemberRequest{UserID: 2})
+
+	// Assert: members are added with the member role by default
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), uint(2), result.UserID)
+	assert.Equal(s.T(), model.OrganizationRoleMember, result.Role)
+}
+
+func (s *ServiceTestSuite) TestAddMember_Errors() {
+	cases := []struct {
+		callerRole string
+		role       string
+		err        error
+	}{
+		{model.OrganizationRoleMember, "", ErrOrganizationAdminRequired},
+		{model.OrganizationRoleAdmin, model.OrganizationRoleOwner, ErrOrganizationOwnerRequired},
+	}
+
+	for _, tc := range cases {
+		s.orgRepo.ExpectedCalls = nil
+		s.membershipRepo.ExpectedCalls = nil
+		s.expectMembers(tc.callerRole)
+
+		// Execute
+		_, err := s.organizations.AddMember(s.ctx, &model.Principal{UserID: 1}, 5, &model.AddMemberRequest{UserID: 2, Role: tc.role})
+
+		// Assert
+		assert.ErrorIs(s.T(), err, tc.err)
+	}
+	s.membershipRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestAddMember_AlreadyMember() {
+	s.expectMembers(model.OrganizationRoleOwner)
+	s.userRepo.On("GetByID", uint(2)).Return(&model.User{ID: 2}, nil)
+	s.membershipRepo.On("Get", uint(2)).Return(&model.Membership{UserID: 2}, nil)
+
+	// Execute
+	_, err := s.organizations.AddMember(s.ctx, &model.Principal{UserID: 1}, 5, &model.AddMemberRequest{UserID: 2})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrMemberExists)
+}
+
+func (s *ServiceTestSuite) TestAddMember_ServiceKey() {
+	s.expectMembers("")
+	s.userRepo.On("GetByID", uint(2)).Return(&model.User{ID: 2}, nil)
+	s.membershipRepo.On("Get", uint(2)).Return(nil, gorm.ErrRecordNotFound)
+	s.membershipRepo.On("Create", mock.AnythingOfType("*model.Membership")).Return(nil)
+
+	// Execute
+	result, err := s.organizations.AddMember(s.ctx, &model.Principal{APIKeyID: 4, Service: "provisioning", OrganizationID: 5}, 5, &model.AddMemberRequest{UserID: 2, Role: model.OrganizationRoleOwner})
+
+	// Assert: service keys are trusted like owners
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.OrganizationRoleOwner, result.Role)
+}
+
+func (s *ServiceTestSuite) TestAddMember_ServiceKeyOfOtherOrganization() {
+	s.expectMembers("")
+
+	// Execute
+	_, err := s.organizations.AddMember(s.ctx, &model.Principal{APIKeyID: 4, Service: "provisioning", OrganizationID: 6}, 5, &model.AddMemberRequest{UserID: 2, Role: model.OrganizationRoleOwner})
+
+	// Assert: service keys are only owners of their own organization
+	assert.ErrorIs(s.T(), err, ErrNotOrganizationMember)
+	s.membershipRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+// Test UpdateMember
+func (s *ServiceTestSuite) TestUpdateMember_Success() {
+	s.expectMembers(model.OrganizationRoleOwner)
+	s.membershipRepo.On("Get", uint(2)).Return(&model.Membership{UserID: 2, Role: model.OrganizationRoleMember}, nil)
+	s.membershipRepo.On("UpdateRole", uint(2), model.OrganizationRoleAdmin).Return(true, nil)
+
+	// Execute
+	result, err := s.organizations.UpdateMember(s.ctx, &model.Principal{UserID: 1}, 5, 2, &model.UpdateMemberRequest{Role: model.OrganizationRoleAdmin})
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.OrganizationRoleAdmin, result.Role)
+}
+
+func (s *ServiceTestSuite) TestUpdateMember_LastOwner() {
+	s.expectMembers(model.OrganizationRoleOwner)
+	s.membershipRepo.On("CountByRole", model.OrganizationRoleOwner).Return(int64(1), nil)
+
+	// Execute
+	_, err := s.organizations.UpdateMember(s.ctx, &model.Principal{UserID: 1}, 5, 1, &model.UpdateMemberRequest{Role: model.OrganizationRoleMember})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrLastOwner)
+	s.membershipRepo.AssertNotCalled(s.T(), "UpdateRole", mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestUpdateMember_AdminCannotDemoteOwner() {
+	s.expectMembers(model.OrganizationRoleAdmin)
+	s.membershipRepo.On("Get", uint(2)).Return(&model.Membership{UserID: 2, Role: model.OrganizationRoleOwner}, nil)
+
+	// Execute
+	_, err := s.organizations.UpdateMember(s.ctx, &model.Principal{UserID: 1}, 5, 2, &model.UpdateMemberRequest{Role: model.OrganizationRoleMember})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrOrganizationOwnerRequired)
+}
+
+func (s *ServiceTestSuite) TestUpdateMember_NotFound() {
+	s.expectMembers(model.OrganizationRoleOwner)
+	s.membershipRepo.On("Get", uint(2)).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	_, err := s.organizations.UpdateMember(s.ctx, &model.Principal{UserID: 1}, 5, 2, &model.UpdateMemberRequest{Role: model.OrganizationRoleAdmin})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrMemberNotFound)
+}
+
+// Test RemoveMember
+func (s *ServiceTestSuite) TestRemoveMember_Success() {
+	s.expectMembers(model.OrganizationRoleAdmin)
+	s.membershipRepo.On("Get", uint(2)).Return(&model.Membership{UserID: 2, Role: model.OrganizationRoleMember}, nil)
+	s.userRepo.On("GetByID", uint(2)).Return(nil, gorm.ErrRecordNotFound)
+	s.membershipRepo.On("Delete", uint(2)).Return(true, nil)
+
+	// Execute
+	err := s.organizations.RemoveMember(s.ctx, &model.Principal{UserID: 1}, 5, 2)
+
+	// Assert
+	assert.NoError(s.T(), err)
+}
+
+func (s *ServiceTestSuite) TestRemoveMember_HomeOrganization() {
+	s.expectMembers(model.OrganizationRoleAdmin)
+	s.membershipRepo.On("Get", uint(2)).Return(&model.Membership{UserID: 2, Role: model.OrganizationRoleMember}, nil)
+	s.userRepo.On("GetByID", uint(2)).Return(&model.User{ID: 2, OrganizationID: 5}, nil)
+
+	// Execute
+	err := s.organizations.RemoveMember(s.ctx, &model.Principal{UserID: 1}, 5, 2)
+
+	// Assert: users are owned by the organization they belong to, and
+	// looked up in it
+	assert.ErrorIs(s.T(), err, ErrHomeOrganization)
+	s.membershipRepo.AssertNotCalled(s.T(), "Delete", mock.Anything)
+	organizationID, _ := tenant.OrganizationFromContext(s.userRepo.Calls[0].Arguments.Get(0).(context.Context))
+	assert.Equal(s.T(), uint(5), organizationID)
+}
+
+func (s *ServiceTestSuite) TestRemoveMember_DeleteFails() {
+	s.expectMembers(model.OrganizationRoleOwner)
+	s.membershipRepo.On("Get", uint(2)).Return(&model.Membership{UserID: 2, Role: model.OrganizationRoleMember}, nil)
+	s.userRepo.On("GetByID", uint(2)).Return(nil, gorm.ErrRecordNotFound)
+	s.membershipRepo.On("Delete", uint(2)).Return(false, errors.New("database error"))
+
+	// Execute
+	err := s.organizations.RemoveMember(s.ctx, &model.Principal{UserID: 1}, 5, 2)
+
+	// Assert
+	assert.Error(s.T(), err)
+}
+
+// Test IsMember
+func (s *ServiceTestSuite) TestIsMember() {
+	s.membershipRepo.On("WithContext", mock.Anything).Return(s.membershipRepo)
+	s.membershipRepo.On("Get", uint(1)).Return(&model.Membership{UserID: 1}, nil)
+	s.membershipRepo.On("Get", uint(2)).Return(nil, gorm.ErrRecordNotFound)
+
+	member, err := s.organizations.IsMember(s.ctx, 5, 1)
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), member)
+
+	member, err = s.organizations.IsMember(s.ctx, 5, 2)
+	assert.NoError(s.T(), err)
+	assert.False(s.T(), member)
+}
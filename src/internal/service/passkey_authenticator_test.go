@@ -0,0 +1,195 @@
+package service
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+// testCredential is a passkey held by a testAuthenticator. SignCount is the
+// last counter value it signed and may be changed to simulate a cloned key.
+type testCredential struct {
+	ID         []byte
+	UserHandle []byte
+	SignCount  uint32
+	RPID       string
+
+	key *ecdsa.PrivateKey
+}
+
+// testAuthenticator is a software authenticator that creates ES256
+// passkeys and answers registration and login options like a browser would.
+// With SkipUserVerification it reports that the user was only present;
+// with NoSignCount it always signs a zero counter, like many synced
+// passkeys, and with BackupEligible it reports that its passkeys are synced.
+type testAuthenticator struct {
+	Origin               string
+	SkipUserVerification bool
+	NoSignCount          bool
+	BackupEligible       bool
+	Credentials          []*testCredential
+}
+
+func newTestAuthenticator(origin string) *testAuthenticator {
+	return &testAuthenticator{Origin: origin}
+}
+
+// Register creates a credential for the options, as
+// navigator.credentials.create() would, unless one of the excluded
+// credentials is already held.
+func (a *testAuthenticator) Register(options *protocol.PublicKeyCredentialCreationOptions) (*protocol.CredentialCreationResponse, error) {
+	for _, excluded := range options.CredentialExcludeList {
+		if a.find(options.RelyingParty.ID, excluded.CredentialID) != nil {
+			return nil, errors.New("authenticator already registered")
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	userHandle, _ := options.User.ID.(protocol.URLEncodedBase64)
+	credential := &testCredential{ID: id, UserHandle: userHandle, RPID: options.RelyingParty.ID, key: key}
+
+	clientDataJSON, err := a.clientData(protocol.CreateCeremony, options.Challenge)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: key.X.FillBytes(make([]byte, 32)),
+		YCoord: key.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	authData := a.authenticatorData(credential, byte(protocol.FlagAttestedCredentialData))
+	authData = append(authData, make([]byte, 16)...) // AAGUID
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(id)))
+	authData = append(authData, id...)
+	authData = append(authData, publicKey...)
+
+	attestationObject, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	a.Credentials = append(a.Credentials, credential)
+	return &protocol.CredentialCreationResponse{
+		PublicKeyCredential: protocol.PublicKeyCredential{
+			Credential: protocol.Credential{ID: protocol.URLEncodedBase64(id).String(), Type: "public-key"},
+			RawID:      id,
+		},
+		AttestationResponse: protocol.AuthenticatorAttestationResponse{
+			AuthenticatorResponse: protocol.AuthenticatorResponse{ClientDataJSON: clientDataJSON},
+			AttestationObject:     attestationObject,
+			Transports:            []string{"internal"},
+		},
+	}, nil
+}
+
+// Login signs the challenge of the options with the first allowed
+// credential, or with the newest credential for the relying party if any
+// is allowed, as navigator.credentials.get() would.
+func (a *testAuthenticator) Login(options *protocol.PublicKeyCredentialRequestOptions) (*protocol.CredentialAssertionResponse, error) {
+	var credential *testCredential
+	if len(options.AllowedCredentials) == 0 {
+		for i := len(a.Credentials) - 1; i >= 0 && credential == nil; i-- {
+			if a.Credentials[i].RPID == options.RelyingPartyID {
+				credential = a.Credentials[i]
+			}
+		}
+	}
+	for _, allowed := range options.AllowedCredentials {
+		if credential = a.find(options.RelyingPartyID, allowed.CredentialID); credential != nil {
+			break
+		}
+	}
+	if credential == nil {
+		return nil, errors.New("no credential for the relying party")
+	}
+
+	clientDataJSON, err := a.clientData(protocol.AssertCeremony, options.Challenge)
+	if err != nil {
+		return nil, err
+	}
+	authData := a.authenticatorData(credential, 0)
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(append([]byte(nil), authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, credential.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return &protocol.CredentialAssertionResponse{
+		PublicKeyCredential: protocol.PublicKeyCredential{
+			Credential: protocol.Credential{ID: protocol.URLEncodedBase64(credential.ID).String(), Type: "public-key"},
+			RawID:      credential.ID,
+		},
+		AssertionResponse: protocol.AuthenticatorAssertionResponse{
+			AuthenticatorResponse: protocol.AuthenticatorResponse{ClientDataJSON: clientDataJSON},
+			AuthenticatorData:     authData,
+			Signature:             signature,
+			UserHandle:            credential.UserHandle,
+		},
+	}, nil
+}
+
+func (a *testAuthenticator) find(rpID string, id []byte) *testCredential {
+	for _, credential := range a.Credentials {
+		if credential.RPID == rpID && bytes.Equal(credential.ID, id) {
+			return credential
+		}
+	}
+	return nil
+}
+
+func (a *testAuthenticator) clientData(ceremony protocol.CeremonyType, challenge protocol.URLEncodedBase64) ([]byte, error) {
+	return json.Marshal(protocol.CollectedClientData{
+		Type:      ceremony,
+		Challenge: challenge.String(),
+		Origin:    a.Origin,
+	})
+}
+
+// authenticatorData returns the authenticator data up to the sign count,
+// advancing the counter of the credential.
+func (a *testAuthenticator) authenticatorData(credential *testCredential, flags byte) []byte {
+	flags |= byte(protocol.FlagUserPresent)
+	if !a.SkipUserVerification {
+		flags |= byte(protocol.FlagUserVerified)
+	}
+	if a.BackupEligible {
+		flags |= byte(protocol.FlagBackupEligible)
+	}
+	if !a.NoSignCount {
+		credential.SignCount++
+	}
+
+	rpIDHash := sha256.Sum256([]byte(credential.RPID))
+	data := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(data, credential.SignCount)
+}
@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidPasskeyChallenge = errors.New("invalid or expired passkey challenge")
+	ErrInvalidPasskey          = errors.New("passkey could not be verified")
+	ErrPasskeyNotFound         = errors.New("passkey not found")
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=PasskeyService --output=./mocks/service --outpkg=service --filename=passkey_service.go --structname=MockPasskeyService --with-expecter=false
+type PasskeyService interface {
+	BeginRegistration(ctx context.Context, userID uint) (*protocol.PublicKeyCredentialCreationOptions, error)
+	FinishRegistration(userID uint, req *model.RegisterPasskeyRequest) (*model.PasskeyResponse, error)
+	BeginLogin() (*protocol.PublicKeyCredentialRequestOptions, error)
+	FinishLogin(ctx context.Context, req *model.PasskeyLoginRequest, client *model.ClientInfo) (*model.LoginResponse, error)
+	ListPasskeys(ctx context.Context, userID uint) ([]*model.PasskeyResponse, error)
+	DeletePasskey(userID, id uint) error
+}
+
+type passkeyService struct {
+	passkeyRepo   repository.PasskeyRepository
+	challengeRepo repository.PasskeyChallengeRepository
+	userRepo      repository.UserRepository
+	auth          AuthService
+	webAuthn      *webauthn.WebAuthn
+	challengeTTL  time.Duration
+}
+
+// NewPasskeyService verifies ceremonies with go-webauthn. Passkeys are
+// discoverable and the authenticator must verify the user, so a passkey
+// stands in for both the password and the second factor. Attestation is
+// not requested.
+func NewPasskeyService(
+	passkeyRepo repository.PasskeyRepository,
+	challengeRepo repository.PasskeyChallengeRepository,
+	userRepo repository.UserRepository,
+	auth AuthService,
+	conf *config.Config,
+) (PasskeyService, error) {
+	timeout := webauthn.TimeoutConfig{Timeout: conf.WebAuthn.ChallengeTTL, TimeoutUVD: conf.WebAuthn.ChallengeTTL}
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          conf.WebAuthn.RPID,
+		RPDisplayName: conf.WebAuthn.RPName,
+		RPOrigins:     conf.WebAuthn.Origins,
+		AuthenticatorSelection: protocol.AuthenticatorSelection{
+			RequireResidentKey: protocol.ResidentKeyRequired(),
+			ResidentKey:        protocol.ResidentKeyRequirementRequired,
+			UserVerification:   protocol.VerificationRequired,
+		},
+		AttestationPreference: protocol.PreferNoAttestation,
+		Timeouts:              webauthn.TimeoutsConfig{Login: timeout, Registration: timeout},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid webauthn configuration: %w", err)
+	}
+
+	return &passkeyService{
+		passkeyRepo:   passkeyRepo,
+		challengeRepo: challengeRepo,
+		userRepo:      userRepo,
+		auth:          auth,
+		webAuthn:      webAuthn,
+		challengeTTL:  conf.WebAuthn.ChallengeTTL,
+	}, nil
+}
+
+// BeginRegistration starts adding a passkey for the user. The options are
+// passed to navigator.credentials.create() and exclude the passkeys the
+// user already has.
+func (s *passkeyService) BeginRegistration(ctx context.Context, userID uint) (*protocol.PublicKeyCredentialCreationOptions, error) {
+	user, err := s.userRepo.WithContext(ctx).GetByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	passkeys, err := s.passkeyRepo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	owner := &passkeyUser{id: user.ID, name: user.Username, passkeys: passkeys}
+
+	creation, session, err := s.webAuthn.BeginRegistration(owner,
+		webauthn.WithExclusions(webauthn.Credentials(owner.WebAuthnCredentials()).CredentialDescriptors()))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.storeChallenge(session.Challenge, model.PasskeyPurposeRegistration, &userID); err != nil {
+		return nil, err
+	}
+	return &creation.Response, nil
+}
+
+// FinishRegistration verifies the new credential against the challenge
+// issued to the user and stores it under the given name.
+func (s *passkeyService) FinishRegistration(userID uint, req *model.RegisterPasskeyRequest) (*model.PasskeyResponse, error) {
+	parsed, err := req.Credential.Parse()
+	if err != nil {
+		return nil, ErrInvalidPasskey
+	}
+
+	challenge, err := s.consumeChallenge(parsed.Response.CollectedClientData.Challenge, model.PasskeyPurposeRegistration, &userID)
+	if err != nil {
+		return nil, err
+	}
+
+	owner := &passkeyUser{id: userID}
+	session := webauthn.SessionData{
+		Challenge:        parsed.Response.CollectedClientData.Challenge,
+		RelyingPartyID:   s.webAuthn.Config.RPID,
+		UserID:           owner.WebAuthnID(),
+		Expires:          challenge.ExpiresAt,
+		UserVerification: protocol.VerificationRequired,
+		CredParams:       webauthn.CredentialParametersDefault(),
+	}
+	credential, err := s.webAuthn.CreateCredential(owner, session, parsed)
+	if err != nil {
+		log.Printf("Passkey registration for user %d rejected: %v", userID, err)
+		return nil, ErrInvalidPasskey
+	}
+
+	credentialID := protocol.URLEncodedBase64(credential.ID).String()
+	if _, err := s.passkeyRepo.GetByCredentialID(credentialID); err == nil {
+		return nil, ErrInvalidPasskey
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	passkey := &model.Passkey{
+		UserID:         userID,
+		Name:           req.Name,
+		CredentialID:   credentialID,
+		PublicKey:      credential.PublicKey,
+		SignCount:      int64(credential.Authenticator.SignCount),
+		BackupEligible: &credential.Flags.BackupEligible,
+		Transports:     req.Credential.AttestationResponse.Transports,
+	}
+	if err := s.passkeyRepo.Create(passkey); err != nil {
+		return nil, err
+	}
+
+	return toPasskeyResponse(passkey), nil
+}
+
+// BeginLogin starts a login with a passkey. No user is named: the browser
+// offers the passkeys it has for the site and the one picked identifies
+// the user.
+func (s *passkeyService) BeginLogin() (*protocol.PublicKeyCredentialRequestOptions, error) {
+	assertion, session, err := s.webAuthn.BeginDiscoverableLogin()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.storeChallenge(session.Challenge, model.PasskeyPurposeLogin, nil); err != nil {
+		return nil, err
+	}
+	return &assertion.Response, nil
+}
+
+// FinishLogin verifies the assertion and logs in the owner of the passkey
+// through CompletePasskeyLogin. The signature counter has to increase with
+// every login, unless the authenticator keeps none; a counter that goes
+// back means the passkey has been copied, and the login is refused.
+func (s *passkeyService) FinishLogin(ctx context.Context, req *model.PasskeyLoginRequest, client *model.ClientInfo) (*model.LoginResponse, error) {
+	parsed, err := req.Credential.Parse()
+	if err != nil {
+		return nil, ErrInvalidPasskey
+	}
+
+	challenge, err := s.consumeChallenge(parsed.Response.CollectedClientData.Challenge, model.PasskeyPurposeLogin, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	passkey, err := s.passkeyRepo.GetByCredentialID(protocol.URLEncodedBase64(parsed.RawID).String())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrInvalidPasskey
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Passkeys registered before backup eligibility was stored take it from
+	// this login, and keep it from then on
+	if passkey.BackupEligible == nil {
+		backupEligible := parsed.Response.AuthenticatorData.Flags.HasBackupEligible()
+		passkey.BackupEligible = &backupEligible
+	}
+
+	session := webauthn.SessionData{
+		Challenge:        parsed.Response.CollectedClientData.Challenge,
+		RelyingPartyID:   s.webAuthn.Config.RPID,
+		Expires:          challenge.ExpiresAt,
+		UserVerification: protocol.VerificationRequired,
+	}
+	owner := &passkeyUser{id: passkey.UserID, passkeys: []*model.Passkey{passkey}}
+	credential, err := s.webAuthn.ValidateDiscoverableLogin(func(_, _ []byte) (webauthn.User, error) {
+		return owner, nil
+	}, session, parsed)
+	if err != nil {
+		log.Printf("Passkey login with passkey %d rejected: %v", passkey.ID, err)
+		return nil, ErrInvalidPasskey
+	}
+	if credential.Authenticator.CloneWarning {
+		log.Printf("Passkey %d of user %d sent a stale signature counter and may have been cloned", passkey.ID, passkey.UserID)
+		return nil, ErrInvalidPasskey
+	}
+
+	recorded, err := s.passkeyRepo.RecordUse(passkey.ID, passkey.SignCount, int64(credential.Authenticator.SignCount), credential.Flags.BackupEligible, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !recorded {
+		return nil, ErrInvalidPasskey
+	}
+
+	// The passkey names the user, whatever the organization of the request
+	user, err := s.userRepo.WithContext(tenant.Unscoped(ctx)).GetByID(passkey.UserID)
+	if err != nil {
+		return nil, ErrInvalidPasskey
+	}
+
+	return s.auth.CompletePasskeyLogin(user, client)
+}
+
+func (s *passkeyService) ListPasskeys(ctx context.Context, userID uint) ([]*model.PasskeyResponse, error) {
+	if _, err := s.userRepo.WithContext(ctx).GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	passkeys, err := s.passkeyRepo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*model.PasskeyResponse, 0, len(passkeys))
+	for _, passkey := range passkeys {
+		responses = append(responses, toPasskeyResponse(passkey))
+	}
+	return responses, nil
+}
+
+func (s *passkeyService) DeletePasskey(userID, id uint) error {
+	deleted, err := s.passkeyRepo.Delete(userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrPasskeyNotFound
+	}
+	return nil
+}
+
+// storeChallenge keeps the challenge of a ceremony that has been started.
+// Only its hash is stored; the browser returns the challenge in the client
+// data, from which the session is rebuilt.
+func (s *passkeyService) storeChallenge(challenge, purpose string, userID *uint) error {
+	now := time.Now()
+	// Abandoned ceremonies would otherwise pile up.
+	if err := s.challengeRepo.DeleteExpired(now); err != nil {
+		log.Printf("Failed to delete expired passkey challenges: %v", err)
+	}
+
+	return s.challengeRepo.Create(&model.PasskeyChallenge{
+		ChallengeHash: hashSecret(challenge),
+		Purpose:       purpose,
+		UserID:        userID,
+		ExpiresAt:     now.Add(s.challengeTTL),
+	})
+}
+
+// consumeChallenge looks up the challenge answered in the client data and
+// removes it, so that each challenge is answered only once.
+func (s *passkeyService) consumeChallenge(challenge, purpose string, userID *uint) (*model.PasskeyChallenge, error) {
+	stored, err := s.challengeRepo.Consume(hashSecret(challenge))
+	if err != nil {
+		return nil, ErrInvalidPasskeyChallenge
+	}
+	if stored.Purpose != purpose || !time.Now().Before(stored.ExpiresAt) {
+		return nil, ErrInvalidPasskeyChallenge
+	}
+	if (userID == nil) != (stored.UserID == nil) || (userID != nil && *userID != *stored.UserID) {
+		return nil, ErrInvalidPasskeyChallenge
+	}
+	return stored, nil
+}
+
+// passkeyUser is a user and its passkeys as go-webauthn sees them.
+type passkeyUser struct {
+	id       uint
+	name     string
+	passkeys []*model.Passkey
+}
+
+// WebAuthnID returns the user handle, which authenticators return on login.
+// It must not contain personal data, so the ID is used.
+func (u *passkeyUser) WebAuthnID() []byte {
+	return binary.BigEndian.AppendUint64(nil, uint64(u.id))
+}
+
+func (u *passkeyUser) WebAuthnName() string {
+	return u.name
+}
+
+func (u *passkeyUser) WebAuthnDisplayName() string {
+	return u.name
+}
+
+func (u *passkeyUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.passkeys))
+	for _, passkey := range u.passkeys {
+		// Credential IDs are only stored base64url-encoded
+		id, _ := base64.RawURLEncoding.DecodeString(passkey.CredentialID)
+		transports := make([]protocol.AuthenticatorTransport, 0, len(passkey.Transports))
+		for _, transport := range passkey.Transports {
+			transports = append(transports, protocol.AuthenticatorTransport(transport))
+		}
+
+		credential := webauthn.Credential{
+			ID:            id,
+			PublicKey:     passkey.PublicKey,
+			Transport:     transports,
+			Authenticator: webauthn.Authenticator{SignCount: uint32(passkey.SignCount)},
+		}
+		if passkey.BackupEligible != nil {
+			credential.Flags.BackupEligible = *passkey.BackupEligible
+		}
+		credentials = append(credentials, credential)
+	}
+	return credentials
+}
+
+func toPasskeyResponse(passkey *model.Passkey) *model.PasskeyResponse {
+	return &model.PasskeyResponse{
+		ID:         passkey.ID,
+		Name:       passkey.Name,
+		LastUsedAt: passkey.LastUsedAt,
+		CreatedAt:  passkey.CreatedAt,
+	}
+}
@@ -0,0 +1,361 @@
+package service
+
+import (
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/gorm"
+)
+
+// storePasskeyChallenges keeps the challenges the service creates, like the
+// repository would.
+func (s *ServiceTestSuite) storePasskeyChallenges() map[string]*model.PasskeyChallenge {
+	challenges := map[string]*model.PasskeyChallenge{}
+	s.challengeRepo.On("DeleteExpired", mock.AnythingOfType("time.Time")).Return(nil).Maybe()
+	s.challengeRepo.On("Create", mock.AnythingOfType("*model.PasskeyChallenge")).Run(func(args mock.Arguments) {
+		challenge := args.Get(0).(*model.PasskeyChallenge)
+		challenges[challenge.ChallengeHash] = challenge
+	}).Return(nil).Maybe()
+	s.challengeRepo.On("Consume", mock.AnythingOfType("string")).Return(func(hash string) (*model.PasskeyChallenge, error) {
+		challenge, ok := challenges[hash]
+		if !ok {
+			return nil, gorm.ErrRecordNotFound
+		}
+		delete(challenges, hash)
+		return challenge, nil
+	}).Maybe()
+	return challenges
+}
+
+// registerPasskey adds a passkey on the authenticator for the test user and
+// returns it as it would be stored.
+func (s *ServiceTestSuite) registerPasskey(authenticator *testAuthenticator) *model.Passkey {
+	s.userRepo.On("GetByID", uint(1)).Return(s.newVerifiedUser(), nil).Once()
+	s.passkeyRepo.On("ListByUser", uint(1)).Return([]*model.Passkey{}, nil).Once()
+	s.passkeyRepo.On("GetByCredentialID", mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound).Once()
+	s.passkeyRepo.On("Create", mock.AnythingOfType("*model.Passkey")).Return(nil).Once()
+
+	options, err := s.passkeys.BeginRegistration(s.ctx, 1)
+	s.Require().NoError(err)
+	credential, err := authenticator.Register(options)
+	s.Require().NoError(err)
+	_, err = s.passkeys.FinishRegistration(1, &model.RegisterPasskeyRequest{Name: "Laptop", Credential: *credential})
+	s.Require().NoError(err)
+
+	passkey := s.passkeyRepo.Calls[len(s.passkeyRepo.Calls)-1].Arguments.Get(0).(*model.Passkey)
+	passkey.ID = 7
+	s.userRepo.ExpectedCalls = nil
+	s.userRepo.On("WithContext", mock.Anything).Return(s.userRepo).Maybe()
+	s.passkeyRepo.ExpectedCalls = nil
+	return passkey
+}
+
+func (s *ServiceTestSuite) passkeyLogin(authenticator *testAuthenticator) *model.PasskeyLoginRequest {
+	options, err := s.passkeys.BeginLogin()
+	s.Require().NoError(err)
+	credential, err := authenticator.Login(options)
+	s.Require().NoError(err)
+	return &model.PasskeyLoginRequest{Credential: *credential}
+}
+
+func (s *ServiceTestSuite) TestBeginPasskeyRegistration() {
+	challenges := s.storePasskeyChallenges()
+	s.userRepo.On("GetByID", uint(1)).Return(s.newVerifiedUser(), nil)
+	s.passkeyRepo.On("ListByUser", uint(1)).Return([]*model.Passkey{
+		{ID: 7, CredentialID: "Y3JlZC0x", Transports: model.TransportList{"usb"}},
+	}, nil)
+
+	// Execute
+	options, err := s.passkeys.BeginRegistration(s.ctx, 1)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "localhost", options.RelyingParty.ID)
+	assert.Equal(s.T(), "testuser", options.User.Name)
+	assert.Equal(s.T(), protocol.URLEncodedBase64{0, 0, 0, 0, 0, 0, 0, 1}, options.User.ID)
+	assert.Equal(s.T(), protocol.ResidentKeyRequirementRequired, options.AuthenticatorSelection.ResidentKey)
+	assert.Equal(s.T(), protocol.VerificationRequired, options.AuthenticatorSelection.UserVerification)
+	s.Require().Len(options.CredentialExcludeList, 1)
+	assert.Equal(s.T(), "cred-1", string(options.CredentialExcludeList[0].CredentialID))
+	assert.Equal(s.T(), []protocol.AuthenticatorTransport{"usb"}, options.CredentialExcludeList[0].Transport)
+
+	stored := challenges[hashSecret(options.Challenge.String())]
+	s.Require().NotNil(stored)
+	assert.Equal(s.T(), model.PasskeyPurposeRegistration, stored.Purpose)
+	assert.Equal(s.T(), uint(1), *stored.UserID)
+	assert.WithinDuration(s.T(), time.Now().Add(5*time.Minute), stored.ExpiresAt, 5*time.Second)
+}
+
+func (s *ServiceTestSuite) TestBeginPasskeyRegistration_UserNotFound() {
+	s.userRepo.On("GetByID", uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	_, err := s.passkeys.BeginRegistration(s.ctx, 9)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+	s.challengeRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestFinishPasskeyRegistration() {
+	s.storePasskeyChallenges()
+	authenticator := newTestAuthenticator("http://localhost:8080")
+
+	// Execute
+	passkey := s.registerPasskey(authenticator)
+
+	// Assert
+	assert.Equal(s.T(), uint(1), passkey.UserID)
+	assert.Equal(s.T(), "Laptop", passkey.Name)
+	assert.Equal(s.T(), protocol.URLEncodedBase64(authenticator.Credentials[0].ID).String(), passkey.CredentialID)
+	assert.NotEmpty(s.T(), passkey.PublicKey)
+	assert.Equal(s.T(), int64(1), passkey.SignCount)
+	assert.False(s.T(), *passkey.BackupEligible)
+	assert.Equal(s.T(), model.TransportList{"internal"}, passkey.Transports)
+}
+
+func (s *ServiceTestSuite) TestFinishPasskeyRegistration_ChallengeOfAnotherUser() {
+	s.storePasskeyChallenges()
+	s.userRepo.On("GetByID", uint(1)).Return(s.newVerifiedUser(), nil)
+	s.passkeyRepo.On("ListByUser", uint(1)).Return([]*model.Passkey{}, nil)
+	options, _ := s.passkeys.BeginRegistration(s.ctx, 1)
+	credential, _ := newTestAuthenticator("http://localhost:8080").Register(options)
+
+	// Execute
+	_, err := s.passkeys.FinishRegistration(2, &model.RegisterPasskeyRequest{Name: "Laptop", Credential: *credential})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidPasskeyChallenge)
+	s.passkeyRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestFinishPasskeyRegistration_Replayed() {
+	s.storePasskeyChallenges()
+	s.userRepo.On("GetByID", uint(1)).Return(s.newVerifiedUser(), nil)
+	s.passkeyRepo.On("ListByUser", uint(1)).Return([]*model.Passkey{}, nil)
+	s.passkeyRepo.On("GetByCredentialID", mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound)
+	s.passkeyRepo.On("Create", mock.AnythingOfType("*model.Passkey")).Return(nil).Once()
+	options, _ := s.passkeys.BeginRegistration(s.ctx, 1)
+	credential, _ := newTestAuthenticator("http://localhost:8080").Register(options)
+	req := &model.RegisterPasskeyRequest{Name: "Laptop", Credential: *credential}
+	_, err := s.passkeys.FinishRegistration(1, req)
+	s.Require().NoError(err)
+
+	// Execute
+	_, err = s.passkeys.FinishRegistration(1, req)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidPasskeyChallenge)
+}
+
+func (s *ServiceTestSuite) TestFinishPasskeyRegistration_WithoutUserVerification() {
+	s.storePasskeyChallenges()
+	s.userRepo.On("GetByID", uint(1)).Return(s.newVerifiedUser(), nil)
+	s.passkeyRepo.On("ListByUser", uint(1)).Return([]*model.Passkey{}, nil)
+	authenticator := newTestAuthenticator("http://localhost:8080")
+	authenticator.SkipUserVerification = true
+	options, _ := s.passkeys.BeginRegistration(s.ctx, 1)
+	credential, _ := authenticator.Register(options)
+
+	// Execute
+	_, err := s.passkeys.FinishRegistration(1, &model.RegisterPasskeyRequest{Name: "Key", Credential: *credential})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidPasskey)
+	s.passkeyRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestPasskeyLogin() {
+	s.storePasskeyChallenges()
+	authenticator := newTestAuthenticator("http://localhost:8080")
+	passkey := s.registerPasskey(authenticator)
+	s.passkeyRepo.On("GetByCredentialID", passkey.CredentialID).Return(passkey, nil)
+	s.passkeyRepo.On("RecordUse", uint(7), int64(1), int64(2), false, mock.AnythingOfType("time.Time")).Return(true, nil)
+	s.userRepo.On("GetByID", uint(1)).Return(s.newVerifiedUser(), nil)
+	s.sessionRepo.On("Create", mock.AnythingOfType("*model.Session")).Return(nil)
+	s.allowLogins()
+	req := s.passkeyLogin(authenticator)
+
+	// Execute
+	result, err := s.passkeys.FinishLogin(s.ctx, req, testClient)
+
+	// Assert: the passkey is both factors, so no TOTP code is asked for
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), result.AccessToken)
+	assert.False(s.T(), result.MFARequired)
+	s.mfaRepo.AssertNotCalled(s.T(), "GetByUserID", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestPasskeyLogin_WithoutSignCount() {
+	s.storePasskeyChallenges()
+	authenticator := newTestAuthenticator("http://localhost:8080")
+	authenticator.NoSignCount = true
+	authenticator.BackupEligible = true
+	passkey := s.registerPasskey(authenticator)
+	s.passkeyRepo.On("GetByCredentialID", passkey.CredentialID).Return(passkey, nil)
+	s.passkeyRepo.On("RecordUse", uint(7), int64(0), int64(0), true, mock.AnythingOfType("time.Time")).Return(true, nil)
+	s.userRepo.On("GetByID", uint(1)).Return(s.newVerifiedUser(), nil)
+	s.sessionRepo.On("Create", mock.AnythingOfType("*model.Session")).Return(nil)
+	s.allowLogins()
+	req := s.passkeyLogin(authenticator)
+
+	// Execute
+	result, err := s.passkeys.FinishLogin(s.ctx, req, testClient)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), result.AccessToken)
+}
+
+func (s *ServiceTestSuite) TestPasskeyLogin_ClonedAuthenticator() {
+	s.storePasskeyChallenges()
+	authenticator := newTestAuthenticator("http://localhost:8080")
+	passkey := s.registerPasskey(authenticator)
+	// The original has logged in a few times since the copy was made.
+	passkey.SignCount = 5
+	s.passkeyRepo.On("GetByCredentialID", passkey.CredentialID).Return(passkey, nil)
+	req := s.passkeyLogin(authenticator)
+
+	// Execute
+	result, err := s.passkeys.FinishLogin(s.ctx, req, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidPasskey)
+	assert.Nil(s.T(), result)
+	s.passkeyRepo.AssertNotCalled(s.T(), "RecordUse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestPasskeyLogin_ConcurrentUse() {
+	s.storePasskeyChallenges()
+	authenticator := newTestAuthenticator("http://localhost:8080")
+	passkey := s.registerPasskey(authenticator)
+	s.passkeyRepo.On("GetByCredentialID", passkey.CredentialID).Return(passkey, nil)
+	s.passkeyRepo.On("RecordUse", uint(7), int64(1), int64(2), false, mock.AnythingOfType("time.Time")).Return(false, nil)
+	req := s.passkeyLogin(authenticator)
+
+	// Execute
+	_, err := s.passkeys.FinishLogin(s.ctx, req, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidPasskey)
+	s.sessionRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestPasskeyLogin_BackupEligibilityChanged() {
+	s.storePasskeyChallenges()
+	authenticator := newTestAuthenticator("http://localhost:8080")
+	passkey := s.registerPasskey(authenticator)
+	authenticator.BackupEligible = true
+	s.passkeyRepo.On("GetByCredentialID", passkey.CredentialID).Return(passkey, nil)
+	req := s.passkeyLogin(authenticator)
+
+	// Execute
+	_, err := s.passkeys.FinishLogin(s.ctx, req, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidPasskey)
+	s.passkeyRepo.AssertNotCalled(s.T(), "RecordUse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestPasskeyLogin_BackupEligibilityNotStored() {
+	s.storePasskeyChallenges()
+	authenticator := newTestAuthenticator("http://localhost:8080")
+	authenticator.BackupEligible = true
+	passkey := s.registerPasskey(authenticator)
+	// Registered before backup eligibility was stored
+	passkey.BackupEligible = nil
+	s.passkeyRepo.On("GetByCredentialID", passkey.CredentialID).Return(passkey, nil)
+	s.passkeyRepo.On("RecordUse", uint(7), int64(1), int64(2), true, mock.AnythingOfType("time.Time")).Return(true, nil)
+	s.userRepo.On("GetByID", uint(1)).Return(s.newVerifiedUser(), nil)
+	s.sessionRepo.On("Create", mock.AnythingOfType("*model.Session")).Return(nil)
+	s.allowLogins()
+	req := s.passkeyLogin(authenticator)
+
+	// Execute
+	result, err := s.passkeys.FinishLogin(s.ctx, req, testClient)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), result.AccessToken)
+}
+
+func (s *ServiceTestSuite) TestPasskeyLogin_UnknownPasskey() {
+	s.storePasskeyChallenges()
+	authenticator := newTestAuthenticator("http://localhost:8080")
+	passkey := s.registerPasskey(authenticator)
+	s.passkeyRepo.On("GetByCredentialID", passkey.CredentialID).Return(nil, gorm.ErrRecordNotFound)
+	req := s.passkeyLogin(authenticator)
+
+	// Execute
+	_, err := s.passkeys.FinishLogin(s.ctx, req, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidPasskey)
+}
+
+func (s *ServiceTestSuite) TestPasskeyLogin_ExpiredChallenge() {
+	challenges := s.storePasskeyChallenges()
+	authenticator := newTestAuthenticator("http://localhost:8080")
+	s.registerPasskey(authenticator)
+	req := s.passkeyLogin(authenticator)
+	for _, challenge := range challenges {
+		challenge.ExpiresAt = time.Now().Add(-time.Second)
+	}
+
+	// Execute
+	_, err := s.passkeys.FinishLogin(s.ctx, req, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidPasskeyChallenge)
+	s.passkeyRepo.AssertNotCalled(s.T(), "RecordUse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestPasskeyLogin_RegistrationChallenge() {
+	s.storePasskeyChallenges()
+	s.userRepo.On("GetByID", uint(1)).Return(s.newVerifiedUser(), nil)
+	s.passkeyRepo.On("ListByUser", uint(1)).Return([]*model.Passkey{}, nil)
+	options, _ := s.passkeys.BeginRegistration(s.ctx, 1)
+	authenticator := newTestAuthenticator("http://localhost:8080")
+	_, _ = authenticator.Register(options)
+
+	// Execute: answer the registration challenge in a login
+	credential, _ := authenticator.Login(&protocol.PublicKeyCredentialRequestOptions{Challenge: options.Challenge, RelyingPartyID: "localhost"})
+	_, err := s.passkeys.FinishLogin(s.ctx, &model.PasskeyLoginRequest{Credential: *credential}, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidPasskeyChallenge)
+}
+
+func (s *ServiceTestSuite) TestListPasskeys() {
+	usedAt := time.Now()
+	s.userRepo.On("GetByID", uint(1)).Return(s.newVerifiedUser(), nil)
+	s.passkeyRepo.On("ListByUser", uint(1)).Return([]*model.Passkey{
+		{ID: 7, Name: "Laptop", CredentialID: "cred-1", LastUsedAt: &usedAt},
+		{ID: 8, Name: "Phone", CredentialID: "cred-2"},
+	}, nil)
+
+	// Execute
+	passkeys, err := s.passkeys.ListPasskeys(s.ctx, 1)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), passkeys, 2)
+	assert.Equal(s.T(), "Laptop", passkeys[0].Name)
+	assert.Equal(s.T(), &usedAt, passkeys[0].LastUsedAt)
+}
+
+func (s *ServiceTestSuite) TestDeletePasskey() {
+	s.passkeyRepo.On("Delete", uint(1), uint(7)).Return(true, nil)
+	s.passkeyRepo.On("Delete", uint(1), uint(8)).Return(false, nil)
+
+	// Execute
+	err := s.passkeys.DeletePasskey(1, 7)
+	notFound := s.passkeys.DeletePasskey(1, 8)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.ErrorIs(s.T(), notFound, ErrPasskeyNotFound)
+}
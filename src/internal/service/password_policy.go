@@ -0,0 +1,96 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// PasswordPolicyError lists the rules a password violates.
+type PasswordPolicyError struct {
+	Violations []string
+}
+
+func (e *PasswordPolicyError) Error() string {
+	return "password does not meet the policy: " + strings.Join(e.Violations, "; ")
+}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=PasswordPolicy --output=./mocks/service --outpkg=service --filename=password_policy.go --structname=MockPasswordPolicy --with-expecter=false
+type PasswordPolicy interface {
+	Validate(password string, user *model.User) error
+}
+
+type passwordPolicy struct {
+	conf config.PasswordConfig
+}
+
+func NewPasswordPolicy(conf *config.Config) PasswordPolicy {
+	return &passwordPolicy{conf: conf.Password}
+}
+
+// Validate checks password against the configured rules. user is used to
+// reject passwords that contain the username or email address.
+func (p *passwordPolicy) Validate(password string, user *model.User) error {
+	var violations []string
+
+	if utf8.RuneCountInString(password) < p.conf.MinLength {
+		violations = append(violations, fmt.Sprintf("must be at least %d characters long", p.conf.MinLength))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	if p.conf.RequireUpper && !hasUpper {
+		violations = append(violations, "must contain an uppercase letter")
+	}
+	if p.conf.RequireLower && !hasLower {
+		violations = append(violations, "must contain a lowercase letter")
+	}
+	if p.conf.RequireDigit && !hasDigit {
+		violations = append(violations, "must contain a digit")
+	}
+	if p.conf.RequireSymbol && !hasSymbol {
+		violations = append(violations, "must contain a symbol")
+	}
+
+	if p.conf.DisallowIdentity && user != nil && containsIdentity(password, user) {
+		violations = append(violations, "must not contain the username or email address")
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+func containsIdentity(password string, user *model.User) bool {
+	lower := strings.ToLower(password)
+
+	identities := []string{user.Username}
+	if local, _, ok := strings.Cut(user.Email, "@"); ok {
+		identities = append(identities, local)
+	}
+
+	for _, identity := range identities {
+		// Very short identities would reject too many passwords by accident.
+		if len(identity) >= 3 && strings.Contains(lower, strings.ToLower(identity)) {
+			return true
+		}
+	}
+	return false
+}
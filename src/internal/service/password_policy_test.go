@@ -0,0 +1,77 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+func (s *ServiceTestSuite) strictPolicy() PasswordPolicy {
+	return NewPasswordPolicy(&config.Config{
+		Password: config.PasswordConfig{
+			MinLength:        10,
+			RequireUpper:     true,
+			RequireLower:     true,
+			RequireDigit:     true,
+			RequireSymbol:    true,
+			DisallowIdentity: true,
+		},
+	})
+}
+
+func (s *ServiceTestSuite) TestPasswordPolicy_Valid() {
+	user := &model.User{Username: "testuser", Email: "test@example.com"}
+
+	err := s.strictPolicy().Validate("Correct-Horse-9", user)
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *ServiceTestSuite) TestPasswordPolicy_AllViolations() {
+	user := &model.User{Username: "testuser", Email: "test@example.com"}
+
+	err := s.strictPolicy().Validate("short", user)
+
+	var policyErr *PasswordPolicyError
+	assert.ErrorAs(s.T(), err, &policyErr)
+	assert.Equal(s.T(), []string{
+		"must be at least 10 characters long",
+		"must contain an uppercase letter",
+		"must contain a digit",
+		"must contain a symbol",
+	}, policyErr.Violations)
+}
+
+func (s *ServiceTestSuite) TestPasswordPolicy_CountsCharactersNotBytes() {
+	policy := NewPasswordPolicy(&config.Config{Password: config.PasswordConfig{MinLength: 4}})
+
+	assert.Error(s.T(), policy.Validate("äöü", nil))
+	assert.NoError(s.T(), policy.Validate("äöüß", nil))
+}
+
+func (s *ServiceTestSuite) TestPasswordPolicy_DisallowsUsername() {
+	user := &model.User{Username: "JohnDoe", Email: "someone@example.com"}
+
+	err := s.strictPolicy().Validate("My-johndoe-99", user)
+
+	var policyErr *PasswordPolicyError
+	assert.ErrorAs(s.T(), err, &policyErr)
+	assert.Equal(s.T(), []string{"must not contain the username or email address"}, policyErr.Violations)
+}
+
+func (s *ServiceTestSuite) TestPasswordPolicy_DisallowsEmailLocalPart() {
+	user := &model.User{Username: "jd", Email: "jane.doe@example.com"}
+
+	err := s.strictPolicy().Validate("Jane.Doe-2025!", user)
+
+	assert.Error(s.T(), err)
+}
+
+func (s *ServiceTestSuite) TestPasswordPolicy_IdentityCheckDisabled() {
+	policy := NewPasswordPolicy(&config.Config{Password: config.PasswordConfig{MinLength: 8}})
+	user := &model.User{Username: "testuser", Email: "test@example.com"}
+
+	err := policy.Validate("testuser123", user)
+
+	assert.NoError(s.T(), err)
+}
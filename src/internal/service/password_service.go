@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/hasher"
+	"github.com/weeranieb/go-kit-base/src/internal/mailer"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+)
+
+var (
+	ErrIncorrectPassword = errors.New("current password is incorrect")
+	ErrInvalidResetToken = errors.New("invalid or expired reset token")
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=PasswordService --output=./mocks/service --outpkg=service --filename=password_service.go --structname=MockPasswordService --with-expecter=false
+type PasswordService interface {
+	ChangePassword(ctx context.Context, id uint, req *model.ChangePasswordRequest, client *model.ClientInfo) error
+	RequestPasswordReset(ctx context.Context, req *model.ForgotPasswordRequest) error
+	ResetPassword(ctx context.Context, req *model.ResetPasswordRequest) error
+}
+
+type passwordService struct {
+	userRepo       repository.UserRepository
+	historyRepo    repository.PasswordHistoryRepository
+	resetRepo      repository.PasswordResetRepository
+	transactor     repository.Transactor
+	sessions       SessionService
+	lockout        LockoutService
+	passwordPolicy PasswordPolicy
+	passwordHasher hasher.PasswordHasher
+	mailer         mailer.Mailer
+	conf           config.PasswordConfig
+}
+
+func NewPasswordService(
+	userRepo repository.UserRepository,
+	historyRepo repository.PasswordHistoryRepository,
+	resetRepo repository.PasswordResetRepository,
+	transactor repository.Transactor,
+	sessions SessionService,
+	lockout LockoutService,
+	passwordPolicy PasswordPolicy,
+	passwordHasher hasher.PasswordHasher,
+	mailer mailer.Mailer,
+	conf *config.Config,
+) PasswordService {
+	return &passwordService{
+		userRepo:       userRepo,
+		historyRepo:    historyRepo,
+		resetRepo:      resetRepo,
+		transactor:     transactor,
+		sessions:       sessions,
+		lockout:        lockout,
+		passwordPolicy: passwordPolicy,
+		passwordHasher: passwordHasher,
+		mailer:         mailer,
+		conf:           conf.Password,
+	}
+}
+
+// ChangePassword sets a new password after checking the current one. The
+// check counts towards the login lockout of the user, like a failed login,
+// so that it cannot be used to guess the password instead.
+func (s *passwordService) ChangePassword(ctx context.Context, id uint, req *model.ChangePasswordRequest, client *model.ClientInfo) error {
+	user, err := s.userRepo.WithContext(ctx).GetByID(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.lockout.Check(user, user.Username, client); err != nil {
+		return err
+	}
+
+	ok, err := s.passwordHasher.Verify(req.CurrentPassword, user.Password)
+	if err != nil || !ok {
+		if err := s.lockout.RecordFailure(user, user.Username, client, LoginReasonIncorrectPassword); err != nil {
+			return err
+		}
+		return ErrIncorrectPassword
+	}
+
+	if err := s.validateNewPassword(ctx, user, req.NewPassword); err != nil {
+		return err
+	}
+
+	return s.storePassword(ctx, user, req.NewPassword)
+}
+
+// RequestPasswordReset mails a reset link to the address if it belongs to a
+// user of any organization. Unknown addresses are ignored so that callers
+// cannot probe for them.
+func (s *passwordService) RequestPasswordReset(ctx context.Context, req *model.ForgotPasswordRequest) error {
+	user, err := s.userRepo.WithContext(tenant.Unscoped(ctx)).GetByEmail(req.Email)
+	if err != nil {
+		return nil
+	}
+
+	now := time.Now()
+	resets := s.resetRepo.WithContext(ctx)
+
+	// Only the most recent link is valid.
+	if err := resets.InvalidateForUser(user.ID, now); err != nil {
+		return err
+	}
+
+	token, tokenHash, err := newSecretToken()
+	if err != nil {
+		return err
+	}
+
+	err = resets.Create(&model.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: tokenHash,
+		ExpiresAt: now.Add(s.conf.ResetTokenTTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.mailer.Send(&mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hello %s,\n\nUse the link below to choose a new password. It expires in %s.\n\n%s?token=%s\n\nIf you did not ask for this, you can ignore this email.\n",
+			user.Username, s.conf.ResetTokenTTL, s.conf.ResetURL, token,
+		),
+	})
+}
+
+// ResetPassword consumes a reset token, sets the new password and revokes
+// all sessions of the user.
+func (s *passwordService) ResetPassword(ctx context.Context, req *model.ResetPasswordRequest) error {
+	resetToken, err := s.resetRepo.WithContext(ctx).GetByTokenHash(hashSecret(req.Token))
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+
+	now := time.Now()
+	if resetToken.UsedAt != nil || !now.Before(resetToken.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	// The token names the user, whatever the organization of the request
+	user, err := s.userRepo.WithContext(tenant.Unscoped(ctx)).GetByID(resetToken.UserID)
+	if err != nil {
+		return ErrInvalidResetToken
+	}
+
+	// Validate before consuming the token so that a rejected password does
+	// not force the user to request a new link.
+	if err := s.validateNewPassword(ctx, user, req.NewPassword); err != nil {
+		return err
+	}
+
+	// The token is only spent if the new password is stored.
+	err = s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		consumed, err := s.resetRepo.WithContext(ctx).MarkUsed(resetToken.ID, now)
+		if err != nil {
+			return err
+		}
+		if !consumed {
+			return ErrInvalidResetToken
+		}
+
+		return s.storePassword(ctx, user, req.NewPassword)
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.sessions.RevokeAll(user.ID)
+}
+
+// validateNewPassword applies the password policy and rejects the current
+// password and the ones kept in the history.
+func (s *passwordService) validateNewPassword(ctx context.Context, user *model.User, password string) error {
+	if err := s.passwordPolicy.Validate(password, user); err != nil {
+		return err
+	}
+
+	if s.conf.HistorySize <= 0 {
+		return nil
+	}
+
+	reused := &PasswordPolicyError{
+		Violations: []string{fmt.Sprintf("must not match any of the last %d passwords", s.conf.HistorySize)},
+	}
+
+	if ok, _ := s.passwordHasher.Verify(password, user.Password); ok {
+		return reused
+	}
+
+	history, err := s.historyRepo.WithContext(ctx).ListRecent(user.ID, s.conf.HistorySize-1)
+	if err != nil {
+		return err
+	}
+	for _, entry := range history {
+		if ok, _ := s.passwordHasher.Verify(password, entry.Hash); ok {
+			return reused
+		}
+	}
+
+	return nil
+}
+
+// storePassword hashes and saves the new password and moves the previous
+// hash into the history, in one transaction.
+func (s *passwordService) storePassword(ctx context.Context, user *model.User, password string) error {
+	hashedPassword, err := s.passwordHasher.Hash(password)
+	if err != nil {
+		return err
+	}
+
+	previousHash := user.Password
+	user.Password = hashedPassword
+
+	return s.transactor.Transaction(ctx, func(ctx context.Context) error {
+		users := s.userRepo.WithContext(tenant.WithOrganization(ctx, user.OrganizationID))
+		if err := users.Update(user); err != nil {
+			return err
+		}
+
+		return s.historyRepo.WithContext(ctx).Create(&model.PasswordHistory{
+			UserID: user.ID,
+			Hash:   previousHash,
+		})
+	})
+}
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/mailer"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/gorm"
+)
+
+func (s *ServiceTestSuite) TestChangePassword_Success() {
+	s.allowLogins()
+	user := s.newUserWithPassword("old-password")
+	oldHash := user.Password
+
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.historyRepo.On("ListRecent", uint(1), 2).Return([]*model.PasswordHistory{}, nil)
+	s.userRepo.On("Update", mock.AnythingOfType("*model.User")).Return(nil)
+	s.historyRepo.On("Create", mock.MatchedBy(func(entry *model.PasswordHistory) bool {
+		return entry.UserID == 1 && entry.Hash == oldHash
+	})).Return(nil)
+
+	// Execute
+	err := s.passwordService.ChangePassword(s.ctx, 1, &model.ChangePasswordRequest{
+		CurrentPassword: "old-password",
+		NewPassword:     "new-password",
+	}, testClient)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	ok, _ := s.passwordHasher.Verify("new-password", user.Password)
+	assert.True(s.T(), ok)
+	s.userRepo.AssertExpectations(s.T())
+	s.historyRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestChangePassword_IncorrectCurrentPassword() {
+	user := s.newUserWithPassword("old-password")
+
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.throttleRepo.On("Get", mock.AnythingOfType("string")).Return(nil, gorm.ErrRecordNotFound)
+	s.attemptRepo.On("Create", mock.MatchedBy(func(attempt *model.LoginAttempt) bool {
+		return attempt.UserID != nil && *attempt.UserID == 1 &&
+			!attempt.Success &&
+			attempt.Reason == LoginReasonIncorrectPassword
+	})).Return(nil)
+	s.throttleRepo.On("RecordFailure", "user:1", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(&model.LoginThrottle{Failures: 1}, nil)
+	s.throttleRepo.On("RecordFailure", "ip:192.0.2.1", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(&model.LoginThrottle{Failures: 1}, nil)
+
+	// Execute
+	err := s.passwordService.ChangePassword(s.ctx, 1, &model.ChangePasswordRequest{
+		CurrentPassword: "wrong-password",
+		NewPassword:     "new-password",
+	}, testClient)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrIncorrectPassword)
+	s.attemptRepo.AssertExpectations(s.T())
+	s.throttleRepo.AssertExpectations(s.T())
+	s.userRepo.AssertNotCalled(s.T(), "Update", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestChangePassword_LockedOut() {
+	user := s.newUserWithPassword("old-password")
+	lockedUntil := time.Now().Add(10 * time.Minute)
+
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.throttleRepo.On("Get", "user:1").Return(&model.LoginThrottle{Subject: "user:1", Failures: 5, LockedUntil: &lockedUntil}, nil)
+	s.throttleRepo.On("Get", "ip:192.0.2.1").Return(nil, gorm.ErrRecordNotFound)
+	s.attemptRepo.On("Create", mock.AnythingOfType("*model.LoginAttempt")).Return(nil)
+
+	// Execute, with the right password
+	err := s.passwordService.ChangePassword(s.ctx, 1, &model.ChangePasswordRequest{
+		CurrentPassword: "old-password",
+		NewPassword:     "new-password",
+	}, testClient)
+
+	// Assert
+	var throttledErr *LoginThrottledError
+	assert.ErrorAs(s.T(), err, &throttledErr)
+	s.userRepo.AssertNotCalled(s.T(), "Update", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestChangePassword_UserNotFound() {
+	s.userRepo.On("GetByID", uint(999)).Return(nil, errors.New("user not found"))
+
+	// Execute
+	err := s.passwordService.ChangePassword(s.ctx, 999, &model.ChangePasswordRequest{
+		CurrentPassword: "old-password",
+		NewPassword:     "new-password",
+	}, testClient)
+
+	// Assert
+	assert.Error(s.T(), err)
+}
+
+func (s *ServiceTestSuite) TestChangePassword_PolicyViolation() {
+	s.allowLogins()
+	user := s.newUserWithPassword("old-password")
+
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+
+	// Execute
+	err := s.passwordService.ChangePassword(s.ctx, 1, &model.ChangePasswordRequest{
+		CurrentPassword: "old-password",
+		NewPassword:     "short",
+	}, testClient)
+
+	// Assert
+	var policyErr *PasswordPolicyError
+	assert.ErrorAs(s.T(), err, &policyErr)
+	s.userRepo.AssertNotCalled(s.T(), "Update", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestChangePassword_SameAsCurrent() {
+	s.allowLogins()
+	user := s.newUserWithPassword("old-password")
+
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+
+	// Execute
+	err := s.passwordService.ChangePassword(s.ctx, 1, &model.ChangePasswordRequest{
+		CurrentPassword: "old-password",
+		NewPassword:     "old-password",
+	}, testClient)
+
+	// Assert
+	var policyErr *PasswordPolicyError
+	assert.ErrorAs(s.T(), err, &policyErr)
+	assert.Equal(s.T(), []string{"must not match any of the last 3 passwords"}, policyErr.Violations)
+}
+
+func (s *ServiceTestSuite) TestChangePassword_ReusedFromHistory() {
+	s.allowLogins()
+	user := s.newUserWithPassword("old-password")
+	previous, _ := s.passwordHasher.Hash("older-password")
+
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.historyRepo.On("ListRecent", uint(1), 2).Return([]*model.PasswordHistory{
+		{UserID: 1, Hash: previous},
+	}, nil)
+
+	// Execute
+	err := s.passwordService.ChangePassword(s.ctx, 1, &model.ChangePasswordRequest{
+		CurrentPassword: "old-password",
+		NewPassword:     "older-password",
+	}, testClient)
+
+	// Assert
+	var policyErr *PasswordPolicyError
+	assert.ErrorAs(s.T(), err, &policyErr)
+	s.userRepo.AssertNotCalled(s.T(), "Update", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestRequestPasswordReset_SendsLink() {
+	user := s.newUserWithPassword("old-password")
+	var storedHash string
+
+	s.userRepo.On("GetByEmail", "test@example.com").Return(user, nil)
+	s.resetRepo.On("InvalidateForUser", uint(1), mock.AnythingOfType("time.Time")).Return(nil)
+	s.resetRepo.On("Create", mock.AnythingOfType("*model.PasswordResetToken")).Return(nil).Run(func(args mock.Arguments) {
+		token := args.Get(0).(*model.PasswordResetToken)
+		storedHash = token.TokenHash
+		assert.WithinDuration(s.T(), time.Now().Add(time.Hour), token.ExpiresAt, time.Minute)
+	})
+	s.mailer.On("Send", mock.AnythingOfType("*mailer.Message")).Return(nil)
+
+	// Execute
+	err := s.passwordService.RequestPasswordReset(s.ctx, &model.ForgotPasswordRequest{Email: "test@example.com"})
+
+	// Assert
+	assert.NoError(s.T(), err)
+	msg := s.mailer.Calls[0].Arguments.Get(0).(*mailer.Message)
+	assert.Equal(s.T(), "test@example.com", msg.To)
+
+	// The mailed token hashes to the stored hash, the raw token is never stored
+	token := msg.Body[strings.Index(msg.Body, "?token=")+len("?token="):]
+	token = strings.Fields(token)[0]
+	assert.Equal(s.T(), storedHash, hashSecret(token))
+	assert.NotEqual(s.T(), storedHash, token)
+	s.resetRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestRequestPasswordReset_UnknownEmail() {
+	s.userRepo.On("GetByEmail", "nobody@example.com").Return(nil, errors.New("not found"))
+
+	// Execute
+	err := s.passwordService.RequestPasswordReset(s.ctx, &model.ForgotPasswordRequest{Email: "nobody@example.com"})
+
+	// Assert
+	assert.NoError(s.T(), err)
+	s.mailer.AssertNotCalled(s.T(), "Send", mock.Anything)
+	s.resetRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestResetPassword_Success() {
+	user := s.newUserWithPassword("old-password")
+	resetToken := &model.PasswordResetToken{
+		ID:        7,
+		UserID:    1,
+		TokenHash: hashSecret("raw-token"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	s.resetRepo.On("GetByTokenHash", hashSecret("raw-token")).Return(resetToken, nil)
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.historyRepo.On("ListRecent", uint(1), 2).Return([]*model.PasswordHistory{}, nil)
+	s.resetRepo.On("MarkUsed", uint(7), mock.AnythingOfType("time.Time")).Return(true, nil)
+	s.userRepo.On("Update", mock.AnythingOfType("*model.User")).Return(nil)
+	s.historyRepo.On("Create", mock.AnythingOfType("*model.PasswordHistory")).Return(nil)
+	s.sessionRepo.On("RevokeAllForUser", uint(1), mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Execute
+	err := s.passwordService.ResetPassword(s.ctx, &model.ResetPasswordRequest{Token: "raw-token", NewPassword: "new-password"})
+
+	// Assert
+	assert.NoError(s.T(), err)
+	ok, _ := s.passwordHasher.Verify("new-password", user.Password)
+	assert.True(s.T(), ok)
+	s.resetRepo.AssertExpectations(s.T())
+	s.sessionRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestResetPassword_HistoryFails() {
+	user := s.newUserWithPassword("old-password")
+	resetToken := &model.PasswordResetToken{
+		ID:        7,
+		UserID:    1,
+		TokenHash: hashSecret("raw-token"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	s.resetRepo.On("GetByTokenHash", hashSecret("raw-token")).Return(resetToken, nil)
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.historyRepo.On("ListRecent", uint(1), 2).Return([]*model.PasswordHistory{}, nil)
+	s.resetRepo.On("MarkUsed", uint(7), mock.AnythingOfType("time.Time")).Return(true, nil)
+	s.userRepo.On("Update", mock.AnythingOfType("*model.User")).Return(nil)
+	s.historyRepo.On("Create", mock.AnythingOfType("*model.PasswordHistory")).Return(errors.New("db down"))
+
+	// Execute
+	err := s.passwordService.ResetPassword(s.ctx, &model.ResetPasswordRequest{Token: "raw-token", NewPassword: "new-password"})
+
+	// Assert: the token, the password and the history are written in one
+	// transaction, which fails as a whole
+	assert.EqualError(s.T(), err, "db down")
+	s.transactor.AssertCalled(s.T(), "Transaction", mock.Anything, mock.Anything)
+	s.sessionRepo.AssertNotCalled(s.T(), "RevokeAllForUser", mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestResetPassword_UnknownToken() {
+	s.resetRepo.On("GetByTokenHash", hashSecret("raw-token")).Return(nil, errors.New("not found"))
+
+	// Execute
+	err := s.passwordService.ResetPassword(s.ctx, &model.ResetPasswordRequest{Token: "raw-token", NewPassword: "new-password"})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidResetToken)
+}
+
+func (s *ServiceTestSuite) TestResetPassword_ExpiredToken() {
+	s.resetRepo.On("GetByTokenHash", hashSecret("raw-token")).Return(&model.PasswordResetToken{
+		ID:        7,
+		UserID:    1,
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}, nil)
+
+	// Execute
+	err := s.passwordService.ResetPassword(s.ctx, &model.ResetPasswordRequest{Token: "raw-token", NewPassword: "new-password"})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidResetToken)
+	s.userRepo.AssertNotCalled(s.T(), "GetByID", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestResetPassword_AlreadyUsedToken() {
+	usedAt := time.Now().Add(-time.Minute)
+	s.resetRepo.On("GetByTokenHash", hashSecret("raw-token")).Return(&model.PasswordResetToken{
+		ID:        7,
+		UserID:    1,
+		ExpiresAt: time.Now().Add(time.Hour),
+		UsedAt:    &usedAt,
+	}, nil)
+
+	// Execute
+	err := s.passwordService.ResetPassword(s.ctx, &model.ResetPasswordRequest{Token: "raw-token", NewPassword: "new-password"})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidResetToken)
+}
+
+func (s *ServiceTestSuite) TestResetPassword_ConsumedConcurrently() {
+	user := s.newUserWithPassword("old-password")
+
+	s.resetRepo.On("GetByTokenHash", hashSecret("raw-token")).Return(&model.PasswordResetToken{
+		ID:        7,
+		UserID:    1,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.historyRepo.On("ListRecent", uint(1), 2).Return([]*model.PasswordHistory{}, nil)
+	s.resetRepo.On("MarkUsed", uint(7), mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	// Execute
+	err := s.passwordService.ResetPassword(s.ctx, &model.ResetPasswordRequest{Token: "raw-token", NewPassword: "new-password"})
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidResetToken)
+	s.userRepo.AssertNotCalled(s.T(), "Update", mock.Anything)
+	s.sessionRepo.AssertNotCalled(s.T(), "RevokeAllForUser", mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestResetPassword_PolicyViolationKeepsToken() {
+	user := s.newUserWithPassword("old-password")
+
+	s.resetRepo.On("GetByTokenHash", hashSecret("raw-token")).Return(&model.PasswordResetToken{
+		ID:        7,
+		UserID:    1,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+
+	// Execute
+	err := s.passwordService.ResetPassword(s.ctx, &model.ResetPasswordRequest{Token: "raw-token", NewPassword: "short"})
+
+	// Assert
+	var policyErr *PasswordPolicyError
+	assert.ErrorAs(s.T(), err, &policyErr)
+	s.resetRepo.AssertNotCalled(s.T(), "MarkUsed", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/presence"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=PresenceService --output=./mocks/service --outpkg=service --filename=presence_service.go --structname=MockPresenceService --with-expecter=false
+type PresenceService interface {
+	GetPresence(ctx context.Context, userID uint) (*model.PresenceResponse, error)
+}
+
+type presenceService struct {
+	userRepo      repository.UserRepository
+	presenceStore presence.Store
+}
+
+func NewPresenceService(userRepo repository.UserRepository, presenceStore presence.Store) PresenceService {
+	return &presenceService{
+		userRepo:      userRepo,
+		presenceStore: presenceStore,
+	}
+}
+
+// GetPresence returns whether a user of the organization in ctx is connected
+// to the gateway. Users of other organizations are not found.
+func (s *presenceService) GetPresence(ctx context.Context, userID uint) (*model.PresenceResponse, error) {
+	if _, err := s.userRepo.WithContext(ctx).GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	status, err := s.presenceStore.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.PresenceResponse{
+		UserID: userID,
+		Status: model.PresenceOffline,
+	}
+	if status.Online {
+		response.Status = model.PresenceOnline
+	}
+	if !status.LastSeenAt.IsZero() {
+		lastSeenAt := status.LastSeenAt
+		response.LastSeenAt = &lastSeenAt
+	}
+	return response, nil
+}
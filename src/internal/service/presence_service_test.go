@@ -0,0 +1,50 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/gorm"
+)
+
+// Test GetPresence
+func (s *ServiceTestSuite) TestGetPresence_NeverConnected() {
+	s.userRepo.On("GetByID", uint(1)).Return(&model.User{ID: 1}, nil)
+
+	presence, err := s.presenceService.GetPresence(s.ctx, 1)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.PresenceOffline, presence.Status)
+	assert.Nil(s.T(), presence.LastSeenAt)
+}
+
+func (s *ServiceTestSuite) TestGetPresence_Online() {
+	s.userRepo.On("GetByID", uint(1)).Return(&model.User{ID: 1}, nil)
+	s.presenceStore.Connect(s.ctx, 1, "connection-1")
+
+	presence, err := s.presenceService.GetPresence(s.ctx, 1)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), uint(1), presence.UserID)
+	assert.Equal(s.T(), model.PresenceOnline, presence.Status)
+	assert.NotNil(s.T(), presence.LastSeenAt)
+}
+
+func (s *ServiceTestSuite) TestGetPresence_Offline() {
+	s.userRepo.On("GetByID", uint(1)).Return(&model.User{ID: 1}, nil)
+	s.presenceStore.Connect(s.ctx, 1, "connection-1")
+	s.presenceStore.Disconnect(s.ctx, 1, "connection-1")
+
+	presence, err := s.presenceService.GetPresence(s.ctx, 1)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.PresenceOffline, presence.Status)
+	assert.NotNil(s.T(), presence.LastSeenAt)
+}
+
+func (s *ServiceTestSuite) TestGetPresence_UserNotFound() {
+	s.userRepo.On("GetByID", uint(2)).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := s.presenceService.GetPresence(s.ctx, 2)
+
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrScheduleNotFound = errors.New("schedule not found")
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=ScheduleService --output=./mocks/service --outpkg=service --filename=schedule_service.go --structname=MockScheduleService --with-expecter=false
+type ScheduleService interface {
+	ListSchedules(ctx context.Context) ([]*model.ScheduleResponse, error)
+	TriggerSchedule(ctx context.Context, name string) (*model.ScheduleResponse, error)
+}
+
+type scheduleService struct {
+	scheduleRepo repository.ScheduleRepository
+}
+
+func NewScheduleService(scheduleRepo repository.ScheduleRepository) ScheduleService {
+	return &scheduleService{
+		scheduleRepo: scheduleRepo,
+	}
+}
+
+// ListSchedules returns the scheduled tasks by name with their last run.
+func (s *scheduleService) ListSchedules(ctx context.Context) ([]*model.ScheduleResponse, error) {
+	schedules, err := s.scheduleRepo.WithContext(ctx).List()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*model.ScheduleResponse, 0, len(schedules))
+	for _, schedule := range schedules {
+		responses = append(responses, toScheduleResponse(schedule))
+	}
+	return responses, nil
+}
+
+// TriggerSchedule makes a task due now, so the scheduler runs it on its
+// next check. A task that is running runs again once it finishes.
+func (s *scheduleService) TriggerSchedule(ctx context.Context, name string) (*model.ScheduleResponse, error) {
+	scheduleRepo := s.scheduleRepo.WithContext(ctx)
+
+	triggered, err := scheduleRepo.Trigger(name)
+	if err != nil {
+		return nil, err
+	}
+	if !triggered {
+		return nil, ErrScheduleNotFound
+	}
+
+	schedule, err := scheduleRepo.GetByName(name)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrScheduleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toScheduleResponse(schedule), nil
+}
+
+func toScheduleResponse(schedule *model.Schedule) *model.ScheduleResponse {
+	return &model.ScheduleResponse{
+		Name:           schedule.Name,
+		Expression:     schedule.Expression,
+		NextRunAt:      schedule.NextRunAt,
+		Running:        schedule.RunningSince != nil,
+		LastRunAt:      schedule.LastRunAt,
+		LastStatus:     schedule.LastStatus,
+		LastError:      schedule.LastError,
+		LastDurationMS: schedule.LastDurationMS,
+	}
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// Test ListSchedules
+func (s *ServiceTestSuite) TestListSchedules() {
+	now := time.Now()
+	s.scheduleRepo.On("List").Return([]*model.Schedule{
+		{Name: "idempotency.cleanup", Expression: "@hourly", NextRunAt: now, RunningSince: &now},
+		{Name: "jobs.retention", Expression: "@daily", NextRunAt: now, LastRunAt: &now, LastStatus: model.ScheduleSucceeded, LastDurationMS: 12},
+	}, nil)
+
+	schedules, err := s.schedules.ListSchedules(s.ctx)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), schedules, 2)
+	assert.True(s.T(), schedules[0].Running)
+	assert.False(s.T(), schedules[1].Running)
+	assert.Equal(s.T(), model.ScheduleSucceeded, schedules[1].LastStatus)
+	assert.Equal(s.T(), int64(12), schedules[1].LastDurationMS)
+}
+
+// Test TriggerSchedule
+func (s *ServiceTestSuite) TestTriggerSchedule_Success() {
+	now := time.Now()
+	s.scheduleRepo.On("Trigger", "jobs.retention").Return(true, nil)
+	s.scheduleRepo.On("GetByName", "jobs.retention").Return(&model.Schedule{Name: "jobs.retention", Expression: "@daily", NextRunAt: now}, nil)
+
+	schedule, err := s.schedules.TriggerSchedule(s.ctx, "jobs.retention")
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), now, schedule.NextRunAt)
+}
+
+func (s *ServiceTestSuite) TestTriggerSchedule_NotFound() {
+	s.scheduleRepo.On("Trigger", "nope").Return(false, nil)
+
+	_, err := s.schedules.TriggerSchedule(s.ctx, "nope")
+
+	assert.ErrorIs(s.T(), err, ErrScheduleNotFound)
+}
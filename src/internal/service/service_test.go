@@ -1,25 +1,308 @@
 package service
 
 import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/hasher"
+	mailerMocks "github.com/weeranieb/go-kit-base/src/internal/mailer/mocks/mailer"
+	"github.com/weeranieb/go-kit-base/src/internal/oidc"
+	"github.com/weeranieb/go-kit-base/src/internal/oidc/oidctest"
+	"github.com/weeranieb/go-kit-base/src/internal/presence"
 	mocks "github.com/weeranieb/go-kit-base/src/internal/repository/mocks/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type ServiceTestSuite struct {
 	suite.Suite
-	userRepo    *mocks.MockUserRepository
-	userService UserService
+	// ctx is scoped to organization 1, which the users of the tests
+	// belong to
+	ctx             context.Context
+	conf            *config.Config
+	userRepo        *mocks.MockUserRepository
+	sessionRepo     *mocks.MockSessionRepository
+	historyRepo     *mocks.MockPasswordHistoryRepository
+	resetRepo       *mocks.MockPasswordResetRepository
+	verifyRepo      *mocks.MockEmailVerificationRepository
+	mfaRepo         *mocks.MockMFARepository
+	attemptRepo     *mocks.MockLoginAttemptRepository
+	throttleRepo    *mocks.MockLoginThrottleRepository
+	apiKeyRepo      *mocks.MockAPIKeyRepository
+	stateRepo       *mocks.MockOIDCStateRepository
+	identityRepo    *mocks.MockExternalIdentityRepository
+	clientRepo      *mocks.MockOAuthClientRepository
+	consentRepo     *mocks.MockOAuthConsentRepository
+	codeRepo        *mocks.MockOAuthCodeRepository
+	signingKeyRepo  *mocks.MockSigningKeyRepository
+	magicLinkRepo   *mocks.MockMagicLinkRepository
+	passkeyRepo     *mocks.MockPasskeyRepository
+	challengeRepo   *mocks.MockPasskeyChallengeRepository
+	invitationRepo  *mocks.MockInvitationRepository
+	orgRepo         *mocks.MockOrganizationRepository
+	membershipRepo  *mocks.MockMembershipRepository
+	groupRepo       *mocks.MockGroupRepository
+	auditRepo       *mocks.MockAuditRepository
+	versionRepo     *mocks.MockUserVersionRepository
+	outboxRepo      *mocks.MockOutboxRepository
+	webhookRepo     *mocks.MockWebhookRepository
+	jobRepo         *mocks.MockJobRepository
+	scheduleRepo    *mocks.MockScheduleRepository
+	ticketRepo      *mocks.MockGatewayTicketRepository
+	transactor      *mocks.MockTransactor
+	mailer          *mailerMocks.MockMailer
+	passwordPolicy  PasswordPolicy
+	passwordHasher  hasher.PasswordHasher
+	tokenService    TokenService
+	sessionService  SessionService
+	apiKeyService   APIKeyService
+	verification    EmailVerificationService
+	mfaService      MFAService
+	lockoutService  LockoutService
+	userService     UserService
+	authService     AuthService
+	passwordService PasswordService
+	oidcServer      *oidctest.Server
+	oidcService     OIDCLoginService
+	rsaKey          *rsa.PrivateKey
+	signingKeys     SigningKeyService
+	oauthClients    OAuthClientService
+	idpService      IdentityProviderService
+	magicLinks      MagicLinkService
+	passkeys        PasskeyService
+	invitations     InvitationService
+	organizations   OrganizationService
+	groups          GroupService
+	audit           AuditService
+	webhooks        WebhookService
+	jobs            JobService
+	schedules       ScheduleService
+	presenceStore   presence.Store
+	presenceService PresenceService
+	tickets         GatewayTicketService
+}
+
+func (s *ServiceTestSuite) SetupSuite() {
+	s.oidcServer = oidctest.NewServer("client-1", "secret-1")
+	s.rsaKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+}
+
+func (s *ServiceTestSuite) TearDownSuite() {
+	s.oidcServer.Close()
 }
 
 func (s *ServiceTestSuite) SetupTest() {
+	s.ctx = tenant.WithOrganization(context.Background(), 1)
+	s.conf = &config.Config{
+		Auth: config.AuthConfig{
+			JWTSecret:        "test-secret",
+			Issuer:           "test",
+			SessionTTL:       time.Hour,
+			SessionCacheTTL:  time.Minute,
+			LastSeenInterval: time.Minute,
+			SelfRegistration: true,
+		},
+		Password: config.PasswordConfig{
+			MinLength:        8,
+			DisallowIdentity: true,
+			HistorySize:      3,
+			ResetTokenTTL:    time.Hour,
+			ResetURL:         "http://localhost/reset-password",
+			// Cheap parameters keep the suite fast
+			Hasher: config.PasswordHasherConfig{
+				Algorithm:  "bcrypt",
+				BcryptCost: bcrypt.MinCost,
+				Argon2id: config.Argon2idConfig{
+					Memory:      64,
+					Iterations:  1,
+					Parallelism: 1,
+				},
+			},
+		},
+		MFA: config.MFAConfig{
+			Issuer:        "test",
+			EncryptionKey: "test-mfa-key",
+			ChallengeTTL:  5 * time.Minute,
+			RecoveryCodes: 3,
+			RequiredRoles: []string{"admin"},
+		},
+		Lockout: config.LockoutConfig{
+			MaxFailures:   5,
+			Duration:      15 * time.Minute,
+			IPMaxFailures: 20,
+			IPDuration:    15 * time.Minute,
+			FailureWindow: 15 * time.Minute,
+			BaseDelay:     time.Second,
+			MaxDelay:      30 * time.Second,
+		},
+		EmailVerification: config.EmailVerificationConfig{
+			TokenTTL:       24 * time.Hour,
+			ResendInterval: time.Minute,
+			VerifyURL:      "http://localhost/verify-email",
+		},
+		OIDC: config.OIDCConfig{
+			StateTTL: 10 * time.Minute,
+		},
+		IdentityProvider: config.IdentityProviderConfig{
+			Issuer:              "http://localhost:8080",
+			EncryptionKey:       "test-idp-key",
+			KeyRotationInterval: 24 * time.Hour,
+			CodeTTL:             time.Minute,
+			TokenTTL:            time.Hour,
+		},
+		MagicLink: config.MagicLinkConfig{
+			TokenTTL:     15 * time.Minute,
+			LoginURL:     "http://localhost/magic-link",
+			MaxPerWindow: 3,
+			Window:       15 * time.Minute,
+		},
+		WebAuthn: config.WebAuthnConfig{
+			RPID:         "localhost",
+			RPName:       "Test",
+			Origins:      []string{"http://localhost:8080"},
+			ChallengeTTL: 5 * time.Minute,
+		},
+		Invitation: config.InvitationConfig{
+			TokenTTL:  7 * 24 * time.Hour,
+			AcceptURL: "http://localhost/accept-invitation",
+		},
+		Webhook: config.WebhookConfig{
+			EncryptionKey: "test-webhook-key",
+			Lease:         time.Minute,
+			MaxAttempts:   3,
+			BaseBackoff:   10 * time.Second,
+			MaxBackoff:    time.Minute,
+			DisableAfter:  5,
+		},
+		Jobs: config.JobsConfig{
+			MaxAttempts: 5,
+		},
+		Gateway: config.GatewayConfig{
+			TicketTTL: 30 * time.Second,
+		},
+	}
+
 	s.userRepo = mocks.NewMockUserRepository(s.T())
-	s.userService = NewUserService(s.userRepo)
+	s.sessionRepo = mocks.NewMockSessionRepository(s.T())
+	s.historyRepo = mocks.NewMockPasswordHistoryRepository(s.T())
+	s.resetRepo = mocks.NewMockPasswordResetRepository(s.T())
+	s.verifyRepo = mocks.NewMockEmailVerificationRepository(s.T())
+	s.mfaRepo = mocks.NewMockMFARepository(s.T())
+	s.attemptRepo = mocks.NewMockLoginAttemptRepository(s.T())
+	s.throttleRepo = mocks.NewMockLoginThrottleRepository(s.T())
+	s.apiKeyRepo = mocks.NewMockAPIKeyRepository(s.T())
+	s.stateRepo = mocks.NewMockOIDCStateRepository(s.T())
+	s.identityRepo = mocks.NewMockExternalIdentityRepository(s.T())
+	s.clientRepo = mocks.NewMockOAuthClientRepository(s.T())
+	s.consentRepo = mocks.NewMockOAuthConsentRepository(s.T())
+	s.codeRepo = mocks.NewMockOAuthCodeRepository(s.T())
+	s.signingKeyRepo = mocks.NewMockSigningKeyRepository(s.T())
+	s.magicLinkRepo = mocks.NewMockMagicLinkRepository(s.T())
+	s.passkeyRepo = mocks.NewMockPasskeyRepository(s.T())
+	s.challengeRepo = mocks.NewMockPasskeyChallengeRepository(s.T())
+	s.invitationRepo = mocks.NewMockInvitationRepository(s.T())
+	s.orgRepo = mocks.NewMockOrganizationRepository(s.T())
+	s.membershipRepo = mocks.NewMockMembershipRepository(s.T())
+	s.groupRepo = mocks.NewMockGroupRepository(s.T())
+	s.auditRepo = mocks.NewMockAuditRepository(s.T())
+	s.versionRepo = mocks.NewMockUserVersionRepository(s.T())
+	s.outboxRepo = mocks.NewMockOutboxRepository(s.T())
+	s.webhookRepo = mocks.NewMockWebhookRepository(s.T())
+	s.jobRepo = mocks.NewMockJobRepository(s.T())
+	s.scheduleRepo = mocks.NewMockScheduleRepository(s.T())
+	s.ticketRepo = mocks.NewMockGatewayTicketRepository(s.T())
+	s.transactor = mocks.NewMockTransactor(s.T())
+	s.mailer = mailerMocks.NewMockMailer(s.T())
+
+	// Binding a repository to a context returns the same mock; isolation is
+	// covered by the repository tests
+	s.userRepo.On("WithContext", mock.Anything).Return(s.userRepo).Maybe()
+	s.invitationRepo.On("WithContext", mock.Anything).Return(s.invitationRepo).Maybe()
+	s.groupRepo.On("WithContext", mock.Anything).Return(s.groupRepo).Maybe()
+	s.auditRepo.On("WithContext", mock.Anything).Return(s.auditRepo).Maybe()
+	s.versionRepo.On("WithContext", mock.Anything).Return(s.versionRepo).Maybe()
+	s.outboxRepo.On("WithContext", mock.Anything).Return(s.outboxRepo).Maybe()
+	s.webhookRepo.On("WithContext", mock.Anything).Return(s.webhookRepo).Maybe()
+	s.jobRepo.On("WithContext", mock.Anything).Return(s.jobRepo).Maybe()
+	s.scheduleRepo.On("WithContext", mock.Anything).Return(s.scheduleRepo).Maybe()
+	s.historyRepo.On("WithContext", mock.Anything).Return(s.historyRepo).Maybe()
+	s.resetRepo.On("WithContext", mock.Anything).Return(s.resetRepo).Maybe()
+	s.ticketRepo.On("WithContext", mock.Anything).Return(s.ticketRepo).Maybe()
+
+	// Transactions run the function right away
+	s.transactor.On("Transaction", mock.Anything, mock.Anything).Return(func(ctx context.Context, fn func(context.Context) error) error {
+		return fn(ctx)
+	}).Maybe()
+
+	s.passwordPolicy = NewPasswordPolicy(s.conf)
+	s.passwordHasher, _ = hasher.NewPasswordHasher(s.conf)
+	s.tokenService, _ = NewTokenService(s.conf)
+	s.sessionService = NewSessionService(s.sessionRepo, s.userRepo, s.tokenService, s.conf)
+	s.groups = NewGroupService(s.groupRepo, s.membershipRepo)
+	s.apiKeyService = NewAPIKeyService(s.apiKeyRepo, s.userRepo, s.groups, s.conf)
+	s.verification = NewEmailVerificationService(s.userRepo, s.verifyRepo, s.mailer, s.conf)
+	s.userService = NewUserService(s.userRepo, s.versionRepo, s.passwordPolicy, s.passwordHasher, s.verification, s.sessionService, s.apiKeyService)
+	s.mfaService, _ = NewMFAService(s.userRepo, s.mfaRepo, s.conf)
+	s.lockoutService = NewLockoutService(s.userRepo, s.attemptRepo, s.throttleRepo, s.conf)
+	s.passwordService = NewPasswordService(s.userRepo, s.historyRepo, s.resetRepo, s.transactor, s.sessionService, s.lockoutService, s.passwordPolicy, s.passwordHasher, s.mailer, s.conf)
+	s.authService = NewAuthService(s.userRepo, s.sessionService, s.tokenService, s.passwordHasher, s.mfaService, s.lockoutService, s.conf)
+
+	providers := oidc.Providers{"test": oidc.NewProvider(config.OIDCProviderConfig{
+		Issuer:       s.oidcServer.Issuer(),
+		ClientID:     "client-1",
+		ClientSecret: "secret-1",
+		RedirectURL:  "http://localhost/callback",
+		Scopes:       []string{"email"},
+	}, http.DefaultClient)}
+	s.oidcService = NewOIDCLoginService(providers, s.stateRepo, s.identityRepo, s.userRepo, s.authService, s.conf)
+
+	s.signingKeys, _ = NewSigningKeyService(s.signingKeyRepo, s.conf)
+	s.oauthClients = NewOAuthClientService(s.clientRepo, s.userRepo)
+	s.idpService = NewIdentityProviderService(s.clientRepo, s.consentRepo, s.codeRepo, s.userRepo, s.sessionRepo, s.signingKeys, s.conf)
+	s.magicLinks = NewMagicLinkService(s.magicLinkRepo, s.userRepo, s.authService, s.mailer, s.conf)
+	s.passkeys, _ = NewPasskeyService(s.passkeyRepo, s.challengeRepo, s.userRepo, s.authService, s.conf)
+	s.invitations = NewInvitationService(s.invitationRepo, s.userRepo, s.membershipRepo, s.transactor, s.passwordPolicy, s.passwordHasher, s.mailer, s.conf)
+	s.organizations = NewOrganizationService(s.orgRepo, s.membershipRepo, s.userRepo)
+	s.audit = NewAuditService(s.auditRepo)
+	s.webhooks, _ = NewWebhookService(s.webhookRepo, s.conf)
+	s.jobs = NewJobService(s.jobRepo, s.conf)
+	s.schedules = NewScheduleService(s.scheduleRepo)
+	s.presenceStore = presence.NewMemoryStore()
+	s.presenceService = NewPresenceService(s.userRepo, s.presenceStore)
+	s.tickets = NewGatewayTicketService(s.ticketRepo, s.conf)
 }
 
 func (s *ServiceTestSuite) TearDownTest() {
 	s.userRepo.ExpectedCalls = nil
+	s.sessionRepo.ExpectedCalls = nil
+	s.historyRepo.ExpectedCalls = nil
+	s.resetRepo.ExpectedCalls = nil
+	s.verifyRepo.ExpectedCalls = nil
+	s.mfaRepo.ExpectedCalls = nil
+	s.attemptRepo.ExpectedCalls = nil
+	s.throttleRepo.ExpectedCalls = nil
+	s.apiKeyRepo.ExpectedCalls = nil
+	s.stateRepo.ExpectedCalls = nil
+	s.identityRepo.ExpectedCalls = nil
+	s.clientRepo.ExpectedCalls = nil
+	s.consentRepo.ExpectedCalls = nil
+	s.codeRepo.ExpectedCalls = nil
+	s.signingKeyRepo.ExpectedCalls = nil
+	s.magicLinkRepo.ExpectedCalls = nil
+	s.passkeyRepo.ExpectedCalls = nil
+	s.challengeRepo.ExpectedCalls = nil
+	s.invitationRepo.ExpectedCalls = nil
+	s.orgRepo.ExpectedCalls = nil
+	s.membershipRepo.ExpectedCalls = nil
+	s.groupRepo.ExpectedCalls = nil
+	s.mailer.ExpectedCalls = nil
 }
 
 func TestServiceSuite(t *testing.T) {
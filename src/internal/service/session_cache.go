@@ -0,0 +1,115 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// maxSessionCacheEntries bounds the cache; stale entries are dropped once it
+// is reached.
+const maxSessionCacheEntries = 10000
+
+// sessionCacheEntry is what access-token checks need to know about a session.
+type sessionCacheEntry struct {
+	userID     uint
+	expiresAt  time.Time
+	revoked    bool
+	lastSeenAt time.Time
+	cachedAt   time.Time
+}
+
+// sessionCache keeps recently checked sessions in memory so that most
+// access-token checks do not hit the database. Revocations made through
+// this process apply at once; revocations made elsewhere are picked up when
+// the entry goes stale after ttl.
+type sessionCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]sessionCacheEntry
+}
+
+func newSessionCache(ttl time.Duration) *sessionCache {
+	return &sessionCache{
+		ttl:     ttl,
+		entries: make(map[string]sessionCacheEntry),
+	}
+}
+
+// get returns the cached entry for the session unless it is missing or stale.
+func (c *sessionCache) get(id string, now time.Time) (sessionCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[id]
+	if !ok || now.Sub(entry.cachedAt) >= c.ttl {
+		return sessionCacheEntry{}, false
+	}
+	return entry, true
+}
+
+func (c *sessionCache) put(session *model.Session, now time.Time) sessionCacheEntry {
+	entry := sessionCacheEntry{
+		userID:     session.UserID,
+		expiresAt:  session.ExpiresAt,
+		revoked:    session.RevokedAt != nil,
+		lastSeenAt: session.LastSeenAt,
+		cachedAt:   now,
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= maxSessionCacheEntries {
+		c.pruneLocked(now)
+	}
+	c.entries[session.ID] = entry
+	return entry
+}
+
+// touch records that the session was seen, keeping the entry's age.
+func (c *sessionCache) touch(id string, lastSeenAt time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[id]; ok {
+		entry.lastSeenAt = lastSeenAt
+		c.entries[id] = entry
+	}
+}
+
+func (c *sessionCache) revoke(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[id]; ok {
+		entry.revoked = true
+		c.entries[id] = entry
+	}
+}
+
+func (c *sessionCache) revokeUser(userID uint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, entry := range c.entries {
+		if entry.userID == userID {
+			entry.revoked = true
+			c.entries[id] = entry
+		}
+	}
+}
+
+// pruneLocked drops stale entries, or all of them if none is stale. The
+// caller must hold c.mu.
+func (c *sessionCache) pruneLocked(now time.Time) {
+	for id, entry := range c.entries {
+		if now.Sub(entry.cachedAt) >= c.ttl {
+			delete(c.entries, id)
+		}
+	}
+	if len(c.entries) >= maxSessionCacheEntries {
+		c.entries = make(map[string]sessionCacheEntry)
+	}
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidAccessToken = errors.New("invalid or expired access token")
+	ErrSessionNotFound    = errors.New("session not found")
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=SessionService --output=./mocks/service --outpkg=service --filename=session_service.go --structname=MockSessionService --with-expecter=false
+type SessionService interface {
+	Create(user *model.User, client *model.ClientInfo) (*model.Session, error)
+	Authenticate(accessToken string) (*model.Principal, error)
+	ListSessions(ctx context.Context, userID uint) ([]*model.SessionResponse, error)
+	Revoke(userID uint, sessionID string) error
+	RevokeAll(userID uint) error
+	RevokeUserSession(ctx context.Context, userID uint, sessionID string) error
+	RevokeUserSessions(ctx context.Context, userID uint) error
+}
+
+type sessionService struct {
+	sessionRepo      repository.SessionRepository
+	userRepo         repository.UserRepository
+	tokenService     TokenService
+	cache            *sessionCache
+	sessionTTL       time.Duration
+	lastSeenInterval time.Duration
+}
+
+func NewSessionService(
+	sessionRepo repository.SessionRepository,
+	userRepo repository.UserRepository,
+	tokenService TokenService,
+	conf *config.Config,
+) SessionService {
+	return &sessionService{
+		sessionRepo:      sessionRepo,
+		userRepo:         userRepo,
+		tokenService:     tokenService,
+		cache:            newSessionCache(conf.Auth.SessionCacheTTL),
+		sessionTTL:       conf.Auth.SessionTTL,
+		lastSeenInterval: conf.Auth.LastSeenInterval,
+	}
+}
+
+// Create starts a new session for the user on the given client.
+func (s *sessionService) Create(user *model.User, client *model.ClientInfo) (*model.Session, error) {
+	now := time.Now()
+	session := &model.Session{
+		ID:         uuid.NewString(),
+		UserID:     user.ID,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(s.sessionTTL),
+	}
+	if client != nil {
+		session.IP = client.IP
+		session.UserAgent = client.UserAgent
+		session.Device = describeDevice(client.UserAgent)
+	}
+
+	if err := s.sessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Authenticate checks an access token and the session it belongs to, and
+// updates the session's last-seen time now and then.
+func (s *sessionService) Authenticate(accessToken string) (*model.Principal, error) {
+	claims, err := s.tokenService.ParseAccessToken(accessToken)
+	if err != nil {
+		return nil, ErrInvalidAccessToken
+	}
+
+	userID, err := strconv.ParseUint(claims.Subject, 10, 32)
+	if err != nil {
+		return nil, ErrInvalidAccessToken
+	}
+
+	now := time.Now()
+
+	entry, ok := s.cache.get(claims.SessionID, now)
+	if !ok {
+		session, err := s.sessionRepo.GetByID(claims.SessionID)
+		if err != nil {
+			return nil, ErrInvalidAccessToken
+		}
+		entry = s.cache.put(session, now)
+	}
+
+	if entry.revoked || !now.Before(entry.expiresAt) || entry.userID != uint(userID) {
+		return nil, ErrInvalidAccessToken
+	}
+
+	if now.Sub(entry.lastSeenAt) >= s.lastSeenInterval {
+		// Losing a last-seen update is harmless; the next request retries.
+		if err := s.sessionRepo.Touch(claims.SessionID, now); err != nil {
+			log.Printf("Failed to update last seen time of session %s: %v", claims.SessionID, err)
+		} else {
+			s.cache.touch(claims.SessionID, now)
+		}
+	}
+
+	return &model.Principal{
+		UserID:    entry.userID,
+		SessionID: claims.SessionID,
+	}, nil
+}
+
+// ListSessions returns the active sessions of a user of the organization of
+// ctx.
+func (s *sessionService) ListSessions(ctx context.Context, userID uint) ([]*model.SessionResponse, error) {
+	if _, err := s.userRepo.WithContext(ctx).GetByID(userID); err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.sessionRepo.ListActiveForUser(userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*model.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		responses = append(responses, &model.SessionResponse{
+			ID:         session.ID,
+			Device:     session.Device,
+			IP:         session.IP,
+			UserAgent:  session.UserAgent,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+		})
+	}
+
+	return responses, nil
+}
+
+// Revoke ends one session of the user.
+func (s *sessionService) Revoke(userID uint, sessionID string) error {
+	revoked, err := s.sessionRepo.Revoke(sessionID, userID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+
+	s.cache.revoke(sessionID)
+	return nil
+}
+
+// RevokeAll ends every session of the user.
+func (s *sessionService) RevokeAll(userID uint) error {
+	if err := s.sessionRepo.RevokeAllForUser(userID, time.Now()); err != nil {
+		return err
+	}
+
+	s.cache.revokeUser(userID)
+	return nil
+}
+
+// RevokeUserSession is Revoke for a user of the organization of ctx.
+func (s *sessionService) RevokeUserSession(ctx context.Context, userID uint, sessionID string) error {
+	if _, err := s.userRepo.WithContext(ctx).GetByID(userID); err != nil {
+		return err
+	}
+	return s.Revoke(userID, sessionID)
+}
+
+// RevokeUserSessions is RevokeAll for a user of the organization of ctx.
+func (s *sessionService) RevokeUserSessions(ctx context.Context, userID uint) error {
+	if _, err := s.userRepo.WithContext(ctx).GetByID(userID); err != nil {
+		return err
+	}
+	return s.RevokeAll(userID)
+}
+
+// describeDevice turns a user agent into a short label such as
+// "Firefox on Windows". It only knows the common browsers and systems.
+func describeDevice(userAgent string) string {
+	if userAgent == "" {
+		return ""
+	}
+
+	browser := "Unknown browser"
+	switch {
+	case strings.Contains(userAgent, "Edg/"):
+		browser = "Edge"
+	case strings.Contains(userAgent, "OPR/"):
+		browser = "Opera"
+	case strings.Contains(userAgent, "Firefox/"):
+		browser = "Firefox"
+	case strings.Contains(userAgent, "Chrome/"), strings.Contains(userAgent, "CriOS/"):
+		browser = "Chrome"
+	case strings.Contains(userAgent, "Safari/"):
+		browser = "Safari"
+	case strings.Contains(userAgent, "curl/"):
+		browser = "curl"
+	}
+
+	system := ""
+	switch {
+	case strings.Contains(userAgent, "iPhone"), strings.Contains(userAgent, "iPad"):
+		system = "iOS"
+	case strings.Contains(userAgent, "Android"):
+		system = "Android"
+	case strings.Contains(userAgent, "Windows"):
+		system = "Windows"
+	case strings.Contains(userAgent, "Mac OS X"), strings.Contains(userAgent, "Macintosh"):
+		system = "macOS"
+	case strings.Contains(userAgent, "Linux"):
+		system = "Linux"
+	}
+
+	if system == "" {
+		return browser
+	}
+	return browser + " on " + system
+}
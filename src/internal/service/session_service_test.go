@@ -0,0 +1,229 @@
+package service
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/gorm"
+)
+
+// newActiveSession returns a session of user 1 and an access token for it.
+func (s *ServiceTestSuite) newActiveSession() (*model.Session, string) {
+	session := &model.Session{
+		ID:         "session-1",
+		UserID:     1,
+		LastSeenAt: time.Now(),
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+	token, _ := s.tokenService.IssueAccessToken(&model.User{ID: 1}, session)
+	return session, token
+}
+
+func (s *ServiceTestSuite) TestCreateSession_RecordsClient() {
+	s.sessionRepo.On("Create", mock.AnythingOfType("*model.Session")).Return(nil)
+
+	// Execute
+	session, err := s.sessionService.Create(&model.User{ID: 1}, &model.ClientInfo{
+		IP:        "192.0.2.1",
+		UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 14_0) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Safari/605.1.15",
+	})
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.NotEmpty(s.T(), session.ID)
+	assert.Equal(s.T(), "192.0.2.1", session.IP)
+	assert.Equal(s.T(), "Safari on macOS", session.Device)
+	assert.WithinDuration(s.T(), time.Now(), session.LastSeenAt, time.Second)
+	assert.WithinDuration(s.T(), time.Now().Add(time.Hour), session.ExpiresAt, time.Second)
+}
+
+func (s *ServiceTestSuite) TestAuthenticate_CachesSession() {
+	session, token := s.newActiveSession()
+
+	s.sessionRepo.On("GetByID", "session-1").Return(session, nil).Once()
+
+	// Execute
+	first, err := s.sessionService.Authenticate(token)
+	assert.NoError(s.T(), err)
+	second, err := s.sessionService.Authenticate(token)
+
+	// Assert: the second check is served from the cache
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), &model.Principal{UserID: 1, SessionID: "session-1"}, first)
+	assert.Equal(s.T(), first, second)
+	s.sessionRepo.AssertNumberOfCalls(s.T(), "GetByID", 1)
+	s.sessionRepo.AssertNotCalled(s.T(), "Touch", mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestAuthenticate_RevokedSession() {
+	session, token := s.newActiveSession()
+	revokedAt := time.Now().Add(-time.Minute)
+	session.RevokedAt = &revokedAt
+
+	s.sessionRepo.On("GetByID", "session-1").Return(session, nil)
+
+	// Execute
+	principal, err := s.sessionService.Authenticate(token)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidAccessToken)
+	assert.Nil(s.T(), principal)
+}
+
+func (s *ServiceTestSuite) TestAuthenticate_UnknownSession() {
+	_, token := s.newActiveSession()
+
+	s.sessionRepo.On("GetByID", "session-1").Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	_, err := s.sessionService.Authenticate(token)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidAccessToken)
+}
+
+func (s *ServiceTestSuite) TestAuthenticate_SessionOfAnotherUser() {
+	session, token := s.newActiveSession()
+	session.UserID = 2
+
+	s.sessionRepo.On("GetByID", "session-1").Return(session, nil)
+
+	// Execute
+	_, err := s.sessionService.Authenticate(token)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidAccessToken)
+}
+
+func (s *ServiceTestSuite) TestAuthenticate_MFAChallengeIsNotAnAccessToken() {
+	challenge, _ := s.tokenService.IssueMFAChallenge(&model.User{ID: 1}, time.Now().Add(time.Minute))
+
+	// Execute
+	_, err := s.sessionService.Authenticate(challenge)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrInvalidAccessToken)
+	s.sessionRepo.AssertNotCalled(s.T(), "GetByID", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestAuthenticate_UpdatesLastSeen() {
+	session, token := s.newActiveSession()
+	session.LastSeenAt = time.Now().Add(-time.Hour)
+
+	s.sessionRepo.On("GetByID", "session-1").Return(session, nil).Once()
+	s.sessionRepo.On("Touch", "session-1", mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	// Execute
+	_, err := s.sessionService.Authenticate(token)
+	assert.NoError(s.T(), err)
+	_, err = s.sessionService.Authenticate(token)
+
+	// Assert: written once, then the cached time is current
+	assert.NoError(s.T(), err)
+	s.sessionRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestRevoke_AppliesToCachedSession() {
+	session, token := s.newActiveSession()
+
+	s.sessionRepo.On("GetByID", "session-1").Return(session, nil).Once()
+	s.sessionRepo.On("Revoke", "session-1", uint(1), mock.AnythingOfType("time.Time")).Return(true, nil)
+
+	_, err := s.sessionService.Authenticate(token)
+	assert.NoError(s.T(), err)
+
+	// Execute
+	err = s.sessionService.Revoke(1, "session-1")
+
+	// Assert
+	assert.NoError(s.T(), err)
+	_, err = s.sessionService.Authenticate(token)
+	assert.ErrorIs(s.T(), err, ErrInvalidAccessToken)
+}
+
+func (s *ServiceTestSuite) TestRevoke_NotFound() {
+	s.sessionRepo.On("Revoke", "missing", uint(1), mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	// Execute
+	err := s.sessionService.Revoke(1, "missing")
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrSessionNotFound)
+}
+
+func (s *ServiceTestSuite) TestRevokeAll_AppliesToCachedSessions() {
+	session, token := s.newActiveSession()
+
+	s.sessionRepo.On("GetByID", "session-1").Return(session, nil).Once()
+	s.sessionRepo.On("RevokeAllForUser", uint(1), mock.AnythingOfType("time.Time")).Return(nil)
+
+	_, err := s.sessionService.Authenticate(token)
+	assert.NoError(s.T(), err)
+
+	// Execute
+	err = s.sessionService.RevokeAll(1)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	_, err = s.sessionService.Authenticate(token)
+	assert.ErrorIs(s.T(), err, ErrInvalidAccessToken)
+}
+
+func (s *ServiceTestSuite) TestListSessions_Success() {
+	user := s.newUserWithPassword("password123")
+	session, _ := s.newActiveSession()
+	session.Device = "Firefox on Linux"
+
+	s.userRepo.On("GetByID", uint(1)).Return(user, nil)
+	s.sessionRepo.On("ListActiveForUser", uint(1), mock.AnythingOfType("time.Time")).Return([]*model.Session{session}, nil)
+
+	// Execute
+	sessions, err := s.sessionService.ListSessions(s.ctx, 1)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	if assert.Len(s.T(), sessions, 1) {
+		assert.Equal(s.T(), "session-1", sessions[0].ID)
+		assert.Equal(s.T(), "Firefox on Linux", sessions[0].Device)
+	}
+}
+
+func (s *ServiceTestSuite) TestListSessions_UserNotFound() {
+	s.userRepo.On("GetByID", uint(999)).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	_, err := s.sessionService.ListSessions(s.ctx, 999)
+
+	// Assert
+	assert.Error(s.T(), err)
+	s.sessionRepo.AssertNotCalled(s.T(), "ListActiveForUser", mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestRevokeUserSessions_OtherOrganization() {
+	s.userRepo.On("GetByID", uint(999)).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	err := s.sessionService.RevokeUserSessions(s.ctx, 999)
+
+	// Assert: users of other organizations are not found
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+	s.sessionRepo.AssertNotCalled(s.T(), "RevokeAllForUser", mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestDescribeDevice() {
+	cases := map[string]string{
+		"": "",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0":    "Edge on Windows",
+		"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0":                                                           "Firefox on Linux",
+		"Mozilla/5.0 (Linux; Android 14) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36":                     "Chrome on Android",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile Safari/604.1": "Safari on iOS",
+		"curl/8.4.0":   "curl",
+		"custom-agent": "Unknown browser",
+	}
+
+	for userAgent, expected := range cases {
+		assert.Equal(s.T(), expected, describeDevice(userAgent), userAgent)
+	}
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrIdentityProviderDisabled = errors.New("identity provider is not configured")
+	ErrUnknownSigningKey        = errors.New("unknown signing key")
+)
+
+const (
+	// signingKeyCacheTTL bounds how long a key rotated by another instance
+	// can go unnoticed when signing.
+	signingKeyCacheTTL = time.Minute
+	signingKeyBits     = 2048
+)
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=SigningKeyService --output=./mocks/service --outpkg=service --filename=signing_key_service.go --structname=MockSigningKeyService --with-expecter=false
+type SigningKeyService interface {
+	Sign(claims jwt.Claims, tokenType string) (string, error)
+	PublicKey(keyID string) (*rsa.PublicKey, error)
+	PublicKeys() (*model.JSONWebKeySet, error)
+}
+
+// signingKey is a decrypted signing key.
+type signingKey struct {
+	id        string
+	private   *rsa.PrivateKey
+	createdAt time.Time
+}
+
+type signingKeyService struct {
+	keyRepo          repository.SigningKeyRepository
+	cipher           *secretCipher
+	rotationInterval time.Duration
+	// retention is how long a key stays published after it was created:
+	// until the last token it can have signed has expired.
+	retention time.Duration
+
+	mu       sync.Mutex
+	keys     []*signingKey
+	loadedAt time.Time
+}
+
+func NewSigningKeyService(keyRepo repository.SigningKeyRepository, conf *config.Config) (SigningKeyService, error) {
+	idp := conf.IdentityProvider
+	secretCipher, err := newSecretCipher(idp.EncryptionKey)
+	if err != nil && idp.Issuer != "" {
+		return nil, errors.New("identity_provider.encryption_key must be set")
+	}
+
+	return &signingKeyService{
+		keyRepo:          keyRepo,
+		cipher:           secretCipher,
+		rotationInterval: idp.KeyRotationInterval,
+		retention:        idp.KeyRotationInterval + idp.TokenTTL,
+	}, nil
+}
+
+// Sign signs the claims with the current key, creating a new key first if
+// the current one is due for rotation. tokenType goes into the typ header.
+func (s *signingKeyService) Sign(claims jwt.Claims, tokenType string) (string, error) {
+	key, err := s.currentKey()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = key.id
+	token.Header["typ"] = tokenType
+	return token.SignedString(key.private)
+}
+
+// PublicKey returns the published key with the ID. Keys created by another
+// instance since the last load are picked up by reloading.
+func (s *signingKeyService) PublicKey(keyID string) (*rsa.PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.loadLocked(false); err != nil {
+		return nil, err
+	}
+	if key := s.findLocked(keyID); key != nil {
+		return &key.private.PublicKey, nil
+	}
+
+	if err := s.loadLocked(true); err != nil {
+		return nil, err
+	}
+	if key := s.findLocked(keyID); key != nil {
+		return &key.private.PublicKey, nil
+	}
+	return nil, ErrUnknownSigningKey
+}
+
+// PublicKeys returns every key that may have signed an unexpired token.
+func (s *signingKeyService) PublicKeys() (*model.JSONWebKeySet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.loadLocked(false); err != nil {
+		return nil, err
+	}
+
+	set := &model.JSONWebKeySet{Keys: make([]model.JSONWebKey, 0, len(s.keys))}
+	for _, key := range s.keys {
+		public := key.private.PublicKey
+		set.Keys = append(set.Keys, model.JSONWebKey{
+			Kty: "RSA",
+			Kid: key.id,
+			Use: "sig",
+			Alg: jwt.SigningMethodRS256.Alg(),
+			N:   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		})
+	}
+	return set, nil
+}
+
+func (s *signingKeyService) currentKey() (*signingKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.loadLocked(false); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if len(s.keys) > 0 && now.Sub(s.keys[0].createdAt) < s.rotationInterval {
+		return s.keys[0], nil
+	}
+
+	key, err := s.createLocked(now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Old keys are only kept while they can verify unexpired tokens
+	cutoff := now.Add(-s.retention)
+	if err := s.keyRepo.DeleteCreatedBefore(cutoff); err != nil {
+		log.Printf("Failed to delete old signing keys: %v", err)
+	}
+
+	keys := []*signingKey{key}
+	for _, old := range s.keys {
+		if !old.createdAt.Before(cutoff) {
+			keys = append(keys, old)
+		}
+	}
+	s.keys = keys
+	return key, nil
+}
+
+// loadLocked reads the published keys unless the cached ones are recent
+// enough.
+func (s *signingKeyService) loadLocked(force bool) error {
+	if s.cipher == nil {
+		return ErrIdentityProviderDisabled
+	}
+
+	now := time.Now()
+	if !force && !s.loadedAt.IsZero() && now.Sub(s.loadedAt) < signingKeyCacheTTL {
+		return nil
+	}
+
+	stored, err := s.keyRepo.ListCreatedSince(now.Add(-s.retention))
+	if err != nil {
+		return err
+	}
+
+	keys := make([]*signingKey, 0, len(stored))
+	for _, key := range stored {
+		der, err := s.cipher.Decrypt(key.PrivateKeyEncrypted)
+		if err != nil {
+			return fmt.Errorf("decrypt signing key %s: %w", key.ID, err)
+		}
+		private, err := x509.ParsePKCS1PrivateKey(der)
+		if err != nil {
+			return fmt.Errorf("parse signing key %s: %w", key.ID, err)
+		}
+		keys = append(keys, &signingKey{id: key.ID, private: private, createdAt: key.CreatedAt})
+	}
+
+	s.keys = keys
+	s.loadedAt = now
+	return nil
+}
+
+func (s *signingKeyService) createLocked(now time.Time) (*signingKey, error) {
+	private, err := rsa.GenerateKey(rand.Reader, signingKeyBits)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := s.cipher.Encrypt(x509.MarshalPKCS1PrivateKey(private))
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	key := &model.SigningKey{
+		ID:                  hex.EncodeToString(buf),
+		PrivateKeyEncrypted: encrypted,
+		CreatedAt:           now,
+	}
+	if err := s.keyRepo.Create(key); err != nil {
+		return nil, err
+	}
+
+	return &signingKey{id: key.ID, private: private, createdAt: now}, nil
+}
+
+func (s *signingKeyService) findLocked(keyID string) *signingKey {
+	for _, key := range s.keys {
+		if key.id == keyID {
+			return key
+		}
+	}
+	return nil
+}
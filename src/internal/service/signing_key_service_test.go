@@ -0,0 +1,124 @@
+package service
+
+import (
+	"crypto/x509"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+)
+
+// storedSigningKey returns the suite's RSA key as the repository stores it.
+func (s *ServiceTestSuite) storedSigningKey(id string, createdAt time.Time) *model.SigningKey {
+	cipher, _ := newSecretCipher("test-idp-key")
+	encrypted, _ := cipher.Encrypt(x509.MarshalPKCS1PrivateKey(s.rsaKey))
+	return &model.SigningKey{ID: id, PrivateKeyEncrypted: encrypted, CreatedAt: createdAt}
+}
+
+func (s *ServiceTestSuite) TestSign_UsesCurrentKey() {
+	s.signingKeyRepo.On("ListCreatedSince", mock.AnythingOfType("time.Time")).
+		Return([]*model.SigningKey{s.storedSigningKey("key-1", time.Now().Add(-time.Hour))}, nil)
+
+	// Execute
+	signed, err := s.signingKeys.Sign(jwt.MapClaims{"sub": "1"}, "JWT")
+
+	// Assert
+	assert.NoError(s.T(), err)
+	token, err := jwt.Parse(signed, func(*jwt.Token) (interface{}, error) { return &s.rsaKey.PublicKey, nil })
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "key-1", token.Header["kid"])
+	assert.Equal(s.T(), "JWT", token.Header["typ"])
+	s.signingKeyRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestSign_CreatesFirstKey() {
+	s.signingKeyRepo.On("ListCreatedSince", mock.AnythingOfType("time.Time")).Return([]*model.SigningKey{}, nil)
+	s.signingKeyRepo.On("Create", mock.AnythingOfType("*model.SigningKey")).Return(nil)
+	s.signingKeyRepo.On("DeleteCreatedBefore", mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Execute
+	signed, err := s.signingKeys.Sign(jwt.MapClaims{"sub": "1"}, "JWT")
+
+	// Assert: the stored key is encrypted and verifies the token
+	assert.NoError(s.T(), err)
+	stored := s.signingKeyRepo.Calls[1].Arguments.Get(0).(*model.SigningKey)
+	cipher, _ := newSecretCipher("test-idp-key")
+	der, err := cipher.Decrypt(stored.PrivateKeyEncrypted)
+	assert.NoError(s.T(), err)
+	private, _ := x509.ParsePKCS1PrivateKey(der)
+
+	token, err := jwt.Parse(signed, func(*jwt.Token) (interface{}, error) { return &private.PublicKey, nil })
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), stored.ID, token.Header["kid"])
+
+	// It is published right away
+	set, err := s.signingKeys.PublicKeys()
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), set.Keys, 1)
+	assert.Equal(s.T(), stored.ID, set.Keys[0].Kid)
+}
+
+func (s *ServiceTestSuite) TestSign_RotatesOldKey() {
+	s.signingKeyRepo.On("ListCreatedSince", mock.AnythingOfType("time.Time")).
+		Return([]*model.SigningKey{s.storedSigningKey("key-1", time.Now().Add(-24*time.Hour-30*time.Minute))}, nil)
+	s.signingKeyRepo.On("Create", mock.AnythingOfType("*model.SigningKey")).Return(nil)
+	s.signingKeyRepo.On("DeleteCreatedBefore", mock.AnythingOfType("time.Time")).Return(nil)
+
+	// Execute
+	signed, err := s.signingKeys.Sign(jwt.MapClaims{"sub": "1"}, "JWT")
+
+	// Assert: the old key stays published for tokens it signed
+	assert.NoError(s.T(), err)
+	token, _, _ := jwt.NewParser().ParseUnverified(signed, jwt.MapClaims{})
+	assert.NotEqual(s.T(), "key-1", token.Header["kid"])
+
+	set, _ := s.signingKeys.PublicKeys()
+	assert.Len(s.T(), set.Keys, 2)
+	assert.Equal(s.T(), token.Header["kid"], set.Keys[0].Kid)
+	assert.Equal(s.T(), "key-1", set.Keys[1].Kid)
+	assert.Equal(s.T(), "AQAB", set.Keys[1].E)
+
+	// Keys older than the rotation interval plus the token lifetime go
+	cutoff := s.signingKeyRepo.Calls[2].Arguments.Get(0).(time.Time)
+	assert.WithinDuration(s.T(), time.Now().Add(-25*time.Hour), cutoff, time.Minute)
+}
+
+func (s *ServiceTestSuite) TestPublicKey_ReloadsForUnknownKey() {
+	s.signingKeyRepo.On("ListCreatedSince", mock.AnythingOfType("time.Time")).Return([]*model.SigningKey{}, nil).Once()
+	s.signingKeyRepo.On("ListCreatedSince", mock.AnythingOfType("time.Time")).
+		Return([]*model.SigningKey{s.storedSigningKey("key-2", time.Now())}, nil).Once()
+
+	// Execute
+	key, err := s.signingKeys.PublicKey("key-2")
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), s.rsaKey.PublicKey.Equal(key))
+}
+
+func (s *ServiceTestSuite) TestPublicKey_Unknown() {
+	s.signingKeyRepo.On("ListCreatedSince", mock.AnythingOfType("time.Time")).Return([]*model.SigningKey{}, nil)
+
+	// Execute
+	_, err := s.signingKeys.PublicKey("missing")
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrUnknownSigningKey)
+}
+
+func (s *ServiceTestSuite) TestNewSigningKeyService_RequiresEncryptionKey() {
+	conf := &config.Config{IdentityProvider: config.IdentityProviderConfig{Issuer: "http://localhost:8080"}}
+
+	_, err := NewSigningKeyService(s.signingKeyRepo, conf)
+
+	assert.Error(s.T(), err)
+
+	// Without an issuer the provider is off and needs no key
+	keys, err := NewSigningKeyService(s.signingKeyRepo, &config.Config{})
+	assert.NoError(s.T(), err)
+	_, err = keys.PublicKeys()
+	assert.ErrorIs(s.T(), err, ErrIdentityProviderDisabled)
+}
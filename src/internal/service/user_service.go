@@ -1,46 +1,99 @@
 package service
 
 import (
+	"context"
 	"errors"
+	"log"
+	"time"
 
+	"github.com/weeranieb/go-kit-base/src/internal/hasher"
 	"github.com/weeranieb/go-kit-base/src/internal/model"
 	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
 
-	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrVersionMismatch is returned when an update was made against a
+	// version of the user that is no longer current.
+	ErrVersionMismatch = errors.New("user was modified by another request")
+	ErrUsernameTaken   = errors.New("username already exists")
+	// ErrUserVersionNotFound is returned for versions a user never had,
+	// including any before it was created.
+	ErrUserVersionNotFound = errors.New("user version not found")
 )
 
 //go:generate go run github.com/vektra/mockery/v2@latest --name=UserService --output=./mocks/service --outpkg=service --filename=user_service.go --structname=MockUserService --with-expecter=false
 type UserService interface {
-	CreateUser(req *model.CreateUserRequest) (*model.UserResponse, error)
-	GetUser(id uint) (*model.UserResponse, error)
-	UpdateUser(id uint, req *model.UpdateUserRequest) (*model.UserResponse, error)
-	DeleteUser(id uint) error
-	ListUsers(limit, offset int) ([]*model.UserResponse, error)
+	CreateUser(ctx context.Context, req *model.CreateUserRequest) (*model.UserResponse, error)
+	GetUser(ctx context.Context, id uint) (*model.UserResponse, error)
+	UpdateUser(ctx context.Context, id uint, version uint, req *model.UpdateUserRequest) (*model.UserResponse, error)
+	DeleteUser(ctx context.Context, id uint) error
+	ListUsers(ctx context.Context, limit, offset int) ([]*model.UserResponse, error)
+	ListUserVersions(ctx context.Context, id uint, limit, offset int) ([]*model.UserVersionResponse, error)
+	GetUserVersion(ctx context.Context, id uint, version uint) (*model.UserVersionResponse, error)
+	GetUserAsOf(ctx context.Context, id uint, at time.Time) (*model.UserResponse, error)
 }
 
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo       repository.UserRepository
+	versionRepo    repository.UserVersionRepository
+	passwordPolicy PasswordPolicy
+	passwordHasher hasher.PasswordHasher
+	verification   EmailVerificationService
+	sessions       SessionService
+	apiKeys        APIKeyService
 }
 
-func NewUserService(userRepo repository.UserRepository) UserService {
-	return &userService{userRepo: userRepo}
+func NewUserService(
+	userRepo repository.UserRepository,
+	versionRepo repository.UserVersionRepository,
+	passwordPolicy PasswordPolicy,
+	passwordHasher hasher.PasswordHasher,
+	verification EmailVerificationService,
+	sessions SessionService,
+	apiKeys APIKeyService,
+) UserService {
+	return &userService{
+		userRepo:       userRepo,
+		versionRepo:    versionRepo,
+		passwordPolicy: passwordPolicy,
+		passwordHasher: passwordHasher,
+		verification:   verification,
+		sessions:       sessions,
+		apiKeys:        apiKeys,
+	}
 }
 
-func (s *userService) CreateUser(req *model.CreateUserRequest) (*model.UserResponse, error) {
-	// Check if email already exists
-	existingUser, _ := s.userRepo.GetByEmail(req.Email)
+// CreateUser creates the user; the repository adds its UserCreated event in
+// the same transaction.
+func (s *userService) CreateUser(ctx context.Context, req *model.CreateUserRequest) (*model.UserResponse, error) {
+	users := s.userRepo.WithContext(ctx)
+
+	// Check if email already exists; addresses are unique across
+	// organizations
+	existingUser, _ := s.userRepo.WithContext(tenant.Unscoped(ctx)).GetByEmail(req.Email)
 	if existingUser != nil {
-		return nil, errors.New("email already exists")
+		return nil, ErrEmailTaken
 	}
 
-	// Check if username already exists
-	existingUser, _ = s.userRepo.GetByUsername(req.Username)
+	// Check if username already exists in the organization
+	existingUser, _ = users.GetByUsername(req.Username)
 	if existingUser != nil {
-		return nil, errors.New("username already exists")
+		return nil, ErrUsernameTaken
+	}
+
+	err := s.passwordPolicy.Validate(req.Password, &model.User{
+		Username: req.Username,
+		Email:    req.Email,
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	hashedPassword, err := s.passwordHasher.Hash(req.Password)
 	if err != nil {
 		return nil, err
 	}
@@ -48,81 +101,200 @@ func (s *userService) CreateUser(req *model.CreateUserRequest) (*model.UserRespo
 	user := &model.User{
 		Username: req.Username,
 		Email:    req.Email,
-		Password: string(hashedPassword),
+		Password: hashedPassword,
+		Role:     model.RoleUser,
 	}
 
-	err = s.userRepo.Create(user)
-	if err != nil {
+	if err := s.userRepo.WithContext(ctx).Create(user); err != nil {
 		return nil, err
 	}
 
-	return s.toUserResponse(user), nil
+	s.sendVerification(user)
+
+	return toUserResponse(user), nil
 }
 
-func (s *userService) GetUser(id uint) (*model.UserResponse, error) {
-	user, err := s.userRepo.GetByID(id)
+func (s *userService) GetUser(ctx context.Context, id uint) (*model.UserResponse, error) {
+	user, err := s.userRepo.WithContext(ctx).GetByID(id)
 	if err != nil {
 		return nil, err
 	}
-	return s.toUserResponse(user), nil
+	return toUserResponse(user), nil
 }
 
-func (s *userService) UpdateUser(id uint, req *model.UpdateUserRequest) (*model.UserResponse, error) {
-	user, err := s.userRepo.GetByID(id)
+// UpdateUser applies req to the user. A non-zero version must match the
+// user's current version, otherwise ErrVersionMismatch is returned. A new
+// email is only stored as pending until its owner confirms it.
+func (s *userService) UpdateUser(ctx context.Context, id uint, version uint, req *model.UpdateUserRequest) (*model.UserResponse, error) {
+	users := s.userRepo.WithContext(ctx)
+
+	user, err := users.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
 
+	if version != 0 && user.Version != version {
+		return nil, ErrVersionMismatch
+	}
+
 	if req.Username != "" {
 		// Check if new username already exists
-		existingUser, _ := s.userRepo.GetByUsername(req.Username)
+		existingUser, _ := users.GetByUsername(req.Username)
 		if existingUser != nil && existingUser.ID != id {
-			return nil, errors.New("username already exists")
+			return nil, ErrUsernameTaken
 		}
 		user.Username = req.Username
 	}
 
-	if req.Email != "" {
+	emailChanged := false
+	if req.Email == user.Email {
+		// Asking for the current email cancels a pending change
+		user.PendingEmail = nil
+	} else if req.Email != "" && (user.PendingEmail == nil || *user.PendingEmail != req.Email) {
 		// Check if new email already exists
-		existingUser, _ := s.userRepo.GetByEmail(req.Email)
+		existingUser, _ := s.userRepo.WithContext(tenant.Unscoped(ctx)).GetByEmail(req.Email)
 		if existingUser != nil && existingUser.ID != id {
-			return nil, errors.New("email already exists")
+			return nil, ErrEmailTaken
 		}
-		user.Email = req.Email
+		pendingEmail := req.Email
+		user.PendingEmail = &pendingEmail
+		emailChanged = true
 	}
 
-	err = s.userRepo.Update(user)
+	err = users.Update(user)
+	if errors.Is(err, repository.ErrVersionConflict) {
+		return nil, ErrVersionMismatch
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return s.toUserResponse(user), nil
+	if emailChanged {
+		s.sendVerification(user)
+	}
+
+	return toUserResponse(user), nil
 }
 
-func (s *userService) DeleteUser(id uint) error {
-	return s.userRepo.Delete(id)
+// DeleteUser soft-deletes the user and revokes its sessions and API keys.
+func (s *userService) DeleteUser(ctx context.Context, id uint) error {
+	users := s.userRepo.WithContext(ctx)
+
+	// Look the user up first so that the sessions of a user of another
+	// organization are left alone
+	user, err := users.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := users.Delete(user.ID); err != nil {
+		return err
+	}
+	if err := s.sessions.RevokeAll(id); err != nil {
+		return err
+	}
+	return s.apiKeys.RevokeAllForUser(id)
 }
 
-func (s *userService) ListUsers(limit, offset int) ([]*model.UserResponse, error) {
-	users, err := s.userRepo.List(limit, offset)
+func (s *userService) ListUsers(ctx context.Context, limit, offset int) ([]*model.UserResponse, error) {
+	users, err := s.userRepo.WithContext(ctx).List(limit, offset)
 	if err != nil {
 		return nil, err
 	}
 
 	responses := make([]*model.UserResponse, 0, len(users))
 	for _, user := range users {
-		responses = append(responses, s.toUserResponse(user))
+		responses = append(responses, toUserResponse(user))
 	}
 
 	return responses, nil
 }
 
-func (s *userService) toUserResponse(user *model.User) *model.UserResponse {
+// ListUserVersions returns a page of the history of the user, newest first.
+func (s *userService) ListUserVersions(ctx context.Context, id uint, limit, offset int) ([]*model.UserVersionResponse, error) {
+	versions, err := s.versionRepo.WithContext(ctx).List(id, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*model.UserVersionResponse, 0, len(versions))
+	for _, version := range versions {
+		responses = append(responses, toUserVersionResponse(version))
+	}
+	return responses, nil
+}
+
+func (s *userService) GetUserVersion(ctx context.Context, id uint, version uint) (*model.UserVersionResponse, error) {
+	userVersion, err := s.versionRepo.WithContext(ctx).Get(id, version)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserVersionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return toUserVersionResponse(userVersion), nil
+}
+
+// GetUserAsOf returns the user as it was at the time, from the version that
+// was current then. The user must still exist.
+func (s *userService) GetUserAsOf(ctx context.Context, id uint, at time.Time) (*model.UserResponse, error) {
+	user, err := s.userRepo.WithContext(ctx).GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	version, err := s.versionRepo.WithContext(ctx).GetAsOf(id, at)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrUserVersionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.UserResponse{
+		ID:              user.ID,
+		OrganizationID:  version.OrganizationID,
+		Username:        version.Username,
+		Email:           version.Email,
+		Role:            version.Role,
+		EmailVerifiedAt: version.EmailVerifiedAt,
+		PendingEmail:    version.PendingEmail,
+		Version:         version.Version,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       version.CreatedAt,
+	}, nil
+}
+
+// sendVerification mails a verification link after the user has been saved.
+// A failure is only logged since the user can ask for the link again.
+func (s *userService) sendVerification(user *model.User) {
+	if err := s.verification.SendVerification(user); err != nil {
+		log.Printf("Failed to send verification email to user %d: %v", user.ID, err)
+	}
+}
+
+func toUserVersionResponse(version *model.UserVersion) *model.UserVersionResponse {
+	return &model.UserVersionResponse{
+		Version:         version.Version,
+		Username:        version.Username,
+		Email:           version.Email,
+		PendingEmail:    version.PendingEmail,
+		Role:            version.Role,
+		EmailVerifiedAt: version.EmailVerifiedAt,
+		CreatedAt:       version.CreatedAt,
+	}
+}
+
+func toUserResponse(user *model.User) *model.UserResponse {
 	return &model.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:              user.ID,
+		OrganizationID:  user.OrganizationID,
+		Username:        user.Username,
+		Email:           user.Email,
+		Role:            user.Role,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		PendingEmail:    user.PendingEmail,
+		Version:         user.Version,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
 	}
 }
@@ -6,7 +6,10 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/mailer"
 	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"gorm.io/gorm"
 )
 
 func (s *ServiceTestSuite) TestCreateUser_Success() {
@@ -35,9 +38,12 @@ func (s *ServiceTestSuite) TestCreateUser_Success() {
 		user.CreatedAt = expectedUser.CreatedAt
 		user.UpdatedAt = expectedUser.UpdatedAt
 	})
+	s.verifyRepo.On("InvalidateForUser", uint(1), mock.AnythingOfType("time.Time")).Return(nil)
+	s.verifyRepo.On("Create", mock.AnythingOfType("*model.EmailVerificationToken")).Return(nil)
+	s.mailer.On("Send", mock.AnythingOfType("*mailer.Message")).Return(nil)
 
 	// Execute
-	result, err := s.userService.CreateUser(req)
+	result, err := s.userService.CreateUser(s.ctx, req)
 
 	// Assert
 	assert.NoError(s.T(), err)
@@ -45,7 +51,55 @@ func (s *ServiceTestSuite) TestCreateUser_Success() {
 	assert.Equal(s.T(), req.Username, result.Username)
 	assert.Equal(s.T(), req.Email, result.Email)
 	assert.Equal(s.T(), expectedUser.ID, result.ID)
+	assert.Nil(s.T(), result.EmailVerifiedAt)
 	s.userRepo.AssertExpectations(s.T())
+
+	// A verification link is sent to the new address
+	token := s.verifyRepo.Calls[1].Arguments.Get(0).(*model.EmailVerificationToken)
+	assert.Equal(s.T(), req.Email, token.Email)
+	s.mailer.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestCreateUser_VerificationMailFailure() {
+	req := &model.CreateUserRequest{
+		Username: "testuser",
+		Email:    "test@example.com",
+		Password: "password123",
+	}
+
+	s.userRepo.On("GetByEmail", req.Email).Return(nil, errors.New("not found"))
+	s.userRepo.On("GetByUsername", req.Username).Return(nil, errors.New("not found"))
+	s.userRepo.On("Create", mock.AnythingOfType("*model.User")).Return(nil)
+	s.verifyRepo.On("InvalidateForUser", mock.Anything, mock.Anything).Return(nil)
+	s.verifyRepo.On("Create", mock.AnythingOfType("*model.EmailVerificationToken")).Return(nil)
+	s.mailer.On("Send", mock.AnythingOfType("*mailer.Message")).Return(errors.New("smtp unavailable"))
+
+	// Execute
+	result, err := s.userService.CreateUser(s.ctx, req)
+
+	// Assert: the user is created and can ask for the link again
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), result)
+}
+
+func (s *ServiceTestSuite) TestCreateUser_NotStored() {
+	req := &model.CreateUserRequest{
+		Username: "testuser",
+		Email:    "test@example.com",
+		Password: "password123",
+	}
+
+	s.userRepo.On("GetByEmail", req.Email).Return(nil, errors.New("not found"))
+	s.userRepo.On("GetByUsername", req.Username).Return(nil, errors.New("not found"))
+	s.userRepo.On("Create", mock.AnythingOfType("*model.User")).Return(errors.New("insert failed"))
+
+	// Execute
+	result, err := s.userService.CreateUser(s.ctx, req)
+
+	// Assert: no link goes out for a user that was never stored
+	assert.Error(s.T(), err)
+	assert.Nil(s.T(), result)
+	s.mailer.AssertNotCalled(s.T(), "Send", mock.Anything)
 }
 
 func (s *ServiceTestSuite) TestCreateUser_EmailExists() {
@@ -64,7 +118,7 @@ func (s *ServiceTestSuite) TestCreateUser_EmailExists() {
 	s.userRepo.On("GetByEmail", req.Email).Return(existingUser, nil)
 
 	// Execute
-	result, err := s.userService.CreateUser(req)
+	result, err := s.userService.CreateUser(s.ctx, req)
 
 	// Assert
 	assert.Error(s.T(), err)
@@ -73,6 +127,28 @@ func (s *ServiceTestSuite) TestCreateUser_EmailExists() {
 	s.userRepo.AssertExpectations(s.T())
 }
 
+func (s *ServiceTestSuite) TestCreateUser_PasswordPolicyViolation() {
+	req := &model.CreateUserRequest{
+		Username: "testuser",
+		Email:    "test@example.com",
+		Password: "testuser1",
+	}
+
+	s.userRepo.On("GetByEmail", req.Email).Return(nil, errors.New("not found"))
+	s.userRepo.On("GetByUsername", req.Username).Return(nil, errors.New("not found"))
+
+	// Execute
+	result, err := s.userService.CreateUser(s.ctx, req)
+
+	// Assert
+	var policyErr *PasswordPolicyError
+	assert.ErrorAs(s.T(), err, &policyErr)
+	assert.Equal(s.T(), []string{"must not contain the username or email address"}, policyErr.Violations)
+	assert.Nil(s.T(), result)
+	s.userRepo.AssertNotCalled(s.T(), "Create", mock.Anything)
+	s.userRepo.AssertExpectations(s.T())
+}
+
 func (s *ServiceTestSuite) TestCreateUser_UsernameExists() {
 	req := &model.CreateUserRequest{
 		Username: "existinguser",
@@ -90,7 +166,7 @@ func (s *ServiceTestSuite) TestCreateUser_UsernameExists() {
 	s.userRepo.On("GetByUsername", req.Username).Return(existingUser, nil)
 
 	// Execute
-	result, err := s.userService.CreateUser(req)
+	result, err := s.userService.CreateUser(s.ctx, req)
 
 	// Assert
 	assert.Error(s.T(), err)
@@ -112,7 +188,7 @@ func (s *ServiceTestSuite) TestGetUser_Success() {
 	s.userRepo.On("GetByID", userID).Return(expectedUser, nil)
 
 	// Execute
-	result, err := s.userService.GetUser(userID)
+	result, err := s.userService.GetUser(s.ctx, userID)
 
 	// Assert
 	assert.NoError(s.T(), err)
@@ -129,7 +205,7 @@ func (s *ServiceTestSuite) TestGetUser_NotFound() {
 	s.userRepo.On("GetByID", userID).Return(nil, errors.New("user not found"))
 
 	// Execute
-	result, err := s.userService.GetUser(userID)
+	result, err := s.userService.GetUser(s.ctx, userID)
 
 	// Assert
 	assert.Error(s.T(), err)
@@ -156,16 +232,73 @@ func (s *ServiceTestSuite) TestUpdateUser_Success() {
 	s.userRepo.On("GetByUsername", req.Username).Return(nil, errors.New("not found"))
 	s.userRepo.On("GetByEmail", req.Email).Return(nil, errors.New("not found"))
 	s.userRepo.On("Update", mock.AnythingOfType("*model.User")).Return(nil)
+	s.verifyRepo.On("InvalidateForUser", userID, mock.AnythingOfType("time.Time")).Return(nil)
+	s.verifyRepo.On("Create", mock.AnythingOfType("*model.EmailVerificationToken")).Return(nil)
+	s.mailer.On("Send", mock.AnythingOfType("*mailer.Message")).Return(nil)
 
 	// Execute
-	result, err := s.userService.UpdateUser(userID, req)
+	result, err := s.userService.UpdateUser(s.ctx, userID, 0, req)
 
-	// Assert
+	// Assert: the email only changes once the new address is confirmed
 	assert.NoError(s.T(), err)
 	assert.NotNil(s.T(), result)
 	assert.Equal(s.T(), req.Username, result.Username)
-	assert.Equal(s.T(), req.Email, result.Email)
+	assert.Equal(s.T(), "old@example.com", result.Email)
+	assert.Equal(s.T(), req.Email, *result.PendingEmail)
 	s.userRepo.AssertExpectations(s.T())
+
+	token := s.verifyRepo.Calls[1].Arguments.Get(0).(*model.EmailVerificationToken)
+	assert.Equal(s.T(), req.Email, token.Email)
+	assert.Equal(s.T(), req.Email, s.mailer.Calls[0].Arguments.Get(0).(*mailer.Message).To)
+}
+
+func (s *ServiceTestSuite) TestUpdateUser_SamePendingEmailNotResent() {
+	userID := uint(1)
+	pendingEmail := "new@example.com"
+	req := &model.UpdateUserRequest{Email: pendingEmail}
+
+	existingUser := &model.User{
+		ID:           userID,
+		Username:     "olduser",
+		Email:        "old@example.com",
+		PendingEmail: &pendingEmail,
+	}
+
+	s.userRepo.On("GetByID", userID).Return(existingUser, nil)
+	s.userRepo.On("Update", mock.AnythingOfType("*model.User")).Return(nil)
+
+	// Execute
+	result, err := s.userService.UpdateUser(s.ctx, userID, 0, req)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), pendingEmail, *result.PendingEmail)
+	s.mailer.AssertNotCalled(s.T(), "Send", mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestUpdateUser_CurrentEmailCancelsPendingChange() {
+	userID := uint(1)
+	pendingEmail := "new@example.com"
+	req := &model.UpdateUserRequest{Email: "old@example.com"}
+
+	existingUser := &model.User{
+		ID:           userID,
+		Username:     "olduser",
+		Email:        "old@example.com",
+		PendingEmail: &pendingEmail,
+	}
+
+	s.userRepo.On("GetByID", userID).Return(existingUser, nil)
+	s.userRepo.On("Update", mock.AnythingOfType("*model.User")).Return(nil)
+
+	// Execute
+	result, err := s.userService.UpdateUser(s.ctx, userID, 0, req)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "old@example.com", result.Email)
+	assert.Nil(s.T(), result.PendingEmail)
+	s.mailer.AssertNotCalled(s.T(), "Send", mock.Anything)
 }
 
 func (s *ServiceTestSuite) TestUpdateUser_NotFound() {
@@ -177,7 +310,7 @@ func (s *ServiceTestSuite) TestUpdateUser_NotFound() {
 	s.userRepo.On("GetByID", userID).Return(nil, errors.New("user not found"))
 
 	// Execute
-	result, err := s.userService.UpdateUser(userID, req)
+	result, err := s.userService.UpdateUser(s.ctx, userID, 0, req)
 
 	// Assert
 	assert.Error(s.T(), err)
@@ -207,7 +340,7 @@ func (s *ServiceTestSuite) TestUpdateUser_UsernameExists() {
 	s.userRepo.On("GetByUsername", req.Username).Return(conflictingUser, nil)
 
 	// Execute
-	result, err := s.userService.UpdateUser(userID, req)
+	result, err := s.userService.UpdateUser(s.ctx, userID, 0, req)
 
 	// Assert
 	assert.Error(s.T(), err)
@@ -216,30 +349,134 @@ func (s *ServiceTestSuite) TestUpdateUser_UsernameExists() {
 	s.userRepo.AssertExpectations(s.T())
 }
 
+func (s *ServiceTestSuite) TestUpdateUser_MatchingVersion() {
+	userID := uint(1)
+	req := &model.UpdateUserRequest{
+		Username: "updateduser",
+	}
+
+	existingUser := &model.User{
+		ID:       userID,
+		Username: "olduser",
+		Email:    "old@example.com",
+		Version:  3,
+	}
+
+	s.userRepo.On("GetByID", userID).Return(existingUser, nil)
+	s.userRepo.On("GetByUsername", req.Username).Return(nil, errors.New("not found"))
+	s.userRepo.On("Update", mock.AnythingOfType("*model.User")).Return(nil).Run(func(args mock.Arguments) {
+		user := args.Get(0).(*model.User)
+		user.Version++
+	})
+
+	// Execute
+	result, err := s.userService.UpdateUser(s.ctx, userID, 3, req)
+
+	// Assert
+	assert.NoError(s.T(), err)
+	assert.NotNil(s.T(), result)
+	assert.Equal(s.T(), uint(4), result.Version)
+	s.userRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestUpdateUser_VersionMismatch() {
+	userID := uint(1)
+	req := &model.UpdateUserRequest{
+		Username: "updateduser",
+	}
+
+	existingUser := &model.User{
+		ID:       userID,
+		Username: "olduser",
+		Email:    "old@example.com",
+		Version:  3,
+	}
+
+	s.userRepo.On("GetByID", userID).Return(existingUser, nil)
+
+	// Execute
+	result, err := s.userService.UpdateUser(s.ctx, userID, 2, req)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrVersionMismatch)
+	assert.Nil(s.T(), result)
+	s.userRepo.AssertNotCalled(s.T(), "Update", mock.Anything)
+	s.userRepo.AssertExpectations(s.T())
+}
+
+func (s *ServiceTestSuite) TestUpdateUser_ConcurrentModification() {
+	userID := uint(1)
+	req := &model.UpdateUserRequest{
+		Username: "updateduser",
+	}
+
+	existingUser := &model.User{
+		ID:       userID,
+		Username: "olduser",
+		Email:    "old@example.com",
+		Version:  3,
+	}
+
+	s.userRepo.On("GetByID", userID).Return(existingUser, nil)
+	s.userRepo.On("GetByUsername", req.Username).Return(nil, errors.New("not found"))
+	s.userRepo.On("Update", mock.AnythingOfType("*model.User")).Return(repository.ErrVersionConflict)
+
+	// Execute
+	result, err := s.userService.UpdateUser(s.ctx, userID, 0, req)
+
+	// Assert
+	assert.ErrorIs(s.T(), err, ErrVersionMismatch)
+	assert.Nil(s.T(), result)
+	s.userRepo.AssertExpectations(s.T())
+}
+
 func (s *ServiceTestSuite) TestDeleteUser_Success() {
 	userID := uint(1)
 
+	user := s.newVerifiedUser()
+	user.OrganizationID = 1
+
+	s.userRepo.On("GetByID", userID).Return(user, nil)
 	s.userRepo.On("Delete", userID).Return(nil)
+	s.sessionRepo.On("RevokeAllForUser", userID, mock.AnythingOfType("time.Time")).Return(nil)
+	s.apiKeyRepo.On("RevokeAllForUser", userID, mock.AnythingOfType("time.Time")).Return(nil)
 
 	// Execute
-	err := s.userService.DeleteUser(userID)
+	err := s.userService.DeleteUser(s.ctx, userID)
 
-	// Assert
+	// Assert: the user's sessions and API keys end with it
 	assert.NoError(s.T(), err)
 	s.userRepo.AssertExpectations(s.T())
+	s.sessionRepo.AssertExpectations(s.T())
+	s.apiKeyRepo.AssertExpectations(s.T())
 }
 
 func (s *ServiceTestSuite) TestDeleteUser_Error() {
 	userID := uint(999)
 
+	s.userRepo.On("GetByID", userID).Return(&model.User{ID: userID}, nil)
 	s.userRepo.On("Delete", userID).Return(errors.New("delete failed"))
 
 	// Execute
-	err := s.userService.DeleteUser(userID)
+	err := s.userService.DeleteUser(s.ctx, userID)
 
 	// Assert
 	assert.Error(s.T(), err)
 	s.userRepo.AssertExpectations(s.T())
+	s.sessionRepo.AssertNotCalled(s.T(), "RevokeAllForUser", mock.Anything, mock.Anything)
+	s.apiKeyRepo.AssertNotCalled(s.T(), "RevokeAllForUser", mock.Anything, mock.Anything)
+}
+
+func (s *ServiceTestSuite) TestDeleteUser_OtherOrganization() {
+	s.userRepo.On("GetByID", uint(7)).Return(nil, gorm.ErrRecordNotFound)
+
+	// Execute
+	err := s.userService.DeleteUser(s.ctx, 7)
+
+	// Assert: the users of other organizations keep their sessions
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+	s.userRepo.AssertNotCalled(s.T(), "Delete", mock.Anything)
+	s.sessionRepo.AssertNotCalled(s.T(), "RevokeAllForUser", mock.Anything, mock.Anything)
 }
 
 func (s *ServiceTestSuite) TestListUsers_Success() {
@@ -266,7 +503,7 @@ func (s *ServiceTestSuite) TestListUsers_Success() {
 	s.userRepo.On("List", limit, offset).Return(expectedUsers, nil)
 
 	// Execute
-	result, err := s.userService.ListUsers(limit, offset)
+	result, err := s.userService.ListUsers(s.ctx, limit, offset)
 
 	// Assert
 	assert.NoError(s.T(), err)
@@ -284,7 +521,7 @@ func (s *ServiceTestSuite) TestListUsers_Empty() {
 	s.userRepo.On("List", limit, offset).Return([]*model.User{}, nil)
 
 	// Execute
-	result, err := s.userService.ListUsers(limit, offset)
+	result, err := s.userService.ListUsers(s.ctx, limit, offset)
 
 	// Assert
 	assert.NoError(s.T(), err)
@@ -300,10 +537,78 @@ func (s *ServiceTestSuite) TestListUsers_Error() {
 	s.userRepo.On("List", limit, offset).Return(nil, errors.New("database error"))
 
 	// Execute
-	result, err := s.userService.ListUsers(limit, offset)
+	result, err := s.userService.ListUsers(s.ctx, limit, offset)
 
 	// Assert
 	assert.Error(s.T(), err)
 	assert.Nil(s.T(), result)
 	s.userRepo.AssertExpectations(s.T())
 }
+
+// Test ListUserVersions
+func (s *ServiceTestSuite) TestListUserVersions_Success() {
+	s.versionRepo.On("List", uint(1), 10, 0).Return([]*model.UserVersion{
+		{UserID: 1, Version: 2, Username: "renamed"},
+		{UserID: 1, Version: 1, Username: "testuser"},
+	}, nil)
+
+	result, err := s.userService.ListUserVersions(s.ctx, 1, 10, 0)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), result, 2)
+	assert.Equal(s.T(), uint(2), result[0].Version)
+	assert.Equal(s.T(), "renamed", result[0].Username)
+}
+
+// Test GetUserVersion
+func (s *ServiceTestSuite) TestGetUserVersion_NotFound() {
+	s.versionRepo.On("Get", uint(1), uint(9)).Return(nil, gorm.ErrRecordNotFound)
+
+	result, err := s.userService.GetUserVersion(s.ctx, 1, 9)
+
+	assert.ErrorIs(s.T(), err, ErrUserVersionNotFound)
+	assert.Nil(s.T(), result)
+}
+
+// Test GetUserAsOf
+func (s *ServiceTestSuite) TestGetUserAsOf_Success() {
+	createdAt := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	at := time.Date(2025, 11, 15, 0, 0, 0, 0, time.UTC)
+	s.userRepo.On("GetByID", uint(1)).Return(&model.User{ID: 1, OrganizationID: 1, Username: "current", Version: 3, CreatedAt: createdAt}, nil)
+	s.versionRepo.On("GetAsOf", uint(1), at).Return(&model.UserVersion{
+		OrganizationID: 1,
+		UserID:         1,
+		Version:        2,
+		Username:       "earlier",
+		Email:          "earlier@example.com",
+		Role:           model.RoleUser,
+		CreatedAt:      time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC),
+	}, nil)
+
+	result, err := s.userService.GetUserAsOf(s.ctx, 1, at)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "earlier", result.Username)
+	assert.Equal(s.T(), uint(2), result.Version)
+	assert.Equal(s.T(), createdAt, result.CreatedAt)
+	assert.Equal(s.T(), time.Date(2025, 11, 10, 0, 0, 0, 0, time.UTC), result.UpdatedAt)
+}
+
+func (s *ServiceTestSuite) TestGetUserAsOf_BeforeCreation() {
+	s.userRepo.On("GetByID", uint(1)).Return(&model.User{ID: 1}, nil)
+	s.versionRepo.On("GetAsOf", uint(1), mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+	result, err := s.userService.GetUserAsOf(s.ctx, 1, time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	assert.ErrorIs(s.T(), err, ErrUserVersionNotFound)
+	assert.Nil(s.T(), result)
+}
+
+func (s *ServiceTestSuite) TestGetUserAsOf_UserNotFound() {
+	s.userRepo.On("GetByID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := s.userService.GetUserAsOf(s.ctx, 1, time.Now())
+
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+	s.versionRepo.AssertNotCalled(s.T(), "GetAsOf", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,398 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/events"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWebhookNotFound         = errors.New("webhook not found")
+	ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrInvalidWebhookURL       = errors.New("webhook URL must be an absolute http or https URL")
+)
+
+// WebhookDispatch is a claimed delivery with the endpoint to send it to and
+// the secret to sign it with.
+type WebhookDispatch struct {
+	Delivery *model.WebhookDelivery
+	URL      string
+	Secret   string
+}
+
+//go:generate go run github.com/vektra/mockery/v2@latest --name=WebhookService --output=./mocks/service --outpkg=service --filename=webhook_service.go --structname=MockWebhookService --with-expecter=false
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, req *model.CreateWebhookRequest) (*model.CreateWebhookResponse, error)
+	ListWebhooks(ctx context.Context) ([]*model.WebhookResponse, error)
+	GetWebhook(ctx context.Context, id uint) (*model.WebhookResponse, error)
+	UpdateWebhook(ctx context.Context, id uint, req *model.UpdateWebhookRequest) (*model.WebhookResponse, error)
+	DeleteWebhook(ctx context.Context, id uint) error
+	ListDeliveries(ctx context.Context, webhookID uint, filter *model.WebhookDeliveryFilter, limit, offset int) ([]*model.WebhookDeliveryResponse, error)
+	GetDelivery(ctx context.Context, webhookID, id uint) (*model.WebhookDeliveryResponse, error)
+	Redeliver(ctx context.Context, webhookID, id uint) (*model.WebhookDeliveryResponse, error)
+	// Publish queues a delivery of the event to each enabled webhook of its
+	// organization that subscribes to it, which makes the service an
+	// events.Publisher for the outbox relay.
+	Publish(ctx context.Context, msg *events.Message) error
+	ClaimDeliveries(ctx context.Context, limit int) ([]*WebhookDispatch, error)
+	RecordAttempt(ctx context.Context, delivery *model.WebhookDelivery, attempt *model.WebhookDeliveryAttempt) error
+}
+
+type webhookService struct {
+	webhookRepo repository.WebhookRepository
+	cipher      *secretCipher
+	conf        config.WebhookConfig
+}
+
+func NewWebhookService(webhookRepo repository.WebhookRepository, conf *config.Config) (WebhookService, error) {
+	secretCipher, err := newSecretCipher(conf.Webhook.EncryptionKey)
+	if err != nil {
+		return nil, errors.New("webhook.encryption_key must be set")
+	}
+
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		cipher:      secretCipher,
+		conf:        conf.Webhook,
+	}, nil
+}
+
+// CreateWebhook subscribes an endpoint of the organization of ctx to events.
+// The signing secret, generated unless one is given, is only part of the
+// response.
+func (s *webhookService) CreateWebhook(ctx context.Context, req *model.CreateWebhookRequest) (*model.CreateWebhookResponse, error) {
+	if err := checkWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		secret, _, err = newSecretToken()
+		if err != nil {
+			return nil, err
+		}
+	}
+	secretEncrypted, err := s.cipher.Encrypt([]byte(secret))
+	if err != nil {
+		return nil, err
+	}
+
+	webhook := &model.Webhook{
+		URL:             req.URL,
+		EventTypes:      model.EventTypeList(req.EventTypes),
+		Description:     req.Description,
+		SecretEncrypted: secretEncrypted,
+	}
+	if err := s.webhookRepo.WithContext(ctx).Create(webhook); err != nil {
+		return nil, err
+	}
+
+	return &model.CreateWebhookResponse{
+		WebhookResponse: *toWebhookResponse(webhook),
+		Secret:          secret,
+	}, nil
+}
+
+func (s *webhookService) ListWebhooks(ctx context.Context) ([]*model.WebhookResponse, error) {
+	webhooks, err := s.webhookRepo.WithContext(ctx).List()
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*model.WebhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		responses = append(responses, toWebhookResponse(webhook))
+	}
+	return responses, nil
+}
+
+func (s *webhookService) GetWebhook(ctx context.Context, id uint) (*model.WebhookResponse, error) {
+	webhook, err := s.getWebhook(s.webhookRepo.WithContext(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+	return toWebhookResponse(webhook), nil
+}
+
+// UpdateWebhook replaces the settings of a webhook. Enabling it again
+// clears its failed attempts, so it gets DisableAfter more before it is
+// disabled again.
+func (s *webhookService) UpdateWebhook(ctx context.Context, id uint, req *model.UpdateWebhookRequest) (*model.WebhookResponse, error) {
+	webhooks := s.webhookRepo.WithContext(ctx)
+
+	webhook, err := s.getWebhook(webhooks, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkWebhookURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	webhook.URL = req.URL
+	webhook.EventTypes = model.EventTypeList(req.EventTypes)
+	webhook.Description = req.Description
+	if req.Secret != "" {
+		webhook.SecretEncrypted, err = s.cipher.Encrypt([]byte(req.Secret))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if req.Enabled != nil {
+		switch {
+		case *req.Enabled && webhook.DisabledAt != nil:
+			webhook.DisabledAt = nil
+			webhook.ConsecutiveFailures = 0
+		case !*req.Enabled && webhook.DisabledAt == nil:
+			now := time.Now()
+			webhook.DisabledAt = &now
+		}
+	}
+
+	updated, err := webhooks.Update(webhook)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, ErrWebhookNotFound
+	}
+	return toWebhookResponse(webhook), nil
+}
+
+// DeleteWebhook deletes a webhook with its deliveries.
+func (s *webhookService) DeleteWebhook(ctx context.Context, id uint) error {
+	deleted, err := s.webhookRepo.WithContext(ctx).Delete(id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// ListDeliveries returns a page of the deliveries of a webhook, newest
+// first.
+func (s *webhookService) ListDeliveries(ctx context.Context, webhookID uint, filter *model.WebhookDeliveryFilter, limit, offset int) ([]*model.WebhookDeliveryResponse, error) {
+	webhooks := s.webhookRepo.WithContext(ctx)
+
+	if _, err := s.getWebhook(webhooks, webhookID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := webhooks.ListDeliveries(webhookID, filter, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*model.WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		responses = append(responses, toWebhookDeliveryResponse(delivery))
+	}
+	return responses, nil
+}
+
+// GetDelivery returns a delivery with the log of its attempts.
+func (s *webhookService) GetDelivery(ctx context.Context, webhookID, id uint) (*model.WebhookDeliveryResponse, error) {
+	webhooks := s.webhookRepo.WithContext(ctx)
+
+	delivery, err := webhooks.GetDelivery(webhookID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := webhooks.ListAttempts(id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := toWebhookDeliveryResponse(delivery)
+	response.Log = make([]*model.WebhookDeliveryAttemptResponse, 0, len(attempts))
+	for _, attempt := range attempts {
+		response.Log = append(response.Log, &model.WebhookDeliveryAttemptResponse{
+			ResponseStatus: attempt.ResponseStatus,
+			Error:          attempt.Error,
+			DurationMS:     attempt.DurationMS,
+			CreatedAt:      attempt.CreatedAt,
+		})
+	}
+	return response, nil
+}
+
+// Redeliver sends a delivery again as soon as the worker gets to it, with a
+// fresh set of attempts. Deliveries of a disabled webhook wait until it is
+// enabled.
+func (s *webhookService) Redeliver(ctx context.Context, webhookID, id uint) (*model.WebhookDeliveryResponse, error) {
+	redelivered, err := s.webhookRepo.WithContext(ctx).Redeliver(webhookID, id)
+	if err != nil {
+		return nil, err
+	}
+	if !redelivered {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	return s.GetDelivery(ctx, webhookID, id)
+}
+
+func (s *webhookService) Publish(ctx context.Context, msg *events.Message) error {
+	webhooks := s.webhookRepo.WithContext(tenant.WithOrganization(ctx, msg.OrganizationID))
+
+	subscribed, err := webhooks.ListEnabled()
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	deliveries := make([]*model.WebhookDelivery, 0, len(subscribed))
+	for _, webhook := range subscribed {
+		if !webhook.Subscribes(msg.Type) {
+			continue
+		}
+		deliveries = append(deliveries, &model.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       msg.ID,
+			EventType:     msg.Type,
+			Payload:       string(payload),
+			Status:        model.WebhookDeliveryPending,
+			NextAttemptAt: now,
+		})
+	}
+	return webhooks.AddDeliveries(deliveries)
+}
+
+// ClaimDeliveries claims up to limit deliveries that are due, of any
+// organization, for the worker to send.
+func (s *webhookService) ClaimDeliveries(ctx context.Context, limit int) ([]*WebhookDispatch, error) {
+	deliveries, err := s.webhookRepo.WithContext(tenant.Unscoped(ctx)).ClaimDeliveries(limit, s.conf.Lease)
+	if err != nil {
+		return nil, err
+	}
+
+	dispatches := make([]*WebhookDispatch, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		secret, err := s.cipher.Decrypt(delivery.Webhook.SecretEncrypted)
+		if err != nil {
+			// The delivery is claimed again once its lease runs out
+			log.Printf("Failed to decrypt the secret of webhook %d: %v", delivery.WebhookID, err)
+			continue
+		}
+		dispatches = append(dispatches, &WebhookDispatch{
+			Delivery: delivery,
+			URL:      delivery.Webhook.URL,
+			Secret:   string(secret),
+		})
+	}
+	return dispatches, nil
+}
+
+// RecordAttempt stores the outcome of an attempt to send a delivery. A
+// failed delivery is retried with exponential backoff until it has had
+// MaxAttempts, when it is dead-lettered.
+func (s *webhookService) RecordAttempt(ctx context.Context, delivery *model.WebhookDelivery, attempt *model.WebhookDeliveryAttempt) error {
+	now := time.Now()
+	delivery.LastResponseStatus = attempt.ResponseStatus
+	delivery.LastError = attempt.Error
+
+	switch {
+	case attempt.Succeeded():
+		delivery.Status = model.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= s.conf.MaxAttempts:
+		delivery.Status = model.WebhookDeliveryFailed
+	default:
+		delivery.NextAttemptAt = now.Add(s.backoff(delivery.Attempts))
+	}
+
+	webhooks := s.webhookRepo.WithContext(tenant.WithOrganization(ctx, delivery.OrganizationID))
+	disabled, err := webhooks.RecordAttempt(delivery, attempt, s.conf.DisableAfter)
+	if err != nil {
+		return err
+	}
+	if disabled {
+		log.Printf("Disabled webhook %d after %d failed attempts in a row", delivery.WebhookID, s.conf.DisableAfter)
+	}
+	return nil
+}
+
+// backoff returns how long to wait after the given number of failed
+// attempts: BaseBackoff, doubling with every further attempt up to
+// MaxBackoff.
+func (s *webhookService) backoff(attempts int) time.Duration {
+	backoff := s.conf.BaseBackoff
+	for i := 1; i < attempts && backoff < s.conf.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > s.conf.MaxBackoff {
+		backoff = s.conf.MaxBackoff
+	}
+	return backoff
+}
+
+func (s *webhookService) getWebhook(webhooks repository.WebhookRepository, id uint) (*model.Webhook, error) {
+	webhook, err := webhooks.GetByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWebhookNotFound
+	}
+	return webhook, err
+}
+
+// checkWebhookURL only accepts http and https URLs.
+func checkWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+	return nil
+}
+
+func toWebhookResponse(webhook *model.Webhook) *model.WebhookResponse {
+	return &model.WebhookResponse{
+		ID:                  webhook.ID,
+		URL:                 webhook.URL,
+		EventTypes:          webhook.EventTypes,
+		Description:         webhook.Description,
+		Enabled:             webhook.DisabledAt == nil,
+		DisabledAt:          webhook.DisabledAt,
+		ConsecutiveFailures: webhook.ConsecutiveFailures,
+		CreatedAt:           webhook.CreatedAt,
+		UpdatedAt:           webhook.UpdatedAt,
+	}
+}
+
+func toWebhookDeliveryResponse(delivery *model.WebhookDelivery) *model.WebhookDeliveryResponse {
+	response := &model.WebhookDeliveryResponse{
+		ID:                 delivery.ID,
+		WebhookID:          delivery.WebhookID,
+		EventID:            delivery.EventID,
+		EventType:          delivery.EventType,
+		Status:             delivery.Status,
+		Attempts:           delivery.Attempts,
+		LastResponseStatus: delivery.LastResponseStatus,
+		LastError:          delivery.LastError,
+		DeliveredAt:        delivery.DeliveredAt,
+		CreatedAt:          delivery.CreatedAt,
+	}
+	if delivery.Status == model.WebhookDeliveryPending {
+		nextAttemptAt := delivery.NextAttemptAt
+		response.NextAttemptAt = &nextAttemptAt
+	}
+	return response
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/weeranieb/go-kit-base/src/internal/events"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"gorm.io/gorm"
+)
+
+// newTestWebhook returns a webhook of organization 1 signing with secret.
+func (s *ServiceTestSuite) newTestWebhook(id uint, secret string, eventTypes ...string) *model.Webhook {
+	secretEncrypted, _ := s.webhookCipher().Encrypt([]byte(secret))
+	return &model.Webhook{
+		ID:              id,
+		OrganizationID:  1,
+		URL:             "https://example.com/hooks",
+		EventTypes:      model.EventTypeList(eventTypes),
+		SecretEncrypted: secretEncrypted,
+	}
+}
+
+func (s *ServiceTestSuite) webhookCipher() *secretCipher {
+	cipher, err := newSecretCipher(s.conf.Webhook.EncryptionKey)
+	if err != nil {
+		s.T().Fatal("Failed to create cipher:", err)
+	}
+	return cipher
+}
+
+// Test CreateWebhook
+func (s *ServiceTestSuite) TestCreateWebhook_GeneratesSecret() {
+	var stored *model.Webhook
+	s.webhookRepo.On("Create", mock.AnythingOfType("*model.Webhook")).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*model.Webhook)
+		stored.ID = 1
+	}).Return(nil)
+
+	webhook, err := s.webhooks.CreateWebhook(s.ctx, &model.CreateWebhookRequest{
+		URL:        "https://example.com/hooks",
+		EventTypes: []string{events.UserCreatedType},
+	})
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), uint(1), webhook.ID)
+	assert.True(s.T(), webhook.Enabled)
+	assert.NotEmpty(s.T(), webhook.Secret)
+	// Only the encrypted secret is stored
+	assert.NotContains(s.T(), stored.SecretEncrypted, webhook.Secret)
+	secret, _ := s.webhookCipher().Decrypt(stored.SecretEncrypted)
+	assert.Equal(s.T(), webhook.Secret, string(secret))
+}
+
+func (s *ServiceTestSuite) TestCreateWebhook_GivenSecret() {
+	s.webhookRepo.On("Create", mock.AnythingOfType("*model.Webhook")).Return(nil)
+
+	webhook, err := s.webhooks.CreateWebhook(s.ctx, &model.CreateWebhookRequest{
+		URL:        "https://example.com/hooks",
+		EventTypes: []string{model.WebhookAllEvents},
+		Secret:     "a-secret-of-my-own",
+	})
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), "a-secret-of-my-own", webhook.Secret)
+}
+
+func (s *ServiceTestSuite) TestCreateWebhook_InvalidURL() {
+	for _, url := range []string{"ftp://example.com/hooks", "/hooks", "https://"} {
+		_, err := s.webhooks.CreateWebhook(s.ctx, &model.CreateWebhookRequest{URL: url, EventTypes: []string{model.WebhookAllEvents}})
+
+		assert.ErrorIs(s.T(), err, ErrInvalidWebhookURL, url)
+	}
+}
+
+// Test UpdateWebhook
+func (s *ServiceTestSuite) TestUpdateWebhook_Enable() {
+	disabledAt := time.Now()
+	webhook := s.newTestWebhook(1, "secret", model.WebhookAllEvents)
+	webhook.DisabledAt = &disabledAt
+	webhook.ConsecutiveFailures = 5
+	enabled := true
+	s.webhookRepo.On("GetByID", uint(1)).Return(webhook, nil)
+	s.webhookRepo.On("Update", mock.MatchedBy(func(w *model.Webhook) bool {
+		return w.DisabledAt == nil && w.ConsecutiveFailures == 0
+	})).Return(true, nil)
+
+	response, err := s.webhooks.UpdateWebhook(s.ctx, 1, &model.UpdateWebhookRequest{
+		URL:        "https://example.com/hooks",
+		EventTypes: []string{model.WebhookAllEvents},
+		Enabled:    &enabled,
+	})
+
+	assert.NoError(s.T(), err)
+	assert.True(s.T(), response.Enabled)
+}
+
+func (s *ServiceTestSuite) TestUpdateWebhook_NotFound() {
+	s.webhookRepo.On("GetByID", uint(1)).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := s.webhooks.UpdateWebhook(s.ctx, 1, &model.UpdateWebhookRequest{URL: "https://example.com/hooks"})
+
+	assert.ErrorIs(s.T(), err, ErrWebhookNotFound)
+}
+
+// Test DeleteWebhook
+func (s *ServiceTestSuite) TestDeleteWebhook_NotFound() {
+	s.webhookRepo.On("Delete", uint(1)).Return(false, nil)
+
+	err := s.webhooks.DeleteWebhook(s.ctx, 1)
+
+	assert.ErrorIs(s.T(), err, ErrWebhookNotFound)
+}
+
+// Test GetDelivery
+func (s *ServiceTestSuite) TestGetDelivery_WithLog() {
+	delivery := &model.WebhookDelivery{ID: 4, WebhookID: 1, Status: model.WebhookDeliveryFailed, Attempts: 2, LastResponseStatus: 500}
+	s.webhookRepo.On("GetDelivery", uint(1), uint(4)).Return(delivery, nil)
+	s.webhookRepo.On("ListAttempts", uint(4)).Return([]*model.WebhookDeliveryAttempt{
+		{ID: 1, Error: "connection refused"},
+		{ID: 2, ResponseStatus: 500, Error: "endpoint responded with 500: oops"},
+	}, nil)
+
+	response, err := s.webhooks.GetDelivery(s.ctx, 1, 4)
+
+	assert.NoError(s.T(), err)
+	assert.Nil(s.T(), response.NextAttemptAt)
+	assert.Len(s.T(), response.Log, 2)
+	assert.Equal(s.T(), 500, response.Log[1].ResponseStatus)
+}
+
+func (s *ServiceTestSuite) TestGetDelivery_NotFound() {
+	s.webhookRepo.On("GetDelivery", uint(1), uint(4)).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := s.webhooks.GetDelivery(s.ctx, 1, 4)
+
+	assert.ErrorIs(s.T(), err, ErrWebhookDeliveryNotFound)
+}
+
+// Test Redeliver
+func (s *ServiceTestSuite) TestRedeliver_NotFound() {
+	s.webhookRepo.On("Redeliver", uint(1), uint(4)).Return(false, nil)
+
+	_, err := s.webhooks.Redeliver(s.ctx, 1, 4)
+
+	assert.ErrorIs(s.T(), err, ErrWebhookDeliveryNotFound)
+}
+
+// Test Publish
+func (s *ServiceTestSuite) TestPublish_Subscribed() {
+	s.webhookRepo.On("ListEnabled").Return([]*model.Webhook{
+		s.newTestWebhook(1, "secret", events.UserCreatedType),
+		s.newTestWebhook(2, "secret", events.UserDeletedType),
+		s.newTestWebhook(3, "secret", model.WebhookAllEvents),
+	}, nil)
+	s.webhookRepo.On("AddDeliveries", mock.MatchedBy(func(deliveries []*model.WebhookDelivery) bool {
+		return len(deliveries) == 2 &&
+			deliveries[0].WebhookID == 1 && deliveries[1].WebhookID == 3 &&
+			deliveries[0].EventID == 7 && deliveries[0].Status == model.WebhookDeliveryPending
+	})).Return(nil)
+
+	err := s.webhooks.Publish(s.ctx, &events.Message{
+		ID:             7,
+		OrganizationID: 1,
+		Type:           events.UserCreatedType,
+		Data:           json.RawMessage(`{"user":{"id":3}}`),
+	})
+
+	assert.NoError(s.T(), err)
+}
+
+// Test ClaimDeliveries
+func (s *ServiceTestSuite) TestClaimDeliveries_DecryptsSecret() {
+	webhook := s.newTestWebhook(1, "whsec_secret", model.WebhookAllEvents)
+	s.webhookRepo.On("ClaimDeliveries", 10, time.Minute).Return([]*model.WebhookDelivery{
+		{ID: 4, WebhookID: 1, Webhook: webhook},
+	}, nil)
+
+	dispatches, err := s.webhooks.ClaimDeliveries(s.ctx, 10)
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), dispatches, 1)
+	assert.Equal(s.T(), "whsec_secret", dispatches[0].Secret)
+	assert.Equal(s.T(), webhook.URL, dispatches[0].URL)
+}
+
+// Test RecordAttempt
+func (s *ServiceTestSuite) TestRecordAttempt_Succeeded() {
+	delivery := &model.WebhookDelivery{ID: 4, WebhookID: 1, Status: model.WebhookDeliveryPending, Attempts: 1}
+	attempt := &model.WebhookDeliveryAttempt{ResponseStatus: 200}
+	s.webhookRepo.On("RecordAttempt", delivery, attempt, 5).Return(false, nil)
+
+	err := s.webhooks.RecordAttempt(s.ctx, delivery, attempt)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.WebhookDeliverySucceeded, delivery.Status)
+	assert.NotNil(s.T(), delivery.DeliveredAt)
+	assert.Equal(s.T(), 200, delivery.LastResponseStatus)
+}
+
+func (s *ServiceTestSuite) TestRecordAttempt_Backoff() {
+	delivery := &model.WebhookDelivery{ID: 4, WebhookID: 1, Status: model.WebhookDeliveryPending, Attempts: 2}
+	attempt := &model.WebhookDeliveryAttempt{ResponseStatus: 500, Error: "endpoint responded with 500: oops"}
+	s.webhookRepo.On("RecordAttempt", delivery, attempt, 5).Return(false, nil)
+
+	err := s.webhooks.RecordAttempt(s.ctx, delivery, attempt)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.WebhookDeliveryPending, delivery.Status)
+	assert.WithinDuration(s.T(), time.Now().Add(20*time.Second), delivery.NextAttemptAt, time.Second)
+	assert.Equal(s.T(), "endpoint responded with 500: oops", delivery.LastError)
+}
+
+func (s *ServiceTestSuite) TestRecordAttempt_DeadLettered() {
+	delivery := &model.WebhookDelivery{ID: 4, WebhookID: 1, Status: model.WebhookDeliveryPending, Attempts: 3}
+	attempt := &model.WebhookDeliveryAttempt{Error: "connection refused"}
+	s.webhookRepo.On("RecordAttempt", delivery, attempt, 5).Return(true, nil)
+
+	err := s.webhooks.RecordAttempt(s.ctx, delivery, attempt)
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), model.WebhookDeliveryFailed, delivery.Status)
+	assert.Nil(s.T(), delivery.DeliveredAt)
+}
+
+func (s *ServiceTestSuite) TestWebhookBackoff() {
+	service := s.webhooks.(*webhookService)
+
+	assert.Equal(s.T(), 10*time.Second, service.backoff(1))
+	assert.Equal(s.T(), 40*time.Second, service.backoff(3))
+	assert.Equal(s.T(), time.Minute, service.backoff(10))
+}
@@ -0,0 +1,247 @@
+// Package stream pushes domain events to subscribers as they are stored,
+// for server-sent event streams. A Hub tails the outbox, which all replicas
+// share, so subscribers see the events of every replica whichever one they
+// are connected to. The latest events are kept so a subscriber that
+// reconnects can resume after the last event it saw.
+package stream
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/events"
+	"github.com/weeranieb/go-kit-base/src/internal/repository"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+)
+
+// batchSize is how many events a poll reads at most.
+const batchSize = 100
+
+var (
+	ErrTooManySubscribers = errors.New("too many event stream subscribers")
+	// ErrSlowSubscriber ends a subscription that fell too far behind.
+	ErrSlowSubscriber = errors.New("subscriber fell too far behind")
+	// ErrHubStopped ends the subscriptions of a hub that has stopped.
+	ErrHubStopped = errors.New("event stream stopped")
+)
+
+// Filter selects the events a subscriber gets.
+type Filter struct {
+	OrganizationID uint
+	// Types lists the event types; empty means all of them.
+	Types []string
+}
+
+func (f Filter) matches(msg *events.Message) bool {
+	return msg.OrganizationID == f.OrganizationID &&
+		(len(f.Types) == 0 || slices.Contains(f.Types, msg.Type))
+}
+
+// Subscription is a subscriber of a Hub.
+type Subscription struct {
+	// Replay holds the buffered events after the one the subscriber
+	// resumed from. Events delivers the events stored after them, and is
+	// closed when the subscription ends.
+	Replay []*events.Message
+	Events <-chan *events.Message
+	// Missed is set when the subscriber resumed from an event that is no
+	// longer buffered, so it may have missed some and should start over.
+	Missed bool
+
+	events chan *events.Message
+	filter Filter
+	// after is the event the subscriber resumed from; a replica that is
+	// behind the one it saw it on must not send it again
+	after uint
+	err   error
+}
+
+// Err returns why the subscription ended once Events is closed: nil after
+// Unsubscribe, ErrSlowSubscriber or ErrHubStopped.
+func (s *Subscription) Err() error {
+	return s.err
+}
+
+// Hub fans out the events stored in the outbox to its subscribers.
+type Hub struct {
+	outboxRepo repository.OutboxRepository
+	conf       config.EventStreamConfig
+
+	// cursor is the ID of the last event read; only Poll uses it and
+	// gapSince, when it started waiting for the event after the cursor
+	cursor   uint
+	started  bool
+	gapSince time.Time
+
+	mu sync.Mutex
+	// buffer holds the latest events in ID order; floor is the ID of the
+	// last event that is not in it
+	buffer      []*events.Message
+	floor       uint
+	subscribers map[*Subscription]struct{}
+	stopped     bool
+}
+
+func NewHub(outboxRepo repository.OutboxRepository, conf *config.Config) *Hub {
+	return &Hub{
+		outboxRepo:  outboxRepo,
+		conf:        conf.EventStream,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Run reads new events every poll interval until ctx is done, then ends
+// all subscriptions.
+func (h *Hub) Run(ctx context.Context) {
+	ticker := time.NewTicker(h.conf.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Full batches mean there is a backlog, so keep going without waiting
+		for {
+			read, err := h.Poll(ctx)
+			if err != nil {
+				log.Printf("Failed to read events for streaming: %v", err)
+				break
+			}
+			if read < batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			h.stop()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll reads the events stored since the last poll and hands them to the
+// subscribers. The first poll starts from the latest event. It returns how
+// many events it read.
+func (h *Hub) Poll(ctx context.Context) (int, error) {
+	outbox := h.outboxRepo.WithContext(tenant.Unscoped(ctx))
+
+	if !h.started {
+		lastID, err := outbox.LastID()
+		if err != nil {
+			return 0, err
+		}
+		h.cursor = lastID
+		h.started = true
+
+		h.mu.Lock()
+		h.floor = lastID
+		h.mu.Unlock()
+	}
+
+	messages, err := outbox.ListAfter(h.cursor, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	for _, message := range messages {
+		// IDs are taken in order but committed in any order, so a missing
+		// ID may still show up; it is given up on after the gap timeout,
+		// as it may belong to a transaction that rolled back
+		if message.ID != h.cursor+1 {
+			if h.gapSince.IsZero() {
+				h.gapSince = now
+			}
+			if now.Sub(h.gapSince) < h.conf.GapTimeout {
+				break
+			}
+		}
+		h.gapSince = time.Time{}
+		h.cursor = message.ID
+		h.broadcast(events.NewMessage(message))
+	}
+	return len(messages), nil
+}
+
+// broadcast buffers the event and hands it to the subscribers it matches.
+func (h *Hub) broadcast(msg *events.Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.buffer = append(h.buffer, msg)
+	if len(h.buffer) > h.conf.BufferSize {
+		h.floor = h.buffer[0].ID
+		h.buffer = slices.Delete(h.buffer, 0, 1)
+	}
+
+	for sub := range h.subscribers {
+		if msg.ID <= sub.after || !sub.filter.matches(msg) {
+			continue
+		}
+		select {
+		case sub.events <- msg:
+		default:
+			h.end(sub, ErrSlowSubscriber)
+		}
+	}
+}
+
+// Subscribe starts a subscription to the events that match filter. With a
+// lastEventID, the buffered events after it are replayed.
+func (h *Hub) Subscribe(filter Filter, lastEventID uint) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.stopped {
+		return nil, ErrHubStopped
+	}
+	if len(h.subscribers) >= h.conf.MaxSubscribers {
+		return nil, ErrTooManySubscribers
+	}
+
+	ch := make(chan *events.Message, h.conf.SubscriberBuffer)
+	sub := &Subscription{Events: ch, events: ch, filter: filter, after: lastEventID}
+	if lastEventID != 0 {
+		sub.Missed = lastEventID < h.floor
+		for _, msg := range h.buffer {
+			if msg.ID > lastEventID && filter.matches(msg) {
+				sub.Replay = append(sub.Replay, msg)
+			}
+		}
+	}
+
+	h.subscribers[sub] = struct{}{}
+	return sub, nil
+}
+
+// Unsubscribe ends the subscription.
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.end(sub, nil)
+}
+
+func (h *Hub) stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.stopped = true
+	for sub := range h.subscribers {
+		h.end(sub, ErrHubStopped)
+	}
+}
+
+// end closes the subscription for err. It must be called with mu held.
+func (h *Hub) end(sub *Subscription, err error) {
+	if _, ok := h.subscribers[sub]; !ok {
+		return
+	}
+	delete(h.subscribers, sub)
+	sub.err = err
+	close(sub.events)
+}
@@ -0,0 +1,211 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/events"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	mocks "github.com/weeranieb/go-kit-base/src/internal/repository/mocks/repository"
+)
+
+type HubTestSuite struct {
+	suite.Suite
+	outboxRepo *mocks.MockOutboxRepository
+	hub        *Hub
+}
+
+func (s *HubTestSuite) SetupTest() {
+	s.outboxRepo = mocks.NewMockOutboxRepository(s.T())
+	s.outboxRepo.On("WithContext", mock.Anything).Return(s.outboxRepo).Maybe()
+
+	s.hub = NewHub(s.outboxRepo, &config.Config{EventStream: config.EventStreamConfig{
+		PollInterval:     10 * time.Millisecond,
+		BufferSize:       3,
+		GapTimeout:       time.Minute,
+		SubscriberBuffer: 2,
+		MaxSubscribers:   2,
+	}})
+
+	// The hub starts after event 10
+	s.outboxRepo.On("LastID").Return(uint(10), nil).Once()
+	s.poll(10)
+}
+
+func TestHubSuite(t *testing.T) {
+	suite.Run(t, new(HubTestSuite))
+}
+
+func newMessage(id, organizationID uint, eventType string) *model.OutboxMessage {
+	return &model.OutboxMessage{
+		ID:             id,
+		OrganizationID: organizationID,
+		EventType:      eventType,
+		AggregateType:  events.AggregateUser,
+		AggregateID:    1,
+		Payload:        `{}`,
+	}
+}
+
+// poll has the hub read the messages stored after the event with ID after.
+func (s *HubTestSuite) poll(after uint, messages ...*model.OutboxMessage) {
+	s.outboxRepo.On("ListAfter", after, batchSize).Return(messages, nil).Once()
+	if _, err := s.hub.Poll(context.Background()); err != nil {
+		s.T().Fatal("Failed to poll:", err)
+	}
+}
+
+func (s *HubTestSuite) subscribe(filter Filter, lastEventID uint) *Subscription {
+	sub, err := s.hub.Subscribe(filter, lastEventID)
+	if err != nil {
+		s.T().Fatal("Failed to subscribe:", err)
+	}
+	return sub
+}
+
+func received(sub *Subscription) []uint {
+	var ids []uint
+	for {
+		select {
+		case msg, ok := <-sub.Events:
+			if !ok {
+				return ids
+			}
+			ids = append(ids, msg.ID)
+		default:
+			return ids
+		}
+	}
+}
+
+func (s *HubTestSuite) TestPoll_Filters() {
+	sub := s.subscribe(Filter{OrganizationID: 1, Types: []string{events.UserCreatedType}}, 0)
+
+	s.poll(10,
+		newMessage(11, 1, events.UserCreatedType),
+		newMessage(12, 2, events.UserCreatedType),
+		newMessage(13, 1, events.UserDeletedType),
+	)
+
+	assert.Equal(s.T(), []uint{11}, received(sub))
+}
+
+func (s *HubTestSuite) TestPoll_WaitsForGap() {
+	sub := s.subscribe(Filter{OrganizationID: 1}, 0)
+
+	// Event 11 has not committed yet
+	s.poll(10, newMessage(12, 1, events.UserCreatedType))
+	assert.Empty(s.T(), received(sub))
+
+	s.poll(10, newMessage(11, 1, events.UserCreatedType), newMessage(12, 1, events.UserUpdatedType))
+	assert.Equal(s.T(), []uint{11, 12}, received(sub))
+}
+
+func (s *HubTestSuite) TestPoll_SkipsGapAfterTimeout() {
+	sub := s.subscribe(Filter{OrganizationID: 1}, 0)
+	s.poll(10, newMessage(12, 1, events.UserCreatedType))
+
+	// Event 11 rolled back
+	s.hub.gapSince = time.Now().Add(-2 * time.Minute)
+	s.poll(10, newMessage(12, 1, events.UserCreatedType))
+
+	assert.Equal(s.T(), []uint{12}, received(sub))
+}
+
+func (s *HubTestSuite) TestSubscribe_Replay() {
+	s.poll(10,
+		newMessage(11, 1, events.UserCreatedType),
+		newMessage(12, 2, events.UserCreatedType),
+		newMessage(13, 1, events.UserUpdatedType),
+	)
+
+	sub := s.subscribe(Filter{OrganizationID: 1}, 11)
+
+	assert.False(s.T(), sub.Missed)
+	assert.Len(s.T(), sub.Replay, 1)
+	assert.Equal(s.T(), uint(13), sub.Replay[0].ID)
+}
+
+func (s *HubTestSuite) TestSubscribe_Missed() {
+	s.poll(10,
+		newMessage(11, 1, events.UserCreatedType),
+		newMessage(12, 1, events.UserUpdatedType),
+		newMessage(13, 1, events.UserUpdatedType),
+		newMessage(14, 1, events.UserDeletedType),
+	)
+
+	// Event 11 has dropped out of the buffer of 3
+	sub := s.subscribe(Filter{OrganizationID: 1}, 10)
+	assert.True(s.T(), sub.Missed)
+	assert.Len(s.T(), sub.Replay, 3)
+
+	sub = s.subscribe(Filter{OrganizationID: 1}, 11)
+	assert.False(s.T(), sub.Missed)
+	assert.Len(s.T(), sub.Replay, 3)
+}
+
+func (s *HubTestSuite) TestSubscribe_AheadOfHub() {
+	// Resuming from an event this hub has not read yet, seen on another
+	// replica
+	sub := s.subscribe(Filter{OrganizationID: 1}, 12)
+
+	s.poll(10,
+		newMessage(11, 1, events.UserCreatedType),
+		newMessage(12, 1, events.UserUpdatedType),
+		newMessage(13, 1, events.UserUpdatedType),
+	)
+
+	assert.Equal(s.T(), []uint{13}, received(sub))
+}
+
+func (s *HubTestSuite) TestSubscribe_TooMany() {
+	s.subscribe(Filter{OrganizationID: 1}, 0)
+	s.subscribe(Filter{OrganizationID: 1}, 0)
+
+	_, err := s.hub.Subscribe(Filter{OrganizationID: 1}, 0)
+
+	assert.ErrorIs(s.T(), err, ErrTooManySubscribers)
+}
+
+func (s *HubTestSuite) TestBroadcast_SlowSubscriber() {
+	slow := s.subscribe(Filter{OrganizationID: 1}, 0)
+	other := s.subscribe(Filter{OrganizationID: 2}, 0)
+
+	s.poll(10,
+		newMessage(11, 1, events.UserCreatedType),
+		newMessage(12, 1, events.UserUpdatedType),
+		newMessage(13, 1, events.UserUpdatedType),
+	)
+
+	assert.Equal(s.T(), []uint{11, 12}, received(slow))
+	_, ok := <-slow.Events
+	assert.False(s.T(), ok)
+	assert.ErrorIs(s.T(), slow.Err(), ErrSlowSubscriber)
+
+	// Its place is free again, and others are not affected
+	s.subscribe(Filter{OrganizationID: 1}, 0)
+	s.hub.Unsubscribe(other)
+	_, ok = <-other.Events
+	assert.False(s.T(), ok)
+	assert.NoError(s.T(), other.Err())
+}
+
+func (s *HubTestSuite) TestRun_StopsSubscriptions() {
+	sub := s.subscribe(Filter{OrganizationID: 1}, 0)
+	s.outboxRepo.On("ListAfter", uint(10), batchSize).Return([]*model.OutboxMessage{}, nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+	defer cancel()
+	s.hub.Run(ctx)
+
+	_, ok := <-sub.Events
+	assert.False(s.T(), ok)
+	assert.ErrorIs(s.T(), sub.Err(), ErrHubStopped)
+	_, err := s.hub.Subscribe(Filter{OrganizationID: 1}, 0)
+	assert.ErrorIs(s.T(), err, ErrHubStopped)
+}
@@ -0,0 +1,159 @@
+// Package tenant scopes database access to an organization. The
+// organization of a request travels in its context; Plugin makes GORM add
+// it to every statement on a model with an organization_id column, so
+// repositories cannot read or change another organization's rows by
+// mistake. Statements on such models fail without an organization in their
+// context, unless the context was made with Unscoped for the lookups that
+// span organizations, such as resolving a session to its user.
+package tenant
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// Column ties a row to the organization that owns it.
+const Column = "organization_id"
+
+var (
+	// ErrNoOrganization is returned for operations that only make sense
+	// within an organization when the context has none.
+	ErrNoOrganization = errors.New("no organization in context")
+	// ErrCrossOrganization is returned when a record of one organization
+	// is created in the context of another.
+	ErrCrossOrganization = errors.New("record belongs to another organization")
+)
+
+type (
+	contextKey  struct{}
+	unscopedKey struct{}
+)
+
+// WithOrganization returns a context scoped to the organization.
+func WithOrganization(ctx context.Context, organizationID uint) context.Context {
+	return context.WithValue(ctx, contextKey{}, organizationID)
+}
+
+// OrganizationFromContext returns the organization the context is scoped
+// to, if any.
+func OrganizationFromContext(ctx context.Context) (uint, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	organizationID, ok := ctx.Value(contextKey{}).(uint)
+	return organizationID, ok && organizationID != 0
+}
+
+// Unscoped returns a context whose statements span organizations. It is
+// for the paths that must find a user before knowing their organization,
+// such as signing in, redeeming tokens and authenticating sessions, and for
+// workers that serve every organization. It drops the organization of ctx;
+// one added to the context later scopes it again.
+func Unscoped(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, contextKey{}, uint(0))
+	return context.WithValue(ctx, unscopedKey{}, true)
+}
+
+// IsUnscoped reports whether the context was made with Unscoped.
+func IsUnscoped(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	unscoped, _ := ctx.Value(unscopedKey{}).(bool)
+	return unscoped
+}
+
+// Plugin scopes GORM statements to the organization in their context.
+// Queries, updates and deletes get a WHERE organization_id = ? clause, and
+// created records get the organization unless they already carry it.
+// Statements with neither an organization nor Unscoped in their context
+// fail with ErrNoOrganization.
+type Plugin struct{}
+
+func (Plugin) Name() string {
+	return "tenant"
+}
+
+func (Plugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	if err := callbacks.Query().Before("gorm:query").Register("tenant:query", scope); err != nil {
+		return err
+	}
+	if err := callbacks.Row().Before("gorm:row").Register("tenant:row", scope); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("tenant:update", scope); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("tenant:delete", scope); err != nil {
+		return err
+	}
+	return callbacks.Create().Before("gorm:create").Register("tenant:create", assign)
+}
+
+func scope(db *gorm.DB) {
+	organizationID, field := target(db)
+	if field == nil {
+		return
+	}
+	db.Statement.AddClause(clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: field.DBName}, Value: organizationID},
+	}})
+}
+
+func assign(db *gorm.DB) {
+	organizationID, field := target(db)
+	if field == nil {
+		return
+	}
+
+	set := func(record reflect.Value) {
+		value, zero := field.ValueOf(db.Statement.Context, record)
+		if zero {
+			if err := field.Set(db.Statement.Context, record, organizationID); err != nil {
+				db.AddError(err)
+			}
+			return
+		}
+		if current, ok := value.(uint); !ok || current != organizationID {
+			db.AddError(ErrCrossOrganization)
+		}
+	}
+
+	switch records := db.Statement.ReflectValue; records.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < records.Len(); i++ {
+			set(reflect.Indirect(records.Index(i)))
+		}
+	case reflect.Struct:
+		set(records)
+	}
+}
+
+// target returns the organization to scope the statement to and the field
+// holding it, or a nil field if the statement is not scoped. Statements on
+// organization-owned models without an organization fail unless their
+// context is unscoped.
+func target(db *gorm.DB) (uint, *schema.Field) {
+	if db.Error != nil || db.Statement.Schema == nil {
+		return 0, nil
+	}
+	field := db.Statement.Schema.LookUpField(Column)
+	if field == nil {
+		return 0, nil
+	}
+
+	organizationID, ok := OrganizationFromContext(db.Statement.Context)
+	if ok {
+		return organizationID, field
+	}
+	if !IsUnscoped(db.Statement.Context) {
+		db.AddError(ErrNoOrganization)
+	}
+	return 0, nil
+}
@@ -0,0 +1,194 @@
+package tenant_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/tenant"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// note is owned by an organization; setting has no owner.
+type note struct {
+	ID             uint
+	OrganizationID uint
+	Body           string
+}
+
+type setting struct {
+	ID    uint
+	Value string
+}
+
+type TenantTestSuite struct {
+	suite.Suite
+	db       *gorm.DB
+	orgA     context.Context
+	orgB     context.Context
+	unscoped context.Context
+}
+
+func (s *TenantTestSuite) SetupSuite() {
+	var err error
+	s.db, err = gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		s.T().Fatal("Failed to connect to test database:", err)
+	}
+	if err := s.db.Use(tenant.Plugin{}); err != nil {
+		s.T().Fatal("Failed to register plugin:", err)
+	}
+	if err := s.db.AutoMigrate(&note{}, &setting{}); err != nil {
+		s.T().Fatal("Failed to migrate database:", err)
+	}
+
+	s.orgA = tenant.WithOrganization(context.Background(), 1)
+	s.orgB = tenant.WithOrganization(context.Background(), 2)
+	s.unscoped = tenant.Unscoped(context.Background())
+}
+
+func (s *TenantTestSuite) TearDownSuite() {
+	sqlDB, _ := s.db.DB()
+	if sqlDB != nil {
+		sqlDB.Close()
+	}
+}
+
+func (s *TenantTestSuite) SetupTest() {
+	s.db.Exec("DELETE FROM notes")
+	s.db.Exec("DELETE FROM settings")
+
+	s.db.WithContext(s.orgA).Create(&note{Body: "a-1"})
+	s.db.WithContext(s.orgA).Create(&note{Body: "a-2"})
+	s.db.WithContext(s.orgB).Create(&note{Body: "b-1"})
+}
+
+func TestTenantSuite(t *testing.T) {
+	suite.Run(t, new(TenantTestSuite))
+}
+
+func (s *TenantTestSuite) TestOrganizationFromContext() {
+	organizationID, ok := tenant.OrganizationFromContext(s.orgA)
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), uint(1), organizationID)
+
+	_, ok = tenant.OrganizationFromContext(context.Background())
+	assert.False(s.T(), ok)
+	_, ok = tenant.OrganizationFromContext(tenant.WithOrganization(context.Background(), 0))
+	assert.False(s.T(), ok)
+}
+
+func (s *TenantTestSuite) TestCreate_AssignsOrganization() {
+	var notes []note
+	s.db.WithContext(s.unscoped).Order("id").Find(&notes)
+
+	assert.Len(s.T(), notes, 3)
+	assert.Equal(s.T(), uint(1), notes[0].OrganizationID)
+	assert.Equal(s.T(), uint(2), notes[2].OrganizationID)
+}
+
+func (s *TenantTestSuite) TestCreate_OtherOrganization() {
+	err := s.db.WithContext(s.orgA).Create(&note{OrganizationID: 2, Body: "sneaky"}).Error
+
+	assert.ErrorIs(s.T(), err, tenant.ErrCrossOrganization)
+}
+
+func (s *TenantTestSuite) TestCreate_Batch() {
+	err := s.db.WithContext(s.orgB).Create([]*note{{Body: "b-2"}, {Body: "b-3"}}).Error
+	assert.NoError(s.T(), err)
+
+	var count int64
+	s.db.WithContext(s.orgB).Model(&note{}).Count(&count)
+	assert.Equal(s.T(), int64(3), count)
+}
+
+func (s *TenantTestSuite) TestQuery_Scoped() {
+	var notes []note
+	err := s.db.WithContext(s.orgA).Find(&notes).Error
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), notes, 2)
+	for _, n := range notes {
+		assert.Equal(s.T(), uint(1), n.OrganizationID)
+	}
+}
+
+func (s *TenantTestSuite) TestQuery_OtherOrganizationByID() {
+	var b note
+	s.db.WithContext(s.unscoped).Where("body = ?", "b-1").First(&b)
+
+	var result note
+	err := s.db.WithContext(s.orgA).First(&result, b.ID).Error
+
+	assert.ErrorIs(s.T(), err, gorm.ErrRecordNotFound)
+}
+
+func (s *TenantTestSuite) TestQuery_Count() {
+	var count int64
+	s.db.WithContext(s.orgB).Model(&note{}).Count(&count)
+
+	assert.Equal(s.T(), int64(1), count)
+}
+
+func (s *TenantTestSuite) TestQuery_Unscoped() {
+	var notes []note
+	err := s.db.WithContext(s.unscoped).Find(&notes).Error
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), notes, 3)
+}
+
+func (s *TenantTestSuite) TestUnscoped_ScopedAgain() {
+	var notes []note
+	err := s.db.WithContext(tenant.WithOrganization(s.unscoped, 2)).Find(&notes).Error
+
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), notes, 1)
+	assert.True(s.T(), tenant.IsUnscoped(s.unscoped))
+	_, ok := tenant.OrganizationFromContext(tenant.Unscoped(s.orgA))
+	assert.False(s.T(), ok)
+	assert.False(s.T(), tenant.IsUnscoped(s.orgA))
+}
+
+func (s *TenantTestSuite) TestNoOrganization_FailsClosed() {
+	var notes []note
+	assert.ErrorIs(s.T(), s.db.Find(&notes).Error, tenant.ErrNoOrganization)
+	assert.ErrorIs(s.T(), s.db.Create(&note{OrganizationID: 1, Body: "a-3"}).Error, tenant.ErrNoOrganization)
+	assert.ErrorIs(s.T(), s.db.Model(&note{}).Where("1 = 1").Update("body", "changed").Error, tenant.ErrNoOrganization)
+	assert.ErrorIs(s.T(), s.db.Where("1 = 1").Delete(&note{}).Error, tenant.ErrNoOrganization)
+
+	var count int64
+	s.db.WithContext(s.unscoped).Model(&note{}).Where("body = ?", "changed").Count(&count)
+	assert.Zero(s.T(), count)
+}
+
+func (s *TenantTestSuite) TestUpdate_Scoped() {
+	result := s.db.WithContext(s.orgA).Model(&note{}).Where("1 = 1").Update("body", "changed")
+
+	assert.NoError(s.T(), result.Error)
+	assert.Equal(s.T(), int64(2), result.RowsAffected)
+	var b note
+	s.db.WithContext(s.unscoped).Where("organization_id = ?", 2).First(&b)
+	assert.Equal(s.T(), "b-1", b.Body)
+}
+
+func (s *TenantTestSuite) TestDelete_Scoped() {
+	var b note
+	s.db.WithContext(s.unscoped).Where("body = ?", "b-1").First(&b)
+
+	result := s.db.WithContext(s.orgA).Delete(&note{}, b.ID)
+
+	assert.NoError(s.T(), result.Error)
+	assert.Zero(s.T(), result.RowsAffected)
+}
+
+func (s *TenantTestSuite) TestUnownedModel() {
+	err := s.db.WithContext(s.orgA).Create(&setting{Value: "x"}).Error
+	assert.NoError(s.T(), err)
+
+	var settings []setting
+	s.db.WithContext(s.orgB).Find(&settings)
+	assert.Len(s.T(), settings, 1)
+}
@@ -0,0 +1,92 @@
+package totp
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type TOTPTestSuite struct {
+	suite.Suite
+	// Secret of the SHA-1 test vectors in RFC 6238 appendix B
+	secret []byte
+}
+
+func (s *TOTPTestSuite) SetupTest() {
+	s.secret = []byte("12345678901234567890")
+}
+
+func TestTOTPSuite(t *testing.T) {
+	suite.Run(t, new(TOTPTestSuite))
+}
+
+func (s *TOTPTestSuite) TestCode_RFC6238Vectors() {
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, expected := range vectors {
+		assert.Equal(s.T(), expected, Code(s.secret, Step(time.Unix(unix, 0))), "time %d", unix)
+	}
+}
+
+func (s *TOTPTestSuite) TestValidate_CurrentStep() {
+	now := time.Unix(1234567890, 0)
+
+	step, ok := Validate(s.secret, "005924", now, 1)
+
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), Step(now), step)
+}
+
+func (s *TOTPTestSuite) TestValidate_Skew() {
+	now := time.Unix(1234567890, 0)
+	previous := Code(s.secret, Step(now)-1)
+
+	step, ok := Validate(s.secret, previous, now, 1)
+	assert.True(s.T(), ok)
+	assert.Equal(s.T(), Step(now)-1, step)
+
+	_, ok = Validate(s.secret, previous, now, 0)
+	assert.False(s.T(), ok)
+}
+
+func (s *TOTPTestSuite) TestValidate_WrongCode() {
+	_, ok := Validate(s.secret, "000000", time.Unix(1234567890, 0), 1)
+	assert.False(s.T(), ok)
+
+	_, ok = Validate(s.secret, "12345", time.Unix(1234567890, 0), 1)
+	assert.False(s.T(), ok)
+}
+
+func (s *TOTPTestSuite) TestGenerateSecret() {
+	first, err := GenerateSecret()
+	assert.NoError(s.T(), err)
+	assert.Len(s.T(), first, 20)
+
+	second, _ := GenerateSecret()
+	assert.NotEqual(s.T(), first, second)
+}
+
+func (s *TOTPTestSuite) TestURI() {
+	uri := URI("go-kit-base", "test@example.com", s.secret)
+
+	parsed, err := url.Parse(uri)
+	s.Require().NoError(err)
+	assert.Equal(s.T(), "otpauth", parsed.Scheme)
+	assert.Equal(s.T(), "totp", parsed.Host)
+	assert.Equal(s.T(), "/go-kit-base:test@example.com", parsed.Path)
+	assert.Equal(s.T(), "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", parsed.Query().Get("secret"))
+	assert.Equal(s.T(), "go-kit-base", parsed.Query().Get("issuer"))
+	assert.Equal(s.T(), "30", parsed.Query().Get("period"))
+	assert.False(s.T(), strings.Contains(parsed.Query().Get("secret"), "="))
+}
@@ -0,0 +1,76 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrPrivateAddress fails the attempt to reach an endpoint that resolves to
+// an address of the server's own networks.
+var ErrPrivateAddress = errors.New("webhook endpoint resolves to a private address")
+
+// reservedRanges are not covered by the netip predicates: "this network",
+// which some systems route to the host itself, and the carrier-grade NAT
+// range, which some clouds use for their metadata services.
+var reservedRanges = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// newTransport returns the transport of the worker. Unless private networks
+// are allowed, it refuses connections to private, loopback, link-local and
+// unspecified addresses. The check runs on the address that is dialed, after
+// the host is resolved, so a name that resolves to an internal address, or
+// is changed to one after the webhook was created, is refused as well.
+// Proxies from the environment are then ignored, as the address of the
+// proxy is all the dialer would see.
+func newTransport(allowPrivateNetworks bool) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if allowPrivateNetworks {
+		return transport
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   refusePrivateAddress,
+	}
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+// refusePrivateAddress is a net.Dialer Control hook that fails the dial to
+// an address that is not public.
+func refusePrivateAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !isPublicAddress(ip) {
+		return fmt.Errorf("%w: %s", ErrPrivateAddress, ip)
+	}
+	return nil
+}
+
+func isPublicAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, reserved := range reservedRanges {
+		if reserved.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
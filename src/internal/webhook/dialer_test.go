@@ -0,0 +1,35 @@
+package webhook
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRefusePrivateAddress(t *testing.T) {
+	refused := []string{
+		"127.0.0.1:80",
+		"10.1.2.3:443",
+		"172.16.0.1:443",
+		"192.168.1.1:80",
+		"169.254.169.254:80",
+		"100.100.100.200:80",
+		"0.0.0.0:80",
+		"[::1]:80",
+		"[::]:80",
+		"[fe80::1]:80",
+		"[fd00::1]:443",
+		"[::ffff:127.0.0.1]:80",
+		"224.0.0.1:80",
+	}
+	for _, address := range refused {
+		err := refusePrivateAddress("tcp", address, nil)
+		assert.True(t, errors.Is(err, ErrPrivateAddress), address)
+	}
+
+	allowed := []string{"93.184.216.34:443", "[2606:2800:220:1::1]:443"}
+	for _, address := range allowed {
+		assert.NoError(t, refusePrivateAddress("tcp", address, nil), address)
+	}
+}
@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+
+	header := Sign("secret", at, []byte(`{"id":1}`))
+
+	assert.Equal(t, "t=1700000000,v1=", header[:16])
+	assert.NoError(t, Verify("secret", header, []byte(`{"id":1}`), time.Minute, at.Add(30*time.Second)))
+}
+
+func TestVerify_TamperedBody(t *testing.T) {
+	at := time.Now()
+	header := Sign("secret", at, []byte(`{"id":1}`))
+
+	err := Verify("secret", header, []byte(`{"id":2}`), time.Minute, at)
+
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestVerify_WrongSecret(t *testing.T) {
+	at := time.Now()
+	header := Sign("secret", at, []byte(`{"id":1}`))
+
+	err := Verify("other-secret", header, []byte(`{"id":1}`), time.Minute, at)
+
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestVerify_TamperedTimestamp(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	header := Sign("secret", at, []byte(`{"id":1}`))
+
+	// Moving the timestamp to replay an old delivery breaks the signature
+	replayed := "t=1700000600" + header[len("t=1700000000"):]
+	err := Verify("secret", replayed, []byte(`{"id":1}`), time.Minute, at.Add(10*time.Minute))
+
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
+
+func TestVerify_Expired(t *testing.T) {
+	at := time.Now()
+	header := Sign("secret", at, []byte(`{"id":1}`))
+
+	err := Verify("secret", header, []byte(`{"id":1}`), time.Minute, at.Add(2*time.Minute))
+
+	assert.ErrorIs(t, err, ErrSignatureExpired)
+}
+
+func TestVerify_Malformed(t *testing.T) {
+	for _, header := range []string{"", "v1=abc", "t=abc,v1=abc", "t=1700000000"} {
+		err := Verify("secret", header, []byte(`{}`), time.Minute, time.Unix(1700000000, 0))
+
+		assert.ErrorIs(t, err, ErrInvalidSignature, header)
+	}
+}
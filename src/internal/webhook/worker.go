@@ -0,0 +1,133 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+)
+
+// maxErrorBodySize caps how much of a failed response is kept in the
+// delivery log.
+const maxErrorBodySize = 512
+
+// Worker sends the pending webhook deliveries of all organizations. Each
+// delivery is a signed POST of the event; anything but a 2xx response,
+// including a redirect, fails the attempt, as does an endpoint on a private
+// network unless the configuration allows them.
+type Worker struct {
+	webhooks   service.WebhookService
+	httpClient *http.Client
+	conf       config.WebhookConfig
+}
+
+func NewWorker(webhooks service.WebhookService, conf *config.Config) *Worker {
+	return &Worker{
+		webhooks: webhooks,
+		httpClient: &http.Client{
+			Timeout:   conf.Webhook.Timeout,
+			Transport: newTransport(conf.Webhook.AllowPrivateNetworks),
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		conf: conf.Webhook,
+	}
+}
+
+// Run sends deliveries every poll interval until ctx is done.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.conf.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Full batches mean there is a backlog, so keep going without waiting
+		for {
+			claimed, err := w.DeliverBatch(ctx)
+			if err != nil {
+				log.Printf("Failed to deliver webhooks: %v", err)
+				break
+			}
+			if claimed < w.conf.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverBatch claims the deliveries that are due and sends them at the same
+// time. It returns how many it claimed, whether or not they succeeded.
+func (w *Worker) DeliverBatch(ctx context.Context) (int, error) {
+	dispatches, err := w.webhooks.ClaimDeliveries(ctx, w.conf.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	for _, dispatch := range dispatches {
+		wg.Add(1)
+		go func(dispatch *service.WebhookDispatch) {
+			defer wg.Done()
+			attempt := w.send(ctx, dispatch)
+			if err := w.webhooks.RecordAttempt(ctx, dispatch.Delivery, attempt); err != nil {
+				// The delivery is sent again once its lease runs out
+				log.Printf("Failed to record attempt of webhook delivery %d: %v", dispatch.Delivery.ID, err)
+			}
+		}(dispatch)
+	}
+	wg.Wait()
+
+	return len(dispatches), nil
+}
+
+// send posts the delivery and reports how it went.
+func (w *Worker) send(ctx context.Context, dispatch *service.WebhookDispatch) *model.WebhookDeliveryAttempt {
+	delivery := dispatch.Delivery
+	body := []byte(delivery.Payload)
+	start := time.Now()
+	attempt := &model.WebhookDeliveryAttempt{}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dispatch.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "go-kit-base-webhooks")
+	req.Header.Set(HeaderID, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderSignature, Sign(dispatch.Secret, start, body))
+
+	resp, err := w.httpClient.Do(req)
+	attempt.DurationMS = time.Since(start).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	attempt.ResponseStatus = resp.StatusCode
+	if !attempt.Succeeded() {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		attempt.Error = fmt.Sprintf("endpoint responded with %d: %s", resp.StatusCode, strings.ToValidUTF8(string(bytes.TrimSpace(snippet)), ""))
+	}
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	return attempt
+}
@@ -0,0 +1,189 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"github.com/weeranieb/go-kit-base/src/internal/config"
+	"github.com/weeranieb/go-kit-base/src/internal/model"
+	"github.com/weeranieb/go-kit-base/src/internal/service"
+	mocks "github.com/weeranieb/go-kit-base/src/internal/service/mocks/service"
+)
+
+const testPayload = `{"id":7,"type":"user.created","data":{"user":{"id":3}}}`
+
+// receivedRequest is what the test receiver got.
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+type WorkerTestSuite struct {
+	suite.Suite
+	webhooks *mocks.MockWebhookService
+	worker   *Worker
+	mu       sync.Mutex
+	received []receivedRequest
+}
+
+func (s *WorkerTestSuite) SetupTest() {
+	s.webhooks = mocks.NewMockWebhookService(s.T())
+	s.worker = NewWorker(s.webhooks, &config.Config{Webhook: config.WebhookConfig{
+		Timeout:      time.Second,
+		PollInterval: 10 * time.Millisecond,
+		BatchSize:    10,
+		// The test receivers listen on the loopback interface
+		AllowPrivateNetworks: true,
+	}})
+	s.received = nil
+}
+
+func TestWorkerSuite(t *testing.T) {
+	suite.Run(t, new(WorkerTestSuite))
+}
+
+// receiver starts an endpoint that records each request and answers with
+// status and body.
+func (s *WorkerTestSuite) receiver(status int, body string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		s.received = append(s.received, receivedRequest{header: r.Header.Clone(), body: payload})
+		s.mu.Unlock()
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}))
+	s.T().Cleanup(server.Close)
+	return server
+}
+
+func newDispatch(id uint, url string) *service.WebhookDispatch {
+	return &service.WebhookDispatch{
+		Delivery: &model.WebhookDelivery{
+			ID:        id,
+			WebhookID: 1,
+			EventID:   7,
+			EventType: "user.created",
+			Payload:   testPayload,
+			Status:    model.WebhookDeliveryPending,
+			Attempts:  1,
+		},
+		URL:    url,
+		Secret: "whsec_test_secret",
+	}
+}
+
+func (s *WorkerTestSuite) TestDeliverBatch_Signed() {
+	server := s.receiver(http.StatusNoContent, "")
+	dispatch := newDispatch(5, server.URL)
+	s.webhooks.On("ClaimDeliveries", mock.Anything, 10).Return([]*service.WebhookDispatch{dispatch}, nil)
+	s.webhooks.On("RecordAttempt", mock.Anything, dispatch.Delivery, mock.MatchedBy(func(attempt *model.WebhookDeliveryAttempt) bool {
+		return attempt.ResponseStatus == http.StatusNoContent && attempt.Error == ""
+	})).Return(nil)
+
+	claimed, err := s.worker.DeliverBatch(context.Background())
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 1, claimed)
+	assert.Len(s.T(), s.received, 1)
+	request := s.received[0]
+	assert.Equal(s.T(), testPayload, string(request.body))
+	assert.Equal(s.T(), "5", request.header.Get(HeaderID))
+	assert.Equal(s.T(), "user.created", request.header.Get(HeaderEvent))
+	assert.Equal(s.T(), "application/json", request.header.Get("Content-Type"))
+	assert.NoError(s.T(), Verify("whsec_test_secret", request.header.Get(HeaderSignature), request.body, time.Minute, time.Now()))
+}
+
+func (s *WorkerTestSuite) TestDeliverBatch_ErrorResponse() {
+	server := s.receiver(http.StatusServiceUnavailable, "  try again later\n")
+	dispatch := newDispatch(5, server.URL)
+	s.webhooks.On("ClaimDeliveries", mock.Anything, 10).Return([]*service.WebhookDispatch{dispatch}, nil)
+	s.webhooks.On("RecordAttempt", mock.Anything, dispatch.Delivery, mock.MatchedBy(func(attempt *model.WebhookDeliveryAttempt) bool {
+		return attempt.ResponseStatus == http.StatusServiceUnavailable &&
+			attempt.Error == "endpoint responded with 503: try again later"
+	})).Return(nil)
+
+	_, err := s.worker.DeliverBatch(context.Background())
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *WorkerTestSuite) TestDeliverBatch_LongErrorResponse() {
+	server := s.receiver(http.StatusBadRequest, strings.Repeat("x", 4096))
+	dispatch := newDispatch(5, server.URL)
+	s.webhooks.On("ClaimDeliveries", mock.Anything, 10).Return([]*service.WebhookDispatch{dispatch}, nil)
+	s.webhooks.On("RecordAttempt", mock.Anything, dispatch.Delivery, mock.MatchedBy(func(attempt *model.WebhookDeliveryAttempt) bool {
+		return len(attempt.Error) == len("endpoint responded with 400: ")+maxErrorBodySize
+	})).Return(nil)
+
+	_, err := s.worker.DeliverBatch(context.Background())
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *WorkerTestSuite) TestDeliverBatch_Redirect() {
+	target := s.receiver(http.StatusOK, "")
+	redirect := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+	defer redirect.Close()
+	dispatch := newDispatch(5, redirect.URL)
+	s.webhooks.On("ClaimDeliveries", mock.Anything, 10).Return([]*service.WebhookDispatch{dispatch}, nil)
+	s.webhooks.On("RecordAttempt", mock.Anything, dispatch.Delivery, mock.MatchedBy(func(attempt *model.WebhookDeliveryAttempt) bool {
+		return attempt.ResponseStatus == http.StatusFound && !attempt.Succeeded()
+	})).Return(nil)
+
+	_, err := s.worker.DeliverBatch(context.Background())
+
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), s.received)
+}
+
+func (s *WorkerTestSuite) TestDeliverBatch_Unreachable() {
+	server := s.receiver(http.StatusOK, "")
+	server.Close()
+	dispatch := newDispatch(5, server.URL)
+	s.webhooks.On("ClaimDeliveries", mock.Anything, 10).Return([]*service.WebhookDispatch{dispatch}, nil)
+	s.webhooks.On("RecordAttempt", mock.Anything, dispatch.Delivery, mock.MatchedBy(func(attempt *model.WebhookDeliveryAttempt) bool {
+		return attempt.ResponseStatus == 0 && attempt.Error != ""
+	})).Return(nil)
+
+	_, err := s.worker.DeliverBatch(context.Background())
+
+	assert.NoError(s.T(), err)
+}
+
+func (s *WorkerTestSuite) TestDeliverBatch_PrivateAddress() {
+	worker := NewWorker(s.webhooks, &config.Config{Webhook: config.WebhookConfig{Timeout: time.Second, BatchSize: 10}})
+	server := s.receiver(http.StatusOK, "")
+	dispatch := newDispatch(5, server.URL)
+	s.webhooks.On("ClaimDeliveries", mock.Anything, 10).Return([]*service.WebhookDispatch{dispatch}, nil)
+	s.webhooks.On("RecordAttempt", mock.Anything, dispatch.Delivery, mock.MatchedBy(func(attempt *model.WebhookDeliveryAttempt) bool {
+		return attempt.ResponseStatus == 0 && strings.Contains(attempt.Error, ErrPrivateAddress.Error())
+	})).Return(nil)
+
+	_, err := worker.DeliverBatch(context.Background())
+
+	assert.NoError(s.T(), err)
+	assert.Empty(s.T(), s.received)
+}
+
+func (s *WorkerTestSuite) TestDeliverBatch_Parallel() {
+	server := s.receiver(http.StatusOK, "")
+	dispatches := []*service.WebhookDispatch{newDispatch(1, server.URL), newDispatch(2, server.URL), newDispatch(3, server.URL)}
+	s.webhooks.On("ClaimDeliveries", mock.Anything, 10).Return(dispatches, nil)
+	s.webhooks.On("RecordAttempt", mock.Anything, mock.Anything, mock.Anything).Return(nil).Times(3)
+
+	claimed, err := s.worker.DeliverBatch(context.Background())
+
+	assert.NoError(s.T(), err)
+	assert.Equal(s.T(), 3, claimed)
+	assert.Len(s.T(), s.received, 3)
+}